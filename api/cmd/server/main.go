@@ -1,8 +1,13 @@
 package main
 
 import (
+	"context"
+	"errors"
 	"log"
 	"os"
+	"os/signal"
+	"syscall"
+	"time"
 
 	"bitgo-wallets-api/internal/api"
 	"bitgo-wallets-api/internal/config"
@@ -22,6 +27,24 @@ func main() {
 	// Load configuration
 	cfg := config.Load()
 
+	// Report every bad setting up front; in release mode critical ones stop startup
+	if err := cfg.Validate(); err != nil {
+		var invalid *config.ValidationError
+		if !errors.As(err, &invalid) {
+			log.Fatalf("Failed to validate configuration: %v", err)
+		}
+		for _, problem := range invalid.Problems {
+			level := "[WARN]"
+			if problem.Critical {
+				level = "[ERROR]"
+			}
+			log.Printf("%s config %s", level, problem)
+		}
+		if invalid.FailsStartup(cfg.GinMode == "release") {
+			log.Fatalf("Refusing to start with invalid configuration")
+		}
+	}
+
 	// Initialize database
 	db, err := database.Connect(cfg.DatabaseURL)
 	if err != nil {
@@ -33,7 +56,31 @@ func main() {
 	server := api.NewServer(db, cfg)
 	log.Printf("Starting server on port %s", cfg.Port)
 
-	if err := server.Start(); err != nil {
-		log.Fatalf("Failed to start server: %v", err)
+	serveErr := make(chan error, 1)
+	go func() { serveErr <- server.Start() }()
+
+	signals := make(chan os.Signal, 1)
+	signal.Notify(signals, syscall.SIGINT, syscall.SIGTERM)
+
+	select {
+	case err := <-serveErr:
+		if err != nil {
+			log.Fatalf("Failed to start server: %v", err)
+		}
+		return
+	case sig := <-signals:
+		log.Printf("Received %s, shutting down", sig)
+	}
+
+	// Finish in-flight work within the deadline; exit nonzero if any of it was cut off
+	timeout := time.Duration(cfg.ShutdownTimeoutSeconds) * time.Second
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+
+	if err := server.Shutdown(ctx); err != nil {
+		log.Printf("Shutdown did not complete cleanly: %v", err)
+		db.Close()
+		os.Exit(1)
 	}
+	log.Printf("Server stopped")
 }
@@ -0,0 +1,220 @@
+package api
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"bitgo-wallets-api/internal/bitgo"
+	"bitgo-wallets-api/internal/models"
+	"bitgo-wallets-api/internal/repository"
+	"bitgo-wallets-api/internal/services"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// maxAddressLabelLength matches the wallet_addresses.label column
+const maxAddressLabelLength = 255
+
+type WalletAddressRequest struct {
+	Label       string `json:"label" binding:"required"`
+	Chain       *int   `json:"chain,omitempty"`
+	AddressType string `json:"address_type,omitempty"`
+}
+
+// getOrCreateWalletAddress returns the wallet's address with the requested label, generating
+// and storing a new one with BitGo the first time the label is used
+func (s *Server) getOrCreateWalletAddress(c *gin.Context) {
+	walletID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid wallet ID"})
+		return
+	}
+
+	var req WalletAddressRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	label := strings.TrimSpace(req.Label)
+	if label == "" || len(label) > maxAddressLabelLength {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Label must be between 1 and 255 characters"})
+		return
+	}
+
+	wallet, err := s.walletRepo.GetByID(walletID)
+	if errors.Is(err, repository.ErrWalletNotFound) {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Wallet not found"})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to get wallet"})
+		return
+	}
+
+	existing, err := s.walletAddressRepo.GetByLabel(walletID, label)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to look up address", "details": err.Error()})
+		return
+	}
+	if existing != nil {
+		c.JSON(http.StatusOK, gin.H{"address": existing, "created": false})
+		return
+	}
+
+	ctx := context.Background()
+	generated, err := s.bitgoClient.GenerateAddress(ctx, wallet.BitgoWalletID, wallet.Coin, &bitgo.AddressOptions{
+		Chain:       req.Chain,
+		AddressType: req.AddressType,
+		Label:       label,
+	})
+	if err != nil {
+		c.JSON(http.StatusBadGateway, gin.H{"error": "Failed to generate address with BitGo", "details": err.Error()})
+		return
+	}
+
+	chain, index := generated.Chain, generated.Index
+	address := &models.WalletAddress{
+		WalletID:     walletID,
+		Address:      generated.Address,
+		Label:        &label,
+		Chain:        &chain,
+		AddressIndex: &index,
+	}
+	if err := s.walletAddressRepo.Create(address); err != nil {
+		// A concurrent request stored the label first; return its address instead
+		if errors.Is(err, repository.ErrDuplicateWalletAddress) {
+			if existing, lookupErr := s.walletAddressRepo.GetByLabel(walletID, label); lookupErr == nil && existing != nil {
+				c.JSON(http.StatusOK, gin.H{"address": existing, "created": false})
+				return
+			}
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to store address", "details": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{"address": address, "created": true})
+}
+
+// listWalletAddresses lists the addresses generated for a wallet
+func (s *Server) listWalletAddresses(c *gin.Context) {
+	walletID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid wallet ID"})
+		return
+	}
+
+	if _, err := s.walletRepo.GetByID(walletID); errors.Is(err, repository.ErrWalletNotFound) {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Wallet not found"})
+		return
+	} else if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to get wallet"})
+		return
+	}
+
+	addresses, err := s.walletAddressRepo.ListByWallet(walletID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to list addresses", "details": err.Error()})
+		return
+	}
+	if addresses == nil {
+		addresses = []*models.WalletAddress{}
+	}
+
+	c.JSON(http.StatusOK, gin.H{"addresses": addresses})
+}
+
+// rejectSelfSend responds with 400 and returns true when recipient is one of the wallet's own
+// generated addresses and the caller hasn't set allow_self_send
+func (s *Server) rejectSelfSend(c *gin.Context, walletID uuid.UUID, recipient string, allowSelfSend bool) bool {
+	if allowSelfSend {
+		return false
+	}
+
+	own, err := s.walletAddressRepo.GetByAddress(walletID, strings.TrimSpace(recipient))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to check recipient address", "details": err.Error()})
+		return true
+	}
+	if own == nil {
+		return false
+	}
+
+	response := gin.H{
+		"error":     "Recipient is one of this wallet's own addresses",
+		"details":   "set allow_self_send to send to the wallet itself",
+		"self_send": true,
+		"address":   own.Address,
+	}
+	if own.Label != nil {
+		response["label"] = *own.Label
+	}
+	c.JSON(http.StatusBadRequest, response)
+	return true
+}
+
+// rejectInvalidAddress responds with 400 and returns true when the recipient address isn't
+// a well-formed address for the coin, such as an Ethereum address in a btc transfer, so the
+// mistake is caught before anything is built
+func (s *Server) rejectInvalidAddress(c *gin.Context, coin, recipient string) bool {
+	valid, err := s.bitgoClient.ValidateAddress(context.Background(), coin, recipient)
+	if err != nil {
+		c.JSON(http.StatusBadGateway, gin.H{"error": "Failed to validate recipient address", "details": err.Error()})
+		return true
+	}
+	if !valid {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Recipient address is not valid for this coin",
+			"code":    "invalid_address",
+			"details": fmt.Sprintf("%s is not a valid %s address", recipient, coin),
+		})
+		return true
+	}
+	return false
+}
+
+// rejectInvalidDestinationTag responds with 400 and returns true when a destination tag is
+// given that the coin doesn't take, is malformed, or conflicts with the memo or address
+func (s *Server) rejectInvalidDestinationTag(c *gin.Context, req CreateTransferRequest) bool {
+	if req.DestinationTag == nil || strings.TrimSpace(*req.DestinationTag) == "" {
+		return false
+	}
+
+	memo := ""
+	if req.Memo != nil {
+		memo = *req.Memo
+	}
+	if err := bitgo.ValidateDestination(req.Coin, req.RecipientAddress, memo, *req.DestinationTag); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Invalid destination tag",
+			"code":    "invalid_destination_tag",
+			"details": err.Error(),
+		})
+		return true
+	}
+	return false
+}
+
+// rejectNetworkMismatch responds with 400 and returns true when the coin or recipient address
+// belongs to a different network than the configured BitGo environment, e.g. a mainnet address
+// in a tbtc transfer
+func (s *Server) rejectNetworkMismatch(c *gin.Context, coin, recipient string) bool {
+	if !s.config.AddressNetworkGuard {
+		return false
+	}
+
+	expected := services.NetworkForEnvironment(s.config.BitGoEnvironment)
+	if err := services.ValidateAddressNetwork(recipient, coin, expected); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":            "Recipient address is for a different network",
+			"code":             "address_network_mismatch",
+			"details":          err.Error(),
+			"expected_network": expected,
+		})
+		return true
+	}
+	return false
+}
@@ -0,0 +1,191 @@
+package api
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+
+	"bitgo-wallets-api/internal/bitgo"
+	"bitgo-wallets-api/internal/models"
+	"bitgo-wallets-api/internal/repository"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// storedWalletAddressRepo keeps generated addresses in memory, unique per wallet by address
+// and by label
+type storedWalletAddressRepo struct {
+	mu        sync.Mutex
+	addresses []*models.WalletAddress
+}
+
+func (r *storedWalletAddressRepo) Create(address *models.WalletAddress) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, existing := range r.addresses {
+		if existing.WalletID != address.WalletID {
+			continue
+		}
+		if existing.Address == address.Address || existing.Label != nil && address.Label != nil && *existing.Label == *address.Label {
+			return repository.ErrDuplicateWalletAddress
+		}
+	}
+	address.ID = uuid.New()
+	r.addresses = append(r.addresses, address)
+	return nil
+}
+
+func (r *storedWalletAddressRepo) GetByLabel(walletID uuid.UUID, label string) (*models.WalletAddress, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, address := range r.addresses {
+		if address.WalletID == walletID && address.Label != nil && *address.Label == label {
+			return address, nil
+		}
+	}
+	return nil, nil
+}
+
+func (r *storedWalletAddressRepo) GetByAddress(walletID uuid.UUID, address string) (*models.WalletAddress, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, stored := range r.addresses {
+		if stored.WalletID == walletID && stored.Address == address {
+			return stored, nil
+		}
+	}
+	return nil, nil
+}
+
+func (r *storedWalletAddressRepo) ListByWallet(walletID uuid.UUID) ([]*models.WalletAddress, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	var addresses []*models.WalletAddress
+	for _, address := range r.addresses {
+		if address.WalletID == walletID {
+			addresses = append(addresses, address)
+		}
+	}
+	return addresses, nil
+}
+
+// generateCountingClient counts the addresses generated with BitGo
+type generateCountingClient struct {
+	*bitgo.SimulatedClient
+
+	mu        sync.Mutex
+	generated int
+}
+
+func (c *generateCountingClient) GenerateAddress(ctx context.Context, walletID, coin string, options *bitgo.AddressOptions) (*bitgo.Address, error) {
+	c.mu.Lock()
+	c.generated++
+	c.mu.Unlock()
+	return c.SimulatedClient.GenerateAddress(ctx, walletID, coin, options)
+}
+
+func TestGetOrCreateWalletAddressIsIdempotentByLabel(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	wallet := &models.Wallet{ID: uuid.New(), BitgoWalletID: "bitgo-btc-1", Coin: "btc", WalletType: models.WalletTypeHot, IsActive: true}
+	client := &generateCountingClient{SimulatedClient: bitgo.NewSimulatedClient(bitgo.SimulationConfig{}, &SimpleLogger{})}
+	server := &Server{
+		bitgoClient:       client,
+		walletRepo:        newMemWalletRepo(wallet),
+		walletAddressRepo: &storedWalletAddressRepo{},
+	}
+	router := gin.New()
+	router.POST("/wallets/:id/addresses", server.getOrCreateWalletAddress)
+
+	request := func(label string) (int, models.WalletAddress, bool) {
+		recorder := httptest.NewRecorder()
+		router.ServeHTTP(recorder, httptest.NewRequest(http.MethodPost, "/wallets/"+wallet.ID.String()+"/addresses", jsonBody(t, WalletAddressRequest{Label: label})))
+		var body struct {
+			Address models.WalletAddress `json:"address"`
+			Created bool                 `json:"created"`
+		}
+		decodeJSON(t, recorder, &body)
+		return recorder.Code, body.Address, body.Created
+	}
+
+	code, first, created := request("customer-42")
+	if code != http.StatusCreated || !created || first.Address == "" {
+		t.Fatalf("first request = %d, created %v, address %q; want a new address", code, created, first.Address)
+	}
+
+	// Surrounding whitespace doesn't make a different label
+	for _, label := range []string{"customer-42", "  customer-42 "} {
+		code, again, created := request(label)
+		if code != http.StatusOK || created || again.Address != first.Address {
+			t.Errorf("label %q = %d, created %v, address %q; want the existing %q", label, code, created, again.Address, first.Address)
+		}
+	}
+	if client.generated != 1 {
+		t.Errorf("generated %d addresses with BitGo, want 1", client.generated)
+	}
+
+	code, other, created := request("customer-43")
+	if code != http.StatusCreated || !created || other.Address == first.Address {
+		t.Errorf("new label = %d, created %v, address %q; want a new address", code, created, other.Address)
+	}
+}
+
+func TestCreateTransferRejectsSelfSend(t *testing.T) {
+	wallet := &models.Wallet{ID: uuid.New(), BitgoWalletID: "bitgo-btc-1", Coin: "btc", WalletType: models.WalletTypeHot, SpendableBalanceString: "10", IsActive: true}
+	client := bitgo.NewSimulatedClient(bitgo.SimulationConfig{}, &SimpleLogger{})
+	label := "deposits"
+	own, err := client.GenerateAddress(context.Background(), wallet.BitgoWalletID, wallet.Coin, &bitgo.AddressOptions{Label: label})
+	if err != nil {
+		t.Fatalf("GenerateAddress() error = %v", err)
+	}
+
+	tests := []struct {
+		name          string
+		recipient     string
+		allowSelfSend bool
+		wantCode      int
+	}{
+		{name: "own address", recipient: own.Address, wantCode: http.StatusBadRequest},
+		{name: "own address allowed", recipient: own.Address, allowSelfSend: true, wantCode: http.StatusCreated},
+		{name: "external address", recipient: testBTCAddress, wantCode: http.StatusCreated},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server, router, repo := newHotTransferTestServer(wallet, client)
+			addresses := &storedWalletAddressRepo{}
+			if err := addresses.Create(&models.WalletAddress{WalletID: wallet.ID, Address: own.Address, Label: &label}); err != nil {
+				t.Fatalf("Create() error = %v", err)
+			}
+			server.walletAddressRepo = addresses
+
+			recorder := postTransfer(t, router, wallet, CreateTransferRequest{
+				RecipientAddress: tt.recipient,
+				AmountString:     "0.01",
+				Coin:             "btc",
+				TransferType:     models.WalletTypeHot,
+				AllowSelfSend:    tt.allowSelfSend,
+			})
+			if recorder.Code != tt.wantCode {
+				t.Fatalf("status = %d, want %d: %s", recorder.Code, tt.wantCode, recorder.Body.String())
+			}
+			if tt.wantCode != http.StatusBadRequest {
+				return
+			}
+
+			var body struct {
+				SelfSend bool   `json:"self_send"`
+				Label    string `json:"label"`
+			}
+			decodeJSON(t, recorder, &body)
+			if !body.SelfSend || body.Label != label {
+				t.Errorf("self_send = %v, label = %q; want true, %q", body.SelfSend, body.Label, label)
+			}
+			if stored, _ := repo.ListByWallet(wallet.ID, repository.TransferListFilter{}, 10, 0); len(stored) != 0 {
+				t.Errorf("%d transfers stored, want none", len(stored))
+			}
+		})
+	}
+}
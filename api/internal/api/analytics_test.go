@@ -0,0 +1,111 @@
+package api
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"bitgo-wallets-api/internal/bitgo"
+	"bitgo-wallets-api/internal/models"
+	"bitgo-wallets-api/internal/services"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+func TestWarmTransfersAnalyticsReportsVolumePerCoinAndInUSD(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	transfer := func(transferType models.WalletType, coin, amount string, status models.TransferStatus) *models.TransferRequest {
+		return &models.TransferRequest{ID: uuid.New(), WalletID: uuid.New(), TransferType: transferType, Coin: coin, AmountString: amount, Status: status}
+	}
+	repo := newMemTransferRepo(
+		transfer(models.WalletTypeWarm, "btc", "0.1", models.TransferStatusCompleted),
+		transfer(models.WalletTypeWarm, "btc", "0.15", models.TransferStatusBroadcast),
+		transfer(models.WalletTypeWarm, "eth", "1.5", models.TransferStatusCompleted),
+		transfer(models.WalletTypeWarm, "doge", "100", models.TransferStatusCompleted),
+		transfer(models.WalletTypeCold, "btc", "5", models.TransferStatusCompleted),
+		transfer(models.WalletTypeWarm, "btc", "1", models.TransferStatusFailed),
+	)
+
+	// doge's price lookup fails
+	prices := map[string]float64{"btc": 40000, "eth": 2000}
+	oracle := services.PriceOracleFunc(func(_ context.Context, coin string) (float64, error) {
+		price, ok := prices[coin]
+		if !ok {
+			return 0, fmt.Errorf("no price for %s", coin)
+		}
+		return price, nil
+	})
+
+	client := bitgo.NewSimulatedClient(bitgo.SimulationConfig{}, &SimpleLogger{})
+	server := &Server{
+		transferRequestRepo: repo,
+		priceOracle:         oracle,
+		warmWalletSvc:       services.NewWarmWalletService(client, nil, repo, nopNotifier{}, &SimpleLogger{}, services.DefaultWarmWalletConfig(), nil, nil, nil),
+	}
+	router := gin.New()
+	router.GET("/transfers/warm/analytics", server.getWarmTransfersAnalytics)
+
+	type analyticsBody struct {
+		VolumeByCoin  map[string]string    `json:"volume_by_coin"`
+		TransferCount int                  `json:"transfer_count"`
+		FiatVolume    *services.FiatVolume `json:"fiat_volume"`
+		Volume        *string              `json:"volume"`
+	}
+	get := func(query string) (int, analyticsBody, string) {
+		recorder := httptest.NewRecorder()
+		router.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/transfers/warm/analytics"+query, nil))
+		var body analyticsBody
+		if recorder.Code == http.StatusOK {
+			decodeJSON(t, recorder, &body)
+		}
+		return recorder.Code, body, recorder.Body.String()
+	}
+
+	code, body, raw := get("?convert=usd")
+	if code != http.StatusOK {
+		t.Fatalf("status = %d, want %d: %s", code, http.StatusOK, raw)
+	}
+
+	// Only the four warm transfers in a reported status count, and volume stays per coin
+	if body.TransferCount != 4 {
+		t.Errorf("transfer_count = %d, want 4", body.TransferCount)
+	}
+	if body.Volume != nil {
+		t.Errorf("volume = %q, want no cross-coin total in native units", *body.Volume)
+	}
+	wantVolumes := map[string]string{"btc": "0.25", "eth": "1.5", "doge": "100"}
+	if len(body.VolumeByCoin) != len(wantVolumes) {
+		t.Errorf("volume_by_coin = %v, want %v", body.VolumeByCoin, wantVolumes)
+	}
+	for coin, want := range wantVolumes {
+		if got := body.VolumeByCoin[coin]; got != want {
+			t.Errorf("%s volume = %q, want %q", coin, got, want)
+		}
+	}
+
+	// The USD total comes from oracle prices and leaves out the coin it couldn't price
+	fiat := body.FiatVolume
+	if fiat == nil {
+		t.Fatalf("no fiat_volume in %s", raw)
+	}
+	if fiat.Currency != "USD" || fiat.Total != 13000 {
+		t.Errorf("fiat total = %v %s, want 13000 USD", fiat.Total, fiat.Currency)
+	}
+	if len(fiat.Coins) != 2 || fiat.Coins["btc"] != 10000 || fiat.Coins["eth"] != 3000 {
+		t.Errorf("fiat coins = %v, want btc 10000 and eth 3000", fiat.Coins)
+	}
+	if len(fiat.Unpriced) != 1 || fiat.Unpriced[0] != "doge" {
+		t.Errorf("unpriced = %v, want [doge]", fiat.Unpriced)
+	}
+
+	if code, body, raw := get(""); code != http.StatusOK || body.FiatVolume != nil {
+		t.Errorf("without convert = %d %s, want %d without fiat_volume", code, raw, http.StatusOK)
+	}
+	if code, _, _ := get("?convert=eur"); code != http.StatusBadRequest {
+		t.Errorf("convert=eur status = %d, want %d", code, http.StatusBadRequest)
+	}
+}
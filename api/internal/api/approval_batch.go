@@ -0,0 +1,234 @@
+package api
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"bitgo-wallets-api/internal/models"
+	"bitgo-wallets-api/internal/repository"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// maxBatchApprovals caps how many decisions one batch request may carry
+const maxBatchApprovals = 100
+
+// Approval decisions an operator can make
+const (
+	approvalDecisionApprove = "approve"
+	approvalDecisionReject  = "reject"
+)
+
+// approvableTransferTypes are the transfer types that go through operator approval
+var approvableTransferTypes = map[models.WalletType]bool{
+	models.WalletTypeCold: true,
+	models.WalletTypeWarm: true,
+}
+
+// BatchApprovalItem is one decision in a batch approval request
+type BatchApprovalItem struct {
+	TransferID uuid.UUID `json:"transfer_id"`
+	Decision   string    `json:"decision"` // approve or reject
+	Comment    string    `json:"comment"`
+}
+
+type BatchApprovalRequest struct {
+	Approvals []BatchApprovalItem `json:"approvals"`
+}
+
+// BatchApprovalResult reports what happened to one decision. Status is approved (the
+// transfer has all its approvals), recorded (more are needed), rejected, or failed.
+type BatchApprovalResult struct {
+	Index      int                     `json:"index"`
+	TransferID uuid.UUID               `json:"transfer_id"`
+	Status     string                  `json:"status"`
+	Transfer   *models.TransferRequest `json:"transfer,omitempty"`
+	Error      string                  `json:"error,omitempty"`
+	Code       string                  `json:"code,omitempty"`
+}
+
+// batchApprovals applies many approval decisions at once, e.g. when working through the cold
+// admin queue. Each decision is checked and applied on its own, in its own transaction, and
+// the response reports every one; a failed decision doesn't stop the rest.
+func (s *Server) batchApprovals(c *gin.Context) {
+	var req BatchApprovalRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	if len(req.Approvals) == 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "At least one approval decision is required"})
+		return
+	}
+	if len(req.Approvals) > maxBatchApprovals {
+		c.JSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("At most %d approval decisions can be sent at once", maxBatchApprovals)})
+		return
+	}
+
+	// A transfer gets one decision per batch; repeats would count the same approver twice
+	results := make([]BatchApprovalResult, len(req.Approvals))
+	counts := map[string]int{"approved": 0, "recorded": 0, "rejected": 0, "failed": 0}
+	seen := make(map[uuid.UUID]int, len(req.Approvals))
+	for i, item := range req.Approvals {
+		if first, ok := seen[item.TransferID]; ok && item.TransferID != uuid.Nil {
+			results[i] = BatchApprovalResult{
+				Index:      i,
+				TransferID: item.TransferID,
+				Status:     "failed",
+				Code:       "duplicate_transfer",
+				Error:      fmt.Sprintf("transfer already decided at index %d of this batch", first),
+			}
+		} else {
+			seen[item.TransferID] = i
+			results[i] = s.applyBatchApproval(c, i, item)
+		}
+		counts[results[i].Status]++
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"results":  results,
+		"total":    len(results),
+		"approved": counts["approved"],
+		"recorded": counts["recorded"],
+		"rejected": counts["rejected"],
+		"failed":   counts["failed"],
+	})
+}
+
+// applyBatchApproval validates one batch item and applies its decision
+func (s *Server) applyBatchApproval(c *gin.Context, index int, item BatchApprovalItem) BatchApprovalResult {
+	result := BatchApprovalResult{Index: index, TransferID: item.TransferID, Status: "failed"}
+
+	decision := strings.ToLower(strings.TrimSpace(item.Decision))
+	if item.TransferID == uuid.Nil {
+		result.Code, result.Error = "invalid_request", "transfer_id is required"
+		return result
+	}
+	if decision != approvalDecisionApprove && decision != approvalDecisionReject {
+		result.Code, result.Error = "invalid_request", "decision must be 'approve' or 'reject'"
+		return result
+	}
+
+	transfer, err := s.transferRequestRepo.GetByID(item.TransferID)
+	if err != nil {
+		result.Code, result.Error = "internal_error", "failed to get transfer: "+err.Error()
+		return result
+	}
+	if transfer == nil {
+		result.Code, result.Error = "not_found", "transfer not found"
+		return result
+	}
+
+	result = s.decideApproval(c, transfer, decision, item.Comment)
+	result.Index = index
+	return result
+}
+
+// decideApproval authorizes the current user and applies their decision on a transfer awaiting
+// approval. Single and batch approvals both go through it, so either way the row is locked, the
+// transfer must still await approval and each approver counts once.
+func (s *Server) decideApproval(c *gin.Context, transfer *models.TransferRequest, decision, comment string) BatchApprovalResult {
+	result := BatchApprovalResult{TransferID: transfer.ID, Status: "failed"}
+	fail := func(code, message string) BatchApprovalResult {
+		result.Code = code
+		result.Error = message
+		return result
+	}
+
+	if !approvableTransferTypes[transfer.TransferType] {
+		return fail("not_approvable", fmt.Sprintf("%s transfers don't go through approval", transfer.TransferType))
+	}
+	denial, err := s.approvalDenial(c, transfer)
+	if err != nil {
+		return fail("internal_error", err.Error())
+	}
+	if denial != "" {
+		return fail("forbidden", denial)
+	}
+
+	var reason *string
+	if comment := strings.TrimSpace(comment); comment != "" {
+		reason = &comment
+	}
+
+	oldStatus := transfer.Status
+	updated, err := s.transferRequestRepo.DecideApproval(transfer.ID, s.approverID(c), decision == approvalDecisionApprove, reason)
+	switch {
+	case errors.Is(err, repository.ErrTransferRequestNotFound):
+		return fail("not_found", "transfer not found")
+	case errors.Is(err, repository.ErrApprovalResolved):
+		result.Transfer = updated
+		return fail("approval_resolved", fmt.Sprintf("transfer is %s, not awaiting approval", updated.Status))
+	case errors.Is(err, repository.ErrAwaitingBitGoApproval):
+		result.Transfer = updated
+		return fail("awaiting_bitgo_approval", "transfer was submitted to BitGo and is awaiting approval under the wallet's BitGo policy")
+	case errors.Is(err, repository.ErrAlreadyDecided):
+		result.Transfer = updated
+		return fail("already_decided", "you have already decided on this transfer")
+	case err != nil:
+		return fail("internal_error", err.Error())
+	}
+
+	if updated.Status != oldStatus {
+		s.notificationSvc.SendTransferStatusNotification(updated, oldStatus, updated.Status)
+	}
+
+	result.Transfer = updated
+	switch {
+	case updated.Status == models.TransferStatusRejected:
+		result.Status = "rejected"
+	case updated.Status == models.TransferStatusApproved:
+		result.Status = "approved"
+	default:
+		result.Status = "recorded"
+	}
+	return result
+}
+
+// approvalFailureStatuses maps a failed decision's code to the HTTP status a single approval returns
+var approvalFailureStatuses = map[string]int{
+	"not_approvable":          http.StatusBadRequest,
+	"forbidden":               http.StatusForbidden,
+	"not_found":               http.StatusNotFound,
+	"approval_resolved":       http.StatusConflict,
+	"awaiting_bitgo_approval": http.StatusConflict,
+	"already_decided":         http.StatusConflict,
+}
+
+// approverID returns the user an approval decision is recorded against, or nil when
+// authentication is disabled and there is no one to record
+func (s *Server) approverID(c *gin.Context) *uuid.UUID {
+	userID, ok := s.authenticatedUserID(c)
+	if !ok {
+		return nil
+	}
+	return &userID
+}
+
+// approvalDenial returns why the current user may not decide on the transfer's approval, or
+// "" when they may. Without an authenticated user (authentication is disabled) there is no
+// one to check, so decisions are allowed as they are for single approvals.
+func (s *Server) approvalDenial(c *gin.Context, transfer *models.TransferRequest) (string, error) {
+	userID, ok := s.authenticatedUserID(c)
+	if !ok {
+		return "", nil
+	}
+	if userID == transfer.RequestedByUserID {
+		return "the user who requested a transfer cannot approve it", nil
+	}
+
+	approvers, err := s.membershipRepo.ListApprovers(transfer.WalletID)
+	if err != nil {
+		return "", fmt.Errorf("failed to list wallet approvers: %w", err)
+	}
+	for _, approver := range approvers {
+		if approver.UserID == userID {
+			return "", nil
+		}
+	}
+	return "not an approver for this wallet", nil
+}
@@ -0,0 +1,94 @@
+package api
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"bitgo-wallets-api/internal/models"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+func TestBatchApprovalsReportEachDecision(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	newTransfer := func(transferType models.WalletType) *models.TransferRequest {
+		return &models.TransferRequest{
+			ID:                uuid.New(),
+			WalletID:          uuid.New(),
+			RequestedByUserID: uuid.New(),
+			RecipientAddress:  testBTCAddress,
+			AmountString:      "0.5",
+			Coin:              "btc",
+			TransferType:      transferType,
+			Status:            models.TransferStatusPendingApproval,
+			RequiredApprovals: 1,
+		}
+	}
+	warm, cold, rejected := newTransfer(models.WalletTypeWarm), newTransfer(models.WalletTypeCold), newTransfer(models.WalletTypeWarm)
+	missing := uuid.New()
+	repo := newMemTransferRepo(warm, cold, rejected)
+
+	server := &Server{transferRequestRepo: repo, notificationSvc: nopNotifier{}}
+	router := gin.New()
+	router.POST("/approvals/batch", server.batchApprovals)
+
+	recorder := httptest.NewRecorder()
+	router.ServeHTTP(recorder, httptest.NewRequest(http.MethodPost, "/approvals/batch", jsonBody(t, BatchApprovalRequest{
+		Approvals: []BatchApprovalItem{
+			{TransferID: warm.ID, Decision: "approve"},
+			{TransferID: cold.ID, Decision: "APPROVE"},
+			{TransferID: rejected.ID, Decision: "reject", Comment: "wrong recipient"},
+			{TransferID: missing, Decision: "approve"},
+		},
+	})))
+	if recorder.Code != http.StatusOK {
+		t.Fatalf("status = %d, want %d: %s", recorder.Code, http.StatusOK, recorder.Body.String())
+	}
+
+	var body struct {
+		Results  []BatchApprovalResult `json:"results"`
+		Total    int                   `json:"total"`
+		Approved int                   `json:"approved"`
+		Rejected int                   `json:"rejected"`
+		Failed   int                   `json:"failed"`
+	}
+	decodeJSON(t, recorder, &body)
+	if body.Total != 4 || body.Approved != 2 || body.Rejected != 1 || body.Failed != 1 {
+		t.Errorf("totals = %d total, %d approved, %d rejected, %d failed; want 4, 2, 1, 1", body.Total, body.Approved, body.Rejected, body.Failed)
+	}
+	if len(body.Results) != 4 {
+		t.Fatalf("%d results, want 4", len(body.Results))
+	}
+
+	tests := []struct {
+		transferID uuid.UUID
+		wantResult string
+		wantCode   string
+		wantStored models.TransferStatus
+	}{
+		{transferID: warm.ID, wantResult: "approved", wantStored: models.TransferStatusApproved},
+		{transferID: cold.ID, wantResult: "approved", wantStored: models.TransferStatusApproved},
+		{transferID: rejected.ID, wantResult: "rejected", wantStored: models.TransferStatusRejected},
+		{transferID: missing, wantResult: "failed", wantCode: "not_found"},
+	}
+	for i, tt := range tests {
+		result := body.Results[i]
+		if result.Index != i || result.TransferID != tt.transferID || result.Status != tt.wantResult || result.Code != tt.wantCode {
+			t.Errorf("result %d = %+v, want %s for %s with code %q", i, result, tt.wantResult, tt.transferID, tt.wantCode)
+		}
+		if tt.wantStored == "" {
+			continue
+		}
+		stored, _ := repo.GetByID(tt.transferID)
+		if stored.Status != tt.wantStored {
+			t.Errorf("transfer %d stored as %s, want %s", i, stored.Status, tt.wantStored)
+		}
+	}
+
+	stored, _ := repo.GetByID(rejected.ID)
+	if stored.StatusReason == nil || *stored.StatusReason != "wrong recipient" {
+		t.Errorf("rejection reason = %v, want the comment", stored.StatusReason)
+	}
+}
@@ -0,0 +1,117 @@
+package api
+
+import (
+	"context"
+	"errors"
+	"net/http"
+
+	"bitgo-wallets-api/internal/models"
+	"bitgo-wallets-api/internal/repository"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// cancellableApprovalStatuses are the statuses in which a transfer is still waiting on approvals
+var cancellableApprovalStatuses = map[models.TransferStatus]bool{
+	models.TransferStatusSubmitted:       true,
+	models.TransferStatusPendingApproval: true,
+}
+
+// cancelTransferApproval lets the user who requested a transfer withdraw it while it's still
+// waiting on approvals. A BitGo pending approval, if there is one, is cancelled first; the local
+// transfer then moves to cancelled.
+func (s *Server) cancelTransferApproval(c *gin.Context) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid transfer ID"})
+		return
+	}
+
+	transfer, err := s.transferRequestRepo.GetByID(id)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to get transfer"})
+		return
+	}
+	if transfer == nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Transfer not found"})
+		return
+	}
+
+	if transfer.RequestedByUserID != s.getCurrentUserID(c) {
+		c.JSON(http.StatusForbidden, gin.H{"error": "Only the user who requested the transfer can cancel its approval"})
+		return
+	}
+	if !cancellableApprovalStatuses[transfer.Status] {
+		c.JSON(http.StatusConflict, gin.H{
+			"error":          "Approval is already resolved",
+			"current_status": transfer.Status,
+		})
+		return
+	}
+
+	var approvalID string
+	if transfer.BitgoTransferID != nil {
+		wallet, err := s.walletRepo.GetByID(transfer.WalletID)
+		if errors.Is(err, repository.ErrWalletNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Wallet not found for transfer"})
+			return
+		}
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to get wallet"})
+			return
+		}
+
+		ctx := context.Background()
+		approval, err := s.approvalSvc.FindTransferApproval(ctx, wallet.BitgoWalletID, wallet.Coin, *transfer.BitgoTransferID)
+		if err != nil {
+			c.JSON(http.StatusBadGateway, gin.H{"error": "Failed to get approval from BitGo", "details": err.Error()})
+			return
+		}
+		if approval == nil {
+			c.JSON(http.StatusConflict, gin.H{
+				"error":   "Approval is already resolved",
+				"details": "BitGo has no pending approval for this transfer",
+			})
+			return
+		}
+
+		if _, err := s.approvalSvc.CancelApproval(ctx, approval.ID); err != nil {
+			c.JSON(http.StatusBadGateway, gin.H{"error": "Failed to cancel approval with BitGo", "details": err.Error()})
+			return
+		}
+		approvalID = approval.ID
+	}
+
+	// Reapplied to the latest row if an approval landed meanwhile; one that resolved the
+	// transfer wins and the cancellation is refused
+	oldStatus := transfer.Status
+	reason := "Approval request cancelled by requestor"
+	cancelled, err := repository.UpdateWithRetry(s.transferRequestRepo, transfer, func(transfer *models.TransferRequest) bool {
+		if !cancellableApprovalStatuses[transfer.Status] {
+			return false
+		}
+		oldStatus = transfer.Status
+		transfer.Status = models.TransferStatusCancelled
+		transfer.StatusReason = &reason
+		return true
+	})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update transfer", "details": err.Error()})
+		return
+	}
+	if !cancelled {
+		c.JSON(http.StatusConflict, gin.H{
+			"error":          "Approval is already resolved",
+			"current_status": transfer.Status,
+		})
+		return
+	}
+	s.notificationSvc.SendTransferStatusNotification(transfer, oldStatus, transfer.Status)
+
+	response := gin.H{"transfer": transfer}
+	if approvalID != "" {
+		response["bitgo_approval_id"] = approvalID
+	}
+	c.JSON(http.StatusOK, response)
+}
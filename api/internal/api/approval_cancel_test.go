@@ -0,0 +1,64 @@
+package api
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"bitgo-wallets-api/internal/models"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+func TestCancelTransferApproval(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	creator, other := uuid.New(), uuid.New()
+
+	tests := []struct {
+		name       string
+		userID     uuid.UUID
+		status     models.TransferStatus
+		wantStatus int
+		wantStored models.TransferStatus
+	}{
+		{name: "creator cancels pending approval", userID: creator, status: models.TransferStatusPendingApproval, wantStatus: http.StatusOK, wantStored: models.TransferStatusCancelled},
+		{name: "another user", userID: other, status: models.TransferStatusPendingApproval, wantStatus: http.StatusForbidden, wantStored: models.TransferStatusPendingApproval},
+		{name: "already approved", userID: creator, status: models.TransferStatusApproved, wantStatus: http.StatusConflict, wantStored: models.TransferStatusApproved},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			transfer := &models.TransferRequest{
+				ID:                uuid.New(),
+				WalletID:          uuid.New(),
+				RequestedByUserID: creator,
+				RecipientAddress:  testBTCAddress,
+				AmountString:      "0.5",
+				Coin:              "btc",
+				TransferType:      models.WalletTypeWarm,
+				Status:            tt.status,
+				RequiredApprovals: 2,
+			}
+			repo := newMemTransferRepo(transfer)
+			server := &Server{transferRequestRepo: repo, notificationSvc: nopNotifier{}}
+			router := gin.New()
+			router.Use(func(c *gin.Context) { c.Set("user_id", tt.userID.String()) })
+			router.DELETE("/transfers/:id/approval", server.cancelTransferApproval)
+
+			recorder := httptest.NewRecorder()
+			router.ServeHTTP(recorder, httptest.NewRequest(http.MethodDelete, "/transfers/"+transfer.ID.String()+"/approval", nil))
+			if recorder.Code != tt.wantStatus {
+				t.Fatalf("status = %d, want %d: %s", recorder.Code, tt.wantStatus, recorder.Body.String())
+			}
+
+			stored, _ := repo.GetByID(transfer.ID)
+			if stored.Status != tt.wantStored {
+				t.Errorf("stored status = %s, want %s", stored.Status, tt.wantStored)
+			}
+			if tt.wantStored == models.TransferStatusCancelled && (stored.StatusReason == nil || *stored.StatusReason == "") {
+				t.Error("cancelled transfer has no status reason")
+			}
+		})
+	}
+}
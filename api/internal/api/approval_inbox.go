@@ -0,0 +1,107 @@
+package api
+
+import (
+	"net/http"
+	"sort"
+	"time"
+
+	"bitgo-wallets-api/internal/models"
+	"bitgo-wallets-api/internal/services"
+
+	"github.com/gin-gonic/gin"
+)
+
+// maxApprovalInboxItems caps how many transfers the approval inbox loads; the query returns
+// the most urgent first, so older low-urgency transfers are the ones left out
+const maxApprovalInboxItems = 500
+
+// urgencyRanks orders urgency levels from most to least urgent; unknown levels sort last.
+// ListAwaitingApproval orders by the same ranks.
+var urgencyRanks = map[string]int{
+	"critical": 0,
+	"high":     1,
+	"normal":   2,
+	"low":      3,
+}
+
+// ApprovalInboxItem is a transfer awaiting the user's approval with its SLA position. The
+// deadline is the processing SLA, which covers gathering approvals.
+type ApprovalInboxItem struct {
+	Transfer      *models.TransferRequest `json:"transfer"`
+	UrgencyLevel  string                  `json:"urgency_level"`
+	SLA           services.SLADeadlines   `json:"sla"`
+	Deadline      time.Time               `json:"deadline"`
+	TimeRemaining string                  `json:"time_remaining"`
+	Breached      bool                    `json:"breached"`
+}
+
+// getApprovalInbox lists the transfers awaiting the current user's approval across every
+// wallet they approve for, most urgent first and then by nearest deadline
+func (s *Server) getApprovalInbox(c *gin.Context) {
+	userID, ok := s.authenticatedUserID(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "The approval inbox requires an authenticated user"})
+		return
+	}
+
+	transfers, err := s.transferRequestRepo.ListAwaitingApproval(userID, maxApprovalInboxItems)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error":   "Failed to get transfers awaiting approval",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	now := time.Now()
+	items := make([]ApprovalInboxItem, 0, len(transfers))
+	for _, transfer := range transfers {
+		var deadlines services.SLADeadlines
+		switch transfer.TransferType {
+		case models.WalletTypeCold:
+			deadlines = s.coldWalletSvc.TransferSLADeadlines(transfer)
+		case models.WalletTypeWarm:
+			deadlines = s.warmWalletSvc.TransferSLADeadlines(transfer)
+		default:
+			continue
+		}
+
+		remaining := deadlines.ProcessingBy.Sub(now)
+		items = append(items, ApprovalInboxItem{
+			Transfer:      transfer,
+			UrgencyLevel:  deadlines.UrgencyLevel,
+			SLA:           deadlines,
+			Deadline:      deadlines.ProcessingBy,
+			TimeRemaining: remaining.Round(time.Second).String(),
+			Breached:      remaining < 0,
+		})
+	}
+
+	sort.SliceStable(items, func(i, j int) bool {
+		ri, rj := urgencyRank(items[i].UrgencyLevel), urgencyRank(items[j].UrgencyLevel)
+		if ri != rj {
+			return ri < rj
+		}
+		return items[i].Deadline.Before(items[j].Deadline)
+	})
+
+	breached := 0
+	for _, item := range items {
+		if item.Breached {
+			breached++
+		}
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"items":    items,
+		"count":    len(items),
+		"breached": breached,
+	})
+}
+
+func urgencyRank(level string) int {
+	if rank, ok := urgencyRanks[level]; ok {
+		return rank
+	}
+	return len(urgencyRanks)
+}
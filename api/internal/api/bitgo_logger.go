@@ -99,6 +99,11 @@ var upgrader = websocket.Upgrader{
 
 // HandleWebSocket handles WebSocket connections for BitGo request logs
 func (s *Server) HandleBitGoRequestLogs(c *gin.Context) {
+	// Request logs expose BitGo request metadata, so require a signed token before upgrading
+	if !s.requireWSToken(c) {
+		return
+	}
+
 	// Upgrade HTTP connection to WebSocket
 	conn, err := upgrader.Upgrade(c.Writer, c.Request, nil)
 	if err != nil {
@@ -0,0 +1,105 @@
+package api
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+
+	"bitgo-wallets-api/internal/models"
+	"bitgo-wallets-api/internal/repository"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+type BlockAddressRequest struct {
+	Address string `json:"address" binding:"required"`
+	Reason  string `json:"reason,omitempty"`
+}
+
+// listBlockedAddresses lists the recipient address denylist
+func (s *Server) listBlockedAddresses(c *gin.Context) {
+	blocked, err := s.blockedAddressRepo.List()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to list blocked addresses", "details": err.Error()})
+		return
+	}
+	if blocked == nil {
+		blocked = []*models.BlockedAddress{}
+	}
+
+	c.JSON(http.StatusOK, gin.H{"blocked_addresses": blocked})
+}
+
+// blockAddress adds a recipient address to the denylist
+func (s *Server) blockAddress(c *gin.Context) {
+	var req BlockAddressRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if strings.TrimSpace(req.Address) == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Address is required"})
+		return
+	}
+
+	blocked := &models.BlockedAddress{Address: req.Address}
+	if reason := strings.TrimSpace(req.Reason); reason != "" {
+		blocked.Reason = &reason
+	}
+
+	if err := s.blockedAddressRepo.Create(blocked); err != nil {
+		if errors.Is(err, repository.ErrDuplicateBlockedAddress) {
+			c.JSON(http.StatusConflict, gin.H{"error": "Address is already blocked"})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to block address", "details": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusCreated, blocked)
+}
+
+// unblockAddress removes an entry from the denylist
+func (s *Server) unblockAddress(c *gin.Context) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid blocked address ID"})
+		return
+	}
+
+	if err := s.blockedAddressRepo.Delete(id); err != nil {
+		if errors.Is(err, repository.ErrBlockedAddressNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Blocked address not found"})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to unblock address", "details": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Address unblocked"})
+}
+
+// rejectBlockedAddress responds with 403 and returns true when the recipient is on the
+// denylist. It applies to every wallet type and can't be overridden per request.
+func (s *Server) rejectBlockedAddress(c *gin.Context, recipient string) bool {
+	blocked, err := s.blockedAddressRepo.GetByAddress(recipient)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to check recipient address", "details": err.Error()})
+		return true
+	}
+	if blocked == nil {
+		return false
+	}
+
+	response := gin.H{
+		"error":   "Recipient address is blocked",
+		"code":    "address_blocked",
+		"address": blocked.Address,
+	}
+	if blocked.Reason != nil {
+		response["reason"] = *blocked.Reason
+	}
+	c.JSON(http.StatusForbidden, response)
+	return true
+}
@@ -0,0 +1,151 @@
+package api
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"bitgo-wallets-api/internal/bitgo"
+	"bitgo-wallets-api/internal/config"
+	"bitgo-wallets-api/internal/models"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+const testAdminKey = "test-admin-key"
+
+func TestBlockedAddressIsRejectedForEveryTransferType(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	wallets := map[models.WalletType]*models.Wallet{}
+	for _, walletType := range []models.WalletType{models.WalletTypeHot, models.WalletTypeWarm, models.WalletTypeCold} {
+		wallets[walletType] = &models.Wallet{ID: uuid.New(), BitgoWalletID: "bitgo-" + string(walletType), Coin: "btc", WalletType: walletType, IsActive: true}
+	}
+	server := &Server{
+		config:             &config.Config{AdminAPIKey: testAdminKey},
+		bitgoClient:        bitgo.NewSimulatedClient(bitgo.SimulationConfig{}, &SimpleLogger{}),
+		walletRepo:         newMemWalletRepo(wallets[models.WalletTypeHot], wallets[models.WalletTypeWarm], wallets[models.WalletTypeCold]),
+		blockedAddressRepo: newMemBlockedAddressRepo(),
+	}
+	router := gin.New()
+	router.POST("/admin/blocked-addresses", server.requireAdmin(), server.blockAddress)
+	router.POST("/wallets/:id/transfers", server.createTransfer)
+	router.POST("/transfers/warm", server.createWarmTransfer)
+	router.POST("/transfers/cold", server.createColdTransfer)
+
+	block := BlockAddressRequest{Address: testBTCAddress, Reason: "sanctioned"}
+	recorder := httptest.NewRecorder()
+	router.ServeHTTP(recorder, httptest.NewRequest(http.MethodPost, "/admin/blocked-addresses", jsonBody(t, block)))
+	if recorder.Code != http.StatusUnauthorized {
+		t.Fatalf("blocking without admin credentials: status = %d, want %d", recorder.Code, http.StatusUnauthorized)
+	}
+
+	request := httptest.NewRequest(http.MethodPost, "/admin/blocked-addresses", jsonBody(t, block))
+	request.Header.Set("X-Admin-Key", testAdminKey)
+	recorder = httptest.NewRecorder()
+	router.ServeHTTP(recorder, request)
+	if recorder.Code != http.StatusCreated {
+		t.Fatalf("blocking as admin: status = %d, want %d: %s", recorder.Code, http.StatusCreated, recorder.Body.String())
+	}
+
+	tests := []struct {
+		name string
+		path string
+		body interface{}
+	}{
+		{
+			name: "hot",
+			path: "/wallets/" + wallets[models.WalletTypeHot].ID.String() + "/transfers",
+			body: CreateTransferRequest{RecipientAddress: testBTCAddress, AmountString: "0.1", Coin: "btc", TransferType: models.WalletTypeHot},
+		},
+		{
+			name: "warm",
+			path: "/transfers/warm",
+			body: map[string]interface{}{"wallet_id": wallets[models.WalletTypeWarm].ID, "recipient_address": testBTCAddress, "amount_string": "0.1", "coin": "btc"},
+		},
+		{
+			name: "cold",
+			path: "/transfers/cold",
+			body: map[string]interface{}{"wallet_id": wallets[models.WalletTypeCold].ID, "recipient_address": testBTCAddress, "amount_string": "0.1", "coin": "btc"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			recorder := httptest.NewRecorder()
+			router.ServeHTTP(recorder, httptest.NewRequest(http.MethodPost, tt.path, jsonBody(t, tt.body)))
+			if recorder.Code != http.StatusForbidden {
+				t.Fatalf("status = %d, want %d: %s", recorder.Code, http.StatusForbidden, recorder.Body.String())
+			}
+
+			var body struct {
+				Code string `json:"code"`
+			}
+			decodeJSON(t, recorder, &body)
+			if body.Code != "address_blocked" {
+				t.Errorf("code = %q, want address_blocked", body.Code)
+			}
+		})
+	}
+}
+
+func TestUnblockAddressRequiresAdmin(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	repo := newMemBlockedAddressRepo()
+	blocked := &models.BlockedAddress{Address: testBTCAddress}
+	if err := repo.Create(blocked); err != nil {
+		t.Fatalf("Create() error = %v", err)
+	}
+	server := &Server{config: &config.Config{AdminAPIKey: testAdminKey}, blockedAddressRepo: repo}
+	router := gin.New()
+	router.DELETE("/admin/blocked-addresses/:id", server.requireAdmin(), server.unblockAddress)
+
+	recorder := httptest.NewRecorder()
+	router.ServeHTTP(recorder, httptest.NewRequest(http.MethodDelete, "/admin/blocked-addresses/"+blocked.ID.String(), nil))
+	if recorder.Code != http.StatusUnauthorized {
+		t.Fatalf("status = %d, want %d", recorder.Code, http.StatusUnauthorized)
+	}
+	if entry, _ := repo.GetByAddress(testBTCAddress); entry == nil {
+		t.Fatal("address was unblocked without admin credentials")
+	}
+
+	request := httptest.NewRequest(http.MethodDelete, "/admin/blocked-addresses/"+blocked.ID.String(), nil)
+	request.Header.Set("X-Admin-Key", testAdminKey)
+	recorder = httptest.NewRecorder()
+	router.ServeHTTP(recorder, request)
+	if recorder.Code != http.StatusOK {
+		t.Fatalf("status = %d, want %d: %s", recorder.Code, http.StatusOK, recorder.Body.String())
+	}
+}
+
+func TestListBlockedAddressesRequiresAdmin(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	repo := newMemBlockedAddressRepo()
+	if err := repo.Create(&models.BlockedAddress{Address: testBTCAddress}); err != nil {
+		t.Fatalf("Create() error = %v", err)
+	}
+	server := &Server{config: &config.Config{AdminAPIKey: testAdminKey}, blockedAddressRepo: repo}
+	router := gin.New()
+	router.GET("/admin/blocked-addresses", server.requireAdmin(), server.listBlockedAddresses)
+
+	recorder := httptest.NewRecorder()
+	router.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/admin/blocked-addresses", nil))
+	if recorder.Code != http.StatusUnauthorized {
+		t.Fatalf("status = %d, want %d", recorder.Code, http.StatusUnauthorized)
+	}
+	if strings.Contains(recorder.Body.String(), testBTCAddress) {
+		t.Fatal("blocked addresses were listed without admin credentials")
+	}
+
+	request := httptest.NewRequest(http.MethodGet, "/admin/blocked-addresses", nil)
+	request.Header.Set("X-Admin-Key", testAdminKey)
+	recorder = httptest.NewRecorder()
+	router.ServeHTTP(recorder, request)
+	if recorder.Code != http.StatusOK {
+		t.Fatalf("status = %d, want %d: %s", recorder.Code, http.StatusOK, recorder.Body.String())
+	}
+	if !strings.Contains(recorder.Body.String(), testBTCAddress) {
+		t.Errorf("body = %s, want it to list %s", recorder.Body.String(), testBTCAddress)
+	}
+}
@@ -0,0 +1,73 @@
+package api
+
+import (
+	"net/http"
+	"strconv"
+
+	"bitgo-wallets-api/internal/bitgo"
+
+	"github.com/gin-gonic/gin"
+)
+
+// CoinResponse describes one coin in the registry, so frontends can format amounts, ask for
+// memos and validate addresses without hardcoding per-coin rules
+type CoinResponse struct {
+	Symbol                 string   `json:"symbol"`
+	Name                   string   `json:"name"`
+	Family                 string   `json:"family"`
+	Testnet                bool     `json:"testnet"`
+	Decimals               int      `json:"decimals"`
+	DustThreshold          *string  `json:"dust_threshold"`            // Decimal amount; null when the coin has no dust limit
+	DustThresholdBaseUnits *string  `json:"dust_threshold_base_units"` // The same limit in base units
+	RequiredConfirmations  int      `json:"required_confirmations"`
+	DefaultAddressType     string   `json:"default_address_type,omitempty"`
+	MemoRequired           bool     `json:"memo_required"`
+	MemoLabel              string   `json:"memo_label,omitempty"`
+	MemoFormat             string   `json:"memo_format,omitempty"`
+	BuildTypes             []string `json:"build_types"`
+	Aliases                []string `json:"aliases"` // Other BitGo symbols that follow this coin's rules
+}
+
+// listCoins returns the coin registry
+func (s *Server) listCoins(c *gin.Context) {
+	aliases := bitgo.CoinAliases()
+	coins := make([]CoinResponse, 0)
+	for _, info := range bitgo.Coins() {
+		coins = append(coins, newCoinResponse(info, aliases[info.Symbol]))
+	}
+
+	c.JSON(http.StatusOK, gin.H{"coins": coins})
+}
+
+func newCoinResponse(info bitgo.CoinInfo, aliases []string) CoinResponse {
+	response := CoinResponse{
+		Symbol:                info.Symbol,
+		Name:                  info.Name,
+		Family:                info.Family,
+		Testnet:               info.Testnet,
+		Decimals:              info.Decimals,
+		RequiredConfirmations: info.RequiredConfirmations,
+		DefaultAddressType:    info.DefaultAddressType,
+		MemoRequired:          info.MemoRequired,
+		MemoLabel:             info.MemoLabel,
+		MemoFormat:            info.MemoFormat,
+		BuildTypes:            info.BuildTypes,
+		Aliases:               aliases,
+	}
+	if response.BuildTypes == nil {
+		response.BuildTypes = []string{bitgo.BuildTypeSend}
+	}
+	if response.Aliases == nil {
+		response.Aliases = []string{}
+	}
+
+	if info.DustThreshold > 0 {
+		baseUnits := strconv.FormatInt(info.DustThreshold, 10)
+		if amount, err := bitgo.AmountFromBaseUnits(info.Symbol, baseUnits); err == nil {
+			response.DustThreshold = &amount
+			response.DustThresholdBaseUnits = &baseUnits
+		}
+	}
+
+	return response
+}
@@ -0,0 +1,53 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+)
+
+func TestListCoins(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.GET("/coins", (&Server{}).listCoins)
+
+	recorder := httptest.NewRecorder()
+	router.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/coins", nil))
+	if recorder.Code != http.StatusOK {
+		t.Fatalf("status = %d, want %d", recorder.Code, http.StatusOK)
+	}
+
+	var body struct {
+		Coins []CoinResponse `json:"coins"`
+	}
+	if err := json.Unmarshal(recorder.Body.Bytes(), &body); err != nil {
+		t.Fatalf("decode response: %v", err)
+	}
+	coins := make(map[string]CoinResponse, len(body.Coins))
+	for _, coin := range body.Coins {
+		coins[coin.Symbol] = coin
+	}
+
+	btc, ok := coins["btc"]
+	if !ok {
+		t.Fatal("btc missing from /coins")
+	}
+	if btc.Decimals != 8 || btc.DustThreshold == nil || *btc.DustThreshold != "0.00000546" ||
+		btc.DustThresholdBaseUnits == nil || *btc.DustThresholdBaseUnits != "546" {
+		t.Errorf("btc = %+v, want 8 decimals and a dust threshold of 0.00000546 (546 base units)", btc)
+	}
+
+	eth, ok := coins["eth"]
+	if !ok {
+		t.Fatal("eth missing from /coins")
+	}
+	if eth.DustThreshold != nil || eth.DustThresholdBaseUnits != nil {
+		t.Errorf("eth has a dust threshold, want none")
+	}
+	if eth.BuildTypes == nil || eth.Aliases == nil {
+		t.Errorf("eth build types %v and aliases %v should be empty lists rather than null", eth.BuildTypes, eth.Aliases)
+	}
+}
@@ -0,0 +1,190 @@
+package api
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"time"
+
+	"bitgo-wallets-api/internal/models"
+	"bitgo-wallets-api/internal/repository"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// transferExportColumns is the CSV header for transfer exports, in column order
+var transferExportColumns = []string{
+	"date", "coin", "amount", "recipient", "status", "fee", "tx_hash", "requestor",
+}
+
+// exportFlushEvery is how many rows are written between flushes to the client
+const exportFlushEvery = 100
+
+// transferExportRow is the JSON shape of an exported transfer
+type transferExportRow struct {
+	Date      string `json:"date"`
+	Coin      string `json:"coin"`
+	Amount    string `json:"amount"`
+	Recipient string `json:"recipient"`
+	Status    string `json:"status"`
+	Fee       string `json:"fee"`
+	TxHash    string `json:"tx_hash"`
+	Requestor string `json:"requestor"`
+}
+
+func newTransferExportRow(transfer *models.TransferRequest, requestorEmail string) transferExportRow {
+	fee := derefString(transfer.FeeString)
+	if fee == "" {
+		fee = derefString(transfer.Fee)
+	}
+	txHash := derefString(transfer.TransactionHash)
+	if txHash == "" {
+		txHash = derefString(transfer.BitgoTxid)
+	}
+	requestor := requestorEmail
+	if requestor == "" {
+		requestor = transfer.RequestedByUserID.String()
+	}
+
+	return transferExportRow{
+		Date:      transfer.CreatedAt.UTC().Format(time.RFC3339),
+		Coin:      transfer.Coin,
+		Amount:    transfer.AmountString,
+		Recipient: transfer.RecipientAddress,
+		Status:    string(transfer.Status),
+		Fee:       fee,
+		TxHash:    txHash,
+		Requestor: requestor,
+	}
+}
+
+func (r transferExportRow) csvRecord() []string {
+	return []string{r.Date, r.Coin, r.Amount, r.Recipient, r.Status, r.Fee, r.TxHash, r.Requestor}
+}
+
+// exportTransfers streams a wallet's full transfer history as CSV (default) or JSON, limited
+// to the transfers the current user may list
+func (s *Server) exportTransfers(c *gin.Context) {
+	walletID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid wallet ID"})
+		return
+	}
+
+	format := c.DefaultQuery("format", "csv")
+	if format != "csv" && format != "json" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid format. Must be 'csv' or 'json'"})
+		return
+	}
+
+	wallet, err := s.walletRepo.GetByID(walletID)
+	if errors.Is(err, repository.ErrWalletNotFound) {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Wallet not found"})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to get wallet"})
+		return
+	}
+
+	requestedBy, ok := s.transferVisibility(c, wallet.ID)
+	if !ok {
+		return
+	}
+
+	filename := fmt.Sprintf("transfers-%s-%s.%s", wallet.ID, time.Now().UTC().Format("20060102"), format)
+	c.Header("Content-Disposition", fmt.Sprintf("attachment; filename=%q", filename))
+
+	if format == "csv" {
+		err = s.streamTransfersCSV(c, wallet.ID, requestedBy)
+	} else {
+		err = s.streamTransfersJSON(c, wallet.ID, requestedBy)
+	}
+	if err != nil {
+		// Headers are already sent, so all we can do is log and cut the response short
+		log.Printf("Transfer export failed for wallet %s: %v", wallet.ID, err)
+	}
+}
+
+func (s *Server) streamTransfersCSV(c *gin.Context, walletID uuid.UUID, requestedBy *uuid.UUID) error {
+	c.Header("Content-Type", "text/csv; charset=utf-8")
+	c.Status(http.StatusOK)
+
+	writer := csv.NewWriter(c.Writer)
+	if err := writer.Write(transferExportColumns); err != nil {
+		return err
+	}
+
+	rows := 0
+	err := s.transferRequestRepo.StreamByWallet(walletID, func(transfer *models.TransferRequest, requestorEmail string) error {
+		if !transferVisibleTo(transfer, requestedBy, true) {
+			return nil
+		}
+		if err := writer.Write(newTransferExportRow(transfer, requestorEmail).csvRecord()); err != nil {
+			return err
+		}
+
+		// Push rows out as we go instead of buffering the whole export
+		rows++
+		if rows%exportFlushEvery == 0 {
+			writer.Flush()
+			c.Writer.Flush()
+		}
+		return writer.Error()
+	})
+
+	writer.Flush()
+	if err != nil {
+		return err
+	}
+	return writer.Error()
+}
+
+func (s *Server) streamTransfersJSON(c *gin.Context, walletID uuid.UUID, requestedBy *uuid.UUID) error {
+	c.Header("Content-Type", "application/json; charset=utf-8")
+	c.Status(http.StatusOK)
+
+	if _, err := c.Writer.WriteString("["); err != nil {
+		return err
+	}
+
+	encoder := json.NewEncoder(c.Writer)
+	rows := 0
+	err := s.transferRequestRepo.StreamByWallet(walletID, func(transfer *models.TransferRequest, requestorEmail string) error {
+		if !transferVisibleTo(transfer, requestedBy, true) {
+			return nil
+		}
+		if rows > 0 {
+			if _, err := c.Writer.WriteString(","); err != nil {
+				return err
+			}
+		}
+
+		if err := encoder.Encode(newTransferExportRow(transfer, requestorEmail)); err != nil {
+			return err
+		}
+
+		rows++
+		if rows%exportFlushEvery == 0 {
+			c.Writer.Flush()
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	_, err = c.Writer.WriteString("]\n")
+	return err
+}
+
+func derefString(value *string) string {
+	if value == nil {
+		return ""
+	}
+	return *value
+}
@@ -0,0 +1,93 @@
+package api
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"bitgo-wallets-api/internal/config"
+	"bitgo-wallets-api/internal/models"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+func TestExportTransfersCSV(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	wallet := &models.Wallet{ID: uuid.New(), BitgoWalletID: "bitgo-wallet-1", Coin: "btc", WalletType: models.WalletTypeHot}
+	created := time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC)
+	requestor := uuid.New()
+	fee, txHash := "0.0001", "tx-hash-1"
+	transfers := []*models.TransferRequest{
+		{ID: uuid.New(), WalletID: wallet.ID, RequestedByUserID: requestor, RecipientAddress: testBTCAddress, AmountString: "0.1", Coin: "btc",
+			Status: models.TransferStatusConfirmed, FeeString: &fee, TransactionHash: &txHash, CreatedAt: created},
+		{ID: uuid.New(), WalletID: wallet.ID, RequestedByUserID: requestor, RecipientAddress: testBTCAddress, AmountString: "0.2", Coin: "btc",
+			Status: models.TransferStatusFailed, CreatedAt: created.Add(time.Hour)},
+		{ID: uuid.New(), WalletID: wallet.ID, RequestedByUserID: requestor, RecipientAddress: testBTCAddress, AmountString: "0.3", Coin: "btc",
+			Status: models.TransferStatusDraft, CreatedAt: created.Add(2 * time.Hour)},
+		// Another wallet's transfer is never exported
+		{ID: uuid.New(), WalletID: uuid.New(), RequestedByUserID: requestor, RecipientAddress: testBTCAddress, AmountString: "9", Coin: "btc",
+			Status: models.TransferStatusDraft, CreatedAt: created},
+	}
+
+	server := &Server{
+		config:              &config.Config{},
+		walletRepo:          newMemWalletRepo(wallet),
+		transferRequestRepo: newMemTransferRepo(transfers...),
+	}
+	router := gin.New()
+	router.GET("/wallets/:id/transfers/export", server.exportTransfers)
+
+	export := func(format string) *httptest.ResponseRecorder {
+		recorder := httptest.NewRecorder()
+		router.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/wallets/"+wallet.ID.String()+"/transfers/export?format="+format, nil))
+		if recorder.Code != http.StatusOK {
+			t.Fatalf("%s export: status = %d, want %d: %s", format, recorder.Code, http.StatusOK, recorder.Body.String())
+		}
+		return recorder
+	}
+
+	t.Run("csv", func(t *testing.T) {
+		recorder := export("csv")
+		if contentType := recorder.Header().Get("Content-Type"); !strings.HasPrefix(contentType, "text/csv") {
+			t.Errorf("Content-Type = %q, want text/csv", contentType)
+		}
+
+		records, err := csv.NewReader(recorder.Body).ReadAll()
+		if err != nil {
+			t.Fatalf("parse CSV: %v", err)
+		}
+		wantHeader := []string{"date", "coin", "amount", "recipient", "status", "fee", "tx_hash", "requestor"}
+		if strings.Join(records[0], ",") != strings.Join(wantHeader, ",") {
+			t.Errorf("header = %v, want %v", records[0], wantHeader)
+		}
+		if rows := len(records) - 1; rows != 3 {
+			t.Fatalf("%d rows, want 3", rows)
+		}
+
+		wantFirst := []string{"2024-03-01T12:00:00Z", "btc", "0.1", testBTCAddress, "confirmed", fee, txHash, requestor.String()}
+		if strings.Join(records[1], ",") != strings.Join(wantFirst, ",") {
+			t.Errorf("first row = %v, want %v", records[1], wantFirst)
+		}
+		for i, amount := range []string{"0.1", "0.2", "0.3"} {
+			if records[i+1][2] != amount {
+				t.Errorf("row %d amount = %q, want %q, oldest first", i+1, records[i+1][2], amount)
+			}
+		}
+	})
+
+	t.Run("json", func(t *testing.T) {
+		var rows []transferExportRow
+		if err := json.Unmarshal(export("json").Body.Bytes(), &rows); err != nil {
+			t.Fatalf("parse JSON: %v", err)
+		}
+		if len(rows) != 3 || rows[0].TxHash != txHash {
+			t.Errorf("rows = %+v, want 3 with the first's tx hash", rows)
+		}
+	})
+}
@@ -0,0 +1,544 @@
+package api
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"net/http/httptest"
+	"sort"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"bitgo-wallets-api/internal/models"
+	"bitgo-wallets-api/internal/repository"
+	"bitgo-wallets-api/internal/services"
+
+	"github.com/google/uuid"
+)
+
+// memTransferRepo keeps transfers in memory, with Update's version check; methods a test
+// doesn't override aren't used
+type memTransferRepo struct {
+	repository.TransferRequestRepository
+
+	mu        sync.Mutex
+	transfers map[uuid.UUID]*models.TransferRequest
+	approvers map[uuid.UUID]map[uuid.UUID]bool // Approvers who have decided, by transfer
+}
+
+func newMemTransferRepo(transfers ...*models.TransferRequest) *memTransferRepo {
+	repo := &memTransferRepo{transfers: make(map[uuid.UUID]*models.TransferRequest)}
+	for _, transfer := range transfers {
+		if transfer.ID == uuid.Nil {
+			transfer.ID = uuid.New()
+		}
+		stored := *transfer
+		repo.transfers[transfer.ID] = &stored
+	}
+	return repo
+}
+
+func (r *memTransferRepo) Create(transfer *models.TransferRequest) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	transfer.ID = uuid.New()
+	if transfer.Origin == "" {
+		transfer.Origin = models.TransferOriginAPI
+	}
+	transfer.Version = 1
+	transfer.CreatedAt = time.Now()
+	transfer.UpdatedAt = transfer.CreatedAt
+	stored := *transfer
+	r.transfers[transfer.ID] = &stored
+	return nil
+}
+
+func (r *memTransferRepo) GetByID(id uuid.UUID) (*models.TransferRequest, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	stored, ok := r.transfers[id]
+	if !ok {
+		return nil, nil
+	}
+	copied := *stored
+	return &copied, nil
+}
+
+func (r *memTransferRepo) Update(transfer *models.TransferRequest) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	stored, ok := r.transfers[transfer.ID]
+	if !ok {
+		return repository.ErrTransferRequestNotFound
+	}
+	if stored.Version != transfer.Version {
+		return repository.ErrTransferVersionConflict
+	}
+	transfer.Version++
+	transfer.UpdatedAt = time.Now()
+	updated := *transfer
+	if updated.Origin == "" {
+		updated.Origin = stored.Origin
+	}
+	r.transfers[transfer.ID] = &updated
+	return nil
+}
+
+func (r *memTransferRepo) UpdateStatus(id uuid.UUID, status models.TransferStatus) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if stored, ok := r.transfers[id]; ok {
+		stored.Status = status
+		stored.Version++
+	}
+	return nil
+}
+
+func (r *memTransferRepo) RecordPollFailure(id uuid.UUID, failures int, pollError string, nextPollAt time.Time, needsAttention bool) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if stored, ok := r.transfers[id]; ok {
+		stored.PollFailures = failures
+		stored.PollError = &pollError
+		stored.NextPollAt = &nextPollAt
+		if needsAttention && stored.NeedsAttentionAt == nil {
+			now := time.Now()
+			stored.NeedsAttentionAt = &now
+		}
+	}
+	return nil
+}
+
+// ListByWallet returns the wallet's transfers newest first, ignoring the filter
+func (r *memTransferRepo) ListByWallet(walletID uuid.UUID, filter repository.TransferListFilter, limit, offset int) ([]*models.TransferRequest, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	var list []*models.TransferRequest
+	for _, stored := range r.transfers {
+		if stored.WalletID == walletID {
+			copied := *stored
+			list = append(list, &copied)
+		}
+	}
+	sort.Slice(list, func(i, j int) bool { return list[i].CreatedAt.After(list[j].CreatedAt) })
+	if offset >= len(list) {
+		return nil, nil
+	}
+	list = list[offset:]
+	if limit > 0 && limit < len(list) {
+		list = list[:limit]
+	}
+	return list, nil
+}
+
+// List returns the wallet's transfers newest first; nothing is archived in memory
+func (r *memTransferRepo) List(walletID uuid.UUID, includeArchived bool, limit, offset int) ([]*models.TransferRequest, error) {
+	return r.ListByWallet(walletID, repository.TransferListFilter{}, limit, offset)
+}
+
+// StreamByWallet calls fn for the wallet's transfers oldest first, without requestor emails
+func (r *memTransferRepo) StreamByWallet(walletID uuid.UUID, fn func(transfer *models.TransferRequest, requestorEmail string) error) error {
+	list, _ := r.ListByWallet(walletID, repository.TransferListFilter{}, 0, 0)
+	for i := len(list) - 1; i >= 0; i-- {
+		if err := fn(list[i], ""); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// listByStatuses returns copies of the transfers in any of the statuses, oldest first
+func (r *memTransferRepo) listByStatuses(statuses []models.TransferStatus) []*models.TransferRequest {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	var list []*models.TransferRequest
+	for _, stored := range r.transfers {
+		for _, status := range statuses {
+			if stored.Status == status {
+				copied := *stored
+				list = append(list, &copied)
+				break
+			}
+		}
+	}
+	sort.Slice(list, func(i, j int) bool { return list[i].CreatedAt.Before(list[j].CreatedAt) })
+	return list
+}
+
+func (r *memTransferRepo) GetTransfersByStatuses(statuses []models.TransferStatus, limit int) ([]*models.TransferRequest, error) {
+	list := r.listByStatuses(statuses)
+	if len(list) > limit {
+		list = list[:limit]
+	}
+	return list, nil
+}
+
+func (r *memTransferRepo) StreamByTypeAndStatuses(transferType models.WalletType, statuses []models.TransferStatus, fn func(transfer *models.TransferRequest) error) error {
+	for _, transfer := range r.listByStatuses(statuses) {
+		if transfer.TransferType != transferType {
+			continue
+		}
+		if err := fn(transfer); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (r *memTransferRepo) ListBitGoReferences(walletID uuid.UUID) (map[string]bool, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	references := make(map[string]bool)
+	for _, stored := range r.transfers {
+		if stored.WalletID != walletID {
+			continue
+		}
+		for _, reference := range []*string{stored.BitgoTransferID, stored.BitgoTxid, stored.TransactionHash} {
+			if reference != nil && *reference != "" {
+				references[*reference] = true
+			}
+		}
+	}
+	return references, nil
+}
+
+// SearchByRecipient matches recipients exactly or by prefix, oldest first, ignoring archiving
+func (r *memTransferRepo) SearchByRecipient(address string, prefix, includeArchived bool, limit, offset int) ([]*models.TransferSearchResult, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	var results []*models.TransferSearchResult
+	for _, stored := range r.transfers {
+		if stored.RecipientAddress == address || prefix && strings.HasPrefix(stored.RecipientAddress, address) {
+			copied := *stored
+			results = append(results, &models.TransferSearchResult{Transfer: &copied, Wallet: models.TransferWallet{ID: stored.WalletID}})
+		}
+	}
+	sort.Slice(results, func(i, j int) bool { return results[i].Transfer.CreatedAt.Before(results[j].Transfer.CreatedAt) })
+	if offset >= len(results) {
+		return nil, nil
+	}
+	results = results[offset:]
+	if limit > 0 && limit < len(results) {
+		results = results[:limit]
+	}
+	return results, nil
+}
+
+func (r *memTransferRepo) CountByRecipient(address string, prefix, includeArchived bool) (int, error) {
+	results, err := r.SearchByRecipient(address, prefix, includeArchived, 0, 0)
+	return len(results), err
+}
+
+// DecideApproval applies a decision like the database does, counting each approver once
+func (r *memTransferRepo) DecideApproval(id uuid.UUID, approverID *uuid.UUID, approve bool, reason *string) (*models.TransferRequest, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	stored, ok := r.transfers[id]
+	if !ok {
+		return nil, repository.ErrTransferRequestNotFound
+	}
+	if stored.Status != models.TransferStatusSubmitted && stored.Status != models.TransferStatusPendingApproval {
+		copied := *stored
+		return &copied, repository.ErrApprovalResolved
+	}
+	if stored.SubmittedAt != nil {
+		copied := *stored
+		return &copied, repository.ErrAwaitingBitGoApproval
+	}
+	if approverID != nil {
+		if r.approvers == nil {
+			r.approvers = make(map[uuid.UUID]map[uuid.UUID]bool)
+		}
+		if r.approvers[id][*approverID] {
+			copied := *stored
+			return &copied, repository.ErrAlreadyDecided
+		}
+		if r.approvers[id] == nil {
+			r.approvers[id] = make(map[uuid.UUID]bool)
+		}
+		r.approvers[id][*approverID] = true
+	}
+
+	now := time.Now()
+	switch {
+	case !approve:
+		stored.Status = models.TransferStatusRejected
+		stored.StatusReason = reason
+	case stored.ReceivedApprovals+1 >= stored.RequiredApprovals:
+		stored.ReceivedApprovals++
+		stored.Status = models.TransferStatusApproved
+		stored.ApprovedAt = &now
+	default:
+		stored.ReceivedApprovals++
+	}
+	stored.Version++
+	stored.UpdatedAt = now
+	copied := *stored
+	return &copied, nil
+}
+
+// memWalletRepo serves wallets from memory; methods a test doesn't override aren't used
+type memWalletRepo struct {
+	repository.WalletRepository
+	wallets map[uuid.UUID]*models.Wallet
+}
+
+func newMemWalletRepo(wallets ...*models.Wallet) *memWalletRepo {
+	repo := &memWalletRepo{wallets: make(map[uuid.UUID]*models.Wallet)}
+	for _, wallet := range wallets {
+		if wallet.ID == uuid.Nil {
+			wallet.ID = uuid.New()
+		}
+		repo.wallets[wallet.ID] = wallet
+	}
+	return repo
+}
+
+func (r *memWalletRepo) GetByID(id uuid.UUID) (*models.Wallet, error) {
+	wallet, ok := r.wallets[id]
+	if !ok {
+		return nil, repository.ErrWalletNotFound
+	}
+	copied := *wallet
+	return &copied, nil
+}
+
+func (r *memWalletRepo) GetByBitgoID(bitgoWalletID string) (*models.Wallet, error) {
+	for _, wallet := range r.wallets {
+		if wallet.BitgoWalletID == bitgoWalletID {
+			copied := *wallet
+			return &copied, nil
+		}
+	}
+	return nil, repository.ErrWalletNotFound
+}
+
+func (r *memWalletRepo) Create(wallet *models.Wallet) error {
+	wallet.ID = uuid.New()
+	stored := *wallet
+	r.wallets[wallet.ID] = &stored
+	return nil
+}
+
+func (r *memWalletRepo) Update(wallet *models.Wallet) error {
+	if _, ok := r.wallets[wallet.ID]; !ok {
+		return repository.ErrWalletNotFound
+	}
+	stored := *wallet
+	r.wallets[wallet.ID] = &stored
+	return nil
+}
+
+func (r *memWalletRepo) SetRequiredApprovalsOverride(id uuid.UUID, override *int) error {
+	wallet, ok := r.wallets[id]
+	if !ok {
+		return repository.ErrWalletNotFound
+	}
+	wallet.RequiredApprovalsOverride = override
+	return nil
+}
+
+func (r *memWalletRepo) SetFrozen(id uuid.UUID, frozen bool, metadata models.JSON) error {
+	wallet, ok := r.wallets[id]
+	if !ok {
+		return repository.ErrWalletNotFound
+	}
+	wallet.Frozen = frozen
+	wallet.Metadata = metadata
+	return nil
+}
+
+// memWalletAddressRepo knows of no generated addresses, so no recipient is a self-send
+type memWalletAddressRepo struct {
+	repository.WalletAddressRepository
+}
+
+func (memWalletAddressRepo) GetByAddress(uuid.UUID, string) (*models.WalletAddress, error) {
+	return nil, nil
+}
+
+// nopTransferEventRepo has no recorded events
+type nopTransferEventRepo struct {
+	repository.TransferEventRepository
+}
+
+func (nopTransferEventRepo) ListByTransfer(uuid.UUID, int64, int) ([]*models.TransferEvent, error) {
+	return nil, nil
+}
+
+// memMembershipRepo holds wallet memberships by wallet and user
+type memMembershipRepo struct {
+	repository.WalletMembershipRepository
+	memberships map[[2]uuid.UUID]*models.WalletMembership
+	emails      map[uuid.UUID]string // User emails for ListApprovers
+}
+
+func newMemMembershipRepo(memberships ...*models.WalletMembership) *memMembershipRepo {
+	repo := &memMembershipRepo{memberships: make(map[[2]uuid.UUID]*models.WalletMembership)}
+	for _, membership := range memberships {
+		repo.memberships[[2]uuid.UUID{membership.WalletID, membership.UserID}] = membership
+	}
+	return repo
+}
+
+func (r *memMembershipRepo) GetByWalletAndUser(walletID, userID uuid.UUID) (*models.WalletMembership, error) {
+	return r.memberships[[2]uuid.UUID{walletID, userID}], nil
+}
+
+// ListApprovers returns the wallet's approver and admin members by email; user roles aren't modelled
+func (r *memMembershipRepo) ListApprovers(walletID uuid.UUID) ([]*models.WalletApprover, error) {
+	var approvers []*models.WalletApprover
+	for _, membership := range r.memberships {
+		role := models.WalletRole(membership.Role)
+		if membership.WalletID != walletID || (role != models.WalletRoleApprover && role != models.WalletRoleAdmin) {
+			continue
+		}
+		approvers = append(approvers, &models.WalletApprover{UserID: membership.UserID, Email: r.emails[membership.UserID], WalletRole: role})
+	}
+	sort.Slice(approvers, func(i, j int) bool { return approvers[i].Email < approvers[j].Email })
+	return approvers, nil
+}
+
+// memBlockedAddressRepo keeps the denylist in memory
+type memBlockedAddressRepo struct {
+	mu      sync.Mutex
+	entries map[uuid.UUID]*models.BlockedAddress
+}
+
+func newMemBlockedAddressRepo() *memBlockedAddressRepo {
+	return &memBlockedAddressRepo{entries: make(map[uuid.UUID]*models.BlockedAddress)}
+}
+
+func (r *memBlockedAddressRepo) Create(blocked *models.BlockedAddress) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	blocked.Address = repository.NormalizeBlockedAddress(blocked.Address)
+	for _, entry := range r.entries {
+		if entry.Address == blocked.Address {
+			return repository.ErrDuplicateBlockedAddress
+		}
+	}
+	blocked.ID = uuid.New()
+	blocked.CreatedAt = time.Now()
+	stored := *blocked
+	r.entries[blocked.ID] = &stored
+	return nil
+}
+
+func (r *memBlockedAddressRepo) Delete(id uuid.UUID) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if _, ok := r.entries[id]; !ok {
+		return repository.ErrBlockedAddressNotFound
+	}
+	delete(r.entries, id)
+	return nil
+}
+
+func (r *memBlockedAddressRepo) GetByAddress(address string) (*models.BlockedAddress, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	address = repository.NormalizeBlockedAddress(address)
+	for _, entry := range r.entries {
+		if entry.Address == address {
+			copied := *entry
+			return &copied, nil
+		}
+	}
+	return nil, nil
+}
+
+func (r *memBlockedAddressRepo) List() ([]*models.BlockedAddress, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	list := make([]*models.BlockedAddress, 0, len(r.entries))
+	for _, entry := range r.entries {
+		copied := *entry
+		list = append(list, &copied)
+	}
+	return list, nil
+}
+
+// memNotificationRepo keeps notification records in memory in the order first saved
+type memNotificationRepo struct {
+	mu      sync.Mutex
+	records []*models.NotificationRecord
+}
+
+func (r *memNotificationRepo) Save(record *models.NotificationRecord) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	copied := *record
+	for i, existing := range r.records {
+		if existing.ID == record.ID {
+			r.records[i] = &copied
+			return nil
+		}
+	}
+	r.records = append(r.records, &copied)
+	return nil
+}
+
+func (r *memNotificationRepo) ListByTransfer(transferID uuid.UUID) ([]*models.NotificationRecord, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	var list []*models.NotificationRecord
+	for _, record := range r.records {
+		if record.TransferID != nil && *record.TransferID == transferID {
+			copied := *record
+			list = append(list, &copied)
+		}
+	}
+	return list, nil
+}
+
+// nopNotifier drops notifications
+type nopNotifier struct {
+	services.NotificationService
+}
+
+func (nopNotifier) SendTransferStatusNotification(*models.TransferRequest, models.TransferStatus, models.TransferStatus) {
+}
+func (nopNotifier) SendTransferCreatedNotification(*models.TransferRequest)         {}
+func (nopNotifier) SendTransferCompletedNotification(*models.TransferRequest)       {}
+func (nopNotifier) SendTransferFailedNotification(*models.TransferRequest, string)  {}
+func (nopNotifier) SendTransferExpiredNotification(*models.TransferRequest, string) {}
+func (nopNotifier) SendWalletFrozenNotification(*models.Wallet, string)             {}
+
+// testClock is a settable clock
+type testClock struct {
+	mu  sync.Mutex
+	now time.Time
+}
+
+func (c *testClock) Now() time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.now
+}
+
+func (c *testClock) Set(now time.Time) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.now = now
+}
+
+// jsonBody encodes v as a request body
+func jsonBody(t *testing.T, v interface{}) io.Reader {
+	t.Helper()
+	body, err := json.Marshal(v)
+	if err != nil {
+		t.Fatalf("encode request: %v", err)
+	}
+	return bytes.NewReader(body)
+}
+
+// decodeJSON decodes the recorded response body into v
+func decodeJSON(t *testing.T, recorder *httptest.ResponseRecorder, v interface{}) {
+	t.Helper()
+	if err := json.Unmarshal(recorder.Body.Bytes(), v); err != nil {
+		t.Fatalf("decode response: %v: %s", err, recorder.Body.String())
+	}
+}
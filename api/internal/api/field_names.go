@@ -0,0 +1,77 @@
+package api
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"strings"
+	"unicode"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Request bodies use snake_case field names. Transfer endpoints used to accept camelCase
+// on some routes, so during the deprecation window camelCase keys are still translated
+// and the response carries a Deprecation header. When both spellings are sent the
+// snake_case value wins.
+
+// deprecatedFieldsHeader lists the camelCase fields a request used
+const deprecatedFieldsHeader = "X-Deprecated-Fields"
+
+// acceptCamelCaseFields rewrites top-level camelCase keys in a JSON request body to
+// snake_case so the handler can bind it normally. Bodies that aren't JSON objects are left
+// for the binder to reject.
+func acceptCamelCaseFields(c *gin.Context) error {
+	body, err := io.ReadAll(c.Request.Body)
+	if err != nil {
+		return err
+	}
+	c.Request.Body = io.NopCloser(bytes.NewReader(body))
+
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(body, &fields); err != nil {
+		return nil
+	}
+
+	var deprecated []string
+	for key, value := range fields {
+		snake := toSnakeCase(key)
+		if snake == key {
+			continue
+		}
+		deprecated = append(deprecated, key)
+		delete(fields, key)
+		if _, exists := fields[snake]; !exists {
+			fields[snake] = value
+		}
+	}
+	if len(deprecated) == 0 {
+		return nil
+	}
+
+	rewritten, err := json.Marshal(fields)
+	if err != nil {
+		return err
+	}
+	c.Request.Body = io.NopCloser(bytes.NewReader(rewritten))
+
+	c.Header("Deprecation", "true")
+	c.Header(deprecatedFieldsHeader, strings.Join(deprecated, ", "))
+	return nil
+}
+
+// toSnakeCase converts a camelCase name such as walletId to wallet_id
+func toSnakeCase(name string) string {
+	var b strings.Builder
+	for i, r := range name {
+		if unicode.IsUpper(r) {
+			if i > 0 {
+				b.WriteByte('_')
+			}
+			b.WriteRune(unicode.ToLower(r))
+			continue
+		}
+		b.WriteRune(r)
+	}
+	return b.String()
+}
@@ -0,0 +1,88 @@
+package api
+
+import (
+	"net/http"
+	"sort"
+	"strings"
+	"testing"
+
+	"bitgo-wallets-api/internal/bitgo"
+	"bitgo-wallets-api/internal/models"
+	"bitgo-wallets-api/internal/repository"
+
+	"github.com/google/uuid"
+)
+
+func TestCreateTransferAcceptsSnakeAndCamelCaseFields(t *testing.T) {
+	wallet := &models.Wallet{ID: uuid.New(), BitgoWalletID: "bitgo-btc-1", Coin: "btc", WalletType: models.WalletTypeHot, SpendableBalanceString: "10", IsActive: true}
+	client := bitgo.NewSimulatedClient(bitgo.SimulationConfig{}, &SimpleLogger{})
+
+	tests := []struct {
+		name           string
+		body           map[string]interface{}
+		wantAmount     string
+		wantDeprecated []string
+	}{
+		{
+			name:       "snake_case",
+			body:       map[string]interface{}{"recipient_address": testBTCAddress, "amount_string": "0.01", "coin": "btc", "transfer_type": "hot"},
+			wantAmount: "0.01",
+		},
+		{
+			name:           "camelCase",
+			body:           map[string]interface{}{"recipientAddress": testBTCAddress, "amountString": "0.02", "coin": "btc", "transferType": "hot"},
+			wantAmount:     "0.02",
+			wantDeprecated: []string{"amountString", "recipientAddress", "transferType"},
+		},
+		{
+			name:           "both spellings, snake_case wins",
+			body:           map[string]interface{}{"recipient_address": testBTCAddress, "amount_string": "0.03", "amountString": "0.5", "coin": "btc", "transfer_type": "hot"},
+			wantAmount:     "0.03",
+			wantDeprecated: []string{"amountString"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, router, repo := newHotTransferTestServer(wallet, client)
+			recorder := postTransfer(t, router, wallet, tt.body)
+			if recorder.Code != http.StatusCreated {
+				t.Fatalf("status = %d, want %d: %s", recorder.Code, http.StatusCreated, recorder.Body.String())
+			}
+
+			transfers, err := repo.ListByWallet(wallet.ID, repository.TransferListFilter{}, 0, 0)
+			if err != nil || len(transfers) != 1 {
+				t.Fatalf("ListByWallet() = %d transfers, %v; want 1", len(transfers), err)
+			}
+			if transfers[0].AmountString != tt.wantAmount || transfers[0].RecipientAddress != testBTCAddress {
+				t.Errorf("stored %s to %s, want %s to %s", transfers[0].AmountString, transfers[0].RecipientAddress, tt.wantAmount, testBTCAddress)
+			}
+
+			var deprecated []string
+			if header := recorder.Header().Get(deprecatedFieldsHeader); header != "" {
+				deprecated = strings.Split(header, ", ")
+				sort.Strings(deprecated)
+			}
+			if strings.Join(deprecated, ",") != strings.Join(tt.wantDeprecated, ",") {
+				t.Errorf("%s = %v, want %v", deprecatedFieldsHeader, deprecated, tt.wantDeprecated)
+			}
+			if wantHeader := len(tt.wantDeprecated) > 0; (recorder.Header().Get("Deprecation") == "true") != wantHeader {
+				t.Errorf("Deprecation header = %q, want set = %v", recorder.Header().Get("Deprecation"), wantHeader)
+			}
+		})
+	}
+}
+
+func TestToSnakeCase(t *testing.T) {
+	for name, want := range map[string]string{
+		"walletId":         "wallet_id",
+		"recipientAddress": "recipient_address",
+		"amount_string":    "amount_string",
+		"coin":             "coin",
+		"Memo":             "memo",
+	} {
+		if got := toSnakeCase(name); got != want {
+			t.Errorf("toSnakeCase(%q) = %q, want %q", name, got, want)
+		}
+	}
+}
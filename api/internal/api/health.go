@@ -17,6 +17,9 @@ type HealthResponse struct {
 	Database  string    `json:"database"`
 }
 
+// healthCheck is the liveness probe: it answers 200 whenever the process can serve HTTP.
+// The database state is reported but doesn't fail the probe, since restarting the process
+// won't fix an unreachable database; /readyz is what gates traffic on dependencies.
 func (s *Server) healthCheck(c *gin.Context) {
 	// Check database connection
 	dbStatus := "ok"
@@ -31,15 +34,88 @@ func (s *Server) healthCheck(c *gin.Context) {
 		Database:  dbStatus,
 	}
 
+	c.JSON(http.StatusOK, response)
+}
+
+// bitgoProbeTTL is how long a BitGo reachability result is reused by /readyz
+const bitgoProbeTTL = 30 * time.Second
+
+// bitgoProbeTimeout bounds the BitGo call made by a readiness check
+const bitgoProbeTimeout = 5 * time.Second
+
+type bitgoProbeResult struct {
+	checkedAt time.Time
+	err       error
+}
+
+// ReadinessResponse reports each dependency readiness depends on
+type ReadinessResponse struct {
+	Status    string            `json:"status"`
+	Timestamp time.Time         `json:"timestamp"`
+	Checks    map[string]string `json:"checks"`
+}
+
+// readinessCheck is the readiness probe. It answers 200 only once startup has finished and
+// the database, the polling worker and BitGo are all usable, and 503 otherwise, so
+// orchestrators route traffic to the instance only while it can serve transfers.
+func (s *Server) readinessCheck(c *gin.Context) {
+	checks := map[string]string{
+		"startup":       "ok",
+		"database":      "ok",
+		"pollingWorker": "ok",
+		"bitgo":         "ok",
+	}
+	ready := true
+
+	if !s.started.Load() {
+		checks["startup"] = "starting"
+		ready = false
+	}
+	if err := s.db.Ping(); err != nil {
+		checks["database"] = "error: " + err.Error()
+		ready = false
+	}
+	if status, _ := s.pollingWorker.HealthCheck()["status"].(string); status != "running" {
+		checks["pollingWorker"] = status
+		ready = false
+	}
+	if err := s.probeBitGo(); err != nil {
+		checks["bitgo"] = "error: " + err.Error()
+		ready = false
+	}
+
+	response := ReadinessResponse{
+		Status:    "ready",
+		Timestamp: time.Now(),
+		Checks:    checks,
+	}
 	statusCode := http.StatusOK
-	if dbStatus == "error" {
-		response.Status = "error"
+	if !ready {
+		response.Status = "not_ready"
 		statusCode = http.StatusServiceUnavailable
 	}
 
 	c.JSON(statusCode, response)
 }
 
+// probeBitGo checks that BitGo answers with the configured credentials, reusing the last
+// result for bitgoProbeTTL
+func (s *Server) probeBitGo() error {
+	s.bitgoProbeMu.Lock()
+	defer s.bitgoProbeMu.Unlock()
+
+	if !s.bitgoProbe.checkedAt.IsZero() && time.Since(s.bitgoProbe.checkedAt) < bitgoProbeTTL {
+		return s.bitgoProbe.err
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), bitgoProbeTimeout)
+	defer cancel()
+
+	_, err := s.bitgoClient.GetCurrentUser(ctx)
+	s.bitgoProbe = bitgoProbeResult{checkedAt: time.Now(), err: err}
+	return err
+}
+
 // DetailedHealthResponse includes background service status
 type DetailedHealthResponse struct {
 	Status         string                 `json:"status"`
@@ -66,13 +142,18 @@ func (s *Server) detailedHealthCheck(c *gin.Context) {
 		Version:   "1.0.0",
 		Database:  dbStatus,
 		BackgroundJobs: map[string]interface{}{
-			"pollingWorker": pollingWorkerHealth,
+			"pollingWorker":    pollingWorkerHealth,
+			"approvalSweeper":  s.approvalSweeper.HealthCheck(),
+			"submissionWorker": s.submissionWorker.HealthCheck(),
+			"retentionJob":     s.retentionJob.HealthCheck(),
 		},
 		Notifications: map[string]interface{}{
 			"service": "running",
 		},
 	}
 
+	// A stopped or stalled polling worker (no completed cycle within its stall threshold)
+	// degrades the service
 	statusCode := http.StatusOK
 	if dbStatus == "error" || pollingWorkerHealth["status"] != "running" {
 		response.Status = "degraded"
@@ -0,0 +1,98 @@
+package api
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"bitgo-wallets-api/internal/bitgo"
+	"bitgo-wallets-api/internal/models"
+	"bitgo-wallets-api/internal/repository"
+	"bitgo-wallets-api/internal/services"
+
+	"github.com/gin-gonic/gin"
+)
+
+// pingableConnector opens connections that answer pings and nothing else
+type pingableConnector struct{}
+
+func (pingableConnector) Connect(context.Context) (driver.Conn, error) { return pingableConn{}, nil }
+func (pingableConnector) Driver() driver.Driver                        { return nil }
+
+type pingableConn struct{}
+
+func (pingableConn) Prepare(string) (driver.Stmt, error) { return nil, errors.New("not supported") }
+func (pingableConn) Close() error                        { return nil }
+func (pingableConn) Begin() (driver.Tx, error)           { return nil, errors.New("not supported") }
+func (pingableConn) Ping(context.Context) error          { return nil }
+
+// idleTransferRepo has no transfers for the background workers to poll, submit, expire or archive
+type idleTransferRepo struct {
+	repository.TransferRequestRepository
+}
+
+func (idleTransferRepo) ListDueForPolling([]models.TransferStatus, map[models.WalletType]time.Time, time.Time, *repository.TransferCursor, int) ([]*models.TransferRequest, error) {
+	return nil, nil
+}
+
+func (idleTransferRepo) ListByTypeAndStatusesAfter(models.WalletType, []models.TransferStatus, *repository.TransferCursor, int) ([]*models.TransferRequest, error) {
+	return nil, nil
+}
+
+func (idleTransferRepo) ListUnsubmittedCreatedBefore(models.WalletType, []models.TransferStatus, time.Time, int) ([]*models.TransferRequest, error) {
+	return nil, nil
+}
+
+func (idleTransferRepo) ArchiveInactiveSince([]models.TransferStatus, time.Time, int) (int64, error) {
+	return 0, nil
+}
+
+func TestReadinessFailsWhenPollingWorkerStops(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	db := sql.OpenDB(pingableConnector{})
+	t.Cleanup(func() { db.Close() })
+
+	client := bitgo.NewSimulatedClient(bitgo.SimulationConfig{}, &SimpleLogger{})
+	worker := services.NewTransferPollingWorker(services.DefaultPollingWorkerConfig(), &SimpleLogger{}, client, idleTransferRepo{}, nil, nopNotifier{})
+	if err := worker.Start(); err != nil {
+		t.Fatalf("Start() error = %v", err)
+	}
+
+	server := &Server{db: db, bitgoClient: client, pollingWorker: worker}
+	server.started.Store(true)
+	router := gin.New()
+	router.GET("/readyz", server.readinessCheck)
+
+	ready := func() (int, ReadinessResponse) {
+		recorder := httptest.NewRecorder()
+		router.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/readyz", nil))
+		var body ReadinessResponse
+		decodeJSON(t, recorder, &body)
+		return recorder.Code, body
+	}
+
+	if code, body := ready(); code != http.StatusOK || body.Status != "ready" {
+		t.Fatalf("readiness with the worker running = %d %+v, want %d ready", code, body, http.StatusOK)
+	}
+
+	if err := worker.Stop(); err != nil {
+		t.Fatalf("Stop() error = %v", err)
+	}
+	code, body := ready()
+	if code != http.StatusServiceUnavailable || body.Status != "not_ready" {
+		t.Errorf("readiness after the worker stopped = %d %s, want %d not_ready", code, body.Status, http.StatusServiceUnavailable)
+	}
+	if body.Checks["pollingWorker"] != "stopped" {
+		t.Errorf("pollingWorker check = %q, want stopped", body.Checks["pollingWorker"])
+	}
+	for _, check := range []string{"startup", "database", "bitgo"} {
+		if body.Checks[check] != "ok" {
+			t.Errorf("%s check = %q, want ok", check, body.Checks[check])
+		}
+	}
+}
@@ -0,0 +1,118 @@
+package api
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"net/http"
+
+	"bitgo-wallets-api/internal/bitgo"
+
+	"github.com/gin-gonic/gin"
+)
+
+// idempotencyKeyHeader is the request header clients use to make a POST safe to retry
+const idempotencyKeyHeader = "Idempotency-Key"
+
+// idempotentResponse is the response cached for a completed idempotent request
+type idempotentResponse struct {
+	Status      int
+	ContentType string
+	Body        []byte
+}
+
+// responseRecorder captures the response body so it can be replayed for retried requests
+type responseRecorder struct {
+	gin.ResponseWriter
+	body bytes.Buffer
+}
+
+func (w *responseRecorder) Write(data []byte) (int, error) {
+	w.body.Write(data)
+	return w.ResponseWriter.Write(data)
+}
+
+func (w *responseRecorder) WriteString(data string) (int, error) {
+	w.body.WriteString(data)
+	return w.ResponseWriter.WriteString(data)
+}
+
+// idempotencyMiddleware replays the stored response when a request is retried with the same
+// Idempotency-Key and body, and rejects reuse of a key with a different body with 409.
+// Requests without the header are passed through unchanged; 5xx responses are not cached.
+func (s *Server) idempotencyMiddleware() gin.HandlerFunc {
+	return gin.HandlerFunc(func(c *gin.Context) {
+		key := c.GetHeader(idempotencyKeyHeader)
+		if key == "" {
+			c.Next()
+			return
+		}
+		c.Header(idempotencyKeyHeader, key)
+
+		body, err := io.ReadAll(c.Request.Body)
+		if err != nil {
+			c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"error": "Failed to read request body", "details": err.Error()})
+			return
+		}
+		c.Request.Body = io.NopCloser(bytes.NewReader(body))
+
+		// Keys are scoped to the route so the same key can't collide across endpoints
+		operation := c.Request.Method + " " + c.Request.URL.Path
+		cacheKey := operation + " " + key
+
+		record, isNew, err := s.idempotencySvc.CheckOrStore(context.Background(), cacheKey, operation, body)
+		if errors.Is(err, bitgo.ErrIdempotencyKeyConflict) {
+			c.AbortWithStatusJSON(http.StatusConflict, gin.H{
+				"error":           "idempotency key reused with different parameters",
+				"idempotency_key": key,
+			})
+			return
+		}
+		if err != nil {
+			c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{"error": "Failed to check idempotency key", "details": err.Error()})
+			return
+		}
+
+		if !isNew {
+			cached, ok := record.Response.(*idempotentResponse)
+			if record.Status != bitgo.IdempotencyStatusCompleted || !ok {
+				c.AbortWithStatusJSON(http.StatusConflict, gin.H{
+					"error":           "A request with this idempotency key is still in progress",
+					"idempotency_key": key,
+				})
+				return
+			}
+
+			c.Header("Idempotent-Replayed", "true")
+			c.Data(cached.Status, cached.ContentType, cached.Body)
+			c.Abort()
+			return
+		}
+
+		// Don't leave the key stuck in progress if the handler panics
+		defer func() {
+			if r := recover(); r != nil {
+				s.idempotencySvc.DeleteRecord(cacheKey)
+				panic(r)
+			}
+		}()
+
+		recorder := &responseRecorder{ResponseWriter: c.Writer}
+		c.Writer = recorder
+		c.Next()
+
+		status := recorder.Status()
+		if status >= http.StatusInternalServerError || status == http.StatusPreconditionRequired {
+			// Let the client retry server-side failures, or resend with an OTP, using the same key
+			s.idempotencySvc.DeleteRecord(cacheKey)
+			return
+		}
+
+		s.idempotencySvc.UpdateRecord(cacheKey, bitgo.IdempotencyStatusCompleted, &idempotentResponse{
+			Status:      status,
+			ContentType: recorder.Header().Get("Content-Type"),
+			Body:        recorder.body.Bytes(),
+		}, nil)
+	})
+}
@@ -0,0 +1,74 @@
+package api
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"bitgo-wallets-api/internal/bitgo"
+
+	"github.com/gin-gonic/gin"
+)
+
+func TestIdempotencyMiddleware(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	server := &Server{idempotencySvc: bitgo.NewIdempotencyService(&SimpleLogger{}, time.Hour)}
+
+	calls := 0
+	router := gin.New()
+	router.POST("/transfers", server.idempotencyMiddleware(), func(c *gin.Context) {
+		calls++
+		c.JSON(http.StatusCreated, gin.H{"call": calls})
+	})
+
+	post := func(key, body string) *httptest.ResponseRecorder {
+		request := httptest.NewRequest(http.MethodPost, "/transfers", strings.NewReader(body))
+		request.Header.Set(idempotencyKeyHeader, key)
+		recorder := httptest.NewRecorder()
+		router.ServeHTTP(recorder, request)
+		return recorder
+	}
+
+	first := post("key-1", `{"amount":"0.1"}`)
+	if first.Code != http.StatusCreated {
+		t.Fatalf("first request: status = %d, want %d", first.Code, http.StatusCreated)
+	}
+
+	t.Run("same key and body", func(t *testing.T) {
+		replay := post("key-1", `{"amount":"0.1"}`)
+		if replay.Code != http.StatusCreated || replay.Body.String() != first.Body.String() {
+			t.Errorf("replay = %d %s, want the cached %d %s", replay.Code, replay.Body.String(), first.Code, first.Body.String())
+		}
+		if replay.Header().Get("Idempotent-Replayed") != "true" {
+			t.Error("replay isn't marked Idempotent-Replayed")
+		}
+		if calls != 1 {
+			t.Errorf("handler ran %d times, want 1", calls)
+		}
+	})
+
+	t.Run("same key different body", func(t *testing.T) {
+		conflict := post("key-1", `{"amount":"5"}`)
+		if conflict.Code != http.StatusConflict {
+			t.Fatalf("status = %d, want %d", conflict.Code, http.StatusConflict)
+		}
+		var body struct {
+			Error string `json:"error"`
+		}
+		decodeJSON(t, conflict, &body)
+		if body.Error != "idempotency key reused with different parameters" {
+			t.Errorf("error = %q", body.Error)
+		}
+		if calls != 1 {
+			t.Errorf("handler ran %d times, want 1", calls)
+		}
+	})
+
+	t.Run("new key", func(t *testing.T) {
+		if recorder := post("key-2", `{"amount":"5"}`); recorder.Code != http.StatusCreated || calls != 2 {
+			t.Errorf("status = %d after %d calls, want a fresh %d", recorder.Code, calls, http.StatusCreated)
+		}
+	})
+}
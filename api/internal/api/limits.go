@@ -0,0 +1,85 @@
+package api
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// errJSONTooDeep is returned when a JSON body nests deeper than the configured limit
+var errJSONTooDeep = errors.New("JSON body is nested too deeply")
+
+// bodyLimitMiddleware caps request bodies at MaxRequestBodyBytes and rejects JSON bodies
+// nested deeper than MaxJSONDepth. Both cases are answered with 413.
+func (s *Server) bodyLimitMiddleware() gin.HandlerFunc {
+	maxBytes := s.config.MaxRequestBodyBytes
+	maxDepth := s.config.MaxJSONDepth
+
+	return gin.HandlerFunc(func(c *gin.Context) {
+		if c.Request.Body == nil || c.Request.Body == http.NoBody {
+			c.Next()
+			return
+		}
+
+		if c.Request.ContentLength > maxBytes {
+			abortBodyTooLarge(c, fmt.Sprintf("request body exceeds %d bytes", maxBytes))
+			return
+		}
+
+		body, err := io.ReadAll(http.MaxBytesReader(c.Writer, c.Request.Body, maxBytes))
+		if err != nil {
+			var maxBytesErr *http.MaxBytesError
+			if errors.As(err, &maxBytesErr) {
+				abortBodyTooLarge(c, fmt.Sprintf("request body exceeds %d bytes", maxBytes))
+				return
+			}
+			c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"error": "Failed to read request body", "details": err.Error()})
+			return
+		}
+
+		if err := checkJSONDepth(body, maxDepth); errors.Is(err, errJSONTooDeep) {
+			abortBodyTooLarge(c, fmt.Sprintf("JSON body exceeds maximum nesting depth of %d", maxDepth))
+			return
+		}
+
+		// Hand the buffered body on to the handlers
+		c.Request.Body = io.NopCloser(bytes.NewReader(body))
+		c.Next()
+	})
+}
+
+func abortBodyTooLarge(c *gin.Context, details string) {
+	c.AbortWithStatusJSON(http.StatusRequestEntityTooLarge, gin.H{
+		"error":   "Request body too large",
+		"details": details,
+	})
+}
+
+// checkJSONDepth walks the JSON tokens and returns errJSONTooDeep if nesting exceeds maxDepth.
+// Malformed JSON is not reported here; binding in the handler produces the proper error.
+func checkJSONDepth(body []byte, maxDepth int) error {
+	decoder := json.NewDecoder(bytes.NewReader(body))
+	depth := 0
+
+	for {
+		token, err := decoder.Token()
+		if err != nil {
+			return nil
+		}
+
+		switch token {
+		case json.Delim('{'), json.Delim('['):
+			depth++
+			if depth > maxDepth {
+				return errJSONTooDeep
+			}
+		case json.Delim('}'), json.Delim(']'):
+			depth--
+		}
+	}
+}
@@ -0,0 +1,62 @@
+package api
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"bitgo-wallets-api/internal/config"
+
+	"github.com/gin-gonic/gin"
+)
+
+func TestBodyLimitMiddleware(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	server := &Server{config: &config.Config{MaxRequestBodyBytes: 64, MaxJSONDepth: 3}}
+
+	var received string
+	router := gin.New()
+	router.Use(server.bodyLimitMiddleware())
+	router.POST("/echo", func(c *gin.Context) {
+		body, _ := io.ReadAll(c.Request.Body)
+		received = string(body)
+		c.Status(http.StatusOK)
+	})
+
+	oversized := `{"memo":"` + strings.Repeat("x", 100) + `"}`
+	tests := []struct {
+		name          string
+		body          string
+		unknownLength bool // Sent without a Content-Length, as a chunked body would be
+		want          int
+	}{
+		{name: "normal body", body: `{"amount":"0.1","tags":["a"]}`, want: http.StatusOK},
+		{name: "oversized body", body: oversized, want: http.StatusRequestEntityTooLarge},
+		{name: "oversized body without length", body: oversized, unknownLength: true, want: http.StatusRequestEntityTooLarge},
+		{name: "too deep", body: `{"a":{"b":{"c":{"d":1}}}}`, want: http.StatusRequestEntityTooLarge},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			received = ""
+			request := httptest.NewRequest(http.MethodPost, "/echo", strings.NewReader(tt.body))
+			if tt.unknownLength {
+				request.ContentLength = -1
+			}
+			recorder := httptest.NewRecorder()
+			router.ServeHTTP(recorder, request)
+
+			if recorder.Code != tt.want {
+				t.Fatalf("status = %d, want %d: %s", recorder.Code, tt.want, recorder.Body.String())
+			}
+			if tt.want == http.StatusOK && received != tt.body {
+				t.Errorf("handler read %q, want the whole body %q", received, tt.body)
+			}
+			if tt.want != http.StatusOK && received != "" {
+				t.Error("handler ran for a rejected body")
+			}
+		})
+	}
+}
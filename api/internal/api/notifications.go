@@ -0,0 +1,47 @@
+package api
+
+import (
+	"net/http"
+
+	"bitgo-wallets-api/internal/models"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// getTransferNotifications returns the notifications sent about a transfer with their delivery
+// outcomes, oldest first
+func (s *Server) getTransferNotifications(c *gin.Context) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid transfer ID"})
+		return
+	}
+
+	transfer, err := s.transferRequestRepo.GetByID(id)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to get transfer"})
+		return
+	}
+	if transfer == nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Transfer not found"})
+		return
+	}
+	if s.rejectHiddenTransfer(c, transfer) {
+		return
+	}
+
+	notifications, err := s.notificationRepo.ListByTransfer(id)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to list notifications", "details": err.Error()})
+		return
+	}
+	if notifications == nil {
+		notifications = []*models.NotificationRecord{}
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"transfer_id":   id,
+		"notifications": notifications,
+	})
+}
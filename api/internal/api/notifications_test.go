@@ -0,0 +1,86 @@
+package api
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"bitgo-wallets-api/internal/models"
+	"bitgo-wallets-api/internal/services"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+func TestTransferNotificationsShowDeliveryOutcomes(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	repo := &memNotificationRepo{}
+	transfer := &models.TransferRequest{
+		ID:                uuid.New(),
+		WalletID:          uuid.New(),
+		RequestedByUserID: uuid.New(),
+		AmountString:      "0.01",
+		Coin:              "btc",
+		TransferType:      models.WalletTypeWarm,
+		Status:            models.TransferStatusConfirmed,
+	}
+
+	// In-app delivery succeeds; the webhook has no URL configured, so it fails
+	send := func(channel services.NotificationChannel, oldStatus, newStatus models.TransferStatus) {
+		t.Helper()
+		ns := services.NewNotificationService(services.NotificationConfig{
+			DefaultChannels: []services.NotificationChannel{channel},
+			RetryAttempts:   1,
+			RetryDelay:      time.Millisecond,
+			QueueSize:       10,
+			Workers:         1,
+		}, &SimpleLogger{}, repo)
+		ns.SendTransferStatusNotification(transfer, oldStatus, newStatus)
+		ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+		defer cancel()
+		if err := ns.Shutdown(ctx); err != nil {
+			t.Fatalf("Shutdown() error = %v", err)
+		}
+	}
+	send(services.NotificationChannelInApp, models.TransferStatusSubmitted, models.TransferStatusBroadcast)
+	send(services.NotificationChannelWebhook, models.TransferStatusBroadcast, models.TransferStatusConfirmed)
+
+	server := &Server{
+		transferRequestRepo: newMemTransferRepo(transfer),
+		notificationRepo:    repo,
+	}
+	router := gin.New()
+	router.GET("/transfers/:id/notifications", server.getTransferNotifications)
+
+	recorder := httptest.NewRecorder()
+	router.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/transfers/"+transfer.ID.String()+"/notifications", nil))
+	if recorder.Code != http.StatusOK {
+		t.Fatalf("status = %d, want %d: %s", recorder.Code, http.StatusOK, recorder.Body.String())
+	}
+	var body struct {
+		TransferID    uuid.UUID                    `json:"transfer_id"`
+		Notifications []*models.NotificationRecord `json:"notifications"`
+	}
+	decodeJSON(t, recorder, &body)
+
+	if body.TransferID != transfer.ID {
+		t.Errorf("transfer_id = %s, want %s", body.TransferID, transfer.ID)
+	}
+	if len(body.Notifications) != 2 {
+		t.Fatalf("%d notifications, want 2: %s", len(body.Notifications), recorder.Body.String())
+	}
+	delivered, failed := body.Notifications[0], body.Notifications[1]
+	for _, record := range body.Notifications {
+		if record.Type != string(services.NotificationTypeTransferStatusChange) {
+			t.Errorf("notification type = %q, want %q", record.Type, services.NotificationTypeTransferStatusChange)
+		}
+	}
+	if delivered.Status != models.NotificationDeliveryDelivered || delivered.DeliveredAt == nil || delivered.LastError != nil {
+		t.Errorf("in-app notification = %s delivered at %v error %v, want delivered without error", delivered.Status, delivered.DeliveredAt, delivered.LastError)
+	}
+	if failed.Status != models.NotificationDeliveryFailed || failed.FailedAt == nil || failed.LastError == nil || *failed.LastError == "" {
+		t.Errorf("webhook notification = %s failed at %v error %v, want failed with its error", failed.Status, failed.FailedAt, failed.LastError)
+	}
+}
@@ -0,0 +1,111 @@
+package api
+
+import (
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+// maxPageLimit is the largest page size list endpoints accept
+const maxPageLimit = 500
+
+// parsePagination reads the limit and offset query parameters, using defaultLimit and 0 when
+// they're absent. Non-integer or out-of-range values are rejected rather than clamped.
+func parsePagination(c *gin.Context, defaultLimit int) (int, int, error) {
+	limit := defaultLimit
+	offset := 0
+
+	if l := c.Query("limit"); l != "" {
+		parsed, err := strconv.Atoi(strings.TrimSpace(l))
+		if err != nil {
+			return 0, 0, fmt.Errorf("limit must be an integer, got %q", l)
+		}
+		if parsed < 1 || parsed > maxPageLimit {
+			return 0, 0, fmt.Errorf("limit must be between 1 and %d, got %d", maxPageLimit, parsed)
+		}
+		limit = parsed
+	}
+
+	if o := c.Query("offset"); o != "" {
+		parsed, err := strconv.Atoi(strings.TrimSpace(o))
+		if err != nil {
+			return 0, 0, fmt.Errorf("offset must be an integer, got %q", o)
+		}
+		if parsed < 0 {
+			return 0, 0, fmt.Errorf("offset must not be negative, got %d", parsed)
+		}
+		offset = parsed
+	}
+
+	return limit, offset, nil
+}
+
+// Pagination describes where a page sits in a list so clients don't have to compute offsets.
+// NextOffset and PrevOffset are null on the last and first page respectively.
+type Pagination struct {
+	Limit      int  `json:"limit"`
+	Offset     int  `json:"offset"`
+	Total      int  `json:"total"`
+	HasMore    bool `json:"has_more"`
+	NextOffset *int `json:"next_offset"`
+	PrevOffset *int `json:"prev_offset"`
+}
+
+// newPagination computes navigation for a page of count items starting at offset
+func newPagination(limit, offset, count, total int) Pagination {
+	p := Pagination{
+		Limit:   limit,
+		Offset:  offset,
+		Total:   total,
+		HasMore: offset+count < total,
+	}
+
+	if p.HasMore {
+		next := offset + count
+		p.NextOffset = &next
+	}
+	if offset > 0 {
+		prev := offset - limit
+		if prev < 0 {
+			prev = 0
+		}
+		p.PrevOffset = &prev
+	}
+
+	return p
+}
+
+// setPaginationHeaders sets an RFC 5988 Link header with first, prev, next and last page links
+func setPaginationHeaders(c *gin.Context, p Pagination) {
+	links := []string{paginationLink(c, p.Limit, 0, "first")}
+	if p.PrevOffset != nil {
+		links = append(links, paginationLink(c, p.Limit, *p.PrevOffset, "prev"))
+	}
+	if p.NextOffset != nil {
+		links = append(links, paginationLink(c, p.Limit, *p.NextOffset, "next"))
+	}
+	if p.Total > 0 {
+		last := ((p.Total - 1) / p.Limit) * p.Limit
+		links = append(links, paginationLink(c, p.Limit, last, "last"))
+	}
+
+	c.Header("Link", strings.Join(links, ", "))
+	c.Header("X-Total-Count", strconv.Itoa(p.Total))
+}
+
+// paginationLink builds a link to the current request path with limit/offset replaced,
+// keeping any other query parameters
+func paginationLink(c *gin.Context, limit, offset int, rel string) string {
+	query := url.Values{}
+	for key, values := range c.Request.URL.Query() {
+		query[key] = values
+	}
+	query.Set("limit", strconv.Itoa(limit))
+	query.Set("offset", strconv.Itoa(offset))
+
+	target := url.URL{Path: c.Request.URL.Path, RawQuery: query.Encode()}
+	return fmt.Sprintf(`<%s>; rel="%s"`, target.String(), rel)
+}
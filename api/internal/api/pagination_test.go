@@ -0,0 +1,137 @@
+package api
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+func TestNewPagination(t *testing.T) {
+	at := func(n int) *int { return &n }
+
+	tests := []struct {
+		name                 string
+		limit, offset, count int
+		total                int
+		wantHasMore          bool
+		wantNext, wantPrev   *int
+	}{
+		{name: "first of three pages", limit: 10, count: 10, total: 25, wantHasMore: true, wantNext: at(10)},
+		{name: "middle page", limit: 10, offset: 10, count: 10, total: 25, wantHasMore: true, wantNext: at(20), wantPrev: at(0)},
+		{name: "last page", limit: 10, offset: 20, count: 5, total: 25, wantPrev: at(10)},
+		{name: "exactly one page", limit: 10, count: 10, total: 10},
+		{name: "empty", limit: 10, total: 0},
+		{name: "unaligned offset", limit: 10, offset: 5, count: 10, total: 25, wantHasMore: true, wantNext: at(15), wantPrev: at(0)},
+		{name: "past the end", limit: 10, offset: 30, total: 25, wantPrev: at(20)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := newPagination(tt.limit, tt.offset, tt.count, tt.total)
+			if p.HasMore != tt.wantHasMore {
+				t.Errorf("HasMore = %v, want %v", p.HasMore, tt.wantHasMore)
+			}
+			if !equalOffset(p.NextOffset, tt.wantNext) {
+				t.Errorf("NextOffset = %v, want %v", formatOffset(p.NextOffset), formatOffset(tt.wantNext))
+			}
+			if !equalOffset(p.PrevOffset, tt.wantPrev) {
+				t.Errorf("PrevOffset = %v, want %v", formatOffset(p.PrevOffset), formatOffset(tt.wantPrev))
+			}
+		})
+	}
+}
+
+func TestPaginationLinkHeader(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.GET("/wallets/:id/transfers", func(c *gin.Context) {
+		setPaginationHeaders(c, newPagination(10, 10, 10, 25))
+	})
+
+	recorder := httptest.NewRecorder()
+	router.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/wallets/w1/transfers?limit=10&offset=10&tag=payroll", nil))
+
+	if total := recorder.Header().Get("X-Total-Count"); total != "25" {
+		t.Errorf("X-Total-Count = %q, want 25", total)
+	}
+	link := recorder.Header().Get("Link")
+	for _, want := range []string{
+		`</wallets/w1/transfers?limit=10&offset=0&tag=payroll>; rel="first"`,
+		`</wallets/w1/transfers?limit=10&offset=0&tag=payroll>; rel="prev"`,
+		`</wallets/w1/transfers?limit=10&offset=20&tag=payroll>; rel="next"`,
+		`</wallets/w1/transfers?limit=10&offset=20&tag=payroll>; rel="last"`,
+	} {
+		if !strings.Contains(link, want) {
+			t.Errorf("Link = %q, want it to contain %q", link, want)
+		}
+	}
+}
+
+func equalOffset(got, want *int) bool {
+	if got == nil || want == nil {
+		return got == want
+	}
+	return *got == *want
+}
+
+func formatOffset(offset *int) interface{} {
+	if offset == nil {
+		return nil
+	}
+	return *offset
+}
+
+func TestParsePagination(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	tests := []struct {
+		query      string
+		wantLimit  int
+		wantOffset int
+		wantErr    bool
+	}{
+		{query: "", wantLimit: 25},
+		{query: "limit=10&offset=20", wantLimit: 10, wantOffset: 20},
+		{query: "limit=%2010%20", wantLimit: 10},
+		{query: "limit=abc", wantErr: true},
+		{query: "limit=-5", wantErr: true},
+		{query: "limit=0", wantErr: true},
+		{query: "limit=1000", wantErr: true},
+		{query: "offset=-1", wantErr: true},
+		{query: "offset=abc", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.query, func(t *testing.T) {
+			c, _ := gin.CreateTestContext(httptest.NewRecorder())
+			c.Request = httptest.NewRequest(http.MethodGet, "/transfers?"+tt.query, nil)
+
+			limit, offset, err := parsePagination(c, 25)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("parsePagination() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err == nil && (limit != tt.wantLimit || offset != tt.wantOffset) {
+				t.Errorf("parsePagination() = %d, %d; want %d, %d", limit, offset, tt.wantLimit, tt.wantOffset)
+			}
+		})
+	}
+}
+
+func TestListTransfersRejectsInvalidPagination(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	server := &Server{}
+	router := gin.New()
+	router.GET("/wallets/:id/transfers", server.listTransfers)
+
+	for _, query := range []string{"limit=abc", "limit=-5", "offset=-1", "limit=1000"} {
+		recorder := httptest.NewRecorder()
+		router.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/wallets/"+uuid.New().String()+"/transfers?"+query, nil))
+		if recorder.Code != http.StatusBadRequest {
+			t.Errorf("%s: status = %d, want %d", query, recorder.Code, http.StatusBadRequest)
+		}
+	}
+}
@@ -0,0 +1,153 @@
+package api
+
+import (
+	"math"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// rateLimitIdleTTL is how long an untouched bucket is kept before it is swept
+const rateLimitIdleTTL = 10 * time.Minute
+
+// tokenBucket refills at rate tokens per second up to capacity
+type tokenBucket struct {
+	tokens   float64
+	lastFill time.Time
+}
+
+// rateLimiter is an in-process token-bucket limiter keyed by client. Each client gets one
+// bucket per limit, so reads and writes are budgeted separately.
+type rateLimiter struct {
+	capacity float64
+	rate     float64
+	now      func() time.Time
+
+	mu        sync.Mutex
+	buckets   map[string]*tokenBucket
+	lastSweep time.Time
+}
+
+// newRateLimiter allows perMinute requests per client per minute, with bursts of up to a
+// minute's allowance
+func newRateLimiter(perMinute int) *rateLimiter {
+	return &rateLimiter{
+		capacity: float64(perMinute),
+		rate:     float64(perMinute) / 60,
+		now:      time.Now,
+		buckets:  make(map[string]*tokenBucket),
+	}
+}
+
+// allow takes a token from the key's bucket. When the bucket is empty it returns false and
+// how long until a token is available.
+func (l *rateLimiter) allow(key string) (bool, time.Duration) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	now := l.now()
+	l.sweep(now)
+
+	bucket, ok := l.buckets[key]
+	if !ok {
+		bucket = &tokenBucket{tokens: l.capacity, lastFill: now}
+		l.buckets[key] = bucket
+	}
+
+	elapsed := now.Sub(bucket.lastFill).Seconds()
+	bucket.tokens = math.Min(l.capacity, bucket.tokens+elapsed*l.rate)
+	bucket.lastFill = now
+
+	if bucket.tokens >= 1 {
+		bucket.tokens--
+		return true, 0
+	}
+
+	wait := time.Duration((1 - bucket.tokens) / l.rate * float64(time.Second))
+	return false, wait
+}
+
+// sweep drops buckets that have been idle long enough to have refilled completely
+func (l *rateLimiter) sweep(now time.Time) {
+	if now.Sub(l.lastSweep) < rateLimitIdleTTL {
+		return
+	}
+	l.lastSweep = now
+
+	for key, bucket := range l.buckets {
+		if now.Sub(bucket.lastFill) >= rateLimitIdleTTL {
+			delete(l.buckets, key)
+		}
+	}
+}
+
+// isWriteMethod reports whether a request method changes state
+func isWriteMethod(method string) bool {
+	switch method {
+	case http.MethodGet, http.MethodHead, http.MethodOptions:
+		return false
+	default:
+		return true
+	}
+}
+
+// rateLimitExemptWrites are routes whose writes aren't budgeted. BitGo's webhook deliveries
+// all come from BitGo and are retried when refused, so limiting them only delays updates.
+var rateLimitExemptWrites = map[string]bool{
+	"/api/v1/webhooks/bitgo": true,
+}
+
+// rateLimitKey identifies the client a request is budgeted against: the authenticated user
+// when there is one, otherwise the client IP
+func (s *Server) rateLimitKey(c *gin.Context) string {
+	if userID, ok := s.authenticatedUserID(c); ok {
+		return "user:" + userID.String()
+	}
+	return "ip:" + c.ClientIP()
+}
+
+// rateLimitMiddleware rejects clients that exceed their read or write budget with 429 and
+// a Retry-After header. Reads and writes draw from separate buckets, so a client hammering
+// transfer creation can still load pages. A budget of zero disables that limit.
+func (s *Server) rateLimitMiddleware() gin.HandlerFunc {
+	var reads, writes *rateLimiter
+	if s.config.RateLimitReadsPerMinute > 0 {
+		reads = newRateLimiter(s.config.RateLimitReadsPerMinute)
+	}
+	if s.config.RateLimitWritesPerMinute > 0 {
+		writes = newRateLimiter(s.config.RateLimitWritesPerMinute)
+	}
+
+	return gin.HandlerFunc(func(c *gin.Context) {
+		limiter := reads
+		if isWriteMethod(c.Request.Method) {
+			limiter = writes
+			if rateLimitExemptWrites[c.FullPath()] {
+				limiter = nil
+			}
+		}
+		if limiter == nil {
+			c.Next()
+			return
+		}
+
+		allowed, wait := limiter.allow(s.rateLimitKey(c))
+		if !allowed {
+			retryAfter := int(math.Ceil(wait.Seconds()))
+			if retryAfter < 1 {
+				retryAfter = 1
+			}
+			c.Header("Retry-After", strconv.Itoa(retryAfter))
+			c.AbortWithStatusJSON(http.StatusTooManyRequests, gin.H{
+				"error":   "Rate limit exceeded",
+				"details": "too many requests, retry after " + strconv.Itoa(retryAfter) + "s",
+			})
+			return
+		}
+
+		c.Next()
+	})
+}
@@ -0,0 +1,49 @@
+package api
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"bitgo-wallets-api/internal/config"
+
+	"github.com/gin-gonic/gin"
+)
+
+func TestRateLimitRejectsWritesOverBudgetWithoutAffectingReads(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	server := &Server{config: &config.Config{RateLimitEnabled: true, RateLimitReadsPerMinute: 600, RateLimitWritesPerMinute: 3}}
+	router := gin.New()
+	api := router.Group("/api/v1")
+	api.Use(server.rateLimitMiddleware())
+	api.POST("/wallets/:id/transfers", func(c *gin.Context) { c.Status(http.StatusCreated) })
+	api.GET("/transfers", func(c *gin.Context) { c.Status(http.StatusOK) })
+
+	serve := func(method, path string) *httptest.ResponseRecorder {
+		recorder := httptest.NewRecorder()
+		request := httptest.NewRequest(method, path, nil)
+		request.RemoteAddr = "203.0.113.7:4000"
+		router.ServeHTTP(recorder, request)
+		return recorder
+	}
+
+	for i := 0; i < 3; i++ {
+		if recorder := serve(http.MethodPost, "/api/v1/wallets/wallet-1/transfers"); recorder.Code != http.StatusCreated {
+			t.Fatalf("write %d: status = %d, want %d", i+1, recorder.Code, http.StatusCreated)
+		}
+	}
+
+	recorder := serve(http.MethodPost, "/api/v1/wallets/wallet-1/transfers")
+	if recorder.Code != http.StatusTooManyRequests {
+		t.Fatalf("write over budget: status = %d, want %d", recorder.Code, http.StatusTooManyRequests)
+	}
+	if recorder.Header().Get("Retry-After") == "" {
+		t.Error("Retry-After missing on the 429")
+	}
+
+	for i := 0; i < 10; i++ {
+		if recorder := serve(http.MethodGet, "/api/v1/transfers"); recorder.Code != http.StatusOK {
+			t.Fatalf("read %d after writes ran out: status = %d, want %d", i+1, recorder.Code, http.StatusOK)
+		}
+	}
+}
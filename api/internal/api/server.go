@@ -1,14 +1,21 @@
 package api
 
 import (
+	"context"
 	"database/sql"
+	"errors"
 	"fmt"
 	"log"
 	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
 	"time"
 
 	"bitgo-wallets-api/internal/bitgo"
 	"bitgo-wallets-api/internal/config"
+	"bitgo-wallets-api/internal/models"
 	"bitgo-wallets-api/internal/repository"
 	"bitgo-wallets-api/internal/services"
 
@@ -20,17 +27,46 @@ type Server struct {
 	config *config.Config
 	router *gin.Engine
 
+	httpServer *http.Server
+
 	// External services
-	bitgoClient        *bitgo.Client
+	bitgoClient        bitgo.BitGoAPI
+	workerBitgoClient  bitgo.BitGoAPI // Used by background workers; the service token client when one is configured
+	approvalSvc        *bitgo.ApprovalService
 	bitgoRequestLogger *BitGoRequestLogger
 	pollingWorker      *services.TransferPollingWorker
 	notificationSvc    services.NotificationService
 	coldWalletSvc      *services.ColdWalletService
 	warmWalletSvc      *services.WarmWalletService
+	approvalSweeper    *services.ApprovalTimeoutSweeper
+	submissionWorker   *services.TransferSubmissionWorker
+	retentionJob       *services.TransferRetentionJob
+	validationMetrics  *services.ValidationMetrics
+	priceOracle        services.PriceOracle
+	balanceReserves    *services.BalanceReservations
+	velocityGuard      *services.VelocityGuard
+	transferBackfiller *services.TransferBackfiller
+	featureFlags       *services.FeatureFlagStore
+	idempotencySvc     *bitgo.IdempotencyService
+	transferBuilder    *bitgo.IdempotentTransferBuilder // Builds hot transfers, reusing a build only within its freshness window
+
+	// Signs admin session tokens and short-lived tokens for the BitGo request-log WebSocket
+	tokenSecret []byte
+
+	// Readiness: started is set once background services are running, and the BitGo probe
+	// result is cached so /readyz doesn't call BitGo on every check
+	started      atomic.Bool
+	bitgoProbe   bitgoProbeResult
+	bitgoProbeMu sync.Mutex
 
 	// Repositories
 	walletRepo          repository.WalletRepository
 	transferRequestRepo repository.TransferRequestRepository
+	walletAddressRepo   repository.WalletAddressRepository
+	blockedAddressRepo  repository.BlockedAddressRepository
+	notificationRepo    repository.NotificationRepository
+	transferEventRepo   repository.TransferEventRepository
+	membershipRepo      repository.WalletMembershipRepository
 }
 
 func NewServer(db *sql.DB, cfg *config.Config) *Server {
@@ -41,28 +77,70 @@ func NewServer(db *sql.DB, cfg *config.Config) *Server {
 
 	// Initialize BitGo request logger first (needed by BitGo client)
 	server.bitgoRequestLogger = NewBitGoRequestLogger()
+	server.tokenSecret = newTokenSecret(cfg.WSTokenSecret)
 
 	// Initialize BitGo client
 	server.initBitGoClient()
 
+	// Initialize repositories; the services below are built on them
+	server.walletRepo = repository.NewWalletRepository(db)
+	server.transferRequestRepo = repository.NewTransferRequestRepository(db)
+	server.walletAddressRepo = repository.NewWalletAddressRepository(db)
+	server.blockedAddressRepo = repository.NewBlockedAddressRepository(db)
+	server.membershipRepo = repository.NewWalletMembershipRepository(db)
+	server.notificationRepo = repository.NewNotificationRepository(db)
+	server.transferEventRepo = repository.NewTransferEventRepository(db, cfg.PIIRedactionFields())
+
+	// Feature flags are consulted by the notification and warm wallet services
+	server.initFeatureFlags()
+
 	// Initialize notification service
 	server.initNotificationService()
 
-	// Initialize repositories
-	server.walletRepo = repository.NewWalletRepository(db)
-	server.transferRequestRepo = repository.NewTransferRequestRepository(db)
+	// Idempotency-Key tracking for retried POST requests
+	server.idempotencySvc = bitgo.NewIdempotencyService(&SimpleLogger{}, 24*time.Hour)
+	server.transferBuilder = bitgo.NewIdempotentTransferBuilder(server.bitgoClient, bitgo.NewIdempotencyService(&SimpleLogger{}, 24*time.Hour))
+
+	// Imports BitGo transfer history for wallets onboarded after they were in use; a bulk
+	// import is background work, so it runs under the workers' token
+	server.transferBackfiller = services.NewTransferBackfiller(server.workerBitgoClient, server.transferRequestRepo, &SimpleLogger{})
 
 	// Initialize background services
 	server.initBackgroundServices()
 
+	// Shared counters for cold/warm validation failures
+	server.validationMetrics = services.NewValidationMetrics()
+
+	// USD prices for fiat transfer limits, cached so validation doesn't hit BitGo every time
+	server.priceOracle = services.NewCachedPriceOracle(services.PriceOracleFunc(server.bitgoClient.GetUSDPrice), 5*time.Minute)
+
+	// Balance held for in-flight cold/warm transfers, shared so both see each wallet's holds
+	server.balanceReserves = services.NewBalanceReservations(server.transferRequestRepo, services.DefaultEstimatedFees(), &SimpleLogger{})
+
+	// Auto-freeze of wallets whose outbound volume spikes past their baseline
+	server.initVelocityGuard()
+
 	// Initialize cold wallet service
 	server.initColdWalletService()
 
 	// Initialize warm wallet service
 	server.initWarmWalletService()
 
+	// Initialize approval timeout enforcement (needs cold/warm configs)
+	server.initApprovalTimeoutSweeper()
+
+	// Initialize auto-submission of approved, signed cold/warm transfers
+	server.initSubmissionWorker()
+
+	// Initialize archival of old terminal transfers
+	server.initRetentionJob()
+
 	// Setup router
 	server.setupRouter()
+	server.httpServer = &http.Server{
+		Addr:    ":" + cfg.Port,
+		Handler: server.router,
+	}
 
 	return server
 }
@@ -71,18 +149,99 @@ func (s *Server) initBitGoClient() {
 	// Create BitGo logger that captures requests for debug console
 	logger := NewBitGoLogger(s.bitgoRequestLogger)
 
+	if s.config.SimulationMode {
+		log.Printf("⚠️ WARNING: SIMULATION_MODE is enabled, BitGo calls are served by an in-memory fake")
+		simConfig := bitgo.DefaultSimulationConfig()
+		simConfig.ConfirmAfterPolls = s.config.SimulationConfirmAfterPolls
+		if s.config.BitGoEnterpriseID != "" {
+			simConfig.Enterprise = s.config.BitGoEnterpriseID
+		}
+		s.bitgoClient = bitgo.NewSimulatedClient(simConfig, logger)
+		s.workerBitgoClient = s.bitgoClient // Workers must see the same simulated state
+		s.approvalSvc = bitgo.NewApprovalService(s.bitgoClient, logger)
+		return
+	}
+
 	log.Printf("🔧 DEBUG: Initializing BitGo client with Enterprise ID: '%s'", s.config.BitGoEnterpriseID)
 
-	bitgoConfig := bitgo.Config{
+	s.bitgoClient = bitgo.NewClient(s.bitgoClientConfig(s.config.BitGoAccessToken), logger)
+	s.approvalSvc = bitgo.NewApprovalService(s.bitgoClient, logger)
+	log.Printf("🔧 DEBUG: BitGo client initialized. Enterprise from client: '%s'", s.bitgoClient.GetEnterprise())
+
+	s.workerBitgoClient = s.bitgoClient
+	if s.config.BitGoServiceAccessToken != "" {
+		s.workerBitgoClient = bitgo.NewClient(s.bitgoClientConfig(s.config.BitGoServiceAccessToken), logger)
+		log.Printf("BitGo workers use the service access token from BITGO_SERVICE_ACCESS_TOKEN")
+	}
+}
+
+// bitgoClientConfig returns the BitGo client settings for an access token
+func (s *Server) bitgoClientConfig(accessToken string) bitgo.Config {
+	return bitgo.Config{
 		BaseURL:     s.config.BitGoBaseURL,
-		AccessToken: s.config.BitGoAccessToken,
+		Environment: s.config.BitGoEnvironment,
+		AccessToken: accessToken,
 		Enterprise:  s.config.BitGoEnterpriseID,
 		Timeout:     30 * time.Second,
 		MaxRetries:  3,
+
+		BuildTimeout:  time.Duration(s.config.BitGoBuildTimeoutSeconds) * time.Second,
+		SubmitTimeout: time.Duration(s.config.BitGoSubmitTimeoutSeconds) * time.Second,
+		GetTimeout:    time.Duration(s.config.BitGoGetTimeoutSeconds) * time.Second,
+		ListTimeout:   time.Duration(s.config.BitGoListTimeoutSeconds) * time.Second,
+
+		RedactFields: s.config.PIIRedactionFields(),
 	}
+}
 
-	s.bitgoClient = bitgo.NewClient(bitgoConfig, logger)
-	log.Printf("🔧 DEBUG: BitGo client initialized. Enterprise from client: '%s'", s.bitgoClient.GetEnterprise())
+// validateBitGoSession checks the BitGo access token, and the service token when workers have
+// their own, with an authenticated call. A failure is fatal only when BITGO_REQUIRE_AUTH_ON_START is set.
+func (s *Server) validateBitGoSession() error {
+	if err := s.validateBitGoToken(s.bitgoClient, "BITGO_ACCESS_TOKEN", "transfers"); err != nil {
+		return err
+	}
+	if s.workerBitgoClient != s.bitgoClient {
+		return s.validateBitGoToken(s.workerBitgoClient, "BITGO_SERVICE_ACCESS_TOKEN", "background workers")
+	}
+	return nil
+}
+
+func (s *Server) validateBitGoToken(client bitgo.BitGoAPI, envVar, usedBy string) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 15*time.Second)
+	defer cancel()
+
+	user, err := client.GetCurrentUser(ctx)
+	if err != nil {
+		if s.config.BitGoRequireAuthOnStart {
+			return fmt.Errorf("BitGo %s validation failed: %w", envVar, err)
+		}
+		log.Printf("⚠️ WARNING: BitGo access token validation failed, %s will not work until %s is fixed: %v", usedBy, envVar, err)
+		return nil
+	}
+
+	log.Printf("BitGo session validated for user %s (%s) from %s", user.Username, user.ID, envVar)
+	return nil
+}
+
+func (s *Server) initFeatureFlags() {
+	flags := services.DefaultFeatureFlags()
+	flags.AutoProcessing = s.config.FeatureAutoProcessing
+	flags.RiskScoring = s.config.FeatureRiskScoring
+	flags.VelocityChecks = s.config.FeatureVelocityChecks
+	flags.Escalation = s.config.FeatureEscalation
+
+	for _, name := range strings.Split(s.config.FeatureNotificationChannels, ",") {
+		switch channel := services.NotificationChannel(strings.TrimSpace(name)); channel {
+		case "":
+		case services.NotificationChannelWebhook, services.NotificationChannelEmail, services.NotificationChannelInApp,
+			services.NotificationChannelSMS, services.NotificationChannelSlack:
+			flags.NotificationChannels = append(flags.NotificationChannels, channel)
+		default:
+			log.Printf("⚠️ WARNING: ignoring unknown notification channel %q in FEATURE_NOTIFICATION_CHANNELS", channel)
+		}
+	}
+
+	s.featureFlags = services.NewFeatureFlagStore(flags)
 }
 
 func (s *Server) initNotificationService() {
@@ -93,10 +252,53 @@ func (s *Server) initNotificationService() {
 	if s.config.WebhookURL != "" {
 		notificationConfig.WebhookURL = s.config.WebhookURL
 	}
+	notificationConfig.WebhookSecret = s.config.WebhookSecret
+
+	switch strategy := services.QueueOverflowStrategy(s.config.NotificationOverflowStrategy); strategy {
+	case services.QueueOverflowBlock, services.QueueOverflowDropOldest, services.QueueOverflowDropNew:
+		notificationConfig.OverflowStrategy = strategy
+	default:
+		log.Printf("⚠️ WARNING: unknown NOTIFICATION_OVERFLOW_STRATEGY %q, using %s", strategy, notificationConfig.OverflowStrategy)
+	}
+
+	notificationConfig.DedupWindow = time.Duration(s.config.NotificationDedupWindowMinutes) * time.Minute
+	notificationConfig.Flags = s.featureFlags
+	notificationConfig.Approvers = s.membershipRepo
+	if s.config.NotificationTemplatesFile != "" {
+		templates, err := services.LoadNotificationTemplates(s.config.NotificationTemplatesFile)
+		if err != nil {
+			log.Printf("⚠️ WARNING: %v; using built-in notification templates", err)
+		} else {
+			notificationConfig.Templates = templates
+		}
+	}
 
-	// Create notification service
+	// Create notification service, persisting delivery state for the per-transfer trail
 	logger := &SimpleLogger{}
-	s.notificationSvc = services.NewNotificationService(notificationConfig, logger)
+	notificationSvc := services.NewNotificationService(notificationConfig, logger, s.notificationRepo)
+
+	// Transfer flows must never stall or crash because notifications are broken
+	s.notificationSvc = services.NewGuardedNotificationService(notificationSvc, notificationConfig.SendTimeout, logger)
+}
+
+// parseCoinTimeouts reads a comma-separated coin=seconds list such as "eth=60,btc=20".
+// Malformed entries are logged and skipped.
+func parseCoinTimeouts(raw string) map[string]time.Duration {
+	timeouts := make(map[string]time.Duration)
+	for _, entry := range strings.Split(raw, ",") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+		coin, value, _ := strings.Cut(entry, "=")
+		seconds, err := strconv.Atoi(strings.TrimSpace(value))
+		if strings.TrimSpace(coin) == "" || err != nil || seconds <= 0 {
+			log.Printf("[WARN] Ignoring malformed POLL_COIN_TIMEOUTS entry %q; expected coin=seconds", entry)
+			continue
+		}
+		timeouts[bitgo.CanonicalCoin(strings.TrimSpace(coin))] = time.Duration(seconds) * time.Second
+	}
+	return timeouts
 }
 
 func (s *Server) initBackgroundServices() {
@@ -110,15 +312,23 @@ func (s *Server) initBackgroundServices() {
 	} else {
 		// Development settings
 		workerConfig.PollInterval = 10 * time.Second
+		workerConfig.TypePollIntervals[models.WalletTypeHot] = 10 * time.Second
+		workerConfig.TypePollIntervals[models.WalletTypeWarm] = 30 * time.Second
+		workerConfig.TypePollIntervals[models.WalletTypeCold] = 2 * time.Minute
 		workerConfig.ConcurrentWorkers = 2
 	}
 
+	workerConfig.RequestTimeout = time.Duration(s.config.PollRequestTimeoutSeconds) * time.Second
+	workerConfig.CoinRequestTimeouts = parseCoinTimeouts(s.config.PollCoinTimeouts)
+	workerConfig.MaxPollBackoff = time.Duration(s.config.PollMaxBackoffMinutes) * time.Minute
+	workerConfig.MaxPollFailures = s.config.PollMaxFailures
+
 	// Create polling worker
 	logger := &SimpleLogger{}
 	s.pollingWorker = services.NewTransferPollingWorker(
 		workerConfig,
 		logger,
-		s.bitgoClient,
+		s.workerBitgoClient,
 		s.transferRequestRepo,
 		s.walletRepo,
 		s.notificationSvc,
@@ -139,6 +349,12 @@ func (s *Server) initColdWalletService() {
 		coldConfig.ApprovalTimeoutHours = 24
 	}
 
+	// Optional fiat ceiling from the environment
+	coldConfig.MaxSingleTransferUSD = float64(s.config.ColdMaxTransferUSD)
+	coldConfig.BalanceMaxAge = time.Duration(s.config.BalanceMaxAgeSeconds) * time.Second
+	coldConfig.MinConfirms = s.config.ColdMinConfirms
+	coldConfig.Reservations = s.balanceReserves
+
 	// Create cold wallet service
 	logger := &SimpleLogger{}
 	s.coldWalletSvc = services.NewColdWalletService(
@@ -148,6 +364,8 @@ func (s *Server) initColdWalletService() {
 		s.notificationSvc,
 		logger,
 		coldConfig,
+		s.validationMetrics,
+		s.priceOracle,
 	)
 }
 
@@ -167,15 +385,99 @@ func (s *Server) initWarmWalletService() {
 		warmConfig.AutoProcessThreshold = "5.0"
 	}
 
-	// Create warm wallet service
+	// Optional fiat ceiling from the environment
+	warmConfig.MaxSingleTransferUSD = float64(s.config.WarmMaxTransferUSD)
+	warmConfig.BalanceMaxAge = time.Duration(s.config.BalanceMaxAgeSeconds) * time.Second
+	warmConfig.MinConfirms = s.config.WarmMinConfirms
+	warmConfig.Reservations = s.balanceReserves
+	warmConfig.TrustedAddresses = s.config.TrustedAddressList()
+	warmConfig.BlockedAddresses = s.blockedAddressRepo
+	if s.config.WarmBusinessPurposeThreshold != "" {
+		warmConfig.BusinessPurposeRequiredThreshold = s.config.WarmBusinessPurposeThreshold
+	}
+
+	// Create warm wallet service. It auto-processes transfers in the background, so it calls
+	// BitGo under the workers' token.
 	logger := &SimpleLogger{}
 	s.warmWalletSvc = services.NewWarmWalletService(
-		s.bitgoClient,
+		s.workerBitgoClient,
 		s.walletRepo,
 		s.transferRequestRepo,
 		s.notificationSvc,
 		logger,
 		warmConfig,
+		s.validationMetrics,
+		s.priceOracle,
+		s.featureFlags,
+	)
+}
+
+func (s *Server) initApprovalTimeoutSweeper() {
+	sweeperConfig := services.DefaultApprovalTimeoutConfig()
+	sweeperConfig.Timeouts[models.WalletTypeCold] = s.coldWalletSvc.ApprovalTimeout()
+	sweeperConfig.Timeouts[models.WalletTypeWarm] = s.warmWalletSvc.ApprovalTimeout()
+
+	if s.config.GinMode != "release" {
+		// Development settings
+		sweeperConfig.SweepInterval = time.Minute
+	}
+
+	logger := &SimpleLogger{}
+	s.approvalSweeper = services.NewApprovalTimeoutSweeper(
+		sweeperConfig,
+		logger,
+		s.transferRequestRepo,
+		s.notificationSvc,
+	)
+}
+
+func (s *Server) initSubmissionWorker() {
+	workerConfig := services.DefaultSubmissionWorkerConfig()
+
+	if s.config.GinMode != "release" {
+		// Development settings
+		workerConfig.Interval = 15 * time.Second
+		workerConfig.InitialBackoff = 10 * time.Second
+		workerConfig.MaxBackoff = 2 * time.Minute
+	}
+
+	logger := &SimpleLogger{}
+	s.submissionWorker = services.NewTransferSubmissionWorker(
+		workerConfig,
+		logger,
+		s.workerBitgoClient,
+		s.transferRequestRepo,
+		s.walletRepo,
+		s.notificationSvc,
+	)
+}
+
+func (s *Server) initRetentionJob() {
+	retentionConfig := services.DefaultTransferRetentionConfig()
+	retentionConfig.MaxAge = time.Duration(s.config.TransferRetentionDays) * 24 * time.Hour
+
+	logger := &SimpleLogger{}
+	s.retentionJob = services.NewTransferRetentionJob(
+		retentionConfig,
+		logger,
+		s.transferRequestRepo,
+	)
+}
+
+func (s *Server) initVelocityGuard() {
+	guardConfig := services.DefaultVelocityGuardConfig()
+	guardConfig.Enabled = s.config.VelocityFreezeEnabled
+	guardConfig.Window = time.Duration(s.config.VelocityFreezeWindowMinutes) * time.Minute
+	guardConfig.BaselinePeriod = time.Duration(s.config.VelocityFreezeBaselineHours) * time.Hour
+	guardConfig.Multiplier = float64(s.config.VelocityFreezeMultiplier)
+	guardConfig.MinBaselineTransfers = s.config.VelocityFreezeMinBaselineTransfers
+
+	s.velocityGuard = services.NewVelocityGuard(
+		guardConfig,
+		s.walletRepo,
+		s.transferRequestRepo,
+		s.notificationSvc,
+		&SimpleLogger{},
 	)
 }
 
@@ -183,11 +485,19 @@ func (s *Server) setupRouter() {
 	gin.SetMode(s.config.GinMode)
 	s.router = gin.Default()
 
+	// Only believe X-Forwarded-For from known proxies, so clients can't pick the IP they're
+	// rate limited under
+	if err := s.router.SetTrustedProxies(s.config.TrustedProxyList()); err != nil {
+		log.Printf("⚠️ WARNING: invalid TRUSTED_PROXIES, trusting none: %v", err)
+		s.router.SetTrustedProxies(nil)
+	}
+
 	// Add CORS middleware
 	s.router.Use(func(c *gin.Context) {
 		c.Header("Access-Control-Allow-Origin", "*")
 		c.Header("Access-Control-Allow-Methods", "GET, POST, PUT, DELETE, OPTIONS")
-		c.Header("Access-Control-Allow-Headers", "Content-Type, Authorization")
+		c.Header("Access-Control-Allow-Headers", "Content-Type, Authorization, Idempotency-Key, X-Admin-Key, X-Admin-Actor")
+		c.Header("Access-Control-Expose-Headers", "Deprecation, Idempotency-Key, Idempotent-Replayed, Link, Retry-After, X-Deprecated-Fields, X-Total-Count")
 
 		if c.Request.Method == "OPTIONS" {
 			c.AbortWithStatus(http.StatusNoContent)
@@ -197,15 +507,24 @@ func (s *Server) setupRouter() {
 		c.Next()
 	})
 
+	// Reject oversized or deeply nested request bodies before they reach handlers
+	s.router.Use(s.bodyLimitMiddleware())
+
 	// Health check
 	s.router.GET("/health", s.healthCheck)
 	s.router.GET("/health/detailed", s.detailedHealthCheck)
+	s.router.GET("/readyz", s.readinessCheck)
 
 	// WebSocket endpoint for BitGo request logs
 	s.router.GET("/ws/bitgo-requests", s.HandleBitGoRequestLogs)
 
 	api := s.router.Group("/api/v1")
-	// NO MIDDLEWARE APPLIED - ALL ROUTES ARE PUBLIC
+	// NO AUTH MIDDLEWARE APPLIED - ALL ROUTES ARE PUBLIC
+
+	// Budget each client's reads and writes
+	if s.config.RateLimitEnabled {
+		api.Use(s.rateLimitMiddleware())
+	}
 
 	// Test endpoints
 	api.GET("/test-bitgo", s.testBitGo)
@@ -214,58 +533,164 @@ func (s *Server) setupRouter() {
 
 	// Auth routes (for compatibility)
 	api.POST("/auth/login", s.login)
+	api.POST("/auth/ws-token", s.requireAdmin(), s.createWSToken)
+
+	// Coin registry, for frontends
+	api.GET("/coins", s.listCoins)
 
 	// Wallet routes - NO AUTH REQUIRED
 	api.GET("/wallets", s.listWallets)
 	api.POST("/wallets", s.createWallet)
 	api.GET("/wallets/discover", s.discoverWallets)
+	api.POST("/wallets/import", s.requireAdmin(), s.importWallets)
 	api.GET("/wallets/:id", s.getWallet)
 	api.PUT("/wallets/:id", s.updateWallet)
 	api.DELETE("/wallets/:id", s.deleteWallet)
 	api.POST("/wallets/:id/sync-balance", s.syncWalletBalance)
+	api.POST("/wallets/:id/reconcile", s.requireAdmin(), s.reconcileWallet)
+	api.POST("/wallets/:id/backfill-transfers", s.requireAdmin(), s.backfillWalletTransfers)
+	api.POST("/wallets/:id/unfreeze", s.requireAdmin(), s.unfreezeWallet)
+	api.GET("/wallets/:id/keys", s.getWalletKeys)
+	api.GET("/wallets/:id/approvers", s.getWalletApprovers)
+	api.GET("/wallets/:id/addresses", s.listWalletAddresses)
+	api.POST("/wallets/:id/addresses", s.getOrCreateWalletAddress)
 	api.GET("/wallets/:id/transfers", s.listTransfers)
-	api.POST("/wallets/:id/transfers", s.createTransfer)
+	api.GET("/wallets/:id/transfers/export", s.exportTransfers)
+	api.POST("/wallets/:id/transfers", s.idempotencyMiddleware(), s.createTransfer)
 
 	// Transfer routes - NO AUTH REQUIRED
+	api.GET("/transfers/search", s.requireAdmin(), s.searchTransfers)
 	api.GET("/transfers/:id", s.getTransfer)
 	api.PUT("/transfers/:id", s.updateTransfer)
 	api.PUT("/transfers/:id/status", s.updateTransferStatus)
-	api.POST("/transfers/:id/submit", s.submitTransfer)
+	api.POST("/transfers/:id/submit", s.idempotencyMiddleware(), s.submitTransfer)
+	api.POST("/transfers/:id/retry", s.requireAdmin(), s.idempotencyMiddleware(), s.retryTransfer)
+	api.DELETE("/transfers/:id/approval", s.cancelTransferApproval)
+	api.GET("/approvals/inbox", s.getApprovalInbox)
+	api.POST("/approvals/batch", s.batchApprovals)
 	api.GET("/transfers/:id/status", s.getTransferStatus)
+	api.GET("/transfers/:id/detail", s.getTransferDetail)
+	api.GET("/transfers/:id/notifications", s.getTransferNotifications)
+	api.GET("/transfers/:id/events", s.getTransferEvents)
 	api.PUT("/transfers/:id/offline-workflow-state", s.updateOfflineWorkflowState)
+	api.POST("/transfers/:id/offline-signature", s.recordOfflineSignature)
 	api.POST("/transfers/verify-address", s.verifyAddress)
+	api.POST("/transfers/refresh", s.refreshTransfers)
 
 	// Cold transfer routes - NO AUTH REQUIRED
-	api.POST("/transfers/cold", s.createColdTransfer)
+	api.POST("/transfers/cold", s.idempotencyMiddleware(), s.createColdTransfer)
 	api.GET("/transfers/cold/sla", s.getColdTransfersSLA)
 	api.GET("/transfers/cold/admin-queue", s.getColdTransfersAdminQueue)
 
 	// Warm transfer routes - NO AUTH REQUIRED
-	api.POST("/transfers/warm", s.createWarmTransfer)
+	api.POST("/transfers/warm", s.idempotencyMiddleware(), s.createWarmTransfer)
 	api.GET("/transfers/warm/sla", s.getWarmTransfersSLA)
 	api.GET("/transfers/warm/analytics", s.getWarmTransfersAnalytics)
 	api.POST("/transfers/warm/:id/process", s.processWarmTransfer)
 
+	// Analytics routes - NO AUTH REQUIRED
+	api.GET("/analytics/transfers", s.getTransferAnalytics)
+
+	// BitGo webhook receiver
+	api.POST("/webhooks/bitgo", s.receiveBitGoWebhook)
+
 	// Admin routes - NO AUTH REQUIRED
 	api.GET("/admin/approvers", s.getApprovers)
+	api.GET("/admin/feature-flags", s.requireAdmin(), s.getFeatureFlags)
+	api.PUT("/admin/wallets/:id/required-approvals", s.requireAdmin(), s.setWalletRequiredApprovals)
+	api.PUT("/admin/wallets/:id/trusted-addresses", s.requireAdmin(), s.setWalletTrustedAddresses)
+	api.GET("/admin/blocked-addresses", s.requireAdmin(), s.listBlockedAddresses)
+	api.POST("/admin/blocked-addresses", s.requireAdmin(), s.blockAddress)
+	api.DELETE("/admin/blocked-addresses/:id", s.requireAdmin(), s.unblockAddress)
 }
 
 func (s *Server) Start() error {
+	// Never let a fake BitGo serve production traffic by accident
+	if s.config.SimulationMode && s.config.GinMode == "release" && !s.config.SimulationAllowRelease {
+		return fmt.Errorf("SIMULATION_MODE cannot be enabled in release mode without SIMULATION_ALLOW_RELEASE")
+	}
+
+	// Make sure the BitGo credentials work before accepting traffic
+	if err := s.validateBitGoSession(); err != nil {
+		return err
+	}
+
 	// Start background services
 	if err := s.pollingWorker.Start(); err != nil {
 		return fmt.Errorf("failed to start polling worker: %w", err)
 	}
+	if err := s.approvalSweeper.Start(); err != nil {
+		return fmt.Errorf("failed to start approval timeout sweeper: %w", err)
+	}
+	if err := s.submissionWorker.Start(); err != nil {
+		return fmt.Errorf("failed to start submission worker: %w", err)
+	}
+	if err := s.retentionJob.Start(); err != nil {
+		return fmt.Errorf("failed to start transfer retention job: %w", err)
+	}
+
+	// The listener opens below; /readyz reports ready from here on
+	s.started.Store(true)
 
-	return s.router.Run(":" + s.config.Port)
+	if err := s.httpServer.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+		return err
+	}
+	return nil
 }
 
 func (s *Server) Stop() error {
-	// Stop background services gracefully
-	if err := s.pollingWorker.Stop(); err != nil {
-		return fmt.Errorf("failed to stop polling worker: %w", err)
+	return s.Shutdown(context.Background())
+}
+
+// Shutdown stops the server in dependency order, all within ctx's deadline: it stops
+// accepting requests and waits for in-flight ones, lets warm auto-processing and the
+// submission worker finish their builds and submits, stops the pollers, and finally
+// drains the notification queue, which the earlier steps may still add to. It returns
+// ctx's error, along with any step that failed, if the deadline is exceeded.
+func (s *Server) Shutdown(ctx context.Context) error {
+	// Stop taking traffic before the workers go away
+	s.started.Store(false)
+
+	var errs []error
+	if err := s.httpServer.Shutdown(ctx); err != nil {
+		errs = append(errs, fmt.Errorf("failed to stop HTTP server: %w", err))
 	}
 
-	return nil
+	steps := []struct {
+		name string
+		stop func() error
+	}{
+		{"warm wallet service", s.warmWalletSvc.Stop},
+		{"submission worker", s.submissionWorker.Stop},
+		{"polling worker", s.pollingWorker.Stop},
+		{"approval timeout sweeper", s.approvalSweeper.Stop},
+		{"transfer retention job", s.retentionJob.Stop},
+	}
+	for _, step := range steps {
+		if err := stopWithin(ctx, step.stop); err != nil {
+			errs = append(errs, fmt.Errorf("failed to stop %s: %w", step.name, err))
+		}
+	}
+
+	if err := s.notificationSvc.Shutdown(ctx); err != nil {
+		errs = append(errs, fmt.Errorf("failed to drain notifications: %w", err))
+	}
+
+	return errors.Join(errs...)
+}
+
+// stopWithin runs stop, giving up with ctx's error if ctx ends first. A stop that is
+// abandoned keeps running in the background.
+func stopWithin(ctx context.Context, stop func() error) error {
+	done := make(chan error, 1)
+	go func() { done <- stop() }()
+
+	select {
+	case err := <-done:
+		return err
+	case <-ctx.Done():
+		return ctx.Err()
+	}
 }
 
 // SimpleLogger implements the bitgo.Logger interface
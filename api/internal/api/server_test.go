@@ -0,0 +1,133 @@
+package api
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"log"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"bitgo-wallets-api/internal/bitgo"
+	"bitgo-wallets-api/internal/config"
+)
+
+// sessionClient answers GetCurrentUser with the user, or the error when set; other methods
+// aren't used
+type sessionClient struct {
+	bitgo.BitGoAPI
+	err error
+}
+
+func (c *sessionClient) GetCurrentUser(ctx context.Context) (*bitgo.User, error) {
+	if c.err != nil {
+		return nil, c.err
+	}
+	return &bitgo.User{ID: "user-1", Username: "ops@example.com"}, nil
+}
+
+func TestValidateBitGoSession(t *testing.T) {
+	unauthorized := errors.New("401 unauthorized")
+	tests := []struct {
+		name        string
+		err         error
+		requireAuth bool
+		wantErr     bool
+		wantWarning bool
+	}{
+		{name: "valid token", requireAuth: true},
+		{name: "invalid token required on start", err: unauthorized, requireAuth: true, wantErr: true},
+		{name: "invalid token not required", err: unauthorized, wantWarning: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var output bytes.Buffer
+			previous := log.Writer()
+			log.SetOutput(&output)
+			t.Cleanup(func() { log.SetOutput(previous) })
+
+			client := &sessionClient{err: tt.err}
+			server := &Server{
+				config:            &config.Config{BitGoRequireAuthOnStart: tt.requireAuth},
+				bitgoClient:       client,
+				workerBitgoClient: client,
+			}
+
+			err := server.validateBitGoSession()
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("validateBitGoSession() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err != nil && !errors.Is(err, unauthorized) {
+				t.Errorf("error = %v, want it to wrap the BitGo error", err)
+			}
+			if warned := strings.Contains(output.String(), "WARNING"); warned != tt.wantWarning {
+				t.Errorf("logged %q, want a warning %v", output.String(), tt.wantWarning)
+			}
+		})
+	}
+
+	t.Run("invalid service token", func(t *testing.T) {
+		server := &Server{
+			config:            &config.Config{BitGoRequireAuthOnStart: true},
+			bitgoClient:       &sessionClient{},
+			workerBitgoClient: &sessionClient{err: unauthorized},
+		}
+		err := server.validateBitGoSession()
+		if err == nil || !strings.Contains(err.Error(), "BITGO_SERVICE_ACCESS_TOKEN") {
+			t.Errorf("validateBitGoSession() error = %v, want it to name BITGO_SERVICE_ACCESS_TOKEN", err)
+		}
+	})
+}
+
+func TestWorkersUseServiceAccessToken(t *testing.T) {
+	// The fake BitGo reports the bearer token it was called with as the user ID
+	bitgoServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		token := strings.TrimPrefix(r.Header.Get("Authorization"), "Bearer ")
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(map[string]interface{}{"user": map[string]string{"id": token}})
+	}))
+	defer bitgoServer.Close()
+
+	tests := []struct {
+		name         string
+		serviceToken string
+		wantWorker   string
+	}{
+		{name: "service token configured", serviceToken: "service-token", wantWorker: "service-token"},
+		{name: "no service token", wantWorker: "user-token"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := &Server{
+				config: &config.Config{
+					BitGoBaseURL:            bitgoServer.URL,
+					BitGoEnvironment:        bitgo.EnvironmentTest,
+					BitGoAccessToken:        "user-token",
+					BitGoServiceAccessToken: tt.serviceToken,
+				},
+				bitgoRequestLogger: NewBitGoRequestLogger(),
+			}
+			server.initBitGoClient()
+
+			tokenOf := func(client bitgo.BitGoAPI) string {
+				t.Helper()
+				user, err := client.GetCurrentUser(context.Background())
+				if err != nil {
+					t.Fatalf("GetCurrentUser() error = %v", err)
+				}
+				return user.ID
+			}
+			if got := tokenOf(server.bitgoClient); got != "user-token" {
+				t.Errorf("request handlers call BitGo with %q, want the user token", got)
+			}
+			if got := tokenOf(server.workerBitgoClient); got != tt.wantWorker {
+				t.Errorf("workers call BitGo with %q, want %q", got, tt.wantWorker)
+			}
+		})
+	}
+}
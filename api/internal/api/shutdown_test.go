@@ -0,0 +1,175 @@
+package api
+
+import (
+	"context"
+	"errors"
+	"net"
+	"net/http"
+	"testing"
+	"time"
+
+	"bitgo-wallets-api/internal/bitgo"
+	"bitgo-wallets-api/internal/config"
+	"bitgo-wallets-api/internal/models"
+	"bitgo-wallets-api/internal/services"
+
+	"github.com/google/uuid"
+)
+
+// newShutdownTestServer returns a server with every background worker running, serving
+// the handler built by newHandler on a local listener, as Start leaves it
+func newShutdownTestServer(t *testing.T, newHandler func(*Server) http.Handler) (*Server, *memNotificationRepo, string) {
+	t.Helper()
+	logger := &SimpleLogger{}
+	client := bitgo.NewSimulatedClient(bitgo.SimulationConfig{}, logger)
+	walletRepo := newMemWalletRepo()
+	notificationRepo := &memNotificationRepo{}
+
+	server := &Server{
+		config:           &config.Config{NotificationOverflowStrategy: string(services.QueueOverflowDropNew)},
+		featureFlags:     services.NewFeatureFlagStore(services.DefaultFeatureFlags()),
+		walletRepo:       walletRepo,
+		notificationRepo: notificationRepo,
+	}
+	server.initNotificationService()
+
+	retentionConfig := services.DefaultTransferRetentionConfig()
+	retentionConfig.MaxAge = 24 * time.Hour
+	server.warmWalletSvc = services.NewWarmWalletService(client, walletRepo, idleTransferRepo{}, server.notificationSvc, logger, services.DefaultWarmWalletConfig(), nil, nil, nil)
+	server.submissionWorker = services.NewTransferSubmissionWorker(services.DefaultSubmissionWorkerConfig(), logger, client, idleTransferRepo{}, walletRepo, server.notificationSvc)
+	server.pollingWorker = services.NewTransferPollingWorker(services.DefaultPollingWorkerConfig(), logger, client, idleTransferRepo{}, nil, server.notificationSvc)
+	server.approvalSweeper = services.NewApprovalTimeoutSweeper(services.DefaultApprovalTimeoutConfig(), logger, idleTransferRepo{}, server.notificationSvc)
+	server.retentionJob = services.NewTransferRetentionJob(retentionConfig, logger, idleTransferRepo{})
+	for name, start := range map[string]func() error{
+		"polling worker":           server.pollingWorker.Start,
+		"approval timeout sweeper": server.approvalSweeper.Start,
+		"submission worker":        server.submissionWorker.Start,
+		"transfer retention job":   server.retentionJob.Start,
+	} {
+		if err := start(); err != nil {
+			t.Fatalf("start %s: %v", name, err)
+		}
+	}
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("listen: %v", err)
+	}
+	server.httpServer = &http.Server{Handler: newHandler(server)}
+	go server.httpServer.Serve(listener)
+	server.started.Store(true)
+
+	return server, notificationRepo, "http://" + listener.Addr().String()
+}
+
+// blockingHandler holds each request until release is closed, then sends a notification
+// for transfer so the request leaves work for the notification queue
+type blockingHandler struct {
+	notifier services.NotificationService
+	transfer *models.TransferRequest
+	entered  chan struct{}
+	release  chan struct{}
+}
+
+func (h *blockingHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	close(h.entered)
+	<-h.release
+	h.notifier.SendTransferCreatedNotification(h.transfer)
+	w.WriteHeader(http.StatusOK)
+}
+
+// newBlockingHandler returns a blocking handler and a newShutdownTestServer handler builder
+// that wires it to the server's notification service
+func newBlockingHandler() (*blockingHandler, func(*Server) http.Handler) {
+	handler := &blockingHandler{
+		transfer: &models.TransferRequest{ID: uuid.New(), WalletID: uuid.New(), AmountString: "0.1", Coin: "btc", TransferType: models.WalletTypeWarm},
+		entered:  make(chan struct{}),
+		release:  make(chan struct{}),
+	}
+	return handler, func(server *Server) http.Handler {
+		handler.notifier = server.notificationSvc
+		return handler
+	}
+}
+
+func TestShutdownDrainsInFlightWorkAndStopsWorkers(t *testing.T) {
+	handler, newHandler := newBlockingHandler()
+	server, notificationRepo, baseURL := newShutdownTestServer(t, newHandler)
+
+	// A request is in flight when shutdown begins
+	responses := make(chan int, 1)
+	go func() {
+		resp, err := http.Get(baseURL + "/slow")
+		if err != nil {
+			t.Errorf("in-flight request error = %v", err)
+			responses <- 0
+			return
+		}
+		resp.Body.Close()
+		responses <- resp.StatusCode
+	}()
+	<-handler.entered
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	shutdown := make(chan error, 1)
+	go func() { shutdown <- server.Shutdown(ctx) }()
+
+	select {
+	case err := <-shutdown:
+		t.Fatalf("Shutdown() returned %v before the in-flight request finished", err)
+	case <-time.After(100 * time.Millisecond):
+	}
+	if server.started.Load() {
+		t.Error("server still reports started while shutting down")
+	}
+
+	close(handler.release)
+	if code := <-responses; code != http.StatusOK {
+		t.Errorf("in-flight request status = %d, want %d", code, http.StatusOK)
+	}
+	if err := <-shutdown; err != nil {
+		t.Fatalf("Shutdown() error = %v", err)
+	}
+
+	// The notification the request queued was delivered before the queue shut down
+	records, _ := notificationRepo.ListByTransfer(handler.transfer.ID)
+	if len(records) != 1 {
+		t.Errorf("%d notifications recorded for the in-flight request, want 1", len(records))
+	}
+
+	if _, err := http.Get(baseURL + "/slow"); err == nil {
+		t.Error("request after shutdown succeeded, want the listener closed")
+	}
+	if server.pollingWorker.IsRunning() {
+		t.Error("polling worker still running after shutdown")
+	}
+	for name, stop := range map[string]func() error{
+		"warm wallet service":      server.warmWalletSvc.Stop,
+		"submission worker":        server.submissionWorker.Stop,
+		"approval timeout sweeper": server.approvalSweeper.Stop,
+	} {
+		if err := stop(); err == nil {
+			t.Errorf("%s Stop() after shutdown succeeded, want it already stopped", name)
+		}
+	}
+}
+
+func TestShutdownReportsDeadlineWhenWorkOutlastsIt(t *testing.T) {
+	handler, newHandler := newBlockingHandler()
+	server, _, baseURL := newShutdownTestServer(t, newHandler)
+	defer close(handler.release)
+
+	go func() {
+		if resp, err := http.Get(baseURL + "/slow"); err == nil {
+			resp.Body.Close()
+		}
+	}()
+	<-handler.entered
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	if err := server.Shutdown(ctx); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("Shutdown() = %v, want it to wrap %v", err, context.DeadlineExceeded)
+	}
+}
@@ -0,0 +1,199 @@
+package api
+
+import (
+	"context"
+	"errors"
+	"log"
+	"net/http"
+	"sort"
+	"time"
+
+	"bitgo-wallets-api/internal/bitgo"
+	"bitgo-wallets-api/internal/models"
+	"bitgo-wallets-api/internal/repository"
+	"bitgo-wallets-api/internal/services"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// transferDetailBitGoTimeout bounds each BitGo call made for the detail view so an
+// unreachable BitGo degrades the response instead of hanging it
+const transferDetailBitGoTimeout = 10 * time.Second
+
+// TransferHistoryEvent is a single entry in a transfer's merged local and BitGo history
+type TransferHistoryEvent struct {
+	At     time.Time `json:"at"`
+	Source string    `json:"source"` // local, offline_workflow or bitgo
+	Action string    `json:"action"`
+	Actor  string    `json:"actor,omitempty"`
+	Notes  string    `json:"notes,omitempty"`
+}
+
+// TransferDetailResponse combines everything a transfer page needs in one response.
+// Sections that depend on BitGo are null when it can't be reached; Unavailable lists them.
+type TransferDetailResponse struct {
+	Transfer        *models.TransferRequest `json:"transfer"`
+	LocalStatus     models.TransferStatus   `json:"local_status"`
+	CanonicalStatus string                  `json:"canonical_status"`
+	BitgoTransfer   *bitgo.Transfer         `json:"bitgo_transfer"`
+	Outputs         *bitgo.TransferOutputs  `json:"outputs"` // BitGo entries by role; null until BitGo reports the transfer
+	Approval        *bitgo.ApprovalStatus   `json:"approval"`
+	SLA             *services.SLADeadlines  `json:"sla"`
+	EVM             *models.TransferEVMInfo `json:"evm"` // Nonce and gas for EVM coins; BitGo's view when available, else the build's
+	History         []TransferHistoryEvent  `json:"history"`
+	Unavailable     map[string]string       `json:"unavailable,omitempty"`
+}
+
+// getTransferDetail returns the local transfer with its normalized BitGo status, approval
+// status, SLA deadlines and history
+func (s *Server) getTransferDetail(c *gin.Context) {
+	idParam := c.Param("id")
+	id, err := uuid.Parse(idParam)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid transfer ID"})
+		return
+	}
+
+	transfer, err := s.transferRequestRepo.GetByID(id)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to get transfer"})
+		return
+	}
+
+	if transfer == nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Transfer not found"})
+		return
+	}
+	if s.rejectHiddenTransfer(c, transfer) {
+		return
+	}
+
+	wallet, err := s.walletRepo.GetByID(transfer.WalletID)
+	if errors.Is(err, repository.ErrWalletNotFound) {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Wallet not found for transfer"})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to get wallet"})
+		return
+	}
+
+	response := TransferDetailResponse{
+		Transfer:        transfer,
+		LocalStatus:     transfer.Status,
+		CanonicalStatus: string(transfer.Status),
+		Unavailable:     map[string]string{},
+	}
+
+	switch transfer.TransferType {
+	case models.WalletTypeCold:
+		deadlines := s.coldWalletSvc.TransferSLADeadlines(transfer)
+		response.SLA = &deadlines
+	case models.WalletTypeWarm:
+		deadlines := s.warmWalletSvc.TransferSLADeadlines(transfer)
+		response.SLA = &deadlines
+	}
+
+	// BitGo status, only once the transfer has been handed to BitGo
+	if transfer.BitgoTransferID != nil && *transfer.BitgoTransferID != "" {
+		ctx, cancel := context.WithTimeout(context.Background(), transferDetailBitGoTimeout)
+		bitgoTransfer, err := s.bitgoClient.GetTransfer(ctx, wallet.BitgoWalletID, wallet.Coin, *transfer.BitgoTransferID)
+		cancel()
+		if err != nil {
+			response.Unavailable["bitgo_transfer"] = err.Error()
+		} else {
+			statusMapper := bitgo.NewStatusMapper()
+			response.BitgoTransfer = bitgoTransfer
+			outputs := bitgo.CategorizeEntries(bitgoTransfer.Entries)
+			response.Outputs = &outputs
+			response.CanonicalStatus = string(statusMapper.NormalizeTransferStatus(bitgoTransfer.State, bitgoTransfer))
+		}
+	}
+
+	if bitgo.IsEVMCoin(wallet.Coin) {
+		response.EVM = s.transferEVMInfo(transfer, response.BitgoTransfer)
+	}
+
+	// Approval status for transfers that need approvals
+	if transfer.RequiredApprovals > 0 && transfer.BitgoTransferID != nil && *transfer.BitgoTransferID != "" {
+		ctx, cancel := context.WithTimeout(context.Background(), transferDetailBitGoTimeout)
+		approval, err := s.approvalSvc.GetTransferApprovalStatus(ctx, wallet.BitgoWalletID, wallet.Coin, *transfer.BitgoTransferID, "")
+		cancel()
+		if err != nil {
+			response.Unavailable["approval"] = err.Error()
+		} else {
+			response.Approval = approval
+		}
+	}
+
+	response.History = transferHistory(transfer, response.BitgoTransfer)
+	if len(response.Unavailable) == 0 {
+		response.Unavailable = nil
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+// transferEVMInfo returns the EVM nonce and gas BitGo reports for the transfer, falling back
+// to what was recorded when it was built
+func (s *Server) transferEVMInfo(transfer *models.TransferRequest, bitgoTransfer *bitgo.Transfer) *models.TransferEVMInfo {
+	if bitgoTransfer != nil {
+		decoded, err := bitgo.DecodeEVMCoinSpecific(bitgoTransfer.CoinSpecific)
+		if err != nil {
+			log.Printf("[WARN] Ignoring BitGo coinSpecific for transfer %s: %v", transfer.ID, err)
+		}
+		if info := newTransferEVMInfo(decoded); info != nil {
+			return info
+		}
+	}
+	if transfer.BuildInfo != nil {
+		return transfer.BuildInfo.EVM
+	}
+	return nil
+}
+
+// transferHistory merges the transfer's lifecycle timestamps, offline workflow transitions
+// and BitGo history into a single timeline, oldest first
+func transferHistory(transfer *models.TransferRequest, bitgoTransfer *bitgo.Transfer) []TransferHistoryEvent {
+	history := []TransferHistoryEvent{
+		{At: transfer.CreatedAt, Source: "local", Action: "created"},
+	}
+
+	addTimestamp := func(at *time.Time, action string) {
+		if at != nil {
+			history = append(history, TransferHistoryEvent{At: *at, Source: "local", Action: action})
+		}
+	}
+	addTimestamp(transfer.ApprovedAt, "approved")
+	addTimestamp(transfer.SubmittedAt, "submitted")
+	addTimestamp(transfer.CompletedAt, "completed")
+	addTimestamp(transfer.FailedAt, "failed")
+
+	for _, transition := range services.OfflineStateHistory(transfer) {
+		history = append(history, TransferHistoryEvent{
+			At:     transition.At,
+			Source: "offline_workflow",
+			Action: string(transition.From) + " -> " + string(transition.To),
+			Notes:  transition.Notes,
+		})
+	}
+
+	if bitgoTransfer != nil {
+		for _, entry := range bitgoTransfer.History {
+			history = append(history, TransferHistoryEvent{
+				At:     entry.Date,
+				Source: "bitgo",
+				Action: entry.Action,
+				Actor:  entry.User,
+				Notes:  entry.Comment,
+			})
+		}
+	}
+
+	sort.SliceStable(history, func(i, j int) bool {
+		return history[i].At.Before(history[j].At)
+	})
+
+	return history
+}
@@ -0,0 +1,138 @@
+package api
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"bitgo-wallets-api/internal/bitgo"
+	"bitgo-wallets-api/internal/models"
+	"bitgo-wallets-api/internal/services"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// confirmedTransferClient reports every transfer as confirmed on BitGo; pending approvals come
+// from the simulation, which has none
+type confirmedTransferClient struct {
+	*bitgo.SimulatedClient
+}
+
+func (c *confirmedTransferClient) GetTransfer(ctx context.Context, walletID, coin, transferID string) (*bitgo.Transfer, error) {
+	return &bitgo.Transfer{
+		ID:            transferID,
+		Coin:          coin,
+		Wallet:        walletID,
+		TxID:          "txid-1",
+		State:         bitgo.TransferStatusConfirmed,
+		Confirmations: bitgo.RequiredConfirmations(coin),
+		Entries:       []bitgo.TransferEntry{{Address: testBTCAddress, Value: 1000000, ValueString: "1000000"}},
+		History:       []bitgo.TransferHistory{{Date: time.Now(), Action: "confirmed"}},
+	}, nil
+}
+
+// unreachableClient fails every BitGo call the detail view makes
+type unreachableClient struct {
+	bitgo.BitGoAPI
+}
+
+func (unreachableClient) GetTransfer(ctx context.Context, walletID, coin, transferID string) (*bitgo.Transfer, error) {
+	return nil, errors.New("dial tcp: connection refused")
+}
+
+func TestTransferDetail(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	simulated := bitgo.NewSimulatedClient(bitgo.SimulationConfig{}, &SimpleLogger{})
+
+	tests := []struct {
+		name            string
+		client          bitgo.BitGoAPI
+		wantCanonical   string
+		wantBitGo       bool
+		wantUnavailable []string
+	}{
+		{name: "bitgo reachable", client: &confirmedTransferClient{SimulatedClient: simulated}, wantCanonical: string(bitgo.CanonicalStatusConfirmed), wantBitGo: true},
+		{name: "bitgo down", client: unreachableClient{BitGoAPI: simulated}, wantCanonical: string(models.TransferStatusBroadcast), wantUnavailable: []string{"bitgo_transfer", "approval"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			wallet := &models.Wallet{ID: uuid.New(), BitgoWalletID: "bitgo-warm-1", Coin: "btc", WalletType: models.WalletTypeWarm}
+			bitgoTransferID := "bitgo-transfer-1"
+			transfer := &models.TransferRequest{
+				ID:                uuid.New(),
+				WalletID:          wallet.ID,
+				RecipientAddress:  testBTCAddress,
+				AmountString:      "0.01",
+				Coin:              "btc",
+				TransferType:      models.WalletTypeWarm,
+				Status:            models.TransferStatusBroadcast,
+				RequiredApprovals: 1,
+				BitgoTransferID:   &bitgoTransferID,
+				CreatedAt:         time.Now().Add(-time.Hour),
+			}
+			server := &Server{
+				bitgoClient:         tt.client,
+				walletRepo:          newMemWalletRepo(wallet),
+				transferRequestRepo: newMemTransferRepo(transfer),
+				approvalSvc:         bitgo.NewApprovalService(tt.client, &SimpleLogger{}),
+				warmWalletSvc:       services.NewWarmWalletService(tt.client, nil, nil, nopNotifier{}, &SimpleLogger{}, services.DefaultWarmWalletConfig(), nil, nil, nil),
+			}
+			router := gin.New()
+			router.GET("/transfers/:id/detail", server.getTransferDetail)
+
+			recorder := httptest.NewRecorder()
+			router.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/transfers/"+transfer.ID.String()+"/detail", nil))
+			if recorder.Code != http.StatusOK {
+				t.Fatalf("status = %d, want %d: %s", recorder.Code, http.StatusOK, recorder.Body.String())
+			}
+
+			var body struct {
+				Transfer        *models.TransferRequest `json:"transfer"`
+				LocalStatus     string                  `json:"local_status"`
+				CanonicalStatus string                  `json:"canonical_status"`
+				BitgoTransfer   *bitgo.Transfer         `json:"bitgo_transfer"`
+				Outputs         *bitgo.TransferOutputs  `json:"outputs"`
+				SLA             *services.SLADeadlines  `json:"sla"`
+				History         []TransferHistoryEvent  `json:"history"`
+				Unavailable     map[string]string       `json:"unavailable"`
+			}
+			decodeJSON(t, recorder, &body)
+
+			if body.Transfer == nil || body.Transfer.ID != transfer.ID || body.LocalStatus != string(models.TransferStatusBroadcast) {
+				t.Errorf("transfer %v with local status %q, want %s broadcast", body.Transfer, body.LocalStatus, transfer.ID)
+			}
+			if body.CanonicalStatus != tt.wantCanonical {
+				t.Errorf("canonical_status = %q, want %q", body.CanonicalStatus, tt.wantCanonical)
+			}
+			if body.SLA == nil {
+				t.Error("sla missing, want the warm transfer's deadlines")
+			}
+			if (body.BitgoTransfer != nil) != tt.wantBitGo || (body.Outputs != nil) != tt.wantBitGo {
+				t.Errorf("bitgo_transfer %v, outputs %v; want present = %v", body.BitgoTransfer, body.Outputs, tt.wantBitGo)
+			}
+			bitgoEvents := 0
+			for _, event := range body.History {
+				if event.Source == "bitgo" {
+					bitgoEvents++
+				}
+			}
+			if len(body.History) == 0 || (bitgoEvents > 0) != tt.wantBitGo {
+				t.Errorf("history %+v, want local events and BitGo events present = %v", body.History, tt.wantBitGo)
+			}
+
+			if len(body.Unavailable) != len(tt.wantUnavailable) {
+				t.Errorf("unavailable = %v, want %v", body.Unavailable, tt.wantUnavailable)
+			}
+			for _, section := range tt.wantUnavailable {
+				if body.Unavailable[section] == "" {
+					t.Errorf("unavailable[%q] missing, want the BitGo error", section)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,72 @@
+package api
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+
+	"bitgo-wallets-api/internal/models"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// defaultTransferEventLimit is how many events a page of the event log holds by default
+const defaultTransferEventLimit = 100
+
+// getTransferEvents returns a transfer's event log in order. Consumers replaying the log pass
+// the last sequence number they saw as after and keep reading while has_more is set.
+func (s *Server) getTransferEvents(c *gin.Context) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid transfer ID"})
+		return
+	}
+
+	limit, _, err := parsePagination(c, defaultTransferEventLimit)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid pagination", "details": err.Error()})
+		return
+	}
+	var after int64
+	if a := c.Query("after"); a != "" {
+		after, err = strconv.ParseInt(strings.TrimSpace(a), 10, 64)
+		if err != nil || after < 0 {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "after must be a non-negative sequence number"})
+			return
+		}
+	}
+
+	transfer, err := s.transferRequestRepo.GetByID(id)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to get transfer"})
+		return
+	}
+	if transfer == nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Transfer not found"})
+		return
+	}
+	if s.rejectHiddenTransfer(c, transfer) {
+		return
+	}
+
+	// Read one extra event to tell whether another page follows
+	events, err := s.transferEventRepo.ListByTransfer(id, after, limit+1)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to list transfer events", "details": err.Error()})
+		return
+	}
+	hasMore := len(events) > limit
+	if hasMore {
+		events = events[:limit]
+	}
+	if events == nil {
+		events = []*models.TransferEvent{}
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"transfer_id": id,
+		"events":      events,
+		"has_more":    hasMore,
+	})
+}
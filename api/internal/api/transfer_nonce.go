@@ -0,0 +1,70 @@
+package api
+
+import (
+	"net/http"
+
+	"bitgo-wallets-api/internal/bitgo"
+	"bitgo-wallets-api/internal/models"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// confirmedNonceStatuses are the statuses in which a transfer's nonce has been used on chain,
+// so there is nothing left to fill
+var confirmedNonceStatuses = map[models.TransferStatus]bool{
+	models.TransferStatusConfirmed: true,
+	models.TransferStatusCompleted: true,
+}
+
+// resolveFillNonce settles the nonce a fillNonce build replaces, taking it from the stuck
+// transfer's recorded EVM details when only stuck_transfer_id is given. It responds with 400
+// and returns false when the nonce is missing, unknown or given for another build type.
+func (s *Server) resolveFillNonce(c *gin.Context, walletID uuid.UUID, req *CreateTransferRequest) bool {
+	if req.BuildType != bitgo.BuildTypeFillNonce {
+		if req.Nonce != nil || req.StuckTransferID != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "nonce and stuck_transfer_id are only used with fillNonce builds"})
+			return false
+		}
+		return true
+	}
+
+	if req.Nonce != nil {
+		return true
+	}
+	if req.StuckTransferID == nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "fillNonce builds require nonce or stuck_transfer_id",
+			"code":  "nonce_required",
+		})
+		return false
+	}
+
+	stuck, err := s.transferRequestRepo.GetByID(*req.StuckTransferID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to get stuck transfer", "details": err.Error()})
+		return false
+	}
+	if stuck == nil || stuck.WalletID != walletID {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Stuck transfer not found on this wallet", "code": "nonce_unknown"})
+		return false
+	}
+	if confirmedNonceStatuses[stuck.Status] {
+		c.JSON(http.StatusConflict, gin.H{
+			"error":          "Stuck transfer is already confirmed, so its nonce is used",
+			"current_status": stuck.Status,
+		})
+		return false
+	}
+	if stuck.BuildInfo == nil || stuck.BuildInfo.EVM == nil || stuck.BuildInfo.EVM.Nonce == nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "Stuck transfer has no recorded nonce; pass nonce explicitly",
+			"code":  "nonce_unknown",
+		})
+		return false
+	}
+
+	nonce := *stuck.BuildInfo.EVM.Nonce
+	req.Nonce = &nonce
+	return true
+}
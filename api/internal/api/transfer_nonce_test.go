@@ -0,0 +1,81 @@
+package api
+
+import (
+	"net/http"
+	"testing"
+
+	"bitgo-wallets-api/internal/bitgo"
+	"bitgo-wallets-api/internal/models"
+
+	"github.com/google/uuid"
+)
+
+const testETHAddress = "0x742d35Cc6634C0532925a3b844Bc454e4438f44e"
+
+func TestFillNonceBuildIsPassedToBitGo(t *testing.T) {
+	wallet := &models.Wallet{ID: uuid.New(), BitgoWalletID: "bitgo-eth-1", Coin: "eth", WalletType: models.WalletTypeHot, SpendableBalanceString: "10", IsActive: true}
+	client := &buildRecordingClient{SimulatedClient: bitgo.NewSimulatedClient(bitgo.SimulationConfig{}, &SimpleLogger{})}
+	_, router, _ := newHotTransferTestServer(wallet, client)
+
+	nonce := uint64(42)
+	recorder := postTransfer(t, router, wallet, CreateTransferRequest{
+		RecipientAddress: testETHAddress,
+		AmountString:     "0.01",
+		Coin:             "eth",
+		TransferType:     models.WalletTypeHot,
+		BuildType:        bitgo.BuildTypeFillNonce,
+		Nonce:            &nonce,
+	})
+	if recorder.Code != http.StatusCreated {
+		t.Fatalf("status = %d, want %d: %s", recorder.Code, http.StatusCreated, recorder.Body.String())
+	}
+	if len(client.builds) != 1 {
+		t.Fatalf("%d builds sent to BitGo, want 1", len(client.builds))
+	}
+	if build := client.builds[0]; build.Type != bitgo.BuildTypeFillNonce || build.Nonce != "42" {
+		t.Errorf("build type %q with nonce %q, want fillNonce with nonce 42", build.Type, build.Nonce)
+	}
+}
+
+func TestInvalidBuildTypeIsRejected(t *testing.T) {
+	tests := []struct {
+		name      string
+		coin      string
+		address   string
+		buildType string
+	}{
+		{name: "unknown type", coin: "eth", address: testETHAddress, buildType: "consolidate"},
+		{name: "fillNonce on a UTXO coin", coin: "btc", address: testBTCAddress, buildType: bitgo.BuildTypeFillNonce},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			wallet := &models.Wallet{ID: uuid.New(), BitgoWalletID: "bitgo-1", Coin: tt.coin, WalletType: models.WalletTypeHot, SpendableBalanceString: "10", IsActive: true}
+			client := &buildRecordingClient{SimulatedClient: bitgo.NewSimulatedClient(bitgo.SimulationConfig{}, &SimpleLogger{})}
+			_, router, _ := newHotTransferTestServer(wallet, client)
+
+			nonce := uint64(1)
+			recorder := postTransfer(t, router, wallet, CreateTransferRequest{
+				RecipientAddress: tt.address,
+				AmountString:     "0.1",
+				Coin:             tt.coin,
+				TransferType:     models.WalletTypeHot,
+				BuildType:        tt.buildType,
+				Nonce:            &nonce,
+			})
+			if recorder.Code != http.StatusBadRequest {
+				t.Fatalf("status = %d, want %d: %s", recorder.Code, http.StatusBadRequest, recorder.Body.String())
+			}
+			var body struct {
+				Error string `json:"error"`
+			}
+			decodeJSON(t, recorder, &body)
+			if body.Error != "Invalid transaction type" {
+				t.Errorf("error = %q, want %q", body.Error, "Invalid transaction type")
+			}
+			if len(client.builds) != 0 {
+				t.Errorf("%d builds sent to BitGo, want none", len(client.builds))
+			}
+		})
+	}
+}
@@ -0,0 +1,162 @@
+package api
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"strings"
+	"sync"
+
+	"bitgo-wallets-api/internal/bitgo"
+	"bitgo-wallets-api/internal/models"
+	"bitgo-wallets-api/internal/repository"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+const (
+	// maxRefreshTransfers caps how many transfers one bulk refresh can look up
+	maxRefreshTransfers = 100
+	// refreshConcurrency bounds the BitGo lookups a bulk refresh runs at once
+	refreshConcurrency = 8
+)
+
+type RefreshTransfersRequest struct {
+	IDs []string `json:"ids" binding:"required"`
+}
+
+// TransferRefreshResult is the outcome of refreshing one transfer. Refreshed is false for
+// transfers that haven't been submitted to BitGo yet, which are returned as stored.
+type TransferRefreshResult struct {
+	ID              string                        `json:"id"`
+	Transfer        *models.TransferRequest       `json:"transfer,omitempty"`
+	CanonicalStatus bitgo.CanonicalTransferStatus `json:"canonical_status,omitempty"`
+	Refreshed       bool                          `json:"refreshed"`
+	Error           string                        `json:"error,omitempty"`
+}
+
+// refreshTransfers refreshes the status of several transfers from BitGo at once. A failed
+// lookup is reported against its ID without failing the rest of the batch.
+func (s *Server) refreshTransfers(c *gin.Context) {
+	var req RefreshTransfersRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	// Keep the caller's order but look each transfer up only once
+	ids := make([]string, 0, len(req.IDs))
+	seen := make(map[string]bool, len(req.IDs))
+	for _, id := range req.IDs {
+		id = strings.TrimSpace(id)
+		if id == "" || seen[id] {
+			continue
+		}
+		seen[id] = true
+		ids = append(ids, id)
+	}
+	if len(ids) == 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "At least one transfer ID is required"})
+		return
+	}
+	if len(ids) > maxRefreshTransfers {
+		c.JSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("At most %d transfers can be refreshed at once", maxRefreshTransfers)})
+		return
+	}
+
+	ctx := context.Background()
+	results := make([]TransferRefreshResult, len(ids))
+	slots := make(chan struct{}, refreshConcurrency)
+	var wg sync.WaitGroup
+
+	for i, id := range ids {
+		wg.Add(1)
+		slots <- struct{}{}
+		go func(i int, id string) {
+			defer wg.Done()
+			defer func() { <-slots }()
+			results[i] = s.refreshTransfer(ctx, id)
+		}(i, id)
+	}
+	wg.Wait()
+
+	failed := 0
+	for _, result := range results {
+		if result.Error != "" {
+			failed++
+		}
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"results":   results,
+		"succeeded": len(results) - failed,
+		"failed":    failed,
+	})
+}
+
+// refreshTransfer fetches one transfer's status from BitGo and saves it if it changed
+func (s *Server) refreshTransfer(ctx context.Context, idParam string) TransferRefreshResult {
+	result := TransferRefreshResult{ID: idParam}
+
+	id, err := uuid.Parse(idParam)
+	if err != nil {
+		result.Error = "invalid transfer ID"
+		return result
+	}
+
+	transfer, err := s.transferRequestRepo.GetByID(id)
+	if err != nil {
+		result.Error = "failed to get transfer"
+		return result
+	}
+	if transfer == nil {
+		result.Error = "transfer not found"
+		return result
+	}
+
+	result.Transfer = transfer
+	result.CanonicalStatus = bitgo.CanonicalTransferStatus(transfer.Status)
+	if transfer.BitgoTransferID == nil {
+		return result
+	}
+
+	wallet, err := s.walletRepo.GetByID(transfer.WalletID)
+	if errors.Is(err, repository.ErrWalletNotFound) {
+		result.Error = "wallet not found for transfer"
+		return result
+	}
+	if err != nil {
+		result.Error = "failed to get wallet"
+		return result
+	}
+
+	bitgoTransfer, err := s.bitgoClient.GetTransfer(ctx, wallet.BitgoWalletID, wallet.Coin, *transfer.BitgoTransferID)
+	if err != nil {
+		result.Error = "failed to get transfer status from BitGo: " + err.Error()
+		return result
+	}
+	if transfer.PollFailures > 0 || transfer.NeedsAttentionAt != nil {
+		// A manual refresh that reaches BitGo puts a backed-off transfer back on the normal schedule
+		if err := s.transferRequestRepo.ResetPollFailures(transfer.ID); err != nil {
+			log.Printf("[WARN] Failed to reset poll failures for transfer %s: %v", transfer.ID, err)
+		}
+	}
+
+	var canonicalStatus bitgo.CanonicalTransferStatus
+	_, err = repository.UpdateWithRetry(s.transferRequestRepo, transfer, func(transfer *models.TransferRequest) bool {
+		var changed bool
+		canonicalStatus, changed = applyBitGoTransferStatus(transfer, bitgoTransfer)
+		return changed
+	})
+	if err != nil {
+		result.Error = "failed to update transfer"
+		return result
+	}
+
+	result.CanonicalStatus = canonicalStatus
+	result.Refreshed = true
+	return result
+}
@@ -0,0 +1,120 @@
+package api
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"bitgo-wallets-api/internal/bitgo"
+	"bitgo-wallets-api/internal/models"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// refreshClient confirms BitGo transfer bitgo-confirmed and fails every other lookup
+type refreshClient struct {
+	*bitgo.SimulatedClient
+}
+
+func (refreshClient) GetTransfer(ctx context.Context, walletID, coin, transferID string) (*bitgo.Transfer, error) {
+	if transferID != "bitgo-confirmed" {
+		return nil, errors.New("dial tcp: connection refused")
+	}
+	return &bitgo.Transfer{
+		ID:            transferID,
+		Coin:          coin,
+		Wallet:        walletID,
+		TxID:          "txid-1",
+		State:         bitgo.TransferStatusConfirmed,
+		Confirmations: bitgo.RequiredConfirmations(coin),
+	}, nil
+}
+
+func TestRefreshTransfersReportsFailuresPerTransfer(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	wallet := &models.Wallet{ID: uuid.New(), BitgoWalletID: "bitgo-wallet-1", Coin: "btc", WalletType: models.WalletTypeWarm}
+	newTransfer := func(status models.TransferStatus, bitgoTransferID string) *models.TransferRequest {
+		transfer := &models.TransferRequest{
+			ID:               uuid.New(),
+			WalletID:         wallet.ID,
+			RecipientAddress: testBTCAddress,
+			AmountString:     "0.1",
+			Coin:             "btc",
+			TransferType:     models.WalletTypeWarm,
+			Status:           status,
+			Version:          1,
+		}
+		if bitgoTransferID != "" {
+			transfer.BitgoTransferID = &bitgoTransferID
+		}
+		return transfer
+	}
+	confirmed := newTransfer(models.TransferStatusBroadcast, "bitgo-confirmed")
+	unreachable := newTransfer(models.TransferStatusBroadcast, "bitgo-unreachable")
+	unsubmitted := newTransfer(models.TransferStatusPendingApproval, "")
+	missing := uuid.New().String()
+
+	repo := newMemTransferRepo(confirmed, unreachable, unsubmitted)
+	server := &Server{
+		bitgoClient:         refreshClient{bitgo.NewSimulatedClient(bitgo.SimulationConfig{}, &SimpleLogger{})},
+		walletRepo:          newMemWalletRepo(wallet),
+		transferRequestRepo: repo,
+	}
+	router := gin.New()
+	router.POST("/transfers/refresh", server.refreshTransfers)
+
+	ids := []string{confirmed.ID.String(), unreachable.ID.String(), unsubmitted.ID.String(), missing, "not-a-uuid", confirmed.ID.String()}
+	recorder := httptest.NewRecorder()
+	router.ServeHTTP(recorder, httptest.NewRequest(http.MethodPost, "/transfers/refresh", jsonBody(t, RefreshTransfersRequest{IDs: ids})))
+	if recorder.Code != http.StatusOK {
+		t.Fatalf("status = %d, want %d: %s", recorder.Code, http.StatusOK, recorder.Body.String())
+	}
+
+	var body struct {
+		Results   []TransferRefreshResult `json:"results"`
+		Succeeded int                     `json:"succeeded"`
+		Failed    int                     `json:"failed"`
+	}
+	decodeJSON(t, recorder, &body)
+
+	// The duplicate ID is looked up once, and results keep the request's order
+	want := []struct {
+		id        string
+		refreshed bool
+		wantErr   bool
+	}{
+		{id: confirmed.ID.String(), refreshed: true},
+		{id: unreachable.ID.String(), wantErr: true},
+		{id: unsubmitted.ID.String()},
+		{id: missing, wantErr: true},
+		{id: "not-a-uuid", wantErr: true},
+	}
+	if len(body.Results) != len(want) {
+		t.Fatalf("%d results, want %d: %+v", len(body.Results), len(want), body.Results)
+	}
+	for i, w := range want {
+		result := body.Results[i]
+		if result.ID != w.id || result.Refreshed != w.refreshed || (result.Error != "") != w.wantErr {
+			t.Errorf("result %d = %+v, want id %s, refreshed %v, error %v", i, result, w.id, w.refreshed, w.wantErr)
+		}
+	}
+	if body.Succeeded != 2 || body.Failed != 3 {
+		t.Errorf("succeeded %d, failed %d; want 2 and 3", body.Succeeded, body.Failed)
+	}
+	if body.Results[0].CanonicalStatus != bitgo.CanonicalStatusConfirmed {
+		t.Errorf("canonical_status = %q, want %q", body.Results[0].CanonicalStatus, bitgo.CanonicalStatusConfirmed)
+	}
+
+	stored, _ := repo.GetByID(confirmed.ID)
+	if stored.Status != models.TransferStatusConfirmed {
+		t.Errorf("refreshed transfer status = %s, want %s", stored.Status, models.TransferStatusConfirmed)
+	}
+	stored, _ = repo.GetByID(unreachable.ID)
+	if stored.Status != models.TransferStatusBroadcast {
+		t.Errorf("failed lookup changed status to %s, want it left %s", stored.Status, models.TransferStatusBroadcast)
+	}
+}
@@ -0,0 +1,259 @@
+package api
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	"bitgo-wallets-api/internal/bitgo"
+	"bitgo-wallets-api/internal/models"
+	"bitgo-wallets-api/internal/repository"
+	"bitgo-wallets-api/internal/services"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// metadataRetries is the metadata key holding a transfer's retry attempts, oldest first
+const metadataRetries = "retries"
+
+// metadataAllowSelfSend records that the transfer was created with allow_self_send, so a
+// retry re-checks the recipient the same way
+const metadataAllowSelfSend = "allow_self_send"
+
+// retryTransfer re-attempts a failed transfer in place so its history is kept. A transfer
+// that was built but failed to submit is resubmitted; a hot transfer that failed to build is
+// rebuilt under a fresh sequence ID. Cold and warm transfers that never built need new
+// approvals, so they can't be retried.
+func (s *Server) retryTransfer(c *gin.Context) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid transfer ID"})
+		return
+	}
+
+	transfer, err := s.transferRequestRepo.GetByID(id)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to get transfer"})
+		return
+	}
+	if transfer == nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Transfer not found"})
+		return
+	}
+
+	if transfer.Status != models.TransferStatusFailed {
+		c.JSON(http.StatusConflict, gin.H{
+			"error":          "Only failed transfers can be retried",
+			"current_status": transfer.Status,
+		})
+		return
+	}
+
+	resubmit := transfer.BitgoTxid != nil
+	if !resubmit && transfer.TransferType != models.WalletTypeHot {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Transfer cannot be retried",
+			"details": fmt.Sprintf("%s transfers that failed before building need fresh approvals; create a new transfer with origin \"retry\"", transfer.TransferType),
+		})
+		return
+	}
+
+	wallet, err := s.walletRepo.GetByID(transfer.WalletID)
+	if errors.Is(err, repository.ErrWalletNotFound) {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Wallet not found for transfer"})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to get wallet"})
+		return
+	}
+
+	if s.rejectRetry(c, wallet, transfer) {
+		return
+	}
+
+	attempt := map[string]interface{}{
+		"attempted_at":       time.Now().UTC(),
+		"previous_failed_at": transfer.FailedAt,
+	}
+	if transfer.StatusReason != nil {
+		attempt["previous_reason"] = *transfer.StatusReason
+	}
+
+	ctx := context.Background()
+	if resubmit {
+		attempt["action"] = "submit"
+		err = s.resubmitTransfer(ctx, wallet, transfer)
+	} else {
+		sequenceID := uuid.New().String()
+		attempt["action"] = "build"
+		attempt["sequence_id"] = sequenceID
+		err = s.rebuildHotTransfer(ctx, wallet, transfer, sequenceID)
+	}
+
+	if err != nil {
+		attempt["result"] = "failed"
+		attempt["error"] = err.Error()
+
+		// Reapply the attempt to the latest row on a version conflict, so the history isn't lost
+		now := time.Now()
+		recorded, updateErr := repository.UpdateWithRetry(s.transferRequestRepo, transfer, func(transfer *models.TransferRequest) bool {
+			if transfer.Status != models.TransferStatusFailed {
+				return false
+			}
+			transfer.FailedAt = &now
+			recordRetryAttempt(transfer, attempt)
+			return true
+		})
+		if updateErr != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{
+				"error":   "Failed to record the retry attempt",
+				"details": fmt.Sprintf("%v; recording the attempt: %v", err, updateErr),
+			})
+			return
+		}
+		if !recorded {
+			c.JSON(http.StatusConflict, gin.H{
+				"error":          "Transfer was updated by another request during the retry",
+				"details":        err.Error(),
+				"current_status": transfer.Status,
+			})
+			return
+		}
+
+		c.JSON(http.StatusBadGateway, gin.H{
+			"error":    "Retry failed",
+			"details":  err.Error(),
+			"transfer": transfer,
+		})
+		return
+	}
+
+	attempt["result"] = "succeeded"
+	transfer.FailedAt = nil
+	transfer.StatusReason = nil
+	recordRetryAttempt(transfer, attempt)
+
+	if err := s.transferRequestRepo.Update(transfer); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update transfer"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"transfer": transfer,
+		"retry":    attempt,
+	})
+}
+
+// rejectRetry runs the guards a new transfer goes through, since the recipient may have been
+// blocked or the wallet frozen since the transfer was first checked. It responds and returns
+// true when the retry must not go ahead.
+func (s *Server) rejectRetry(c *gin.Context, wallet *models.Wallet, transfer *models.TransferRequest) bool {
+	allowSelfSend := false
+	if transfer.Metadata != nil {
+		allowSelfSend, _ = transfer.Metadata[metadataAllowSelfSend].(bool)
+	}
+
+	return s.rejectNetworkMismatch(c, transfer.Coin, transfer.RecipientAddress) ||
+		s.rejectInvalidAddress(c, transfer.Coin, transfer.RecipientAddress) ||
+		s.rejectBlockedAddress(c, transfer.RecipientAddress) ||
+		s.rejectSelfSend(c, wallet.ID, transfer.RecipientAddress, allowSelfSend) ||
+		s.rejectFrozenWallet(c, wallet)
+}
+
+// rebuildHotTransfer builds a hot transfer again with the original recipient and amount
+func (s *Server) rebuildHotTransfer(ctx context.Context, wallet *models.Wallet, transfer *models.TransferRequest, sequenceID string) error {
+	recipientAddress := transfer.RecipientAddress
+	if transfer.DestinationTag != nil {
+		recipientAddress = bitgo.AddressWithDestinationTag(transfer.Coin, recipientAddress, *transfer.DestinationTag)
+	}
+
+	buildRequest := bitgo.BuildTransferRequest{
+		Recipients: []bitgo.TransferRecipient{
+			{
+				Address:      recipientAddress,
+				AmountString: transfer.AmountString,
+			},
+		},
+		SequenceId:  sequenceID,
+		MinConfirms: s.config.HotMinConfirms,
+	}
+	if transfer.Memo != nil {
+		buildRequest.Memo = bitgo.TextMemo(*transfer.Memo)
+	}
+	if transfer.DestinationTag != nil {
+		if memo := bitgo.DestinationTagMemo(transfer.Coin, transfer.RecipientAddress, *transfer.DestinationTag); memo != nil {
+			buildRequest.Memo = memo
+		}
+	}
+	if transfer.Comment != nil {
+		buildRequest.Comment = *transfer.Comment
+	}
+	maxAmount, sendMax := sendMaxAmount(transfer)
+	if sendMax {
+		// Send up to the original ceiling again; the fee may have changed since the first build
+		maxValue, err := sendMaxValue(transfer.Coin, maxAmount)
+		if err != nil {
+			return err
+		}
+		buildRequest.MaxValue = maxValue
+		buildRequest.Recipients[0].AmountString = maxAmount
+	}
+
+	buildResponse, err := s.transferBuilder.BuildTransferIdempotent(ctx, wallet.BitgoWalletID, wallet.Coin, buildRequest)
+	if err != nil {
+		return fmt.Errorf("failed to build transfer with BitGo: %w", err)
+	}
+	if sendMax {
+		if err := applySendMaxAmount(transfer, buildRequest, buildResponse, recipientAddress); err != nil {
+			return err
+		}
+	}
+
+	transfer.Status = models.TransferStatusSigned // Hot transfers go directly to signed
+	if buildResponse.Transfer != nil {
+		transfer.BitgoTxid = &buildResponse.Transfer.TxID
+	}
+	if buildResponse.FeeInfo != nil {
+		transfer.Fee = &buildResponse.FeeInfo.FeeString
+		feeRateStr := fmt.Sprintf("%d", buildResponse.FeeInfo.FeeRate)
+		transfer.FeeRate = &feeRateStr
+	}
+	transfer.BuildInfo = newTransferBuildInfo(buildResponse, wallet.Coin)
+
+	return nil
+}
+
+// resubmitTransfer submits an already built transfer to BitGo again
+func (s *Server) resubmitTransfer(ctx context.Context, wallet *models.Wallet, transfer *models.TransferRequest) error {
+	submitResponse, err := s.bitgoClient.SubmitTransfer(ctx, wallet.BitgoWalletID, wallet.Coin, bitgo.SubmitTransferRequest{
+		TxHex: *transfer.BitgoTxid,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to submit transfer to BitGo: %w", err)
+	}
+
+	services.ApplySubmittedStatus(transfer, submitResponse, time.Now())
+	if submitResponse.Transfer != nil {
+		transfer.BitgoTransferID = &submitResponse.Transfer.ID
+		transfer.TransactionHash = &submitResponse.Transfer.TxID
+	} else if submitResponse.TxID != "" {
+		transfer.TransactionHash = &submitResponse.TxID
+	}
+
+	return nil
+}
+
+// recordRetryAttempt appends attempt to the transfer's retry history in metadata
+func recordRetryAttempt(transfer *models.TransferRequest, attempt map[string]interface{}) {
+	if transfer.Metadata == nil {
+		transfer.Metadata = models.JSON{}
+	}
+
+	retries, _ := transfer.Metadata[metadataRetries].([]interface{})
+	attempt["attempt"] = len(retries) + 1
+	transfer.Metadata[metadataRetries] = append(retries, attempt)
+}
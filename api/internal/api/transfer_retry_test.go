@@ -0,0 +1,163 @@
+package api
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"bitgo-wallets-api/internal/bitgo"
+	"bitgo-wallets-api/internal/config"
+	"bitgo-wallets-api/internal/models"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// newRetryTestServer returns a server routed for POST /transfers/:id/retry holding transfer,
+// which is from a warm BTC wallet
+func newRetryTestServer(transfer *models.TransferRequest) (*Server, *gin.Engine) {
+	gin.SetMode(gin.TestMode)
+
+	wallet := &models.Wallet{ID: uuid.New(), BitgoWalletID: "bitgo-wallet-1", Coin: "btc", WalletType: models.WalletTypeWarm, IsActive: true}
+	transfer.WalletID = wallet.ID
+	server := &Server{
+		config:              &config.Config{AdminAPIKey: testAdminKey},
+		bitgoClient:         bitgo.NewSimulatedClient(bitgo.SimulationConfig{}, &SimpleLogger{}),
+		walletRepo:          newMemWalletRepo(wallet),
+		walletAddressRepo:   memWalletAddressRepo{},
+		blockedAddressRepo:  newMemBlockedAddressRepo(),
+		transferRequestRepo: newMemTransferRepo(transfer),
+	}
+	router := gin.New()
+	router.POST("/transfers/:id/retry", server.requireAdmin(), server.idempotencyMiddleware(), server.retryTransfer)
+	return server, router
+}
+
+// newRetryTestTransfer returns a built warm transfer in the given status
+func newRetryTestTransfer(status models.TransferStatus) *models.TransferRequest {
+	txHex := "signed-tx-hex"
+	reason := "BitGo unavailable"
+	return &models.TransferRequest{
+		ID:               uuid.New(),
+		RecipientAddress: testBTCAddress,
+		AmountString:     "0.1",
+		Coin:             "btc",
+		TransferType:     models.WalletTypeWarm,
+		Status:           status,
+		StatusReason:     &reason,
+		BitgoTxid:        &txHex,
+		Version:          1,
+	}
+}
+
+func retryRequest(transfer *models.TransferRequest, adminKey string) *http.Request {
+	request := httptest.NewRequest(http.MethodPost, "/transfers/"+transfer.ID.String()+"/retry", nil)
+	if adminKey != "" {
+		request.Header.Set("X-Admin-Key", adminKey)
+	}
+	return request
+}
+
+func TestRetryResubmitsFailedTransfer(t *testing.T) {
+	transfer := newRetryTestTransfer(models.TransferStatusFailed)
+	server, router := newRetryTestServer(transfer)
+
+	recorder := httptest.NewRecorder()
+	router.ServeHTTP(recorder, retryRequest(transfer, ""))
+	if recorder.Code != http.StatusUnauthorized {
+		t.Fatalf("without admin credentials: status = %d, want %d", recorder.Code, http.StatusUnauthorized)
+	}
+	if stored, _ := server.transferRequestRepo.GetByID(transfer.ID); stored.Status != models.TransferStatusFailed {
+		t.Fatalf("status = %s after an unauthorized retry, want it left %s", stored.Status, models.TransferStatusFailed)
+	}
+
+	recorder = httptest.NewRecorder()
+	router.ServeHTTP(recorder, retryRequest(transfer, testAdminKey))
+	if recorder.Code != http.StatusOK {
+		t.Fatalf("status = %d, want %d: %s", recorder.Code, http.StatusOK, recorder.Body.String())
+	}
+
+	var body struct {
+		Retry map[string]interface{} `json:"retry"`
+	}
+	decodeJSON(t, recorder, &body)
+	if body.Retry["action"] != "submit" || body.Retry["result"] != "succeeded" {
+		t.Errorf("retry = %v, want a succeeded submit", body.Retry)
+	}
+
+	stored, _ := server.transferRequestRepo.GetByID(transfer.ID)
+	if stored.Status == models.TransferStatusFailed {
+		t.Error("transfer is still failed after a successful retry")
+	}
+	if stored.TransactionHash == nil {
+		t.Error("TransactionHash = nil, want the resubmitted transaction")
+	}
+	if stored.StatusReason != nil {
+		t.Errorf("StatusReason = %q, want it cleared", *stored.StatusReason)
+	}
+	if retries, _ := stored.Metadata[metadataRetries].([]interface{}); len(retries) != 1 {
+		t.Errorf("recorded %d retry attempts, want 1", len(retries))
+	}
+}
+
+func TestRetryRejectsConfirmedTransfer(t *testing.T) {
+	transfer := newRetryTestTransfer(models.TransferStatusConfirmed)
+	server, router := newRetryTestServer(transfer)
+
+	recorder := httptest.NewRecorder()
+	router.ServeHTTP(recorder, retryRequest(transfer, testAdminKey))
+	if recorder.Code != http.StatusConflict {
+		t.Fatalf("status = %d, want %d: %s", recorder.Code, http.StatusConflict, recorder.Body.String())
+	}
+
+	stored, _ := server.transferRequestRepo.GetByID(transfer.ID)
+	if stored.Status != models.TransferStatusConfirmed || stored.Version != transfer.Version {
+		t.Errorf("transfer changed: status = %s, version = %d", stored.Status, stored.Version)
+	}
+}
+
+// racingSubmitClient fails every submit after tagging the transfer, standing in for a
+// concurrent update that lands while BitGo is handling the retry
+type racingSubmitClient struct {
+	*bitgo.SimulatedClient
+	repo       *memTransferRepo
+	transferID uuid.UUID
+}
+
+func (c *racingSubmitClient) SubmitTransfer(ctx context.Context, walletID, coin string, req bitgo.SubmitTransferRequest) (*bitgo.SubmitTransferResponse, error) {
+	if transfer, _ := c.repo.GetByID(c.transferID); transfer != nil {
+		transfer.Tags = []string{"racing"}
+		c.repo.Update(transfer)
+	}
+	return nil, bitgo.APIError{StatusCode: http.StatusServiceUnavailable, Message: "BitGo unavailable"}
+}
+
+func TestFailedRetryRecordsAttemptDespiteConcurrentUpdate(t *testing.T) {
+	transfer := newRetryTestTransfer(models.TransferStatusFailed)
+	server, router := newRetryTestServer(transfer)
+	repo := server.transferRequestRepo.(*memTransferRepo)
+	server.bitgoClient = &racingSubmitClient{
+		SimulatedClient: bitgo.NewSimulatedClient(bitgo.SimulationConfig{}, &SimpleLogger{}),
+		repo:            repo,
+		transferID:      transfer.ID,
+	}
+
+	recorder := httptest.NewRecorder()
+	router.ServeHTTP(recorder, retryRequest(transfer, testAdminKey))
+	if recorder.Code != http.StatusBadGateway {
+		t.Fatalf("status = %d, want %d: %s", recorder.Code, http.StatusBadGateway, recorder.Body.String())
+	}
+
+	stored, _ := repo.GetByID(transfer.ID)
+	retries, _ := stored.Metadata[metadataRetries].([]interface{})
+	if len(retries) != 1 {
+		t.Fatalf("recorded %d retry attempts, want 1", len(retries))
+	}
+	if attempt, _ := retries[0].(map[string]interface{}); attempt["result"] != "failed" {
+		t.Errorf("attempt = %v, want a failed attempt", attempt)
+	}
+	if len(stored.Tags) != 1 || stored.Tags[0] != "racing" {
+		t.Errorf("tags = %v, want the concurrent update kept", stored.Tags)
+	}
+}
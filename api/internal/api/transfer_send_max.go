@@ -0,0 +1,165 @@
+package api
+
+import (
+	"fmt"
+	"math/big"
+	"net/http"
+
+	"bitgo-wallets-api/internal/bitgo"
+	"bitgo-wallets-api/internal/models"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Transfer metadata keys recording a send-max transfer's ceiling
+const (
+	metadataSendMax   = "send_max"
+	metadataMaxAmount = "max_amount"
+)
+
+// rejectInvalidSendMax responds with 400 and returns true when send_max is set on a transfer
+// that can't be built with BitGo's maxValue: only hot sends are built here, and the ceiling
+// must convert to the coin's base units
+func (s *Server) rejectInvalidSendMax(c *gin.Context, wallet *models.Wallet, req CreateTransferRequest) bool {
+	if !req.SendMax {
+		if req.SendMaxBuffer != "" {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "send_max_buffer requires send_max"})
+			return true
+		}
+		return false
+	}
+
+	if wallet.WalletType != models.WalletTypeHot {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Send-max is only supported for hot wallets"})
+		return true
+	}
+	if req.BuildType != "" && req.BuildType != bitgo.BuildTypeSend {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": fmt.Sprintf("Send-max is not supported for %s builds", req.BuildType),
+		})
+		return true
+	}
+	if _, err := sendMaxValue(req.Coin, req.AmountString); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid amount", "code": "invalid_amount", "details": err.Error()})
+		return true
+	}
+	return false
+}
+
+// applySendMaxBuffer lowers a send-max transfer's ceiling so that at least send_max_buffer
+// stays in the wallet: the ceiling becomes the wallet's spendable balance less the buffer
+// when that is below amount_string. It responds with 400 and returns false when the buffer is
+// invalid or leaves nothing to send.
+func (s *Server) applySendMaxBuffer(c *gin.Context, wallet *models.Wallet, req *CreateTransferRequest) bool {
+	if !req.SendMax || req.SendMaxBuffer == "" {
+		return true
+	}
+	if !s.normalizeTransferAmount(c, req.Coin, &req.SendMaxBuffer) {
+		return false
+	}
+
+	ceiling, err := sendMaxCeiling(req.Coin, req.AmountString, wallet.SpendableBalanceString, req.SendMaxBuffer)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid amount", "code": "invalid_amount", "details": err.Error()})
+		return false
+	}
+	req.AmountString = ceiling
+	return true
+}
+
+// sendMaxCeiling returns the lesser of amount and the spendable balance less buffer, all in
+// the coin's units
+func sendMaxCeiling(coin, amount, spendable, buffer string) (string, error) {
+	amountUnits, err := baseUnits(coin, amount)
+	if err != nil {
+		return "", err
+	}
+	spendableUnits, err := baseUnits(coin, spendable)
+	if err != nil {
+		return "", fmt.Errorf("wallet spendable balance %q is unusable: %w", spendable, err)
+	}
+	bufferUnits, err := baseUnits(coin, buffer)
+	if err != nil {
+		return "", err
+	}
+
+	available := new(big.Int).Sub(spendableUnits, bufferUnits)
+	if available.Sign() <= 0 {
+		return "", fmt.Errorf("spendable balance of %s %s does not cover the buffer of %s", spendable, coin, buffer)
+	}
+	if available.Cmp(amountUnits) >= 0 {
+		return amount, nil
+	}
+	return bitgo.AmountFromBaseUnits(coin, available.String())
+}
+
+// sendMaxValue converts a send-max ceiling to the base units BitGo expects as maxValue
+func sendMaxValue(coin, amount string) (string, error) {
+	value, err := baseUnits(coin, amount)
+	if err != nil {
+		return "", err
+	}
+	if value.Sign() <= 0 {
+		return "", fmt.Errorf("amount %q must be positive for a send-max build", amount)
+	}
+	return value.String(), nil
+}
+
+// baseUnits converts an amount in the coin's units to base units
+func baseUnits(coin, amount string) (*big.Int, error) {
+	converted, err := bitgo.AmountToBaseUnits(coin, amount)
+	if err != nil {
+		return nil, err
+	}
+	value, ok := new(big.Int).SetString(converted, 10)
+	if !ok {
+		return nil, fmt.Errorf("amount %q is not a whole number of base units", amount)
+	}
+	return value, nil
+}
+
+// markSendMax records a send-max transfer's ceiling in its metadata, so a rebuild sends up to
+// the same ceiling rather than a fixed amount
+func markSendMax(transfer *models.TransferRequest, maxAmount string) {
+	if transfer.Metadata == nil {
+		transfer.Metadata = models.JSON{}
+	}
+	transfer.Metadata[metadataSendMax] = true
+	transfer.Metadata[metadataMaxAmount] = maxAmount
+}
+
+// sendMaxAmount returns the ceiling of a send-max transfer, or false for a fixed-amount one
+func sendMaxAmount(transfer *models.TransferRequest) (string, bool) {
+	if sendMax, _ := transfer.Metadata[metadataSendMax].(bool); !sendMax {
+		return "", false
+	}
+	maxAmount, ok := transfer.Metadata[metadataMaxAmount].(string)
+	return maxAmount, ok && maxAmount != ""
+}
+
+// applySendMaxAmount sets a send-max transfer's amount to what the build actually pays the
+// recipient after fees. It fails when BitGo didn't report the amount or reported one outside
+// the ceiling.
+func applySendMaxAmount(transfer *models.TransferRequest, buildRequest bitgo.BuildTransferRequest, buildResponse *bitgo.BuildTransferResponse, recipientAddress string) error {
+	sent, ok := buildResponse.SentAmount(recipientAddress)
+	if !ok {
+		return fmt.Errorf("BitGo did not report the amount sent")
+	}
+	maxValue, ok := new(big.Int).SetString(buildRequest.MaxValue, 10)
+	if !ok {
+		return fmt.Errorf("send-max build has no usable maxValue %q", buildRequest.MaxValue)
+	}
+	if sent.Sign() <= 0 || sent.Cmp(maxValue) > 0 {
+		return fmt.Errorf("BitGo built a send of %s base units, outside the maximum of %s", sent, maxValue)
+	}
+
+	amount, err := bitgo.AmountFromBaseUnits(transfer.Coin, sent.String())
+	if err != nil {
+		return err
+	}
+	if err := bitgo.CheckDust(transfer.Coin, amount); err != nil {
+		return fmt.Errorf("BitGo built a send that is too small after fees: %w", err)
+	}
+	transfer.AmountString = amount
+	return nil
+}
@@ -0,0 +1,77 @@
+package api
+
+import (
+	"context"
+	"testing"
+
+	"bitgo-wallets-api/internal/bitgo"
+	"bitgo-wallets-api/internal/models"
+)
+
+const testBTCAddress = "bc1qar0srrr7xfkvy5l643lydnw9re59gtzzwf5mdq"
+
+func TestSendMaxBuildRecordsPostFeeAmount(t *testing.T) {
+	client := bitgo.NewSimulatedClient(bitgo.SimulationConfig{FeeRate: 10000}, &SimpleLogger{})
+
+	maxValue, err := sendMaxValue("btc", "0.001")
+	if err != nil {
+		t.Fatalf("sendMaxValue() error = %v", err)
+	}
+	buildRequest := bitgo.BuildTransferRequest{
+		Recipients: []bitgo.TransferRecipient{{Address: testBTCAddress, AmountString: "0.001"}},
+		MaxValue:   maxValue,
+	}
+	buildResponse, err := client.BuildTransfer(context.Background(), "wallet-1", "btc", buildRequest)
+	if err != nil {
+		t.Fatalf("BuildTransfer() error = %v", err)
+	}
+
+	// 100000 satoshis less the simulated fee of 2500
+	transfer := &models.TransferRequest{Coin: "btc", AmountString: "0.001"}
+	if err := applySendMaxAmount(transfer, buildRequest, buildResponse, testBTCAddress); err != nil {
+		t.Fatalf("applySendMaxAmount() error = %v", err)
+	}
+	if transfer.AmountString != "0.000975" {
+		t.Errorf("AmountString = %q, want %q", transfer.AmountString, "0.000975")
+	}
+}
+
+func TestApplySendMaxAmountRequiresReportedAmount(t *testing.T) {
+	buildRequest := bitgo.BuildTransferRequest{MaxValue: "100000"}
+	buildResponse := &bitgo.BuildTransferResponse{FeeInfo: &bitgo.FeeInfo{Fee: 2500, FeeString: "2500"}}
+
+	transfer := &models.TransferRequest{Coin: "btc", AmountString: "0.001"}
+	if err := applySendMaxAmount(transfer, buildRequest, buildResponse, testBTCAddress); err == nil {
+		t.Error("applySendMaxAmount() succeeded without recipient entries, want an error")
+	}
+	if transfer.AmountString != "0.001" {
+		t.Errorf("AmountString changed to %q on failure", transfer.AmountString)
+	}
+}
+
+func TestSendMaxCeilingKeepsBuffer(t *testing.T) {
+	tests := []struct {
+		name      string
+		amount    string
+		spendable string
+		buffer    string
+		want      string
+		wantErr   bool
+	}{
+		{name: "buffer lowers ceiling", amount: "1", spendable: "0.5", buffer: "0.1", want: "0.4"},
+		{name: "amount below spendable less buffer", amount: "0.2", spendable: "0.5", buffer: "0.1", want: "0.2"},
+		{name: "buffer exceeds balance", amount: "1", spendable: "0.1", buffer: "0.1", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := sendMaxCeiling("btc", tt.amount, tt.spendable, tt.buffer)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("sendMaxCeiling() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("sendMaxCeiling() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,151 @@
+package api
+
+import (
+	"encoding/csv"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"bitgo-wallets-api/internal/bitgo"
+	"bitgo-wallets-api/internal/config"
+	"bitgo-wallets-api/internal/models"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+func TestTransferVisibilityForViewerAndAdmin(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	wallet := &models.Wallet{ID: uuid.New(), BitgoWalletID: "bitgo-wallet-1", Coin: "btc", WalletType: models.WalletTypeHot}
+	viewer, admin, other := uuid.New(), uuid.New(), uuid.New()
+	created := time.Now().Add(-time.Hour)
+	own := &models.TransferRequest{ID: uuid.New(), WalletID: wallet.ID, RequestedByUserID: viewer, RecipientAddress: testBTCAddress, AmountString: "0.1", Coin: "btc", TransferType: models.WalletTypeHot, Status: models.TransferStatusDraft, CreatedAt: created}
+	others := &models.TransferRequest{ID: uuid.New(), WalletID: wallet.ID, RequestedByUserID: other, RecipientAddress: testBTCAddress, AmountString: "0.2", Coin: "btc", TransferType: models.WalletTypeHot, Status: models.TransferStatusDraft, CreatedAt: created.Add(time.Minute)}
+
+	server := &Server{
+		config:              &config.Config{},
+		bitgoClient:         bitgo.NewSimulatedClient(bitgo.SimulationConfig{}, &SimpleLogger{}),
+		walletRepo:          newMemWalletRepo(wallet),
+		transferRequestRepo: newMemTransferRepo(own, others),
+		transferEventRepo:   nopTransferEventRepo{},
+		notificationRepo:    &memNotificationRepo{},
+		membershipRepo: newMemMembershipRepo(
+			&models.WalletMembership{WalletID: wallet.ID, UserID: viewer, Role: string(models.WalletRoleViewer)},
+			&models.WalletMembership{WalletID: wallet.ID, UserID: admin, Role: string(models.WalletRoleAdmin)},
+		),
+	}
+
+	// serve runs a GET, or a POST for submissions, as the given user
+	serve := func(t *testing.T, userID uuid.UUID, path string) *httptest.ResponseRecorder {
+		router := gin.New()
+		router.Use(func(c *gin.Context) { c.Set("user_id", userID.String()) })
+		router.GET("/wallets/:id/transfers/export", server.exportTransfers)
+		router.GET("/transfers/search", server.searchTransfers)
+		router.GET("/transfers/:id", server.getTransfer)
+		router.GET("/transfers/:id/detail", server.getTransferDetail)
+		router.GET("/transfers/:id/events", server.getTransferEvents)
+		router.GET("/transfers/:id/status", server.getTransferStatus)
+		router.GET("/transfers/:id/notifications", server.getTransferNotifications)
+		router.POST("/transfers/:id/submit", server.submitTransfer)
+
+		method := http.MethodGet
+		if strings.HasSuffix(path, "/submit") {
+			method = http.MethodPost
+		}
+		recorder := httptest.NewRecorder()
+		router.ServeHTTP(recorder, httptest.NewRequest(method, path, nil))
+		return recorder
+	}
+
+	tests := []struct {
+		name      string
+		userID    uuid.UUID
+		wantCount int
+	}{
+		{name: "viewer sees own transfers", userID: viewer, wantCount: 1},
+		{name: "admin sees every transfer", userID: admin, wantCount: 2},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			recorder := serve(t, tt.userID, "/wallets/"+wallet.ID.String()+"/transfers/export")
+			if recorder.Code != http.StatusOK {
+				t.Fatalf("export: status = %d, want %d: %s", recorder.Code, http.StatusOK, recorder.Body.String())
+			}
+			records, err := csv.NewReader(recorder.Body).ReadAll()
+			if err != nil {
+				t.Fatalf("read export: %v", err)
+			}
+			if rows := len(records) - 1; rows != tt.wantCount {
+				t.Errorf("export has %d rows, want %d", rows, tt.wantCount)
+			}
+
+			recorder = serve(t, tt.userID, "/transfers/search?recipient="+testBTCAddress)
+			if recorder.Code != http.StatusOK {
+				t.Fatalf("search: status = %d, want %d: %s", recorder.Code, http.StatusOK, recorder.Body.String())
+			}
+			var search struct {
+				Count      int        `json:"count"`
+				Pagination Pagination `json:"pagination"`
+			}
+			decodeJSON(t, recorder, &search)
+			if search.Count != tt.wantCount || search.Pagination.Total != tt.wantCount {
+				t.Errorf("search found %d of %d, want %d", search.Count, search.Pagination.Total, tt.wantCount)
+			}
+
+			for _, suffix := range []string{"", "/detail", "/events", "/status", "/notifications", "/submit"} {
+				for _, transfer := range []*models.TransferRequest{own, others} {
+					want := http.StatusOK
+					if suffix == "/submit" {
+						// Visible drafts get as far as the approval check
+						want = http.StatusBadRequest
+					}
+					if transfer.RequestedByUserID != tt.userID && tt.userID != admin {
+						want = http.StatusNotFound
+					}
+					path := "/transfers/" + transfer.ID.String() + suffix
+					if recorder := serve(t, tt.userID, path); recorder.Code != want {
+						t.Errorf("%s: status = %d, want %d: %s", path, recorder.Code, want, recorder.Body.String())
+					}
+				}
+			}
+		})
+	}
+
+	if code := serve(t, other, "/transfers/"+others.ID.String()).Code; code != http.StatusNotFound {
+		t.Errorf("non-member: status = %d, want %d", code, http.StatusNotFound)
+	}
+}
+
+func TestSearchTransfersRequiresAdmin(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	first := &models.TransferRequest{WalletID: uuid.New(), RecipientAddress: testBTCAddress}
+	second := &models.TransferRequest{WalletID: uuid.New(), RecipientAddress: testBTCAddress}
+	server := &Server{config: &config.Config{AdminAPIKey: testAdminKey}, transferRequestRepo: newMemTransferRepo(first, second)}
+	router := gin.New()
+	router.GET("/transfers/search", server.requireAdmin(), server.searchTransfers)
+
+	recorder := httptest.NewRecorder()
+	router.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/transfers/search?recipient="+testBTCAddress, nil))
+	if recorder.Code != http.StatusUnauthorized {
+		t.Fatalf("without admin credentials: status = %d, want %d", recorder.Code, http.StatusUnauthorized)
+	}
+
+	request := httptest.NewRequest(http.MethodGet, "/transfers/search?recipient="+testBTCAddress, nil)
+	request.Header.Set("X-Admin-Key", testAdminKey)
+	recorder = httptest.NewRecorder()
+	router.ServeHTTP(recorder, request)
+	if recorder.Code != http.StatusOK {
+		t.Fatalf("as admin: status = %d, want %d: %s", recorder.Code, http.StatusOK, recorder.Body.String())
+	}
+	var body struct {
+		Count int `json:"count"`
+	}
+	decodeJSON(t, recorder, &body)
+	if body.Count != 2 {
+		t.Errorf("count = %d, want both wallets' transfers", body.Count)
+	}
+}
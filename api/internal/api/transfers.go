@@ -2,13 +2,17 @@ package api
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"log"
 	"net/http"
 	"strconv"
+	"strings"
 	"time"
 
 	"bitgo-wallets-api/internal/bitgo"
 	"bitgo-wallets-api/internal/models"
+	"bitgo-wallets-api/internal/repository"
 	"bitgo-wallets-api/internal/services"
 
 	"github.com/gin-gonic/gin"
@@ -16,11 +20,31 @@ import (
 )
 
 type CreateTransferRequest struct {
-	RecipientAddress string            `json:"recipient_address" binding:"required"`
-	AmountString     string            `json:"amount_string" binding:"required"`
-	Coin             string            `json:"coin" binding:"required"`
-	TransferType     models.WalletType `json:"transfer_type" binding:"required"`
-	Memo             *string           `json:"memo"`
+	RecipientAddress string                `json:"recipient_address" binding:"required"`
+	AmountString     string                `json:"amount_string" binding:"required"`
+	Coin             string                `json:"coin" binding:"required"`
+	TransferType     models.WalletType     `json:"transfer_type" binding:"required"`
+	Memo             *string               `json:"memo"`
+	DestinationTag   *string               `json:"destination_tag,omitempty"` // XRP destination tag or XLM/EOS memo ID
+	BuildType        string                `json:"type,omitempty"`            // BitGo build type: send, fillNonce or acceleration
+	FeeStrategy      bitgo.FeeStrategy     `json:"fee_strategy,omitempty"`    // economy, normal, priority or custom
+	FeeRate          int64                 `json:"fee_rate,omitempty"`        // Explicit fee rate for the custom strategy; gas price in wei for EVM coins
+	Comment          string                `json:"comment,omitempty"`
+	Tags             []string              `json:"tags,omitempty"`
+	AllowSelfSend    bool                  `json:"allow_self_send,omitempty"` // Permit sending to one of the wallet's own addresses
+	Origin           models.TransferOrigin `json:"origin,omitempty"`          // retry, sweep or consolidate; defaults to api
+
+	// SendMax sends up to amount_string with the fee paid out of it, so the recipient gets the
+	// amount less fees; the transfer records what was actually sent. Hot sends only.
+	SendMax bool `json:"send_max,omitempty"`
+	// SendMaxBuffer keeps at least this much in the wallet on a send-max transfer, lowering the
+	// ceiling to the spendable balance less the buffer when that is below amount_string
+	SendMaxBuffer string `json:"send_max_buffer,omitempty"`
+
+	// fillNonce builds replace a stuck EVM transaction: either give the nonce to fill or the
+	// stuck transfer whose recorded nonce should be used
+	Nonce           *uint64    `json:"nonce,omitempty"`
+	StuckTransferID *uuid.UUID `json:"stuck_transfer_id,omitempty"`
 
 	// Additional fields for warm/cold transfers
 	BusinessPurpose string `json:"business_purpose,omitempty"`
@@ -43,6 +67,11 @@ func (s *Server) createTransfer(c *gin.Context) {
 		return
 	}
 
+	if err := acceptCamelCaseFields(c); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Failed to read request body", "details": err.Error()})
+		return
+	}
+
 	var req CreateTransferRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
 		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
@@ -51,13 +80,93 @@ func (s *Server) createTransfer(c *gin.Context) {
 
 	// Verify wallet exists and get its type
 	wallet, err := s.walletRepo.GetByID(walletID)
+	if errors.Is(err, repository.ErrWalletNotFound) {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Wallet not found"})
+		return
+	}
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to get wallet"})
 		return
 	}
 
-	if wallet == nil {
-		c.JSON(http.StatusNotFound, gin.H{"error": "Wallet not found"})
+	// Validate the requested build type for the coin; only hot transfers are built here
+	if err := bitgo.ValidateBuildType(req.Coin, req.BuildType); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid transaction type", "details": err.Error()})
+		return
+	}
+	if req.BuildType != "" && req.BuildType != bitgo.BuildTypeSend && wallet.WalletType != models.WalletTypeHot {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": fmt.Sprintf("Transaction type %s is only supported for hot wallets", req.BuildType),
+		})
+		return
+	}
+
+	if !s.resolveFillNonce(c, walletID, &req) {
+		return
+	}
+
+	// Validate the fee strategy; like build types, fees are only chosen for hot builds
+	if err := bitgo.ValidateFeeStrategy(req.FeeStrategy, req.FeeRate, s.maxFeeRate(wallet.Coin)); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid fee strategy", "details": err.Error()})
+		return
+	}
+	if req.FeeStrategy != "" && wallet.WalletType != models.WalletTypeHot {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "Fee strategy is only supported for hot wallets",
+		})
+		return
+	}
+
+	// Validate optional comment and tags
+	if err := services.ValidateTransferComment(req.Comment); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid comment", "details": err.Error()})
+		return
+	}
+	tags, err := services.NormalizeTransferTags(req.Tags)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid tags", "details": err.Error()})
+		return
+	}
+	req.Tags = tags
+	origin, err := services.RequestedTransferOrigin(req.Origin)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid origin", "details": err.Error()})
+		return
+	}
+	req.Origin = origin
+
+	if !s.normalizeTransferAmount(c, req.Coin, &req.AmountString) {
+		return
+	}
+	if s.rejectInvalidSendMax(c, wallet, req) {
+		return
+	}
+	if !s.applySendMaxBuffer(c, wallet, &req) {
+		return
+	}
+	if s.rejectDustAmount(c, req.Coin, req.AmountString) {
+		return
+	}
+
+	if s.rejectNetworkMismatch(c, req.Coin, req.RecipientAddress) {
+		return
+	}
+	if s.rejectInvalidAddress(c, req.Coin, req.RecipientAddress) {
+		return
+	}
+	if s.rejectInvalidDestinationTag(c, req) {
+		return
+	}
+	if s.rejectBlockedAddress(c, req.RecipientAddress) {
+		return
+	}
+	if s.rejectSelfSend(c, walletID, req.RecipientAddress, req.AllowSelfSend) {
+		return
+	}
+	if s.rejectFrozenWallet(c, wallet) {
+		return
+	}
+	if s.rejectVelocitySpike(c, wallet, req.AmountString) {
 		return
 	}
 
@@ -78,16 +187,23 @@ func (s *Server) createTransfer(c *gin.Context) {
 			RequestorName:    req.RequestorName,
 			RequestorEmail:   req.RequestorEmail,
 			UrgencyLevel:     req.UrgencyLevel,
+			Comment:          req.Comment,
+			Tags:             req.Tags,
+			Origin:           req.Origin,
 		}
 		if req.Memo != nil {
 			coldReq.Memo = *req.Memo
 		}
+		if req.DestinationTag != nil {
+			coldReq.DestinationTag = *req.DestinationTag
+		}
 
 		transfer, err := s.coldWalletSvc.CreateColdTransferRequest(ctx, coldReq, userID)
 		if err != nil {
 			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 			return
 		}
+		s.recordOutflow(transfer)
 
 		c.JSON(http.StatusCreated, gin.H{
 			"transfer": transfer,
@@ -107,16 +223,23 @@ func (s *Server) createTransfer(c *gin.Context) {
 			RequestorEmail:   req.RequestorEmail,
 			UrgencyLevel:     req.UrgencyLevel,
 			AutoProcess:      req.AutoProcess,
+			Comment:          req.Comment,
+			Tags:             req.Tags,
+			Origin:           req.Origin,
 		}
 		if req.Memo != nil {
 			warmReq.Memo = *req.Memo
 		}
+		if req.DestinationTag != nil {
+			warmReq.DestinationTag = *req.DestinationTag
+		}
 
 		transfer, err := s.warmWalletSvc.CreateWarmTransferRequest(ctx, warmReq, userID)
 		if err != nil {
 			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 			return
 		}
+		s.recordOutflow(transfer)
 
 		c.JSON(http.StatusCreated, gin.H{
 			"transfer": transfer,
@@ -149,12 +272,41 @@ func (s *Server) createHotTransfer(c *gin.Context, walletID uuid.UUID, wallet *m
 		RequiredApprovals: 0, // Hot transfers require no approvals
 		ReceivedApprovals: 0,
 		Memo:              req.Memo,
+		DestinationTag:    req.DestinationTag,
+		Tags:              req.Tags,
+		Origin:            req.Origin,
+	}
+	if comment := strings.TrimSpace(req.Comment); comment != "" {
+		transferRequest.Comment = &comment
+	}
+	if req.SendMax {
+		markSendMax(transferRequest, req.AmountString)
+	}
+	if req.AllowSelfSend {
+		if transferRequest.Metadata == nil {
+			transferRequest.Metadata = models.JSON{}
+		}
+		transferRequest.Metadata[metadataAllowSelfSend] = true
+	}
+
+	// BitGo checks the balance when it builds the transfer, but hold the amount until it is
+	// broadcast so cold and warm transfers validated meanwhile don't count on it
+	hold, err := s.balanceReserves.Hold(walletID, req.Coin, req.AmountString)
+	if err != nil {
+		log.Printf("[WARN] Not holding balance for the new transfer from wallet %s: %v", walletID, err)
 	}
 
 	if err := s.transferRequestRepo.Create(transferRequest); err != nil {
+		if hold != nil {
+			s.balanceReserves.Release(hold)
+		}
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create transfer request"})
 		return
 	}
+	if hold != nil {
+		s.balanceReserves.Assign(hold, transferRequest.ID)
+	}
+	s.recordOutflow(transferRequest)
 
 	// Try to build the transfer with BitGo immediately
 	ctx := context.Background()
@@ -162,19 +314,68 @@ func (s *Server) createHotTransfer(c *gin.Context, walletID uuid.UUID, wallet *m
 	if req.Memo != nil {
 		memoStr = *req.Memo
 	}
+	recipientAddress := req.RecipientAddress
+	if req.DestinationTag != nil {
+		recipientAddress = bitgo.AddressWithDestinationTag(req.Coin, recipientAddress, *req.DestinationTag)
+	}
 
 	buildRequest := bitgo.BuildTransferRequest{
+		Type: req.BuildType,
 		Recipients: []bitgo.TransferRecipient{
 			{
-				Address:      req.RecipientAddress,
+				Address:      recipientAddress,
 				AmountString: req.AmountString,
 			},
 		},
-		Memo: memoStr,
+		Memo:        bitgo.TextMemo(memoStr),
+		Comment:     strings.TrimSpace(req.Comment),
+		SequenceId:  services.SubmitSequenceID(transferRequest),
+		MinConfirms: s.config.HotMinConfirms, // Cold and warm builds take their policy in their services
+	}
+	if req.DestinationTag != nil {
+		if memo := bitgo.DestinationTagMemo(req.Coin, req.RecipientAddress, *req.DestinationTag); memo != nil {
+			buildRequest.Memo = memo
+		}
+	}
+	if req.Nonce != nil {
+		buildRequest.Nonce = strconv.FormatUint(*req.Nonce, 10)
+	}
+	if req.SendMax {
+		// Already validated by rejectInvalidSendMax
+		buildRequest.MaxValue, _ = sendMaxValue(req.Coin, req.AmountString)
+	}
+
+	// Price the fee from BitGo's current estimate unless the caller gave an explicit rate
+	var feeEstimate *bitgo.FeeEstimate
+	if req.FeeStrategy != "" && req.FeeStrategy != bitgo.FeeStrategyCustom {
+		estimate, err := s.bitgoClient.EstimateFee(ctx, wallet.Coin, 0)
+		if err != nil {
+			if !s.failHotTransfer(c, transferRequest, err) {
+				return
+			}
+
+			c.JSON(http.StatusBadGateway, gin.H{
+				"error":   "Failed to estimate fee with BitGo",
+				"details": err.Error(),
+			})
+			return
+		}
+		feeEstimate = estimate
+	}
+	if err := bitgo.ApplyFeeStrategy(&buildRequest, wallet.Coin, req.FeeStrategy, feeEstimate, req.FeeRate, s.maxFeeRate(wallet.Coin)); err != nil {
+		if !s.failHotTransfer(c, transferRequest, err) {
+			return
+		}
+
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Invalid fee strategy",
+			"details": err.Error(),
+		})
+		return
 	}
 
 	// Build transfer with BitGo
-	buildResponse, err := s.bitgoClient.BuildTransfer(
+	buildResponse, err := s.transferBuilder.BuildTransferIdempotent(
 		ctx,
 		wallet.BitgoWalletID,
 		wallet.Coin,
@@ -183,8 +384,14 @@ func (s *Server) createHotTransfer(c *gin.Context, walletID uuid.UUID, wallet *m
 
 	if err != nil {
 		// Update transfer request status to failed
-		transferRequest.Status = models.TransferStatusFailed
-		s.transferRequestRepo.Update(transferRequest)
+		if !s.failHotTransfer(c, transferRequest, err) {
+			return
+		}
+
+		if bitgo.IsInsufficientFunds(err) {
+			s.respondInsufficientFunds(ctx, c, wallet, req, err)
+			return
+		}
 
 		c.JSON(http.StatusBadRequest, gin.H{
 			"error":   "Failed to build transfer with BitGo",
@@ -193,21 +400,51 @@ func (s *Server) createHotTransfer(c *gin.Context, walletID uuid.UUID, wallet *m
 		return
 	}
 
-	// Update transfer request with BitGo transaction info
-	transferRequest.Status = models.TransferStatusSigned // Hot transfers go directly to signed
-	if buildResponse.Transfer != nil {
-		transferRequest.BitgoTxid = &buildResponse.Transfer.TxID
-	}
-	if buildResponse.FeeInfo != nil {
-		transferRequest.Fee = &buildResponse.FeeInfo.FeeString
-		feeRateStr := fmt.Sprintf("%d", buildResponse.FeeInfo.FeeRate)
-		transferRequest.FeeRate = &feeRateStr
+	if req.SendMax {
+		if err := applySendMaxAmount(transferRequest, buildRequest, buildResponse, recipientAddress); err != nil {
+			if !s.failHotTransfer(c, transferRequest, err) {
+				return
+			}
+
+			c.JSON(http.StatusBadGateway, gin.H{
+				"error":   "Failed to determine the send-max amount",
+				"details": err.Error(),
+			})
+			return
+		}
 	}
+	sentAmount := transferRequest.AmountString
 
-	if err := s.transferRequestRepo.Update(transferRequest); err != nil {
+	// Update transfer request with BitGo transaction info, reapplied to the latest row on a
+	// version conflict
+	updated, err := repository.UpdateWithRetry(s.transferRequestRepo, transferRequest, func(transfer *models.TransferRequest) bool {
+		if transfer.Status != models.TransferStatusDraft {
+			return false
+		}
+		transfer.Status = models.TransferStatusSigned // Hot transfers go directly to signed
+		if buildResponse.Transfer != nil {
+			transfer.BitgoTxid = &buildResponse.Transfer.TxID
+		}
+		if buildResponse.FeeInfo != nil {
+			transfer.Fee = &buildResponse.FeeInfo.FeeString
+			feeRateStr := fmt.Sprintf("%d", buildResponse.FeeInfo.FeeRate)
+			transfer.FeeRate = &feeRateStr
+		}
+		transfer.BuildInfo = newTransferBuildInfo(buildResponse, wallet.Coin)
+		transfer.AmountString = sentAmount
+		return true
+	})
+	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update transfer request"})
 		return
 	}
+	if !updated {
+		c.JSON(http.StatusConflict, gin.H{
+			"error":          "Transfer was updated by another request while it was being built",
+			"current_status": transferRequest.Status,
+		})
+		return
+	}
 
 	// Return the transfer request with BitGo transaction details
 	response := gin.H{
@@ -215,10 +452,162 @@ func (s *Server) createHotTransfer(c *gin.Context, walletID uuid.UUID, wallet *m
 		"message":  "Hot transfer created and ready for broadcast",
 		"type":     "hot",
 	}
+	if req.SendMax {
+		response["max_amount"] = req.AmountString
+	}
 
 	c.JSON(http.StatusCreated, response)
 }
 
+// failHotTransfer marks a hot transfer that couldn't be built as failed, reapplying the
+// failure to the latest row on a version conflict. It responds with 500 and returns false
+// when the failure can't be saved.
+func (s *Server) failHotTransfer(c *gin.Context, transfer *models.TransferRequest, cause error) bool {
+	now := time.Now()
+	_, err := repository.UpdateWithRetry(s.transferRequestRepo, transfer, func(transfer *models.TransferRequest) bool {
+		if transfer.Status != models.TransferStatusDraft {
+			return false
+		}
+		transfer.Status = models.TransferStatusFailed
+		transfer.FailedAt = &now
+		return true
+	})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error":   "Failed to record the failed transfer",
+			"details": fmt.Sprintf("%v; recording the failure: %v", cause, err),
+		})
+		return false
+	}
+	return true
+}
+
+// normalizeTransferAmount rewrites amount into the coin's canonical decimal form, so limits,
+// velocity checks and BitGo all see the same value. It responds with 400 and returns false
+// when the amount isn't valid for the coin.
+func (s *Server) normalizeTransferAmount(c *gin.Context, coin string, amount *string) bool {
+	normalized, err := bitgo.NormalizeAmount(coin, *amount)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid amount", "code": "invalid_amount", "details": err.Error()})
+		return false
+	}
+	*amount = normalized
+	return true
+}
+
+// rejectDustAmount responds with 400 and returns true when the amount is below the coin's
+// dust threshold, as advertised by GET /coins
+func (s *Server) rejectDustAmount(c *gin.Context, coin, amount string) bool {
+	if err := bitgo.CheckDust(coin, amount); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Amount is below the dust threshold", "code": "amount_below_dust", "details": err.Error()})
+		return true
+	}
+	return false
+}
+
+// maxFeeRate is the fee cap for builds in the coin: a gas price in wei for EVM coins, a per-kB
+// rate otherwise
+func (s *Server) maxFeeRate(coin string) int64 {
+	if bitgo.IsEVMCoin(coin) {
+		return s.config.MaxGasPrice
+	}
+	return s.config.MaxFeeRate
+}
+
+// newTransferBuildInfo summarises the transaction BitGo built so it can be kept with the
+// transfer. It returns nil when the response has neither a prebuild nor EVM details to describe.
+func newTransferBuildInfo(resp *bitgo.BuildTransferResponse, coin string) *models.TransferBuildInfo {
+	var evm *models.TransferEVMInfo
+	if bitgo.IsEVMCoin(coin) {
+		decoded, err := bitgo.DecodeEVMCoinSpecific(resp.CoinSpecific)
+		if err != nil {
+			log.Printf("[WARN] Ignoring build coinSpecific for %s: %v", coin, err)
+		}
+		evm = newTransferEVMInfo(decoded)
+	}
+
+	if resp.PrebuildTx == nil {
+		if evm == nil {
+			return nil
+		}
+		return &models.TransferBuildInfo{EVM: evm}
+	}
+
+	prebuild := resp.PrebuildTx
+	info := &models.TransferBuildInfo{
+		Size:    prebuild.FeeInfo.Size,
+		FeeRate: prebuild.FeeInfo.FeeRate,
+		EVM:     evm,
+	}
+	if resp.FeeInfo != nil {
+		if resp.FeeInfo.Size > 0 {
+			info.Size = resp.FeeInfo.Size
+		}
+		if resp.FeeInfo.FeeRate > 0 {
+			info.FeeRate = resp.FeeInfo.FeeRate
+		}
+	}
+
+	// UTXO builds describe the transaction in txInfo; account-based coins leave it empty
+	txInfo := prebuild.TxInfo
+	if unspents, ok := txInfo["unspents"].([]interface{}); ok {
+		info.InputCount = len(unspents)
+	} else {
+		info.InputCount = jsonInt(txInfo["nP2SHInputs"]) + jsonInt(txInfo["nSegwitInputs"]) + jsonInt(txInfo["nP2shP2wshInputs"])
+	}
+	info.OutputCount = jsonInt(txInfo["nOutputs"])
+	if changes, ok := txInfo["changeAddresses"].([]interface{}); ok && len(changes) > 0 {
+		info.ChangeAddress, _ = changes[0].(string)
+	}
+
+	return info
+}
+
+// newTransferEVMInfo copies decoded EVM coinSpecific fields onto the transfer's model, or
+// returns nil when there are none
+func newTransferEVMInfo(decoded *bitgo.EVMCoinSpecific) *models.TransferEVMInfo {
+	if decoded == nil {
+		return nil
+	}
+	return &models.TransferEVMInfo{
+		Nonce:                decoded.Nonce,
+		GasLimit:             decoded.GasLimit,
+		GasPrice:             decoded.GasPrice,
+		MaxFeePerGas:         decoded.MaxFeePerGas,
+		MaxPriorityFeePerGas: decoded.MaxPriorityFeePerGas,
+	}
+}
+
+// jsonInt reads a number decoded from JSON, returning 0 for anything else
+func jsonInt(v interface{}) int {
+	if n, ok := v.(float64); ok {
+		return int(n)
+	}
+	return 0
+}
+
+// respondInsufficientFunds returns 402 with the requested amount and the wallet's current
+// spendable balance, fetched fresh so clients can see how far short the wallet is
+func (s *Server) respondInsufficientFunds(ctx context.Context, c *gin.Context, wallet *models.Wallet, req CreateTransferRequest, buildErr error) {
+	response := gin.H{
+		"error":            "Insufficient funds",
+		"code":             "insufficient_funds",
+		"details":          buildErr.Error(),
+		"coin":             wallet.Coin,
+		"requested_amount": req.AmountString,
+	}
+
+	balance, err := s.bitgoClient.GetWalletBalance(ctx, wallet.BitgoWalletID, wallet.Coin)
+	if err != nil {
+		// Still report the shortfall; the balance is just unavailable
+		response["available_balance"] = nil
+	} else {
+		response["available_balance"] = balance.SpendableBalanceString
+	}
+
+	c.JSON(http.StatusPaymentRequired, response)
+}
+
 func (s *Server) listTransfers(c *gin.Context) {
 	// Get wallet ID from path
 	walletIDParam := c.Param("id")
@@ -229,33 +618,113 @@ func (s *Server) listTransfers(c *gin.Context) {
 	}
 
 	// Get pagination parameters
-	limit := 25
-	offset := 0
+	limit, offset, err := parsePagination(c, 25)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid pagination parameters", "details": err.Error()})
+		return
+	}
 
-	if l := c.Query("limit"); l != "" {
-		if parsed, err := strconv.Atoi(l); err == nil && parsed > 0 && parsed <= 500 {
-			limit = parsed
-		}
+	// Optionally filter by tag or to transfers needing attention; archived transfers are
+	// hidden unless asked for
+	tag := strings.ToLower(strings.TrimSpace(c.Query("tag")))
+	filter := repository.TransferListFilter{
+		Tag:             tag,
+		NeedsAttention:  c.Query("needs_attention") == "true",
+		IncludeArchived: c.Query("include_archived") == "true",
 	}
 
-	if o := c.Query("offset"); o != "" {
-		if parsed, err := strconv.Atoi(o); err == nil && parsed >= 0 {
-			offset = parsed
-		}
+	requestedBy, ok := s.transferVisibility(c, walletID)
+	if !ok {
+		return
 	}
+	filter.RequestedBy = requestedBy
 
-	transfers, err := s.transferRequestRepo.List(walletID, limit, offset)
+	transfers, err := s.transferRequestRepo.ListByWallet(walletID, filter, limit, offset)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to list transfers"})
 		return
 	}
 
-	c.JSON(http.StatusOK, gin.H{
-		"transfers": transfers,
-		"count":     len(transfers),
-		"limit":     limit,
-		"offset":    offset,
-	})
+	total, err := s.transferRequestRepo.CountByWallet(walletID, filter)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to count transfers"})
+		return
+	}
+
+	pagination := newPagination(limit, offset, len(transfers), total)
+	setPaginationHeaders(c, pagination)
+
+	response := gin.H{
+		"transfers":  transfers,
+		"count":      len(transfers),
+		"limit":      limit,
+		"offset":     offset,
+		"pagination": pagination,
+	}
+	if tag != "" {
+		response["tag"] = tag
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+// transferVisibility decides which of a wallet's transfers the current user may list: wallet
+// admins see all of them, other members only those they requested. It responds with 403 and
+// returns false when the user has no membership on the wallet. Requests without an
+// authenticated user (authentication disabled) are not scoped.
+func (s *Server) transferVisibility(c *gin.Context, walletID uuid.UUID) (*uuid.UUID, bool) {
+	requestedBy, member, err := s.transferScope(c, walletID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to check wallet membership", "details": err.Error()})
+		return nil, false
+	}
+	if !member {
+		c.JSON(http.StatusForbidden, gin.H{"error": "You are not a member of this wallet"})
+		return nil, false
+	}
+	return requestedBy, true
+}
+
+// transferScope returns whose transfers on the wallet the current user may see, nil meaning
+// everyone's, and whether the user may see any of them
+func (s *Server) transferScope(c *gin.Context, walletID uuid.UUID) (*uuid.UUID, bool, error) {
+	userID, ok := s.authenticatedUserID(c)
+	if !ok {
+		return nil, true, nil
+	}
+
+	membership, err := s.membershipRepo.GetByWalletAndUser(walletID, userID)
+	if err != nil {
+		return nil, false, err
+	}
+	if membership == nil {
+		return nil, false, nil
+	}
+
+	if models.WalletRole(membership.Role) == models.WalletRoleAdmin {
+		return nil, true, nil
+	}
+	return &userID, true, nil
+}
+
+// transferVisibleTo reports whether a transfer falls within a scope from transferScope
+func transferVisibleTo(transfer *models.TransferRequest, requestedBy *uuid.UUID, member bool) bool {
+	return member && (requestedBy == nil || transfer.RequestedByUserID == *requestedBy)
+}
+
+// rejectHiddenTransfer responds and returns true when the current user may not see the
+// transfer. Hidden transfers are reported as not found so their existence isn't revealed.
+func (s *Server) rejectHiddenTransfer(c *gin.Context, transfer *models.TransferRequest) bool {
+	requestedBy, member, err := s.transferScope(c, transfer.WalletID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to check wallet membership", "details": err.Error()})
+		return true
+	}
+	if !transferVisibleTo(transfer, requestedBy, member) {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Transfer not found"})
+		return true
+	}
+	return false
 }
 
 func (s *Server) getTransfer(c *gin.Context) {
@@ -276,10 +745,91 @@ func (s *Server) getTransfer(c *gin.Context) {
 		c.JSON(http.StatusNotFound, gin.H{"error": "Transfer not found"})
 		return
 	}
+	if s.rejectHiddenTransfer(c, transfer) {
+		return
+	}
 
 	c.JSON(http.StatusOK, transfer)
 }
 
+// searchTransfers finds transfers sent to a recipient address across all wallets
+func (s *Server) searchTransfers(c *gin.Context) {
+	recipient := strings.TrimSpace(c.Query("recipient"))
+	if recipient == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "recipient query parameter is required"})
+		return
+	}
+
+	prefix := c.Query("prefix") == "true"
+	includeArchived := c.Query("include_archived") == "true"
+
+	// Get pagination parameters
+	limit, offset, err := parsePagination(c, 25)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid pagination parameters", "details": err.Error()})
+		return
+	}
+
+	results, err := s.transferRequestRepo.SearchByRecipient(recipient, prefix, includeArchived, limit, offset)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to search transfers"})
+		return
+	}
+
+	total, err := s.transferRequestRepo.CountByRecipient(recipient, prefix, includeArchived)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to count transfers"})
+		return
+	}
+
+	results, hidden, err := s.visibleSearchResults(c, results)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to check wallet membership", "details": err.Error()})
+		return
+	}
+	total -= hidden
+
+	pagination := newPagination(limit, offset, len(results), total)
+	setPaginationHeaders(c, pagination)
+
+	c.JSON(http.StatusOK, gin.H{
+		"results":    results,
+		"count":      len(results),
+		"recipient":  recipient,
+		"prefix":     prefix,
+		"limit":      limit,
+		"offset":     offset,
+		"pagination": pagination,
+	})
+}
+
+// visibleSearchResults drops the results the current user may not see, returning how many
+// were dropped. Each wallet's scope is looked up once.
+func (s *Server) visibleSearchResults(c *gin.Context, results []*models.TransferSearchResult) ([]*models.TransferSearchResult, int, error) {
+	type scope struct {
+		requestedBy *uuid.UUID
+		member      bool
+	}
+	scopes := make(map[uuid.UUID]scope)
+
+	visible := make([]*models.TransferSearchResult, 0, len(results))
+	for _, result := range results {
+		walletScope, ok := scopes[result.Transfer.WalletID]
+		if !ok {
+			requestedBy, member, err := s.transferScope(c, result.Transfer.WalletID)
+			if err != nil {
+				return nil, 0, err
+			}
+			walletScope = scope{requestedBy: requestedBy, member: member}
+			scopes[result.Transfer.WalletID] = walletScope
+		}
+		if transferVisibleTo(result.Transfer, walletScope.requestedBy, walletScope.member) {
+			visible = append(visible, result)
+		}
+	}
+	return visible, len(results) - len(visible), nil
+}
+
 func (s *Server) updateTransfer(c *gin.Context) {
 	idParam := c.Param("id")
 	id, err := uuid.Parse(idParam)
@@ -334,6 +884,11 @@ func (s *Server) updateTransferStatus(c *gin.Context) {
 	c.JSON(http.StatusOK, transfer)
 }
 
+// SubmitTransferBody is the optional body of the submit endpoint
+type SubmitTransferBody struct {
+	Otp string `json:"otp,omitempty"`
+}
+
 // submitTransfer submits an approved transfer to BitGo for execution
 func (s *Server) submitTransfer(c *gin.Context) {
 	idParam := c.Param("id")
@@ -343,6 +898,15 @@ func (s *Server) submitTransfer(c *gin.Context) {
 		return
 	}
 
+	// The body is optional; it only carries an OTP for wallets that require one
+	var body SubmitTransferBody
+	if c.Request.ContentLength != 0 {
+		if err := c.ShouldBindJSON(&body); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+	}
+
 	// Get transfer request
 	transfer, err := s.transferRequestRepo.GetByID(id)
 	if err != nil {
@@ -354,6 +918,9 @@ func (s *Server) submitTransfer(c *gin.Context) {
 		c.JSON(http.StatusNotFound, gin.H{"error": "Transfer not found"})
 		return
 	}
+	if s.rejectHiddenTransfer(c, transfer) {
+		return
+	}
 
 	// Check if transfer is in a valid state for submission
 	if transfer.Status != models.TransferStatusApproved {
@@ -366,14 +933,24 @@ func (s *Server) submitTransfer(c *gin.Context) {
 
 	// Get wallet details
 	wallet, err := s.walletRepo.GetByID(transfer.WalletID)
+	if errors.Is(err, repository.ErrWalletNotFound) {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Wallet not found for transfer"})
+		return
+	}
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to get wallet"})
 		return
 	}
+	if s.rejectFrozenWallet(c, wallet) {
+		return
+	}
 
 	// Build submit request
 	submitRequest := bitgo.SubmitTransferRequest{
-		TxHex: *transfer.BitgoTxid, // Using TxHex instead of TxId
+		TxHex:      *transfer.BitgoTxid, // Using TxHex instead of TxId
+		Otp:        strings.TrimSpace(body.Otp),
+		Comment:    services.SubmitComment(transfer),
+		SequenceId: services.SubmitSequenceID(transfer),
 		// In a real implementation, you would include the signed transaction
 		// This would come from the approval process
 	}
@@ -387,12 +964,29 @@ func (s *Server) submitTransfer(c *gin.Context) {
 		submitRequest,
 	)
 
+	if bitgo.IsOTPRequired(err) {
+		// Not a failure: the transfer stays approved so it can be resubmitted with an OTP
+		c.JSON(http.StatusPreconditionRequired, gin.H{
+			"error":   "OTP required",
+			"code":    "otp_required",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	if bitgo.IsAlreadySubmitted(err) {
+		s.respondAlreadySubmitted(c, wallet, transfer, err)
+		return
+	}
+
 	if err != nil {
 		// Update transfer status to failed
-		transfer.Status = models.TransferStatusFailed
 		now := time.Now()
-		transfer.FailedAt = &now
-		s.transferRequestRepo.Update(transfer)
+		repository.UpdateWithRetry(s.transferRequestRepo, transfer, func(transfer *models.TransferRequest) bool {
+			transfer.Status = models.TransferStatusFailed
+			transfer.FailedAt = &now
+			return true
+		})
 
 		c.JSON(http.StatusBadRequest, gin.H{
 			"error":   "Failed to submit transfer to BitGo",
@@ -401,14 +995,20 @@ func (s *Server) submitTransfer(c *gin.Context) {
 		return
 	}
 
-	// Update transfer request with submission details
-	transfer.Status = models.TransferStatusBroadcast
-	transfer.BitgoTransferID = &submitResponse.Transfer.ID
-	transfer.TransactionHash = &submitResponse.Transfer.TxID
+	// Update transfer request with submission details, in the status BitGo reported. They
+	// are reapplied to the latest row if the poller saved the transfer in the meantime.
 	now := time.Now()
-	transfer.SubmittedAt = &now
-
-	if err := s.transferRequestRepo.Update(transfer); err != nil {
+	_, err = repository.UpdateWithRetry(s.transferRequestRepo, transfer, func(transfer *models.TransferRequest) bool {
+		services.ApplySubmittedStatus(transfer, submitResponse, now)
+		if submitResponse.Transfer != nil {
+			transfer.BitgoTransferID = &submitResponse.Transfer.ID
+			transfer.TransactionHash = &submitResponse.Transfer.TxID
+		} else if submitResponse.TxID != "" {
+			transfer.TransactionHash = &submitResponse.TxID
+		}
+		return true
+	})
+	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update transfer"})
 		return
 	}
@@ -421,6 +1021,64 @@ func (s *Server) submitTransfer(c *gin.Context) {
 	c.JSON(http.StatusOK, response)
 }
 
+// respondAlreadySubmitted answers a submit BitGo rejected as a duplicate, e.g. a double click
+// whose first request went through. The BitGo transfer the first submit created is looked up
+// by sequenceId and, if the first submit didn't get to record itself and the row is still
+// approved, recorded here as the submission worker does, so the transfer is polled and isn't
+// submitted again. The original submission is returned as a success. If BitGo can't find it
+// the transfer is flagged as needing attention instead of being recorded without a BitGo id.
+func (s *Server) respondAlreadySubmitted(c *gin.Context, wallet *models.Wallet, transfer *models.TransferRequest, submitErr error) {
+	log.Printf("[WARN] Transfer %s was already submitted to BitGo: %v", transfer.ID, submitErr)
+
+	submitResponse, err := services.FindAlreadySubmitted(c.Request.Context(), s.bitgoClient, wallet, transfer)
+	if err != nil {
+		log.Printf("[WARN] Failed to find already submitted transfer %s on BitGo: %v", transfer.ID, err)
+		pollError := fmt.Sprintf("already submitted to BitGo, but the transfer could not be found by sequenceId: %v", err)
+		if err := s.transferRequestRepo.RecordPollFailure(transfer.ID, transfer.PollFailures, pollError, time.Now(), true); err != nil {
+			log.Printf("[WARN] Failed to flag already submitted transfer %s: %v", transfer.ID, err)
+		}
+		c.JSON(http.StatusAccepted, gin.H{
+			"error":             "Transfer was already submitted to BitGo but could not be found there",
+			"code":              "already_submitted_unresolved",
+			"details":           err.Error(),
+			"already_submitted": true,
+		})
+		return
+	}
+
+	current, err := s.transferRequestRepo.GetByID(transfer.ID)
+	if err != nil || current == nil {
+		current = transfer
+	}
+
+	oldStatus := current.Status
+	now := time.Now()
+	updated, err := repository.UpdateWithRetry(s.transferRequestRepo, current, func(transfer *models.TransferRequest) bool {
+		if transfer.Status != models.TransferStatusApproved {
+			return false // The first submit already recorded where it went
+		}
+		services.ApplySubmittedStatus(transfer, submitResponse, now)
+		transfer.BitgoTransferID = &submitResponse.Transfer.ID
+		if submitResponse.TxID != "" {
+			transfer.TransactionHash = &submitResponse.TxID
+		}
+		return true
+	})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update transfer"})
+		return
+	}
+	if updated {
+		s.notificationSvc.SendTransferStatusNotification(current, oldStatus, current.Status)
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"transfer_request":  current,
+		"bitgo_response":    submitResponse,
+		"already_submitted": true,
+	})
+}
+
 // getTransferStatus gets the current status of a transfer from BitGo
 func (s *Server) getTransferStatus(c *gin.Context) {
 	idParam := c.Param("id")
@@ -441,10 +1099,17 @@ func (s *Server) getTransferStatus(c *gin.Context) {
 		c.JSON(http.StatusNotFound, gin.H{"error": "Transfer not found"})
 		return
 	}
+	if s.rejectHiddenTransfer(c, transfer) {
+		return
+	}
 
 	// If transfer has been submitted, get status from BitGo
 	if transfer.BitgoTransferID != nil {
 		wallet, err := s.walletRepo.GetByID(transfer.WalletID)
+		if errors.Is(err, repository.ErrWalletNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Wallet not found for transfer"})
+			return
+		}
 		if err != nil {
 			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to get wallet"})
 			return
@@ -453,41 +1118,34 @@ func (s *Server) getTransferStatus(c *gin.Context) {
 		ctx := context.Background()
 		bitgoTransfer, err := s.bitgoClient.GetTransfer(ctx, wallet.BitgoWalletID, wallet.Coin, *transfer.BitgoTransferID)
 		if err != nil {
-			c.JSON(http.StatusInternalServerError, gin.H{
-				"error":   "Failed to get transfer status from BitGo",
+			status := bitgoLookupErrorStatus(err)
+			message := "Failed to get transfer status from BitGo"
+			if status == http.StatusNotFound {
+				message = "Transfer not found on BitGo"
+			}
+			c.JSON(status, gin.H{
+				"error":   message,
 				"details": err.Error(),
 			})
 			return
 		}
 
-		// Normalize the status from BitGo
-		statusMapper := bitgo.NewStatusMapper()
-		canonicalStatus := statusMapper.NormalizeTransferStatus(bitgoTransfer.State, bitgoTransfer)
-
-		// Update our local record if status changed
-		if transfer.Status != models.TransferStatus(canonicalStatus) {
-			transfer.Status = models.TransferStatus(canonicalStatus)
-
-			// Update completion timestamps based on status
-			now := time.Now()
-			switch canonicalStatus {
-			case "confirmed":
-				if transfer.CompletedAt == nil {
-					transfer.CompletedAt = &now
-				}
-			case "failed":
-				if transfer.FailedAt == nil {
-					transfer.FailedAt = &now
-				}
-			}
-
-			s.transferRequestRepo.Update(transfer)
+		// Update our local record if status changed, on top of any concurrent update
+		var canonicalStatus bitgo.CanonicalTransferStatus
+		_, err = repository.UpdateWithRetry(s.transferRequestRepo, transfer, func(transfer *models.TransferRequest) bool {
+			var changed bool
+			canonicalStatus, changed = applyBitGoTransferStatus(transfer, bitgoTransfer)
+			return changed
+		})
+		if err != nil {
+			log.Printf("[WARN] Failed to save BitGo status for transfer %s: %v", transfer.ID, err)
 		}
 
 		response := gin.H{
 			"transfer_request": transfer,
 			"bitgo_transfer":   bitgoTransfer,
 			"canonical_status": canonicalStatus,
+			"outputs":          bitgo.CategorizeEntries(bitgoTransfer.Entries),
 		}
 
 		c.JSON(http.StatusOK, response)
@@ -502,13 +1160,86 @@ func (s *Server) getTransferStatus(c *gin.Context) {
 	})
 }
 
+// bitgoLookupErrorStatus maps a failed BitGo lookup to our response status: 404 when BitGo has
+// no record of the id, 504 when the request timed out and 502 for anything else, so clients can
+// tell "never existed" from "temporarily unavailable"
+func bitgoLookupErrorStatus(err error) int {
+	switch {
+	case bitgo.IsNotFound(err):
+		return http.StatusNotFound
+	case bitgo.IsTimeout(err):
+		return http.StatusGatewayTimeout
+	default:
+		return http.StatusBadGateway
+	}
+}
+
+// applyBitGoTransferStatus normalizes BitGo's state for a transfer and copies it onto the local
+// record, setting completion timestamps. It reports the canonical status and whether it changed.
+func applyBitGoTransferStatus(transfer *models.TransferRequest, bitgoTransfer *bitgo.Transfer) (bitgo.CanonicalTransferStatus, bool) {
+	statusMapper := bitgo.NewStatusMapper()
+	canonicalStatus := statusMapper.NormalizeTransferStatus(bitgoTransfer.State, bitgoTransfer)
+
+	if transfer.Status == models.TransferStatus(canonicalStatus) {
+		return canonicalStatus, false
+	}
+	transfer.Status = models.TransferStatus(canonicalStatus)
+
+	// Update completion timestamps based on status
+	now := time.Now()
+	switch canonicalStatus {
+	case "confirmed":
+		if transfer.CompletedAt == nil {
+			transfer.CompletedAt = &now
+		}
+	case "failed":
+		if transfer.FailedAt == nil {
+			transfer.FailedAt = &now
+		}
+	}
+
+	return canonicalStatus, true
+}
+
 // createColdTransfer creates a new cold storage transfer request
 func (s *Server) createColdTransfer(c *gin.Context) {
+	if err := acceptCamelCaseFields(c); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Failed to read request body", "details": err.Error()})
+		return
+	}
+
 	var req services.ColdTransferRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
 		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 		return
 	}
+	if !s.normalizeTransferAmount(c, req.Coin, &req.AmountString) {
+		return
+	}
+
+	if s.rejectNetworkMismatch(c, req.Coin, req.RecipientAddress) {
+		return
+	}
+	if s.rejectInvalidAddress(c, req.Coin, req.RecipientAddress) {
+		return
+	}
+	if s.rejectBlockedAddress(c, req.RecipientAddress) {
+		return
+	}
+	if s.rejectSelfSend(c, req.WalletID, req.RecipientAddress, req.AllowSelfSend) {
+		return
+	}
+
+	wallet := s.loadTransferWallet(c, req.WalletID)
+	if wallet == nil {
+		return
+	}
+	if s.rejectFrozenWallet(c, wallet) {
+		return
+	}
+	if s.rejectVelocitySpike(c, wallet, req.AmountString) {
+		return
+	}
 
 	// Get current user ID
 	userID := s.getCurrentUserID(c)
@@ -523,6 +1254,7 @@ func (s *Server) createColdTransfer(c *gin.Context) {
 		})
 		return
 	}
+	s.recordOutflow(transfer)
 
 	c.JSON(http.StatusCreated, gin.H{
 		"transfer_request": transfer,
@@ -557,6 +1289,10 @@ func (s *Server) updateOfflineWorkflowState(c *gin.Context) {
 	var req struct {
 		State services.OfflineWorkflowState `json:"state" binding:"required"`
 		Notes string                        `json:"notes"`
+
+		// Required when moving to awaiting_hsm
+		Operator string `json:"operator"`
+		HSMID    string `json:"hsm_id"`
 	}
 
 	if err := c.ShouldBindJSON(&req); err != nil {
@@ -565,7 +1301,22 @@ func (s *Server) updateOfflineWorkflowState(c *gin.Context) {
 	}
 
 	ctx := context.Background()
-	if err := s.coldWalletSvc.UpdateOfflineWorkflowState(ctx, id, req.State, req.Notes); err != nil {
+	hsm := &services.HSMSessionRequest{Operator: req.Operator, HSMID: req.HSMID}
+	if err := s.coldWalletSvc.UpdateOfflineWorkflowState(ctx, id, req.State, req.Notes, hsm); err != nil {
+		if errors.Is(err, services.ErrInvalidOfflineTransition) {
+			c.JSON(http.StatusConflict, gin.H{
+				"error":   "Invalid offline workflow transition",
+				"details": err.Error(),
+			})
+			return
+		}
+		if errors.Is(err, repository.ErrTransferVersionConflict) {
+			c.JSON(http.StatusConflict, gin.H{
+				"error":   "Transfer was updated by another request; reload it and try again",
+				"details": err.Error(),
+			})
+			return
+		}
 		c.JSON(http.StatusBadRequest, gin.H{
 			"error":   "Failed to update offline workflow state",
 			"details": err.Error(),
@@ -580,22 +1331,54 @@ func (s *Server) updateOfflineWorkflowState(c *gin.Context) {
 	})
 }
 
-// getColdTransfersAdminQueue gets cold transfers for admin review
-func (s *Server) getColdTransfersAdminQueue(c *gin.Context) {
-	// Get pagination parameters
-	limit := 50
-	offset := 0
+// recordOfflineSignature records the signed transaction hex produced at the HSM for a cold transfer
+func (s *Server) recordOfflineSignature(c *gin.Context) {
+	idParam := c.Param("id")
+	id, err := uuid.Parse(idParam)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid transfer ID"})
+		return
+	}
 
-	if l := c.Query("limit"); l != "" {
-		if parsed, err := strconv.Atoi(l); err == nil && parsed > 0 && parsed <= 500 {
-			limit = parsed
-		}
+	var req struct {
+		SignedTxHex string `json:"signed_tx_hex" binding:"required"`
+		SignedBy    string `json:"signed_by"`
+	}
+
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
 	}
 
-	if o := c.Query("offset"); o != "" {
-		if parsed, err := strconv.Atoi(o); err == nil && parsed >= 0 {
-			offset = parsed
+	ctx := context.Background()
+	transfer, err := s.coldWalletSvc.RecordOfflineSignature(ctx, id, req.SignedTxHex, req.SignedBy)
+	if err != nil {
+		status := http.StatusBadRequest
+		if errors.Is(err, services.ErrInvalidOfflineTransition) || errors.Is(err, services.ErrHSMSessionExpired) ||
+			errors.Is(err, repository.ErrTransferVersionConflict) {
+			status = http.StatusConflict
 		}
+		c.JSON(status, gin.H{
+			"error":   "Failed to record offline signature",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message":  "Offline signature recorded successfully",
+		"state":    services.OfflineStateReadyToExecute,
+		"transfer": transfer,
+	})
+}
+
+// getColdTransfersAdminQueue gets cold transfers for admin review
+func (s *Server) getColdTransfersAdminQueue(c *gin.Context) {
+	// Get pagination parameters
+	limit, offset, err := parsePagination(c, 50)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid pagination parameters", "details": err.Error()})
+		return
 	}
 
 	// Get cold transfers that need attention
@@ -605,32 +1388,30 @@ func (s *Server) getColdTransfersAdminQueue(c *gin.Context) {
 		models.TransferStatusApproved,
 	}
 
-	transfers, err := s.transferRequestRepo.GetTransfersByStatuses(coldStatuses, limit)
+	coldTransfers, err := s.transferRequestRepo.ListByTypeAndStatuses(models.WalletTypeCold, coldStatuses, limit, offset)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to get cold transfers"})
 		return
 	}
 
-	// Filter only cold transfers
-	coldTransfers := make([]*models.TransferRequest, 0)
-	for _, transfer := range transfers {
-		if transfer.TransferType == models.WalletTypeCold {
-			coldTransfers = append(coldTransfers, transfer)
-		}
+	total, err := s.transferRequestRepo.CountByTypeAndStatuses(models.WalletTypeCold, coldStatuses)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to count cold transfers"})
+		return
 	}
 
 	// Get SLA status for context
 	ctx := context.Background()
 	slaStatus, _ := s.coldWalletSvc.GetColdTransfersSLAStatus(ctx)
 
+	pagination := newPagination(limit, offset, len(coldTransfers), total)
+	setPaginationHeaders(c, pagination)
+
 	response := gin.H{
 		"transfers":   coldTransfers,
 		"count":       len(coldTransfers),
 		"sla_summary": slaStatus,
-		"pagination": gin.H{
-			"limit":  limit,
-			"offset": offset,
-		},
+		"pagination":  pagination,
 	}
 
 	c.JSON(http.StatusOK, response)
@@ -640,6 +1421,7 @@ func (s *Server) getColdTransfersAdminQueue(c *gin.Context) {
 func (s *Server) verifyAddress(c *gin.Context) {
 	var req struct {
 		Address string `json:"address" binding:"required"`
+		Coin    string `json:"coin"` // Optional; checks the address against this coin's format
 	}
 
 	if err := c.ShouldBindJSON(&req); err != nil {
@@ -649,7 +1431,7 @@ func (s *Server) verifyAddress(c *gin.Context) {
 
 	// Use BitGo client to verify address format
 	ctx := context.Background()
-	isValid, err := s.bitgoClient.ValidateAddress(ctx, req.Address)
+	isValid, err := s.bitgoClient.ValidateAddress(ctx, req.Coin, req.Address)
 	if err != nil {
 		c.JSON(http.StatusOK, gin.H{
 			"valid": false,
@@ -681,15 +1463,87 @@ func (s *Server) getApprovers(c *gin.Context) {
 	})
 }
 
+// getWalletApprovers lists the users who can approve a wallet's transfers, resolved from
+// its memberships and their user roles
+func (s *Server) getWalletApprovers(c *gin.Context) {
+	walletID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid wallet ID"})
+		return
+	}
+
+	if _, err := s.walletRepo.GetByID(walletID); errors.Is(err, repository.ErrWalletNotFound) {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Wallet not found"})
+		return
+	} else if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to get wallet"})
+		return
+	}
+
+	approvers, err := s.membershipRepo.ListApprovers(walletID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to list approvers", "details": err.Error()})
+		return
+	}
+	if approvers == nil {
+		approvers = []*models.WalletApprover{}
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"wallet_id": walletID,
+		"approvers": approvers,
+		"count":     len(approvers),
+	})
+}
+
+// getFeatureFlags returns the feature flags currently in effect
+func (s *Server) getFeatureFlags(c *gin.Context) {
+	c.JSON(http.StatusOK, gin.H{
+		"feature_flags": s.featureFlags.Get(),
+	})
+}
+
 // WARM TRANSFER ENDPOINTS
 
 // createWarmTransfer creates a new warm storage transfer request
 func (s *Server) createWarmTransfer(c *gin.Context) {
+	if err := acceptCamelCaseFields(c); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Failed to read request body", "details": err.Error()})
+		return
+	}
+
 	var req services.WarmTransferRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
 		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 		return
 	}
+	if !s.normalizeTransferAmount(c, req.Coin, &req.AmountString) {
+		return
+	}
+
+	if s.rejectNetworkMismatch(c, req.Coin, req.RecipientAddress) {
+		return
+	}
+	if s.rejectInvalidAddress(c, req.Coin, req.RecipientAddress) {
+		return
+	}
+	if s.rejectBlockedAddress(c, req.RecipientAddress) {
+		return
+	}
+	if s.rejectSelfSend(c, req.WalletID, req.RecipientAddress, req.AllowSelfSend) {
+		return
+	}
+
+	wallet := s.loadTransferWallet(c, req.WalletID)
+	if wallet == nil {
+		return
+	}
+	if s.rejectFrozenWallet(c, wallet) {
+		return
+	}
+	if s.rejectVelocitySpike(c, wallet, req.AmountString) {
+		return
+	}
 
 	// Get user ID from context (this would come from JWT token in real implementation)
 	userID := uuid.New() // Mock user ID
@@ -700,6 +1554,7 @@ func (s *Server) createWarmTransfer(c *gin.Context) {
 		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 		return
 	}
+	s.recordOutflow(transfer)
 
 	c.JSON(http.StatusCreated, gin.H{
 		"transfer": transfer,
@@ -740,54 +1595,94 @@ func (s *Server) getWarmTransfersAnalytics(c *gin.Context) {
 		models.TransferStatusCompleted,
 	}
 
-	transfers, err := s.transferRequestRepo.GetTransfersByStatuses(warmStatuses, 1000)
+	// Volume is only meaningful per coin; a common total needs prices
+	aggregator := services.NewTransferAnalyticsAggregator()
+	err = s.transferRequestRepo.StreamByTypeAndStatuses(models.WalletTypeWarm, warmStatuses, func(transfer *models.TransferRequest) error {
+		aggregator.Add(transfer)
+		return nil
+	})
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to get transfers"})
 		return
 	}
+	report := aggregator.Report()
+	volumeByCoin := make(map[string]string, len(report.Coins))
+	for coin, coinAnalytics := range report.Coins {
+		volumeByCoin[coin] = coinAnalytics.Volume
+	}
 
-	// Filter warm transfers
-	warmTransfers := make([]*models.TransferRequest, 0)
-	for _, transfer := range transfers {
-		if transfer.TransferType == models.WalletTypeWarm {
-			warmTransfers = append(warmTransfers, transfer)
-		}
+	analytics := map[string]interface{}{
+		"sla_status":           slaStatus,
+		"volume_by_coin":       volumeByCoin,
+		"avg_processing_hours": report.AvgProcessingHours,
+		"status_breakdown":     report.StatusBreakdown,
+		"origin_breakdown":     report.OriginBreakdown,
+		"auto_rate":            report.AutoRate,
+		"transfer_count":       report.TransferCount,
+	}
+
+	switch convert := strings.ToLower(c.Query("convert")); convert {
+	case "":
+	case "usd":
+		analytics["fiat_volume"] = services.ConvertVolumeToUSD(ctx, s.priceOracle, report)
+	default:
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid convert. Must be 'usd'"})
+		return
 	}
 
-	// Calculate additional metrics
-	totalVolume := 0.0
-	avgProcessingTime := 0.0
-	statusBreakdown := make(map[models.TransferStatus]int)
+	c.JSON(http.StatusOK, analytics)
+}
 
-	for _, transfer := range warmTransfers {
-		// Parse amount for volume calculation
-		if amount, err := parseAmountFloat(transfer.AmountString); err == nil {
-			totalVolume += amount
-		}
+// getTransferAnalytics gets volume, processing time and success metrics grouped by coin
+// for all transfers or those of a single wallet type
+func (s *Server) getTransferAnalytics(c *gin.Context) {
+	transferType := c.DefaultQuery("type", "all")
 
-		// Status breakdown
-		statusBreakdown[transfer.Status]++
+	allStatuses := []models.TransferStatus{
+		models.TransferStatusDraft,
+		models.TransferStatusSubmitted,
+		models.TransferStatusPendingApproval,
+		models.TransferStatusApproved,
+		models.TransferStatusSigned,
+		models.TransferStatusBroadcast,
+		models.TransferStatusConfirmed,
+		models.TransferStatusCompleted,
+		models.TransferStatusFailed,
+		models.TransferStatusRejected,
+		models.TransferStatusCancelled,
+		models.TransferStatusExpired,
+	}
 
-		// Processing time calculation (simplified)
-		if transfer.Status == models.TransferStatusCompleted && !transfer.UpdatedAt.IsZero() {
-			processingTime := transfer.UpdatedAt.Sub(transfer.CreatedAt).Hours()
-			avgProcessingTime += processingTime
-		}
+	var streamType models.WalletType
+	switch models.WalletType(transferType) {
+	case models.WalletTypeHot, models.WalletTypeWarm, models.WalletTypeCold:
+		streamType = models.WalletType(transferType)
+	case "all":
+	default:
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid type. Must be 'all', 'hot', 'warm', or 'cold'"})
+		return
 	}
 
-	if len(warmTransfers) > 0 {
-		avgProcessingTime = avgProcessingTime / float64(len(warmTransfers))
+	aggregator := services.NewTransferAnalyticsAggregator()
+	err := s.transferRequestRepo.StreamByTypeAndStatuses(streamType, allStatuses, func(transfer *models.TransferRequest) error {
+		aggregator.Add(transfer)
+		return nil
+	})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to get transfers", "details": err.Error()})
+		return
 	}
 
-	analytics := map[string]interface{}{
-		"sla_status":           slaStatus,
-		"total_volume":         totalVolume,
-		"avg_processing_hours": avgProcessingTime,
-		"status_breakdown":     statusBreakdown,
-		"transfer_count":       len(warmTransfers),
+	rejectedFieldsType := models.WalletType(transferType)
+	if transferType == "all" {
+		rejectedFieldsType = ""
 	}
 
-	c.JSON(http.StatusOK, analytics)
+	c.JSON(http.StatusOK, gin.H{
+		"type":                transferType,
+		"analytics":           aggregator.Report(),
+		"top_rejected_fields": s.validationMetrics.TopRejectedFields(rejectedFieldsType, 10),
+	})
 }
 
 // processWarmTransfer manually processes a warm transfer (for admin override)
@@ -810,7 +1705,7 @@ func (s *Server) processWarmTransfer(c *gin.Context) {
 
 	// Get the transfer
 	transfer, err := s.transferRequestRepo.GetByID(transferID)
-	if err != nil {
+	if err != nil || transfer == nil {
 		c.JSON(http.StatusNotFound, gin.H{"error": "Transfer not found"})
 		return
 	}
@@ -821,19 +1716,28 @@ func (s *Server) processWarmTransfer(c *gin.Context) {
 	}
 
 	switch req.Action {
-	case "approve":
-		transfer.Status = models.TransferStatusApproved
-		transfer.ReceivedApprovals = transfer.RequiredApprovals
-	case "reject":
-		transfer.Status = models.TransferStatusRejected
+	case approvalDecisionApprove, approvalDecisionReject:
+		s.decideWarmTransfer(c, transfer, req.Action == approvalDecisionApprove, req.Notes)
+		return
 	case "process":
-		// Trigger automated processing
-		if transfer.Status == models.TransferStatusApproved {
-			// This would trigger the actual BitGo processing
-			// For now, we'll just update the status
+		// Trigger automated processing. This would trigger the actual BitGo processing; for
+		// now we just update the status, on the latest row if it changed since it was read.
+		processed, err := repository.UpdateWithRetry(s.transferRequestRepo, transfer, func(transfer *models.TransferRequest) bool {
+			if transfer.Status != models.TransferStatusApproved {
+				return false
+			}
 			transfer.Status = models.TransferStatusSigned
-		} else {
-			c.JSON(http.StatusBadRequest, gin.H{"error": "Transfer must be approved before processing"})
+			return true
+		})
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update transfer"})
+			return
+		}
+		if !processed {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"error":          "Transfer must be approved before processing",
+				"current_status": transfer.Status,
+			})
 			return
 		}
 	default:
@@ -841,11 +1745,6 @@ func (s *Server) processWarmTransfer(c *gin.Context) {
 		return
 	}
 
-	if err := s.transferRequestRepo.Update(transfer); err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update transfer"})
-		return
-	}
-
 	c.JSON(http.StatusOK, gin.H{
 		"transfer": transfer,
 		"message":  fmt.Sprintf("Transfer %s successfully", req.Action),
@@ -853,9 +1752,31 @@ func (s *Server) processWarmTransfer(c *gin.Context) {
 	})
 }
 
-// Helper function to parse amount as float
-func parseAmountFloat(amountStr string) (float64, error) {
-	var amount float64
-	_, err := fmt.Sscanf(amountStr, "%f", &amount)
-	return amount, err
+// decideWarmTransfer applies one approver's decision the same way a batch approval does
+func (s *Server) decideWarmTransfer(c *gin.Context, transfer *models.TransferRequest, approve bool, notes string) {
+	decision := approvalDecisionReject
+	if approve {
+		decision = approvalDecisionApprove
+	}
+
+	result := s.decideApproval(c, transfer, decision, notes)
+	if result.Status == "failed" {
+		status, ok := approvalFailureStatuses[result.Code]
+		if !ok {
+			status = http.StatusInternalServerError
+		}
+		c.JSON(status, gin.H{"error": "Failed to record approval decision", "details": result.Error, "code": result.Code})
+		return
+	}
+
+	updated := result.Transfer
+	message := fmt.Sprintf("Approval recorded (%d of %d)", updated.ReceivedApprovals, updated.RequiredApprovals)
+	if !approve {
+		message = "Transfer rejected successfully"
+	}
+	c.JSON(http.StatusOK, gin.H{
+		"transfer": updated,
+		"message":  message,
+		"notes":    notes,
+	})
 }
@@ -0,0 +1,703 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"bitgo-wallets-api/internal/bitgo"
+	"bitgo-wallets-api/internal/config"
+	"bitgo-wallets-api/internal/models"
+	"bitgo-wallets-api/internal/repository"
+	"bitgo-wallets-api/internal/services"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// newSubmitTestServer returns a server with an approved transfer ready to submit from a
+// warm BTC wallet, routed for POST /transfers/:id/submit
+func newSubmitTestServer(client bitgo.BitGoAPI) (*Server, *gin.Engine, *models.Wallet, *models.TransferRequest) {
+	gin.SetMode(gin.TestMode)
+
+	wallet := &models.Wallet{ID: uuid.New(), BitgoWalletID: "bitgo-wallet-1", Coin: "btc", WalletType: models.WalletTypeWarm}
+	txHex := "signed-tx-hex"
+	transfer := &models.TransferRequest{
+		ID:               uuid.New(),
+		WalletID:         wallet.ID,
+		RecipientAddress: testBTCAddress,
+		AmountString:     "0.1",
+		Coin:             "btc",
+		TransferType:     models.WalletTypeWarm,
+		Status:           models.TransferStatusApproved,
+		BitgoTxid:        &txHex,
+		Version:          1,
+	}
+
+	server := &Server{
+		bitgoClient:         client,
+		walletRepo:          newMemWalletRepo(wallet),
+		transferRequestRepo: newMemTransferRepo(transfer),
+		notificationSvc:     nopNotifier{},
+	}
+	router := gin.New()
+	router.POST("/transfers/:id/submit", server.submitTransfer)
+	return server, router, wallet, transfer
+}
+
+func TestDuplicateSubmitReturnsOriginalTxid(t *testing.T) {
+	client := bitgo.NewSimulatedClient(bitgo.SimulationConfig{}, &SimpleLogger{})
+	server, router, wallet, transfer := newSubmitTestServer(client)
+
+	// A first submit BitGo accepted but whose response never made it back
+	original, err := client.SubmitTransfer(context.Background(), wallet.BitgoWalletID, wallet.Coin, bitgo.SubmitTransferRequest{
+		TxHex:      *transfer.BitgoTxid,
+		SequenceId: transfer.ID.String(),
+	})
+	if err != nil {
+		t.Fatalf("first SubmitTransfer() error = %v", err)
+	}
+
+	recorder := httptest.NewRecorder()
+	router.ServeHTTP(recorder, httptest.NewRequest(http.MethodPost, "/transfers/"+transfer.ID.String()+"/submit", nil))
+	if recorder.Code != http.StatusOK {
+		t.Fatalf("status = %d, want %d: %s", recorder.Code, http.StatusOK, recorder.Body.String())
+	}
+
+	var body struct {
+		AlreadySubmitted bool                         `json:"already_submitted"`
+		BitGoResponse    bitgo.SubmitTransferResponse `json:"bitgo_response"`
+	}
+	if err := json.Unmarshal(recorder.Body.Bytes(), &body); err != nil {
+		t.Fatalf("decode response: %v", err)
+	}
+	if !body.AlreadySubmitted {
+		t.Error("already_submitted = false, want true")
+	}
+	if body.BitGoResponse.TxID != original.TxID {
+		t.Errorf("txid = %q, want the original %q", body.BitGoResponse.TxID, original.TxID)
+	}
+
+	stored, _ := server.transferRequestRepo.GetByID(transfer.ID)
+	if stored.Status != models.TransferStatusBroadcast {
+		t.Errorf("status = %s, want %s", stored.Status, models.TransferStatusBroadcast)
+	}
+	if stored.BitgoTransferID == nil || *stored.BitgoTransferID != original.Transfer.ID {
+		t.Errorf("BitgoTransferID = %v, want %q", stored.BitgoTransferID, original.Transfer.ID)
+	}
+	if stored.TransactionHash == nil || *stored.TransactionHash != original.TxID {
+		t.Errorf("TransactionHash = %v, want %q", stored.TransactionHash, original.TxID)
+	}
+}
+
+// duplicateSubmitClient reports every submit as a duplicate BitGo has no record of
+type duplicateSubmitClient struct {
+	*bitgo.SimulatedClient
+}
+
+func (duplicateSubmitClient) SubmitTransfer(ctx context.Context, walletID, coin string, req bitgo.SubmitTransferRequest) (*bitgo.SubmitTransferResponse, error) {
+	return nil, bitgo.APIError{StatusCode: http.StatusBadRequest, Name: "TransactionAlreadySubmitted"}
+}
+
+func TestDuplicateSubmitNotFoundOnBitGoNeedsAttention(t *testing.T) {
+	client := duplicateSubmitClient{bitgo.NewSimulatedClient(bitgo.SimulationConfig{}, &SimpleLogger{})}
+	server, router, _, transfer := newSubmitTestServer(client)
+
+	recorder := httptest.NewRecorder()
+	router.ServeHTTP(recorder, httptest.NewRequest(http.MethodPost, "/transfers/"+transfer.ID.String()+"/submit", nil))
+	if recorder.Code != http.StatusAccepted {
+		t.Fatalf("status = %d, want %d: %s", recorder.Code, http.StatusAccepted, recorder.Body.String())
+	}
+
+	stored, _ := server.transferRequestRepo.GetByID(transfer.ID)
+	if stored.Status != models.TransferStatusApproved {
+		t.Errorf("status = %s, want it left %s", stored.Status, models.TransferStatusApproved)
+	}
+	if stored.NeedsAttentionAt == nil {
+		t.Error("NeedsAttentionAt = nil, want the transfer flagged")
+	}
+	if stored.BitgoTransferID != nil {
+		t.Errorf("BitgoTransferID = %q, want none", *stored.BitgoTransferID)
+	}
+}
+
+// otpClient requires the OTP 123456 on every submit and records the OTPs it was sent
+type otpClient struct {
+	*bitgo.SimulatedClient
+	otps []string
+}
+
+func (c *otpClient) SubmitTransfer(ctx context.Context, walletID, coin string, req bitgo.SubmitTransferRequest) (*bitgo.SubmitTransferResponse, error) {
+	c.otps = append(c.otps, req.Otp)
+	if req.Otp != "123456" {
+		return nil, bitgo.APIError{StatusCode: http.StatusUnauthorized, ErrorMsg: "needs unlock", NeedsOTP: true}
+	}
+	return c.SimulatedClient.SubmitTransfer(ctx, walletID, coin, req)
+}
+
+func TestSubmitForwardsOTP(t *testing.T) {
+	tests := []struct {
+		name       string
+		body       interface{}
+		wantOTP    string
+		wantCode   int
+		wantStatus models.TransferStatus
+	}{
+		{name: "otp forwarded", body: SubmitTransferBody{Otp: " 123456 "}, wantOTP: "123456", wantCode: http.StatusOK, wantStatus: models.TransferStatusBroadcast},
+		{name: "otp missing", wantCode: http.StatusPreconditionRequired, wantStatus: models.TransferStatusApproved},
+		{name: "otp wrong", body: SubmitTransferBody{Otp: "000000"}, wantOTP: "000000", wantCode: http.StatusPreconditionRequired, wantStatus: models.TransferStatusApproved},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client := &otpClient{SimulatedClient: bitgo.NewSimulatedClient(bitgo.SimulationConfig{}, &SimpleLogger{})}
+			server, router, _, transfer := newSubmitTestServer(client)
+
+			var body io.Reader
+			if tt.body != nil {
+				body = jsonBody(t, tt.body)
+			}
+			recorder := httptest.NewRecorder()
+			router.ServeHTTP(recorder, httptest.NewRequest(http.MethodPost, "/transfers/"+transfer.ID.String()+"/submit", body))
+			if recorder.Code != tt.wantCode {
+				t.Fatalf("status = %d, want %d: %s", recorder.Code, tt.wantCode, recorder.Body.String())
+			}
+			if len(client.otps) != 1 || client.otps[0] != tt.wantOTP {
+				t.Errorf("BitGo was sent OTPs %q, want [%q]", client.otps, tt.wantOTP)
+			}
+
+			if tt.wantCode == http.StatusPreconditionRequired {
+				var response struct {
+					Code string `json:"code"`
+				}
+				decodeJSON(t, recorder, &response)
+				if response.Code != "otp_required" {
+					t.Errorf("code = %q, want otp_required", response.Code)
+				}
+			}
+
+			// An OTP challenge isn't a failure, so the transfer can be resubmitted
+			stored, _ := server.transferRequestRepo.GetByID(transfer.ID)
+			if stored.Status != tt.wantStatus {
+				t.Errorf("transfer status = %s, want %s", stored.Status, tt.wantStatus)
+			}
+		})
+	}
+}
+
+// buildRecordingClient records the build requests sent to BitGo
+type buildRecordingClient struct {
+	*bitgo.SimulatedClient
+	builds []bitgo.BuildTransferRequest
+}
+
+func (c *buildRecordingClient) BuildTransfer(ctx context.Context, walletID, coin string, req bitgo.BuildTransferRequest) (*bitgo.BuildTransferResponse, error) {
+	c.builds = append(c.builds, req)
+	return c.SimulatedClient.BuildTransfer(ctx, walletID, coin, req)
+}
+
+func TestHotBuildCarriesMinConfirms(t *testing.T) {
+	wallet := &models.Wallet{ID: uuid.New(), BitgoWalletID: "bitgo-btc-1", Coin: "btc", WalletType: models.WalletTypeHot, SpendableBalanceString: "10", IsActive: true}
+	client := &buildRecordingClient{SimulatedClient: bitgo.NewSimulatedClient(bitgo.SimulationConfig{}, &SimpleLogger{})}
+	_, router, _ := newHotTransferTestServer(wallet, client)
+
+	recorder := postTransfer(t, router, wallet, CreateTransferRequest{RecipientAddress: testBTCAddress, AmountString: "0.1", Coin: "btc", TransferType: models.WalletTypeHot})
+	if recorder.Code != http.StatusCreated {
+		t.Fatalf("status = %d, want %d: %s", recorder.Code, http.StatusCreated, recorder.Body.String())
+	}
+	if len(client.builds) != 1 {
+		t.Fatalf("sent %d builds, want 1", len(client.builds))
+	}
+
+	// Check the body BitGo receives
+	encoded, err := json.Marshal(client.builds[0])
+	if err != nil {
+		t.Fatalf("encode build: %v", err)
+	}
+	var body map[string]interface{}
+	if err := json.Unmarshal(encoded, &body); err != nil {
+		t.Fatalf("decode build: %v", err)
+	}
+	if body["minConfirms"] != float64(1) {
+		t.Errorf("minConfirms = %v, want 1", body["minConfirms"])
+	}
+	if _, ok := body["enforceMinConfirmsForChange"]; ok {
+		t.Errorf("enforceMinConfirmsForChange = %v, want it left to BitGo", body["enforceMinConfirmsForChange"])
+	}
+}
+
+// newHotTransferTestServer returns a server that creates transfers on the hot wallet through
+// the client, routed for POST /wallets/:id/transfers
+func newHotTransferTestServer(wallet *models.Wallet, client bitgo.BitGoAPI) (*Server, *gin.Engine, *memTransferRepo) {
+	gin.SetMode(gin.TestMode)
+
+	logger := &SimpleLogger{}
+	walletRepo := newMemWalletRepo(wallet)
+	transferRepo := newMemTransferRepo()
+	server := &Server{
+		config:              &config.Config{HotMinConfirms: 1, MaxFeeRate: 1000000, MaxGasPrice: 1000000000000},
+		bitgoClient:         client,
+		walletRepo:          walletRepo,
+		walletAddressRepo:   memWalletAddressRepo{},
+		blockedAddressRepo:  newMemBlockedAddressRepo(),
+		transferRequestRepo: transferRepo,
+		transferBuilder:     bitgo.NewIdempotentTransferBuilder(client, bitgo.NewIdempotencyService(logger, time.Hour)),
+		balanceReserves:     services.NewBalanceReservations(transferRepo, services.DefaultEstimatedFees(), logger),
+		velocityGuard:       services.NewVelocityGuard(services.DefaultVelocityGuardConfig(), walletRepo, transferRepo, nopNotifier{}, logger),
+	}
+	router := gin.New()
+	router.POST("/wallets/:id/transfers", server.createTransfer)
+	return server, router, transferRepo
+}
+
+// postTransfer sends the create-transfer request for the wallet
+func postTransfer(t *testing.T, router *gin.Engine, wallet *models.Wallet, body interface{}) *httptest.ResponseRecorder {
+	t.Helper()
+	recorder := httptest.NewRecorder()
+	router.ServeHTTP(recorder, httptest.NewRequest(http.MethodPost, "/wallets/"+wallet.ID.String()+"/transfers", jsonBody(t, body)))
+	return recorder
+}
+
+func TestCreateTransferNormalizesTags(t *testing.T) {
+	wallet := &models.Wallet{ID: uuid.New(), BitgoWalletID: "bitgo-btc-1", Coin: "btc", WalletType: models.WalletTypeHot, SpendableBalanceString: "10", IsActive: true}
+	client := bitgo.NewSimulatedClient(bitgo.SimulationConfig{}, &SimpleLogger{})
+
+	t.Run("stored lowercased without duplicates", func(t *testing.T) {
+		_, router, repo := newHotTransferTestServer(wallet, client)
+		recorder := postTransfer(t, router, wallet, CreateTransferRequest{
+			RecipientAddress: testBTCAddress,
+			AmountString:     "0.01",
+			Coin:             "btc",
+			TransferType:     models.WalletTypeHot,
+			Tags:             []string{" Payroll ", "payroll", "Q3"},
+		})
+		if recorder.Code != http.StatusCreated {
+			t.Fatalf("status = %d, want %d: %s", recorder.Code, http.StatusCreated, recorder.Body.String())
+		}
+
+		transfers, err := repo.ListByWallet(wallet.ID, repository.TransferListFilter{}, 0, 0)
+		if err != nil || len(transfers) != 1 {
+			t.Fatalf("ListByWallet() = %d transfers, %v; want 1", len(transfers), err)
+		}
+		if tags := transfers[0].Tags; len(tags) != 2 || tags[0] != "payroll" || tags[1] != "q3" {
+			t.Errorf("stored tags = %v, want [payroll q3]", tags)
+		}
+	})
+
+	t.Run("invalid tag is rejected", func(t *testing.T) {
+		_, router, repo := newHotTransferTestServer(wallet, client)
+		recorder := postTransfer(t, router, wallet, CreateTransferRequest{
+			RecipientAddress: testBTCAddress,
+			AmountString:     "0.01",
+			Coin:             "btc",
+			TransferType:     models.WalletTypeHot,
+			Tags:             []string{"pay roll"},
+		})
+		if recorder.Code != http.StatusBadRequest {
+			t.Fatalf("status = %d, want %d: %s", recorder.Code, http.StatusBadRequest, recorder.Body.String())
+		}
+		if transfers, _ := repo.ListByWallet(wallet.ID, repository.TransferListFilter{}, 0, 0); len(transfers) != 0 {
+			t.Errorf("%d transfers stored, want none", len(transfers))
+		}
+	})
+}
+
+func TestFeeStrategyIsPricedIntoTheBuild(t *testing.T) {
+	t.Run("hot wallet", func(t *testing.T) {
+		wallet := &models.Wallet{ID: uuid.New(), BitgoWalletID: "bitgo-btc-1", Coin: "btc", WalletType: models.WalletTypeHot, SpendableBalanceString: "10", IsActive: true}
+		client := &buildRecordingClient{SimulatedClient: bitgo.NewSimulatedClient(bitgo.SimulationConfig{FeeRate: 10000}, &SimpleLogger{})}
+		server, router, _ := newHotTransferTestServer(wallet, client)
+
+		recorder := postTransfer(t, router, wallet, CreateTransferRequest{
+			RecipientAddress: testBTCAddress,
+			AmountString:     "0.01",
+			Coin:             "btc",
+			TransferType:     models.WalletTypeHot,
+			FeeStrategy:      bitgo.FeeStrategyPriority,
+		})
+		if recorder.Code != http.StatusCreated {
+			t.Fatalf("status = %d, want %d: %s", recorder.Code, http.StatusCreated, recorder.Body.String())
+		}
+		if len(client.builds) != 1 {
+			t.Fatalf("%d builds sent to BitGo, want 1", len(client.builds))
+		}
+		if build := client.builds[0]; build.FeeRate != 15000 || build.MaxFeeRate != server.config.MaxFeeRate {
+			t.Errorf("build feeRate %d, maxFeeRate %d; want 15000, %d", build.FeeRate, build.MaxFeeRate, server.config.MaxFeeRate)
+		}
+	})
+
+	t.Run("warm wallet is rejected", func(t *testing.T) {
+		wallet := &models.Wallet{ID: uuid.New(), BitgoWalletID: "bitgo-btc-2", Coin: "btc", WalletType: models.WalletTypeWarm, SpendableBalanceString: "10", IsActive: true}
+		client := &buildRecordingClient{SimulatedClient: bitgo.NewSimulatedClient(bitgo.SimulationConfig{}, &SimpleLogger{})}
+		_, router, _ := newHotTransferTestServer(wallet, client)
+
+		recorder := postTransfer(t, router, wallet, CreateTransferRequest{
+			RecipientAddress: testBTCAddress,
+			AmountString:     "0.01",
+			Coin:             "btc",
+			TransferType:     models.WalletTypeWarm,
+			FeeStrategy:      bitgo.FeeStrategyEconomy,
+		})
+		if recorder.Code != http.StatusBadRequest {
+			t.Fatalf("status = %d, want %d: %s", recorder.Code, http.StatusBadRequest, recorder.Body.String())
+		}
+		if len(client.builds) != 0 {
+			t.Errorf("%d builds sent to BitGo, want none", len(client.builds))
+		}
+	})
+}
+
+// insufficientFundsClient rejects every build for lack of funds and reports a fixed balance,
+// or fails the balance lookup when balanceErr is set
+type insufficientFundsClient struct {
+	*bitgo.SimulatedClient
+	balanceErr error
+}
+
+func (c *insufficientFundsClient) BuildTransfer(ctx context.Context, walletID, coin string, req bitgo.BuildTransferRequest) (*bitgo.BuildTransferResponse, error) {
+	return nil, bitgo.APIError{StatusCode: http.StatusBadRequest, Name: "InsufficientBalance", Message: "insufficient balance"}
+}
+
+func (c *insufficientFundsClient) GetWalletBalance(ctx context.Context, walletID, coin string) (*bitgo.WalletBalance, error) {
+	if c.balanceErr != nil {
+		return nil, c.balanceErr
+	}
+	return &bitgo.WalletBalance{WalletID: walletID, Coin: coin, SpendableBalanceString: "5000"}, nil
+}
+
+func TestInsufficientFundsReturnsPaymentRequired(t *testing.T) {
+	tests := []struct {
+		name        string
+		balanceErr  error
+		wantBalance interface{}
+	}{
+		{name: "with balance", wantBalance: "5000"},
+		{name: "balance unavailable", balanceErr: errors.New("timeout"), wantBalance: nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			wallet := &models.Wallet{ID: uuid.New(), BitgoWalletID: "bitgo-btc-1", Coin: "btc", WalletType: models.WalletTypeHot, SpendableBalanceString: "10", IsActive: true}
+			client := &insufficientFundsClient{SimulatedClient: bitgo.NewSimulatedClient(bitgo.SimulationConfig{}, &SimpleLogger{}), balanceErr: tt.balanceErr}
+			_, router, repo := newHotTransferTestServer(wallet, client)
+
+			recorder := postTransfer(t, router, wallet, CreateTransferRequest{
+				RecipientAddress: testBTCAddress,
+				AmountString:     "0.01",
+				Coin:             "btc",
+				TransferType:     models.WalletTypeHot,
+			})
+			if recorder.Code != http.StatusPaymentRequired {
+				t.Fatalf("status = %d, want %d: %s", recorder.Code, http.StatusPaymentRequired, recorder.Body.String())
+			}
+			var body map[string]interface{}
+			decodeJSON(t, recorder, &body)
+			if body["code"] != "insufficient_funds" || body["requested_amount"] != "0.01" {
+				t.Errorf("body = %v, want code insufficient_funds for 0.01", body)
+			}
+			if balance, ok := body["available_balance"]; !ok || balance != tt.wantBalance {
+				t.Errorf("available_balance = %v, want %v", balance, tt.wantBalance)
+			}
+
+			transfers, _ := repo.ListByWallet(wallet.ID, repository.TransferListFilter{}, 0, 0)
+			if len(transfers) != 1 || transfers[0].Status != models.TransferStatusFailed {
+				t.Errorf("stored transfers = %d, want one marked failed", len(transfers))
+			}
+		})
+	}
+}
+
+// txInfoClient builds like the simulation but describes the UTXO transaction in txInfo, as
+// BitGo does, decoded from JSON
+type txInfoClient struct {
+	*bitgo.SimulatedClient
+}
+
+func (c txInfoClient) BuildTransfer(ctx context.Context, walletID, coin string, req bitgo.BuildTransferRequest) (*bitgo.BuildTransferResponse, error) {
+	resp, err := c.SimulatedClient.BuildTransfer(ctx, walletID, coin, req)
+	if err != nil {
+		return nil, err
+	}
+	resp.PrebuildTx.TxInfo = map[string]interface{}{
+		"unspents":        []interface{}{map[string]interface{}{"id": "a:0"}, map[string]interface{}{"id": "b:1"}},
+		"nOutputs":        float64(2),
+		"changeAddresses": []interface{}{"bc1qchange"},
+	}
+	return resp, nil
+}
+
+func TestNewTransferBuildInfo(t *testing.T) {
+	tests := []struct {
+		name string
+		resp *bitgo.BuildTransferResponse
+		want *models.TransferBuildInfo
+	}{
+		{
+			name: "unspents listed",
+			resp: &bitgo.BuildTransferResponse{
+				PrebuildTx: &bitgo.PrebuildTransaction{
+					FeeInfo: bitgo.FeeInfo{Size: 250, FeeRate: 10000},
+					TxInfo: map[string]interface{}{
+						"unspents":        []interface{}{"a:0", "b:1", "c:2"},
+						"nOutputs":        float64(2),
+						"changeAddresses": []interface{}{"bc1qchange"},
+					},
+				},
+			},
+			want: &models.TransferBuildInfo{Size: 250, FeeRate: 10000, InputCount: 3, OutputCount: 2, ChangeAddress: "bc1qchange"},
+		},
+		{
+			name: "input counts by type",
+			resp: &bitgo.BuildTransferResponse{
+				PrebuildTx: &bitgo.PrebuildTransaction{
+					TxInfo: map[string]interface{}{"nP2SHInputs": float64(1), "nSegwitInputs": float64(2), "nOutputs": float64(1)},
+				},
+				FeeInfo: &bitgo.FeeInfo{Size: 300, FeeRate: 12000},
+			},
+			want: &models.TransferBuildInfo{Size: 300, FeeRate: 12000, InputCount: 3, OutputCount: 1},
+		},
+		{name: "no prebuild", resp: &bitgo.BuildTransferResponse{}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := newTransferBuildInfo(tt.resp, "btc")
+			if (got == nil) != (tt.want == nil) || got != nil && *got != *tt.want {
+				t.Errorf("newTransferBuildInfo() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCreateTransferStoresBuildInfo(t *testing.T) {
+	wallet := &models.Wallet{ID: uuid.New(), BitgoWalletID: "bitgo-btc-1", Coin: "btc", WalletType: models.WalletTypeHot, SpendableBalanceString: "10", IsActive: true}
+	client := txInfoClient{bitgo.NewSimulatedClient(bitgo.SimulationConfig{}, &SimpleLogger{})}
+	_, router, repo := newHotTransferTestServer(wallet, client)
+
+	recorder := postTransfer(t, router, wallet, CreateTransferRequest{
+		RecipientAddress: testBTCAddress,
+		AmountString:     "0.01",
+		Coin:             "btc",
+		TransferType:     models.WalletTypeHot,
+	})
+	if recorder.Code != http.StatusCreated {
+		t.Fatalf("status = %d, want %d: %s", recorder.Code, http.StatusCreated, recorder.Body.String())
+	}
+
+	var body struct {
+		Transfer models.TransferRequest `json:"transfer"`
+	}
+	decodeJSON(t, recorder, &body)
+	want := models.TransferBuildInfo{Size: 250, FeeRate: 10000, InputCount: 2, OutputCount: 2, ChangeAddress: "bc1qchange"}
+	if got := body.Transfer.BuildInfo; got == nil || *got != want {
+		t.Errorf("returned build_info = %+v, want %+v", got, want)
+	}
+
+	stored, _ := repo.GetByID(body.Transfer.ID)
+	if stored == nil {
+		t.Fatalf("transfer %s not stored", body.Transfer.ID)
+	}
+	if got := stored.BuildInfo; got == nil || *got != want {
+		t.Errorf("stored build info = %+v, want %+v", got, want)
+	}
+}
+
+func TestCreateTransferRejectsWrongNetworkAddress(t *testing.T) {
+	wallet := &models.Wallet{ID: uuid.New(), BitgoWalletID: "bitgo-tbtc-1", Coin: "tbtc", WalletType: models.WalletTypeHot, SpendableBalanceString: "10", IsActive: true}
+	client := bitgo.NewSimulatedClient(bitgo.SimulationConfig{}, &SimpleLogger{})
+
+	tests := []struct {
+		name      string
+		recipient string
+		wantCode  int
+	}{
+		{name: "mainnet address", recipient: testBTCAddress, wantCode: http.StatusBadRequest},
+		{name: "testnet address", recipient: "tb1qw508d6qejxtdg4y5r3zarvary0c5xw7kxpjzsx", wantCode: http.StatusCreated},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server, router, _ := newHotTransferTestServer(wallet, client)
+			server.config.BitGoEnvironment = "test"
+			server.config.AddressNetworkGuard = true
+
+			recorder := postTransfer(t, router, wallet, CreateTransferRequest{
+				RecipientAddress: tt.recipient,
+				AmountString:     "0.01",
+				Coin:             "tbtc",
+				TransferType:     models.WalletTypeHot,
+			})
+			if recorder.Code != tt.wantCode {
+				t.Fatalf("status = %d, want %d: %s", recorder.Code, tt.wantCode, recorder.Body.String())
+			}
+			if tt.wantCode != http.StatusBadRequest {
+				return
+			}
+
+			var body struct {
+				Code            string `json:"code"`
+				ExpectedNetwork string `json:"expected_network"`
+			}
+			decodeJSON(t, recorder, &body)
+			if body.Code != "address_network_mismatch" || body.ExpectedNetwork != string(services.NetworkTestnet) {
+				t.Errorf("code = %q, expected_network = %q; want address_network_mismatch, %s", body.Code, body.ExpectedNetwork, services.NetworkTestnet)
+			}
+		})
+	}
+}
+
+// racingBuildClient fails every build after tagging the transfer being built, standing in for
+// a concurrent update that lands while BitGo is building
+type racingBuildClient struct {
+	*bitgo.SimulatedClient
+	repo *memTransferRepo
+}
+
+func (c *racingBuildClient) BuildTransfer(ctx context.Context, walletID, coin string, req bitgo.BuildTransferRequest) (*bitgo.BuildTransferResponse, error) {
+	if id, err := uuid.Parse(req.SequenceId); err == nil {
+		if transfer, _ := c.repo.GetByID(id); transfer != nil {
+			transfer.Tags = []string{"racing"}
+			c.repo.Update(transfer)
+		}
+	}
+	return nil, bitgo.APIError{StatusCode: http.StatusBadRequest, Message: "invalid recipient", Name: "InvalidRecipient"}
+}
+
+func TestHotBuildFailureSurvivesConcurrentUpdate(t *testing.T) {
+	wallet := &models.Wallet{ID: uuid.New(), BitgoWalletID: "bitgo-btc-1", Coin: "btc", WalletType: models.WalletTypeHot, SpendableBalanceString: "10", IsActive: true}
+	client := &racingBuildClient{SimulatedClient: bitgo.NewSimulatedClient(bitgo.SimulationConfig{}, &SimpleLogger{})}
+	_, router, repo := newHotTransferTestServer(wallet, client)
+	client.repo = repo
+
+	recorder := postTransfer(t, router, wallet, CreateTransferRequest{RecipientAddress: testBTCAddress, AmountString: "0.1", Coin: "btc", TransferType: models.WalletTypeHot})
+	if recorder.Code != http.StatusBadRequest {
+		t.Fatalf("status = %d, want %d: %s", recorder.Code, http.StatusBadRequest, recorder.Body.String())
+	}
+
+	transfers, err := repo.ListByWallet(wallet.ID, repository.TransferListFilter{}, 0, 0)
+	if err != nil || len(transfers) != 1 {
+		t.Fatalf("ListByWallet() = %d transfers, %v; want 1", len(transfers), err)
+	}
+	if transfers[0].Status != models.TransferStatusFailed || transfers[0].FailedAt == nil {
+		t.Errorf("status %s with failed_at %v, want %s", transfers[0].Status, transfers[0].FailedAt, models.TransferStatusFailed)
+	}
+	if len(transfers[0].Tags) != 1 || transfers[0].Tags[0] != "racing" {
+		t.Errorf("tags = %v, want the concurrent update kept", transfers[0].Tags)
+	}
+}
+
+// failingLookupClient fails every BitGo transfer lookup with err
+type failingLookupClient struct {
+	bitgo.BitGoAPI
+	err error
+}
+
+func (c failingLookupClient) GetTransfer(ctx context.Context, walletID, coin, transferID string) (*bitgo.Transfer, error) {
+	return nil, c.err
+}
+
+func TestGetTransferStatusMapsBitGoLookupErrors(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	tests := []struct {
+		name       string
+		err        error
+		wantStatus int
+		wantError  string
+	}{
+		{name: "not found on bitgo", err: bitgo.APIError{StatusCode: http.StatusNotFound, Message: "transfer not found"}, wantStatus: http.StatusNotFound, wantError: "Transfer not found on BitGo"},
+		{name: "bitgo server error", err: bitgo.APIError{StatusCode: http.StatusServiceUnavailable, Message: "service unavailable"}, wantStatus: http.StatusBadGateway, wantError: "Failed to get transfer status from BitGo"},
+		{name: "timeout", err: fmt.Errorf("get transfer: %w", context.DeadlineExceeded), wantStatus: http.StatusGatewayTimeout, wantError: "Failed to get transfer status from BitGo"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			wallet := &models.Wallet{ID: uuid.New(), BitgoWalletID: "bitgo-warm-1", Coin: "btc", WalletType: models.WalletTypeWarm}
+			bitgoTransferID := "bitgo-transfer-1"
+			transfer := &models.TransferRequest{
+				ID:               uuid.New(),
+				WalletID:         wallet.ID,
+				RecipientAddress: testBTCAddress,
+				AmountString:     "0.01",
+				Coin:             "btc",
+				TransferType:     models.WalletTypeWarm,
+				Status:           models.TransferStatusBroadcast,
+				BitgoTransferID:  &bitgoTransferID,
+			}
+			server := &Server{
+				bitgoClient:         failingLookupClient{err: tt.err},
+				walletRepo:          newMemWalletRepo(wallet),
+				transferRequestRepo: newMemTransferRepo(transfer),
+			}
+			router := gin.New()
+			router.GET("/transfers/:id/status", server.getTransferStatus)
+
+			recorder := httptest.NewRecorder()
+			router.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/transfers/"+transfer.ID.String()+"/status", nil))
+			if recorder.Code != tt.wantStatus {
+				t.Fatalf("status = %d, want %d: %s", recorder.Code, tt.wantStatus, recorder.Body.String())
+			}
+			var body struct {
+				Error string `json:"error"`
+			}
+			decodeJSON(t, recorder, &body)
+			if body.Error != tt.wantError {
+				t.Errorf("error = %q, want %q", body.Error, tt.wantError)
+			}
+		})
+	}
+}
+
+func TestCreateTransferNormalizesAmount(t *testing.T) {
+	wallet := &models.Wallet{ID: uuid.New(), BitgoWalletID: "bitgo-btc-1", Coin: "btc", WalletType: models.WalletTypeHot, SpendableBalanceString: "10", IsActive: true}
+	client := bitgo.NewSimulatedClient(bitgo.SimulationConfig{}, &SimpleLogger{})
+
+	tests := []struct {
+		name       string
+		amount     string
+		wantStatus int
+		wantAmount string
+	}{
+		{name: "trailing zeros", amount: "1.50000", wantStatus: http.StatusCreated, wantAmount: "1.5"},
+		{name: "decimal comma", amount: "1,5", wantStatus: http.StatusBadRequest},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, router, repo := newHotTransferTestServer(wallet, client)
+			recorder := postTransfer(t, router, wallet, CreateTransferRequest{
+				RecipientAddress: testBTCAddress,
+				AmountString:     tt.amount,
+				Coin:             "btc",
+				TransferType:     models.WalletTypeHot,
+			})
+			if recorder.Code != tt.wantStatus {
+				t.Fatalf("status = %d, want %d: %s", recorder.Code, tt.wantStatus, recorder.Body.String())
+			}
+
+			transfers, _ := repo.ListByWallet(wallet.ID, repository.TransferListFilter{}, 0, 0)
+			if tt.wantAmount == "" {
+				var body struct {
+					Code string `json:"code"`
+				}
+				decodeJSON(t, recorder, &body)
+				if body.Code != "invalid_amount" {
+					t.Errorf("code = %q, want invalid_amount", body.Code)
+				}
+				if len(transfers) != 0 {
+					t.Errorf("%d transfers stored, want none", len(transfers))
+				}
+				return
+			}
+			if len(transfers) != 1 || transfers[0].AmountString != tt.wantAmount {
+				t.Errorf("stored transfers = %v, want one for %s", transfers, tt.wantAmount)
+			}
+		})
+	}
+}
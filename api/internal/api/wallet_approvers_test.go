@@ -0,0 +1,75 @@
+package api
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"bitgo-wallets-api/internal/bitgo"
+	"bitgo-wallets-api/internal/config"
+	"bitgo-wallets-api/internal/models"
+	"bitgo-wallets-api/internal/services"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+func TestWalletApproversReceivePendingApprovalNotifications(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	wallet := &models.Wallet{ID: uuid.New(), BitgoWalletID: "bitgo-wallet-1", Coin: "btc", WalletType: models.WalletTypeWarm}
+	approver, admin, viewer := uuid.New(), uuid.New(), uuid.New()
+	membershipRepo := newMemMembershipRepo(
+		&models.WalletMembership{WalletID: wallet.ID, UserID: approver, Role: string(models.WalletRoleApprover)},
+		&models.WalletMembership{WalletID: wallet.ID, UserID: admin, Role: string(models.WalletRoleAdmin)},
+		&models.WalletMembership{WalletID: wallet.ID, UserID: viewer, Role: string(models.WalletRoleViewer)},
+		&models.WalletMembership{WalletID: uuid.New(), UserID: uuid.New(), Role: string(models.WalletRoleApprover)},
+	)
+	membershipRepo.emails = map[uuid.UUID]string{approver: "approver@example.com", admin: "admin@example.com", viewer: "viewer@example.com"}
+	notificationRepo := &memNotificationRepo{}
+
+	server := &Server{
+		config:           &config.Config{NotificationOverflowStrategy: string(services.QueueOverflowDropNew)},
+		featureFlags:     services.NewFeatureFlagStore(services.DefaultFeatureFlags()),
+		walletRepo:       newMemWalletRepo(wallet),
+		membershipRepo:   membershipRepo,
+		notificationRepo: notificationRepo,
+	}
+	server.initNotificationService()
+
+	router := gin.New()
+	router.GET("/wallets/:id/approvers", server.getWalletApprovers)
+	recorder := httptest.NewRecorder()
+	router.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/wallets/"+wallet.ID.String()+"/approvers", nil))
+	if recorder.Code != http.StatusOK {
+		t.Fatalf("status = %d, want %d: %s", recorder.Code, http.StatusOK, recorder.Body.String())
+	}
+	var body struct {
+		Approvers []*models.WalletApprover `json:"approvers"`
+		Count     int                      `json:"count"`
+	}
+	decodeJSON(t, recorder, &body)
+	if body.Count != 2 || len(body.Approvers) != 2 ||
+		body.Approvers[0].UserID != admin || body.Approvers[1].UserID != approver {
+		t.Fatalf("approvers = %s, want the admin and the approver", recorder.Body.String())
+	}
+
+	transfer := &models.TransferRequest{ID: uuid.New(), WalletID: wallet.ID, RequestedByUserID: viewer, AmountString: "0.1", Coin: "btc", TransferType: models.WalletTypeWarm}
+	server.notificationSvc.SendPendingApprovalNotification(transfer, &bitgo.ApprovalStatus{RequiredApprovals: 2})
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+	defer cancel()
+	if err := server.notificationSvc.Shutdown(ctx); err != nil {
+		t.Fatalf("Shutdown() error = %v", err)
+	}
+
+	records, _ := notificationRepo.ListByTransfer(transfer.ID)
+	if len(records) != 1 {
+		t.Fatalf("%d notifications recorded, want 1", len(records))
+	}
+	recipients := records[0].Recipients
+	if len(recipients) != 2 || recipients[0] != "admin@example.com" || recipients[1] != "approver@example.com" {
+		t.Errorf("recipients = %v, want the wallet's two approvers", recipients)
+	}
+}
@@ -0,0 +1,48 @@
+package api
+
+import (
+	"context"
+	"errors"
+	"net/http"
+
+	"bitgo-wallets-api/internal/repository"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// backfillWalletTransfers imports the wallet's BitGo send history as backfill-origin transfer
+// requests. Transfers already recorded locally are skipped, so it is safe to run repeatedly.
+func (s *Server) backfillWalletTransfers(c *gin.Context) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid wallet ID"})
+		return
+	}
+
+	wallet, err := s.walletRepo.GetByID(id)
+	if errors.Is(err, repository.ErrWalletNotFound) {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Wallet not found"})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to get wallet"})
+		return
+	}
+
+	report, err := s.transferBackfiller.Backfill(context.Background(), wallet, s.getCurrentUserID(c))
+	if err != nil {
+		response := gin.H{
+			"error":   "Failed to backfill transfers from BitGo",
+			"details": err.Error(),
+		}
+		// Transfers imported before the failure are kept; report them so the caller knows
+		if report != nil {
+			response["report"] = report
+		}
+		c.JSON(http.StatusBadGateway, response)
+		return
+	}
+
+	c.JSON(http.StatusOK, report)
+}
@@ -0,0 +1,42 @@
+package api
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"bitgo-wallets-api/internal/config"
+	"bitgo-wallets-api/internal/models"
+	"bitgo-wallets-api/internal/services"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+func TestBackfillWalletTransfersRequiresAdmin(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	wallet := &models.Wallet{ID: uuid.New(), BitgoWalletID: "bitgo-wallet-1", Coin: "btc", WalletType: models.WalletTypeHot}
+	transferRepo := newMemTransferRepo()
+	server := &Server{
+		config:             &config.Config{AdminAPIKey: testAdminKey},
+		walletRepo:         newMemWalletRepo(wallet),
+		transferBackfiller: services.NewTransferBackfiller(&transferListClient{}, transferRepo, &SimpleLogger{}),
+	}
+	router := gin.New()
+	router.POST("/wallets/:id/backfill-transfers", server.requireAdmin(), server.backfillWalletTransfers)
+
+	recorder := httptest.NewRecorder()
+	router.ServeHTTP(recorder, httptest.NewRequest(http.MethodPost, "/wallets/"+wallet.ID.String()+"/backfill-transfers", nil))
+	if recorder.Code != http.StatusUnauthorized {
+		t.Fatalf("status = %d, want %d", recorder.Code, http.StatusUnauthorized)
+	}
+
+	request := httptest.NewRequest(http.MethodPost, "/wallets/"+wallet.ID.String()+"/backfill-transfers", nil)
+	request.Header.Set("X-Admin-Key", testAdminKey)
+	recorder = httptest.NewRecorder()
+	router.ServeHTTP(recorder, request)
+	if recorder.Code != http.StatusOK {
+		t.Fatalf("status = %d, want %d: %s", recorder.Code, http.StatusOK, recorder.Body.String())
+	}
+}
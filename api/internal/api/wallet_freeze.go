@@ -0,0 +1,114 @@
+package api
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"bitgo-wallets-api/internal/models"
+	"bitgo-wallets-api/internal/repository"
+	"bitgo-wallets-api/internal/services"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// unfreezeWallet lifts a freeze, including one the velocity guard applied, and starts a new
+// velocity window so the volume that tripped the freeze doesn't trip it again. It is the
+// only way to lift a freeze.
+func (s *Server) unfreezeWallet(c *gin.Context) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid wallet ID"})
+		return
+	}
+
+	wallet, err := s.walletRepo.GetByID(id)
+	if errors.Is(err, repository.ErrWalletNotFound) {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Wallet not found"})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to get wallet"})
+		return
+	}
+	if !wallet.Frozen {
+		c.JSON(http.StatusConflict, gin.H{"error": "Wallet is not frozen"})
+		return
+	}
+
+	wallet.Frozen = false
+	services.ClearFrozenMetadata(wallet)
+	if err := s.walletRepo.SetFrozen(wallet.ID, false, wallet.Metadata); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update wallet", "details": err.Error()})
+		return
+	}
+	s.velocityGuard.Reset(wallet.ID)
+
+	c.JSON(http.StatusOK, wallet)
+}
+
+// loadTransferWallet loads the wallet a transfer is requested from, responding and returning
+// nil when it can't be loaded
+func (s *Server) loadTransferWallet(c *gin.Context, walletID uuid.UUID) *models.Wallet {
+	wallet, err := s.walletRepo.GetByID(walletID)
+	if errors.Is(err, repository.ErrWalletNotFound) {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Wallet not found"})
+		return nil
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to get wallet"})
+		return nil
+	}
+	return wallet
+}
+
+// rejectFrozenWallet responds with 423 and returns true when the wallet is frozen. Frozen
+// wallets can't send until an operator unfreezes them.
+func (s *Server) rejectFrozenWallet(c *gin.Context, wallet *models.Wallet) bool {
+	if !wallet.Frozen {
+		return false
+	}
+
+	response := gin.H{
+		"error":     "Wallet is frozen",
+		"code":      "wallet_frozen",
+		"wallet_id": wallet.ID,
+	}
+	if reason := services.FrozenReason(wallet); reason != "" {
+		response["reason"] = reason
+	}
+	c.JSON(http.StatusLocked, response)
+	return true
+}
+
+// rejectVelocitySpike responds with 423 and returns true when the transfer would spike the
+// wallet's outbound velocity, in which case the guard has just frozen the wallet. Amounts
+// that don't parse are left for transfer validation to reject.
+func (s *Server) rejectVelocitySpike(c *gin.Context, wallet *models.Wallet, amountString string) bool {
+	amount, err := strconv.ParseFloat(amountString, 64)
+	if err != nil {
+		return false
+	}
+
+	var spike *services.VelocitySpikeError
+	if err := s.velocityGuard.Check(wallet, amount); errors.As(err, &spike) {
+		c.JSON(http.StatusLocked, gin.H{
+			"error":         "Wallet frozen after an outbound velocity spike",
+			"code":          "wallet_frozen",
+			"wallet_id":     wallet.ID,
+			"reason":        spike.Error(),
+			"window_volume": spike.WindowVolume,
+			"baseline":      spike.Baseline,
+		})
+		return true
+	}
+	return false
+}
+
+// recordOutflow adds a created transfer to its wallet's velocity history
+func (s *Server) recordOutflow(transfer *models.TransferRequest) {
+	if amount, err := strconv.ParseFloat(transfer.AmountString, 64); err == nil {
+		s.velocityGuard.Record(transfer.WalletID, amount)
+	}
+}
@@ -0,0 +1,131 @@
+package api
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"bitgo-wallets-api/internal/bitgo"
+	"bitgo-wallets-api/internal/config"
+	"bitgo-wallets-api/internal/models"
+	"bitgo-wallets-api/internal/services"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+func TestVelocitySpikeFreezesWalletUntilAdminUnfreezes(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	now := time.Now()
+	clock := &testClock{}
+
+	wallet := &models.Wallet{
+		ID:                     uuid.New(),
+		BitgoWalletID:          "bitgo-warm-1",
+		Coin:                   "btc",
+		WalletType:             models.WalletTypeWarm,
+		BalanceString:          "100",
+		ConfirmedBalanceString: "100",
+		SpendableBalanceString: "100",
+		IsActive:               true,
+		CreatedAt:              now.Add(-72 * time.Hour),
+	}
+	walletRepo := newMemWalletRepo(wallet)
+	transferRepo := newMemTransferRepo()
+	logger := &SimpleLogger{}
+	client := bitgo.NewSimulatedClient(bitgo.SimulationConfig{}, logger)
+
+	guardConfig := services.DefaultVelocityGuardConfig()
+	guardConfig.BaselinePeriod = 48 * time.Hour
+	guardConfig.Clock = clock
+	guard := services.NewVelocityGuard(guardConfig, walletRepo, transferRepo, nopNotifier{}, logger)
+
+	// A baseline of one transfer of 1 every few hours, about 0.1 per hour
+	for i := guardConfig.MinBaselineTransfers; i > 0; i-- {
+		clock.Set(now.Add(-time.Duration(i*4) * time.Hour))
+		guard.Record(wallet.ID, 1)
+	}
+	clock.Set(now)
+
+	server := &Server{
+		config:             &config.Config{AdminAPIKey: testAdminKey},
+		bitgoClient:        client,
+		walletRepo:         walletRepo,
+		walletAddressRepo:  memWalletAddressRepo{},
+		blockedAddressRepo: newMemBlockedAddressRepo(),
+		velocityGuard:      guard,
+		warmWalletSvc: services.NewWarmWalletService(
+			client,
+			walletRepo,
+			transferRepo,
+			nopNotifier{},
+			logger,
+			services.DefaultWarmWalletConfig(),
+			nil,
+			nil,
+			nil,
+		),
+	}
+	router := gin.New()
+	router.POST("/wallets/:id/transfers", server.createTransfer)
+	router.POST("/wallets/:id/unfreeze", server.requireAdmin(), server.unfreezeWallet)
+
+	transfersPath := "/wallets/" + wallet.ID.String() + "/transfers"
+	createTransfer := func(amount string) *httptest.ResponseRecorder {
+		body := CreateTransferRequest{
+			RecipientAddress: testBTCAddress,
+			AmountString:     amount,
+			Coin:             "btc",
+			TransferType:     models.WalletTypeWarm,
+			RequestorName:    "Test Requestor",
+			RequestorEmail:   "requestor@example.com",
+			UrgencyLevel:     "normal",
+		}
+		recorder := httptest.NewRecorder()
+		router.ServeHTTP(recorder, httptest.NewRequest(http.MethodPost, transfersPath, jsonBody(t, body)))
+		return recorder
+	}
+
+	if recorder := createTransfer("5"); recorder.Code != http.StatusLocked {
+		t.Fatalf("spiking transfer: status = %d, want %d: %s", recorder.Code, http.StatusLocked, recorder.Body.String())
+	}
+	if !wallet.Frozen {
+		t.Fatal("wallet wasn't frozen by the spike")
+	}
+
+	// Even a transfer within the baseline is rejected while the wallet is frozen
+	recorder := createTransfer("0.1")
+	if recorder.Code != http.StatusLocked {
+		t.Fatalf("transfer from frozen wallet: status = %d, want %d: %s", recorder.Code, http.StatusLocked, recorder.Body.String())
+	}
+	var body struct {
+		Code string `json:"code"`
+	}
+	decodeJSON(t, recorder, &body)
+	if body.Code != "wallet_frozen" {
+		t.Errorf("code = %q, want wallet_frozen", body.Code)
+	}
+
+	unfreezePath := "/wallets/" + wallet.ID.String() + "/unfreeze"
+	recorder = httptest.NewRecorder()
+	router.ServeHTTP(recorder, httptest.NewRequest(http.MethodPost, unfreezePath, nil))
+	if recorder.Code != http.StatusUnauthorized {
+		t.Fatalf("unfreeze without admin credentials: status = %d, want %d", recorder.Code, http.StatusUnauthorized)
+	}
+	if !wallet.Frozen {
+		t.Fatal("wallet was unfrozen without admin credentials")
+	}
+
+	request := httptest.NewRequest(http.MethodPost, unfreezePath, nil)
+	request.Header.Set("X-Admin-Key", testAdminKey)
+	recorder = httptest.NewRecorder()
+	router.ServeHTTP(recorder, request)
+	if recorder.Code != http.StatusOK {
+		t.Fatalf("unfreeze as admin: status = %d, want %d: %s", recorder.Code, http.StatusOK, recorder.Body.String())
+	}
+
+	if recorder := createTransfer("0.1"); recorder.Code != http.StatusCreated {
+		t.Errorf("transfer after unfreeze: status = %d, want %d: %s", recorder.Code, http.StatusCreated, recorder.Body.String())
+	}
+}
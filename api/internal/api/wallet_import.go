@@ -0,0 +1,227 @@
+package api
+
+import (
+	"context"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	"bitgo-wallets-api/internal/bitgo"
+	"bitgo-wallets-api/internal/models"
+	"bitgo-wallets-api/internal/repository"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// maxImportWallets caps how many wallets one import request may register
+const maxImportWallets = 200
+
+// importableWalletTypes are the wallet types an import may assign
+var importableWalletTypes = map[models.WalletType]bool{
+	models.WalletTypeCustodial: true,
+	models.WalletTypeHot:       true,
+	models.WalletTypeWarm:      true,
+	models.WalletTypeCold:      true,
+}
+
+// WalletImportEntry is one existing BitGo wallet to register
+type WalletImportEntry struct {
+	BitgoWalletID string            `json:"bitgo_wallet_id"`
+	Label         string            `json:"label"`
+	Coin          string            `json:"coin"`
+	WalletType    models.WalletType `json:"wallet_type"`
+}
+
+type ImportWalletsRequest struct {
+	Wallets []WalletImportEntry `json:"wallets"`
+}
+
+// WalletImportResult reports what happened to one import entry. Status is created, updated
+// or failed.
+type WalletImportResult struct {
+	Index         int            `json:"index"`
+	BitgoWalletID string         `json:"bitgo_wallet_id"`
+	Status        string         `json:"status"`
+	Wallet        *models.Wallet `json:"wallet,omitempty"`
+	Error         string         `json:"error,omitempty"`
+}
+
+// importWallets registers many existing BitGo wallets at once. The body is either JSON
+// ({"wallets": [...]}) or CSV with a bitgo_wallet_id,label,coin,wallet_type header. Like
+// discovery, wallets already registered are updated rather than duplicated. Each entry
+// succeeds or fails on its own and the response reports every one.
+func (s *Server) importWallets(c *gin.Context) {
+	var entries []WalletImportEntry
+	if strings.HasPrefix(c.ContentType(), "text/csv") {
+		parsed, err := parseWalletImportCSV(c.Request.Body)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid CSV", "details": err.Error()})
+			return
+		}
+		entries = parsed
+	} else {
+		var req ImportWalletsRequest
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		entries = req.Wallets
+	}
+
+	if len(entries) == 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "At least one wallet is required"})
+		return
+	}
+	if len(entries) > maxImportWallets {
+		c.JSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("At most %d wallets can be imported at once", maxImportWallets)})
+		return
+	}
+
+	// Get organization ID (in a real implementation, get from user context)
+	orgID := uuid.New()
+	ctx := context.Background()
+
+	results := make([]WalletImportResult, len(entries))
+	counts := map[string]int{"created": 0, "updated": 0, "failed": 0}
+	for i, entry := range entries {
+		results[i] = s.importWallet(ctx, orgID, i, entry)
+		counts[results[i].Status]++
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"results": results,
+		"total":   len(results),
+		"created": counts["created"],
+		"updated": counts["updated"],
+		"failed":  counts["failed"],
+	})
+}
+
+// importWallet validates one entry, confirms the wallet with BitGo and upserts it
+func (s *Server) importWallet(ctx context.Context, orgID uuid.UUID, index int, entry WalletImportEntry) WalletImportResult {
+	entry.BitgoWalletID = strings.TrimSpace(entry.BitgoWalletID)
+	entry.Label = strings.TrimSpace(entry.Label)
+	entry.Coin = strings.ToLower(strings.TrimSpace(entry.Coin))
+	entry.WalletType = models.WalletType(strings.ToLower(strings.TrimSpace(string(entry.WalletType))))
+
+	result := WalletImportResult{Index: index, BitgoWalletID: entry.BitgoWalletID, Status: "failed"}
+
+	switch {
+	case entry.BitgoWalletID == "":
+		result.Error = "bitgo_wallet_id is required"
+		return result
+	case entry.Label == "":
+		result.Error = "label is required"
+		return result
+	case !importableWalletTypes[entry.WalletType]:
+		result.Error = fmt.Sprintf("unsupported wallet_type %q", entry.WalletType)
+		return result
+	}
+	if _, ok := bitgo.LookupCoin(entry.Coin); !ok {
+		result.Error = fmt.Sprintf("unsupported coin %q", entry.Coin)
+		return result
+	}
+
+	bgWallet, err := s.bitgoClient.GetWallet(ctx, entry.BitgoWalletID, entry.Coin)
+	if err != nil {
+		result.Error = "failed to get wallet from BitGo: " + err.Error()
+		return result
+	}
+
+	syncedAt := time.Now()
+	existing, err := s.walletRepo.GetByBitgoID(entry.BitgoWalletID)
+	if err != nil && !errors.Is(err, repository.ErrWalletNotFound) {
+		result.Error = "failed to look up wallet: " + err.Error()
+		return result
+	}
+	if err == nil {
+		existing.Label = entry.Label
+		existing.BalanceString = bgWallet.BalanceString
+		existing.ConfirmedBalanceString = bgWallet.ConfirmedBalanceString
+		existing.SpendableBalanceString = bgWallet.SpendableBalanceString
+		existing.BalanceSyncedAt = &syncedAt
+
+		if err := s.walletRepo.Update(existing); err != nil {
+			result.Error = "failed to update wallet: " + err.Error()
+			return result
+		}
+		result.Status = "updated"
+		result.Wallet = existing
+		return result
+	}
+
+	wallet := &models.Wallet{
+		OrganizationID:         orgID,
+		BitgoWalletID:          entry.BitgoWalletID,
+		Label:                  entry.Label,
+		Coin:                   entry.Coin,
+		WalletType:             entry.WalletType,
+		BalanceString:          bgWallet.BalanceString,
+		ConfirmedBalanceString: bgWallet.ConfirmedBalanceString,
+		SpendableBalanceString: bgWallet.SpendableBalanceString,
+		BalanceSyncedAt:        &syncedAt,
+		IsActive:               true,
+		Frozen:                 false,
+		Threshold:              2, // Default
+	}
+	if err := s.walletRepo.Create(wallet); err != nil {
+		result.Error = "failed to create wallet: " + err.Error()
+		return result
+	}
+
+	result.Status = "created"
+	result.Wallet = wallet
+	return result
+}
+
+// parseWalletImportCSV reads import entries from CSV whose header names the columns
+func parseWalletImportCSV(body io.Reader) ([]WalletImportEntry, error) {
+	reader := csv.NewReader(body)
+	reader.TrimLeadingSpace = true
+
+	header, err := reader.Read()
+	if err == io.EOF {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	columns := make(map[string]int, len(header))
+	for i, name := range header {
+		columns[strings.ToLower(strings.TrimSpace(name))] = i
+	}
+	for _, required := range []string{"bitgo_wallet_id", "label", "coin", "wallet_type"} {
+		if _, ok := columns[required]; !ok {
+			return nil, fmt.Errorf("missing %s column", required)
+		}
+	}
+
+	var entries []WalletImportEntry
+	for {
+		record, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+		if len(entries) >= maxImportWallets {
+			return nil, fmt.Errorf("at most %d wallets can be imported at once", maxImportWallets)
+		}
+
+		entries = append(entries, WalletImportEntry{
+			BitgoWalletID: record[columns["bitgo_wallet_id"]],
+			Label:         record[columns["label"]],
+			Coin:          record[columns["coin"]],
+			WalletType:    models.WalletType(record[columns["wallet_type"]]),
+		})
+	}
+
+	return entries, nil
+}
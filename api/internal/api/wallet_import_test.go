@@ -0,0 +1,114 @@
+package api
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"bitgo-wallets-api/internal/bitgo"
+	"bitgo-wallets-api/internal/config"
+	"bitgo-wallets-api/internal/models"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+func TestImportWalletsReportsInvalidCoinAlongsideImported(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	existing := &models.Wallet{ID: uuid.New(), BitgoWalletID: "bitgo-existing", Label: "Old label", Coin: "btc", WalletType: models.WalletTypeWarm, IsActive: true}
+	walletRepo := newMemWalletRepo(existing)
+	server := &Server{
+		bitgoClient: bitgo.NewSimulatedClient(bitgo.SimulationConfig{}, &SimpleLogger{}),
+		walletRepo:  walletRepo,
+	}
+	router := gin.New()
+	router.POST("/wallets/import", server.importWallets)
+
+	recorder := httptest.NewRecorder()
+	router.ServeHTTP(recorder, httptest.NewRequest(http.MethodPost, "/wallets/import", jsonBody(t, ImportWalletsRequest{
+		Wallets: []WalletImportEntry{
+			{BitgoWalletID: "bitgo-new-btc", Label: "Treasury", Coin: "BTC", WalletType: models.WalletTypeHot},
+			{BitgoWalletID: "bitgo-bad-coin", Label: "Mystery", Coin: "notacoin", WalletType: models.WalletTypeWarm},
+			{BitgoWalletID: "bitgo-existing", Label: "New label", Coin: "btc", WalletType: models.WalletTypeWarm},
+		},
+	})))
+	if recorder.Code != http.StatusOK {
+		t.Fatalf("status = %d, want %d: %s", recorder.Code, http.StatusOK, recorder.Body.String())
+	}
+
+	var body struct {
+		Results []WalletImportResult `json:"results"`
+		Total   int                  `json:"total"`
+		Created int                  `json:"created"`
+		Updated int                  `json:"updated"`
+		Failed  int                  `json:"failed"`
+	}
+	decodeJSON(t, recorder, &body)
+	if body.Total != 3 || body.Created != 1 || body.Updated != 1 || body.Failed != 1 {
+		t.Errorf("totals = %d total, %d created, %d updated, %d failed; want 3, 1, 1, 1", body.Total, body.Created, body.Updated, body.Failed)
+	}
+	if len(body.Results) != 3 {
+		t.Fatalf("%d results, want 3", len(body.Results))
+	}
+
+	tests := []struct {
+		bitgoWalletID string
+		wantStatus    string
+		wantError     string
+	}{
+		{bitgoWalletID: "bitgo-new-btc", wantStatus: "created"},
+		{bitgoWalletID: "bitgo-bad-coin", wantStatus: "failed", wantError: `unsupported coin "notacoin"`},
+		{bitgoWalletID: "bitgo-existing", wantStatus: "updated"},
+	}
+	for i, tt := range tests {
+		result := body.Results[i]
+		if result.Index != i || result.BitgoWalletID != tt.bitgoWalletID || result.Status != tt.wantStatus || result.Error != tt.wantError {
+			t.Errorf("result %d = %+v, want %s for %s with error %q", i, result, tt.wantStatus, tt.bitgoWalletID, tt.wantError)
+		}
+	}
+
+	if _, err := walletRepo.GetByBitgoID("bitgo-bad-coin"); err == nil {
+		t.Error("wallet with an invalid coin was stored")
+	}
+	if created, err := walletRepo.GetByBitgoID("bitgo-new-btc"); err != nil || created.Coin != "btc" {
+		t.Errorf("imported wallet = %+v, %v; want a stored btc wallet", created, err)
+	}
+	if updated, _ := walletRepo.GetByID(existing.ID); updated.Label != "New label" {
+		t.Errorf("existing wallet label = %q, want it updated", updated.Label)
+	}
+}
+
+func TestImportWalletsRequiresAdmin(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	walletRepo := newMemWalletRepo()
+	server := &Server{
+		config:      &config.Config{AdminAPIKey: testAdminKey},
+		bitgoClient: bitgo.NewSimulatedClient(bitgo.SimulationConfig{}, &SimpleLogger{}),
+		walletRepo:  walletRepo,
+	}
+	router := gin.New()
+	router.POST("/wallets/import", server.requireAdmin(), server.importWallets)
+	payload := ImportWalletsRequest{
+		Wallets: []WalletImportEntry{{BitgoWalletID: "bitgo-new-btc", Label: "Treasury", Coin: "btc", WalletType: models.WalletTypeHot}},
+	}
+
+	recorder := httptest.NewRecorder()
+	router.ServeHTTP(recorder, httptest.NewRequest(http.MethodPost, "/wallets/import", jsonBody(t, payload)))
+	if recorder.Code != http.StatusUnauthorized {
+		t.Fatalf("status = %d, want %d", recorder.Code, http.StatusUnauthorized)
+	}
+	if wallet, _ := walletRepo.GetByBitgoID("bitgo-new-btc"); wallet != nil {
+		t.Fatal("wallet was imported without admin credentials")
+	}
+
+	request := httptest.NewRequest(http.MethodPost, "/wallets/import", jsonBody(t, payload))
+	request.Header.Set("X-Admin-Key", testAdminKey)
+	recorder = httptest.NewRecorder()
+	router.ServeHTTP(recorder, request)
+	if recorder.Code != http.StatusOK {
+		t.Fatalf("status = %d, want %d: %s", recorder.Code, http.StatusOK, recorder.Body.String())
+	}
+	if wallet, _ := walletRepo.GetByBitgoID("bitgo-new-btc"); wallet == nil {
+		t.Error("wallet not imported with admin credentials")
+	}
+}
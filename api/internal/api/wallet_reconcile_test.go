@@ -0,0 +1,122 @@
+package api
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"bitgo-wallets-api/internal/bitgo"
+	"bitgo-wallets-api/internal/config"
+	"bitgo-wallets-api/internal/models"
+	"bitgo-wallets-api/internal/services"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// transferListClient lists the given BitGo transfers; other methods aren't used
+type transferListClient struct {
+	bitgo.BitGoAPI
+	transfers []bitgo.Transfer
+}
+
+func (c *transferListClient) ListTransfers(ctx context.Context, walletID, coin string, options *bitgo.TransferListOptions) (*bitgo.TransferListResponse, error) {
+	return &bitgo.TransferListResponse{Transfers: c.transfers, Count: len(c.transfers)}, nil
+}
+
+func TestReconcileWalletDetectsAndCorrectsDrift(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	wallet := &models.Wallet{ID: uuid.New(), BitgoWalletID: "bitgo-wallet-1", Coin: "btc", WalletType: models.WalletTypeHot}
+	bitgoTransferID := "bitgo-transfer-1"
+	drifted := &models.TransferRequest{
+		WalletID:         wallet.ID,
+		RecipientAddress: testBTCAddress,
+		AmountString:     "0.1",
+		Coin:             "btc",
+		Status:           models.TransferStatusBroadcast,
+		BitgoTransferID:  &bitgoTransferID,
+	}
+	transferRepo := newMemTransferRepo()
+	if err := transferRepo.Create(drifted); err != nil {
+		t.Fatalf("Create() error = %v", err)
+	}
+
+	server := &Server{
+		config:              &config.Config{},
+		bitgoClient:         &transferListClient{transfers: []bitgo.Transfer{{ID: bitgoTransferID, Coin: "btc", State: bitgo.TransferStatusConfirmed, Confirmations: 6, TxID: "tx-1"}}},
+		walletRepo:          newMemWalletRepo(wallet),
+		transferRequestRepo: transferRepo,
+		notificationSvc:     nopNotifier{},
+	}
+	router := gin.New()
+	router.POST("/wallets/:id/reconcile", server.reconcileWallet)
+
+	reconcile := func(body string) services.ReconciliationReport {
+		t.Helper()
+		recorder := httptest.NewRecorder()
+		router.ServeHTTP(recorder, httptest.NewRequest(http.MethodPost, "/wallets/"+wallet.ID.String()+"/reconcile", strings.NewReader(body)))
+		if recorder.Code != http.StatusOK {
+			t.Fatalf("status = %d, want %d: %s", recorder.Code, http.StatusOK, recorder.Body.String())
+		}
+		var report services.ReconciliationReport
+		decodeJSON(t, recorder, &report)
+		return report
+	}
+
+	report := reconcile("")
+	if !report.DryRun || report.MatchedCount != 1 || len(report.Drifts) != 1 {
+		t.Fatalf("dry run report = %+v, want one matched, drifted transfer", report)
+	}
+	if drift := report.Drifts[0]; drift.TransferID != drifted.ID || drift.LocalStatus != models.TransferStatusBroadcast ||
+		drift.ExpectedStatus != models.TransferStatusConfirmed || drift.Corrected {
+		t.Errorf("drift = %+v, want broadcast expected confirmed, not corrected", drift)
+	}
+	if stored, _ := transferRepo.GetByID(drifted.ID); stored.Status != models.TransferStatusBroadcast {
+		t.Errorf("dry run changed the status to %s", stored.Status)
+	}
+
+	report = reconcile(`{"dryRun":false}`)
+	if report.DryRun || len(report.Drifts) != 1 || !report.Drifts[0].Corrected {
+		t.Fatalf("report = %+v, want the drift corrected", report)
+	}
+	stored, _ := transferRepo.GetByID(drifted.ID)
+	if stored.Status != models.TransferStatusConfirmed || stored.CompletedAt == nil {
+		t.Errorf("corrected transfer: status %s, completed at %v; want confirmed with a completion time", stored.Status, stored.CompletedAt)
+	}
+
+	if report := reconcile(`{"dryRun":false}`); len(report.Drifts) != 0 {
+		t.Errorf("after correction %d drifts remain, want none", len(report.Drifts))
+	}
+}
+
+func TestReconcileWalletRequiresAdmin(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	wallet := &models.Wallet{ID: uuid.New(), BitgoWalletID: "bitgo-wallet-1", Coin: "btc", WalletType: models.WalletTypeHot}
+	server := &Server{
+		config:              &config.Config{AdminAPIKey: testAdminKey},
+		bitgoClient:         &transferListClient{},
+		walletRepo:          newMemWalletRepo(wallet),
+		transferRequestRepo: newMemTransferRepo(),
+		notificationSvc:     nopNotifier{},
+	}
+	router := gin.New()
+	router.POST("/wallets/:id/reconcile", server.requireAdmin(), server.reconcileWallet)
+
+	recorder := httptest.NewRecorder()
+	router.ServeHTTP(recorder, httptest.NewRequest(http.MethodPost, "/wallets/"+wallet.ID.String()+"/reconcile", nil))
+	if recorder.Code != http.StatusUnauthorized {
+		t.Fatalf("status = %d, want %d", recorder.Code, http.StatusUnauthorized)
+	}
+
+	request := httptest.NewRequest(http.MethodPost, "/wallets/"+wallet.ID.String()+"/reconcile", nil)
+	request.Header.Set("X-Admin-Key", testAdminKey)
+	recorder = httptest.NewRecorder()
+	router.ServeHTTP(recorder, request)
+	if recorder.Code != http.StatusOK {
+		t.Fatalf("status = %d, want %d: %s", recorder.Code, http.StatusOK, recorder.Body.String())
+	}
+}
@@ -2,12 +2,18 @@ package api
 
 import (
 	"context"
+	"errors"
+	"fmt"
 	"log"
+	"net"
 	"net/http"
-	"strconv"
+	"strings"
+	"time"
 
 	"bitgo-wallets-api/internal/bitgo"
 	"bitgo-wallets-api/internal/models"
+	"bitgo-wallets-api/internal/repository"
+	"bitgo-wallets-api/internal/services"
 
 	"github.com/gin-gonic/gin"
 	"github.com/google/uuid"
@@ -29,11 +35,27 @@ type UpdateWalletRequest struct {
 	BalanceString          string      `json:"balance_string"`
 	ConfirmedBalanceString string      `json:"confirmed_balance_string"`
 	SpendableBalanceString string      `json:"spendable_balance_string"`
-	Frozen                 bool        `json:"frozen"`
+	Frozen                 *bool       `json:"frozen"` // Only true is accepted; lift a freeze with POST /wallets/:id/unfreeze
 	Tags                   []string    `json:"tags"`
 	Metadata               models.JSON `json:"metadata"`
 }
 
+// maxRequiredApprovalsOverride bounds the approvals an admin can require for one wallet
+const maxRequiredApprovalsOverride = 10
+
+// SetRequiredApprovalsRequest sets a wallet's approvals override; null clears it
+type SetRequiredApprovalsRequest struct {
+	RequiredApprovalsOverride *int `json:"required_approvals_override"`
+}
+
+// maxTrustedAddresses bounds a wallet's trusted destinations
+const maxTrustedAddresses = 100
+
+// SetTrustedAddressesRequest replaces a wallet's trusted destinations; an empty list clears them
+type SetTrustedAddressesRequest struct {
+	TrustedAddresses []string `json:"trusted_addresses"`
+}
+
 func (s *Server) createWallet(c *gin.Context) {
 	log.Printf("� WALLET CREATION ENDPOINT HIT - THIS SHOULD APPEAR IN LOGS!")
 	log.Printf("�🔧 DEBUG: Wallet creation endpoint called")
@@ -183,19 +205,10 @@ func (s *Server) testBitGoLogging(c *gin.Context) {
 
 func (s *Server) listWallets(c *gin.Context) {
 	// Get pagination parameters
-	limit := 25
-	offset := 0
-
-	if l := c.Query("limit"); l != "" {
-		if parsed, err := strconv.Atoi(l); err == nil && parsed > 0 && parsed <= 500 {
-			limit = parsed
-		}
-	}
-
-	if o := c.Query("offset"); o != "" {
-		if parsed, err := strconv.Atoi(o); err == nil && parsed >= 0 {
-			offset = parsed
-		}
+	limit, offset, err := parsePagination(c, 25)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid pagination parameters", "details": err.Error()})
+		return
 	}
 
 	// For demo, use a hardcoded organization ID
@@ -207,11 +220,21 @@ func (s *Server) listWallets(c *gin.Context) {
 		return
 	}
 
+	total, err := s.walletRepo.Count(orgID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to count wallets"})
+		return
+	}
+
+	pagination := newPagination(limit, offset, len(wallets), total)
+	setPaginationHeaders(c, pagination)
+
 	c.JSON(http.StatusOK, gin.H{
-		"wallets": wallets,
-		"count":   len(wallets),
-		"limit":   limit,
-		"offset":  offset,
+		"wallets":    wallets,
+		"count":      len(wallets),
+		"limit":      limit,
+		"offset":     offset,
+		"pagination": pagination,
 	})
 }
 
@@ -224,13 +247,12 @@ func (s *Server) getWallet(c *gin.Context) {
 	}
 
 	wallet, err := s.walletRepo.GetByID(id)
-	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to get wallet"})
+	if errors.Is(err, repository.ErrWalletNotFound) {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Wallet not found"})
 		return
 	}
-
-	if wallet == nil {
-		c.JSON(http.StatusNotFound, gin.H{"error": "Wallet not found"})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to get wallet"})
 		return
 	}
 
@@ -253,13 +275,12 @@ func (s *Server) updateWallet(c *gin.Context) {
 
 	// Get existing wallet
 	wallet, err := s.walletRepo.GetByID(id)
-	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to get wallet"})
+	if errors.Is(err, repository.ErrWalletNotFound) {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Wallet not found"})
 		return
 	}
-
-	if wallet == nil {
-		c.JSON(http.StatusNotFound, gin.H{"error": "Wallet not found"})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to get wallet"})
 		return
 	}
 
@@ -276,7 +297,13 @@ func (s *Server) updateWallet(c *gin.Context) {
 	if req.SpendableBalanceString != "" {
 		wallet.SpendableBalanceString = req.SpendableBalanceString
 	}
-	wallet.Frozen = req.Frozen
+	if req.Frozen != nil && !*req.Frozen && wallet.Frozen {
+		c.JSON(http.StatusConflict, gin.H{
+			"error": "Use POST /wallets/:id/unfreeze to lift a freeze",
+			"code":  "wallet_frozen",
+		})
+		return
+	}
 	if req.Tags != nil {
 		wallet.Tags = req.Tags
 	}
@@ -288,7 +315,123 @@ func (s *Server) updateWallet(c *gin.Context) {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update wallet"})
 		return
 	}
+	if req.Frozen != nil && *req.Frozen && !wallet.Frozen {
+		if err := s.walletRepo.SetFrozen(wallet.ID, true, wallet.Metadata); err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to freeze wallet"})
+			return
+		}
+		wallet.Frozen = true
+	}
+
+	c.JSON(http.StatusOK, wallet)
+}
+
+// setWalletRequiredApprovals lets an admin require more approvals for a wallet's cold and
+// warm transfers than the service default. It only affects transfers created afterwards.
+func (s *Server) setWalletRequiredApprovals(c *gin.Context) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid wallet ID"})
+		return
+	}
+
+	var req SetRequiredApprovalsRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if override := req.RequiredApprovalsOverride; override != nil && (*override < 1 || *override > maxRequiredApprovalsOverride) {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": fmt.Sprintf("required_approvals_override must be between 1 and %d", maxRequiredApprovalsOverride),
+		})
+		return
+	}
+
+	err = s.walletRepo.SetRequiredApprovalsOverride(id, req.RequiredApprovalsOverride)
+	if errors.Is(err, repository.ErrWalletNotFound) {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Wallet not found"})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update wallet", "details": err.Error()})
+		return
+	}
+
+	wallet, err := s.walletRepo.GetByID(id)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to get wallet"})
+		return
+	}
+
+	c.JSON(http.StatusOK, wallet)
+}
+
+// setWalletTrustedAddresses replaces the recipients whose warm transfers from the wallet skip
+// risk review and approvals. Each address must be valid for the wallet's coin and not blocked.
+func (s *Server) setWalletTrustedAddresses(c *gin.Context) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid wallet ID"})
+		return
+	}
+
+	var req SetTrustedAddressesRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if len(req.TrustedAddresses) > maxTrustedAddresses {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": fmt.Sprintf("trusted_addresses may list at most %d addresses", maxTrustedAddresses),
+		})
+		return
+	}
+
+	wallet, err := s.walletRepo.GetByID(id)
+	if errors.Is(err, repository.ErrWalletNotFound) {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Wallet not found"})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to get wallet"})
+		return
+	}
+
+	addresses := make([]string, 0, len(req.TrustedAddresses))
+	seen := make(map[string]bool, len(req.TrustedAddresses))
+	for _, address := range req.TrustedAddresses {
+		address = strings.TrimSpace(address)
+		if s.rejectInvalidAddress(c, wallet.Coin, address) || s.rejectBlockedAddress(c, address) {
+			return
+		}
+		if key := repository.NormalizeBlockedAddress(address); !seen[key] {
+			seen[key] = true
+			addresses = append(addresses, address)
+		}
+	}
 
+	audit := &models.AuditLog{Metadata: models.JSON{"actor": s.adminActor(c)}}
+	if userID, ok := s.authenticatedUserID(c); ok {
+		audit.UserID = &userID
+	}
+	if ip := net.ParseIP(c.ClientIP()); ip != nil {
+		audit.IPAddress = &ip
+	}
+	if userAgent := c.Request.UserAgent(); userAgent != "" {
+		audit.UserAgent = &userAgent
+	}
+	err = s.walletRepo.SetTrustedAddresses(id, addresses, audit)
+	if errors.Is(err, repository.ErrWalletNotFound) {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Wallet not found"})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update wallet", "details": err.Error()})
+		return
+	}
+	log.Printf("[INFO] Trusted addresses for wallet %s set to %d address(es) by %s", id, len(addresses), audit.Metadata["actor"])
+
+	wallet.TrustedAddresses = addresses
 	c.JSON(http.StatusOK, wallet)
 }
 
@@ -333,20 +476,26 @@ func (s *Server) discoverWallets(c *gin.Context) {
 	orgID := uuid.New()
 
 	var syncedWallets []models.Wallet
-	var errors []string
+	var syncErrors []string
 
+	syncedAt := time.Now()
 	for _, bgWallet := range bitgoWallets.Wallets {
 		// Check if wallet already exists
 		existingWallet, err := s.walletRepo.GetByBitgoID(bgWallet.ID)
+		if err != nil && !errors.Is(err, repository.ErrWalletNotFound) {
+			syncErrors = append(syncErrors, "Failed to look up wallet "+bgWallet.ID+": "+err.Error())
+			continue
+		}
 		if err == nil {
 			// Wallet exists, update it
 			existingWallet.Label = bgWallet.Label
 			existingWallet.BalanceString = bgWallet.BalanceString
 			existingWallet.ConfirmedBalanceString = bgWallet.ConfirmedBalanceString
 			existingWallet.SpendableBalanceString = bgWallet.SpendableBalanceString
+			existingWallet.BalanceSyncedAt = &syncedAt
 
 			if err := s.walletRepo.Update(existingWallet); err != nil {
-				errors = append(errors, "Failed to update wallet "+bgWallet.ID+": "+err.Error())
+				syncErrors = append(syncErrors, "Failed to update wallet "+bgWallet.ID+": "+err.Error())
 			} else {
 				syncedWallets = append(syncedWallets, *existingWallet)
 			}
@@ -376,13 +525,14 @@ func (s *Server) discoverWallets(c *gin.Context) {
 			BalanceString:          bgWallet.BalanceString,
 			ConfirmedBalanceString: bgWallet.ConfirmedBalanceString,
 			SpendableBalanceString: bgWallet.SpendableBalanceString,
+			BalanceSyncedAt:        &syncedAt,
 			IsActive:               true,
 			Frozen:                 false,
 			Threshold:              2, // Default
 		}
 
 		if err := s.walletRepo.Create(wallet); err != nil {
-			errors = append(errors, "Failed to create wallet "+bgWallet.ID+": "+err.Error())
+			syncErrors = append(syncErrors, "Failed to create wallet "+bgWallet.ID+": "+err.Error())
 		} else {
 			syncedWallets = append(syncedWallets, *wallet)
 		}
@@ -393,8 +543,8 @@ func (s *Server) discoverWallets(c *gin.Context) {
 		"wallets":      syncedWallets,
 	}
 
-	if len(errors) > 0 {
-		response["errors"] = errors
+	if len(syncErrors) > 0 {
+		response["errors"] = syncErrors
 	}
 
 	c.JSON(http.StatusOK, response)
@@ -411,10 +561,14 @@ func (s *Server) syncWalletBalance(c *gin.Context) {
 
 	// Get wallet from database
 	wallet, err := s.walletRepo.GetByID(id)
-	if err != nil {
+	if errors.Is(err, repository.ErrWalletNotFound) {
 		c.JSON(http.StatusNotFound, gin.H{"error": "Wallet not found"})
 		return
 	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to get wallet"})
+		return
+	}
 
 	// Get balance from BitGo
 	ctx := context.Background()
@@ -431,6 +585,8 @@ func (s *Server) syncWalletBalance(c *gin.Context) {
 	wallet.BalanceString = balance.Balance
 	wallet.ConfirmedBalanceString = balance.ConfirmedBalance
 	wallet.SpendableBalanceString = balance.SpendableBalance
+	syncedAt := time.Now()
+	wallet.BalanceSyncedAt = &syncedAt
 
 	if err := s.walletRepo.Update(wallet); err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update wallet balance"})
@@ -439,3 +595,114 @@ func (s *Server) syncWalletBalance(c *gin.Context) {
 
 	c.JSON(http.StatusOK, wallet)
 }
+
+// getWalletKeys returns the public key metadata for a wallet; private key material is never included
+func (s *Server) getWalletKeys(c *gin.Context) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid wallet ID"})
+		return
+	}
+
+	wallet, err := s.walletRepo.GetByID(id)
+	if errors.Is(err, repository.ErrWalletNotFound) {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Wallet not found"})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to get wallet"})
+		return
+	}
+
+	ctx := context.Background()
+	keys, err := s.bitgoClient.GetWalletKeys(ctx, wallet.BitgoWalletID, wallet.Coin)
+	if err != nil {
+		c.JSON(http.StatusBadGateway, gin.H{
+			"error":   "Failed to get wallet keys from BitGo",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"wallet_id": wallet.ID,
+		"keys":      keys,
+	})
+}
+
+// reconcileWalletTransfersLimit caps how many transfers are compared per reconciliation
+const reconcileWalletTransfersLimit = 500
+
+// reconcileWallet compares local transfer statuses against BitGo and reports drift.
+// With dryRun=false the drifted transfers are updated to match BitGo.
+func (s *Server) reconcileWallet(c *gin.Context) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid wallet ID"})
+		return
+	}
+
+	req := struct {
+		DryRun *bool `json:"dryRun"`
+	}{}
+	if c.Request.ContentLength > 0 {
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+	}
+	dryRun := req.DryRun == nil || *req.DryRun
+
+	wallet, err := s.walletRepo.GetByID(id)
+	if errors.Is(err, repository.ErrWalletNotFound) {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Wallet not found"})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to get wallet"})
+		return
+	}
+
+	ctx := context.Background()
+	bitgoTransfers, err := s.bitgoClient.ListTransfers(ctx, wallet.BitgoWalletID, wallet.Coin, &bitgo.TransferListOptions{
+		Limit: reconcileWalletTransfersLimit,
+	})
+	if err != nil {
+		c.JSON(http.StatusBadGateway, gin.H{
+			"error":   "Failed to list transfers from BitGo",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	// Archived transfers still exist on BitGo, so include them to avoid false mismatches
+	localTransfers, err := s.transferRequestRepo.List(wallet.ID, true, reconcileWalletTransfersLimit, 0)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to list transfers", "details": err.Error()})
+		return
+	}
+
+	report := services.ReconcileTransfers(wallet.ID, localTransfers, bitgoTransfers.Transfers)
+	report.DryRun = dryRun
+
+	if !dryRun {
+		now := time.Now()
+		for i := range report.Drifts {
+			drift := &report.Drifts[i]
+			saved, err := drift.Save(s.transferRequestRepo, now)
+			if err != nil {
+				drift.Error = err.Error()
+				continue
+			}
+			if !saved {
+				drift.Error = "transfer status changed since it was read; reconcile again"
+				continue
+			}
+			drift.Corrected = true
+
+			s.notificationSvc.SendTransferStatusNotification(drift.Transfer(), drift.LocalStatus, drift.ExpectedStatus)
+		}
+	}
+
+	c.JSON(http.StatusOK, report)
+}
@@ -0,0 +1,73 @@
+package api
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"bitgo-wallets-api/internal/config"
+	"bitgo-wallets-api/internal/models"
+	"bitgo-wallets-api/internal/services"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+func TestSetRequiredApprovalsRequiresAdmin(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	wallet := &models.Wallet{ID: uuid.New(), Coin: "btc", WalletType: models.WalletTypeWarm}
+	server := &Server{config: &config.Config{AdminAPIKey: testAdminKey}, walletRepo: newMemWalletRepo(wallet)}
+	router := gin.New()
+	router.PUT("/admin/wallets/:id/required-approvals", server.requireAdmin(), server.setWalletRequiredApprovals)
+
+	path := "/admin/wallets/" + wallet.ID.String() + "/required-approvals"
+	override := 3
+	body := SetRequiredApprovalsRequest{RequiredApprovalsOverride: &override}
+
+	recorder := httptest.NewRecorder()
+	router.ServeHTTP(recorder, httptest.NewRequest(http.MethodPut, path, jsonBody(t, body)))
+	if recorder.Code != http.StatusUnauthorized {
+		t.Fatalf("without admin credentials: status = %d, want %d", recorder.Code, http.StatusUnauthorized)
+	}
+	if wallet.RequiredApprovalsOverride != nil {
+		t.Fatal("override was set without admin credentials")
+	}
+
+	request := httptest.NewRequest(http.MethodPut, path, jsonBody(t, body))
+	request.Header.Set("X-Admin-Key", testAdminKey)
+	recorder = httptest.NewRecorder()
+	router.ServeHTTP(recorder, request)
+	if recorder.Code != http.StatusOK {
+		t.Fatalf("as admin: status = %d, want %d: %s", recorder.Code, http.StatusOK, recorder.Body.String())
+	}
+
+	var updated models.Wallet
+	decodeJSON(t, recorder, &updated)
+	if updated.RequiredApprovalsOverride == nil || *updated.RequiredApprovalsOverride != override {
+		t.Errorf("RequiredApprovalsOverride = %v, want %d", updated.RequiredApprovalsOverride, override)
+	}
+}
+
+func TestFeatureFlagsRequireAdmin(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	server := &Server{
+		config:       &config.Config{AdminAPIKey: testAdminKey},
+		featureFlags: services.NewFeatureFlagStore(services.DefaultFeatureFlags()),
+	}
+	router := gin.New()
+	router.GET("/admin/feature-flags", server.requireAdmin(), server.getFeatureFlags)
+
+	recorder := httptest.NewRecorder()
+	router.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/admin/feature-flags", nil))
+	if recorder.Code != http.StatusUnauthorized {
+		t.Fatalf("without admin credentials: status = %d, want %d", recorder.Code, http.StatusUnauthorized)
+	}
+
+	request := httptest.NewRequest(http.MethodGet, "/admin/feature-flags", nil)
+	request.Header.Set("X-Admin-Key", testAdminKey)
+	recorder = httptest.NewRecorder()
+	router.ServeHTTP(recorder, request)
+	if recorder.Code != http.StatusOK {
+		t.Fatalf("as admin: status = %d, want %d: %s", recorder.Code, http.StatusOK, recorder.Body.String())
+	}
+}
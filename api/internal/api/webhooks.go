@@ -0,0 +1,54 @@
+package api
+
+import (
+	"errors"
+	"io"
+	"log"
+	"net/http"
+
+	"bitgo-wallets-api/internal/bitgo"
+	"bitgo-wallets-api/internal/webhooksig"
+
+	"github.com/gin-gonic/gin"
+)
+
+// receiveBitGoWebhook accepts a BitGo webhook notification. When a webhook secret is
+// configured the body must carry a valid signature, otherwise it is rejected with 401.
+// Malformed payloads are rejected with 400 and their redacted body logged so BitGo's shape
+// for a coin can be diagnosed.
+func (s *Server) receiveBitGoWebhook(c *gin.Context) {
+	body, err := io.ReadAll(c.Request.Body)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Failed to read request body", "details": err.Error()})
+		return
+	}
+
+	if secret := s.config.BitGoWebhookSecret; secret != "" {
+		if err := webhooksig.Verify(body, c.GetHeader(webhooksig.Header), secret); err != nil {
+			log.Printf("[WARN] Rejected BitGo webhook with bad signature: %v", err)
+			c.JSON(http.StatusUnauthorized, gin.H{"error": "Invalid webhook signature", "details": err.Error()})
+			return
+		}
+	}
+
+	payload, err := bitgo.DecodeWebhookPayload(body)
+	if err != nil {
+		log.Printf("[WARN] Rejected malformed BitGo webhook: %v body=%s", err, bitgo.RedactWebhookBody(body))
+
+		response := gin.H{"error": "Invalid webhook payload", "details": err.Error()}
+		var validationErr *bitgo.WebhookValidationError
+		if errors.As(err, &validationErr) {
+			response["field"] = validationErr.Field
+		}
+		c.JSON(http.StatusBadRequest, response)
+		return
+	}
+
+	log.Printf("[INFO] Received BitGo webhook type=%s wallet=%s coin=%s transfer=%s hash=%s pending_approval=%s",
+		payload.Type, payload.WalletID, payload.Coin, payload.Transfer, payload.Hash, payload.PendingApprovalID)
+
+	c.JSON(http.StatusOK, gin.H{
+		"received": true,
+		"type":     payload.Type,
+	})
+}
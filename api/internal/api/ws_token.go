@@ -0,0 +1,107 @@
+package api
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// wsTokenTTL is how long a request-log WebSocket token can be used to connect
+const wsTokenTTL = 60 * time.Second
+
+// Token scopes are mixed into the signature so a token issued for one purpose can't be used
+// for another
+const (
+	wsTokenScope      = "ws:bitgo-requests"
+	adminSessionScope = "session:admin"
+)
+
+var (
+	ErrTokenMissing = errors.New("token is required")
+	ErrTokenInvalid = errors.New("token is invalid")
+	ErrTokenExpired = errors.New("token has expired")
+)
+
+// newTokenSecret returns the configured signing secret, or a random one when none is set.
+// A random secret invalidates outstanding tokens on restart, which for admin sessions means
+// signing in again.
+func newTokenSecret(configured string) []byte {
+	if configured != "" {
+		return []byte(configured)
+	}
+
+	secret := make([]byte, 32)
+	if _, err := rand.Read(secret); err != nil {
+		log.Fatalf("Failed to generate token secret: %v", err)
+	}
+	return secret
+}
+
+// signToken returns a token for scope of the form "<unix expiry>.<hex HMAC-SHA256>"
+func signToken(secret []byte, scope string, expiresAt time.Time) string {
+	expiry := strconv.FormatInt(expiresAt.Unix(), 10)
+	return expiry + "." + tokenSignature(secret, scope, expiry)
+}
+
+func tokenSignature(secret []byte, scope, expiry string) string {
+	mac := hmac.New(sha256.New, secret)
+	mac.Write([]byte(scope + ":" + expiry))
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// validateToken checks the token was signed for scope and hasn't expired at now
+func validateToken(secret []byte, scope, token string, now time.Time) error {
+	if token == "" {
+		return ErrTokenMissing
+	}
+
+	expiry, signature, ok := strings.Cut(token, ".")
+	if !ok {
+		return ErrTokenInvalid
+	}
+	expiresAt, err := strconv.ParseInt(expiry, 10, 64)
+	if err != nil {
+		return ErrTokenInvalid
+	}
+	if !hmac.Equal([]byte(signature), []byte(tokenSignature(secret, scope, expiry))) {
+		return ErrTokenInvalid
+	}
+	if !now.Before(time.Unix(expiresAt, 0)) {
+		return ErrTokenExpired
+	}
+
+	return nil
+}
+
+// createWSToken issues a short-lived token for connecting to the BitGo request-log WebSocket
+func (s *Server) createWSToken(c *gin.Context) {
+	expiresAt := time.Now().Add(wsTokenTTL)
+
+	c.JSON(http.StatusOK, gin.H{
+		"token":      signToken(s.tokenSecret, wsTokenScope, expiresAt),
+		"expires_at": expiresAt.UTC().Format(time.RFC3339),
+	})
+}
+
+// requireWSToken rejects WebSocket requests without a valid token in the token query
+// parameter. It runs before the upgrade so unauthenticated clients get a plain 401.
+func (s *Server) requireWSToken(c *gin.Context) bool {
+	if err := validateToken(s.tokenSecret, wsTokenScope, c.Query("token"), time.Now()); err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{
+			"error":   "Unauthorized",
+			"details": fmt.Sprintf("websocket %v; request one from POST /api/v1/auth/ws-token", err),
+		})
+		return false
+	}
+	return true
+}
@@ -0,0 +1,125 @@
+package api
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"bitgo-wallets-api/internal/config"
+
+	"github.com/gin-gonic/gin"
+)
+
+var testTokenSecret = []byte("test-token-secret")
+
+func TestValidateToken(t *testing.T) {
+	now := time.Now()
+
+	tests := []struct {
+		name  string
+		token string
+		scope string
+		want  error
+	}{
+		{name: "valid", token: signToken(testTokenSecret, wsTokenScope, now.Add(time.Minute)), scope: wsTokenScope},
+		{name: "expired", token: signToken(testTokenSecret, wsTokenScope, now.Add(-time.Second)), scope: wsTokenScope, want: ErrTokenExpired},
+		{name: "missing", token: "", scope: wsTokenScope, want: ErrTokenMissing},
+		{name: "malformed", token: "not-a-token", scope: wsTokenScope, want: ErrTokenInvalid},
+		{name: "other secret", token: signToken([]byte("other"), wsTokenScope, now.Add(time.Minute)), scope: wsTokenScope, want: ErrTokenInvalid},
+		{name: "other scope", token: signToken(testTokenSecret, wsTokenScope, now.Add(time.Minute)), scope: adminSessionScope, want: ErrTokenInvalid},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := validateToken(testTokenSecret, tt.scope, tt.token, now); !errors.Is(err, tt.want) {
+				t.Errorf("validateToken() = %v, want %v", err, tt.want)
+			}
+		})
+	}
+}
+
+func TestWSTokenRequiresAdminSession(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	server := &Server{config: &config.Config{}, tokenSecret: testTokenSecret}
+	router := gin.New()
+	router.POST("/auth/ws-token", server.requireAdmin(), server.createWSToken)
+
+	tests := []struct {
+		name          string
+		authorization string
+		want          int
+	}{
+		{name: "valid session", authorization: "Bearer " + signToken(testTokenSecret, adminSessionScope, time.Now().Add(time.Hour)), want: http.StatusOK},
+		{name: "expired session", authorization: "Bearer " + signToken(testTokenSecret, adminSessionScope, time.Now().Add(-time.Minute)), want: http.StatusUnauthorized},
+		{name: "websocket token as session", authorization: "Bearer " + signToken(testTokenSecret, wsTokenScope, time.Now().Add(time.Hour)), want: http.StatusUnauthorized},
+		{name: "missing", want: http.StatusUnauthorized},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			request := httptest.NewRequest(http.MethodPost, "/auth/ws-token", nil)
+			if tt.authorization != "" {
+				request.Header.Set("Authorization", tt.authorization)
+			}
+			recorder := httptest.NewRecorder()
+			router.ServeHTTP(recorder, request)
+			if recorder.Code != tt.want {
+				t.Errorf("status = %d, want %d: %s", recorder.Code, tt.want, recorder.Body.String())
+			}
+		})
+	}
+}
+
+func TestLoginIssuesAdminSession(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	server := &Server{
+		config:      &config.Config{AdminEmail: "admin@example.com", AdminPassword: "secret"},
+		tokenSecret: testTokenSecret,
+	}
+
+	recorder := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(recorder)
+	c.Request = httptest.NewRequest(http.MethodPost, "/auth/login", jsonBody(t, LoginRequest{Email: "admin@example.com", Password: "secret"}))
+	c.Request.Header.Set("Content-Type", "application/json")
+	server.login(c)
+	if recorder.Code != http.StatusOK {
+		t.Fatalf("status = %d, want %d: %s", recorder.Code, http.StatusOK, recorder.Body.String())
+	}
+
+	var response LoginResponse
+	decodeJSON(t, recorder, &response)
+	if err := validateToken(testTokenSecret, adminSessionScope, response.Token, time.Now()); err != nil {
+		t.Errorf("login token is not an admin session: %v", err)
+	}
+}
+
+func TestRequireWSToken(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	server := &Server{tokenSecret: testTokenSecret}
+
+	tests := []struct {
+		name  string
+		token string
+		want  bool
+	}{
+		{name: "valid", token: signToken(testTokenSecret, wsTokenScope, time.Now().Add(wsTokenTTL)), want: true},
+		{name: "expired", token: signToken(testTokenSecret, wsTokenScope, time.Now().Add(-time.Second)), want: false},
+		{name: "missing", token: "", want: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			recorder := httptest.NewRecorder()
+			c, _ := gin.CreateTestContext(recorder)
+			c.Request = httptest.NewRequest(http.MethodGet, "/ws/bitgo-requests?token="+tt.token, nil)
+			if got := server.requireWSToken(c); got != tt.want {
+				t.Errorf("requireWSToken() = %v, want %v", got, tt.want)
+			}
+			if !tt.want && recorder.Code != http.StatusUnauthorized {
+				t.Errorf("status = %d, want %d", recorder.Code, http.StatusUnauthorized)
+			}
+		})
+	}
+}
@@ -0,0 +1,59 @@
+package bitgo
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+)
+
+// BitGoAPI is the client surface the services and HTTP handlers depend on. *Client talks to
+// BitGo; *SimulatedClient fakes it so the transfer lifecycle can run without BitGo.
+type BitGoAPI interface {
+	GetEnterprise() string
+	GetCurrentUser(ctx context.Context) (*User, error)
+
+	ListWallets(ctx context.Context, opts WalletListOptions) (*WalletListResponse, error)
+	CreateWalletRaw(ctx context.Context, coin string, body map[string]interface{}) (*Wallet, error)
+	GetWallet(ctx context.Context, walletID, coin string) (*Wallet, error)
+	GetWalletBalance(ctx context.Context, walletID, coin string) (*WalletBalance, error)
+	GetWalletKeys(ctx context.Context, walletID, coin string) ([]Keychain, error)
+	GenerateAddress(ctx context.Context, walletID, coin string, options *AddressOptions) (*Address, error)
+
+	BuildTransfer(ctx context.Context, walletID, coin string, req BuildTransferRequest) (*BuildTransferResponse, error)
+	SubmitTransfer(ctx context.Context, walletID, coin string, req SubmitTransferRequest) (*SubmitTransferResponse, error)
+	GetTransfer(ctx context.Context, walletID, coin, transferID string) (*Transfer, error)
+	GetTransferBySequenceID(ctx context.Context, walletID, coin, sequenceID string) (*Transfer, error)
+	ListTransfers(ctx context.Context, walletID, coin string, options *TransferListOptions) (*TransferListResponse, error)
+
+	EstimateFee(ctx context.Context, coin string, numBlocks int) (*FeeEstimate, error)
+	GetUSDPrice(ctx context.Context, coin string) (float64, error)
+	ValidateAddress(ctx context.Context, coin, address string) (bool, error)
+}
+
+// requester performs raw requests for endpoints without a typed BitGoAPI method, such as
+// pending approvals. Mock clients don't need to implement it.
+type requester interface {
+	makeRequest(ctx context.Context, opts RequestOptions) (*http.Response, error)
+}
+
+// unsupportedRequester stands in for clients that can't make raw requests
+type unsupportedRequester struct{}
+
+func (unsupportedRequester) makeRequest(ctx context.Context, opts RequestOptions) (*http.Response, error) {
+	return nil, fmt.Errorf("%s %s is not supported by this BitGo client", opts.Method, opts.Path)
+}
+
+// requesterFor returns client's raw request support, if it has any
+func requesterFor(client BitGoAPI) requester {
+	if r, ok := client.(requester); ok {
+		return r
+	}
+	return unsupportedRequester{}
+}
+
+var (
+	_ BitGoAPI  = (*Client)(nil)
+	_ BitGoAPI  = (*SimulatedClient)(nil)
+	_ requester = (*Client)(nil)
+	_ requester = (*SimulatedClient)(nil)
+)
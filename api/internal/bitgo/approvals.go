@@ -102,14 +102,14 @@ type ListApprovalsResponse struct {
 
 // ApprovalService handles BitGo approval operations
 type ApprovalService struct {
-	client *Client
+	client requester
 	logger Logger
 }
 
 // NewApprovalService creates a new approval service
-func NewApprovalService(client *Client, logger Logger) *ApprovalService {
+func NewApprovalService(client BitGoAPI, logger Logger) *ApprovalService {
 	return &ApprovalService{
-		client: client,
+		client: requesterFor(client),
 		logger: logger,
 	}
 }
@@ -203,26 +203,58 @@ func (as *ApprovalService) GetApproval(ctx context.Context, approvalID string) (
 	return &approval, nil
 }
 
-// GetWalletApprovals gets pending approvals for a specific wallet
-func (as *ApprovalService) GetWalletApprovals(ctx context.Context, walletID, coin string) ([]ApprovalInfo, error) {
+const (
+	// approvalPageSize is how many pending approvals are requested per page
+	approvalPageSize = 100
+	// maxApprovalPages stops paging if BitGo keeps returning full pages
+	maxApprovalPages = 50
+)
+
+// scanWalletApprovals pages through the enterprise's pending transaction approvals, calling
+// visit for each one belonging to the wallet until visit returns false or the list is exhausted
+func (as *ApprovalService) scanWalletApprovals(ctx context.Context, walletID, coin string, visit func(approval ApprovalInfo) bool) error {
 	params := ListApprovalsParams{
 		Coin:  coin,
 		Type:  ApprovalTypeTransactionRequest,
 		State: ApprovalStatePending,
-		Limit: 100,
+		Limit: approvalPageSize,
 	}
 
-	response, err := as.ListPendingApprovals(ctx, params)
-	if err != nil {
-		return nil, err
+	for page := 0; page < maxApprovalPages; page++ {
+		response, err := as.ListPendingApprovals(ctx, params)
+		if err != nil {
+			return err
+		}
+
+		for _, approval := range response.Approvals {
+			if approval.WalletID == walletID && !visit(approval) {
+				return nil
+			}
+		}
+
+		params.Skip += len(response.Approvals)
+		if len(response.Approvals) < params.Limit || (response.Count > 0 && params.Skip >= response.Count) {
+			return nil
+		}
 	}
 
-	// Filter approvals for specific wallet
+	as.logger.Warn("Stopped paging pending approvals",
+		"wallet_id", walletID,
+		"coin", coin,
+		"pages", maxApprovalPages,
+	)
+	return nil
+}
+
+// GetWalletApprovals gets all pending approvals for a specific wallet, across pages
+func (as *ApprovalService) GetWalletApprovals(ctx context.Context, walletID, coin string) ([]ApprovalInfo, error) {
 	var walletApprovals []ApprovalInfo
-	for _, approval := range response.Approvals {
-		if approval.WalletID == walletID {
-			walletApprovals = append(walletApprovals, approval)
-		}
+	err := as.scanWalletApprovals(ctx, walletID, coin, func(approval ApprovalInfo) bool {
+		walletApprovals = append(walletApprovals, approval)
+		return true
+	})
+	if err != nil {
+		return nil, err
 	}
 
 	as.logger.Info("Retrieved wallet approvals",
@@ -343,22 +375,65 @@ func (as *ApprovalService) MapApprovalToUIStatus(approval *ApprovalInfo, current
 	return status
 }
 
+// FindTransferApproval returns the pending approval for a transfer, or nil if it has none
+func (as *ApprovalService) FindTransferApproval(ctx context.Context, walletID, coin, transferID string) (*ApprovalInfo, error) {
+	// Page through the wallet's pending approvals until one matches the transfer
+	var match *ApprovalInfo
+	err := as.scanWalletApprovals(ctx, walletID, coin, func(approval ApprovalInfo) bool {
+		if approval.Info.TransactionRequest != nil &&
+			approval.Info.TransactionRequest.TxRequestID == transferID {
+			match = &approval
+			return false
+		}
+		return true
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return match, nil
+}
+
 // GetTransferApprovalStatus gets approval status for a specific transfer
 func (as *ApprovalService) GetTransferApprovalStatus(ctx context.Context, walletID, coin, transferID string, currentUserID string) (*ApprovalStatus, error) {
-	// Get all pending approvals for the wallet
-	approvals, err := as.GetWalletApprovals(ctx, walletID, coin)
+	match, err := as.FindTransferApproval(ctx, walletID, coin, transferID)
 	if err != nil {
 		return nil, err
 	}
 
-	// Find approval matching the transfer
-	for _, approval := range approvals {
-		if approval.Info.TransactionRequest != nil &&
-			approval.Info.TransactionRequest.TxRequestID == transferID {
-			return as.MapApprovalToUIStatus(&approval, currentUserID), nil
-		}
+	// No pending approval found for this transfer
+	if match == nil {
+		return nil, nil
 	}
 
-	// No pending approval found for this transfer
-	return nil, nil
+	return as.MapApprovalToUIStatus(match, currentUserID), nil
+}
+
+// CancelApproval withdraws a pending approval. BitGo treats a rejection by the user who
+// created the approval as a cancellation.
+func (as *ApprovalService) CancelApproval(ctx context.Context, approvalID string) (*ApprovalInfo, error) {
+	path := fmt.Sprintf("/pendingapprovals/%s", approvalID)
+
+	resp, err := as.client.makeRequest(ctx, RequestOptions{
+		Method: "PUT",
+		Path:   path,
+		Body:   map[string]string{"state": string(ApprovalStateRejected)},
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to cancel approval %s: %w", approvalID, err)
+	}
+	defer resp.Body.Close()
+
+	var approval ApprovalInfo
+	if err := json.NewDecoder(resp.Body).Decode(&approval); err != nil {
+		return nil, fmt.Errorf("failed to decode approval response: %w", err)
+	}
+
+	as.logger.Info("Cancelled approval",
+		"approval_id", approvalID,
+		"state", approval.State,
+		"wallet_id", approval.WalletID,
+	)
+
+	return &approval, nil
 }
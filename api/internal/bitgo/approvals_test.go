@@ -0,0 +1,108 @@
+package bitgo
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"sync"
+	"testing"
+)
+
+// newApprovalsServer serves total pending approvals a page at a time, alternating between
+// wallet-1 and wallet-2. The approval at index i is for transaction request tx-i. It returns
+// the server and a func reporting how many pages were requested.
+func newApprovalsServer(t *testing.T, total int) (*httptest.Server, func() int) {
+	t.Helper()
+
+	var mu sync.Mutex
+	pages := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		pages++
+		mu.Unlock()
+
+		skip, _ := strconv.Atoi(r.URL.Query().Get("skip"))
+		limit, _ := strconv.Atoi(r.URL.Query().Get("limit"))
+		response := ListApprovalsResponse{Approvals: []ApprovalInfo{}, Count: total}
+		for i := skip; i < total && i < skip+limit; i++ {
+			response.Approvals = append(response.Approvals, ApprovalInfo{
+				ID:       fmt.Sprintf("approval-%d", i),
+				State:    ApprovalStatePending,
+				WalletID: fmt.Sprintf("wallet-%d", i%2+1),
+				Info: ApprovalDetails{TransactionRequest: &TransactionRequestInfo{
+					TxRequestID: fmt.Sprintf("tx-%d", i),
+				}},
+				ApprovalsRequired: 1,
+			})
+		}
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(response)
+	}))
+	t.Cleanup(server.Close)
+
+	return server, func() int {
+		mu.Lock()
+		defer mu.Unlock()
+		return pages
+	}
+}
+
+func TestGetTransferApprovalStatusPagesUntilMatch(t *testing.T) {
+	tests := []struct {
+		name       string
+		transferID string
+		wantID     string
+		wantPages  int
+	}{
+		{name: "first page", transferID: "tx-10", wantID: "approval-10", wantPages: 1},
+		{name: "second page", transferID: "tx-150", wantID: "approval-150", wantPages: 2},
+		{name: "other wallet's transfer", transferID: "tx-151", wantPages: 3},
+		{name: "no match", transferID: "tx-missing", wantPages: 3},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server, pages := newApprovalsServer(t, 250)
+			service := NewApprovalService(NewClient(Config{BaseURL: server.URL, AccessToken: "token"}, testLogger{}), testLogger{})
+
+			status, err := service.GetTransferApprovalStatus(context.Background(), "wallet-1", "btc", tt.transferID, "user-1")
+			if err != nil {
+				t.Fatalf("GetTransferApprovalStatus() error = %v", err)
+			}
+			if tt.wantID == "" {
+				if status != nil {
+					t.Errorf("GetTransferApprovalStatus() = %+v, want no approval", status)
+				}
+			} else if status == nil || status.ID != tt.wantID {
+				t.Errorf("GetTransferApprovalStatus() = %+v, want approval %s", status, tt.wantID)
+			}
+			if pages() != tt.wantPages {
+				t.Errorf("requested %d pages, want %d", pages(), tt.wantPages)
+			}
+		})
+	}
+}
+
+func TestGetWalletApprovalsCollectsEveryPage(t *testing.T) {
+	server, pages := newApprovalsServer(t, 250)
+	service := NewApprovalService(NewClient(Config{BaseURL: server.URL, AccessToken: "token"}, testLogger{}), testLogger{})
+
+	approvals, err := service.GetWalletApprovals(context.Background(), "wallet-1", "btc")
+	if err != nil {
+		t.Fatalf("GetWalletApprovals() error = %v", err)
+	}
+	if len(approvals) != 125 {
+		t.Errorf("GetWalletApprovals() returned %d approvals, want 125", len(approvals))
+	}
+	for _, approval := range approvals {
+		if approval.WalletID != "wallet-1" {
+			t.Errorf("approval %s is for %s, want wallet-1", approval.ID, approval.WalletID)
+		}
+	}
+	if pages() != 3 {
+		t.Errorf("requested %d pages, want 3", pages())
+	}
+}
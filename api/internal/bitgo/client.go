@@ -4,8 +4,10 @@ import (
 	"bytes"
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
+	"net"
 	"net/http"
 	"regexp"
 	"strings"
@@ -16,11 +18,23 @@ import (
 
 // Config holds BitGo client configuration
 type Config struct {
-	BaseURL     string
+	BaseURL     string // Defaults to the platform URL for Environment
+	Environment string // test or prod
 	AccessToken string
 	Enterprise  string
 	Timeout     time.Duration
 	MaxRetries  int
+
+	// Per-operation limits covering every attempt of the call; zero falls back to Timeout.
+	// Builds of large multi-recipient transactions can need longer than status lookups.
+	BuildTimeout  time.Duration
+	SubmitTimeout time.Duration
+	GetTimeout    time.Duration
+	ListTimeout   time.Duration
+
+	// RedactFields are redacted from logged request bodies on top of the secrets that always
+	// are, e.g. memos and other PII
+	RedactFields []string
 }
 
 // Logger interface for structured logging
@@ -37,14 +51,25 @@ type Client struct {
 	accessToken string
 	enterprise  string
 	httpClient  *http.Client
+	timeouts    operationTimeouts
 	logger      Logger
+
+	redactFields []string
+}
+
+// operationTimeouts bounds each kind of call to BitGo
+type operationTimeouts struct {
+	build, submit, get, list time.Duration
 }
 
 // APIError represents a BitGo API error response
 type APIError struct {
 	ErrorMsg    string `json:"error"`
 	Message     string `json:"message"`
+	Name        string `json:"name,omitempty"`
+	ErrorName   string `json:"errorName,omitempty"`
 	RequestID   string `json:"requestId,omitempty"`
+	NeedsOTP    bool   `json:"needsOTP,omitempty"`
 	StatusCode  int    `json:"-"`
 	RequestInfo string `json:"-"`
 }
@@ -56,6 +81,118 @@ func (e APIError) Error() string {
 	return fmt.Sprintf("BitGo API error (%d): %s", e.StatusCode, e.ErrorMsg)
 }
 
+// insufficientFundsNames are the BitGo error names reported when a wallet can't cover a transfer
+var insufficientFundsNames = map[string]bool{
+	"InsufficientBalance": true,
+	"InsufficientFunds":   true,
+}
+
+// IsInsufficientFunds reports whether BitGo rejected the request because the wallet lacks funds
+func (e APIError) IsInsufficientFunds() bool {
+	if insufficientFundsNames[e.Name] || insufficientFundsNames[e.ErrorName] {
+		return true
+	}
+
+	// Older endpoints only describe the problem in the message
+	for _, msg := range []string{e.ErrorMsg, e.Message} {
+		msg = strings.ToLower(msg)
+		if strings.Contains(msg, "insufficient balance") || strings.Contains(msg, "insufficient funds") {
+			return true
+		}
+	}
+	return false
+}
+
+// IsInsufficientFunds reports whether err is a BitGo insufficient-funds error
+func IsInsufficientFunds(err error) bool {
+	var apiErr APIError
+	return errors.As(err, &apiErr) && apiErr.IsInsufficientFunds()
+}
+
+// IsOTPRequired reports whether BitGo rejected the request until a one-time password is supplied
+func (e APIError) IsOTPRequired() bool {
+	if e.NeedsOTP {
+		return true
+	}
+
+	// Session unlock failures only say so in the message
+	for _, msg := range []string{e.ErrorMsg, e.Message} {
+		msg = strings.ToLower(msg)
+		if strings.Contains(msg, "needs unlock") || strings.Contains(msg, "otp required") {
+			return true
+		}
+	}
+	return false
+}
+
+// IsOTPRequired reports whether err is a BitGo OTP-required error
+func IsOTPRequired(err error) bool {
+	var apiErr APIError
+	return errors.As(err, &apiErr) && apiErr.IsOTPRequired()
+}
+
+// alreadySubmittedNames are the BitGo error names reported when a transaction was submitted before
+var alreadySubmittedNames = map[string]bool{
+	"DuplicateTransaction":        true,
+	"TransactionAlreadySubmitted": true,
+	"TransactionAlreadyExists":    true,
+}
+
+// alreadySubmittedMessages are what nodes and older endpoints say about a transaction they
+// have already accepted
+var alreadySubmittedMessages = []string{
+	"already submitted",
+	"already broadcast",
+	"already known",
+	"txn-already-known",
+	"transaction already in block chain",
+}
+
+// IsAlreadySubmitted reports whether BitGo rejected a submit because the same transaction was
+// already submitted, which means an earlier submit went through
+func (e APIError) IsAlreadySubmitted() bool {
+	if alreadySubmittedNames[e.Name] || alreadySubmittedNames[e.ErrorName] {
+		return true
+	}
+
+	for _, msg := range []string{e.ErrorMsg, e.Message} {
+		msg = strings.ToLower(msg)
+		for _, known := range alreadySubmittedMessages {
+			if strings.Contains(msg, known) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// IsAlreadySubmitted reports whether err is a BitGo duplicate-submission error
+func IsAlreadySubmitted(err error) bool {
+	var apiErr APIError
+	return errors.As(err, &apiErr) && apiErr.IsAlreadySubmitted()
+}
+
+// IsNotFound reports whether err is a BitGo 404, e.g. an id BitGo has no record of
+func IsNotFound(err error) bool {
+	var apiErr APIError
+	return errors.As(err, &apiErr) && apiErr.StatusCode == http.StatusNotFound
+}
+
+// IsTimeout reports whether err is a request to BitGo that timed out before an answer arrived
+func IsTimeout(err error) bool {
+	if errors.Is(err, context.DeadlineExceeded) {
+		return true
+	}
+	var netErr net.Error
+	return errors.As(err, &netErr) && netErr.Timeout()
+}
+
+// maxResponseBodySize caps how much of a BitGo response body we are willing to read
+const maxResponseBodySize = 10 << 20
+
+// ErrEmptyResponse is returned when BitGo answers a request with no body
+var ErrEmptyResponse = errors.New("empty response body")
+
 // RequestOptions holds options for API requests
 type RequestOptions struct {
 	Method         string
@@ -63,12 +200,14 @@ type RequestOptions struct {
 	Body           interface{}
 	Headers        map[string]string
 	IdempotencyKey string
+	// AccessToken overrides the client's token for this request only. It is never logged.
+	AccessToken string
 }
 
 // NewClient creates a new BitGo API client
 func NewClient(config Config, logger Logger) *Client {
 	if config.BaseURL == "" {
-		config.BaseURL = "https://app.bitgo-test.com"
+		config.BaseURL = BaseURLForEnvironment(config.Environment)
 	}
 	if config.Timeout == 0 {
 		config.Timeout = 30 * time.Second
@@ -77,17 +216,50 @@ func NewClient(config Config, logger Logger) *Client {
 		config.MaxRetries = 3
 	}
 
+	timeouts := operationTimeouts{
+		build:  durationOr(config.BuildTimeout, config.Timeout),
+		submit: durationOr(config.SubmitTimeout, config.Timeout),
+		get:    durationOr(config.GetTimeout, config.Timeout),
+		list:   durationOr(config.ListTimeout, config.Timeout),
+	}
+
+	// The per-attempt HTTP timeout has to allow the slowest operation; each operation's own
+	// limit is then applied through its context
+	httpTimeout := config.Timeout
+	for _, timeout := range []time.Duration{timeouts.build, timeouts.submit, timeouts.get, timeouts.list} {
+		if timeout > httpTimeout {
+			httpTimeout = timeout
+		}
+	}
+
 	return &Client{
 		baseURL:     config.BaseURL,
 		accessToken: config.AccessToken,
 		enterprise:  config.Enterprise,
 		httpClient: &http.Client{
-			Timeout: config.Timeout,
+			Timeout: httpTimeout,
 		},
-		logger: logger,
+		timeouts:     timeouts,
+		logger:       logger,
+		redactFields: config.RedactFields,
 	}
 }
 
+func durationOr(value, fallback time.Duration) time.Duration {
+	if value > 0 {
+		return value
+	}
+	return fallback
+}
+
+// withOperationTimeout bounds ctx by an operation's timeout
+func withOperationTimeout(ctx context.Context, timeout time.Duration) (context.Context, context.CancelFunc) {
+	if timeout <= 0 {
+		return context.WithCancel(ctx)
+	}
+	return context.WithTimeout(ctx, timeout)
+}
+
 // GetEnterprise returns the enterprise ID
 func (c *Client) GetEnterprise() string {
 	return c.enterprise
@@ -112,11 +284,13 @@ func (c *Client) makeRequest(ctx context.Context, opts RequestOptions) (*http.Re
 	// Redact sensitive information for logging
 	logBody := c.redactSensitiveFields(opts.Body)
 	url := c.baseURL + "/api/v2" + opts.Path
+	accessToken := c.accessTokenFor(ctx, opts)
 	c.logger.Info("Making BitGo API request",
 		"method", opts.Method,
 		"url", c.redactURL(url),
 		"correlation_id", correlationID,
 		"body", logBody,
+		"token_override", accessToken != c.accessToken,
 	)
 
 	req, err := http.NewRequestWithContext(ctx, opts.Method, url, bodyReader)
@@ -125,7 +299,7 @@ func (c *Client) makeRequest(ctx context.Context, opts RequestOptions) (*http.Re
 	}
 
 	// Set authentication headers
-	req.Header.Set("Authorization", "Bearer "+c.accessToken)
+	req.Header.Set("Authorization", "Bearer "+accessToken)
 	req.Header.Set("Content-Type", "application/json")
 	req.Header.Set("User-Agent", "bitgo-wallets-api/1.0")
 	req.Header.Set("X-Correlation-ID", correlationID)
@@ -182,6 +356,10 @@ func (c *Client) doWithRetry(req *http.Request, correlationID string) (*http.Res
 				resp.Body.Close()
 			}
 			if attempt < maxRetries {
+				// The operation's timeout covers every attempt, so don't retry past it
+				if ctxErr := req.Context().Err(); ctxErr != nil {
+					return nil, fmt.Errorf("HTTP request failed: %w", ctxErr)
+				}
 				delay := time.Duration(attempt+1) * baseDelay
 				c.logger.Warn("Retrying BitGo API request",
 					"attempt", attempt+1,
@@ -189,8 +367,12 @@ func (c *Client) doWithRetry(req *http.Request, correlationID string) (*http.Res
 					"error", err,
 					"correlation_id", correlationID,
 				)
-				time.Sleep(delay)
-				continue
+				select {
+				case <-time.After(delay):
+					continue
+				case <-req.Context().Done():
+					return nil, fmt.Errorf("HTTP request failed: %w", req.Context().Err())
+				}
 			}
 		}
 
@@ -209,6 +391,35 @@ func (c *Client) doWithRetry(req *http.Request, correlationID string) (*http.Res
 	return nil, fmt.Errorf("max retries exceeded")
 }
 
+// decodeResponse checks the response status, reads the body up to maxResponseBodySize and
+// decodes it into v. Non-2xx responses are returned as APIError.
+func (c *Client) decodeResponse(resp *http.Response, v interface{}) error {
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		correlationID := ""
+		if resp.Request != nil {
+			correlationID = resp.Request.Header.Get("X-Correlation-ID")
+		}
+		return c.parseAPIError(resp, correlationID)
+	}
+
+	body, err := io.ReadAll(io.LimitReader(resp.Body, maxResponseBodySize+1))
+	if err != nil {
+		return fmt.Errorf("failed to read response body: %w", err)
+	}
+	if len(body) > maxResponseBodySize {
+		return fmt.Errorf("response body exceeds %d bytes", maxResponseBodySize)
+	}
+	if len(bytes.TrimSpace(body)) == 0 {
+		return fmt.Errorf("unexpected status %d: %w", resp.StatusCode, ErrEmptyResponse)
+	}
+
+	if err := json.Unmarshal(body, v); err != nil {
+		return fmt.Errorf("failed to unmarshal response: %w", err)
+	}
+
+	return nil
+}
+
 // shouldRetry determines if a request should be retried
 func (c *Client) shouldRetry(resp *http.Response, attempt, maxRetries int) bool {
 	if attempt >= maxRetries {
@@ -265,7 +476,14 @@ func (c *Client) parseAPIError(resp *http.Response, correlationID string) error
 	return apiErr
 }
 
-// redactSensitiveFields removes sensitive information from request bodies for logging
+// sensitiveFields lists the fields that must never be logged or returned to API clients
+var sensitiveFields = []string{
+	"passphrase", "password", "otp", "backup", "recoveryXpub",
+	"userKey", "backupKey", "bitgoKey", "prv", "encryptedPrv",
+}
+
+// redactSensitiveFields removes sensitive information, and the client's extra redacted
+// fields, from request bodies for logging
 func (c *Client) redactSensitiveFields(body interface{}) interface{} {
 	if body == nil {
 		return nil
@@ -282,19 +500,7 @@ func (c *Client) redactSensitiveFields(body interface{}) interface{} {
 		return "[REDACTION_ERROR]"
 	}
 
-	// List of fields to redact
-	sensitiveFields := []string{
-		"passphrase", "password", "otp", "backup", "recoveryXpub",
-		"userKey", "backupKey", "bitgoKey", "prv", "encryptedPrv",
-	}
-
-	for _, field := range sensitiveFields {
-		if _, exists := data[field]; exists {
-			data[field] = "[REDACTED]"
-		}
-	}
-
-	return data
+	return redactValue(data, c.redactFields)
 }
 
 // redactURL removes sensitive information from URLs for logging
@@ -314,24 +520,25 @@ func (c *Client) redactURL(url string) string {
 	return url
 }
 
-// ValidateAddress validates if a blockchain address is valid using BitGo API
-func (c *Client) ValidateAddress(ctx context.Context, address string) (bool, error) {
+// ValidateAddress checks that an address is well-formed for the coin. BitGo validates
+// addresses client-side in its SDK rather than over the REST API, so this mirrors that
+// check locally; an empty coin applies the generic format check.
+func (c *Client) ValidateAddress(ctx context.Context, coin, address string) (bool, error) {
+	return ValidAddressForCoin(coin, address), nil
+}
+
+// validAddressFormat does a basic format check of bitcoin and ethereum addresses
+func validAddressFormat(address string) bool {
 	// Simple regex validation first - this is a basic check
 	// Bitcoin addresses typically start with 1, 3, or bc1
 	// Ethereum addresses start with 0x and are 42 characters long
 	if len(address) < 26 {
-		return false, nil
+		return false
 	}
 
 	// Basic format validation
 	bitcoinRegex := regexp.MustCompile(`^(1|3|bc1)[a-zA-Z0-9]{25,62}$`)
 	ethereumRegex := regexp.MustCompile(`^0x[a-fA-F0-9]{40}$`)
 
-	if bitcoinRegex.MatchString(address) || ethereumRegex.MatchString(address) {
-		return true, nil
-	}
-
-	// If basic regex doesn't match, return false
-	// In a real implementation, you might want to call BitGo's address validation API
-	return false, nil
+	return bitcoinRegex.MatchString(address) || ethereumRegex.MatchString(address)
 }
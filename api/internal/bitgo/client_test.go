@@ -0,0 +1,186 @@
+package bitgo
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+// testLogger discards log output
+type testLogger struct{}
+
+func (testLogger) Info(string, ...interface{})  {}
+func (testLogger) Warn(string, ...interface{})  {}
+func (testLogger) Error(string, ...interface{}) {}
+func (testLogger) Debug(string, ...interface{}) {}
+
+func TestDecodeResponse(t *testing.T) {
+	client := NewClient(Config{Environment: "test"}, testLogger{})
+	response := func(status int, body string) *http.Response {
+		return &http.Response{StatusCode: status, Body: io.NopCloser(strings.NewReader(body))}
+	}
+
+	t.Run("success", func(t *testing.T) {
+		var wallet Wallet
+		if err := client.decodeResponse(response(http.StatusOK, `{"id":"wallet-1","coin":"btc"}`), &wallet); err != nil {
+			t.Fatalf("decodeResponse() error = %v", err)
+		}
+		if wallet.ID != "wallet-1" || wallet.Coin != "btc" {
+			t.Errorf("decoded %+v, want wallet-1 on btc", wallet)
+		}
+	})
+
+	t.Run("server error", func(t *testing.T) {
+		var wallet Wallet
+		err := client.decodeResponse(response(http.StatusInternalServerError, `{"error":"internal error","name":"ServerError"}`), &wallet)
+		var apiErr APIError
+		if !errors.As(err, &apiErr) {
+			t.Fatalf("decodeResponse() error = %v, want an APIError", err)
+		}
+		if apiErr.StatusCode != http.StatusInternalServerError || apiErr.ErrorMsg != "internal error" {
+			t.Errorf("APIError = %+v, want status 500 with the body's error", apiErr)
+		}
+	})
+
+	for _, status := range []int{http.StatusOK, http.StatusNoContent} {
+		t.Run("empty body "+http.StatusText(status), func(t *testing.T) {
+			var wallet Wallet
+			if err := client.decodeResponse(response(status, " \n"), &wallet); !errors.Is(err, ErrEmptyResponse) {
+				t.Errorf("decodeResponse() error = %v, want %v", err, ErrEmptyResponse)
+			}
+		})
+	}
+}
+
+func TestIsInsufficientFunds(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		want bool
+	}{
+		{name: "name", err: APIError{StatusCode: http.StatusBadRequest, Name: "InsufficientBalance"}, want: true},
+		{name: "error name", err: APIError{StatusCode: http.StatusBadRequest, ErrorName: "InsufficientFunds"}, want: true},
+		{name: "message only", err: APIError{StatusCode: http.StatusBadRequest, ErrorMsg: "Insufficient balance for transfer"}, want: true},
+		{name: "wrapped", err: fmt.Errorf("failed to build transfer: %w", APIError{Name: "InsufficientBalance"}), want: true},
+		{name: "other API error", err: APIError{StatusCode: http.StatusBadRequest, Name: "InvalidAddress", Message: "invalid address"}},
+		{name: "not an API error", err: errors.New("insufficient funds")},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := IsInsufficientFunds(tt.err); got != tt.want {
+				t.Errorf("IsInsufficientFunds(%v) = %v, want %v", tt.err, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestIsOTPRequired(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		want bool
+	}{
+		{name: "needsOTP flag", err: APIError{StatusCode: http.StatusUnauthorized, NeedsOTP: true}, want: true},
+		{name: "needs unlock message", err: APIError{StatusCode: http.StatusUnauthorized, ErrorMsg: "needs unlock"}, want: true},
+		{name: "otp required message", err: APIError{StatusCode: http.StatusUnauthorized, Message: "OTP required"}, want: true},
+		{name: "wrapped", err: fmt.Errorf("failed to submit transfer: %w", APIError{NeedsOTP: true}), want: true},
+		{name: "other API error", err: APIError{StatusCode: http.StatusUnauthorized, ErrorMsg: "unauthorized"}},
+		{name: "not an API error", err: errors.New("otp required")},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := IsOTPRequired(tt.err); got != tt.want {
+				t.Errorf("IsOTPRequired(%v) = %v, want %v", tt.err, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestBuildTimeoutBoundsOnlyBuilds(t *testing.T) {
+	release := make(chan struct{})
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if strings.HasSuffix(r.URL.Path, "/tx/build") {
+			// Slower than the build timeout allows
+			select {
+			case <-r.Context().Done():
+			case <-release:
+			}
+			return
+		}
+		// Slower than the build timeout but well within the default
+		time.Sleep(100 * time.Millisecond)
+		w.Header().Set("Content-Type", "application/json")
+		fmt.Fprint(w, `{"id":"transfer-1","coin":"tbtc","wallet":"wallet-1","state":"confirmed"}`)
+	}))
+	defer server.Close()
+	defer close(release)
+
+	client := NewClient(Config{BaseURL: server.URL, AccessToken: "token", Timeout: 5 * time.Second, MaxRetries: 1, BuildTimeout: 50 * time.Millisecond}, testLogger{})
+
+	start := time.Now()
+	_, err := client.BuildTransfer(context.Background(), "wallet-1", "tbtc", BuildTransferRequest{
+		Recipients: []TransferRecipient{{Address: "2N8hwP1WmJrFF5QWABn38y63uYLhnJYJYTF", AmountString: "1000"}},
+	})
+	if !IsTimeout(err) {
+		t.Errorf("BuildTransfer() error = %v, want a timeout", err)
+	}
+	if elapsed := time.Since(start); elapsed > time.Second {
+		t.Errorf("BuildTransfer() took %v, want it cut off by the 50ms build timeout", elapsed)
+	}
+
+	transfer, err := client.GetTransfer(context.Background(), "wallet-1", "tbtc", "transfer-1")
+	if err != nil {
+		t.Fatalf("GetTransfer() error = %v, want the default timeout to allow it", err)
+	}
+	if transfer.ID != "transfer-1" {
+		t.Errorf("GetTransfer() ID = %q, want transfer-1", transfer.ID)
+	}
+}
+
+func TestGenerateAddressDefaultsAddressType(t *testing.T) {
+	var body map[string]interface{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body = nil
+		if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+			t.Errorf("decode request body: %v", err)
+		}
+		w.Header().Set("Content-Type", "application/json")
+		fmt.Fprint(w, `{"id":"address-1","address":"addr"}`)
+	}))
+	defer server.Close()
+	client := NewClient(Config{BaseURL: server.URL, AccessToken: "token"}, testLogger{})
+
+	chain := 10
+	tests := []struct {
+		name     string
+		coin     string
+		options  *AddressOptions
+		wantType interface{} // nil when no addressType is sent
+	}{
+		{name: "btc default", coin: "btc", wantType: AddressTypeP2WSH},
+		{name: "ltc default", coin: "tltc", options: &AddressOptions{Label: "deposits"}, wantType: AddressTypeP2WSH},
+		{name: "requested type kept", coin: "btc", options: &AddressOptions{AddressType: AddressTypeP2TR}, wantType: AddressTypeP2TR},
+		{name: "explicit chain", coin: "btc", options: &AddressOptions{Chain: &chain}},
+		{name: "account-based coin", coin: "eth"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := client.GenerateAddress(context.Background(), "wallet-1", tt.coin, tt.options); err != nil {
+				t.Fatalf("GenerateAddress() error = %v", err)
+			}
+			if got := body["addressType"]; got != tt.wantType {
+				t.Errorf("addressType = %v, want %v", got, tt.wantType)
+			}
+		})
+	}
+}
@@ -0,0 +1,124 @@
+package bitgo
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// EVMCoinSpecific is the nonce and gas detail BitGo reports under coinSpecific for EVM
+// coins. Gas values are decimal strings in wei so large values keep their precision.
+type EVMCoinSpecific struct {
+	Nonce                *uint64 `json:"nonce,omitempty"`
+	GasLimit             string  `json:"gasLimit,omitempty"`
+	GasPrice             string  `json:"gasPrice,omitempty"` // Legacy transactions
+	MaxFeePerGas         string  `json:"maxFeePerGas,omitempty"`
+	MaxPriorityFeePerGas string  `json:"maxPriorityFeePerGas,omitempty"`
+}
+
+// IsEVMCoin reports whether the coin is account-based with EVM nonces and gas
+func IsEVMCoin(coin string) bool {
+	info, ok := LookupCoin(coin)
+	return ok && info.Family == "eth"
+}
+
+// DecodeEVMCoinSpecific reads the known EVM fields from a coinSpecific object. Numbers may
+// be JSON numbers, decimal strings or 0x-prefixed hex strings; EIP-1559 fees may sit at the
+// top level or under eip1559. It returns nil when none of the fields are present.
+func DecodeEVMCoinSpecific(raw json.RawMessage) (*EVMCoinSpecific, error) {
+	if len(bytes.TrimSpace(raw)) == 0 || string(bytes.TrimSpace(raw)) == "null" {
+		return nil, nil
+	}
+
+	var fields struct {
+		Nonce                json.RawMessage `json:"nonce"`
+		GasLimit             json.RawMessage `json:"gasLimit"`
+		GasPrice             json.RawMessage `json:"gasPrice"`
+		MaxFeePerGas         json.RawMessage `json:"maxFeePerGas"`
+		MaxPriorityFeePerGas json.RawMessage `json:"maxPriorityFeePerGas"`
+		EIP1559              *struct {
+			MaxFeePerGas         json.RawMessage `json:"maxFeePerGas"`
+			MaxPriorityFeePerGas json.RawMessage `json:"maxPriorityFeePerGas"`
+		} `json:"eip1559"`
+	}
+	if err := json.Unmarshal(raw, &fields); err != nil {
+		return nil, fmt.Errorf("failed to decode coinSpecific: %w", err)
+	}
+
+	decoded := &EVMCoinSpecific{}
+	var err error
+	if decoded.GasLimit, err = evmQuantity("gasLimit", fields.GasLimit); err != nil {
+		return nil, err
+	}
+	if decoded.GasPrice, err = evmQuantity("gasPrice", fields.GasPrice); err != nil {
+		return nil, err
+	}
+	maxFee, maxPriorityFee := fields.MaxFeePerGas, fields.MaxPriorityFeePerGas
+	if fields.EIP1559 != nil {
+		if len(maxFee) == 0 {
+			maxFee = fields.EIP1559.MaxFeePerGas
+		}
+		if len(maxPriorityFee) == 0 {
+			maxPriorityFee = fields.EIP1559.MaxPriorityFeePerGas
+		}
+	}
+	if decoded.MaxFeePerGas, err = evmQuantity("maxFeePerGas", maxFee); err != nil {
+		return nil, err
+	}
+	if decoded.MaxPriorityFeePerGas, err = evmQuantity("maxPriorityFeePerGas", maxPriorityFee); err != nil {
+		return nil, err
+	}
+
+	nonce, err := evmQuantity("nonce", fields.Nonce)
+	if err != nil {
+		return nil, err
+	}
+	if nonce != "" {
+		value, err := strconv.ParseUint(nonce, 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("coinSpecific nonce %s is out of range", nonce)
+		}
+		decoded.Nonce = &value
+	}
+
+	if *decoded == (EVMCoinSpecific{}) {
+		return nil, nil
+	}
+	return decoded, nil
+}
+
+// evmQuantity normalizes a JSON number or string quantity to a decimal string, or "" when absent
+func evmQuantity(field string, raw json.RawMessage) (string, error) {
+	raw = bytes.TrimSpace(raw)
+	if len(raw) == 0 || string(raw) == "null" {
+		return "", nil
+	}
+
+	value := string(raw)
+	if raw[0] == '"' {
+		if err := json.Unmarshal(raw, &value); err != nil {
+			return "", fmt.Errorf("coinSpecific %s is not a valid string", field)
+		}
+		value = strings.TrimSpace(value)
+		if value == "" {
+			return "", nil
+		}
+	}
+
+	if hex := strings.TrimPrefix(strings.TrimPrefix(value, "0x"), "0X"); hex != value {
+		parsed, err := strconv.ParseUint(hex, 16, 64)
+		if err != nil {
+			return "", fmt.Errorf("coinSpecific %s %q is not a valid hex quantity", field, value)
+		}
+		return strconv.FormatUint(parsed, 10), nil
+	}
+
+	for _, r := range value {
+		if r < '0' || r > '9' {
+			return "", fmt.Errorf("coinSpecific %s %q is not a non-negative integer", field, value)
+		}
+	}
+	return value, nil
+}
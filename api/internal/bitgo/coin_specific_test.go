@@ -0,0 +1,86 @@
+package bitgo
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestDecodeEVMCoinSpecific(t *testing.T) {
+	nonce := func(n uint64) *uint64 { return &n }
+
+	tests := []struct {
+		name    string
+		raw     string
+		want    *EVMCoinSpecific
+		wantErr bool
+	}{
+		{
+			name: "legacy numbers",
+			raw:  `{"nonce":7,"gasLimit":21000,"gasPrice":30000000000}`,
+			want: &EVMCoinSpecific{Nonce: nonce(7), GasLimit: "21000", GasPrice: "30000000000"},
+		},
+		{
+			name: "hex and decimal strings",
+			raw:  `{"nonce":"0x1a","gasLimit":"0x5208","maxFeePerGas":" 40000000000 ","maxPriorityFeePerGas":"0x77359400"}`,
+			want: &EVMCoinSpecific{Nonce: nonce(26), GasLimit: "21000", MaxFeePerGas: "40000000000", MaxPriorityFeePerGas: "2000000000"},
+		},
+		{
+			name: "fees under eip1559",
+			raw:  `{"nonce":0,"eip1559":{"maxFeePerGas":"40000000000","maxPriorityFeePerGas":"2000000000"}}`,
+			want: &EVMCoinSpecific{Nonce: nonce(0), MaxFeePerGas: "40000000000", MaxPriorityFeePerGas: "2000000000"},
+		},
+		{
+			name: "top-level fees win over eip1559",
+			raw:  `{"maxFeePerGas":"50","eip1559":{"maxFeePerGas":"40","maxPriorityFeePerGas":"2"}}`,
+			want: &EVMCoinSpecific{MaxFeePerGas: "50", MaxPriorityFeePerGas: "2"},
+		},
+		{name: "no known fields", raw: `{"isBatch":false}`},
+		{name: "empty strings", raw: `{"gasPrice":"","nonce":null}`},
+		{name: "null", raw: `null`},
+		{name: "absent", raw: ``},
+		{name: "negative gas limit", raw: `{"gasLimit":-1}`, wantErr: true},
+		{name: "fractional gas price", raw: `{"gasPrice":"1.5"}`, wantErr: true},
+		{name: "bad hex nonce", raw: `{"nonce":"0xzz"}`, wantErr: true},
+		{name: "not an object", raw: `[1,2]`, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := DecodeEVMCoinSpecific(json.RawMessage(tt.raw))
+			if tt.wantErr {
+				if err == nil {
+					t.Errorf("DecodeEVMCoinSpecific() = %+v, want an error", got)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("DecodeEVMCoinSpecific() error = %v", err)
+			}
+			if tt.want == nil {
+				if got != nil {
+					t.Errorf("DecodeEVMCoinSpecific() = %+v, want nil", got)
+				}
+				return
+			}
+			if got == nil {
+				t.Fatalf("DecodeEVMCoinSpecific() = nil, want %+v", tt.want)
+			}
+			if (got.Nonce == nil) != (tt.want.Nonce == nil) || got.Nonce != nil && *got.Nonce != *tt.want.Nonce {
+				t.Errorf("nonce = %v, want %v", got.Nonce, tt.want.Nonce)
+			}
+			gotFees, wantFees := *got, *tt.want
+			gotFees.Nonce, wantFees.Nonce = nil, nil
+			if gotFees != wantFees {
+				t.Errorf("DecodeEVMCoinSpecific() = %+v, want %+v", gotFees, wantFees)
+			}
+		})
+	}
+}
+
+func TestIsEVMCoin(t *testing.T) {
+	for coin, want := range map[string]bool{"eth": true, "teth": true, "btc": false, "xrp": false, "unknown": false} {
+		if got := IsEVMCoin(coin); got != want {
+			t.Errorf("IsEVMCoin(%q) = %v, want %v", coin, got, want)
+		}
+	}
+}
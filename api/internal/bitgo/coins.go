@@ -0,0 +1,447 @@
+package bitgo
+
+import (
+	"fmt"
+	"net/url"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// CoinInfo describes coin-specific behavior that affects how transfers are built
+type CoinInfo struct {
+	Symbol  string `json:"symbol"`
+	Name    string `json:"name"`
+	Family  string `json:"family"`
+	Testnet bool   `json:"testnet"`
+
+	// Transaction types accepted by the build endpoint, in addition to send
+	BuildTypes []string `json:"buildTypes"`
+
+	// Confirmations needed before a transfer is treated as final
+	RequiredConfirmations int `json:"requiredConfirmations"`
+
+	// Digits after the decimal point in the coin's smallest unit
+	Decimals int `json:"decimals"`
+
+	// Smallest output, in base units, the network relays; zero for account-based coins
+	DustThreshold int64 `json:"dustThreshold,omitempty"`
+
+	// Address type requested for new receive addresses when the caller doesn't pick one.
+	// Empty for account-based coins, which have a single address format.
+	DefaultAddressType string `json:"defaultAddressType,omitempty"`
+
+	// Destination memo/tag handling
+	MemoRequired bool           `json:"memoRequired"`
+	MemoLabel    string         `json:"memoLabel,omitempty"`
+	MemoFormat   string         `json:"memoFormat,omitempty"`
+	memoPattern  *regexp.Regexp `json:"-"`
+
+	// addressPattern matches well-formed addresses of the coin on its own network
+	addressPattern *regexp.Regexp `json:"-"`
+}
+
+// Address types accepted by BitGo's address endpoint for UTXO coins
+const (
+	AddressTypeP2SH      = "p2sh"
+	AddressTypeP2SHP2WSH = "p2shP2wsh"
+	AddressTypeP2WSH     = "p2wsh"
+	AddressTypeP2TR      = "p2tr"
+)
+
+// Build types understood by BitGo's tx/build endpoint
+const (
+	BuildTypeSend         = "send"
+	BuildTypeFillNonce    = "fillNonce"
+	BuildTypeAcceleration = "acceleration"
+)
+
+var evmBuildTypes = []string{BuildTypeSend, BuildTypeFillNonce, BuildTypeAcceleration}
+
+// utxoDustThreshold is the dust limit at the default relay fee shared by Bitcoin and Litecoin
+const utxoDustThreshold = 546
+
+// defaultRequiredConfirmations applies to coins that aren't in the registry
+const defaultRequiredConfirmations = 1
+
+// maxAmountDecimals bounds the precision accepted for coins that aren't in the registry
+const maxAmountDecimals = 18
+
+// amountPattern matches a plain non-negative decimal: no sign, exponent, or thousands separators
+var amountPattern = regexp.MustCompile(`^[0-9]+(\.[0-9]+)?$`)
+
+var (
+	btcAddress  = regexp.MustCompile(`^([13][1-9A-HJ-NP-Za-km-z]{25,34}|(bc1|BC1)[a-zA-Z0-9]{11,71})$`)
+	tbtcAddress = regexp.MustCompile(`^([mn2][1-9A-HJ-NP-Za-km-z]{25,34}|(tb1|TB1)[a-zA-Z0-9]{11,71})$`)
+	ltcAddress  = regexp.MustCompile(`^([LM3][1-9A-HJ-NP-Za-km-z]{25,34}|(ltc1|LTC1)[a-zA-Z0-9]{11,71})$`)
+	tltcAddress = regexp.MustCompile(`^([mnQ2][1-9A-HJ-NP-Za-km-z]{25,34}|(tltc1|TLTC1)[a-zA-Z0-9]{11,71})$`)
+	ethAddress  = regexp.MustCompile(`^0x[0-9a-fA-F]{40}$`)
+	xrpAddress  = regexp.MustCompile(`^r[1-9A-HJ-NP-Za-km-z]{24,34}(\?dt=[0-9]+)?$`)
+	xlmAddress  = regexp.MustCompile(`^G[A-Z2-7]{55}(\?memoId=[^&]*)?$`)
+	eosAddress  = regexp.MustCompile(`^[a-z1-5.]{1,12}(\?memoId=[^&]*)?$`)
+
+	xrpDestinationTag = regexp.MustCompile(`^[0-9]{1,10}$`)
+	xlmMemo           = regexp.MustCompile(`^.{1,28}$`)
+	eosMemo           = regexp.MustCompile(`^.{1,256}$`)
+)
+
+// coinRegistry holds the coins we know how to handle, keyed by BitGo coin symbol
+var coinRegistry = map[string]CoinInfo{
+	"btc":  {Symbol: "btc", Name: "Bitcoin", Family: "btc", RequiredConfirmations: 1, Decimals: 8, DustThreshold: utxoDustThreshold, DefaultAddressType: AddressTypeP2WSH, addressPattern: btcAddress},
+	"tbtc": {Symbol: "tbtc", Name: "Testnet Bitcoin", Family: "btc", Testnet: true, RequiredConfirmations: 1, Decimals: 8, DustThreshold: utxoDustThreshold, DefaultAddressType: AddressTypeP2WSH, addressPattern: tbtcAddress},
+	"ltc":  {Symbol: "ltc", Name: "Litecoin", Family: "ltc", RequiredConfirmations: 4, Decimals: 8, DustThreshold: utxoDustThreshold, DefaultAddressType: AddressTypeP2WSH, addressPattern: ltcAddress},
+	"tltc": {Symbol: "tltc", Name: "Testnet Litecoin", Family: "ltc", Testnet: true, RequiredConfirmations: 4, Decimals: 8, DustThreshold: utxoDustThreshold, DefaultAddressType: AddressTypeP2WSH, addressPattern: tltcAddress},
+	"eth":  {Symbol: "eth", Name: "Ethereum", Family: "eth", BuildTypes: evmBuildTypes, RequiredConfirmations: 12, Decimals: 18, addressPattern: ethAddress},
+	"teth": {Symbol: "teth", Name: "Testnet Ethereum", Family: "eth", Testnet: true, BuildTypes: evmBuildTypes, RequiredConfirmations: 12, Decimals: 18, addressPattern: ethAddress},
+	"xrp": {Symbol: "xrp", Name: "XRP", Family: "xrp", RequiredConfirmations: 1, Decimals: 6,
+		MemoRequired: true, MemoLabel: "destination tag", MemoFormat: "numeric, 0-4294967295", memoPattern: xrpDestinationTag, addressPattern: xrpAddress},
+	"txrp": {Symbol: "txrp", Name: "Testnet XRP", Family: "xrp", Testnet: true, RequiredConfirmations: 1, Decimals: 6,
+		MemoRequired: true, MemoLabel: "destination tag", MemoFormat: "numeric, 0-4294967295", memoPattern: xrpDestinationTag, addressPattern: xrpAddress},
+	"xlm": {Symbol: "xlm", Name: "Stellar", Family: "xlm", RequiredConfirmations: 1, Decimals: 7,
+		MemoRequired: true, MemoLabel: "memo", MemoFormat: "text, up to 28 characters", memoPattern: xlmMemo, addressPattern: xlmAddress},
+	"txlm": {Symbol: "txlm", Name: "Testnet Stellar", Family: "xlm", Testnet: true, RequiredConfirmations: 1, Decimals: 7,
+		MemoRequired: true, MemoLabel: "memo", MemoFormat: "text, up to 28 characters", memoPattern: xlmMemo, addressPattern: xlmAddress},
+	"eos": {Symbol: "eos", Name: "EOS", Family: "eos", RequiredConfirmations: 1, Decimals: 4,
+		MemoRequired: true, MemoLabel: "memo", MemoFormat: "text, up to 256 characters", memoPattern: eosMemo, addressPattern: eosAddress},
+	"teos": {Symbol: "teos", Name: "Testnet EOS", Family: "eos", Testnet: true, RequiredConfirmations: 1, Decimals: 4,
+		MemoRequired: true, MemoLabel: "memo", MemoFormat: "text, up to 256 characters", memoPattern: eosMemo, addressPattern: eosAddress},
+}
+
+// coinAliases maps BitGo coin variants that aren't in the registry, such as additional
+// testnets, onto the registry coin whose rules they share
+var coinAliases = map[string]string{
+	"tbtc4": "tbtc",
+	"gteth": "teth",
+	"hteth": "teth",
+}
+
+// CanonicalCoin returns the registry symbol whose rules apply to a coin: trimmed, lowercased
+// and with testnet aliases resolved. It is only for looking up rules; BitGo must still be sent
+// the original symbol since it treats each variant as a distinct coin.
+func CanonicalCoin(symbol string) string {
+	symbol = strings.ToLower(strings.TrimSpace(symbol))
+	if canonical, ok := coinAliases[symbol]; ok {
+		return canonical
+	}
+	return symbol
+}
+
+// CoinFamily returns the family (btc, ltc, eth, ...) whose address and amount rules apply to
+// a coin, or "" for coins that aren't in the registry
+func CoinFamily(symbol string) string {
+	if info, ok := LookupCoin(symbol); ok {
+		return info.Family
+	}
+	return ""
+}
+
+// LookupCoin returns the registry entry for a coin symbol, resolving testnet aliases
+func LookupCoin(symbol string) (CoinInfo, bool) {
+	info, ok := coinRegistry[CanonicalCoin(symbol)]
+	return info, ok
+}
+
+// Coins returns the registry's coins ordered by symbol
+func Coins() []CoinInfo {
+	coins := make([]CoinInfo, 0, len(coinRegistry))
+	for _, info := range coinRegistry {
+		coins = append(coins, info)
+	}
+	sort.Slice(coins, func(i, j int) bool { return coins[i].Symbol < coins[j].Symbol })
+	return coins
+}
+
+// CoinAliases returns the coin variants that share a registry coin's rules, keyed by registry symbol
+func CoinAliases() map[string][]string {
+	aliases := make(map[string][]string)
+	for alias, symbol := range coinAliases {
+		aliases[symbol] = append(aliases[symbol], alias)
+	}
+	for _, list := range aliases {
+		sort.Strings(list)
+	}
+	return aliases
+}
+
+// RequiredConfirmations returns how many confirmations a transfer of the coin needs to be final
+func RequiredConfirmations(coin string) int {
+	if info, ok := LookupCoin(coin); ok && info.RequiredConfirmations > 0 {
+		return info.RequiredConfirmations
+	}
+	return defaultRequiredConfirmations
+}
+
+// DefaultAddressType returns the address type new receive addresses of the coin use when
+// none is requested, or "" to let BitGo decide
+func DefaultAddressType(coin string) string {
+	if info, ok := LookupCoin(coin); ok {
+		return info.DefaultAddressType
+	}
+	return ""
+}
+
+// ValidAddressForCoin reports whether an address is well-formed for the coin on its
+// network, e.g. an Ethereum address is not valid for btc and a mainnet address is not valid
+// for tbtc. Coins that aren't in the registry get the generic format check.
+func ValidAddressForCoin(coin, address string) bool {
+	address = strings.TrimSpace(address)
+	info, ok := LookupCoin(coin)
+	if !ok || info.addressPattern == nil {
+		return validAddressFormat(address)
+	}
+	return info.addressPattern.MatchString(address)
+}
+
+// AmountError is returned when a transfer amount is malformed or too precise for its coin
+type AmountError struct {
+	Amount  string
+	Message string
+}
+
+func (e AmountError) Error() string {
+	return fmt.Sprintf("amount %q %s", e.Amount, e.Message)
+}
+
+// NormalizeAmount canonicalizes a decimal amount so equal amounts compare equal: surrounding
+// whitespace, leading zeros and trailing fractional zeros are removed. Amounts using commas,
+// signs or exponents, zero amounts, and amounts with more fractional digits than the coin
+// supports are rejected rather than guessed at.
+func NormalizeAmount(coin, amount string) (string, error) {
+	trimmed := strings.TrimSpace(amount)
+	if !amountPattern.MatchString(trimmed) {
+		return "", AmountError{Amount: amount, Message: "must be a plain decimal number such as 1.5"}
+	}
+
+	whole, fraction, _ := strings.Cut(trimmed, ".")
+	whole = strings.TrimLeft(whole, "0")
+	fraction = strings.TrimRight(fraction, "0")
+	if whole == "" {
+		whole = "0"
+	}
+	if whole == "0" && fraction == "" {
+		return "", AmountError{Amount: amount, Message: "must be greater than zero"}
+	}
+
+	decimals := maxAmountDecimals
+	if info, ok := LookupCoin(coin); ok && info.Decimals > 0 {
+		decimals = info.Decimals
+	}
+	if len(fraction) > decimals {
+		return "", AmountError{Amount: amount, Message: fmt.Sprintf("has more than %d decimal places", decimals)}
+	}
+
+	if fraction == "" {
+		return whole, nil
+	}
+	return whole + "." + fraction, nil
+}
+
+// AmountFromBaseUnits converts an amount in the coin's base units, as BitGo reports values
+// (e.g. satoshis), to the normalized decimal amount transfers are stored in. A leading minus,
+// as on the wallet's own entries, is dropped. Coins outside the registry are rejected since
+// their decimals are unknown.
+func AmountFromBaseUnits(coin, baseUnits string) (string, error) {
+	info, ok := LookupCoin(coin)
+	if !ok || info.Decimals <= 0 {
+		return "", AmountError{Amount: baseUnits, Message: fmt.Sprintf("can't be converted from base units of unknown coin %s", coin)}
+	}
+
+	digits := strings.TrimPrefix(strings.TrimSpace(baseUnits), "-")
+	if digits == "" || strings.Trim(digits, "0123456789") != "" {
+		return "", AmountError{Amount: baseUnits, Message: "is not an integer amount of base units"}
+	}
+
+	if len(digits) <= info.Decimals {
+		digits = strings.Repeat("0", info.Decimals-len(digits)+1) + digits
+	}
+	split := len(digits) - info.Decimals
+	return NormalizeAmount(coin, digits[:split]+"."+digits[split:])
+}
+
+// AmountToBaseUnits converts a decimal amount to the coin's base units (e.g. satoshis), the
+// inverse of AmountFromBaseUnits. Coins outside the registry are rejected since their
+// decimals are unknown.
+func AmountToBaseUnits(coin, amount string) (string, error) {
+	info, ok := LookupCoin(coin)
+	if !ok || info.Decimals <= 0 {
+		return "", AmountError{Amount: amount, Message: fmt.Sprintf("can't be converted to base units of unknown coin %s", coin)}
+	}
+
+	normalized, err := NormalizeAmount(coin, amount)
+	if err != nil {
+		return "", err
+	}
+
+	whole, fraction, _ := strings.Cut(normalized, ".")
+	digits := strings.TrimLeft(whole+fraction+strings.Repeat("0", info.Decimals-len(fraction)), "0")
+	if digits == "" {
+		return "0", nil
+	}
+	return digits, nil
+}
+
+// CheckDust rejects an amount below the coin's dust threshold, which the network won't relay.
+// Coins without a dust threshold, or outside the registry, accept any amount.
+func CheckDust(coin, amount string) error {
+	info, ok := LookupCoin(coin)
+	if !ok || info.DustThreshold <= 0 {
+		return nil
+	}
+
+	baseUnits, err := AmountToBaseUnits(coin, amount)
+	if err != nil {
+		return err
+	}
+	value, err := strconv.ParseInt(baseUnits, 10, 64)
+	if err == nil && value < info.DustThreshold {
+		return AmountError{Amount: amount, Message: fmt.Sprintf("is below the %s dust threshold of %d base units", info.Symbol, info.DustThreshold)}
+	}
+	return nil
+}
+
+// ValidateBuildType checks that a build type is supported for the coin. An empty type means
+// a regular send and is always accepted.
+func ValidateBuildType(coin, buildType string) error {
+	if buildType == "" || buildType == BuildTypeSend {
+		return nil
+	}
+
+	info, ok := LookupCoin(coin)
+	if ok {
+		for _, allowed := range info.BuildTypes {
+			if allowed == buildType {
+				return nil
+			}
+		}
+	}
+
+	return fmt.Errorf("transaction type %q is not supported for %s", buildType, coin)
+}
+
+// MemoError is returned when a memo is missing or malformed for a coin that requires one
+type MemoError struct {
+	Coin    string
+	Label   string
+	Message string
+}
+
+func (e MemoError) Error() string {
+	return fmt.Sprintf("%s for %s %s", e.Label, e.Coin, e.Message)
+}
+
+// ValidateMemo checks that a memo is present and well-formed for coins that require one.
+// Coins that don't require a memo accept any value, including empty.
+func ValidateMemo(coin, memo string) error {
+	info, ok := LookupCoin(coin)
+	if !ok || !info.MemoRequired {
+		return nil
+	}
+
+	memo = strings.TrimSpace(memo)
+	if memo == "" {
+		return MemoError{Coin: info.Symbol, Label: info.MemoLabel, Message: "is required"}
+	}
+	return validateMemoFormat(info, memo)
+}
+
+// validateMemoFormat checks a non-empty memo or destination tag against the coin's format
+func validateMemoFormat(info CoinInfo, memo string) error {
+	if info.memoPattern != nil && !info.memoPattern.MatchString(memo) {
+		return MemoError{Coin: info.Symbol, Label: info.MemoLabel, Message: "must be " + info.MemoFormat}
+	}
+
+	// XRP destination tags are unsigned 32-bit integers
+	if info.Family == "xrp" {
+		var tag uint64
+		if _, err := fmt.Sscanf(memo, "%d", &tag); err != nil || tag > 4294967295 {
+			return MemoError{Coin: info.Symbol, Label: info.MemoLabel, Message: "must be " + info.MemoFormat}
+		}
+	}
+
+	return nil
+}
+
+// ValidateDestinationTag checks a destination tag given as its own recipient field rather
+// than as the memo. Only memo/tag coins accept one.
+func ValidateDestinationTag(coin, tag string) error {
+	info, ok := LookupCoin(coin)
+	if !ok || !info.MemoRequired {
+		return fmt.Errorf("%s transfers don't take a destination tag", coin)
+	}
+
+	tag = strings.TrimSpace(tag)
+	if tag == "" {
+		return MemoError{Coin: info.Symbol, Label: info.MemoLabel, Message: "is empty"}
+	}
+	return validateMemoFormat(info, tag)
+}
+
+// ValidateDestination checks the memo and destination tag of a transfer together. A valid
+// destination tag satisfies a coin's memo requirement; a memo given as well must match it,
+// and the address mustn't carry a tag of its own.
+func ValidateDestination(coin, address, memo, tag string) error {
+	tag = strings.TrimSpace(tag)
+	if tag == "" {
+		return ValidateMemo(coin, memo)
+	}
+
+	if err := ValidateDestinationTag(coin, tag); err != nil {
+		return err
+	}
+	if strings.Contains(address, "?") {
+		return fmt.Errorf("recipient address already carries a destination tag; don't also give destination_tag")
+	}
+	if memo = strings.TrimSpace(memo); memo != "" && memo != tag {
+		info, _ := LookupCoin(coin)
+		return MemoError{Coin: info.Symbol, Label: info.MemoLabel, Message: "differs between memo and destination_tag; give it once"}
+	}
+	return nil
+}
+
+// destinationTagParams are the address query parameters BitGo reads a destination tag from
+var destinationTagParams = map[string]string{
+	"xrp": "dt",
+	"xlm": "memoId",
+	"eos": "memoId",
+}
+
+// AddressWithDestinationTag returns the recipient address BitGo expects for a transfer with a
+// destination tag, e.g. r...?dt=123 for XRP. The address is returned unchanged when there is
+// no tag, the coin has no tag parameter, the address already carries one, or the tag is an XLM
+// text memo, which DestinationTagMemo sends instead.
+func AddressWithDestinationTag(coin, address, tag string) string {
+	tag = strings.TrimSpace(tag)
+	info, ok := LookupCoin(coin)
+	if tag == "" || !ok || strings.Contains(address, "?") || isXLMTextMemo(info, tag) {
+		return address
+	}
+
+	param, ok := destinationTagParams[info.Family]
+	if !ok {
+		return address
+	}
+	return address + "?" + param + "=" + url.QueryEscape(tag)
+}
+
+// DestinationTagMemo returns the memo to build a transfer with when its destination tag can't
+// go in the address: an XLM memo that isn't numeric, since ?memoId= only takes memo IDs. It
+// returns nil for every other tag.
+func DestinationTagMemo(coin, address, tag string) *TransferMemo {
+	tag = strings.TrimSpace(tag)
+	info, ok := LookupCoin(coin)
+	if !ok || strings.Contains(address, "?") || !isXLMTextMemo(info, tag) {
+		return nil
+	}
+	return TextMemo(tag)
+}
+
+// isXLMTextMemo reports whether a Stellar memo has to be sent as a text memo rather than a
+// memo ID, i.e. it isn't an unsigned 64-bit integer
+func isXLMTextMemo(info CoinInfo, tag string) bool {
+	if info.Family != "xlm" || tag == "" {
+		return false
+	}
+	_, err := strconv.ParseUint(tag, 10, 64)
+	return err != nil
+}
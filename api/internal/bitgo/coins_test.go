@@ -0,0 +1,200 @@
+package bitgo
+
+import (
+	"errors"
+	"testing"
+)
+
+const testXLMAddress = "GAJ6AJ6PCF7FJ7RGS2MR2PLAIN7SYCT3WOIKH4LZGHT3U7AKQLJZDUAS"
+
+func TestAddressWithDestinationTag(t *testing.T) {
+	tests := []struct {
+		name    string
+		coin    string
+		address string
+		tag     string
+		want    string
+	}{
+		{name: "xrp tag", coin: "xrp", address: "rEb8TK3gBgk5auZkwc6sHnwrGVJH8DuaLh", tag: "123", want: "rEb8TK3gBgk5auZkwc6sHnwrGVJH8DuaLh?dt=123"},
+		{name: "xlm memo id", coin: "xlm", address: testXLMAddress, tag: "42", want: testXLMAddress + "?memoId=42"},
+		{name: "xlm text memo", coin: "xlm", address: testXLMAddress, tag: "invoice 7", want: testXLMAddress},
+		{name: "eos memo", coin: "eos", address: "bitgoeos1234", tag: "hello", want: "bitgoeos1234?memoId=hello"},
+		{name: "no tag", coin: "xrp", address: "rEb8TK3gBgk5auZkwc6sHnwrGVJH8DuaLh", tag: " ", want: "rEb8TK3gBgk5auZkwc6sHnwrGVJH8DuaLh"},
+		{name: "tag already in address", coin: "xrp", address: "rEb8TK3gBgk5auZkwc6sHnwrGVJH8DuaLh?dt=1", tag: "2", want: "rEb8TK3gBgk5auZkwc6sHnwrGVJH8DuaLh?dt=1"},
+		{name: "coin without tags", coin: "btc", address: "bc1qexample", tag: "1", want: "bc1qexample"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := AddressWithDestinationTag(tt.coin, tt.address, tt.tag); got != tt.want {
+				t.Errorf("AddressWithDestinationTag() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestDestinationTagMemo(t *testing.T) {
+	memo := DestinationTagMemo("xlm", testXLMAddress, "invoice 7")
+	if memo == nil || memo.Type != "text" || memo.Value != "invoice 7" {
+		t.Errorf("DestinationTagMemo(xlm text) = %+v, want a text memo", memo)
+	}
+
+	for _, tt := range []struct{ coin, tag string }{
+		{"xlm", "42"},
+		{"xrp", "123"},
+		{"eos", "hello"},
+		{"xlm", ""},
+	} {
+		if memo := DestinationTagMemo(tt.coin, testXLMAddress, tt.tag); memo != nil {
+			t.Errorf("DestinationTagMemo(%s, %q) = %+v, want nil", tt.coin, tt.tag, memo)
+		}
+	}
+}
+
+func TestValidateDestinationRequiresMemo(t *testing.T) {
+	const xrpAddress = "rEb8TK3gBgk5auZkwc6sHnwrGVJH8DuaLh"
+	tests := []struct {
+		name    string
+		coin    string
+		address string
+		memo    string
+		tag     string
+		wantErr bool
+	}{
+		{name: "xrp without tag", coin: "xrp", address: xrpAddress, wantErr: true},
+		{name: "xrp numeric memo", coin: "xrp", address: xrpAddress, memo: "123456"},
+		{name: "xrp numeric tag", coin: "xrp", address: xrpAddress, tag: "123456"},
+		{name: "xrp text tag", coin: "xrp", address: xrpAddress, tag: "invoice", wantErr: true},
+		{name: "xrp tag out of range", coin: "xrp", address: xrpAddress, tag: "4294967296", wantErr: true},
+		{name: "xlm without memo", coin: "xlm", address: testXLMAddress, wantErr: true},
+		{name: "btc without memo", coin: "btc", address: "bc1qar0srrr7xfkvy5l643lydnw9re59gtzzwf5mdq"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ValidateDestination(tt.coin, tt.address, tt.memo, tt.tag)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("ValidateDestination() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestCheckDust(t *testing.T) {
+	tests := []struct {
+		coin    string
+		amount  string
+		wantErr bool
+	}{
+		{"btc", "0.00000545", true},
+		{"btc", "0.00000546", false},
+		{"ltc", "0.000001", true},
+		{"eth", "0.000000000000000001", false},
+		{"unknowncoin", "0.1", false},
+	}
+
+	for _, tt := range tests {
+		if err := CheckDust(tt.coin, tt.amount); (err != nil) != tt.wantErr {
+			t.Errorf("CheckDust(%s, %s) error = %v, wantErr %v", tt.coin, tt.amount, err, tt.wantErr)
+		}
+	}
+}
+
+func TestDefaultAddressType(t *testing.T) {
+	tests := []struct {
+		coin string
+		want string
+	}{
+		{coin: "btc", want: AddressTypeP2WSH},
+		{coin: "tbtc", want: AddressTypeP2WSH},
+		{coin: "LTC", want: AddressTypeP2WSH},
+		{coin: "tltc", want: AddressTypeP2WSH},
+		{coin: "eth", want: ""}, // Account-based, single address format
+		{coin: "xrp", want: ""},
+		{coin: "unknown", want: ""},
+	}
+
+	for _, tt := range tests {
+		if got := DefaultAddressType(tt.coin); got != tt.want {
+			t.Errorf("DefaultAddressType(%q) = %q, want %q", tt.coin, got, tt.want)
+		}
+	}
+}
+
+func TestNormalizeAmount(t *testing.T) {
+	tests := []struct {
+		coin    string
+		amount  string
+		want    string
+		wantErr bool
+	}{
+		{coin: "btc", amount: "1.50000", want: "1.5"},
+		{coin: "btc", amount: " 001.0 ", want: "1"},
+		{coin: "btc", amount: "0.00000001", want: "0.00000001"},
+		{coin: "btc", amount: "10", want: "10"},
+		{coin: "eth", amount: "0.000000000000000001", want: "0.000000000000000001"},
+		{coin: "btc", amount: "1,5", wantErr: true},
+		{coin: "btc", amount: "1,000.5", wantErr: true},
+		{coin: "btc", amount: "-1", wantErr: true},
+		{coin: "btc", amount: "1e3", wantErr: true},
+		{coin: "btc", amount: ".5", wantErr: true},
+		{coin: "btc", amount: "0.000", wantErr: true},
+		{coin: "btc", amount: "", wantErr: true},
+		{coin: "btc", amount: "0.000000001", wantErr: true}, // 9 places; btc has 8
+		{coin: "xrp", amount: "1.1234567", wantErr: true},   // 7 places; xrp has 6
+		{coin: "xrp", amount: "1.1234560", want: "1.123456"},
+	}
+
+	for _, tt := range tests {
+		got, err := NormalizeAmount(tt.coin, tt.amount)
+		if tt.wantErr {
+			var amountErr AmountError
+			if !errors.As(err, &amountErr) {
+				t.Errorf("NormalizeAmount(%s, %q) = %q, %v; want an AmountError", tt.coin, tt.amount, got, err)
+			}
+			continue
+		}
+		if err != nil || got != tt.want {
+			t.Errorf("NormalizeAmount(%s, %q) = %q, %v; want %q", tt.coin, tt.amount, got, err, tt.want)
+		}
+	}
+}
+
+func TestTestnetCoinsShareMainnetRules(t *testing.T) {
+	tests := []struct {
+		coin          string
+		wantCanonical string
+		wantFamily    string
+		wantTestnet   bool
+	}{
+		{coin: "tltc", wantCanonical: "tltc", wantFamily: "ltc", wantTestnet: true},
+		{coin: "teth", wantCanonical: "teth", wantFamily: "eth", wantTestnet: true},
+		{coin: " TETH ", wantCanonical: "teth", wantFamily: "eth", wantTestnet: true},
+		{coin: "hteth", wantCanonical: "teth", wantFamily: "eth", wantTestnet: true},
+		{coin: "gteth", wantCanonical: "teth", wantFamily: "eth", wantTestnet: true},
+		{coin: "tbtc4", wantCanonical: "tbtc", wantFamily: "btc", wantTestnet: true},
+		{coin: "ltc", wantCanonical: "ltc", wantFamily: "ltc"},
+		{coin: "unknown", wantCanonical: "unknown"},
+	}
+
+	for _, tt := range tests {
+		if got := CanonicalCoin(tt.coin); got != tt.wantCanonical {
+			t.Errorf("CanonicalCoin(%q) = %q, want %q", tt.coin, got, tt.wantCanonical)
+		}
+		if got := CoinFamily(tt.coin); got != tt.wantFamily {
+			t.Errorf("CoinFamily(%q) = %q, want %q", tt.coin, got, tt.wantFamily)
+		}
+		info, ok := LookupCoin(tt.coin)
+		if ok != (tt.wantFamily != "") || info.Testnet != tt.wantTestnet {
+			t.Errorf("LookupCoin(%q) = %+v, %v; want testnet %v", tt.coin, info, ok, tt.wantTestnet)
+		}
+	}
+
+	// Aliases get their registry coin's confirmation and precision rules
+	if got, want := RequiredConfirmations("hteth"), RequiredConfirmations("eth"); got != want {
+		t.Errorf("RequiredConfirmations(hteth) = %d, want %d", got, want)
+	}
+	if _, err := NormalizeAmount("tltc", "0.000000001"); err == nil {
+		t.Error("NormalizeAmount(tltc) accepted 9 decimal places, want ltc's limit of 8")
+	}
+}
@@ -0,0 +1,48 @@
+package bitgo
+
+import "context"
+
+type credentialsKey int
+
+const (
+	accessTokenKey credentialsKey = iota
+	enterpriseKey
+)
+
+// WithAccessToken returns a context whose BitGo requests authenticate with token instead of
+// the client's configured access token. An empty token leaves ctx unchanged.
+func WithAccessToken(ctx context.Context, token string) context.Context {
+	if token == "" {
+		return ctx
+	}
+	return context.WithValue(ctx, accessTokenKey, token)
+}
+
+// WithEnterprise returns a context whose BitGo requests act on enterprise instead of the
+// client's configured enterprise. An empty enterprise leaves ctx unchanged.
+func WithEnterprise(ctx context.Context, enterprise string) context.Context {
+	if enterprise == "" {
+		return ctx
+	}
+	return context.WithValue(ctx, enterpriseKey, enterprise)
+}
+
+// accessTokenFor picks the token for a request: the request option first, then the
+// context override, then the client default
+func (c *Client) accessTokenFor(ctx context.Context, opts RequestOptions) string {
+	if opts.AccessToken != "" {
+		return opts.AccessToken
+	}
+	if token, ok := ctx.Value(accessTokenKey).(string); ok && token != "" {
+		return token
+	}
+	return c.accessToken
+}
+
+// EnterpriseFor returns the enterprise a request made with ctx acts on
+func (c *Client) EnterpriseFor(ctx context.Context) string {
+	if enterprise, ok := ctx.Value(enterpriseKey).(string); ok && enterprise != "" {
+		return enterprise
+	}
+	return c.enterprise
+}
@@ -0,0 +1,81 @@
+package bitgo
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+)
+
+// recordingLogger keeps every log line with its fields
+type recordingLogger struct {
+	mu    sync.Mutex
+	lines []string
+}
+
+func (l *recordingLogger) record(msg string, fields ...interface{}) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.lines = append(l.lines, fmt.Sprint(append([]interface{}{msg}, fields...)...))
+}
+
+func (l *recordingLogger) Info(msg string, fields ...interface{})  { l.record(msg, fields...) }
+func (l *recordingLogger) Warn(msg string, fields ...interface{})  { l.record(msg, fields...) }
+func (l *recordingLogger) Error(msg string, fields ...interface{}) { l.record(msg, fields...) }
+func (l *recordingLogger) Debug(msg string, fields ...interface{}) { l.record(msg, fields...) }
+
+func (l *recordingLogger) String() string {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return strings.Join(l.lines, "\n")
+}
+
+func TestAccessTokenOverride(t *testing.T) {
+	var authorization string
+	bitgoServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		authorization = r.Header.Get("Authorization")
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{}`))
+	}))
+	defer bitgoServer.Close()
+
+	tests := []struct {
+		name      string
+		ctx       context.Context
+		opts      RequestOptions
+		wantToken string
+	}{
+		{name: "client default", ctx: context.Background(), wantToken: "default-token"},
+		{name: "context override", ctx: WithAccessToken(context.Background(), "context-token-secret"), wantToken: "context-token-secret"},
+		{name: "request option wins", ctx: WithAccessToken(context.Background(), "context-token-secret"), opts: RequestOptions{AccessToken: "option-token-secret"}, wantToken: "option-token-secret"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			logger := &recordingLogger{}
+			client := NewClient(Config{BaseURL: bitgoServer.URL, AccessToken: "default-token"}, logger)
+
+			opts := tt.opts
+			opts.Method = http.MethodGet
+			opts.Path = "/user/me"
+			resp, err := client.makeRequest(tt.ctx, opts)
+			if err != nil {
+				t.Fatalf("makeRequest() error = %v", err)
+			}
+			resp.Body.Close()
+
+			if authorization != "Bearer "+tt.wantToken {
+				t.Errorf("Authorization = %q, want Bearer %s", authorization, tt.wantToken)
+			}
+			logged := logger.String()
+			for _, secret := range []string{"context-token-secret", "option-token-secret", "default-token"} {
+				if strings.Contains(logged, secret) {
+					t.Errorf("logs contain %q:\n%s", secret, logged)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,42 @@
+package bitgo
+
+import (
+	"net/url"
+	"strings"
+)
+
+// BitGo environments and the platform URLs that serve them
+const (
+	EnvironmentTest = "test"
+	EnvironmentProd = "prod"
+
+	TestBaseURL = "https://app.bitgo-test.com"
+	ProdBaseURL = "https://app.bitgo.com"
+)
+
+// environmentHosts maps BitGo's own API hosts to the environment they serve
+var environmentHosts = map[string]string{
+	"app.bitgo-test.com": EnvironmentTest,
+	"app.bitgo.com":      EnvironmentProd,
+}
+
+// BaseURLForEnvironment returns BitGo's platform URL for the environment, defaulting to
+// the test platform for anything but prod
+func BaseURLForEnvironment(environment string) string {
+	if environment == EnvironmentProd {
+		return ProdBaseURL
+	}
+	return TestBaseURL
+}
+
+// EnvironmentForBaseURL returns the environment a BitGo platform URL serves. It reports
+// false for other hosts, such as a local BitGo Express or a proxy, whose environment can't
+// be told from the URL.
+func EnvironmentForBaseURL(baseURL string) (string, bool) {
+	parsed, err := url.Parse(baseURL)
+	if err != nil {
+		return "", false
+	}
+	environment, ok := environmentHosts[strings.ToLower(parsed.Hostname())]
+	return environment, ok
+}
@@ -0,0 +1,42 @@
+package bitgo
+
+import "testing"
+
+func TestEnvironmentForBaseURL(t *testing.T) {
+	tests := []struct {
+		baseURL string
+		want    string
+		wantOK  bool
+	}{
+		{baseURL: TestBaseURL, want: EnvironmentTest, wantOK: true},
+		{baseURL: ProdBaseURL, want: EnvironmentProd, wantOK: true},
+		{baseURL: "https://APP.BITGO.COM/api/v2", want: EnvironmentProd, wantOK: true},
+		{baseURL: "https://app.bitgo-test.com:443", want: EnvironmentTest, wantOK: true},
+		{baseURL: "http://localhost:3080"},
+		{baseURL: "https://bitgo-proxy.internal.example.com"},
+		{baseURL: "://not a url"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.baseURL, func(t *testing.T) {
+			got, ok := EnvironmentForBaseURL(tt.baseURL)
+			if got != tt.want || ok != tt.wantOK {
+				t.Errorf("EnvironmentForBaseURL(%q) = %q, %v; want %q, %v", tt.baseURL, got, ok, tt.want, tt.wantOK)
+			}
+		})
+	}
+}
+
+func TestBaseURLForEnvironment(t *testing.T) {
+	tests := map[string]string{
+		EnvironmentTest: TestBaseURL,
+		EnvironmentProd: ProdBaseURL,
+		"":              TestBaseURL,
+		"staging":       TestBaseURL,
+	}
+	for environment, want := range tests {
+		if got := BaseURLForEnvironment(environment); got != want {
+			t.Errorf("BaseURLForEnvironment(%q) = %s, want %s", environment, got, want)
+		}
+	}
+}
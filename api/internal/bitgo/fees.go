@@ -0,0 +1,188 @@
+package bitgo
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"math"
+	"math/big"
+	"net/http"
+	"net/url"
+	"strconv"
+)
+
+// FeeStrategy selects how aggressively a transfer's fee is priced
+type FeeStrategy string
+
+const (
+	FeeStrategyEconomy  FeeStrategy = "economy"
+	FeeStrategyNormal   FeeStrategy = "normal"
+	FeeStrategyPriority FeeStrategy = "priority"
+	FeeStrategyCustom   FeeStrategy = "custom"
+)
+
+// feeStrategyMultipliers scales BitGo's fee estimate for each non-custom strategy
+var feeStrategyMultipliers = map[FeeStrategy]float64{
+	FeeStrategyEconomy:  0.75,
+	FeeStrategyNormal:   1.0,
+	FeeStrategyPriority: 1.5,
+}
+
+// FeeEstimate represents BitGo's fee estimate for a coin
+type FeeEstimate struct {
+	FeePerKb         int64            `json:"feePerKb"`
+	CpfpFeePerKb     int64            `json:"cpfpFeePerKb,omitempty"`
+	NumBlocks        int              `json:"numBlocks,omitempty"`
+	Confidence       int              `json:"confidence,omitempty"`
+	FeeByBlockTarget map[string]int64 `json:"feeByBlockTarget,omitempty"`
+
+	// EVM coins are priced per unit of gas: BitGo reports the gas price in wei as feeEstimate
+	// and the gas a plain send needs as gasLimitEstimate
+	GasPrice json.Number `json:"feeEstimate,omitempty"`
+	GasLimit int64       `json:"gasLimitEstimate,omitempty"`
+}
+
+// EstimateFee retrieves BitGo's current fee estimate for a coin. numBlocks is the
+// confirmation target; zero uses BitGo's default.
+func (c *Client) EstimateFee(ctx context.Context, coin string, numBlocks int) (*FeeEstimate, error) {
+	if coin == "" {
+		return nil, fmt.Errorf("coin is required")
+	}
+
+	path := fmt.Sprintf("/%s/tx/fee", coin)
+	if numBlocks > 0 {
+		params := url.Values{}
+		params.Set("numBlocks", strconv.Itoa(numBlocks))
+		path += "?" + params.Encode()
+	}
+
+	resp, err := c.makeRequest(ctx, RequestOptions{
+		Method: http.MethodGet,
+		Path:   path,
+		Headers: map[string]string{
+			"Accept": "application/json",
+		},
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to estimate fee: %w", err)
+	}
+	defer resp.Body.Close()
+
+	var estimate FeeEstimate
+	if err := c.decodeResponse(resp, &estimate); err != nil {
+		return nil, err
+	}
+
+	c.logger.Info("Retrieved fee estimate successfully",
+		"coin", coin,
+		"fee_per_kb", estimate.FeePerKb,
+		"num_blocks", estimate.NumBlocks,
+	)
+
+	return &estimate, nil
+}
+
+// ValidateFeeStrategy checks a fee strategy and, for custom, its explicit rate against maxFeeRate.
+// An empty strategy is allowed and leaves fees to BitGo's defaults; maxFeeRate of zero means no cap.
+func ValidateFeeStrategy(strategy FeeStrategy, customRate, maxFeeRate int64) error {
+	switch strategy {
+	case "":
+		if customRate != 0 {
+			return fmt.Errorf("fee rate requires the custom fee strategy")
+		}
+		return nil
+	case FeeStrategyEconomy, FeeStrategyNormal, FeeStrategyPriority:
+		if customRate != 0 {
+			return fmt.Errorf("fee rate is only allowed with the custom fee strategy")
+		}
+		return nil
+	case FeeStrategyCustom:
+		if customRate <= 0 {
+			return fmt.Errorf("custom fee strategy requires a positive fee rate")
+		}
+		if maxFeeRate > 0 && customRate > maxFeeRate {
+			return fmt.Errorf("fee rate %d exceeds the maximum fee rate %d", customRate, maxFeeRate)
+		}
+		return nil
+	default:
+		return fmt.Errorf("unsupported fee strategy %q (allowed: economy, normal, priority, custom)", strategy)
+	}
+}
+
+// ApplyFeeStrategy sets the fee parameters on a build request. Economy, normal and priority
+// scale the estimate's fee rate; custom uses customRate as-is. maxFeeRate caps every strategy.
+// EVM coins are priced by gas price in wei instead of a per-kB rate, so for them customRate
+// and maxFeeRate are gas prices and the estimate's gas limit is passed along.
+func ApplyFeeStrategy(req *BuildTransferRequest, coin string, strategy FeeStrategy, estimate *FeeEstimate, customRate, maxFeeRate int64) error {
+	if err := ValidateFeeStrategy(strategy, customRate, maxFeeRate); err != nil {
+		return err
+	}
+	if IsEVMCoin(coin) {
+		return applyEVMFeeStrategy(req, strategy, estimate, customRate, maxFeeRate)
+	}
+
+	switch strategy {
+	case "":
+		return nil
+	case FeeStrategyCustom:
+		req.FeeRate = customRate
+	default:
+		if estimate == nil || estimate.FeePerKb <= 0 {
+			return fmt.Errorf("fee estimate is required for the %s fee strategy", strategy)
+		}
+		multiplier := feeStrategyMultipliers[strategy]
+		feeRate := int64(math.Ceil(float64(estimate.FeePerKb) * multiplier))
+		if maxFeeRate > 0 && feeRate > maxFeeRate {
+			feeRate = maxFeeRate
+		}
+		// Only the scaled rate is sent; also sending feeMultiplier would scale the fee twice
+		req.FeeRate = feeRate
+	}
+
+	if maxFeeRate > 0 {
+		req.MaxFeeRate = maxFeeRate
+	}
+
+	return nil
+}
+
+// applyEVMFeeStrategy sets gasPrice (and gasLimit when estimated) on an EVM build. The per-kB
+// feeRate fields mean nothing to EVM builds and are left unset.
+func applyEVMFeeStrategy(req *BuildTransferRequest, strategy FeeStrategy, estimate *FeeEstimate, customRate, maxFeeRate int64) error {
+	var gasPrice *big.Int
+	switch strategy {
+	case "":
+		return nil
+	case FeeStrategyCustom:
+		gasPrice = big.NewInt(customRate)
+	default:
+		estimated, ok := new(big.Int).SetString(estimate.gasPrice(), 10)
+		if !ok || estimated.Sign() <= 0 {
+			return fmt.Errorf("gas price estimate is required for the %s fee strategy", strategy)
+		}
+		// Scale with exact arithmetic, rounding up, since wei prices don't fit a float's precision
+		scaled := new(big.Rat).Mul(new(big.Rat).SetInt(estimated), new(big.Rat).SetFloat64(feeStrategyMultipliers[strategy]))
+		gasPrice = new(big.Int).Quo(scaled.Num(), scaled.Denom())
+		if new(big.Rat).SetInt(gasPrice).Cmp(scaled) < 0 {
+			gasPrice.Add(gasPrice, big.NewInt(1))
+		}
+	}
+
+	if maxFeeRate > 0 && gasPrice.Cmp(big.NewInt(maxFeeRate)) > 0 {
+		gasPrice = big.NewInt(maxFeeRate)
+	}
+	req.GasPrice = gasPrice.String()
+	if estimate != nil && estimate.GasLimit > 0 {
+		req.GasLimit = estimate.GasLimit
+	}
+
+	return nil
+}
+
+// gasPrice returns the estimated EVM gas price in wei, or "" when there is none
+func (e *FeeEstimate) gasPrice() string {
+	if e == nil {
+		return ""
+	}
+	return e.GasPrice.String()
+}
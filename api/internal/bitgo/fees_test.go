@@ -0,0 +1,58 @@
+package bitgo
+
+import (
+	"testing"
+)
+
+func TestApplyFeeStrategy(t *testing.T) {
+	utxoEstimate := &FeeEstimate{FeePerKb: 10000}
+	evmEstimate := &FeeEstimate{GasPrice: "30000000001", GasLimit: 21000}
+
+	tests := []struct {
+		name         string
+		coin         string
+		strategy     FeeStrategy
+		estimate     *FeeEstimate
+		customRate   int64
+		maxFeeRate   int64
+		wantFeeRate  int64
+		wantMaxRate  int64
+		wantGasPrice string
+		wantGasLimit int64
+		wantErr      bool
+	}{
+		{name: "no strategy leaves BitGo defaults", coin: "btc"},
+		{name: "economy", coin: "btc", strategy: FeeStrategyEconomy, estimate: utxoEstimate, wantFeeRate: 7500},
+		{name: "normal", coin: "btc", strategy: FeeStrategyNormal, estimate: utxoEstimate, wantFeeRate: 10000},
+		{name: "priority", coin: "btc", strategy: FeeStrategyPriority, estimate: utxoEstimate, wantFeeRate: 15000},
+		{name: "priority capped", coin: "btc", strategy: FeeStrategyPriority, estimate: utxoEstimate, maxFeeRate: 12000, wantFeeRate: 12000, wantMaxRate: 12000},
+		{name: "custom", coin: "btc", strategy: FeeStrategyCustom, customRate: 2000, maxFeeRate: 12000, wantFeeRate: 2000, wantMaxRate: 12000},
+		{name: "custom over the cap", coin: "btc", strategy: FeeStrategyCustom, customRate: 20000, maxFeeRate: 12000, wantErr: true},
+		{name: "estimate required", coin: "btc", strategy: FeeStrategyNormal, wantErr: true},
+		{name: "rate without custom", coin: "btc", strategy: FeeStrategyNormal, estimate: utxoEstimate, customRate: 2000, wantErr: true},
+		{name: "unknown strategy", coin: "btc", strategy: "fastest", estimate: utxoEstimate, wantErr: true},
+		{name: "evm priority rounds up", coin: "eth", strategy: FeeStrategyPriority, estimate: evmEstimate, wantGasPrice: "45000000002", wantGasLimit: 21000},
+		{name: "evm capped", coin: "eth", strategy: FeeStrategyPriority, estimate: evmEstimate, maxFeeRate: 40000000000, wantGasPrice: "40000000000", wantGasLimit: 21000},
+		{name: "evm custom", coin: "eth", strategy: FeeStrategyCustom, customRate: 25000000000, wantGasPrice: "25000000000"},
+		{name: "evm estimate required", coin: "eth", strategy: FeeStrategyEconomy, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := BuildTransferRequest{}
+			err := ApplyFeeStrategy(&req, tt.coin, tt.strategy, tt.estimate, tt.customRate, tt.maxFeeRate)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ApplyFeeStrategy() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err != nil {
+				return
+			}
+			if req.FeeRate != tt.wantFeeRate || req.MaxFeeRate != tt.wantMaxRate {
+				t.Errorf("feeRate %d, maxFeeRate %d; want %d, %d", req.FeeRate, req.MaxFeeRate, tt.wantFeeRate, tt.wantMaxRate)
+			}
+			if req.GasPrice != tt.wantGasPrice || req.GasLimit != tt.wantGasLimit {
+				t.Errorf("gasPrice %q, gasLimit %d; want %q, %d", req.GasPrice, req.GasLimit, tt.wantGasPrice, tt.wantGasLimit)
+			}
+		})
+	}
+}
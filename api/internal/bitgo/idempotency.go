@@ -5,6 +5,7 @@ import (
 	"crypto/sha256"
 	"encoding/hex"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"sync"
 	"time"
@@ -12,12 +13,25 @@ import (
 	"github.com/google/uuid"
 )
 
+// ErrIdempotencyKeyConflict is returned when an idempotency key is reused with a different request
+var ErrIdempotencyKeyConflict = errors.New("idempotency key reused with different parameters")
+
+// defaultBuildResultFreshness is how long a cached build may be reused. A build spends a
+// particular set of unspent outputs and fee estimate, both of which go stale long before
+// the dedup TTL is up.
+const defaultBuildResultFreshness = 5 * time.Minute
+
 // IdempotencyService handles idempotency for BitGo operations
 type IdempotencyService struct {
 	cache  map[string]*IdempotencyRecord
 	mutex  sync.RWMutex
 	logger Logger
 	ttl    time.Duration
+
+	// freshness bounds, per operation, how long a completed result may be returned from
+	// cache. The key is still deduplicated for the full ttl, but a result older than its
+	// window is re-executed rather than reused.
+	freshness map[string]time.Duration
 }
 
 // IdempotencyRecord represents a cached operation result
@@ -27,9 +41,11 @@ type IdempotencyRecord struct {
 	Operation   string            `json:"operation"`
 	Status      IdempotencyStatus `json:"status"`
 	Request     interface{}       `json:"request"`
+	RequestHash string            `json:"requestHash"`
 	Response    interface{}       `json:"response,omitempty"`
 	Error       string            `json:"error,omitempty"`
 	CreatedAt   time.Time         `json:"createdAt"`
+	CompletedAt *time.Time        `json:"completedAt,omitempty"`
 	ExpiresAt   time.Time         `json:"expiresAt"`
 	Attempts    int               `json:"attempts"`
 	LastAttempt time.Time         `json:"lastAttempt"`
@@ -55,6 +71,9 @@ func NewIdempotencyService(logger Logger, ttl time.Duration) *IdempotencyService
 		cache:  make(map[string]*IdempotencyRecord),
 		logger: logger,
 		ttl:    ttl,
+		freshness: map[string]time.Duration{
+			"build-transfer": defaultBuildResultFreshness,
+		},
 	}
 
 	// Start cleanup routine
@@ -63,6 +82,48 @@ func NewIdempotencyService(logger Logger, ttl time.Duration) *IdempotencyService
 	return service
 }
 
+// SetResultFreshness sets how long a completed result of the operation may be reused.
+// Zero removes the limit, so results are reused for the full TTL.
+func (s *IdempotencyService) SetResultFreshness(operation string, window time.Duration) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	if window <= 0 {
+		delete(s.freshness, operation)
+		return
+	}
+	s.freshness[operation] = window
+}
+
+// isStale reports whether a completed record is older than its operation's freshness
+// window. Callers must hold the mutex.
+func (s *IdempotencyService) isStale(record *IdempotencyRecord, now time.Time) bool {
+	window, ok := s.freshness[record.Operation]
+	if !ok || record.Status != IdempotencyStatusCompleted || record.CompletedAt == nil {
+		return false
+	}
+	return now.Sub(*record.CompletedAt) > window
+}
+
+// claimStaleResult moves a stale completed record back to pending so exactly one caller
+// re-executes it. It returns false if the record is fresh or another caller got there first.
+func (s *IdempotencyService) claimStaleResult(key string) bool {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	record, exists := s.cache[key]
+	if !exists || !s.isStale(record, time.Now()) {
+		return false
+	}
+
+	record.Status = IdempotencyStatusPending
+	record.Response = nil
+	record.CompletedAt = nil
+	record.Attempts++
+	record.LastAttempt = time.Now()
+	return true
+}
+
 // GenerateKey generates an idempotency key based on operation and request parameters
 func (s *IdempotencyService) GenerateKey(operation string, request interface{}) string {
 	// Create a deterministic hash of the operation and request
@@ -81,18 +142,47 @@ func (s *IdempotencyService) GenerateKey(operation string, request interface{})
 	return hex.EncodeToString(hash[:])
 }
 
+// hashRequest returns a stable hash of a request so reused keys can be checked for mismatches
+func hashRequest(request interface{}) string {
+	var data []byte
+	switch r := request.(type) {
+	case []byte:
+		data = r
+	case string:
+		data = []byte(r)
+	default:
+		var err error
+		if data, err = json.Marshal(request); err != nil {
+			return ""
+		}
+	}
+
+	hash := sha256.Sum256(data)
+	return hex.EncodeToString(hash[:])
+}
+
 // CheckOrStore checks if an operation is already in progress or completed
-// Returns (record, isNew) where isNew indicates if this is a new operation
+// Returns (record, isNew) where isNew indicates if this is a new operation.
+// Reusing a key with a different operation or request returns ErrIdempotencyKeyConflict.
 func (s *IdempotencyService) CheckOrStore(ctx context.Context, key, operation string, request interface{}) (*IdempotencyRecord, bool, error) {
 	s.mutex.Lock()
 	defer s.mutex.Unlock()
 
+	requestHash := hashRequest(request)
+
 	// Check if record exists
 	if record, exists := s.cache[key]; exists {
 		// Check if expired
 		if time.Now().After(record.ExpiresAt) {
 			delete(s.cache, key)
 			s.logger.Info("Expired idempotency record removed", "key", key)
+		} else if record.Operation != operation || record.RequestHash != requestHash {
+			s.logger.Warn("Idempotency key reused with different parameters",
+				"key", key,
+				"operation", operation,
+				"original_operation", record.Operation,
+			)
+			return record, false, ErrIdempotencyKeyConflict
 		} else {
 			s.logger.Info("Found existing idempotency record",
 				"key", key,
@@ -110,6 +200,7 @@ func (s *IdempotencyService) CheckOrStore(ctx context.Context, key, operation st
 		Operation:   operation,
 		Status:      IdempotencyStatusPending,
 		Request:     request,
+		RequestHash: requestHash,
 		CreatedAt:   time.Now(),
 		ExpiresAt:   time.Now().Add(s.ttl),
 		Attempts:    1,
@@ -141,6 +232,10 @@ func (s *IdempotencyService) UpdateRecord(key string, status IdempotencyStatus,
 	record.Status = status
 	record.Response = response
 	record.LastAttempt = time.Now()
+	if status == IdempotencyStatusCompleted {
+		completedAt := record.LastAttempt
+		record.CompletedAt = &completedAt
+	}
 
 	if err != nil {
 		record.Error = err.Error()
@@ -153,6 +248,13 @@ func (s *IdempotencyService) UpdateRecord(key string, status IdempotencyStatus,
 	)
 }
 
+// DeleteRecord forgets a key so the operation can be attempted again
+func (s *IdempotencyService) DeleteRecord(key string) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	delete(s.cache, key)
+}
+
 // RetryRecord increments the attempt count for a record
 func (s *IdempotencyService) RetryRecord(key string) {
 	s.mutex.Lock()
@@ -242,8 +344,18 @@ func (s *IdempotencyService) ExecuteIdempotent(ctx context.Context, key, operati
 	if !isNew {
 		switch record.Status {
 		case IdempotencyStatusCompleted:
-			s.logger.Info("Returning cached result for idempotent operation", "key", key)
-			return record.Response, nil
+			if !s.claimStaleResult(key) {
+				if current, ok := s.GetRecord(key); ok && current.Status == IdempotencyStatusPending {
+					s.logger.Warn("Duplicate request detected for pending operation", "key", key)
+					return nil, fmt.Errorf("operation already in progress")
+				}
+				s.logger.Info("Returning cached result for idempotent operation", "key", key)
+				return record.Response, nil
+			}
+			s.logger.Info("Cached result is past its freshness window, re-executing",
+				"key", key,
+				"operation", operation,
+			)
 
 		case IdempotencyStatusFailed:
 			s.logger.Info("Returning cached error for idempotent operation", "key", key)
@@ -298,12 +410,12 @@ func (s *IdempotencyService) GetStats() map[string]interface{} {
 
 // IdempotentTransferBuilder wraps transfer building with idempotency
 type IdempotentTransferBuilder struct {
-	client      *Client
+	client      BitGoAPI
 	idempotency *IdempotencyService
 }
 
 // NewIdempotentTransferBuilder creates a new idempotent transfer builder
-func NewIdempotentTransferBuilder(client *Client, idempotency *IdempotencyService) *IdempotentTransferBuilder {
+func NewIdempotentTransferBuilder(client BitGoAPI, idempotency *IdempotencyService) *IdempotentTransferBuilder {
 	return &IdempotentTransferBuilder{
 		client:      client,
 		idempotency: idempotency,
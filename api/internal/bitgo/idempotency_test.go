@@ -0,0 +1,68 @@
+package bitgo
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+// backdateResult makes the key's result look as if it completed age ago
+func backdateResult(s *IdempotencyService, key string, age time.Duration) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	completedAt := time.Now().Add(-age)
+	s.cache[key].CompletedAt = &completedAt
+}
+
+func TestCachedResultsAreReusedOnlyWhileFresh(t *testing.T) {
+	tests := []struct {
+		name          string
+		operation     string
+		freshness     time.Duration // Set with SetResultFreshness when non-zero
+		clear         bool          // Remove the operation's freshness limit
+		age           time.Duration
+		wantExecCount int
+	}{
+		{name: "fresh build", operation: "build-transfer", age: time.Minute, wantExecCount: 1},
+		{name: "stale build", operation: "build-transfer", age: defaultBuildResultFreshness + time.Second, wantExecCount: 2},
+		{name: "custom window", operation: "build-transfer", freshness: 30 * time.Second, age: time.Minute, wantExecCount: 2},
+		{name: "limit removed", operation: "build-transfer", clear: true, age: time.Hour, wantExecCount: 1},
+		{name: "operation without a window", operation: "submit-transfer", age: time.Hour, wantExecCount: 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := NewIdempotencyService(testLogger{}, 24*time.Hour)
+			if tt.freshness > 0 {
+				s.SetResultFreshness(tt.operation, tt.freshness)
+			}
+			if tt.clear {
+				s.SetResultFreshness(tt.operation, 0)
+			}
+
+			executions := 0
+			op := func(context.Context) (interface{}, error) {
+				executions++
+				return executions, nil
+			}
+			request := map[string]string{"wallet": "wallet-1"}
+			key := s.GenerateKey(tt.operation, request)
+
+			if _, err := s.ExecuteIdempotent(context.Background(), key, tt.operation, request, op); err != nil {
+				t.Fatalf("first ExecuteIdempotent() error = %v", err)
+			}
+			backdateResult(s, key, tt.age)
+
+			result, err := s.ExecuteIdempotent(context.Background(), key, tt.operation, request, op)
+			if err != nil {
+				t.Fatalf("second ExecuteIdempotent() error = %v", err)
+			}
+			if executions != tt.wantExecCount {
+				t.Errorf("operation ran %d times, want %d", executions, tt.wantExecCount)
+			}
+			if result != tt.wantExecCount {
+				t.Errorf("result = %v, want the result of execution %d", result, tt.wantExecCount)
+			}
+		})
+	}
+}
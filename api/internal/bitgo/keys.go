@@ -0,0 +1,91 @@
+package bitgo
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+)
+
+// keyRoles names wallet keys by their position in Wallet.Keys
+var keyRoles = []string{"user", "backup", "bitgo"}
+
+// Keychain holds the public metadata of a wallet key. Private key material is never included.
+type Keychain struct {
+	ID             string `json:"id"`
+	Role           string `json:"role"`
+	Pub            string `json:"pub,omitempty"`
+	CommonKeychain string `json:"commonKeychain,omitempty"`
+	EthAddress     string `json:"ethAddress,omitempty"`
+	Source         string `json:"source,omitempty"`
+	Type           string `json:"type,omitempty"`
+	IsBitGo        bool   `json:"isBitGo,omitempty"`
+	IsTrust        bool   `json:"isTrust,omitempty"`
+	Provider       string `json:"provider,omitempty"` // Key recovery service holding the backup key
+}
+
+// GetWalletKeys retrieves the public key metadata for each of a wallet's keys
+func (c *Client) GetWalletKeys(ctx context.Context, walletID, coin string) ([]Keychain, error) {
+	wallet, err := c.GetWallet(ctx, walletID, coin)
+	if err != nil {
+		return nil, err
+	}
+
+	keychains := make([]Keychain, 0, len(wallet.Keys))
+	for i, keyID := range wallet.Keys {
+		keychain, err := c.getKeychain(ctx, coin, keyID)
+		if err != nil {
+			return nil, err
+		}
+		if i < len(keyRoles) {
+			keychain.Role = keyRoles[i]
+		}
+		keychains = append(keychains, *keychain)
+	}
+
+	c.logger.Info("Retrieved wallet keys successfully",
+		"wallet_id", walletID,
+		"coin", coin,
+		"count", len(keychains),
+	)
+
+	return keychains, nil
+}
+
+// getKeychain fetches a single keychain and strips any private material from it
+func (c *Client) getKeychain(ctx context.Context, coin, keyID string) (*Keychain, error) {
+	path := fmt.Sprintf("/%s/key/%s", coin, keyID)
+
+	resp, err := c.makeRequest(ctx, RequestOptions{
+		Method: http.MethodGet,
+		Path:   path,
+		Headers: map[string]string{
+			"Accept": "application/json",
+		},
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get keychain: %w", err)
+	}
+	defer resp.Body.Close()
+
+	var raw map[string]interface{}
+	if err := c.decodeResponse(resp, &raw); err != nil {
+		return nil, err
+	}
+
+	for _, field := range sensitiveFields {
+		delete(raw, field)
+	}
+
+	publicData, err := json.Marshal(raw)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal keychain: %w", err)
+	}
+
+	var keychain Keychain
+	if err := json.Unmarshal(publicData, &keychain); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal keychain: %w", err)
+	}
+
+	return &keychain, nil
+}
@@ -0,0 +1,58 @@
+package bitgo
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestGetWalletKeysStripsPrivateMaterial(t *testing.T) {
+	bitgoServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch r.URL.Path {
+		case "/api/v2/tbtc/wallet/wallet-1":
+			w.Write([]byte(`{"id":"wallet-1","coin":"tbtc","keys":["key-user","key-backup","key-bitgo"]}`))
+		case "/api/v2/tbtc/key/key-user":
+			w.Write([]byte(`{"id":"key-user","pub":"xpub-user","source":"user","prv":"xprv-user","encryptedPrv":"{\"iv\":\"secret\"}"}`))
+		case "/api/v2/tbtc/key/key-backup":
+			w.Write([]byte(`{"id":"key-backup","pub":"xpub-backup","source":"backup","provider":"keyternal","encryptedPrv":"{\"iv\":\"backup-secret\"}"}`))
+		case "/api/v2/tbtc/key/key-bitgo":
+			w.Write([]byte(`{"id":"key-bitgo","pub":"xpub-bitgo","isBitGo":true}`))
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer bitgoServer.Close()
+
+	client := NewClient(Config{BaseURL: bitgoServer.URL, AccessToken: "token"}, testLogger{})
+	keys, err := client.GetWalletKeys(context.Background(), "wallet-1", "tbtc")
+	if err != nil {
+		t.Fatalf("GetWalletKeys() error = %v", err)
+	}
+
+	wantRoles := []string{"user", "backup", "bitgo"}
+	if len(keys) != len(wantRoles) {
+		t.Fatalf("got %d keys, want %d", len(keys), len(wantRoles))
+	}
+	for i, key := range keys {
+		if key.Role != wantRoles[i] || key.Pub == "" {
+			t.Errorf("key %d = %+v, want the %s key with its pub", i, key, wantRoles[i])
+		}
+	}
+	if keys[1].Provider != "keyternal" {
+		t.Errorf("backup provider = %q, want keyternal", keys[1].Provider)
+	}
+
+	encoded, err := json.Marshal(keys)
+	if err != nil {
+		t.Fatalf("marshal keys: %v", err)
+	}
+	for _, secret := range []string{"prv", "xprv-user", "secret"} {
+		if strings.Contains(string(encoded), secret) {
+			t.Errorf("keys %s contain %q", encoded, secret)
+		}
+	}
+}
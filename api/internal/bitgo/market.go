@@ -0,0 +1,61 @@
+package bitgo
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+)
+
+// MarketData holds the latest market prices BitGo reports for a coin
+type MarketData struct {
+	Coin       string                    `json:"coin"`
+	Currencies map[string]CurrencyPrices `json:"currencies"`
+}
+
+// CurrencyPrices holds prices for a coin in a single fiat currency
+type CurrencyPrices struct {
+	Last float64 `json:"last"`
+	Bid  float64 `json:"bid,omitempty"`
+	Ask  float64 `json:"ask,omitempty"`
+}
+
+// marketDataResponse wraps the market data returned by the market/latest endpoint
+type marketDataResponse struct {
+	MarketData []MarketData `json:"marketData"`
+}
+
+// GetUSDPrice retrieves the latest USD price for one unit of coin
+func (c *Client) GetUSDPrice(ctx context.Context, coin string) (float64, error) {
+	if coin == "" {
+		return 0, fmt.Errorf("coin is required")
+	}
+
+	resp, err := c.makeRequest(ctx, RequestOptions{
+		Method: http.MethodGet,
+		Path:   fmt.Sprintf("/%s/market/latest", coin),
+		Headers: map[string]string{
+			"Accept": "application/json",
+		},
+	})
+	if err != nil {
+		return 0, fmt.Errorf("failed to get market data: %w", err)
+	}
+	defer resp.Body.Close()
+
+	var result marketDataResponse
+	if err := c.decodeResponse(resp, &result); err != nil {
+		return 0, err
+	}
+
+	for _, data := range result.MarketData {
+		if data.Coin != "" && !strings.EqualFold(data.Coin, coin) {
+			continue
+		}
+		if usd, ok := data.Currencies["USD"]; ok && usd.Last > 0 {
+			return usd.Last, nil
+		}
+	}
+
+	return 0, fmt.Errorf("no USD price available for %s", coin)
+}
@@ -0,0 +1,582 @@
+package bitgo
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// SimulationConfig controls how the simulated BitGo client behaves
+type SimulationConfig struct {
+	// ConfirmAfterPolls is how many GetTransfer calls a submitted transfer takes to confirm
+	ConfirmAfterPolls int
+	Enterprise        string
+	// Balance is the base-unit balance every simulated wallet reports
+	Balance string
+	// FeeRate is the fee rate returned by fee estimates and used for builds
+	FeeRate int64
+}
+
+// DefaultSimulationConfig returns the default simulation settings
+func DefaultSimulationConfig() SimulationConfig {
+	return SimulationConfig{
+		ConfirmAfterPolls: 3,
+		Enterprise:        "sim-enterprise",
+		Balance:           "1000000000",
+		FeeRate:           10000,
+	}
+}
+
+// simulatedUSDPrices are the fixed prices the simulated client reports; other coins are $1
+var simulatedUSDPrices = map[string]float64{
+	"btc": 60000, "tbtc": 60000,
+	"eth": 3000, "teth": 3000, "hteth": 3000,
+	"ltc": 80, "tltc": 80,
+}
+
+type simulatedTransfer struct {
+	transfer Transfer
+	polls    int
+}
+
+// SimulatedClient is a deterministic, in-memory stand-in for BitGo. Builds always succeed,
+// submits return fake txids and submitted transfers confirm after ConfirmAfterPolls reads.
+// IDs and txids are derived from a sequence counter so runs are reproducible.
+type SimulatedClient struct {
+	config SimulationConfig
+	logger Logger
+
+	mu        sync.Mutex
+	seq       int
+	wallets   map[string]*Wallet
+	transfers map[string]*simulatedTransfer
+}
+
+// NewSimulatedClient creates a simulated BitGo client
+func NewSimulatedClient(config SimulationConfig, logger Logger) *SimulatedClient {
+	defaults := DefaultSimulationConfig()
+	if config.ConfirmAfterPolls <= 0 {
+		config.ConfirmAfterPolls = defaults.ConfirmAfterPolls
+	}
+	if config.Enterprise == "" {
+		config.Enterprise = defaults.Enterprise
+	}
+	if config.Balance == "" {
+		config.Balance = defaults.Balance
+	}
+	if config.FeeRate <= 0 {
+		config.FeeRate = defaults.FeeRate
+	}
+
+	return &SimulatedClient{
+		config:    config,
+		logger:    logger,
+		wallets:   make(map[string]*Wallet),
+		transfers: make(map[string]*simulatedTransfer),
+	}
+}
+
+// nextID returns the next deterministic identifier with the given prefix. Callers hold s.mu.
+func (s *SimulatedClient) nextID(prefix string) string {
+	s.seq++
+	return fmt.Sprintf("%s-%06d", prefix, s.seq)
+}
+
+// simulatedHash derives a stable 64-character hex string from parts
+func simulatedHash(parts ...string) string {
+	sum := sha256.Sum256([]byte(strings.Join(parts, "|")))
+	return hex.EncodeToString(sum[:])
+}
+
+// GetEnterprise returns the simulated enterprise ID
+func (s *SimulatedClient) GetEnterprise() string {
+	return s.config.Enterprise
+}
+
+// GetCurrentUser returns a fixed simulated user
+func (s *SimulatedClient) GetCurrentUser(ctx context.Context) (*User, error) {
+	user := &User{ID: "sim-user", Username: "simulation@bitgo-wallets.local"}
+	user.Name.Full = "Simulation User"
+	user.Email.Email = user.Username
+	user.Email.Verified = true
+	return user, nil
+}
+
+// ListWallets returns the wallets created through the simulated client
+func (s *SimulatedClient) ListWallets(ctx context.Context, opts WalletListOptions) (*WalletListResponse, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	wallets := []Wallet{}
+	for _, wallet := range s.wallets {
+		if opts.Coin != "" && wallet.Coin != opts.Coin {
+			continue
+		}
+		wallets = append(wallets, *wallet)
+	}
+	sort.Slice(wallets, func(i, j int) bool { return wallets[i].ID < wallets[j].ID })
+
+	return &WalletListResponse{Wallets: wallets, Coin: opts.Coin, Count: len(wallets), Total: len(wallets)}, nil
+}
+
+// CreateWalletRaw creates a simulated wallet
+func (s *SimulatedClient) CreateWalletRaw(ctx context.Context, coin string, body map[string]interface{}) (*Wallet, error) {
+	if coin == "" {
+		return nil, fmt.Errorf("coin is required")
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	wallet := s.simulatedWallet(s.nextID("sim-wallet"), coin)
+	if label, ok := body["label"].(string); ok {
+		wallet.Label = label
+	}
+	s.wallets[wallet.ID] = wallet
+
+	copied := *wallet
+	return &copied, nil
+}
+
+// GetWallet returns the simulated wallet, creating it on first use so wallets registered
+// locally with any BitGo ID can be exercised
+func (s *SimulatedClient) GetWallet(ctx context.Context, walletID, coin string) (*Wallet, error) {
+	if walletID == "" {
+		return nil, fmt.Errorf("wallet ID is required")
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	wallet, ok := s.wallets[walletID]
+	if !ok {
+		wallet = s.simulatedWallet(walletID, coin)
+		s.wallets[walletID] = wallet
+	}
+
+	copied := *wallet
+	return &copied, nil
+}
+
+// simulatedWallet builds a wallet with the configured balance and three fake keys
+func (s *SimulatedClient) simulatedWallet(walletID, coin string) *Wallet {
+	return &Wallet{
+		ID:                     walletID,
+		Label:                  "Simulated " + coin + " wallet",
+		Coin:                   coin,
+		Enterprise:             s.config.Enterprise,
+		Balance:                s.config.Balance,
+		ConfirmedBalance:       s.config.Balance,
+		SpendableBalance:       s.config.Balance,
+		BalanceString:          s.config.Balance,
+		ConfirmedBalanceString: s.config.Balance,
+		SpendableBalanceString: s.config.Balance,
+		Keys:                   []string{walletID + "-user", walletID + "-backup", walletID + "-bitgo"},
+		Multisig:               true,
+		Threshold:              2,
+	}
+}
+
+// GetWalletBalance returns the configured simulated balance
+func (s *SimulatedClient) GetWalletBalance(ctx context.Context, walletID, coin string) (*WalletBalance, error) {
+	wallet, err := s.GetWallet(ctx, walletID, coin)
+	if err != nil {
+		return nil, err
+	}
+
+	return &WalletBalance{
+		WalletID:               wallet.ID,
+		Coin:                   coin,
+		Balance:                wallet.Balance,
+		ConfirmedBalance:       wallet.ConfirmedBalance,
+		SpendableBalance:       wallet.SpendableBalance,
+		BalanceString:          wallet.BalanceString,
+		ConfirmedBalanceString: wallet.ConfirmedBalanceString,
+		SpendableBalanceString: wallet.SpendableBalanceString,
+	}, nil
+}
+
+// GetWalletKeys returns fake public key metadata for the wallet's keys
+func (s *SimulatedClient) GetWalletKeys(ctx context.Context, walletID, coin string) ([]Keychain, error) {
+	wallet, err := s.GetWallet(ctx, walletID, coin)
+	if err != nil {
+		return nil, err
+	}
+
+	keychains := make([]Keychain, 0, len(wallet.Keys))
+	for i, keyID := range wallet.Keys {
+		keychains = append(keychains, Keychain{
+			ID:      keyID,
+			Role:    keyRoles[i],
+			Pub:     "xpub-sim-" + simulatedHash(keyID)[:32],
+			IsBitGo: i == 2,
+		})
+	}
+	return keychains, nil
+}
+
+// GenerateAddress returns a new fake receive address for the wallet
+func (s *SimulatedClient) GenerateAddress(ctx context.Context, walletID, coin string, options *AddressOptions) (*Address, error) {
+	if walletID == "" {
+		return nil, fmt.Errorf("wallet ID is required")
+	}
+	if coin == "" {
+		return nil, fmt.Errorf("coin is required")
+	}
+
+	s.mu.Lock()
+	s.seq++
+	index := s.seq
+	s.mu.Unlock()
+
+	address := &Address{
+		Address:  simulatedAddress(coin, fmt.Sprintf("%s-%d", walletID, index)),
+		Index:    index,
+		Coin:     coin,
+		WalletID: walletID,
+	}
+	if options != nil {
+		if options.Chain != nil {
+			address.Chain = *options.Chain
+		}
+		address.AddressType = options.AddressType
+	}
+	return address, nil
+}
+
+// simulatedAddress derives an address that passes ValidAddressForCoin for the coin
+func simulatedAddress(coin, seed string) string {
+	hash := simulatedHash(seed)
+	info, _ := LookupCoin(coin)
+	switch {
+	case info.Family == "eth" || strings.Contains(strings.ToLower(coin), "eth"):
+		return "0x" + hash[:40]
+	case info.Family == "ltc" && info.Testnet:
+		return "tltc1q" + hash[:38]
+	case info.Family == "ltc":
+		return "ltc1q" + hash[:38]
+	case info.Family == "btc" && info.Testnet:
+		return "tb1q" + hash[:38]
+	case info.Family == "xrp":
+		return "r" + simulatedEncode(hash, "123456789ABCDEFGHJKLMNPQRSTUVWXYZabcdefghijkmnopqrstuvwxyz", 33)
+	case info.Family == "xlm":
+		return "G" + simulatedEncode(hash, "ABCDEFGHIJKLMNOPQRSTUVWXYZ234567", 55)
+	case info.Family == "eos":
+		return simulatedEncode(hash, "abcdefghijklmnopqrstuvwxyz12345", 12)
+	}
+	return "bc1q" + hash[:38]
+}
+
+// simulatedEncode spells out a hex hash in the alphabet, wrapping the hash as needed, so
+// coins whose addresses aren't hex still get a deterministic address of the right shape
+func simulatedEncode(hash, alphabet string, length int) string {
+	out := make([]byte, length)
+	for i := range out {
+		out[i] = alphabet[(int(hash[i%len(hash)])+i)%len(alphabet)]
+	}
+	return string(out)
+}
+
+// BuildTransfer returns a fake unsigned transaction for the recipients
+func (s *SimulatedClient) BuildTransfer(ctx context.Context, walletID, coin string, req BuildTransferRequest) (*BuildTransferResponse, error) {
+	if walletID == "" {
+		return nil, fmt.Errorf("wallet ID is required")
+	}
+	if coin == "" {
+		return nil, fmt.Errorf("coin is required")
+	}
+	if len(req.Recipients) == 0 {
+		return nil, fmt.Errorf("at least one recipient is required")
+	}
+
+	s.mu.Lock()
+	buildID := s.nextID("sim-build")
+	s.mu.Unlock()
+
+	feeRate := req.FeeRate
+	if feeRate <= 0 {
+		feeRate = s.config.FeeRate
+	}
+	// Assume a 250 byte transaction
+	fee := feeRate * 250 / 1000
+	feeInfo := FeeInfo{Fee: fee, FeeString: fmt.Sprintf("%d", fee), FeeRate: feeRate, Size: 250}
+	if IsEVMCoin(coin) {
+		// EVM fees are gas price times gas used
+		gasPrice, err := strconv.ParseInt(req.GasPrice, 10, 64)
+		if err != nil || gasPrice <= 0 {
+			gasPrice = simulatedGasPrice
+		}
+		gasLimit := req.GasLimit
+		if gasLimit <= 0 {
+			gasLimit = simulatedGasLimit
+		}
+		fee = gasPrice * gasLimit
+		feeInfo = FeeInfo{Fee: fee, FeeString: fmt.Sprintf("%d", fee)}
+	}
+	txHex := simulatedHash(buildID, walletID, coin)
+
+	// maxValue builds pay the fee out of the amount, so the recipient gets what's left
+	var entries []TransferEntry
+	if req.MaxValue != "" {
+		maxValue, err := strconv.ParseInt(req.MaxValue, 10, 64)
+		if err != nil || maxValue <= 0 {
+			return nil, APIError{
+				StatusCode: http.StatusBadRequest,
+				Message:    fmt.Sprintf("invalid maxValue %q", req.MaxValue),
+				Name:       "InvalidParameter",
+			}
+		}
+		sent := maxValue - fee
+		if sent <= 0 {
+			return nil, APIError{
+				StatusCode: http.StatusBadRequest,
+				Message:    fmt.Sprintf("insufficient funds: maxValue %d does not cover the fee of %d", maxValue, fee),
+				Name:       "InsufficientBalance",
+			}
+		}
+		entries = []TransferEntry{{
+			Address:     req.Recipients[0].Address,
+			Value:       sent,
+			ValueString: fmt.Sprintf("%d", sent),
+		}}
+	}
+
+	s.logger.Info("Simulated transfer build",
+		"wallet_id", walletID,
+		"coin", coin,
+		"build_id", buildID,
+	)
+
+	return &BuildTransferResponse{
+		Transfer: &Transfer{
+			Coin:    coin,
+			Wallet:  walletID,
+			TxID:    txHex,
+			State:   TransferStatusSigning,
+			Entries: entries,
+		},
+		PrebuildTx: &PrebuildTransaction{
+			TxHex:    txHex,
+			FeeInfo:  feeInfo,
+			WalletId: walletID,
+		},
+		FeeInfo: &feeInfo,
+	}, nil
+}
+
+// SubmitTransfer records a pending transfer with a fake txid
+func (s *SimulatedClient) SubmitTransfer(ctx context.Context, walletID, coin string, req SubmitTransferRequest) (*SubmitTransferResponse, error) {
+	if walletID == "" {
+		return nil, fmt.Errorf("wallet ID is required")
+	}
+	if coin == "" {
+		return nil, fmt.Errorf("coin is required")
+	}
+	if req.TxHex == "" && req.HalfSigned == nil {
+		return nil, fmt.Errorf("either txHex or halfSigned is required")
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	// Like BitGo, a sequenceId can only be submitted once
+	if req.SequenceId != "" && s.findBySequenceID(walletID, req.SequenceId) != nil {
+		return nil, APIError{
+			StatusCode: http.StatusBadRequest,
+			Message:    fmt.Sprintf("transaction with sequenceId %s already submitted", req.SequenceId),
+			Name:       "TransactionAlreadySubmitted",
+		}
+	}
+
+	now := time.Now()
+	transferID := s.nextID("sim-transfer")
+	transfer := Transfer{
+		ID:           transferID,
+		Coin:         coin,
+		Wallet:       walletID,
+		Enterprise:   s.config.Enterprise,
+		TxID:         simulatedHash(transferID, req.TxHex),
+		SequenceID:   req.SequenceId,
+		Date:         now,
+		Type:         TransferTypeSend,
+		State:        TransferStatusPending,
+		Comment:      req.Comment,
+		History:      []TransferHistory{{Date: now, Action: "signed"}},
+		CreatedTime:  now,
+		ModifiedTime: now,
+	}
+	s.transfers[transferID] = &simulatedTransfer{transfer: transfer}
+
+	s.logger.Info("Simulated transfer submitted",
+		"wallet_id", walletID,
+		"coin", coin,
+		"transfer_id", transferID,
+	)
+
+	copied := transfer
+	return &SubmitTransferResponse{Transfer: &copied, TxID: transfer.TxID, Status: string(transfer.State)}, nil
+}
+
+// GetTransfer returns a simulated transfer. Each call counts as a poll; once the transfer has
+// been polled ConfirmAfterPolls times it reports confirmed with enough confirmations.
+func (s *SimulatedClient) GetTransfer(ctx context.Context, walletID, coin, transferID string) (*Transfer, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	simulated, ok := s.transfers[transferID]
+	if !ok || simulated.transfer.Wallet != walletID {
+		return nil, APIError{StatusCode: http.StatusNotFound, Message: "transfer not found", Name: "NotFound"}
+	}
+
+	simulated.polls++
+	if simulated.transfer.State == TransferStatusPending && simulated.polls >= s.config.ConfirmAfterPolls {
+		now := time.Now()
+		simulated.transfer.State = TransferStatusConfirmed
+		simulated.transfer.Confirmations = RequiredConfirmations(coin)
+		simulated.transfer.Height = int64(800000 + s.seq)
+		simulated.transfer.ConfirmedTime = &now
+		simulated.transfer.ModifiedTime = now
+		simulated.transfer.History = append(simulated.transfer.History, TransferHistory{Date: now, Action: "confirmed"})
+	}
+
+	copied := simulated.transfer
+	return &copied, nil
+}
+
+// GetTransferBySequenceID returns the simulated transfer submitted with the sequenceId. Unlike
+// GetTransfer it doesn't count as a poll.
+func (s *SimulatedClient) GetTransferBySequenceID(ctx context.Context, walletID, coin, sequenceID string) (*Transfer, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	simulated := s.findBySequenceID(walletID, sequenceID)
+	if simulated == nil {
+		return nil, APIError{StatusCode: http.StatusNotFound, Message: "transfer not found", Name: "NotFound"}
+	}
+
+	copied := simulated.transfer
+	return &copied, nil
+}
+
+// findBySequenceID returns the wallet's transfer submitted with the sequenceId, or nil. The
+// caller holds s.mu.
+func (s *SimulatedClient) findBySequenceID(walletID, sequenceID string) *simulatedTransfer {
+	if sequenceID == "" {
+		return nil
+	}
+	for _, simulated := range s.transfers {
+		if simulated.transfer.Wallet == walletID && simulated.transfer.SequenceID == sequenceID {
+			return simulated
+		}
+	}
+	return nil
+}
+
+// ListTransfers returns the simulated transfers for a wallet, newest first
+func (s *SimulatedClient) ListTransfers(ctx context.Context, walletID, coin string, options *TransferListOptions) (*TransferListResponse, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	transfers := []Transfer{}
+	for _, simulated := range s.transfers {
+		if simulated.transfer.Wallet != walletID {
+			continue
+		}
+		if options != nil && options.State != "" && simulated.transfer.State != options.State {
+			continue
+		}
+		transfers = append(transfers, simulated.transfer)
+	}
+	sort.Slice(transfers, func(i, j int) bool { return transfers[i].ID > transfers[j].ID })
+
+	total := len(transfers)
+	nextBatchPrevID := ""
+	if options != nil {
+		if options.PrevID != "" {
+			// Pages continue after the last transfer of the previous one
+			start := sort.Search(len(transfers), func(i int) bool { return transfers[i].ID < options.PrevID })
+			transfers = transfers[start:]
+		}
+		if options.Skip > 0 {
+			if options.Skip >= len(transfers) {
+				transfers = []Transfer{}
+			} else {
+				transfers = transfers[options.Skip:]
+			}
+		}
+		if options.Limit > 0 && len(transfers) > options.Limit {
+			transfers = transfers[:options.Limit]
+			nextBatchPrevID = transfers[len(transfers)-1].ID
+		}
+	}
+
+	return &TransferListResponse{Transfers: transfers, Count: len(transfers), Total: total, NextBatchPrevId: nextBatchPrevID}, nil
+}
+
+// Simulated EVM fees: a 20 gwei gas price and the gas a plain send uses
+const (
+	simulatedGasPrice = 20_000_000_000
+	simulatedGasLimit = 21000
+)
+
+// EstimateFee returns the configured simulated fee rate, or a fixed gas price for EVM coins
+func (s *SimulatedClient) EstimateFee(ctx context.Context, coin string, numBlocks int) (*FeeEstimate, error) {
+	if coin == "" {
+		return nil, fmt.Errorf("coin is required")
+	}
+	if numBlocks <= 0 {
+		numBlocks = 2
+	}
+	if IsEVMCoin(coin) {
+		return &FeeEstimate{GasPrice: json.Number(strconv.FormatInt(simulatedGasPrice, 10)), GasLimit: simulatedGasLimit, NumBlocks: numBlocks, Confidence: 80}, nil
+	}
+	return &FeeEstimate{FeePerKb: s.config.FeeRate, NumBlocks: numBlocks, Confidence: 80}, nil
+}
+
+// GetUSDPrice returns a fixed price for the coin
+func (s *SimulatedClient) GetUSDPrice(ctx context.Context, coin string) (float64, error) {
+	if coin == "" {
+		return 0, fmt.Errorf("coin is required")
+	}
+	if price, ok := simulatedUSDPrices[strings.ToLower(coin)]; ok {
+		return price, nil
+	}
+	return 1, nil
+}
+
+// ValidateAddress applies the same format checks as the real client
+func (s *SimulatedClient) ValidateAddress(ctx context.Context, coin, address string) (bool, error) {
+	return ValidAddressForCoin(coin, address), nil
+}
+
+// makeRequest answers the untyped endpoints the services call. Pending approval lists are
+// always empty; anything else is reported as not found.
+func (s *SimulatedClient) makeRequest(ctx context.Context, opts RequestOptions) (*http.Response, error) {
+	if opts.Method == http.MethodGet && strings.HasPrefix(opts.Path, "/pendingapprovals") {
+		body, err := json.Marshal(ListApprovalsResponse{Approvals: []ApprovalInfo{}})
+		if err != nil {
+			return nil, err
+		}
+		return &http.Response{
+			StatusCode: http.StatusOK,
+			Header:     http.Header{"Content-Type": []string{"application/json"}},
+			Body:       io.NopCloser(bytes.NewReader(body)),
+		}, nil
+	}
+
+	return nil, APIError{
+		StatusCode: http.StatusNotFound,
+		Message:    fmt.Sprintf("%s %s is not supported in simulation mode", opts.Method, opts.Path),
+		Name:       "NotFound",
+	}
+}
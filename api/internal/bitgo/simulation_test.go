@@ -0,0 +1,74 @@
+package bitgo
+
+import (
+	"context"
+	"testing"
+)
+
+func TestSimulatedAddressValidForEveryCoin(t *testing.T) {
+	for coin := range coinRegistry {
+		address := simulatedAddress(coin, "wallet-1|0")
+		if !ValidAddressForCoin(coin, address) {
+			t.Errorf("simulatedAddress(%q) = %q, which ValidAddressForCoin rejects", coin, address)
+		}
+	}
+}
+
+func TestSimulatedAddressDeterministic(t *testing.T) {
+	if a, b := simulatedAddress("xrp", "seed"), simulatedAddress("xrp", "seed"); a != b {
+		t.Errorf("simulatedAddress is not deterministic: %q != %q", a, b)
+	}
+	if a, b := simulatedAddress("xrp", "seed-a"), simulatedAddress("xrp", "seed-b"); a == b {
+		t.Errorf("different seeds gave the same address %q", a)
+	}
+}
+
+func TestSimulatedHotTransferLifecycle(t *testing.T) {
+	client := NewSimulatedClient(SimulationConfig{ConfirmAfterPolls: 2}, testLogger{})
+	ctx := context.Background()
+
+	build, err := client.BuildTransfer(ctx, "wallet-1", "btc", BuildTransferRequest{
+		Recipients: []TransferRecipient{{Address: simulatedAddress("btc", "recipient"), Amount: 100000}},
+	})
+	if err != nil {
+		t.Fatalf("BuildTransfer() error = %v", err)
+	}
+	if build.PrebuildTx == nil || build.PrebuildTx.TxHex == "" || build.FeeInfo == nil || build.FeeInfo.Fee <= 0 {
+		t.Fatalf("BuildTransfer() = %+v, want a prebuilt transaction with a fee", build)
+	}
+
+	submitted, err := client.SubmitTransfer(ctx, "wallet-1", "btc", SubmitTransferRequest{TxHex: build.PrebuildTx.TxHex, SequenceId: "seq-1"})
+	if err != nil {
+		t.Fatalf("SubmitTransfer() error = %v", err)
+	}
+	if submitted.Transfer == nil || submitted.Transfer.ID == "" || submitted.TxID == "" {
+		t.Fatalf("SubmitTransfer() = %+v, want a transfer ID and txid", submitted)
+	}
+	if _, err := client.SubmitTransfer(ctx, "wallet-1", "btc", SubmitTransferRequest{TxHex: build.PrebuildTx.TxHex, SequenceId: "seq-1"}); !IsAlreadySubmitted(err) {
+		t.Errorf("resubmitting the sequenceId error = %v, want already submitted", err)
+	}
+
+	mapper := NewStatusMapper()
+	wantStates := []TransferStatus{TransferStatusPending, TransferStatusConfirmed, TransferStatusConfirmed}
+	for poll, want := range wantStates {
+		transfer, err := client.GetTransfer(ctx, "wallet-1", "btc", submitted.Transfer.ID)
+		if err != nil {
+			t.Fatalf("GetTransfer() poll %d error = %v", poll+1, err)
+		}
+		if transfer.State != want {
+			t.Fatalf("poll %d state = %s, want %s", poll+1, transfer.State, want)
+		}
+		if want == TransferStatusConfirmed {
+			if canonical := mapper.NormalizeTransferStatus(transfer.State, transfer); canonical != CanonicalStatusConfirmed {
+				t.Errorf("poll %d canonical status = %s, want %s", poll+1, canonical, CanonicalStatusConfirmed)
+			}
+			if transfer.ConfirmedTime == nil || transfer.TxID != submitted.TxID {
+				t.Errorf("confirmed transfer %+v, want a confirmed time and the submitted txid", transfer)
+			}
+		}
+	}
+
+	if _, err := client.GetTransfer(ctx, "wallet-2", "btc", submitted.Transfer.ID); err == nil {
+		t.Error("GetTransfer() from another wallet succeeded, want not found")
+	}
+}
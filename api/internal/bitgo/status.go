@@ -46,10 +46,21 @@ func NewStatusMapper() *StatusMapper {
 	return &StatusMapper{}
 }
 
-// NormalizeTransferStatus converts BitGo transfer status to canonical status
+// NormalizeTransferStatus converts BitGo transfer status to canonical status. A transfer BitGo
+// reports as confirmed stays broadcast until it has the coin's required confirmations.
 func (sm *StatusMapper) NormalizeTransferStatus(bitgoStatus TransferStatus, transfer *Transfer) CanonicalTransferStatus {
 	switch bitgoStatus {
 	case TransferStatusConfirmed:
+		if transfer != nil {
+			// BitGo only marks a transfer confirmed once it's in a block
+			confirmations := transfer.Confirmations
+			if confirmations < 1 {
+				confirmations = 1
+			}
+			if confirmations < RequiredConfirmations(transfer.Coin) {
+				return CanonicalStatusBroadcast
+			}
+		}
 		return CanonicalStatusConfirmed
 
 	case TransferStatusPending:
@@ -304,6 +315,7 @@ type NormalizedTransfer struct {
 	Risk              TransferRisk            `json:"risk"`
 	SLA               TransferSLA             `json:"sla"`
 	WalletType        CanonicalWalletType     `json:"walletType"`
+	Outputs           TransferOutputs         `json:"outputs"`
 }
 
 // NormalizeTransfer converts a BitGo transfer to our normalized format
@@ -332,5 +344,6 @@ func (sm *StatusMapper) NormalizeTransfer(transfer *Transfer, wallet *Wallet, bu
 		Risk:              risk,
 		SLA:               sla,
 		WalletType:        walletType,
+		Outputs:           CategorizeEntries(transfer.Entries),
 	}
 }
@@ -0,0 +1,30 @@
+package bitgo
+
+import (
+	"testing"
+)
+
+func TestConfirmedTransferNeedsRequiredConfirmations(t *testing.T) {
+	tests := []struct {
+		coin          string
+		confirmations int
+		want          CanonicalTransferStatus
+	}{
+		{coin: "eth", confirmations: 3, want: CanonicalStatusBroadcast},
+		{coin: "eth", confirmations: 11, want: CanonicalStatusBroadcast},
+		{coin: "eth", confirmations: 12, want: CanonicalStatusConfirmed},
+		{coin: "teth", confirmations: 12, want: CanonicalStatusConfirmed},
+		{coin: "ltc", confirmations: 3, want: CanonicalStatusBroadcast},
+		{coin: "ltc", confirmations: 4, want: CanonicalStatusConfirmed},
+		{coin: "btc", confirmations: 1, want: CanonicalStatusConfirmed},
+		{coin: "btc", confirmations: 0, want: CanonicalStatusConfirmed}, // In a block even if BitGo omits the count
+	}
+
+	mapper := NewStatusMapper()
+	for _, tt := range tests {
+		transfer := &Transfer{Coin: tt.coin, State: TransferStatusConfirmed, Confirmations: tt.confirmations}
+		if got := mapper.NormalizeTransferStatus(transfer.State, transfer); got != tt.want {
+			t.Errorf("%s with %d confirmations = %s, want %s", tt.coin, tt.confirmations, got, tt.want)
+		}
+	}
+}
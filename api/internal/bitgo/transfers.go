@@ -4,8 +4,11 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
-	"io"
+	"math/big"
 	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
 	"time"
 
 	"github.com/google/uuid"
@@ -40,6 +43,7 @@ type Transfer struct {
 	Wallet          string            `json:"wallet"`
 	Enterprise      string            `json:"enterprise,omitempty"`
 	TxID            string            `json:"txid,omitempty"`
+	SequenceID      string            `json:"sequenceId,omitempty"`
 	Height          int64             `json:"height,omitempty"`
 	Date            time.Time         `json:"date"`
 	Type            TransferType      `json:"type"`
@@ -61,6 +65,7 @@ type Transfer struct {
 	UnconfirmedTime *time.Time        `json:"unconfirmedTime,omitempty"`
 	CreatedTime     time.Time         `json:"createdTime"`
 	ModifiedTime    time.Time         `json:"modifiedTime"`
+	CoinSpecific    json.RawMessage   `json:"coinSpecific,omitempty"` // See DecodeEVMCoinSpecific for EVM coins
 }
 
 // TransferHistory represents the history of state changes for a transfer
@@ -80,6 +85,41 @@ type TransferEntry struct {
 	IsPayGo     bool   `json:"isPayGo,omitempty"`
 }
 
+// TransferOutputs splits a transfer's entries by role so the actual outputs can be checked.
+// Entries with a negative value are the wallet's own spent amount.
+type TransferOutputs struct {
+	Recipients []TransferEntry `json:"recipients"`
+	Change     []TransferEntry `json:"change"`
+	PayGo      []TransferEntry `json:"payGo"`
+	Inputs     []TransferEntry `json:"inputs"`
+}
+
+// CategorizeEntries sorts a transfer's entries into recipient, change, pay-go and input
+// entries, preserving BitGo's order within each group
+func CategorizeEntries(entries []TransferEntry) TransferOutputs {
+	outputs := TransferOutputs{
+		Recipients: []TransferEntry{},
+		Change:     []TransferEntry{},
+		PayGo:      []TransferEntry{},
+		Inputs:     []TransferEntry{},
+	}
+
+	for _, entry := range entries {
+		switch {
+		case entry.IsPayGo:
+			outputs.PayGo = append(outputs.PayGo, entry)
+		case entry.IsChange:
+			outputs.Change = append(outputs.Change, entry)
+		case entry.Value < 0 || strings.HasPrefix(entry.ValueString, "-"):
+			outputs.Inputs = append(outputs.Inputs, entry)
+		default:
+			outputs.Recipients = append(outputs.Recipients, entry)
+		}
+	}
+
+	return outputs
+}
+
 // BuildTransferRequest represents a request to build a transfer
 type BuildTransferRequest struct {
 	Type                        string               `json:"type,omitempty"`
@@ -87,17 +127,35 @@ type BuildTransferRequest struct {
 	FeeRate                     int64                `json:"feeRate,omitempty"`
 	FeeMultiplier               float64              `json:"feeMultiplier,omitempty"`
 	MaxFeeRate                  int64                `json:"maxFeeRate,omitempty"`
+	GasPrice                    string               `json:"gasPrice,omitempty"` // EVM gas price in wei
+	GasLimit                    int64                `json:"gasLimit,omitempty"` // EVM gas limit
 	MinConfirms                 int                  `json:"minConfirms,omitempty"`
 	EnforceMinConfirmsForChange bool                 `json:"enforceMinConfirmsForChange,omitempty"`
 	SequenceId                  string               `json:"sequenceId,omitempty"`
 	Comment                     string               `json:"comment,omitempty"`
 	Otp                         string               `json:"otp,omitempty"`
-	Memo                        string               `json:"memo,omitempty"`
+	Memo                        *TransferMemo        `json:"memo,omitempty"`
 	CpfpTxIds                   []string             `json:"cpfpTxIds,omitempty"`
 	CpfpFeeRate                 int64                `json:"cpfpFeeRate,omitempty"`
-	MaxValue                    int64                `json:"maxValue,omitempty"`
+	MaxValue                    string               `json:"maxValue,omitempty"` // Send up to this many base units, fees included
 	Prebuild                    *PrebuildTransaction `json:"prebuild,omitempty"`
 	Preview                     bool                 `json:"preview,omitempty"`
+	Nonce                       string               `json:"nonce,omitempty"` // EVM nonce to fill, for fillNonce builds
+}
+
+// TransferMemo is a memo sent with a transfer, in the typed form BitGo takes for coins such
+// as XLM
+type TransferMemo struct {
+	Type  string `json:"type"`
+	Value string `json:"value"`
+}
+
+// TextMemo returns a text memo, or nil when there is no memo to send
+func TextMemo(value string) *TransferMemo {
+	if value = strings.TrimSpace(value); value == "" {
+		return nil
+	}
+	return &TransferMemo{Type: "text", Value: value}
 }
 
 // TransferRecipient represents a recipient in a transfer
@@ -131,7 +189,30 @@ type BuildTransferResponse struct {
 	PrebuildTx   *PrebuildTransaction   `json:"prebuildTx,omitempty"`
 	BuildParams  map[string]interface{} `json:"buildParams,omitempty"`
 	FeeInfo      *FeeInfo               `json:"feeInfo,omitempty"`
-	CoinSpecific interface{}            `json:"coinSpecific,omitempty"`
+	CoinSpecific json.RawMessage        `json:"coinSpecific,omitempty"`
+}
+
+// SentAmount returns the base units a maxValue build pays to the recipient address, read
+// from the built transfer's recipient entries. ok is false when BitGo reports no entries for
+// the address.
+func (r *BuildTransferResponse) SentAmount(address string) (amount *big.Int, ok bool) {
+	if r.Transfer == nil {
+		return nil, false
+	}
+	total := new(big.Int)
+	found := false
+	for _, recipient := range CategorizeEntries(r.Transfer.Entries).Recipients {
+		if recipient.Address != address {
+			continue
+		}
+		value, parsed := new(big.Int).SetString(recipient.ValueString, 10)
+		if !parsed {
+			value = big.NewInt(recipient.Value)
+		}
+		total.Add(total, value)
+		found = true
+	}
+	return total, found
 }
 
 // SubmitTransferRequest represents a request to submit a transfer
@@ -140,6 +221,7 @@ type SubmitTransferRequest struct {
 	HalfSigned map[string]interface{} `json:"halfSigned,omitempty"`
 	Comment    string                 `json:"comment,omitempty"`
 	Otp        string                 `json:"otp,omitempty"`
+	SequenceId string                 `json:"sequenceId,omitempty"` // Lets GetTransferBySequenceID find the transfer if the submit's outcome is lost
 }
 
 // SubmitTransferResponse represents the response from submitting a transfer
@@ -175,6 +257,9 @@ func (c *Client) BuildTransfer(ctx context.Context, walletID, coin string, req B
 		"recipients_count", len(req.Recipients),
 	)
 
+	ctx, cancel := withOperationTimeout(ctx, c.timeouts.build)
+	defer cancel()
+
 	resp, err := c.makeRequest(ctx, RequestOptions{
 		Method: http.MethodPost,
 		Path:   path,
@@ -189,14 +274,9 @@ func (c *Client) BuildTransfer(ctx context.Context, walletID, coin string, req B
 	}
 	defer resp.Body.Close()
 
-	body, err := io.ReadAll(resp.Body)
-	if err != nil {
-		return nil, fmt.Errorf("failed to read response body: %w", err)
-	}
-
 	var result BuildTransferResponse
-	if err := json.Unmarshal(body, &result); err != nil {
-		return nil, fmt.Errorf("failed to unmarshal response: %w", err)
+	if err := c.decodeResponse(resp, &result); err != nil {
+		return nil, err
 	}
 
 	c.logger.Info("Transfer built successfully",
@@ -227,6 +307,9 @@ func (c *Client) SubmitTransfer(ctx context.Context, walletID, coin string, req
 		"coin", coin,
 	)
 
+	ctx, cancel := withOperationTimeout(ctx, c.timeouts.submit)
+	defer cancel()
+
 	resp, err := c.makeRequest(ctx, RequestOptions{
 		Method: http.MethodPost,
 		Path:   path,
@@ -240,14 +323,9 @@ func (c *Client) SubmitTransfer(ctx context.Context, walletID, coin string, req
 	}
 	defer resp.Body.Close()
 
-	body, err := io.ReadAll(resp.Body)
-	if err != nil {
-		return nil, fmt.Errorf("failed to read response body: %w", err)
-	}
-
 	var result SubmitTransferResponse
-	if err := json.Unmarshal(body, &result); err != nil {
-		return nil, fmt.Errorf("failed to unmarshal response: %w", err)
+	if err := c.decodeResponse(resp, &result); err != nil {
+		return nil, err
 	}
 
 	c.logger.Info("Transfer submitted successfully",
@@ -273,6 +351,9 @@ func (c *Client) GetTransfer(ctx context.Context, walletID, coin, transferID str
 
 	path := fmt.Sprintf("/%s/wallet/%s/transfer/%s", coin, walletID, transferID)
 
+	ctx, cancel := withOperationTimeout(ctx, c.timeouts.get)
+	defer cancel()
+
 	resp, err := c.makeRequest(ctx, RequestOptions{
 		Method: http.MethodGet,
 		Path:   path,
@@ -285,14 +366,9 @@ func (c *Client) GetTransfer(ctx context.Context, walletID, coin, transferID str
 	}
 	defer resp.Body.Close()
 
-	body, err := io.ReadAll(resp.Body)
-	if err != nil {
-		return nil, fmt.Errorf("failed to read response body: %w", err)
-	}
-
 	var transfer Transfer
-	if err := json.Unmarshal(body, &transfer); err != nil {
-		return nil, fmt.Errorf("failed to unmarshal response: %w", err)
+	if err := c.decodeResponse(resp, &transfer); err != nil {
+		return nil, err
 	}
 
 	c.logger.Info("Retrieved transfer successfully",
@@ -305,6 +381,51 @@ func (c *Client) GetTransfer(ctx context.Context, walletID, coin, transferID str
 	return &transfer, nil
 }
 
+// GetTransferBySequenceID retrieves the transfer submitted with the given sequenceId, e.g. to
+// recover the id and txid of a submit BitGo reports as already done
+func (c *Client) GetTransferBySequenceID(ctx context.Context, walletID, coin, sequenceID string) (*Transfer, error) {
+	if walletID == "" {
+		return nil, fmt.Errorf("wallet ID is required")
+	}
+	if coin == "" {
+		return nil, fmt.Errorf("coin is required")
+	}
+	if sequenceID == "" {
+		return nil, fmt.Errorf("sequence ID is required")
+	}
+
+	path := fmt.Sprintf("/%s/wallet/%s/transfer/sequenceId/%s", coin, walletID, url.PathEscape(sequenceID))
+
+	ctx, cancel := withOperationTimeout(ctx, c.timeouts.get)
+	defer cancel()
+
+	resp, err := c.makeRequest(ctx, RequestOptions{
+		Method: http.MethodGet,
+		Path:   path,
+		Headers: map[string]string{
+			"Accept": "application/json",
+		},
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get transfer by sequence ID: %w", err)
+	}
+	defer resp.Body.Close()
+
+	var transfer Transfer
+	if err := c.decodeResponse(resp, &transfer); err != nil {
+		return nil, err
+	}
+
+	c.logger.Info("Retrieved transfer by sequence ID",
+		"wallet_id", walletID,
+		"coin", coin,
+		"sequence_id", sequenceID,
+		"transfer_id", transfer.ID,
+	)
+
+	return &transfer, nil
+}
+
 // ListTransfers retrieves transfers for a wallet
 func (c *Client) ListTransfers(ctx context.Context, walletID, coin string, options *TransferListOptions) (*TransferListResponse, error) {
 	if walletID == "" {
@@ -316,6 +437,28 @@ func (c *Client) ListTransfers(ctx context.Context, walletID, coin string, optio
 
 	path := fmt.Sprintf("/%s/wallet/%s/transfer", coin, walletID)
 
+	if options != nil {
+		query := url.Values{}
+		if options.Limit > 0 {
+			query.Set("limit", strconv.Itoa(options.Limit))
+		}
+		if options.State != "" {
+			query.Set("state", string(options.State))
+		}
+		if options.Type != "" {
+			query.Set("type", string(options.Type))
+		}
+		if options.PrevID != "" {
+			query.Set("prevId", options.PrevID)
+		}
+		if len(query) > 0 {
+			path += "?" + query.Encode()
+		}
+	}
+
+	ctx, cancel := withOperationTimeout(ctx, c.timeouts.list)
+	defer cancel()
+
 	resp, err := c.makeRequest(ctx, RequestOptions{
 		Method: http.MethodGet,
 		Path:   path,
@@ -328,14 +471,9 @@ func (c *Client) ListTransfers(ctx context.Context, walletID, coin string, optio
 	}
 	defer resp.Body.Close()
 
-	body, err := io.ReadAll(resp.Body)
-	if err != nil {
-		return nil, fmt.Errorf("failed to read response body: %w", err)
-	}
-
 	var result TransferListResponse
-	if err := json.Unmarshal(body, &result); err != nil {
-		return nil, fmt.Errorf("failed to unmarshal response: %w", err)
+	if err := c.decodeResponse(resp, &result); err != nil {
+		return nil, err
 	}
 
 	c.logger.Info("Listed transfers successfully",
@@ -356,6 +494,7 @@ type TransferListOptions struct {
 	SearchLabel string         `json:"searchLabel,omitempty"`
 	StartDate   *time.Time     `json:"startDate,omitempty"`
 	EndDate     *time.Time     `json:"endDate,omitempty"`
+	PrevID      string         `json:"prevId,omitempty"` // NextBatchPrevId of the previous page
 }
 
 // TransferListResponse represents the response from listing transfers
@@ -381,9 +520,10 @@ func (c *Client) BuildAndSubmitTransfer(ctx context.Context, walletID, coin stri
 
 	// For custodial wallets, the transaction should be ready to submit
 	submitReq := SubmitTransferRequest{
-		TxHex:   buildResp.PrebuildTx.TxHex,
-		Comment: buildReq.Comment,
-		Otp:     buildReq.Otp,
+		TxHex:      buildResp.PrebuildTx.TxHex,
+		Comment:    buildReq.Comment,
+		Otp:        buildReq.Otp,
+		SequenceId: buildReq.SequenceId,
 	}
 
 	// Submit the transaction
@@ -0,0 +1,55 @@
+package bitgo
+
+import "testing"
+
+func TestCategorizeEntries(t *testing.T) {
+	entries := []TransferEntry{
+		{Address: "wallet-input", Value: -150000, ValueString: "-150000"},
+		{Address: "recipient-1", Value: 100000, ValueString: "100000"},
+		{Address: "change-1", Value: 39000, ValueString: "39000", IsChange: true},
+		{Address: "paygo-1", Value: 1000, ValueString: "1000", IsPayGo: true},
+		{Address: "recipient-2", Value: 9000, ValueString: "9000"},
+		{Address: "paygo-change", Value: 500, ValueString: "500", IsChange: true, IsPayGo: true},
+		{Address: "wallet-input-2", ValueString: "-10000"},
+	}
+
+	outputs := CategorizeEntries(entries)
+
+	tests := []struct {
+		category string
+		got      []TransferEntry
+		want     []string
+	}{
+		{category: "recipients", got: outputs.Recipients, want: []string{"recipient-1", "recipient-2"}},
+		{category: "change", got: outputs.Change, want: []string{"change-1"}},
+		{category: "pay-go", got: outputs.PayGo, want: []string{"paygo-1", "paygo-change"}},
+		{category: "inputs", got: outputs.Inputs, want: []string{"wallet-input", "wallet-input-2"}},
+	}
+	for _, tt := range tests {
+		if len(tt.got) != len(tt.want) {
+			t.Errorf("%s = %v, want %v", tt.category, tt.got, tt.want)
+			continue
+		}
+		for i, entry := range tt.got {
+			if entry.Address != tt.want[i] {
+				t.Errorf("%s[%d] = %s, want %s", tt.category, i, entry.Address, tt.want[i])
+			}
+		}
+	}
+
+	// Change and pay-go outputs go back to the wallet or BitGo, not to the recipients
+	var recipientTotal int64
+	for _, entry := range outputs.Recipients {
+		recipientTotal += entry.Value
+	}
+	if recipientTotal != 109000 {
+		t.Errorf("recipient total = %d, want 109000", recipientTotal)
+	}
+}
+
+func TestCategorizeEntriesWithoutEntries(t *testing.T) {
+	outputs := CategorizeEntries(nil)
+	if outputs.Recipients == nil || outputs.Change == nil || outputs.PayGo == nil || outputs.Inputs == nil {
+		t.Errorf("CategorizeEntries(nil) = %+v, want empty, non-nil groups", outputs)
+	}
+}
@@ -0,0 +1,60 @@
+package bitgo
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+)
+
+// User represents the BitGo user that owns the access token
+type User struct {
+	ID       string `json:"id"`
+	Username string `json:"username"`
+	Name     struct {
+		Full string `json:"full"`
+	} `json:"name"`
+	Email struct {
+		Email    string `json:"email"`
+		Verified bool   `json:"verified"`
+	} `json:"email"`
+}
+
+// userResponse wraps the user returned by the /user/me endpoint
+type userResponse struct {
+	User User `json:"user"`
+}
+
+// GetCurrentUser retrieves the user associated with the configured access token
+func (c *Client) GetCurrentUser(ctx context.Context) (*User, error) {
+	if c.accessTokenFor(ctx, RequestOptions{}) == "" {
+		return nil, fmt.Errorf("access token is not configured")
+	}
+
+	resp, err := c.makeRequest(ctx, RequestOptions{
+		Method: http.MethodGet,
+		Path:   "/user/me",
+		Headers: map[string]string{
+			"Accept": "application/json",
+		},
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get current user: %w", err)
+	}
+	defer resp.Body.Close()
+
+	var result userResponse
+	if err := c.decodeResponse(resp, &result); err != nil {
+		return nil, err
+	}
+
+	if result.User.ID == "" {
+		return nil, fmt.Errorf("access token is not associated with a user")
+	}
+
+	c.logger.Info("Validated BitGo session",
+		"user_id", result.User.ID,
+		"username", result.User.Username,
+	)
+
+	return &result.User, nil
+}
@@ -6,6 +6,8 @@ import (
 	"fmt"
 	"io"
 	"net/http"
+	"net/url"
+	"strconv"
 	"time"
 )
 
@@ -33,6 +35,7 @@ type Wallet struct {
 	SpendableBalanceString          string            `json:"spendableBalanceString"`
 	ReceiveAddress                  *Address          `json:"receiveAddress,omitempty"`
 	PendingApprovals                []PendingApproval `json:"pendingApprovals,omitempty"`
+	Keys                            []string          `json:"keys,omitempty"`
 	Multisig                        bool              `json:"multisig"`
 	MultisigType                    string            `json:"multisigType,omitempty"`
 	Threshold                       int               `json:"threshold,omitempty"`
@@ -108,10 +111,10 @@ func (c *Client) ListWallets(ctx context.Context, opts WalletListOptions) (*Wall
 	path := "/wallets"
 
 	// Add enterprise filter if specified
-	if opts.Enterprise != "" || c.enterprise != "" {
+	if opts.Enterprise != "" || c.EnterpriseFor(ctx) != "" {
 		enterprise := opts.Enterprise
 		if enterprise == "" {
-			enterprise = c.enterprise
+			enterprise = c.EnterpriseFor(ctx)
 		}
 		path += "/" + enterprise
 	}
@@ -128,14 +131,9 @@ func (c *Client) ListWallets(ctx context.Context, opts WalletListOptions) (*Wall
 	}
 	defer resp.Body.Close()
 
-	body, err := io.ReadAll(resp.Body)
-	if err != nil {
-		return nil, fmt.Errorf("failed to read response body: %w", err)
-	}
-
 	var result WalletListResponse
-	if err := json.Unmarshal(body, &result); err != nil {
-		return nil, fmt.Errorf("failed to unmarshal response: %w", err)
+	if err := c.decodeResponse(resp, &result); err != nil {
+		return nil, err
 	}
 
 	c.logger.Info("Listed wallets successfully",
@@ -154,7 +152,7 @@ func (c *Client) CreateWalletRaw(ctx context.Context, coin string, body map[stri
 	c.logger.Info("Creating wallet via direct API",
 		"coin", coin,
 		"path", path,
-		"enterprise", c.enterprise,
+		"enterprise", c.EnterpriseFor(ctx),
 	)
 
 	resp, err := c.makeRequest(ctx, RequestOptions{
@@ -208,14 +206,9 @@ func (c *Client) GetWallet(ctx context.Context, walletID, coin string) (*Wallet,
 	}
 	defer resp.Body.Close()
 
-	body, err := io.ReadAll(resp.Body)
-	if err != nil {
-		return nil, fmt.Errorf("failed to read response body: %w", err)
-	}
-
 	var wallet Wallet
-	if err := json.Unmarshal(body, &wallet); err != nil {
-		return nil, fmt.Errorf("failed to unmarshal response: %w", err)
+	if err := c.decodeResponse(resp, &wallet); err != nil {
+		return nil, err
 	}
 
 	c.logger.Info("Retrieved wallet successfully",
@@ -284,6 +277,15 @@ func (c *Client) GenerateAddress(ctx context.Context, walletID, coin string, opt
 		}
 	}
 
+	// An explicit chain already determines the address type
+	_, hasChain := body["chain"]
+	_, hasType := body["addressType"]
+	if !hasChain && !hasType {
+		if addressType := DefaultAddressType(coin); addressType != "" {
+			body["addressType"] = addressType
+		}
+	}
+
 	resp, err := c.makeRequest(ctx, RequestOptions{
 		Method: http.MethodPost,
 		Path:   path,
@@ -334,6 +336,28 @@ func (c *Client) ListWalletAddresses(ctx context.Context, walletID, coin string,
 
 	path := fmt.Sprintf("/%s/wallet/%s/addresses", coin, walletID)
 
+	if options != nil {
+		query := url.Values{}
+		if options.Limit > 0 {
+			query.Set("limit", strconv.Itoa(options.Limit))
+		}
+		if options.Skip > 0 {
+			query.Set("skip", strconv.Itoa(options.Skip))
+		}
+		if options.Chain != nil {
+			query.Set("chains", strconv.Itoa(*options.Chain))
+		}
+		if options.Label != "" {
+			query.Set("labelContains", options.Label)
+		}
+		if options.PrevID != "" {
+			query.Set("prevId", options.PrevID)
+		}
+		if len(query) > 0 {
+			path += "?" + query.Encode()
+		}
+	}
+
 	resp, err := c.makeRequest(ctx, RequestOptions{
 		Method: http.MethodGet,
 		Path:   path,
@@ -365,11 +389,40 @@ func (c *Client) ListWalletAddresses(ctx context.Context, walletID, coin string,
 	return &result, nil
 }
 
-// AddressListOptions holds options for listing addresses
+// ListAllWalletAddresses pages through every address of a wallet by following
+// NextBatchPrevId until BitGo reports no further batch. Limit in options sets the page
+// size; Skip and PrevID are ignored.
+func (c *Client) ListAllWalletAddresses(ctx context.Context, walletID, coin string, options *AddressListOptions) ([]Address, error) {
+	page := AddressListOptions{}
+	if options != nil {
+		page.Limit = options.Limit
+		page.Chain = options.Chain
+		page.Label = options.Label
+	}
+
+	var addresses []Address
+	for {
+		result, err := c.ListWalletAddresses(ctx, walletID, coin, &page)
+		if err != nil {
+			return nil, err
+		}
+		addresses = append(addresses, result.Addresses...)
+
+		if result.NextBatchPrevId == "" || result.NextBatchPrevId == page.PrevID || len(result.Addresses) == 0 {
+			return addresses, nil
+		}
+		page.PrevID = result.NextBatchPrevId
+	}
+}
+
+// AddressListOptions holds options for listing addresses. Chain is a pointer because
+// chain 0 is a valid filter. Label matches addresses whose label contains it.
 type AddressListOptions struct {
-	Limit int `json:"limit,omitempty"`
-	Skip  int `json:"skip,omitempty"`
-	Chain int `json:"chain,omitempty"`
+	Limit  int    `json:"limit,omitempty"`
+	Skip   int    `json:"skip,omitempty"`
+	Chain  *int   `json:"chain,omitempty"`
+	Label  string `json:"label,omitempty"`
+	PrevID string `json:"prevId,omitempty"`
 }
 
 // AddressListResponse represents the response from listing addresses
@@ -0,0 +1,134 @@
+package bitgo
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"sync"
+	"testing"
+)
+
+// newAddressesServer serves a wallet's addresses in pages of pageSize, keyed by prevId, and
+// records the query of every request it receives
+func newAddressesServer(t *testing.T, addresses []string, pageSize int) (*httptest.Server, func() []url.Values) {
+	t.Helper()
+	var mu sync.Mutex
+	var queries []url.Values
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/api/v2/tbtc/wallet/wallet-1/addresses" {
+			t.Errorf("request path = %q, want /api/v2/tbtc/wallet/wallet-1/addresses", r.URL.Path)
+		}
+		query := r.URL.Query()
+		mu.Lock()
+		queries = append(queries, query)
+		mu.Unlock()
+
+		start := 0
+		if prevID := query.Get("prevId"); prevID != "" {
+			for i, address := range addresses {
+				if address == prevID {
+					start = i + 1
+				}
+			}
+		}
+		end := start + pageSize
+		if end > len(addresses) {
+			end = len(addresses)
+		}
+
+		response := AddressListResponse{Total: len(addresses)}
+		for _, address := range addresses[start:end] {
+			response.Addresses = append(response.Addresses, Address{Address: address, Coin: "tbtc"})
+		}
+		response.Count = len(response.Addresses)
+		if end < len(addresses) {
+			response.NextBatchPrevId = addresses[end-1]
+		}
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(response)
+	}))
+	t.Cleanup(server.Close)
+
+	return server, func() []url.Values {
+		mu.Lock()
+		defer mu.Unlock()
+		return append([]url.Values(nil), queries...)
+	}
+}
+
+func TestListWalletAddressesSendsQueryParameters(t *testing.T) {
+	server, queries := newAddressesServer(t, []string{"addr-1", "addr-2", "addr-3"}, 10)
+	client := NewClient(Config{BaseURL: server.URL, AccessToken: "token"}, testLogger{})
+
+	chain := 0
+	tests := []struct {
+		name    string
+		options *AddressListOptions
+		want    url.Values
+	}{
+		{name: "no options", want: url.Values{}},
+		{name: "limit", options: &AddressListOptions{Limit: 25}, want: url.Values{"limit": {"25"}}},
+		{name: "prev id", options: &AddressListOptions{PrevID: "addr-1"}, want: url.Values{"prevId": {"addr-1"}}},
+		{name: "label filter", options: &AddressListOptions{Label: "deposit desk"}, want: url.Values{"labelContains": {"deposit desk"}}},
+		{name: "chain zero", options: &AddressListOptions{Chain: &chain}, want: url.Values{"chains": {"0"}}},
+		{
+			name:    "all together",
+			options: &AddressListOptions{Limit: 2, Skip: 4, Label: "cold", PrevID: "addr-2"},
+			want:    url.Values{"limit": {"2"}, "skip": {"4"}, "labelContains": {"cold"}, "prevId": {"addr-2"}},
+		},
+	}
+
+	for i, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := client.ListWalletAddresses(context.Background(), "wallet-1", "tbtc", tt.options); err != nil {
+				t.Fatalf("ListWalletAddresses() error = %v", err)
+			}
+			got := queries()
+			if len(got) != i+1 {
+				t.Fatalf("requests = %d, want %d", len(got), i+1)
+			}
+			if query := got[i]; query.Encode() != tt.want.Encode() {
+				t.Errorf("query = %q, want %q", query.Encode(), tt.want.Encode())
+			}
+		})
+	}
+}
+
+func TestListAllWalletAddressesConcatenatesPages(t *testing.T) {
+	all := []string{"addr-1", "addr-2", "addr-3", "addr-4", "addr-5"}
+	server, queries := newAddressesServer(t, all, 2)
+	client := NewClient(Config{BaseURL: server.URL, AccessToken: "token"}, testLogger{})
+
+	// Skip and PrevID from the caller are ignored; the label filter is kept on every page
+	options := &AddressListOptions{Limit: 2, Skip: 3, Label: "deposits", PrevID: "addr-4"}
+	addresses, err := client.ListAllWalletAddresses(context.Background(), "wallet-1", "tbtc", options)
+	if err != nil {
+		t.Fatalf("ListAllWalletAddresses() error = %v", err)
+	}
+
+	if len(addresses) != len(all) {
+		t.Fatalf("addresses = %d, want %d", len(addresses), len(all))
+	}
+	for i, address := range addresses {
+		if address.Address != all[i] {
+			t.Errorf("addresses[%d] = %q, want %q", i, address.Address, all[i])
+		}
+	}
+
+	wantPrevIDs := []string{"", "addr-2", "addr-4"}
+	got := queries()
+	if len(got) != len(wantPrevIDs) {
+		t.Fatalf("requests = %d, want %d", len(got), len(wantPrevIDs))
+	}
+	for i, query := range got {
+		if query.Get("prevId") != wantPrevIDs[i] {
+			t.Errorf("page %d prevId = %q, want %q", i, query.Get("prevId"), wantPrevIDs[i])
+		}
+		if query.Get("limit") != "2" || query.Get("labelContains") != "deposits" || query.Has("skip") {
+			t.Errorf("page %d query = %q, want limit=2 and labelContains=deposits without skip", i, query.Encode())
+		}
+	}
+}
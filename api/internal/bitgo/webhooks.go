@@ -0,0 +1,217 @@
+package bitgo
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// WebhookType identifies the event a BitGo webhook reports
+type WebhookType string
+
+const (
+	WebhookTypeTransfer        WebhookType = "transfer"
+	WebhookTypePendingApproval WebhookType = "pendingapproval"
+	WebhookTypeAddressConfirm  WebhookType = "address_confirmation"
+)
+
+// WebhookPayload is a BitGo webhook notification. BitGo's payloads vary by coin and event,
+// so decoding is tolerant: the wallet may arrive as walletId or wallet, numbers may be
+// strings, and unknown fields are ignored. Validate checks what each type must carry.
+type WebhookPayload struct {
+	Type              WebhookType `json:"type"`
+	WalletID          string      `json:"walletId"`
+	Coin              string      `json:"coin,omitempty"`
+	Transfer          string      `json:"transfer,omitempty"`
+	Hash              string      `json:"hash,omitempty"`
+	State             string      `json:"state,omitempty"`
+	PendingApprovalID string      `json:"pendingApprovalId,omitempty"`
+	Address           string      `json:"address,omitempty"`
+	Confirmations     int         `json:"confirmations,omitempty"`
+	Simulation        bool        `json:"simulation,omitempty"`
+}
+
+// webhookWire is the loosely typed shape BitGo webhooks are decoded from
+type webhookWire struct {
+	Type              string          `json:"type"`
+	WalletID          string          `json:"walletId"`
+	Wallet            string          `json:"wallet"`
+	Coin              string          `json:"coin"`
+	Transfer          string          `json:"transfer"`
+	TransferID        string          `json:"transferId"`
+	Hash              string          `json:"hash"`
+	TxID              string          `json:"txid"`
+	State             string          `json:"state"`
+	PendingApprovalID string          `json:"pendingApprovalId"`
+	Address           string          `json:"address"`
+	Confirmations     json.RawMessage `json:"confirmations"`
+	Simulation        bool            `json:"simulation"`
+}
+
+// UnmarshalJSON decodes a webhook, accepting the field aliases BitGo uses across coins
+func (p *WebhookPayload) UnmarshalJSON(data []byte) error {
+	var wire webhookWire
+	if err := json.Unmarshal(data, &wire); err != nil {
+		return err
+	}
+
+	confirmations, err := decodeLooseInt(wire.Confirmations)
+	if err != nil {
+		return fmt.Errorf("confirmations: %w", err)
+	}
+
+	*p = WebhookPayload{
+		Type:              WebhookType(strings.ToLower(strings.TrimSpace(wire.Type))),
+		WalletID:          firstNonEmpty(wire.WalletID, wire.Wallet),
+		Coin:              strings.ToLower(strings.TrimSpace(wire.Coin)),
+		Transfer:          firstNonEmpty(wire.Transfer, wire.TransferID),
+		Hash:              firstNonEmpty(wire.Hash, wire.TxID),
+		State:             strings.TrimSpace(wire.State),
+		PendingApprovalID: strings.TrimSpace(wire.PendingApprovalID),
+		Address:           strings.TrimSpace(wire.Address),
+		Confirmations:     confirmations,
+		Simulation:        wire.Simulation,
+	}
+	return nil
+}
+
+// decodeLooseInt reads an integer sent either as a JSON number or a numeric string
+func decodeLooseInt(raw json.RawMessage) (int, error) {
+	if len(raw) == 0 || string(raw) == "null" {
+		return 0, nil
+	}
+
+	var n int
+	if err := json.Unmarshal(raw, &n); err == nil {
+		return n, nil
+	}
+	var s string
+	if err := json.Unmarshal(raw, &s); err != nil {
+		return 0, fmt.Errorf("expected a number, got %s", raw)
+	}
+	n, err := strconv.Atoi(strings.TrimSpace(s))
+	if err != nil {
+		return 0, fmt.Errorf("expected a number, got %q", s)
+	}
+	return n, nil
+}
+
+func firstNonEmpty(values ...string) string {
+	for _, v := range values {
+		if v = strings.TrimSpace(v); v != "" {
+			return v
+		}
+	}
+	return ""
+}
+
+// WebhookValidationError describes why a webhook payload was rejected
+type WebhookValidationError struct {
+	Field   string
+	Message string
+}
+
+func (e *WebhookValidationError) Error() string {
+	return fmt.Sprintf("%s: %s", e.Field, e.Message)
+}
+
+// Validate checks the fields every webhook needs and those its type requires: transfer
+// webhooks must name the transfer or its hash, pending-approval webhooks the approval.
+func (p *WebhookPayload) Validate() error {
+	if p.Type == "" {
+		return &WebhookValidationError{Field: "type", Message: "is required"}
+	}
+	if p.WalletID == "" {
+		return &WebhookValidationError{Field: "walletId", Message: "is required"}
+	}
+
+	switch p.Type {
+	case WebhookTypeTransfer:
+		if p.Transfer == "" && p.Hash == "" {
+			return &WebhookValidationError{Field: "transfer", Message: "transfer or hash is required"}
+		}
+	case WebhookTypePendingApproval:
+		if p.PendingApprovalID == "" {
+			return &WebhookValidationError{Field: "pendingApprovalId", Message: "is required"}
+		}
+	}
+	if p.Confirmations < 0 {
+		return &WebhookValidationError{Field: "confirmations", Message: "must not be negative"}
+	}
+
+	return nil
+}
+
+// DecodeWebhookPayload decodes and validates a webhook body
+func DecodeWebhookPayload(body []byte) (*WebhookPayload, error) {
+	if len(bytes.TrimSpace(body)) == 0 {
+		return nil, &WebhookValidationError{Field: "body", Message: "is empty"}
+	}
+
+	var payload WebhookPayload
+	if err := json.Unmarshal(body, &payload); err != nil {
+		return nil, &WebhookValidationError{Field: "body", Message: err.Error()}
+	}
+	if err := payload.Validate(); err != nil {
+		return nil, err
+	}
+	return &payload, nil
+}
+
+// maxLoggedWebhookBody caps how much of a webhook body is kept for diagnostics
+const maxLoggedWebhookBody = 2048
+
+// RedactWebhookBody returns a webhook body safe to log. Sensitive fields are replaced at
+// any depth; bodies that aren't JSON objects are only described by their length.
+func RedactWebhookBody(body []byte) string {
+	var data interface{}
+	if err := json.Unmarshal(body, &data); err != nil {
+		return fmt.Sprintf("[unparseable body, %d bytes]", len(body))
+	}
+
+	redacted, err := json.Marshal(redactValue(data, nil))
+	if err != nil {
+		return "[REDACTION_ERROR]"
+	}
+	if len(redacted) > maxLoggedWebhookBody {
+		return string(redacted[:maxLoggedWebhookBody]) + "...[truncated]"
+	}
+	return string(redacted)
+}
+
+// redactValue replaces sensitiveFields and any extra fields in nested objects and arrays
+func redactValue(value interface{}, extra []string) interface{} {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		for key, inner := range v {
+			if isSensitiveField(key, extra) {
+				v[key] = "[REDACTED]"
+				continue
+			}
+			v[key] = redactValue(inner, extra)
+		}
+		return v
+	case []interface{}:
+		for i, inner := range v {
+			v[i] = redactValue(inner, extra)
+		}
+		return v
+	default:
+		return v
+	}
+}
+
+func isSensitiveField(key string, extra []string) bool {
+	return containsFold(sensitiveFields, key) || containsFold(extra, key)
+}
+
+func containsFold(fields []string, key string) bool {
+	for _, field := range fields {
+		if strings.EqualFold(key, field) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,56 @@
+package bitgo
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestDecodeWebhookPayload(t *testing.T) {
+	tests := []struct {
+		name      string
+		body      string
+		want      WebhookPayload
+		wantField string // Field of the expected validation error; empty when decoding succeeds
+	}{
+		{
+			name: "transfer",
+			body: `{"type":"transfer","walletId":"wallet-1","coin":"BTC","transfer":"transfer-1","hash":"txid-1","state":"confirmed","confirmations":"3"}`,
+			want: WebhookPayload{Type: WebhookTypeTransfer, WalletID: "wallet-1", Coin: "btc", Transfer: "transfer-1", Hash: "txid-1", State: "confirmed", Confirmations: 3},
+		},
+		{
+			name: "transfer with aliased fields",
+			body: `{"type":"Transfer","wallet":"wallet-1","transferId":"transfer-1","txid":"txid-1","confirmations":2}`,
+			want: WebhookPayload{Type: WebhookTypeTransfer, WalletID: "wallet-1", Transfer: "transfer-1", Hash: "txid-1", Confirmations: 2},
+		},
+		{
+			name: "pending approval",
+			body: `{"type":"pendingapproval","walletId":"wallet-1","pendingApprovalId":"approval-1","state":"pending"}`,
+			want: WebhookPayload{Type: WebhookTypePendingApproval, WalletID: "wallet-1", PendingApprovalID: "approval-1", State: "pending"},
+		},
+		{name: "pending approval without its id", body: `{"type":"pendingapproval","walletId":"wallet-1"}`, wantField: "pendingApprovalId"},
+		{name: "transfer without transfer or hash", body: `{"type":"transfer","walletId":"wallet-1"}`, wantField: "transfer"},
+		{name: "missing wallet", body: `{"type":"transfer","transfer":"transfer-1"}`, wantField: "walletId"},
+		{name: "malformed JSON", body: `{"type":"transfer","walletId":`, wantField: "body"},
+		{name: "non-numeric confirmations", body: `{"type":"transfer","walletId":"wallet-1","transfer":"transfer-1","confirmations":"many"}`, wantField: "body"},
+		{name: "empty body", body: "  ", wantField: "body"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := DecodeWebhookPayload([]byte(tt.body))
+			if tt.wantField != "" {
+				var validationErr *WebhookValidationError
+				if !errors.As(err, &validationErr) || validationErr.Field != tt.wantField {
+					t.Errorf("DecodeWebhookPayload() error = %v, want a %s validation error", err, tt.wantField)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("DecodeWebhookPayload() error = %v", err)
+			}
+			if *got != tt.want {
+				t.Errorf("DecodeWebhookPayload() = %+v, want %+v", *got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,15 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// BlockedAddress is a recipient address no transfer may be sent to
+type BlockedAddress struct {
+	ID        uuid.UUID `json:"id" db:"id"`
+	Address   string    `json:"address" db:"address"`
+	Reason    *string   `json:"reason" db:"reason"`
+	CreatedAt time.Time `json:"created_at" db:"created_at"`
+}
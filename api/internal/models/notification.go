@@ -0,0 +1,39 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+)
+
+// NotificationDeliveryStatus is where a notification is in its delivery
+type NotificationDeliveryStatus string
+
+const (
+	NotificationDeliveryPending   NotificationDeliveryStatus = "pending"
+	NotificationDeliveryRetrying  NotificationDeliveryStatus = "retrying"
+	NotificationDeliveryDelivered NotificationDeliveryStatus = "delivered"
+	NotificationDeliveryFailed    NotificationDeliveryStatus = "failed"
+	NotificationDeliveryDropped   NotificationDeliveryStatus = "dropped"
+)
+
+// NotificationRecord is the stored delivery trail of one notification
+type NotificationRecord struct {
+	ID          uuid.UUID                  `json:"id" db:"id"`
+	TransferID  *uuid.UUID                 `json:"transfer_id" db:"transfer_id"`
+	Type        string                     `json:"type" db:"type"`
+	Priority    string                     `json:"priority" db:"priority"`
+	Title       string                     `json:"title" db:"title"`
+	Message     string                     `json:"message" db:"message"`
+	Recipients  pq.StringArray             `json:"recipients" db:"recipients"`
+	Channels    pq.StringArray             `json:"channels" db:"channels"`
+	Status      NotificationDeliveryStatus `json:"status" db:"status"`
+	RetryCount  int                        `json:"retry_count" db:"retry_count"`
+	LastError   *string                    `json:"last_error" db:"last_error"`
+	Sequence    *int64                     `json:"sequence,omitempty" db:"sequence"`
+	CreatedAt   time.Time                  `json:"created_at" db:"created_at"`
+	DeliveredAt *time.Time                 `json:"delivered_at" db:"delivered_at"`
+	FailedAt    *time.Time                 `json:"failed_at" db:"failed_at"`
+	UpdatedAt   time.Time                  `json:"updated_at" db:"updated_at"`
+}
@@ -0,0 +1,37 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// TransferEventType names an entry in a transfer's event log
+type TransferEventType string
+
+const (
+	// TransferEventCreated is the first event of every transfer
+	TransferEventCreated TransferEventType = "created"
+	// TransferEventApprovalRecorded is an approval that didn't yet complete the quorum
+	TransferEventApprovalRecorded TransferEventType = "approval_recorded"
+)
+
+// TransferEventForStatus returns the event type recorded when a transfer moves to status,
+// which is the status itself, e.g. approved, signed, broadcast, confirmed or failed
+func TransferEventForStatus(status TransferStatus) TransferEventType {
+	return TransferEventType(status)
+}
+
+// TransferEvent is one append-only entry in a transfer's event log. Sequence numbers a
+// transfer's events from 1 in the order they happened; ID orders events across all
+// transfers, so consumers can resume the stream after the last ID they saw.
+type TransferEvent struct {
+	ID         int64             `json:"id" db:"id"`
+	TransferID uuid.UUID         `json:"transfer_id" db:"transfer_id"`
+	Sequence   int64             `json:"sequence" db:"sequence"`
+	Type       TransferEventType `json:"type" db:"type"`
+	FromStatus *TransferStatus   `json:"from_status,omitempty" db:"from_status"`
+	ToStatus   TransferStatus    `json:"to_status" db:"to_status"`
+	Payload    JSON              `json:"payload" db:"payload"`
+	CreatedAt  time.Time         `json:"created_at" db:"created_at"`
+}
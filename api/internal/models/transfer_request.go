@@ -1,38 +1,100 @@
 package models
 
 import (
+	"database/sql/driver"
+	"encoding/json"
 	"time"
 
 	"github.com/google/uuid"
+	"github.com/lib/pq"
 )
 
 type TransferRequest struct {
-	ID                 uuid.UUID      `json:"id" db:"id"`
-	WalletID           uuid.UUID      `json:"wallet_id" db:"wallet_id"`
-	RequestedByUserID  uuid.UUID      `json:"requested_by_user_id" db:"requested_by_user_id"`
-	RecipientAddress   string         `json:"recipient_address" db:"recipient_address"`
-	AmountString       string         `json:"amount_string" db:"amount_string"`
-	Coin               string         `json:"coin" db:"coin"`
-	TransferType       WalletType     `json:"transfer_type" db:"transfer_type"`
-	Status             TransferStatus `json:"status" db:"status"`
-	BitgoTransferID    *string        `json:"bitgo_transfer_id" db:"bitgo_transfer_id"`
-	BitgoTxid          *string        `json:"bitgo_txid" db:"bitgo_txid"`
-	TransactionHash    *string        `json:"transaction_hash" db:"transaction_hash"`
-	Fee                *string        `json:"fee" db:"fee"`
-	FeeRate            *string        `json:"fee_rate" db:"fee_rate"`
-	RequiredApprovals  int            `json:"required_approvals" db:"required_approvals"`
-	ReceivedApprovals  int            `json:"received_approvals" db:"received_approvals"`
-	Memo               *string        `json:"memo" db:"memo"`
-	FeeString          *string        `json:"fee_string" db:"fee_string"`
-	EstimatedFeeString *string        `json:"estimated_fee_string" db:"estimated_fee_string"`
-	SubmittedAt        *time.Time     `json:"submitted_at" db:"submitted_at"`
-	ApprovedAt         *time.Time     `json:"approved_at" db:"approved_at"`
-	CompletedAt        *time.Time     `json:"completed_at" db:"completed_at"`
-	FailedAt           *time.Time     `json:"failed_at" db:"failed_at"`
-	CreatedAt          time.Time      `json:"created_at" db:"created_at"`
-	UpdatedAt          time.Time      `json:"updated_at" db:"updated_at"`
+	ID                 uuid.UUID          `json:"id" db:"id"`
+	WalletID           uuid.UUID          `json:"wallet_id" db:"wallet_id"`
+	RequestedByUserID  uuid.UUID          `json:"requested_by_user_id" db:"requested_by_user_id"`
+	RecipientAddress   string             `json:"recipient_address" db:"recipient_address"`
+	AmountString       string             `json:"amount_string" db:"amount_string"`
+	Coin               string             `json:"coin" db:"coin"`
+	TransferType       WalletType         `json:"transfer_type" db:"transfer_type"`
+	Origin             TransferOrigin     `json:"origin" db:"origin"`
+	Status             TransferStatus     `json:"status" db:"status"`
+	StatusReason       *string            `json:"status_reason" db:"status_reason"`
+	BitgoTransferID    *string            `json:"bitgo_transfer_id" db:"bitgo_transfer_id"`
+	BitgoTxid          *string            `json:"bitgo_txid" db:"bitgo_txid"`
+	TransactionHash    *string            `json:"transaction_hash" db:"transaction_hash"`
+	Fee                *string            `json:"fee" db:"fee"`
+	FeeRate            *string            `json:"fee_rate" db:"fee_rate"`
+	RequiredApprovals  int                `json:"required_approvals" db:"required_approvals"`
+	ReceivedApprovals  int                `json:"received_approvals" db:"received_approvals"`
+	Memo               *string            `json:"memo" db:"memo"`
+	DestinationTag     *string            `json:"destination_tag,omitempty" db:"destination_tag"` // XRP destination tag or XLM/EOS memo ID, sent in the recipient address
+	Comment            *string            `json:"comment" db:"comment"`
+	Tags               pq.StringArray     `json:"tags" db:"tags"`
+	FeeString          *string            `json:"fee_string" db:"fee_string"`
+	EstimatedFeeString *string            `json:"estimated_fee_string" db:"estimated_fee_string"`
+	SubmittedAt        *time.Time         `json:"submitted_at" db:"submitted_at"`
+	ApprovedAt         *time.Time         `json:"approved_at" db:"approved_at"`
+	CompletedAt        *time.Time         `json:"completed_at" db:"completed_at"`
+	FailedAt           *time.Time         `json:"failed_at" db:"failed_at"`
+	LastPolledAt       *time.Time         `json:"last_polled_at" db:"last_polled_at"`
+	PollFailures       int                `json:"poll_failures,omitempty" db:"poll_failures"`           // Consecutive failed BitGo lookups
+	PollError          *string            `json:"poll_error,omitempty" db:"poll_error"`                 // Error of the last failed lookup
+	NextPollAt         *time.Time         `json:"next_poll_at,omitempty" db:"next_poll_at"`             // Polling backs off until then
+	NeedsAttentionAt   *time.Time         `json:"needs_attention_at,omitempty" db:"needs_attention_at"` // Polling gave up; set until a manual refresh succeeds
+	ArchivedAt         *time.Time         `json:"archived_at,omitempty" db:"archived_at"`
+	BuildInfo          *TransferBuildInfo `json:"build_info,omitempty" db:"build_info"`
+	Metadata           JSON               `json:"metadata" db:"metadata"`
+	Version            int                `json:"version" db:"version"` // Bumped by every update; Update fails if it moved since the read
+	CreatedAt          time.Time          `json:"created_at" db:"created_at"`
+	UpdatedAt          time.Time          `json:"updated_at" db:"updated_at"`
 }
 
+// TransferBuildInfo summarises the transaction BitGo constructed for a transfer
+type TransferBuildInfo struct {
+	Size          int    `json:"size,omitempty"`
+	InputCount    int    `json:"input_count"`
+	OutputCount   int    `json:"output_count"`
+	ChangeAddress string `json:"change_address,omitempty"`
+	FeeRate       int64  `json:"fee_rate,omitempty"`
+
+	EVM *TransferEVMInfo `json:"evm,omitempty"` // Nonce and gas for EVM coins
+}
+
+// TransferEVMInfo is the nonce and gas an EVM transaction was built with. Gas values are
+// decimal strings in wei.
+type TransferEVMInfo struct {
+	Nonce                *uint64 `json:"nonce,omitempty"`
+	GasLimit             string  `json:"gas_limit,omitempty"`
+	GasPrice             string  `json:"gas_price,omitempty"`
+	MaxFeePerGas         string  `json:"max_fee_per_gas,omitempty"`
+	MaxPriorityFeePerGas string  `json:"max_priority_fee_per_gas,omitempty"`
+}
+
+func (b TransferBuildInfo) Value() (driver.Value, error) {
+	return json.Marshal(b)
+}
+
+func (b *TransferBuildInfo) Scan(value interface{}) error {
+	bytes, ok := value.([]byte)
+	if !ok {
+		return nil
+	}
+	return json.Unmarshal(bytes, b)
+}
+
+// TransferOrigin records which path created a transfer, for auditing
+type TransferOrigin string
+
+const (
+	TransferOriginAPI         TransferOrigin = "api"
+	TransferOriginAuto        TransferOrigin = "auto"
+	TransferOriginRetry       TransferOrigin = "retry"
+	TransferOriginSweep       TransferOrigin = "sweep"
+	TransferOriginConsolidate TransferOrigin = "consolidate"
+	TransferOriginBackfill    TransferOrigin = "backfill" // Imported from the wallet's BitGo transfer history
+)
+
 type TransferStatus string
 
 const (
@@ -47,4 +109,20 @@ const (
 	TransferStatusFailed          TransferStatus = "failed"
 	TransferStatusRejected        TransferStatus = "rejected"
 	TransferStatusCancelled       TransferStatus = "cancelled"
+	TransferStatusExpired         TransferStatus = "expired"
 )
+
+// TransferSearchResult pairs a transfer request with the wallet it was sent from
+type TransferSearchResult struct {
+	Transfer *TransferRequest `json:"transfer"`
+	Wallet   TransferWallet   `json:"wallet"`
+}
+
+// TransferWallet is the wallet context returned alongside cross-wallet transfer results
+type TransferWallet struct {
+	ID            uuid.UUID  `json:"id" db:"id"`
+	BitgoWalletID string     `json:"bitgo_wallet_id" db:"bitgo_wallet_id"`
+	Label         string     `json:"label" db:"label"`
+	Coin          string     `json:"coin" db:"coin"`
+	WalletType    WalletType `json:"wallet_type" db:"wallet_type"`
+}
@@ -10,23 +10,26 @@ import (
 )
 
 type Wallet struct {
-	ID                     uuid.UUID      `json:"id" db:"id"`
-	OrganizationID         uuid.UUID      `json:"organization_id" db:"organization_id"`
-	BitgoWalletID          string         `json:"bitgo_wallet_id" db:"bitgo_wallet_id"`
-	Label                  string         `json:"label" db:"label"`
-	Coin                   string         `json:"coin" db:"coin"`
-	WalletType             WalletType     `json:"wallet_type" db:"wallet_type"`
-	BalanceString          string         `json:"balance_string" db:"balance_string"`
-	ConfirmedBalanceString string         `json:"confirmed_balance_string" db:"confirmed_balance_string"`
-	SpendableBalanceString string         `json:"spendable_balance_string" db:"spendable_balance_string"`
-	IsActive               bool           `json:"is_active" db:"is_active"`
-	Frozen                 bool           `json:"frozen" db:"frozen"`
-	MultisigType           *string        `json:"multisig_type" db:"multisig_type"`
-	Threshold              int            `json:"threshold" db:"threshold"`
-	Tags                   pq.StringArray `json:"tags" db:"tags"`
-	Metadata               JSON           `json:"metadata" db:"metadata"`
-	CreatedAt              time.Time      `json:"created_at" db:"created_at"`
-	UpdatedAt              time.Time      `json:"updated_at" db:"updated_at"`
+	ID                        uuid.UUID      `json:"id" db:"id"`
+	OrganizationID            uuid.UUID      `json:"organization_id" db:"organization_id"`
+	BitgoWalletID             string         `json:"bitgo_wallet_id" db:"bitgo_wallet_id"`
+	Label                     string         `json:"label" db:"label"`
+	Coin                      string         `json:"coin" db:"coin"`
+	WalletType                WalletType     `json:"wallet_type" db:"wallet_type"`
+	BalanceString             string         `json:"balance_string" db:"balance_string"`
+	ConfirmedBalanceString    string         `json:"confirmed_balance_string" db:"confirmed_balance_string"`
+	SpendableBalanceString    string         `json:"spendable_balance_string" db:"spendable_balance_string"`
+	BalanceSyncedAt           *time.Time     `json:"balance_synced_at" db:"balance_synced_at"`
+	IsActive                  bool           `json:"is_active" db:"is_active"`
+	Frozen                    bool           `json:"frozen" db:"frozen"`
+	MultisigType              *string        `json:"multisig_type" db:"multisig_type"`
+	Threshold                 int            `json:"threshold" db:"threshold"`
+	RequiredApprovalsOverride *int           `json:"required_approvals_override" db:"required_approvals_override"` // Minimum approvals for the wallet's transfers; nil uses the service default
+	TrustedAddresses          pq.StringArray `json:"trusted_addresses" db:"trusted_addresses"`                     // Recipients whose warm transfers skip risk review and approvals
+	Tags                      pq.StringArray `json:"tags" db:"tags"`
+	Metadata                  JSON           `json:"metadata" db:"metadata"`
+	CreatedAt                 time.Time      `json:"created_at" db:"created_at"`
+	UpdatedAt                 time.Time      `json:"updated_at" db:"updated_at"`
 }
 
 type WalletType string
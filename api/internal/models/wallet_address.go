@@ -0,0 +1,18 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// WalletAddress is a receive address generated for one of our wallets
+type WalletAddress struct {
+	ID           uuid.UUID `json:"id" db:"id"`
+	WalletID     uuid.UUID `json:"wallet_id" db:"wallet_id"`
+	Address      string    `json:"address" db:"address"`
+	Label        *string   `json:"label" db:"label"`
+	Chain        *int      `json:"chain" db:"chain"`
+	AddressIndex *int      `json:"address_index" db:"address_index"`
+	CreatedAt    time.Time `json:"created_at" db:"created_at"`
+}
@@ -19,7 +19,19 @@ type WalletMembership struct {
 type WalletRole string
 
 const (
-	WalletRoleViewer  WalletRole = "viewer"
-	WalletRoleSpender WalletRole = "spender"
-	WalletRoleAdmin   WalletRole = "admin"
+	WalletRoleViewer   WalletRole = "viewer"
+	WalletRoleSpender  WalletRole = "spender"
+	WalletRoleApprover WalletRole = "approver"
+	WalletRoleAdmin    WalletRole = "admin"
 )
+
+// WalletApprover is a wallet member allowed to approve its transfers, either through an
+// approver/admin membership or an approver/admin user role
+type WalletApprover struct {
+	UserID     uuid.UUID  `json:"user_id"`
+	Email      string     `json:"email"`
+	FirstName  *string    `json:"first_name"`
+	LastName   *string    `json:"last_name"`
+	WalletRole WalletRole `json:"wallet_role"`
+	UserRole   string     `json:"user_role"`
+}
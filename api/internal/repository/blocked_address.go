@@ -0,0 +1,128 @@
+package repository
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"strings"
+
+	"bitgo-wallets-api/internal/models"
+
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+)
+
+var (
+	// ErrDuplicateBlockedAddress is returned when the address is already on the denylist
+	ErrDuplicateBlockedAddress = errors.New("address is already blocked")
+	// ErrBlockedAddressNotFound is returned when deleting an entry that doesn't exist
+	ErrBlockedAddressNotFound = errors.New("blocked address not found")
+)
+
+type BlockedAddressRepository interface {
+	Create(blocked *models.BlockedAddress) error
+	Delete(id uuid.UUID) error
+	GetByAddress(address string) (*models.BlockedAddress, error)
+	List() ([]*models.BlockedAddress, error)
+}
+
+type blockedAddressRepository struct {
+	db *sql.DB
+}
+
+func NewBlockedAddressRepository(db *sql.DB) BlockedAddressRepository {
+	return &blockedAddressRepository{db: db}
+}
+
+const blockedAddressColumns = `id, address, reason, created_at`
+
+// NormalizeBlockedAddress trims the address and lowercases hex (0x) addresses, which are
+// case-insensitive, so lookups match however the address was typed
+func NormalizeBlockedAddress(address string) string {
+	address = strings.TrimSpace(address)
+	if strings.HasPrefix(strings.ToLower(address), "0x") {
+		return strings.ToLower(address)
+	}
+	return address
+}
+
+func scanBlockedAddress(row interface{ Scan(...interface{}) error }) (*models.BlockedAddress, error) {
+	blocked := &models.BlockedAddress{}
+	err := row.Scan(&blocked.ID, &blocked.Address, &blocked.Reason, &blocked.CreatedAt)
+	return blocked, err
+}
+
+func (r *blockedAddressRepository) Create(blocked *models.BlockedAddress) error {
+	query := `
+		INSERT INTO blocked_addresses (id, address, reason)
+		VALUES ($1, $2, $3)
+		RETURNING created_at
+	`
+
+	blocked.ID = uuid.New()
+	blocked.Address = NormalizeBlockedAddress(blocked.Address)
+	err := r.db.QueryRow(query, blocked.ID, blocked.Address, blocked.Reason).Scan(&blocked.CreatedAt)
+
+	var pqErr *pq.Error
+	if errors.As(err, &pqErr) && pqErr.Code == "23505" {
+		return ErrDuplicateBlockedAddress
+	}
+	if err != nil {
+		return fmt.Errorf("failed to create blocked address: %w", err)
+	}
+
+	return nil
+}
+
+func (r *blockedAddressRepository) Delete(id uuid.UUID) error {
+	result, err := r.db.Exec(`DELETE FROM blocked_addresses WHERE id = $1`, id)
+	if err != nil {
+		return fmt.Errorf("failed to delete blocked address: %w", err)
+	}
+
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to delete blocked address: %w", err)
+	}
+	if rows == 0 {
+		return ErrBlockedAddressNotFound
+	}
+
+	return nil
+}
+
+// GetByAddress returns the denylist entry for address, or nil if it isn't blocked
+func (r *blockedAddressRepository) GetByAddress(address string) (*models.BlockedAddress, error) {
+	query := `SELECT ` + blockedAddressColumns + ` FROM blocked_addresses WHERE address = $1`
+
+	blocked, err := scanBlockedAddress(r.db.QueryRow(query, NormalizeBlockedAddress(address)))
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get blocked address: %w", err)
+	}
+
+	return blocked, nil
+}
+
+func (r *blockedAddressRepository) List() ([]*models.BlockedAddress, error) {
+	query := `SELECT ` + blockedAddressColumns + ` FROM blocked_addresses ORDER BY created_at DESC`
+
+	rows, err := r.db.Query(query)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list blocked addresses: %w", err)
+	}
+	defer rows.Close()
+
+	var blocked []*models.BlockedAddress
+	for rows.Next() {
+		entry, err := scanBlockedAddress(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan blocked address: %w", err)
+		}
+		blocked = append(blocked, entry)
+	}
+
+	return blocked, nil
+}
@@ -0,0 +1,81 @@
+package repository
+
+import (
+	"database/sql"
+	"fmt"
+
+	"bitgo-wallets-api/internal/models"
+
+	"github.com/google/uuid"
+)
+
+type NotificationRepository interface {
+	Save(record *models.NotificationRecord) error
+	ListByTransfer(transferID uuid.UUID) ([]*models.NotificationRecord, error)
+}
+
+type notificationRepository struct {
+	db *sql.DB
+}
+
+func NewNotificationRepository(db *sql.DB) NotificationRepository {
+	return &notificationRepository{db: db}
+}
+
+const notificationColumns = `id, transfer_id, type, priority, title, message, recipients, channels, status,
+	retry_count, last_error, sequence, created_at, delivered_at, failed_at, updated_at`
+
+// Save inserts the notification or updates its delivery state if it's already stored
+func (r *notificationRepository) Save(record *models.NotificationRecord) error {
+	query := `
+		INSERT INTO notifications (
+			id, transfer_id, type, priority, title, message, recipients, channels, status,
+			retry_count, last_error, sequence, created_at, delivered_at, failed_at
+		) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15)
+		ON CONFLICT (id) DO UPDATE
+		SET status = EXCLUDED.status, retry_count = EXCLUDED.retry_count,
+		    last_error = EXCLUDED.last_error, delivered_at = EXCLUDED.delivered_at,
+		    failed_at = EXCLUDED.failed_at, sequence = EXCLUDED.sequence, updated_at = NOW()
+		RETURNING updated_at
+	`
+
+	err := r.db.QueryRow(
+		query,
+		record.ID, record.TransferID, record.Type, record.Priority, record.Title,
+		record.Message, record.Recipients, record.Channels, record.Status,
+		record.RetryCount, record.LastError, record.Sequence, record.CreatedAt,
+		record.DeliveredAt, record.FailedAt,
+	).Scan(&record.UpdatedAt)
+	if err != nil {
+		return fmt.Errorf("failed to save notification: %w", err)
+	}
+
+	return nil
+}
+
+// ListByTransfer returns a transfer's notifications, oldest first
+func (r *notificationRepository) ListByTransfer(transferID uuid.UUID) ([]*models.NotificationRecord, error) {
+	query := `SELECT ` + notificationColumns + ` FROM notifications WHERE transfer_id = $1 ORDER BY created_at ASC, sequence ASC`
+
+	rows, err := r.db.Query(query, transferID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list notifications: %w", err)
+	}
+	defer rows.Close()
+
+	var records []*models.NotificationRecord
+	for rows.Next() {
+		record := &models.NotificationRecord{}
+		if err := rows.Scan(
+			&record.ID, &record.TransferID, &record.Type, &record.Priority, &record.Title,
+			&record.Message, &record.Recipients, &record.Channels, &record.Status,
+			&record.RetryCount, &record.LastError, &record.Sequence, &record.CreatedAt,
+			&record.DeliveredAt, &record.FailedAt, &record.UpdatedAt,
+		); err != nil {
+			return nil, fmt.Errorf("failed to scan notification: %w", err)
+		}
+		records = append(records, record)
+	}
+
+	return records, nil
+}
@@ -0,0 +1,145 @@
+package repository
+
+import (
+	"crypto/rand"
+	"database/sql"
+	"encoding/hex"
+	"net/url"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"testing"
+
+	"bitgo-wallets-api/internal/models"
+
+	"github.com/google/uuid"
+	_ "github.com/lib/pq"
+)
+
+// openTestDB connects to the Postgres server in TEST_DATABASE_URL and applies the migrations in
+// a schema of its own, dropped when the test ends, so tests never see each other's rows. Tests
+// using it are skipped when the variable isn't set.
+func openTestDB(t *testing.T) *sql.DB {
+	t.Helper()
+
+	dsn := os.Getenv("TEST_DATABASE_URL")
+	if dsn == "" {
+		t.Skip("TEST_DATABASE_URL is not set")
+	}
+
+	admin, err := sql.Open("postgres", dsn)
+	if err != nil {
+		t.Fatalf("open test database: %v", err)
+	}
+	t.Cleanup(func() { admin.Close() })
+
+	// Extensions are per database, so keep uuid-ossp in public where every test schema sees it
+	if _, err := admin.Exec(`CREATE EXTENSION IF NOT EXISTS "uuid-ossp" WITH SCHEMA public`); err != nil {
+		t.Fatalf("create uuid-ossp extension: %v", err)
+	}
+
+	suffix := make([]byte, 6)
+	rand.Read(suffix)
+	schema := "test_" + hex.EncodeToString(suffix)
+	if _, err := admin.Exec(`CREATE SCHEMA ` + schema); err != nil {
+		t.Fatalf("create test schema: %v", err)
+	}
+	t.Cleanup(func() { admin.Exec(`DROP SCHEMA ` + schema + ` CASCADE`) })
+
+	scoped, err := url.Parse(dsn)
+	if err != nil {
+		t.Fatalf("TEST_DATABASE_URL must be a postgres:// URL: %v", err)
+	}
+	query := scoped.Query()
+	query.Set("search_path", schema+",public")
+	scoped.RawQuery = query.Encode()
+
+	db, err := sql.Open("postgres", scoped.String())
+	if err != nil {
+		t.Fatalf("open test schema: %v", err)
+	}
+	t.Cleanup(func() { db.Close() })
+
+	migrations, err := filepath.Glob(filepath.Join("..", "..", "migrations", "*.sql"))
+	if err != nil || len(migrations) == 0 {
+		t.Fatalf("find migrations: %v", err)
+	}
+	sort.Strings(migrations)
+	for _, migration := range migrations {
+		contents, err := os.ReadFile(migration)
+		if err != nil {
+			t.Fatalf("read %s: %v", migration, err)
+		}
+		if _, err := db.Exec(string(contents)); err != nil {
+			t.Fatalf("apply %s: %v", filepath.Base(migration), err)
+		}
+	}
+
+	return db
+}
+
+// createTestUser inserts an active user with the given role
+func createTestUser(t *testing.T, db *sql.DB, role models.UserRole) uuid.UUID {
+	t.Helper()
+
+	id := uuid.New()
+	_, err := db.Exec(
+		`INSERT INTO users (id, email, password_hash, role) VALUES ($1, $2, 'x', $3)`,
+		id, strings.ReplaceAll(id.String(), "-", "")+"@example.com", string(role),
+	)
+	if err != nil {
+		t.Fatalf("create user: %v", err)
+	}
+	return id
+}
+
+// createTestWallet inserts an active wallet of the given type in a new organization
+func createTestWallet(t *testing.T, db *sql.DB, coin string, walletType models.WalletType) *models.Wallet {
+	t.Helper()
+
+	var organizationID uuid.UUID
+	if err := db.QueryRow(`INSERT INTO organizations (name) VALUES ('Test') RETURNING id`).Scan(&organizationID); err != nil {
+		t.Fatalf("create organization: %v", err)
+	}
+
+	wallet := &models.Wallet{
+		OrganizationID:         organizationID,
+		BitgoWalletID:          "bitgo-" + uuid.New().String(),
+		Label:                  "Test wallet",
+		Coin:                   coin,
+		WalletType:             walletType,
+		BalanceString:          "10",
+		ConfirmedBalanceString: "10",
+		SpendableBalanceString: "10",
+		IsActive:               true,
+		Threshold:              2,
+	}
+	if err := NewWalletRepository(db).Create(wallet); err != nil {
+		t.Fatalf("create wallet: %v", err)
+	}
+	return wallet
+}
+
+// addTestMembership makes the user a member of the wallet with the given membership role
+func addTestMembership(t *testing.T, db *sql.DB, walletID, userID uuid.UUID, role string) {
+	t.Helper()
+
+	if _, err := db.Exec(`INSERT INTO wallet_memberships (wallet_id, user_id, role) VALUES ($1, $2, $3)`, walletID, userID, role); err != nil {
+		t.Fatalf("add wallet membership: %v", err)
+	}
+}
+
+// newTestTransfer returns an unsaved transfer request from the user on the wallet
+func newTestTransfer(wallet *models.Wallet, requestedBy uuid.UUID, status models.TransferStatus) *models.TransferRequest {
+	return &models.TransferRequest{
+		WalletID:          wallet.ID,
+		RequestedByUserID: requestedBy,
+		RecipientAddress:  "bc1qar0srrr7xfkvy5l643lydnw9re59gtzzwf5mdq",
+		AmountString:      "0.1",
+		Coin:              wallet.Coin,
+		TransferType:      wallet.WalletType,
+		Status:            status,
+		RequiredApprovals: 1,
+	}
+}
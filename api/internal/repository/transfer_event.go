@@ -0,0 +1,142 @@
+package repository
+
+import (
+	"database/sql"
+	"fmt"
+	"strings"
+
+	"bitgo-wallets-api/internal/models"
+
+	"github.com/google/uuid"
+)
+
+// TransferEventRepository reads the transfer event log. Events are written by the transfer
+// request repository in the same transaction as the change they record.
+type TransferEventRepository interface {
+	ListByTransfer(transferID uuid.UUID, afterSequence int64, limit int) ([]*models.TransferEvent, error)
+}
+
+type transferEventRepository struct {
+	db *sql.DB
+
+	// redactFields are replaced in payloads as they're read, e.g. memos when PII is redacted
+	redactFields []string
+}
+
+func NewTransferEventRepository(db *sql.DB, redactFields []string) TransferEventRepository {
+	return &transferEventRepository{db: db, redactFields: redactFields}
+}
+
+const transferEventColumns = `id, transfer_id, sequence, type, from_status, to_status, payload, created_at`
+
+// ListByTransfer returns a transfer's events after the given sequence number, in order
+func (r *transferEventRepository) ListByTransfer(transferID uuid.UUID, afterSequence int64, limit int) ([]*models.TransferEvent, error) {
+	query := `
+		SELECT ` + transferEventColumns + `
+		FROM transfer_events
+		WHERE transfer_id = $1 AND sequence > $2
+		ORDER BY sequence ASC
+		LIMIT $3
+	`
+
+	rows, err := r.db.Query(query, transferID, afterSequence, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list transfer events: %w", err)
+	}
+	defer rows.Close()
+
+	var events []*models.TransferEvent
+	for rows.Next() {
+		event := &models.TransferEvent{}
+		if err := rows.Scan(
+			&event.ID, &event.TransferID, &event.Sequence, &event.Type, &event.FromStatus,
+			&event.ToStatus, &event.Payload, &event.CreatedAt,
+		); err != nil {
+			return nil, fmt.Errorf("failed to scan transfer event: %w", err)
+		}
+		redactPayload(event.Payload, r.redactFields)
+		events = append(events, event)
+	}
+
+	return events, rows.Err()
+}
+
+// appendTransferEvent adds the next event to a transfer's log. The payload is stored as is,
+// so the log can still rebuild the transfer; redaction happens when events are read. The
+// caller must hold the transfer's row lock in tx so concurrent writers can't take the same
+// sequence number.
+func appendTransferEvent(tx *sql.Tx, eventType models.TransferEventType, from *models.TransferStatus, request *models.TransferRequest) error {
+	query := `
+		INSERT INTO transfer_events (transfer_id, sequence, type, from_status, to_status, payload)
+		SELECT $1, COALESCE(MAX(sequence), 0) + 1, $2, $3, $4, $5
+		FROM transfer_events
+		WHERE transfer_id = $1
+	`
+
+	payload := transferEventPayload(request)
+	if eventType == models.TransferEventCreated {
+		payload["wallet_id"] = request.WalletID
+		payload["requested_by_user_id"] = request.RequestedByUserID
+		payload["recipient_address"] = request.RecipientAddress
+		payload["amount_string"] = request.AmountString
+		payload["coin"] = request.Coin
+		payload["transfer_type"] = request.TransferType
+		payload["origin"] = request.Origin
+		payload["memo"] = request.Memo
+		payload["destination_tag"] = request.DestinationTag
+	}
+
+	if _, err := tx.Exec(query, request.ID, eventType, from, request.Status, payload); err != nil {
+		return fmt.Errorf("failed to append transfer event: %w", err)
+	}
+	return nil
+}
+
+// transferEventPayload is the state a transfer event carries: the fields that change over a
+// transfer's life, enough to rebuild it from its created event onwards
+func transferEventPayload(request *models.TransferRequest) models.JSON {
+	return models.JSON{
+		"status":               request.Status,
+		"status_reason":        request.StatusReason,
+		"required_approvals":   request.RequiredApprovals,
+		"received_approvals":   request.ReceivedApprovals,
+		"bitgo_transfer_id":    request.BitgoTransferID,
+		"bitgo_txid":           request.BitgoTxid,
+		"transaction_hash":     request.TransactionHash,
+		"fee_string":           request.FeeString,
+		"estimated_fee_string": request.EstimatedFeeString,
+		"submitted_at":         request.SubmittedAt,
+		"approved_at":          request.ApprovedAt,
+		"completed_at":         request.CompletedAt,
+		"failed_at":            request.FailedAt,
+	}
+}
+
+// redactPayload replaces redactFields in an event payload that carry a value
+func redactPayload(payload models.JSON, redactFields []string) {
+	for key, value := range payload {
+		if value == nil {
+			continue // Nothing to hide; keep the null
+		}
+		if containsField(redactFields, key) {
+			payload[key] = "[REDACTED]"
+		}
+	}
+}
+
+func containsField(fields []string, key string) bool {
+	for _, field := range fields {
+		if strings.EqualFold(field, key) {
+			return true
+		}
+	}
+	return false
+}
+
+// lockTransferStatus takes the transfer's row lock in tx and returns its current status and version
+func lockTransferStatus(tx *sql.Tx, id uuid.UUID) (models.TransferStatus, int, error) {
+	var status models.TransferStatus
+	var version int
+	err := tx.QueryRow(`SELECT status, version FROM transfer_requests WHERE id = $1 FOR UPDATE`, id).Scan(&status, &version)
+	return status, version, err
+}
@@ -0,0 +1,105 @@
+package repository
+
+import (
+	"testing"
+
+	"bitgo-wallets-api/internal/models"
+
+	"github.com/google/uuid"
+)
+
+func TestRedactPayload(t *testing.T) {
+	payload := models.JSON{
+		"memo":            "invoice 7",
+		"destination_tag": nil,
+		"amount_string":   "1.5",
+	}
+
+	redactPayload(payload, []string{"Memo", "destination_tag"})
+
+	if payload["memo"] != "[REDACTED]" {
+		t.Errorf("memo = %v, want it redacted", payload["memo"])
+	}
+	if payload["destination_tag"] != nil {
+		t.Errorf("destination_tag = %v, want the null kept", payload["destination_tag"])
+	}
+	if payload["amount_string"] != "1.5" {
+		t.Errorf("amount_string = %v, want it untouched", payload["amount_string"])
+	}
+}
+
+func TestTransferEventsRecordEachTransitionInOrder(t *testing.T) {
+	db := openTestDB(t)
+	repo := NewTransferRequestRepository(db)
+	events := NewTransferEventRepository(db, nil)
+	requester := createTestUser(t, db, models.RoleOperator)
+	firstApprover := createTestUser(t, db, models.RoleApprover)
+	secondApprover := createTestUser(t, db, models.RoleApprover)
+	wallet := createTestWallet(t, db, "btc", models.WalletTypeWarm)
+
+	transfer := newTestTransfer(wallet, requester, models.TransferStatusPendingApproval)
+	transfer.RequiredApprovals = 2
+	if err := repo.Create(transfer); err != nil {
+		t.Fatalf("Create() error = %v", err)
+	}
+	for _, approver := range []uuid.UUID{firstApprover, secondApprover} {
+		if _, err := repo.DecideApproval(transfer.ID, &approver, true, nil); err != nil {
+			t.Fatalf("DecideApproval() error = %v", err)
+		}
+	}
+
+	stored, err := repo.GetByID(transfer.ID)
+	if err != nil {
+		t.Fatalf("GetByID() error = %v", err)
+	}
+	stored.Status = models.TransferStatusSubmitted
+	if err := repo.Update(stored); err != nil {
+		t.Fatalf("Update() error = %v", err)
+	}
+	// Repeating a status records nothing
+	for _, status := range []models.TransferStatus{models.TransferStatusBroadcast, models.TransferStatusBroadcast, models.TransferStatusConfirmed} {
+		if err := repo.UpdateStatus(transfer.ID, status); err != nil {
+			t.Fatalf("UpdateStatus(%s) error = %v", status, err)
+		}
+	}
+
+	pending, approved := models.TransferStatusPendingApproval, models.TransferStatusApproved
+	submitted, broadcast := models.TransferStatusSubmitted, models.TransferStatusBroadcast
+	want := []struct {
+		eventType models.TransferEventType
+		from      *models.TransferStatus
+		to        models.TransferStatus
+	}{
+		{eventType: models.TransferEventCreated, to: pending},
+		{eventType: models.TransferEventApprovalRecorded, from: &pending, to: pending},
+		{eventType: "approved", from: &pending, to: approved},
+		{eventType: "submitted", from: &approved, to: submitted},
+		{eventType: "broadcast", from: &submitted, to: broadcast},
+		{eventType: "confirmed", from: &broadcast, to: models.TransferStatusConfirmed},
+	}
+
+	got, err := events.ListByTransfer(transfer.ID, 0, 100)
+	if err != nil {
+		t.Fatalf("ListByTransfer() error = %v", err)
+	}
+	if len(got) != len(want) {
+		t.Fatalf("%d events, want %d", len(got), len(want))
+	}
+	for i, event := range got {
+		w := want[i]
+		if event.Sequence != int64(i+1) || event.Type != w.eventType || event.ToStatus != w.to ||
+			(event.FromStatus == nil) != (w.from == nil) || (w.from != nil && *event.FromStatus != *w.from) {
+			t.Errorf("event %d = #%d %s %v -> %s, want #%d %s %v -> %s",
+				i, event.Sequence, event.Type, event.FromStatus, event.ToStatus, i+1, w.eventType, w.from, w.to)
+		}
+	}
+
+	// Reading on from a sequence number resumes the log in order
+	page, err := events.ListByTransfer(transfer.ID, 4, 10)
+	if err != nil {
+		t.Fatalf("ListByTransfer(after 4) error = %v", err)
+	}
+	if len(page) != 2 || page[0].Sequence != 5 || page[1].Sequence != 6 {
+		t.Errorf("events after 4 = %d starting at %v, want sequences 5 and 6", len(page), page)
+	}
+}
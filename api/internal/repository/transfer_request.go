@@ -2,22 +2,107 @@ package repository
 
 import (
 	"database/sql"
+	"errors"
 	"fmt"
+	"strings"
 	"time"
 
 	"bitgo-wallets-api/internal/models"
 
 	"github.com/google/uuid"
+	"github.com/lib/pq"
 )
 
+// ErrTransferRequestNotFound is returned by updates that target a transfer request that doesn't exist
+var ErrTransferRequestNotFound = errors.New("transfer request not found")
+
+// ErrDuplicateTransferRequest is returned by Create when the BitGo transfer was already
+// backfilled into the wallet
+var ErrDuplicateTransferRequest = errors.New("transfer request already exists")
+
+// backfillUniqueIndex is the unique index that makes a backfilled BitGo transfer unique per wallet
+const backfillUniqueIndex = "idx_transfer_requests_backfill"
+
+// ErrTransferVersionConflict is returned by Update when the transfer request changed since
+// it was read. Callers reload it and apply their change again; see UpdateWithRetry.
+var ErrTransferVersionConflict = errors.New("transfer request was modified concurrently")
+
+// ErrApprovalResolved is returned when an approval decision targets a transfer that is no
+// longer awaiting approval
+var ErrApprovalResolved = errors.New("transfer is not awaiting approval")
+
+// ErrAwaitingBitGoApproval is returned when an approval decision targets a transfer already
+// submitted to BitGo, whose approval now happens under the wallet's BitGo policy
+var ErrAwaitingBitGoApproval = errors.New("transfer is awaiting approval at BitGo")
+
+// ErrAlreadyDecided is returned when an approver has already decided on a transfer
+var ErrAlreadyDecided = errors.New("approver has already decided on this transfer")
+
+// awaitingApprovalStatuses are the statuses in which a transfer accepts approval decisions
+var awaitingApprovalStatuses = map[models.TransferStatus]bool{
+	models.TransferStatusSubmitted:       true,
+	models.TransferStatusPendingApproval: true,
+}
+
 type TransferRequestRepository interface {
 	Create(request *models.TransferRequest) error
 	GetByID(id uuid.UUID) (*models.TransferRequest, error)
-	List(walletID uuid.UUID, limit, offset int) ([]*models.TransferRequest, error)
+	GetByIDs(ids []uuid.UUID) ([]*models.TransferRequest, error)
+	List(walletID uuid.UUID, includeArchived bool, limit, offset int) ([]*models.TransferRequest, error)
+	ListByWallet(walletID uuid.UUID, filter TransferListFilter, limit, offset int) ([]*models.TransferRequest, error)
+	CountByWallet(walletID uuid.UUID, filter TransferListFilter) (int, error)
+	StreamByWallet(walletID uuid.UUID, fn func(transfer *models.TransferRequest, requestorEmail string) error) error
 	ListByStatus(status models.TransferStatus, limit, offset int) ([]*models.TransferRequest, error)
 	GetTransfersByStatuses(statuses []models.TransferStatus, limit int) ([]*models.TransferRequest, error)
+	SearchByRecipient(address string, prefix, includeArchived bool, limit, offset int) ([]*models.TransferSearchResult, error)
+	CountByRecipient(address string, prefix, includeArchived bool) (int, error)
+	ListByTypeAndStatuses(transferType models.WalletType, statuses []models.TransferStatus, limit, offset int) ([]*models.TransferRequest, error)
+	ListByTypeAndStatusesAfter(transferType models.WalletType, statuses []models.TransferStatus, after *TransferCursor, limit int) ([]*models.TransferRequest, error)
+	StreamByTypeAndStatuses(transferType models.WalletType, statuses []models.TransferStatus, fn func(transfer *models.TransferRequest) error) error
+	CountByTypeAndStatuses(transferType models.WalletType, statuses []models.TransferStatus) (int, error)
+	ListDueForPolling(statuses []models.TransferStatus, dueBefore map[models.WalletType]time.Time, now time.Time, after *TransferCursor, limit int) ([]*models.TransferRequest, error)
+	MarkPolled(id uuid.UUID, polledAt time.Time) error
+	RecordPollFailure(id uuid.UUID, failures int, pollError string, nextPollAt time.Time, needsAttention bool) error
+	ResetPollFailures(id uuid.UUID) error
+	ListUnsubmittedCreatedBefore(transferType models.WalletType, statuses []models.TransferStatus, before time.Time, limit int) ([]*models.TransferRequest, error)
+	ArchiveInactiveSince(statuses []models.TransferStatus, before time.Time, limit int) (int64, error)
 	Update(request *models.TransferRequest) error
 	UpdateStatus(id uuid.UUID, status models.TransferStatus) error
+	DecideApproval(id uuid.UUID, approverID *uuid.UUID, approve bool, reason *string) (*models.TransferRequest, error)
+	ListAwaitingApproval(approverID uuid.UUID, limit int) ([]*models.TransferRequest, error)
+	ListBitGoReferences(walletID uuid.UUID) (map[string]bool, error)
+}
+
+// TransferListFilter narrows a wallet's transfer listing; zero values don't filter
+type TransferListFilter struct {
+	Tag             string     // Only transfers carrying this tag
+	RequestedBy     *uuid.UUID // Only transfers this user requested
+	NeedsAttention  bool       // Only transfers whose polling gave up
+	IncludeArchived bool
+}
+
+// where returns the WHERE clause and arguments for the wallet's transfers matching the filter
+func (f TransferListFilter) where(walletID uuid.UUID) (string, []interface{}) {
+	clause := `wallet_id = $1` + archivedFilter("", f.IncludeArchived)
+	args := []interface{}{walletID}
+	if f.Tag != "" {
+		args = append(args, f.Tag)
+		clause += fmt.Sprintf(` AND $%d = ANY(tags)`, len(args))
+	}
+	if f.RequestedBy != nil {
+		args = append(args, *f.RequestedBy)
+		clause += fmt.Sprintf(` AND requested_by_user_id = $%d`, len(args))
+	}
+	if f.NeedsAttention {
+		clause += ` AND needs_attention_at IS NOT NULL`
+	}
+	return clause, args
+}
+
+// TransferCursor marks a position in transfers ordered by (updated_at, id)
+type TransferCursor struct {
+	UpdatedAt time.Time
+	ID        uuid.UUID
 }
 
 type transferRequestRepository struct {
@@ -28,54 +113,72 @@ func NewTransferRequestRepository(db *sql.DB) TransferRequestRepository {
 	return &transferRequestRepository{db: db}
 }
 
+// Create inserts a new transfer request. BitGo references, fee and timestamps are stored
+// when already set, as for transfers imported from BitGo; CreatedAt defaults to now.
 func (r *transferRequestRepository) Create(request *models.TransferRequest) error {
 	query := `
 		INSERT INTO transfer_requests (
 			id, wallet_id, requested_by_user_id, recipient_address, amount_string,
-			coin, transfer_type, status, required_approvals, memo
-		) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10)
-		RETURNING created_at, updated_at
+			coin, transfer_type, status, required_approvals, memo, metadata,
+			comment, tags, origin, destination_tag, bitgo_transfer_id, bitgo_txid,
+			transaction_hash, fee_string, submitted_at, completed_at, failed_at, created_at
+		) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, COALESCE($11, '{}'::jsonb),
+			$12, COALESCE($13, '{}'::text[]), $14, $15, $16, $17, $18, $19, $20, $21, $22,
+			COALESCE($23, NOW()))
+		RETURNING version, created_at, updated_at
 	`
 
 	request.ID = uuid.New()
-	err := r.db.QueryRow(
+	if request.Origin == "" {
+		request.Origin = models.TransferOriginAPI
+	}
+	var createdAt *time.Time
+	if !request.CreatedAt.IsZero() {
+		createdAt = &request.CreatedAt
+	}
+
+	tx, err := r.db.Begin()
+	if err != nil {
+		return fmt.Errorf("failed to begin transfer request transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	err = tx.QueryRow(
 		query,
 		request.ID, request.WalletID, request.RequestedByUserID,
 		request.RecipientAddress, request.AmountString, request.Coin,
 		request.TransferType, request.Status, request.RequiredApprovals,
-		request.Memo,
-	).Scan(&request.CreatedAt, &request.UpdatedAt)
+		request.Memo, request.Metadata, request.Comment, request.Tags, request.Origin,
+		request.DestinationTag, request.BitgoTransferID, request.BitgoTxid,
+		request.TransactionHash, request.FeeString, request.SubmittedAt, request.CompletedAt,
+		request.FailedAt, createdAt,
+	).Scan(&request.Version, &request.CreatedAt, &request.UpdatedAt)
 
+	var pqErr *pq.Error
+	if errors.As(err, &pqErr) && pqErr.Code == "23505" && pqErr.Constraint == backfillUniqueIndex {
+		return ErrDuplicateTransferRequest
+	}
 	if err != nil {
 		return fmt.Errorf("failed to create transfer request: %w", err)
 	}
+	if err := appendTransferEvent(tx, models.TransferEventCreated, nil, request); err != nil {
+		return err
+	}
 
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit transfer request: %w", err)
+	}
 	return nil
 }
 
 func (r *transferRequestRepository) GetByID(id uuid.UUID) (*models.TransferRequest, error) {
 	query := `
-		SELECT id, wallet_id, requested_by_user_id, recipient_address, amount_string,
-		       coin, transfer_type, status, bitgo_transfer_id, transaction_hash,
-		       required_approvals, received_approvals, memo, fee_string,
-		       estimated_fee_string, submitted_at, approved_at, completed_at,
-		       failed_at, created_at, updated_at
+		SELECT ` + transferRequestColumns("") + `
 		FROM transfer_requests
 		WHERE id = $1
 	`
 
-	request := &models.TransferRequest{}
-	err := r.db.QueryRow(query, id).Scan(
-		&request.ID, &request.WalletID, &request.RequestedByUserID,
-		&request.RecipientAddress, &request.AmountString, &request.Coin,
-		&request.TransferType, &request.Status, &request.BitgoTransferID,
-		&request.TransactionHash, &request.RequiredApprovals,
-		&request.ReceivedApprovals, &request.Memo, &request.FeeString,
-		&request.EstimatedFeeString, &request.SubmittedAt, &request.ApprovedAt,
-		&request.CompletedAt, &request.FailedAt, &request.CreatedAt,
-		&request.UpdatedAt,
-	)
-
+	request, err := scanTransferRequest(r.db.QueryRow(query, id))
 	if err == sql.ErrNoRows {
 		return nil, nil
 	}
@@ -86,15 +189,41 @@ func (r *transferRequestRepository) GetByID(id uuid.UUID) (*models.TransferReque
 	return request, nil
 }
 
-func (r *transferRequestRepository) List(walletID uuid.UUID, limit, offset int) ([]*models.TransferRequest, error) {
+// GetByIDs returns the transfers with the given IDs; IDs with no transfer are left out
+func (r *transferRequestRepository) GetByIDs(ids []uuid.UUID) ([]*models.TransferRequest, error) {
+	if len(ids) == 0 {
+		return nil, nil
+	}
+
+	placeholders := ""
+	args := make([]interface{}, 0, len(ids))
+	for i, id := range ids {
+		if i > 0 {
+			placeholders += ", "
+		}
+		placeholders += fmt.Sprintf("$%d", i+1)
+		args = append(args, id)
+	}
+
+	query := fmt.Sprintf(`
+		SELECT %s
+		FROM transfer_requests
+		WHERE id IN (%s)
+	`, transferRequestColumns(""), placeholders)
+
+	rows, err := r.db.Query(query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get transfer requests by ID: %w", err)
+	}
+
+	return scanTransferRequests(rows)
+}
+
+func (r *transferRequestRepository) List(walletID uuid.UUID, includeArchived bool, limit, offset int) ([]*models.TransferRequest, error) {
 	query := `
-		SELECT id, wallet_id, requested_by_user_id, recipient_address, amount_string,
-		       coin, transfer_type, status, bitgo_transfer_id, transaction_hash,
-		       required_approvals, received_approvals, memo, fee_string,
-		       estimated_fee_string, submitted_at, approved_at, completed_at,
-		       failed_at, created_at, updated_at
+		SELECT ` + transferRequestColumns("") + `
 		FROM transfer_requests
-		WHERE wallet_id = $1
+		WHERE wallet_id = $1` + archivedFilter("", includeArchived) + `
 		ORDER BY created_at DESC
 		LIMIT $2 OFFSET $3
 	`
@@ -103,37 +232,81 @@ func (r *transferRequestRepository) List(walletID uuid.UUID, limit, offset int)
 	if err != nil {
 		return nil, fmt.Errorf("failed to list transfer requests: %w", err)
 	}
+
+	return scanTransferRequests(rows)
+}
+
+// ListByWallet lists a wallet's transfers matching the filter, newest first
+func (r *transferRequestRepository) ListByWallet(walletID uuid.UUID, filter TransferListFilter, limit, offset int) ([]*models.TransferRequest, error) {
+	where, args := filter.where(walletID)
+	query := fmt.Sprintf(`
+		SELECT `+transferRequestColumns("")+`
+		FROM transfer_requests
+		WHERE %s
+		ORDER BY created_at DESC
+		LIMIT $%d OFFSET $%d
+	`, where, len(args)+1, len(args)+2)
+
+	rows, err := r.db.Query(query, append(args, limit, offset)...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list transfer requests: %w", err)
+	}
+
+	return scanTransferRequests(rows)
+}
+
+// CountByWallet counts a wallet's transfers matching the filter
+func (r *transferRequestRepository) CountByWallet(walletID uuid.UUID, filter TransferListFilter) (int, error) {
+	where, args := filter.where(walletID)
+	query := `SELECT COUNT(*) FROM transfer_requests WHERE ` + where
+
+	var total int
+	if err := r.db.QueryRow(query, args...).Scan(&total); err != nil {
+		return 0, fmt.Errorf("failed to count transfer requests: %w", err)
+	}
+
+	return total, nil
+}
+
+// StreamByWallet calls fn for every transfer of a wallet, oldest first, together with the
+// requestor's email. Rows are read one at a time so large histories aren't buffered; an error
+// returned by fn stops the iteration and is returned as is.
+func (r *transferRequestRepository) StreamByWallet(walletID uuid.UUID, fn func(transfer *models.TransferRequest, requestorEmail string) error) error {
+	query := `
+		SELECT ` + transferRequestColumns("t") + `, COALESCE(u.email, '')
+		FROM transfer_requests t
+		LEFT JOIN users u ON u.id = t.requested_by_user_id
+		WHERE t.wallet_id = $1
+		ORDER BY t.created_at ASC, t.id ASC
+	`
+
+	rows, err := r.db.Query(query, walletID)
+	if err != nil {
+		return fmt.Errorf("failed to query transfer requests for export: %w", err)
+	}
 	defer rows.Close()
 
-	var requests []*models.TransferRequest
 	for rows.Next() {
-		request := &models.TransferRequest{}
-		err := rows.Scan(
-			&request.ID, &request.WalletID, &request.RequestedByUserID,
-			&request.RecipientAddress, &request.AmountString, &request.Coin,
-			&request.TransferType, &request.Status, &request.BitgoTransferID,
-			&request.TransactionHash, &request.RequiredApprovals,
-			&request.ReceivedApprovals, &request.Memo, &request.FeeString,
-			&request.EstimatedFeeString, &request.SubmittedAt, &request.ApprovedAt,
-			&request.CompletedAt, &request.FailedAt, &request.CreatedAt,
-			&request.UpdatedAt,
-		)
+		var requestorEmail string
+		transfer, err := scanTransferRequest(rows, &requestorEmail)
 		if err != nil {
-			return nil, fmt.Errorf("failed to scan transfer request: %w", err)
+			return fmt.Errorf("failed to scan transfer request: %w", err)
+		}
+		if err := fn(transfer, requestorEmail); err != nil {
+			return err
 		}
-		requests = append(requests, request)
 	}
 
-	return requests, nil
+	if err := rows.Err(); err != nil {
+		return fmt.Errorf("error iterating transfer requests: %w", err)
+	}
+
+	return nil
 }
 
 func (r *transferRequestRepository) ListByStatus(status models.TransferStatus, limit, offset int) ([]*models.TransferRequest, error) {
 	query := `
-		SELECT id, wallet_id, requested_by_user_id, recipient_address, amount_string,
-		       coin, transfer_type, status, bitgo_transfer_id, transaction_hash,
-		       required_approvals, received_approvals, memo, fee_string,
-		       estimated_fee_string, submitted_at, approved_at, completed_at,
-		       failed_at, created_at, updated_at
+		SELECT ` + transferRequestColumns("") + `
 		FROM transfer_requests
 		WHERE status = $1
 		ORDER BY created_at DESC
@@ -144,83 +317,148 @@ func (r *transferRequestRepository) ListByStatus(status models.TransferStatus, l
 	if err != nil {
 		return nil, fmt.Errorf("failed to list transfer requests by status: %w", err)
 	}
-	defer rows.Close()
 
-	var requests []*models.TransferRequest
-	for rows.Next() {
-		request := &models.TransferRequest{}
-		err := rows.Scan(
-			&request.ID, &request.WalletID, &request.RequestedByUserID,
-			&request.RecipientAddress, &request.AmountString, &request.Coin,
-			&request.TransferType, &request.Status, &request.BitgoTransferID,
-			&request.TransactionHash, &request.RequiredApprovals,
-			&request.ReceivedApprovals, &request.Memo, &request.FeeString,
-			&request.EstimatedFeeString, &request.SubmittedAt, &request.ApprovedAt,
-			&request.CompletedAt, &request.FailedAt, &request.CreatedAt,
-			&request.UpdatedAt,
-		)
-		if err != nil {
-			return nil, fmt.Errorf("failed to scan transfer request: %w", err)
-		}
-		requests = append(requests, request)
-	}
-
-	return requests, nil
+	return scanTransferRequests(rows)
 }
 
 func (r *transferRequestRepository) Update(request *models.TransferRequest) error {
 	query := `
 		UPDATE transfer_requests
-		SET status = $1, bitgo_transfer_id = $2, transaction_hash = $3,
-		    received_approvals = $4, fee_string = $5, estimated_fee_string = $6,
-		    submitted_at = $7, approved_at = $8, completed_at = $9, failed_at = $10,
-		    updated_at = NOW()
-		WHERE id = $11
-		RETURNING updated_at
+		SET status = $1, status_reason = $2, bitgo_transfer_id = $3, bitgo_txid = $4,
+		    transaction_hash = $5, fee = $6, fee_rate = $7, received_approvals = $8,
+		    fee_string = $9, estimated_fee_string = $10, submitted_at = $11,
+		    approved_at = $12, completed_at = $13, failed_at = $14,
+		    metadata = COALESCE($15, metadata), build_info = COALESCE($16, build_info),
+		    origin = COALESCE(NULLIF($17, ''), origin),
+		    version = version + 1, updated_at = NOW()
+		WHERE id = $18
+		RETURNING version, updated_at
 	`
 
-	err := r.db.QueryRow(
+	tx, err := r.db.Begin()
+	if err != nil {
+		return fmt.Errorf("failed to begin transfer request transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	previous, version, err := lockTransferStatus(tx, request.ID)
+	if err != nil {
+		return fmt.Errorf("failed to update transfer request: %w", err)
+	}
+	if version != request.Version {
+		return ErrTransferVersionConflict
+	}
+
+	err = tx.QueryRow(
 		query,
-		request.Status, request.BitgoTransferID, request.TransactionHash,
-		request.ReceivedApprovals, request.FeeString, request.EstimatedFeeString,
-		request.SubmittedAt, request.ApprovedAt, request.CompletedAt,
-		request.FailedAt, request.ID,
-	).Scan(&request.UpdatedAt)
+		request.Status, request.StatusReason, request.BitgoTransferID, request.BitgoTxid,
+		request.TransactionHash, request.Fee, request.FeeRate, request.ReceivedApprovals,
+		request.FeeString, request.EstimatedFeeString, request.SubmittedAt,
+		request.ApprovedAt, request.CompletedAt, request.FailedAt, request.Metadata,
+		request.BuildInfo, request.Origin, request.ID,
+	).Scan(&request.Version, &request.UpdatedAt)
 
 	if err != nil {
 		return fmt.Errorf("failed to update transfer request: %w", err)
 	}
+	if request.Status != previous {
+		if err := appendTransferEvent(tx, models.TransferEventForStatus(request.Status), &previous, request); err != nil {
+			return err
+		}
+	}
 
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit transfer request update: %w", err)
+	}
 	return nil
 }
 
+// maxUpdateAttempts bounds how often UpdateWithRetry reloads and reapplies a change that
+// keeps conflicting
+const maxUpdateAttempts = 3
+
+// UpdateWithRetry applies change to the transfer and saves it. When the save conflicts with a
+// concurrent update, the latest row is loaded into transfer and change applied again, so the
+// change lands on top of the other update rather than overwriting it. change returns false
+// when the row as loaded needs no update, in which case nothing is saved. It reports whether
+// the transfer was saved.
+func UpdateWithRetry(repo TransferRequestRepository, transfer *models.TransferRequest, change func(transfer *models.TransferRequest) bool) (bool, error) {
+	for attempt := 1; ; attempt++ {
+		if !change(transfer) {
+			return false, nil
+		}
+
+		err := repo.Update(transfer)
+		if err == nil {
+			return true, nil
+		}
+		if !errors.Is(err, ErrTransferVersionConflict) || attempt == maxUpdateAttempts {
+			return false, err
+		}
+
+		latest, err := repo.GetByID(transfer.ID)
+		if err != nil {
+			return false, err
+		}
+		if latest == nil {
+			return false, ErrTransferRequestNotFound
+		}
+		*transfer = *latest
+	}
+}
+
 func (r *transferRequestRepository) UpdateStatus(id uuid.UUID, status models.TransferStatus) error {
 	var query string
 	var args []interface{}
 
 	switch status {
 	case models.TransferStatusSubmitted:
-		query = `UPDATE transfer_requests SET status = $1, submitted_at = $2, updated_at = NOW() WHERE id = $3`
+		query = `UPDATE transfer_requests SET status = $1, submitted_at = $2, version = version + 1, updated_at = NOW() WHERE id = $3`
 		args = []interface{}{status, time.Now(), id}
 	case models.TransferStatusApproved:
-		query = `UPDATE transfer_requests SET status = $1, approved_at = $2, updated_at = NOW() WHERE id = $3`
+		query = `UPDATE transfer_requests SET status = $1, approved_at = $2, version = version + 1, updated_at = NOW() WHERE id = $3`
 		args = []interface{}{status, time.Now(), id}
 	case models.TransferStatusCompleted:
-		query = `UPDATE transfer_requests SET status = $1, completed_at = $2, updated_at = NOW() WHERE id = $3`
+		query = `UPDATE transfer_requests SET status = $1, completed_at = $2, version = version + 1, updated_at = NOW() WHERE id = $3`
 		args = []interface{}{status, time.Now(), id}
 	case models.TransferStatusFailed:
-		query = `UPDATE transfer_requests SET status = $1, failed_at = $2, updated_at = NOW() WHERE id = $3`
+		query = `UPDATE transfer_requests SET status = $1, failed_at = $2, version = version + 1, updated_at = NOW() WHERE id = $3`
 		args = []interface{}{status, time.Now(), id}
 	default:
-		query = `UPDATE transfer_requests SET status = $1, updated_at = NOW() WHERE id = $2`
+		query = `UPDATE transfer_requests SET status = $1, version = version + 1, updated_at = NOW() WHERE id = $2`
 		args = []interface{}{status, id}
 	}
 
-	_, err := r.db.Exec(query, args...)
+	tx, err := r.db.Begin()
+	if err != nil {
+		return fmt.Errorf("failed to begin transfer request transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	previous, _, err := lockTransferStatus(tx, id)
+	if err == sql.ErrNoRows {
+		return nil
+	}
 	if err != nil {
 		return fmt.Errorf("failed to update transfer request status: %w", err)
 	}
 
+	if _, err := tx.Exec(query, args...); err != nil {
+		return fmt.Errorf("failed to update transfer request status: %w", err)
+	}
+	if status != previous {
+		request, err := scanTransferRequest(tx.QueryRow(`SELECT `+transferRequestColumns("")+` FROM transfer_requests WHERE id = $1`, id))
+		if err != nil {
+			return fmt.Errorf("failed to reload transfer request: %w", err)
+		}
+		if err := appendTransferEvent(tx, models.TransferEventForStatus(status), &previous, request); err != nil {
+			return err
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit transfer request status: %w", err)
+	}
 	return nil
 }
 
@@ -245,43 +483,634 @@ func (r *transferRequestRepository) GetTransfersByStatuses(statuses []models.Tra
 	args = append(args, limit)
 
 	query := fmt.Sprintf(`
-		SELECT id, wallet_id, requested_by_user_id, recipient_address, amount_string,
-			   coin, transfer_type, status, bitgo_transfer_id, bitgo_txid, transaction_hash,
-			   fee, fee_rate, required_approvals, received_approvals, memo,
-			   fee_string, estimated_fee_string, submitted_at, approved_at,
-			   completed_at, failed_at, created_at, updated_at
+		SELECT %s
 		FROM transfer_requests
 		WHERE status IN (%s)
 		ORDER BY updated_at ASC
 		LIMIT $%d
-	`, statusPlaceholders, len(args))
+	`, transferRequestColumns(""), statusPlaceholders, len(args))
 
 	rows, err := r.db.Query(query, args...)
 	if err != nil {
 		return nil, fmt.Errorf("failed to query transfer requests by statuses: %w", err)
 	}
+
+	return scanTransferRequests(rows)
+}
+
+// typeAndStatusFilter builds the WHERE clause shared by the type/status queries, returning
+// the clause and its arguments
+func typeAndStatusFilter(transferType models.WalletType, statuses []models.TransferStatus) (string, []interface{}) {
+	args := []interface{}{transferType}
+	placeholders := make([]string, len(statuses))
+	for i, status := range statuses {
+		args = append(args, status)
+		placeholders[i] = fmt.Sprintf("$%d", len(args))
+	}
+
+	return fmt.Sprintf("transfer_type = $1 AND status IN (%s)", strings.Join(placeholders, ", ")), args
+}
+
+// ListByTypeAndStatuses gets a page of transfers of a type in any of the given statuses, oldest activity first
+func (r *transferRequestRepository) ListByTypeAndStatuses(transferType models.WalletType, statuses []models.TransferStatus, limit, offset int) ([]*models.TransferRequest, error) {
+	if len(statuses) == 0 {
+		return []*models.TransferRequest{}, nil
+	}
+
+	where, args := typeAndStatusFilter(transferType, statuses)
+	args = append(args, limit, offset)
+
+	query := fmt.Sprintf(`
+		SELECT %s
+		FROM transfer_requests
+		WHERE %s
+		ORDER BY updated_at ASC, id ASC
+		LIMIT $%d OFFSET $%d
+	`, transferRequestColumns(""), where, len(args)-1, len(args))
+
+	rows, err := r.db.Query(query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query transfer requests by type and statuses: %w", err)
+	}
+
+	return scanTransferRequests(rows)
+}
+
+// ListByTypeAndStatusesAfter is ListByTypeAndStatuses paged by cursor: when after is set only
+// transfers past it in (updated_at, id) order are returned, so callers can walk the whole set
+// without rows that change status shifting an offset.
+func (r *transferRequestRepository) ListByTypeAndStatusesAfter(transferType models.WalletType, statuses []models.TransferStatus, after *TransferCursor, limit int) ([]*models.TransferRequest, error) {
+	if len(statuses) == 0 {
+		return []*models.TransferRequest{}, nil
+	}
+
+	where, args := typeAndStatusFilter(transferType, statuses)
+	if after != nil {
+		args = append(args, after.UpdatedAt, after.ID)
+		where += fmt.Sprintf(" AND (updated_at, id) > ($%d, $%d)", len(args)-1, len(args))
+	}
+	args = append(args, limit)
+
+	query := fmt.Sprintf(`
+		SELECT %s
+		FROM transfer_requests
+		WHERE %s
+		ORDER BY updated_at ASC, id ASC
+		LIMIT $%d
+	`, transferRequestColumns(""), where, len(args))
+
+	rows, err := r.db.Query(query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query transfer requests by type and statuses: %w", err)
+	}
+
+	return scanTransferRequests(rows)
+}
+
+// StreamByTypeAndStatuses calls fn for every transfer in any of the given statuses, of the given
+// type or of any type when transferType is empty. Rows are read one at a time so aggregations
+// over the whole table aren't buffered; an error returned by fn stops the iteration.
+func (r *transferRequestRepository) StreamByTypeAndStatuses(transferType models.WalletType, statuses []models.TransferStatus, fn func(transfer *models.TransferRequest) error) error {
+	if len(statuses) == 0 {
+		return nil
+	}
+
+	var where string
+	var args []interface{}
+	if transferType != "" {
+		where, args = typeAndStatusFilter(transferType, statuses)
+	} else {
+		placeholders := make([]string, len(statuses))
+		for i, status := range statuses {
+			args = append(args, status)
+			placeholders[i] = fmt.Sprintf("$%d", len(args))
+		}
+		where = fmt.Sprintf("status IN (%s)", strings.Join(placeholders, ", "))
+	}
+
+	query := fmt.Sprintf(`
+		SELECT %s
+		FROM transfer_requests
+		WHERE %s
+		ORDER BY created_at ASC, id ASC
+	`, transferRequestColumns(""), where)
+
+	rows, err := r.db.Query(query, args...)
+	if err != nil {
+		return fmt.Errorf("failed to query transfer requests by type and statuses: %w", err)
+	}
 	defer rows.Close()
 
-	var requests []*models.TransferRequest
 	for rows.Next() {
-		request := &models.TransferRequest{}
-		err := rows.Scan(
-			&request.ID, &request.WalletID, &request.RequestedByUserID,
-			&request.RecipientAddress, &request.AmountString, &request.Coin,
-			&request.TransferType, &request.Status, &request.BitgoTransferID,
-			&request.BitgoTxid, &request.TransactionHash, &request.Fee, &request.FeeRate,
-			&request.RequiredApprovals, &request.ReceivedApprovals, &request.Memo,
-			&request.FeeString, &request.EstimatedFeeString, &request.SubmittedAt,
-			&request.ApprovedAt, &request.CompletedAt, &request.FailedAt,
-			&request.CreatedAt, &request.UpdatedAt,
+		transfer, err := scanTransferRequest(rows)
+		if err != nil {
+			return fmt.Errorf("failed to scan transfer request: %w", err)
+		}
+		if err := fn(transfer); err != nil {
+			return err
+		}
+	}
+
+	if err := rows.Err(); err != nil {
+		return fmt.Errorf("error iterating transfer requests: %w", err)
+	}
+
+	return nil
+}
+
+// CountByTypeAndStatuses counts transfers of a type in any of the given statuses
+func (r *transferRequestRepository) CountByTypeAndStatuses(transferType models.WalletType, statuses []models.TransferStatus) (int, error) {
+	if len(statuses) == 0 {
+		return 0, nil
+	}
+
+	where, args := typeAndStatusFilter(transferType, statuses)
+	query := fmt.Sprintf(`SELECT COUNT(*) FROM transfer_requests WHERE %s`, where)
+
+	var total int
+	if err := r.db.QueryRow(query, args...).Scan(&total); err != nil {
+		return 0, fmt.Errorf("failed to count transfer requests by type and statuses: %w", err)
+	}
+
+	return total, nil
+}
+
+// ListDueForPolling gets transfers in any of the given statuses whose wallet type has a cutoff in
+// dueBefore and that were never polled or last polled before that cutoff. Transfers of types
+// without a cutoff are not returned. Results are ordered by (updated_at, id); when after is set
+// only transfers past that cursor are returned, so callers can page through the whole backlog.
+func (r *transferRequestRepository) ListDueForPolling(statuses []models.TransferStatus, dueBefore map[models.WalletType]time.Time, now time.Time, after *TransferCursor, limit int) ([]*models.TransferRequest, error) {
+	if len(statuses) == 0 || len(dueBefore) == 0 {
+		return []*models.TransferRequest{}, nil
+	}
+
+	args := make([]interface{}, 0, len(statuses)+2*len(dueBefore)+1)
+	statusPlaceholders := make([]string, len(statuses))
+	for i, status := range statuses {
+		args = append(args, status)
+		statusPlaceholders[i] = fmt.Sprintf("$%d", len(args))
+	}
+
+	typeConditions := make([]string, 0, len(dueBefore))
+	for transferType, cutoff := range dueBefore {
+		args = append(args, transferType, cutoff)
+		typeConditions = append(typeConditions, fmt.Sprintf(
+			"(transfer_type = $%d AND (last_polled_at IS NULL OR last_polled_at <= $%d))",
+			len(args)-1, len(args),
+		))
+	}
+	where := fmt.Sprintf("status IN (%s) AND (%s)", strings.Join(statusPlaceholders, ", "), strings.Join(typeConditions, " OR "))
+
+	// Transfers backing off after failed lookups wait out their delay; flagged ones aren't polled
+	args = append(args, now)
+	where += fmt.Sprintf(" AND (next_poll_at IS NULL OR next_poll_at <= $%d) AND needs_attention_at IS NULL", len(args))
+	if after != nil {
+		args = append(args, after.UpdatedAt, after.ID)
+		where += fmt.Sprintf(" AND (updated_at, id) > ($%d, $%d)", len(args)-1, len(args))
+	}
+	args = append(args, limit)
+
+	query := fmt.Sprintf(`
+		SELECT %s
+		FROM transfer_requests
+		WHERE %s
+		ORDER BY updated_at ASC, id ASC
+		LIMIT $%d
+	`, transferRequestColumns(""), where, len(args))
+
+	rows, err := r.db.Query(query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query transfer requests due for polling: %w", err)
+	}
+
+	return scanTransferRequests(rows)
+}
+
+// RecordPollFailure records a failed BitGo lookup: the consecutive failure count, its error,
+// and when the transfer may be polled again. needsAttention stops polling it altogether.
+func (r *transferRequestRepository) RecordPollFailure(id uuid.UUID, failures int, pollError string, nextPollAt time.Time, needsAttention bool) error {
+	query := `
+		UPDATE transfer_requests
+		SET poll_failures = $1, poll_error = $2, next_poll_at = $3,
+		    needs_attention_at = CASE WHEN $4::boolean THEN COALESCE(needs_attention_at, NOW()) END
+		WHERE id = $5
+	`
+
+	if _, err := r.db.Exec(query, failures, pollError, nextPollAt, needsAttention, id); err != nil {
+		return fmt.Errorf("failed to record poll failure: %w", err)
+	}
+	return nil
+}
+
+// ResetPollFailures clears a transfer's failed lookups after a successful one, flag included
+func (r *transferRequestRepository) ResetPollFailures(id uuid.UUID) error {
+	query := `
+		UPDATE transfer_requests
+		SET poll_failures = 0, poll_error = NULL, next_poll_at = NULL, needs_attention_at = NULL
+		WHERE id = $1
+	`
+
+	if _, err := r.db.Exec(query, id); err != nil {
+		return fmt.Errorf("failed to reset poll failures: %w", err)
+	}
+	return nil
+}
+
+// MarkPolled records when a transfer was last polled
+func (r *transferRequestRepository) MarkPolled(id uuid.UUID, polledAt time.Time) error {
+	query := `UPDATE transfer_requests SET last_polled_at = $1 WHERE id = $2`
+
+	_, err := r.db.Exec(query, polledAt, id)
+	if err != nil {
+		return fmt.Errorf("failed to mark transfer request as polled: %w", err)
+	}
+
+	return nil
+}
+
+// DecideApproval applies one approver's decision to a transfer awaiting approval in a single
+// transaction that holds the row lock. Approving records an approval and marks the transfer
+// approved once it has enough; rejecting marks it rejected with the reason. When approverID
+// is set the decision is recorded against them so each approver counts once. It returns the
+// updated transfer, ErrTransferRequestNotFound, ErrApprovalResolved when the transfer has
+// already moved on, or ErrAlreadyDecided when the approver has already decided on it.
+func (r *transferRequestRepository) DecideApproval(id uuid.UUID, approverID *uuid.UUID, approve bool, reason *string) (*models.TransferRequest, error) {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin approval transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	query := `
+		SELECT ` + transferRequestColumns("") + `
+		FROM transfer_requests
+		WHERE id = $1
+		FOR UPDATE
+	`
+	request, err := scanTransferRequest(tx.QueryRow(query, id))
+	if err == sql.ErrNoRows {
+		return nil, ErrTransferRequestNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to lock transfer request: %w", err)
+	}
+	if !awaitingApprovalStatuses[request.Status] {
+		return request, ErrApprovalResolved
+	}
+	if request.SubmittedAt != nil {
+		return request, ErrAwaitingBitGoApproval
+	}
+
+	if approverID != nil {
+		result, err := tx.Exec(
+			`INSERT INTO transfer_approvals (transfer_id, approver_id, approved) VALUES ($1, $2, $3) ON CONFLICT DO NOTHING`,
+			id, *approverID, approve,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("failed to record approver: %w", err)
+		}
+		if recorded, err := result.RowsAffected(); err != nil {
+			return nil, fmt.Errorf("failed to record approver: %w", err)
+		} else if recorded == 0 {
+			return request, ErrAlreadyDecided
+		}
+	}
+
+	previous := request.Status
+	now := time.Now()
+	switch {
+	case !approve:
+		request.Status = models.TransferStatusRejected
+		request.StatusReason = reason
+		_, err = tx.Exec(
+			`UPDATE transfer_requests SET status = $1, status_reason = $2, version = version + 1, updated_at = NOW() WHERE id = $3`,
+			request.Status, request.StatusReason, id,
+		)
+	case request.ReceivedApprovals+1 >= request.RequiredApprovals:
+		request.ReceivedApprovals++
+		request.Status = models.TransferStatusApproved
+		request.ApprovedAt = &now
+		_, err = tx.Exec(
+			`UPDATE transfer_requests SET received_approvals = $1, status = $2, approved_at = $3, version = version + 1, updated_at = NOW() WHERE id = $4`,
+			request.ReceivedApprovals, request.Status, now, id,
+		)
+	default:
+		request.ReceivedApprovals++
+		_, err = tx.Exec(
+			`UPDATE transfer_requests SET received_approvals = $1, version = version + 1, updated_at = NOW() WHERE id = $2`,
+			request.ReceivedApprovals, id,
+		)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to record approval decision: %w", err)
+	}
+
+	eventType := models.TransferEventApprovalRecorded
+	if request.Status != previous {
+		eventType = models.TransferEventForStatus(request.Status)
+	}
+	if err := appendTransferEvent(tx, eventType, &previous, request); err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("failed to commit approval decision: %w", err)
+	}
+	request.Version++
+	request.UpdatedAt = now
+	return request, nil
+}
+
+// ListAwaitingApproval gets transfers awaiting approval on every wallet the user can approve
+// for, under the same rules as ListApprovers, most urgent first and then by nearest processing
+// deadline, so a limit keeps the most pressing ones. Transfers the user requested are left
+// out since they can't approve them, as are transfers already submitted to BitGo.
+func (r *transferRequestRepository) ListAwaitingApproval(approverID uuid.UUID, limit int) ([]*models.TransferRequest, error) {
+	query := fmt.Sprintf(`
+		SELECT %s
+		FROM transfer_requests t
+		JOIN wallet_memberships m ON m.wallet_id = t.wallet_id AND m.user_id = $1
+		JOIN users u ON u.id = m.user_id
+		WHERE t.status IN ($2, $3) AND t.submitted_at IS NULL AND t.requested_by_user_id <> $1%s
+		  AND u.is_active
+		  AND (m.role IN ('approver', 'admin') OR u.role IN ('approver', 'admin'))
+		ORDER BY CASE t.metadata->>'urgency_level'
+		           WHEN 'critical' THEN 0 WHEN 'high' THEN 1 WHEN 'normal' THEN 2 WHEN 'low' THEN 3 ELSE 4
+		         END,
+		         (t.metadata->>'sla_processing_by')::timestamptz ASC NULLS LAST,
+		         t.created_at ASC, t.id ASC
+		LIMIT $4
+	`, transferRequestColumns("t"), archivedFilter("t", false))
+
+	rows, err := r.db.Query(query, approverID,
+		models.TransferStatusSubmitted, models.TransferStatusPendingApproval, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query transfers awaiting approval: %w", err)
+	}
+
+	return scanTransferRequests(rows)
+}
+
+// ListBitGoReferences returns every BitGo transfer ID and txid recorded on the wallet's
+// transfers, archived ones included, so BitGo transfers already known locally can be told apart
+func (r *transferRequestRepository) ListBitGoReferences(walletID uuid.UUID) (map[string]bool, error) {
+	query := `
+		SELECT reference
+		FROM transfer_requests,
+			UNNEST(ARRAY[bitgo_transfer_id, bitgo_txid, transaction_hash]) AS reference
+		WHERE wallet_id = $1 AND reference IS NOT NULL AND reference <> ''
+	`
+
+	rows, err := r.db.Query(query, walletID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list BitGo references: %w", err)
+	}
+	defer rows.Close()
+
+	references := make(map[string]bool)
+	for rows.Next() {
+		var reference string
+		if err := rows.Scan(&reference); err != nil {
+			return nil, fmt.Errorf("failed to scan BitGo reference: %w", err)
+		}
+		references[reference] = true
+	}
+
+	return references, rows.Err()
+}
+
+// ListUnsubmittedCreatedBefore returns transfers of the type in the statuses, created before
+// the cutoff and never submitted to BitGo, oldest first
+func (r *transferRequestRepository) ListUnsubmittedCreatedBefore(transferType models.WalletType, statuses []models.TransferStatus, before time.Time, limit int) ([]*models.TransferRequest, error) {
+	if len(statuses) == 0 {
+		return []*models.TransferRequest{}, nil
+	}
+
+	args := []interface{}{transferType, before}
+	placeholders := make([]string, len(statuses))
+	for i, status := range statuses {
+		args = append(args, status)
+		placeholders[i] = fmt.Sprintf("$%d", len(args))
+	}
+	args = append(args, limit)
+
+	query := fmt.Sprintf(`
+		SELECT %s
+		FROM transfer_requests
+		WHERE transfer_type = $1 AND created_at < $2 AND status IN (%s) AND submitted_at IS NULL
+		ORDER BY created_at ASC
+		LIMIT $%d
+	`, transferRequestColumns(""), strings.Join(placeholders, ", "), len(args))
+
+	rows, err := r.db.Query(query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query transfer requests created before cutoff: %w", err)
+	}
+
+	return scanTransferRequests(rows)
+}
+
+// recipientFilter builds the recipient address condition shared by the search queries
+func recipientFilter(address string, prefix bool) (string, string) {
+	if prefix {
+		return `t.recipient_address LIKE $1 ESCAPE '\'`, escapeLikePattern(address) + "%"
+	}
+	return "t.recipient_address = $1", address
+}
+
+// CountByRecipient counts the transfers SearchByRecipient would find across all pages
+func (r *transferRequestRepository) CountByRecipient(address string, prefix, includeArchived bool) (int, error) {
+	condition, arg := recipientFilter(address, prefix)
+	query := fmt.Sprintf(`SELECT COUNT(*) FROM transfer_requests t WHERE %s%s`, condition, archivedFilter("t", includeArchived))
+
+	var total int
+	if err := r.db.QueryRow(query, arg).Scan(&total); err != nil {
+		return 0, fmt.Errorf("failed to count transfer requests by recipient: %w", err)
+	}
+
+	return total, nil
+}
+
+// SearchByRecipient finds transfers sent to the given address across all wallets.
+// When prefix is true, the address is matched as a prefix instead of exactly.
+func (r *transferRequestRepository) SearchByRecipient(address string, prefix, includeArchived bool, limit, offset int) ([]*models.TransferSearchResult, error) {
+	condition, arg := recipientFilter(address, prefix)
+	condition += archivedFilter("t", includeArchived)
+
+	query := fmt.Sprintf(`
+		SELECT %s,
+		       w.id, w.bitgo_wallet_id, w.label, w.coin, w.wallet_type
+		FROM transfer_requests t
+		JOIN wallets w ON w.id = t.wallet_id
+		WHERE %s
+		ORDER BY t.created_at DESC
+		LIMIT $2 OFFSET $3
+	`, transferRequestColumns("t"), condition)
+
+	rows, err := r.db.Query(query, arg, limit, offset)
+	if err != nil {
+		return nil, fmt.Errorf("failed to search transfer requests by recipient: %w", err)
+	}
+	defer rows.Close()
+
+	var results []*models.TransferSearchResult
+	for rows.Next() {
+		result := &models.TransferSearchResult{}
+		request, err := scanTransferRequest(rows,
+			&result.Wallet.ID, &result.Wallet.BitgoWalletID, &result.Wallet.Label,
+			&result.Wallet.Coin, &result.Wallet.WalletType,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan transfer search result: %w", err)
+		}
+		result.Transfer = request
+		results = append(results, result)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating transfer search results: %w", err)
+	}
+
+	return results, nil
+}
+
+// archivedFilter returns the condition that hides archived transfers from default lists,
+// or nothing when archived transfers were asked for
+func archivedFilter(alias string, includeArchived bool) string {
+	if includeArchived {
+		return ""
+	}
+	if alias != "" {
+		return " AND " + alias + ".archived_at IS NULL"
+	}
+	return " AND archived_at IS NULL"
+}
+
+// ArchiveInactiveSince flags up to limit unarchived transfers in one of statuses that
+// haven't changed since before, oldest first, and returns how many were archived. Archived
+// rows stay in place so audit logs and direct lookups keep working.
+func (r *transferRequestRepository) ArchiveInactiveSince(statuses []models.TransferStatus, before time.Time, limit int) (int64, error) {
+	if len(statuses) == 0 {
+		return 0, nil
+	}
+
+	args := []interface{}{before}
+	placeholders := make([]string, len(statuses))
+	for i, status := range statuses {
+		args = append(args, status)
+		placeholders[i] = fmt.Sprintf("$%d", len(args))
+	}
+	args = append(args, limit)
+
+	query := fmt.Sprintf(`
+		UPDATE transfer_requests
+		SET archived_at = NOW()
+		WHERE id IN (
+			SELECT id FROM transfer_requests
+			WHERE archived_at IS NULL AND updated_at < $1 AND status IN (%s)
+			ORDER BY updated_at ASC
+			LIMIT $%d
 		)
+	`, strings.Join(placeholders, ", "), len(args))
+
+	result, err := r.db.Exec(query, args...)
+	if err != nil {
+		return 0, fmt.Errorf("failed to archive transfer requests: %w", err)
+	}
+
+	archived, err := result.RowsAffected()
+	if err != nil {
+		return 0, fmt.Errorf("failed to count archived transfer requests: %w", err)
+	}
+
+	return archived, nil
+}
+
+// escapeLikePattern escapes LIKE wildcards so user input is matched literally
+func escapeLikePattern(value string) string {
+	replacer := strings.NewReplacer(`\`, `\\`, "%", `\%`, "_", `\_`)
+	return replacer.Replace(value)
+}
+
+// transferRequestColumnNames lists the transfer_requests columns in the order
+// scanTransferRequest expects them
+var transferRequestColumnNames = []string{
+	"id", "wallet_id", "requested_by_user_id", "recipient_address", "amount_string",
+	"coin", "transfer_type", "origin", "status", "status_reason", "bitgo_transfer_id", "bitgo_txid",
+	"transaction_hash", "fee", "fee_rate", "required_approvals", "received_approvals",
+	"memo", "destination_tag", "comment", "tags", "fee_string", "estimated_fee_string", "submitted_at", "approved_at",
+	"completed_at", "failed_at", "last_polled_at", "poll_failures", "poll_error", "next_poll_at",
+	"needs_attention_at", "archived_at", "build_info", "metadata", "version",
+	"created_at", "updated_at",
+}
+
+// transferRequestColumns returns the select list for a transfer request,
+// optionally qualified with a table alias for use in joins
+func transferRequestColumns(alias string) string {
+	if alias == "" {
+		return strings.Join(transferRequestColumnNames, ", ")
+	}
+
+	qualified := make([]string, len(transferRequestColumnNames))
+	for i, column := range transferRequestColumnNames {
+		qualified[i] = alias + "." + column
+	}
+	return strings.Join(qualified, ", ")
+}
+
+// rowScanner is satisfied by both *sql.Row and *sql.Rows
+type rowScanner interface {
+	Scan(dest ...interface{}) error
+}
+
+// scanTransferRequest scans a row selected with transferRequestColumns.
+// Any extra destinations are scanned from the columns that follow.
+func scanTransferRequest(row rowScanner, extra ...interface{}) (*models.TransferRequest, error) {
+	request := &models.TransferRequest{}
+	var buildInfo []byte
+	dest := []interface{}{
+		&request.ID, &request.WalletID, &request.RequestedByUserID,
+		&request.RecipientAddress, &request.AmountString, &request.Coin,
+		&request.TransferType, &request.Origin, &request.Status, &request.StatusReason,
+		&request.BitgoTransferID, &request.BitgoTxid, &request.TransactionHash,
+		&request.Fee, &request.FeeRate, &request.RequiredApprovals,
+		&request.ReceivedApprovals, &request.Memo, &request.DestinationTag, &request.Comment,
+		&request.Tags, &request.FeeString,
+		&request.EstimatedFeeString, &request.SubmittedAt, &request.ApprovedAt,
+		&request.CompletedAt, &request.FailedAt, &request.LastPolledAt,
+		&request.PollFailures, &request.PollError, &request.NextPollAt, &request.NeedsAttentionAt,
+		&request.ArchivedAt, &buildInfo, &request.Metadata, &request.Version, &request.CreatedAt,
+		&request.UpdatedAt,
+	}
+
+	if err := row.Scan(append(dest, extra...)...); err != nil {
+		return nil, err
+	}
+
+	if buildInfo != nil {
+		request.BuildInfo = &models.TransferBuildInfo{}
+		if err := request.BuildInfo.Scan(buildInfo); err != nil {
+			return nil, fmt.Errorf("failed to decode build info: %w", err)
+		}
+	}
+
+	return request, nil
+}
+
+// scanTransferRequests scans and closes a result set of transfer requests
+func scanTransferRequests(rows *sql.Rows) ([]*models.TransferRequest, error) {
+	defer rows.Close()
+
+	var requests []*models.TransferRequest
+	for rows.Next() {
+		request, err := scanTransferRequest(rows)
 		if err != nil {
 			return nil, fmt.Errorf("failed to scan transfer request: %w", err)
 		}
 		requests = append(requests, request)
 	}
 
-	if err = rows.Err(); err != nil {
+	if err := rows.Err(); err != nil {
 		return nil, fmt.Errorf("error iterating transfer requests: %w", err)
 	}
 
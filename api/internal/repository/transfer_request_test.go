@@ -0,0 +1,667 @@
+package repository
+
+import (
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"bitgo-wallets-api/internal/models"
+
+	"github.com/google/uuid"
+)
+
+func TestTransferOriginIsStoredForEachCreationPath(t *testing.T) {
+	db := openTestDB(t)
+	repo := NewTransferRequestRepository(db)
+	user := createTestUser(t, db, models.RoleOperator)
+	wallet := createTestWallet(t, db, "btc", models.WalletTypeWarm)
+
+	tests := []struct {
+		name   string
+		origin models.TransferOrigin
+		want   models.TransferOrigin
+	}{
+		{name: "default", origin: "", want: models.TransferOriginAPI},
+		{name: "retry", origin: models.TransferOriginRetry, want: models.TransferOriginRetry},
+		{name: "sweep", origin: models.TransferOriginSweep, want: models.TransferOriginSweep},
+		{name: "consolidate", origin: models.TransferOriginConsolidate, want: models.TransferOriginConsolidate},
+		{name: "backfill", origin: models.TransferOriginBackfill, want: models.TransferOriginBackfill},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			transfer := newTestTransfer(wallet, user, models.TransferStatusSubmitted)
+			transfer.Origin = tt.origin
+			if err := repo.Create(transfer); err != nil {
+				t.Fatalf("Create() error = %v", err)
+			}
+
+			stored, err := repo.GetByID(transfer.ID)
+			if err != nil || stored == nil {
+				t.Fatalf("GetByID() = %v, %v", stored, err)
+			}
+			if stored.Origin != tt.want {
+				t.Errorf("stored origin = %q, want %q", stored.Origin, tt.want)
+			}
+		})
+	}
+
+	t.Run("auto", func(t *testing.T) {
+		// Automated processing only claims a transfer once it starts, through Update
+		transfer := newTestTransfer(wallet, user, models.TransferStatusSubmitted)
+		if err := repo.Create(transfer); err != nil {
+			t.Fatalf("Create() error = %v", err)
+		}
+		transfer.Status = models.TransferStatusApproved
+		transfer.Origin = models.TransferOriginAuto
+		if err := repo.Update(transfer); err != nil {
+			t.Fatalf("Update() error = %v", err)
+		}
+
+		stored, err := repo.GetByID(transfer.ID)
+		if err != nil || stored == nil {
+			t.Fatalf("GetByID() = %v, %v", stored, err)
+		}
+		if stored.Origin != models.TransferOriginAuto {
+			t.Errorf("stored origin = %q, want %q", stored.Origin, models.TransferOriginAuto)
+		}
+	})
+
+	t.Run("update without origin keeps it", func(t *testing.T) {
+		transfer := newTestTransfer(wallet, user, models.TransferStatusSubmitted)
+		transfer.Origin = models.TransferOriginSweep
+		if err := repo.Create(transfer); err != nil {
+			t.Fatalf("Create() error = %v", err)
+		}
+		transfer.Origin = ""
+		transfer.Status = models.TransferStatusApproved
+		if err := repo.Update(transfer); err != nil {
+			t.Fatalf("Update() error = %v", err)
+		}
+
+		stored, err := repo.GetByID(transfer.ID)
+		if err != nil || stored == nil {
+			t.Fatalf("GetByID() = %v, %v", stored, err)
+		}
+		if stored.Origin != models.TransferOriginSweep {
+			t.Errorf("stored origin = %q, want %q", stored.Origin, models.TransferOriginSweep)
+		}
+	})
+}
+
+func TestSearchByRecipient(t *testing.T) {
+	db := openTestDB(t)
+	repo := NewTransferRequestRepository(db)
+	user := createTestUser(t, db, models.RoleOperator)
+	first := createTestWallet(t, db, "btc", models.WalletTypeWarm)
+	second := createTestWallet(t, db, "btc", models.WalletTypeCold)
+
+	const recipient = "bc1qsearchrecipient000000000000000000000a"
+	for _, transfer := range []*models.TransferRequest{
+		newTestTransfer(first, user, models.TransferStatusSubmitted),
+		newTestTransfer(second, user, models.TransferStatusSubmitted),
+		newTestTransfer(first, user, models.TransferStatusSubmitted),
+	} {
+		transfer.RecipientAddress = recipient
+		if err := repo.Create(transfer); err != nil {
+			t.Fatalf("Create() error = %v", err)
+		}
+	}
+	sibling := newTestTransfer(second, user, models.TransferStatusSubmitted)
+	sibling.RecipientAddress = "bc1qsearchrecipient000000000000000000000b"
+	if err := repo.Create(sibling); err != nil {
+		t.Fatalf("Create() error = %v", err)
+	}
+
+	tests := []struct {
+		name        string
+		address     string
+		prefix      bool
+		want        int
+		wantWallets int
+	}{
+		{name: "exact match spans wallets", address: recipient, want: 3, wantWallets: 2},
+		{name: "prefix", address: "bc1qsearchrecipient", prefix: true, want: 4, wantWallets: 2},
+		{name: "prefix wildcard is literal", address: "bc1qsearch%", prefix: true, want: 0},
+		{name: "exact match ignores prefixes", address: "bc1qsearchrecipient", want: 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			results, err := repo.SearchByRecipient(tt.address, tt.prefix, false, 10, 0)
+			if err != nil {
+				t.Fatalf("SearchByRecipient() error = %v", err)
+			}
+			if len(results) != tt.want {
+				t.Fatalf("found %d transfers, want %d", len(results), tt.want)
+			}
+
+			wallets := map[uuid.UUID]bool{}
+			for _, result := range results {
+				if result.Wallet.ID != result.Transfer.WalletID {
+					t.Errorf("result wallet = %s, want the transfer's wallet %s", result.Wallet.ID, result.Transfer.WalletID)
+				}
+				wallets[result.Wallet.ID] = true
+			}
+			if len(wallets) != tt.wantWallets {
+				t.Errorf("results span %d wallets, want %d", len(wallets), tt.wantWallets)
+			}
+
+			total, err := repo.CountByRecipient(tt.address, tt.prefix, false)
+			if err != nil {
+				t.Fatalf("CountByRecipient() error = %v", err)
+			}
+			if total != tt.want {
+				t.Errorf("CountByRecipient() = %d, want %d", total, tt.want)
+			}
+		})
+	}
+}
+
+func TestConcurrentApprovalDecisionsAreAllCounted(t *testing.T) {
+	db := openTestDB(t)
+	repo := NewTransferRequestRepository(db)
+	requester := createTestUser(t, db, models.RoleOperator)
+	wallet := createTestWallet(t, db, "btc", models.WalletTypeWarm)
+
+	const approvers = 8
+	transfer := newTestTransfer(wallet, requester, models.TransferStatusPendingApproval)
+	// One more than the approvers, so every decision only adds to the count
+	transfer.RequiredApprovals = approvers + 1
+	if err := repo.Create(transfer); err != nil {
+		t.Fatalf("Create() error = %v", err)
+	}
+
+	ids := make([]uuid.UUID, approvers)
+	for i := range ids {
+		ids[i] = createTestUser(t, db, models.RoleApprover)
+	}
+
+	var wg sync.WaitGroup
+	errs := make([]error, approvers)
+	for i := range ids {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			_, errs[i] = repo.DecideApproval(transfer.ID, &ids[i], true, nil)
+		}(i)
+	}
+	wg.Wait()
+	for i, err := range errs {
+		if err != nil {
+			t.Errorf("approver %d: DecideApproval() error = %v", i, err)
+		}
+	}
+
+	stored, err := repo.GetByID(transfer.ID)
+	if err != nil || stored == nil {
+		t.Fatalf("GetByID() = %v, %v", stored, err)
+	}
+	if stored.ReceivedApprovals != approvers {
+		t.Errorf("received approvals = %d, want %d", stored.ReceivedApprovals, approvers)
+	}
+	if stored.Status != models.TransferStatusPendingApproval {
+		t.Errorf("status = %q, want %q", stored.Status, models.TransferStatusPendingApproval)
+	}
+
+	var recorded int
+	if err := db.QueryRow(`SELECT COUNT(*) FROM transfer_approvals WHERE transfer_id = $1`, transfer.ID).Scan(&recorded); err != nil {
+		t.Fatalf("count approvals: %v", err)
+	}
+	if recorded != approvers {
+		t.Errorf("recorded approvals = %d, want %d", recorded, approvers)
+	}
+
+	t.Run("same approver twice", func(t *testing.T) {
+		if _, err := repo.DecideApproval(transfer.ID, &ids[0], true, nil); !errors.Is(err, ErrAlreadyDecided) {
+			t.Errorf("DecideApproval() error = %v, want %v", err, ErrAlreadyDecided)
+		}
+		stored, err := repo.GetByID(transfer.ID)
+		if err != nil || stored == nil {
+			t.Fatalf("GetByID() = %v, %v", stored, err)
+		}
+		if stored.ReceivedApprovals != approvers {
+			t.Errorf("received approvals = %d, want %d", stored.ReceivedApprovals, approvers)
+		}
+	})
+}
+
+func TestUpdateWithRetryKeepsConcurrentWrites(t *testing.T) {
+	db := openTestDB(t)
+	repo := NewTransferRequestRepository(db)
+	user := createTestUser(t, db, models.RoleOperator)
+	wallet := createTestWallet(t, db, "btc", models.WalletTypeWarm)
+
+	t.Run("stale copy", func(t *testing.T) {
+		transfer := newTestTransfer(wallet, user, models.TransferStatusSubmitted)
+		if err := repo.Create(transfer); err != nil {
+			t.Fatalf("Create() error = %v", err)
+		}
+		stale, err := repo.GetByID(transfer.ID)
+		if err != nil || stale == nil {
+			t.Fatalf("GetByID() = %v, %v", stale, err)
+		}
+
+		bitgoID := "bitgo-transfer-1"
+		transfer.BitgoTransferID = &bitgoID
+		if err := repo.Update(transfer); err != nil {
+			t.Fatalf("Update() error = %v", err)
+		}
+
+		fee := "0.0001"
+		saved, err := UpdateWithRetry(repo, stale, func(transfer *models.TransferRequest) bool {
+			transfer.FeeString = &fee
+			return true
+		})
+		if err != nil || !saved {
+			t.Fatalf("UpdateWithRetry() = %v, %v", saved, err)
+		}
+
+		stored, err := repo.GetByID(transfer.ID)
+		if err != nil || stored == nil {
+			t.Fatalf("GetByID() = %v, %v", stored, err)
+		}
+		if stored.BitgoTransferID == nil || *stored.BitgoTransferID != bitgoID {
+			t.Errorf("bitgo transfer id = %v, want %q; the earlier write was lost", stored.BitgoTransferID, bitgoID)
+		}
+		if stored.FeeString == nil || *stored.FeeString != fee {
+			t.Errorf("fee = %v, want %q", stored.FeeString, fee)
+		}
+	})
+
+	t.Run("concurrent increments", func(t *testing.T) {
+		transfer := newTestTransfer(wallet, user, models.TransferStatusSubmitted)
+		if err := repo.Create(transfer); err != nil {
+			t.Fatalf("Create() error = %v", err)
+		}
+
+		const writers = 6
+		var wg sync.WaitGroup
+		saved := make([]bool, writers)
+		errs := make([]error, writers)
+		for i := 0; i < writers; i++ {
+			snapshot := *transfer
+			wg.Add(1)
+			go func(i int, transfer *models.TransferRequest) {
+				defer wg.Done()
+				saved[i], errs[i] = UpdateWithRetry(repo, transfer, func(transfer *models.TransferRequest) bool {
+					transfer.ReceivedApprovals++
+					return true
+				})
+			}(i, &snapshot)
+		}
+		wg.Wait()
+
+		// Each writer either lands on top of the others or gives up with the conflict the
+		// handlers turn into a 409; none may silently overwrite another
+		succeeded := 0
+		for i := range errs {
+			switch {
+			case errs[i] == nil && saved[i]:
+				succeeded++
+			case errors.Is(errs[i], ErrTransferVersionConflict):
+			default:
+				t.Errorf("writer %d: UpdateWithRetry() = %v, %v", i, saved[i], errs[i])
+			}
+		}
+		if succeeded == 0 {
+			t.Fatal("no writer succeeded")
+		}
+
+		stored, err := repo.GetByID(transfer.ID)
+		if err != nil || stored == nil {
+			t.Fatalf("GetByID() = %v, %v", stored, err)
+		}
+		if stored.ReceivedApprovals != succeeded {
+			t.Errorf("received approvals = %d, want %d, one per successful write", stored.ReceivedApprovals, succeeded)
+		}
+	})
+}
+
+func TestListByTypeAndStatusesPagesWithinType(t *testing.T) {
+	db := openTestDB(t)
+	repo := NewTransferRequestRepository(db)
+	user := createTestUser(t, db, models.RoleOperator)
+	cold := createTestWallet(t, db, "btc", models.WalletTypeCold)
+	warm := createTestWallet(t, db, "btc", models.WalletTypeWarm)
+
+	// Warm transfers first, so a page taken before filtering by type would hold none of the cold ones
+	for i := 0; i < 3; i++ {
+		if err := repo.Create(newTestTransfer(warm, user, models.TransferStatusPendingApproval)); err != nil {
+			t.Fatalf("Create() error = %v", err)
+		}
+	}
+	var coldIDs []uuid.UUID
+	for i := 0; i < 5; i++ {
+		transfer := newTestTransfer(cold, user, models.TransferStatusPendingApproval)
+		if err := repo.Create(transfer); err != nil {
+			t.Fatalf("Create() error = %v", err)
+		}
+		coldIDs = append(coldIDs, transfer.ID)
+	}
+	if err := repo.Create(newTestTransfer(cold, user, models.TransferStatusCompleted)); err != nil {
+		t.Fatalf("Create() error = %v", err)
+	}
+
+	statuses := []models.TransferStatus{models.TransferStatusPendingApproval}
+	page, err := repo.ListByTypeAndStatuses(models.WalletTypeCold, statuses, 2, 2)
+	if err != nil {
+		t.Fatalf("ListByTypeAndStatuses() error = %v", err)
+	}
+	if len(page) != 2 {
+		t.Fatalf("page two has %d transfers, want 2", len(page))
+	}
+	for i, transfer := range page {
+		if transfer.ID != coldIDs[2+i] {
+			t.Errorf("page two[%d] = %s, want cold transfer %s", i, transfer.ID, coldIDs[2+i])
+		}
+	}
+
+	total, err := repo.CountByTypeAndStatuses(models.WalletTypeCold, statuses)
+	if err != nil {
+		t.Fatalf("CountByTypeAndStatuses() error = %v", err)
+	}
+	if total != len(coldIDs) {
+		t.Errorf("CountByTypeAndStatuses() = %d, want %d", total, len(coldIDs))
+	}
+}
+
+func TestListDueForPollingUsesEachTypesInterval(t *testing.T) {
+	db := openTestDB(t)
+	repo := NewTransferRequestRepository(db)
+	user := createTestUser(t, db, models.RoleOperator)
+	hot := newTestTransfer(createTestWallet(t, db, "btc", models.WalletTypeHot), user, models.TransferStatusBroadcast)
+	cold := newTestTransfer(createTestWallet(t, db, "btc", models.WalletTypeCold), user, models.TransferStatusBroadcast)
+
+	polledAt := time.Now().Add(-time.Hour).Truncate(time.Second)
+	for _, transfer := range []*models.TransferRequest{hot, cold} {
+		if err := repo.Create(transfer); err != nil {
+			t.Fatalf("Create() error = %v", err)
+		}
+		if err := repo.MarkPolled(transfer.ID, polledAt); err != nil {
+			t.Fatalf("MarkPolled() error = %v", err)
+		}
+	}
+
+	intervals := map[models.WalletType]time.Duration{
+		models.WalletTypeHot:  30 * time.Second,
+		models.WalletTypeCold: 15 * time.Minute,
+	}
+	statuses := []models.TransferStatus{models.TransferStatusBroadcast}
+	due := func(now time.Time) map[uuid.UUID]bool {
+		t.Helper()
+		dueBefore := make(map[models.WalletType]time.Time, len(intervals))
+		for walletType, interval := range intervals {
+			dueBefore[walletType] = now.Add(-interval)
+		}
+		transfers, err := repo.ListDueForPolling(statuses, dueBefore, now, nil, 10)
+		if err != nil {
+			t.Fatalf("ListDueForPolling() error = %v", err)
+		}
+		ids := make(map[uuid.UUID]bool, len(transfers))
+		for _, transfer := range transfers {
+			ids[transfer.ID] = true
+		}
+		return ids
+	}
+
+	tests := []struct {
+		name     string
+		now      time.Time
+		wantHot  bool
+		wantCold bool
+	}{
+		{name: "within both intervals", now: polledAt.Add(10 * time.Second)},
+		{name: "past the hot interval only", now: polledAt.Add(time.Minute), wantHot: true},
+		{name: "past both intervals", now: polledAt.Add(16 * time.Minute), wantHot: true, wantCold: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ids := due(tt.now)
+			if ids[hot.ID] != tt.wantHot {
+				t.Errorf("hot transfer due = %v, want %v", ids[hot.ID], tt.wantHot)
+			}
+			if ids[cold.ID] != tt.wantCold {
+				t.Errorf("cold transfer due = %v, want %v", ids[cold.ID], tt.wantCold)
+			}
+		})
+	}
+}
+
+func TestListDueForPollingPagesByCursor(t *testing.T) {
+	db := openTestDB(t)
+	repo := NewTransferRequestRepository(db)
+	user := createTestUser(t, db, models.RoleOperator)
+	wallet := createTestWallet(t, db, "btc", models.WalletTypeHot)
+
+	pending := make(map[uuid.UUID]bool)
+	for i := 0; i < 5; i++ {
+		transfer := newTestTransfer(wallet, user, models.TransferStatusBroadcast)
+		if err := repo.Create(transfer); err != nil {
+			t.Fatalf("Create() error = %v", err)
+		}
+		pending[transfer.ID] = true
+	}
+
+	now := time.Now()
+	statuses := []models.TransferStatus{models.TransferStatusBroadcast}
+	dueBefore := map[models.WalletType]time.Time{models.WalletTypeHot: now}
+	var after *TransferCursor
+	for page := 0; page < 3; page++ {
+		transfers, err := repo.ListDueForPolling(statuses, dueBefore, now, after, 2)
+		if err != nil {
+			t.Fatalf("ListDueForPolling() error = %v", err)
+		}
+		for _, transfer := range transfers {
+			if !pending[transfer.ID] {
+				t.Errorf("page %d returned %s again or unexpectedly", page, transfer.ID)
+			}
+			delete(pending, transfer.ID)
+		}
+		if len(transfers) == 0 {
+			break
+		}
+		last := transfers[len(transfers)-1]
+		after = &TransferCursor{UpdatedAt: last.UpdatedAt, ID: last.ID}
+	}
+	if len(pending) != 0 {
+		t.Errorf("%d transfers never returned across pages", len(pending))
+	}
+}
+
+func TestListByWalletFiltersByTag(t *testing.T) {
+	db := openTestDB(t)
+	repo := NewTransferRequestRepository(db)
+	user := createTestUser(t, db, models.RoleOperator)
+	wallet := createTestWallet(t, db, "btc", models.WalletTypeWarm)
+
+	for _, tags := range [][]string{{"payroll", "q3"}, {"payroll"}, {"vendor"}, nil} {
+		transfer := newTestTransfer(wallet, user, models.TransferStatusSubmitted)
+		transfer.Tags = tags
+		if err := repo.Create(transfer); err != nil {
+			t.Fatalf("Create() error = %v", err)
+		}
+	}
+
+	tests := []struct {
+		tag  string
+		want int
+	}{
+		{tag: "payroll", want: 2},
+		{tag: "q3", want: 1},
+		{tag: "vendor", want: 1},
+		{tag: "missing", want: 0},
+		{tag: "", want: 4},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.tag, func(t *testing.T) {
+			filter := TransferListFilter{Tag: tt.tag}
+			transfers, err := repo.ListByWallet(wallet.ID, filter, 10, 0)
+			if err != nil {
+				t.Fatalf("ListByWallet() error = %v", err)
+			}
+			if len(transfers) != tt.want {
+				t.Fatalf("listed %d transfers, want %d", len(transfers), tt.want)
+			}
+			for _, transfer := range transfers {
+				if tt.tag != "" && !containsTag(transfer.Tags, tt.tag) {
+					t.Errorf("transfer %s tags = %v, want %q among them", transfer.ID, transfer.Tags, tt.tag)
+				}
+			}
+
+			total, err := repo.CountByWallet(wallet.ID, filter)
+			if err != nil {
+				t.Fatalf("CountByWallet() error = %v", err)
+			}
+			if total != tt.want {
+				t.Errorf("CountByWallet() = %d, want %d", total, tt.want)
+			}
+		})
+	}
+}
+
+func containsTag(tags []string, tag string) bool {
+	for _, candidate := range tags {
+		if candidate == tag {
+			return true
+		}
+	}
+	return false
+}
+
+func TestArchiveInactiveSinceHidesOldTerminalTransfers(t *testing.T) {
+	db := openTestDB(t)
+	repo := NewTransferRequestRepository(db)
+	user := createTestUser(t, db, models.RoleOperator)
+	wallet := createTestWallet(t, db, "btc", models.WalletTypeWarm)
+
+	create := func(status models.TransferStatus, age time.Duration) *models.TransferRequest {
+		transfer := newTestTransfer(wallet, user, status)
+		if err := repo.Create(transfer); err != nil {
+			t.Fatalf("Create() error = %v", err)
+		}
+		if _, err := db.Exec(`UPDATE transfer_requests SET updated_at = NOW() - $2 * INTERVAL '1 second' WHERE id = $1`, transfer.ID, age.Seconds()); err != nil {
+			t.Fatalf("backdate transfer: %v", err)
+		}
+		return transfer
+	}
+	oldCompleted := create(models.TransferStatusCompleted, 90*24*time.Hour)
+	oldPending := create(models.TransferStatusPendingApproval, 90*24*time.Hour)
+	recentCompleted := create(models.TransferStatusCompleted, time.Hour)
+
+	if _, err := db.Exec(
+		`INSERT INTO audit_logs (user_id, wallet_id, transfer_request_id, action, resource_type, resource_id) VALUES ($1, $2, $3, 'transfer_completed', 'transfer_request', $3)`,
+		user, wallet.ID, oldCompleted.ID,
+	); err != nil {
+		t.Fatalf("create audit log: %v", err)
+	}
+
+	terminal := []models.TransferStatus{models.TransferStatusCompleted, models.TransferStatusFailed}
+	archived, err := repo.ArchiveInactiveSince(terminal, time.Now().Add(-30*24*time.Hour), 100)
+	if err != nil {
+		t.Fatalf("ArchiveInactiveSince() error = %v", err)
+	}
+	if archived != 1 {
+		t.Fatalf("ArchiveInactiveSince() = %d, want 1", archived)
+	}
+
+	listed := func(includeArchived bool) map[uuid.UUID]bool {
+		transfers, err := repo.List(wallet.ID, includeArchived, 10, 0)
+		if err != nil {
+			t.Fatalf("List() error = %v", err)
+		}
+		ids := make(map[uuid.UUID]bool)
+		for _, transfer := range transfers {
+			ids[transfer.ID] = true
+		}
+		return ids
+	}
+	if byDefault := listed(false); byDefault[oldCompleted.ID] || !byDefault[oldPending.ID] || !byDefault[recentCompleted.ID] {
+		t.Errorf("default list = %v, want the pending and recent transfers only", byDefault)
+	}
+	if all := listed(true); len(all) != 3 {
+		t.Errorf("list with archived = %v, want all 3 transfers", all)
+	}
+
+	stored, err := repo.GetByID(oldCompleted.ID)
+	if err != nil {
+		t.Fatalf("GetByID() error = %v", err)
+	}
+	if stored.ArchivedAt == nil {
+		t.Error("archived_at not set on the archived transfer")
+	}
+
+	var auditLogs int
+	if err := db.QueryRow(`SELECT COUNT(*) FROM audit_logs WHERE transfer_request_id = $1`, oldCompleted.ID).Scan(&auditLogs); err != nil {
+		t.Fatalf("count audit logs: %v", err)
+	}
+	if auditLogs != 1 {
+		t.Errorf("%d audit logs for the archived transfer, want 1", auditLogs)
+	}
+}
+
+func TestBuildInfoIsStored(t *testing.T) {
+	db := openTestDB(t)
+	repo := NewTransferRequestRepository(db)
+	user := createTestUser(t, db, models.RoleOperator)
+	wallet := createTestWallet(t, db, "btc", models.WalletTypeHot)
+
+	transfer := newTestTransfer(wallet, user, models.TransferStatusPendingApproval)
+	if err := repo.Create(transfer); err != nil {
+		t.Fatalf("Create() error = %v", err)
+	}
+	want := models.TransferBuildInfo{Size: 250, InputCount: 2, OutputCount: 2, ChangeAddress: "bc1qchange", FeeRate: 10000}
+	buildInfo := want
+	transfer.BuildInfo = &buildInfo
+	if err := repo.Update(transfer); err != nil {
+		t.Fatalf("Update() error = %v", err)
+	}
+
+	stored, err := repo.GetByID(transfer.ID)
+	if err != nil {
+		t.Fatalf("GetByID() error = %v", err)
+	}
+	if stored.BuildInfo == nil || *stored.BuildInfo != want {
+		t.Errorf("BuildInfo = %+v, want %+v", stored.BuildInfo, want)
+	}
+}
+
+func TestListAwaitingApprovalCoversOnlyMemberWallets(t *testing.T) {
+	db := openTestDB(t)
+	repo := NewTransferRequestRepository(db)
+	requester := createTestUser(t, db, models.RoleOperator)
+	approver := createTestUser(t, db, models.RoleOperator)
+
+	warm := createTestWallet(t, db, "btc", models.WalletTypeWarm)
+	cold := createTestWallet(t, db, "btc", models.WalletTypeCold)
+	other := createTestWallet(t, db, "btc", models.WalletTypeWarm)
+	addTestMembership(t, db, warm.ID, approver, "approver")
+	addTestMembership(t, db, cold.ID, approver, "admin")
+
+	want := make(map[uuid.UUID]bool)
+	for _, wallet := range []*models.Wallet{warm, cold, other} {
+		transfer := newTestTransfer(wallet, requester, models.TransferStatusPendingApproval)
+		if err := repo.Create(transfer); err != nil {
+			t.Fatalf("Create() error = %v", err)
+		}
+		if wallet != other {
+			want[transfer.ID] = true
+		}
+	}
+
+	transfers, err := repo.ListAwaitingApproval(approver, 10)
+	if err != nil {
+		t.Fatalf("ListAwaitingApproval() error = %v", err)
+	}
+	if len(transfers) != len(want) {
+		t.Fatalf("%d transfers awaiting approval, want %d", len(transfers), len(want))
+	}
+	for _, transfer := range transfers {
+		if !want[transfer.ID] {
+			t.Errorf("transfer %s on wallet %s listed, want only the member wallets' transfers", transfer.ID, transfer.WalletID)
+		}
+	}
+}
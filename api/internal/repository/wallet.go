@@ -2,19 +2,28 @@ package repository
 
 import (
 	"database/sql"
+	"errors"
 	"fmt"
 
 	"bitgo-wallets-api/internal/models"
 
 	"github.com/google/uuid"
+	"github.com/lib/pq"
 )
 
+// ErrWalletNotFound is returned when no active wallet matches the lookup
+var ErrWalletNotFound = errors.New("wallet not found")
+
 type WalletRepository interface {
 	Create(wallet *models.Wallet) error
 	GetByID(id uuid.UUID) (*models.Wallet, error)
 	GetByBitgoID(bitgoWalletID string) (*models.Wallet, error)
 	List(organizationID uuid.UUID, limit, offset int) ([]*models.Wallet, error)
+	Count(organizationID uuid.UUID) (int, error)
 	Update(wallet *models.Wallet) error
+	SetFrozen(id uuid.UUID, frozen bool, metadata models.JSON) error
+	SetRequiredApprovalsOverride(id uuid.UUID, override *int) error
+	SetTrustedAddresses(id uuid.UUID, addresses []string, audit *models.AuditLog) error
 	Delete(id uuid.UUID) error
 }
 
@@ -31,8 +40,8 @@ func (r *walletRepository) Create(wallet *models.Wallet) error {
 		INSERT INTO wallets (
 			id, organization_id, bitgo_wallet_id, label, coin, wallet_type,
 			balance_string, confirmed_balance_string, spendable_balance_string,
-			is_active, frozen, multisig_type, threshold, tags, metadata
-		) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15)
+			is_active, frozen, multisig_type, threshold, tags, metadata, balance_synced_at
+		) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16)
 		RETURNING created_at, updated_at
 	`
 
@@ -43,7 +52,7 @@ func (r *walletRepository) Create(wallet *models.Wallet) error {
 		wallet.Coin, wallet.WalletType, wallet.BalanceString,
 		wallet.ConfirmedBalanceString, wallet.SpendableBalanceString,
 		wallet.IsActive, wallet.Frozen, wallet.MultisigType, wallet.Threshold,
-		wallet.Tags, wallet.Metadata,
+		wallet.Tags, wallet.Metadata, wallet.BalanceSyncedAt,
 	).Scan(&wallet.CreatedAt, &wallet.UpdatedAt)
 
 	if err != nil {
@@ -57,8 +66,8 @@ func (r *walletRepository) GetByID(id uuid.UUID) (*models.Wallet, error) {
 	query := `
 		SELECT id, organization_id, bitgo_wallet_id, label, coin, wallet_type,
 		       balance_string, confirmed_balance_string, spendable_balance_string,
-		       is_active, frozen, multisig_type, threshold, tags, metadata,
-		       created_at, updated_at
+		       balance_synced_at, is_active, frozen, multisig_type, threshold, required_approvals_override, trusted_addresses,
+		       tags, metadata, created_at, updated_at
 		FROM wallets
 		WHERE id = $1 AND is_active = true
 	`
@@ -68,12 +77,12 @@ func (r *walletRepository) GetByID(id uuid.UUID) (*models.Wallet, error) {
 		&wallet.ID, &wallet.OrganizationID, &wallet.BitgoWalletID, &wallet.Label,
 		&wallet.Coin, &wallet.WalletType, &wallet.BalanceString,
 		&wallet.ConfirmedBalanceString, &wallet.SpendableBalanceString,
-		&wallet.IsActive, &wallet.Frozen, &wallet.MultisigType, &wallet.Threshold,
-		&wallet.Tags, &wallet.Metadata, &wallet.CreatedAt, &wallet.UpdatedAt,
+		&wallet.BalanceSyncedAt, &wallet.IsActive, &wallet.Frozen, &wallet.MultisigType, &wallet.Threshold,
+		&wallet.RequiredApprovalsOverride, &wallet.TrustedAddresses, &wallet.Tags, &wallet.Metadata, &wallet.CreatedAt, &wallet.UpdatedAt,
 	)
 
 	if err == sql.ErrNoRows {
-		return nil, nil
+		return nil, ErrWalletNotFound
 	}
 	if err != nil {
 		return nil, fmt.Errorf("failed to get wallet by ID: %w", err)
@@ -86,8 +95,8 @@ func (r *walletRepository) GetByBitgoID(bitgoWalletID string) (*models.Wallet, e
 	query := `
 		SELECT id, organization_id, bitgo_wallet_id, label, coin, wallet_type,
 		       balance_string, confirmed_balance_string, spendable_balance_string,
-		       is_active, frozen, multisig_type, threshold, tags, metadata,
-		       created_at, updated_at
+		       balance_synced_at, is_active, frozen, multisig_type, threshold, required_approvals_override, trusted_addresses,
+		       tags, metadata, created_at, updated_at
 		FROM wallets
 		WHERE bitgo_wallet_id = $1 AND is_active = true
 	`
@@ -97,12 +106,12 @@ func (r *walletRepository) GetByBitgoID(bitgoWalletID string) (*models.Wallet, e
 		&wallet.ID, &wallet.OrganizationID, &wallet.BitgoWalletID, &wallet.Label,
 		&wallet.Coin, &wallet.WalletType, &wallet.BalanceString,
 		&wallet.ConfirmedBalanceString, &wallet.SpendableBalanceString,
-		&wallet.IsActive, &wallet.Frozen, &wallet.MultisigType, &wallet.Threshold,
-		&wallet.Tags, &wallet.Metadata, &wallet.CreatedAt, &wallet.UpdatedAt,
+		&wallet.BalanceSyncedAt, &wallet.IsActive, &wallet.Frozen, &wallet.MultisigType, &wallet.Threshold,
+		&wallet.RequiredApprovalsOverride, &wallet.TrustedAddresses, &wallet.Tags, &wallet.Metadata, &wallet.CreatedAt, &wallet.UpdatedAt,
 	)
 
 	if err == sql.ErrNoRows {
-		return nil, nil
+		return nil, ErrWalletNotFound
 	}
 	if err != nil {
 		return nil, fmt.Errorf("failed to get wallet by BitGo ID: %w", err)
@@ -111,12 +120,24 @@ func (r *walletRepository) GetByBitgoID(bitgoWalletID string) (*models.Wallet, e
 	return wallet, nil
 }
 
+// Count counts an organization's active wallets
+func (r *walletRepository) Count(organizationID uuid.UUID) (int, error) {
+	query := `SELECT COUNT(*) FROM wallets WHERE organization_id = $1 AND is_active = true`
+
+	var total int
+	if err := r.db.QueryRow(query, organizationID).Scan(&total); err != nil {
+		return 0, fmt.Errorf("failed to count wallets: %w", err)
+	}
+
+	return total, nil
+}
+
 func (r *walletRepository) List(organizationID uuid.UUID, limit, offset int) ([]*models.Wallet, error) {
 	query := `
 		SELECT id, organization_id, bitgo_wallet_id, label, coin, wallet_type,
 		       balance_string, confirmed_balance_string, spendable_balance_string,
-		       is_active, frozen, multisig_type, threshold, tags, metadata,
-		       created_at, updated_at
+		       balance_synced_at, is_active, frozen, multisig_type, threshold, required_approvals_override, trusted_addresses,
+		       tags, metadata, created_at, updated_at
 		FROM wallets
 		WHERE organization_id = $1 AND is_active = true
 		ORDER BY created_at DESC
@@ -136,8 +157,8 @@ func (r *walletRepository) List(organizationID uuid.UUID, limit, offset int) ([]
 			&wallet.ID, &wallet.OrganizationID, &wallet.BitgoWalletID, &wallet.Label,
 			&wallet.Coin, &wallet.WalletType, &wallet.BalanceString,
 			&wallet.ConfirmedBalanceString, &wallet.SpendableBalanceString,
-			&wallet.IsActive, &wallet.Frozen, &wallet.MultisigType, &wallet.Threshold,
-			&wallet.Tags, &wallet.Metadata, &wallet.CreatedAt, &wallet.UpdatedAt,
+			&wallet.BalanceSyncedAt, &wallet.IsActive, &wallet.Frozen, &wallet.MultisigType, &wallet.Threshold,
+			&wallet.RequiredApprovalsOverride, &wallet.TrustedAddresses, &wallet.Tags, &wallet.Metadata, &wallet.CreatedAt, &wallet.UpdatedAt,
 		)
 		if err != nil {
 			return nil, fmt.Errorf("failed to scan wallet: %w", err)
@@ -148,22 +169,24 @@ func (r *walletRepository) List(organizationID uuid.UUID, limit, offset int) ([]
 	return wallets, nil
 }
 
+// Update saves the wallet's editable fields. The frozen flag isn't one of them: it only
+// changes through SetFrozen, so saving a wallet read before a freeze can't lift it.
 func (r *walletRepository) Update(wallet *models.Wallet) error {
 	query := `
 		UPDATE wallets
 		SET label = $1, balance_string = $2, confirmed_balance_string = $3,
-		    spendable_balance_string = $4, frozen = $5, tags = $6, metadata = $7,
-		    updated_at = NOW()
+		    spendable_balance_string = $4, tags = $5, metadata = $6,
+		    balance_synced_at = $7, updated_at = NOW()
 		WHERE id = $8
-		RETURNING updated_at
+		RETURNING frozen, updated_at
 	`
 
 	err := r.db.QueryRow(
 		query,
 		wallet.Label, wallet.BalanceString, wallet.ConfirmedBalanceString,
-		wallet.SpendableBalanceString, wallet.Frozen, wallet.Tags,
-		wallet.Metadata, wallet.ID,
-	).Scan(&wallet.UpdatedAt)
+		wallet.SpendableBalanceString, wallet.Tags,
+		wallet.Metadata, wallet.BalanceSyncedAt, wallet.ID,
+	).Scan(&wallet.Frozen, &wallet.UpdatedAt)
 
 	if err != nil {
 		return fmt.Errorf("failed to update wallet: %w", err)
@@ -172,6 +195,108 @@ func (r *walletRepository) Update(wallet *models.Wallet) error {
 	return nil
 }
 
+// SetRequiredApprovalsOverride sets or, with nil, clears a wallet's approvals override
+func (r *walletRepository) SetRequiredApprovalsOverride(id uuid.UUID, override *int) error {
+	query := `
+		UPDATE wallets
+		SET required_approvals_override = $1, updated_at = NOW()
+		WHERE id = $2 AND is_active = true
+	`
+
+	result, err := r.db.Exec(query, override, id)
+	if err != nil {
+		return fmt.Errorf("failed to set required approvals override: %w", err)
+	}
+	if rows, err := result.RowsAffected(); err == nil && rows == 0 {
+		return ErrWalletNotFound
+	}
+
+	return nil
+}
+
+// SetFrozen freezes or unfreezes a wallet, saving the metadata that records why alongside
+func (r *walletRepository) SetFrozen(id uuid.UUID, frozen bool, metadata models.JSON) error {
+	query := `
+		UPDATE wallets
+		SET frozen = $1, metadata = $2, updated_at = NOW()
+		WHERE id = $3
+	`
+
+	result, err := r.db.Exec(query, frozen, metadata, id)
+	if err != nil {
+		return fmt.Errorf("failed to set wallet frozen: %w", err)
+	}
+	if rows, err := result.RowsAffected(); err == nil && rows == 0 {
+		return ErrWalletNotFound
+	}
+
+	return nil
+}
+
+// SetTrustedAddresses replaces a wallet's trusted destinations; an empty list clears them.
+// The change is written to the audit log in the same transaction, with the list it replaced
+// as the old values; audit supplies who made it.
+func (r *walletRepository) SetTrustedAddresses(id uuid.UUID, addresses []string, audit *models.AuditLog) error {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return fmt.Errorf("failed to begin trusted addresses transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	var previous pq.StringArray
+	err = tx.QueryRow(`SELECT trusted_addresses FROM wallets WHERE id = $1 AND is_active = true FOR UPDATE`, id).Scan(&previous)
+	if errors.Is(err, sql.ErrNoRows) {
+		return ErrWalletNotFound
+	}
+	if err != nil {
+		return fmt.Errorf("failed to get trusted addresses: %w", err)
+	}
+
+	if _, err := tx.Exec(`UPDATE wallets SET trusted_addresses = $1, updated_at = NOW() WHERE id = $2`, pq.StringArray(addresses), id); err != nil {
+		return fmt.Errorf("failed to set trusted addresses: %w", err)
+	}
+
+	audit.WalletID = &id
+	audit.Action = "wallet_trusted_addresses_updated"
+	audit.ResourceType = "wallet"
+	resourceID := id.String()
+	audit.ResourceID = &resourceID
+	audit.OldValues = models.JSON{"trusted_addresses": []string(previous)}
+	audit.NewValues = models.JSON{"trusted_addresses": addresses}
+	if err := insertAuditLog(tx, audit); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit trusted addresses: %w", err)
+	}
+	return nil
+}
+
+// insertAuditLog writes an audit log entry inside the caller's transaction
+func insertAuditLog(tx *sql.Tx, audit *models.AuditLog) error {
+	var ipAddress *string
+	if audit.IPAddress != nil {
+		ip := audit.IPAddress.String()
+		ipAddress = &ip
+	}
+
+	err := tx.QueryRow(`
+		INSERT INTO audit_logs (
+			user_id, wallet_id, transfer_request_id, action, resource_type, resource_id,
+			old_values, new_values, metadata, ip_address, user_agent
+		) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, COALESCE($9, '{}'::jsonb), $10::inet, $11)
+		RETURNING id, created_at
+	`,
+		audit.UserID, audit.WalletID, audit.TransferRequestID, audit.Action, audit.ResourceType,
+		audit.ResourceID, audit.OldValues, audit.NewValues, audit.Metadata, ipAddress, audit.UserAgent,
+	).Scan(&audit.ID, &audit.CreatedAt)
+	if err != nil {
+		return fmt.Errorf("failed to write audit log: %w", err)
+	}
+	return nil
+}
+
 func (r *walletRepository) Delete(id uuid.UUID) error {
 	query := `UPDATE wallets SET is_active = false, updated_at = NOW() WHERE id = $1`
 
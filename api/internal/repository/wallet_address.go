@@ -0,0 +1,122 @@
+package repository
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"strings"
+
+	"bitgo-wallets-api/internal/models"
+
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+)
+
+// ErrDuplicateWalletAddress is returned when a wallet already has the address or label
+var ErrDuplicateWalletAddress = errors.New("wallet address already exists")
+
+type WalletAddressRepository interface {
+	Create(address *models.WalletAddress) error
+	GetByLabel(walletID uuid.UUID, label string) (*models.WalletAddress, error)
+	GetByAddress(walletID uuid.UUID, address string) (*models.WalletAddress, error)
+	ListByWallet(walletID uuid.UUID) ([]*models.WalletAddress, error)
+}
+
+type walletAddressRepository struct {
+	db *sql.DB
+}
+
+func NewWalletAddressRepository(db *sql.DB) WalletAddressRepository {
+	return &walletAddressRepository{db: db}
+}
+
+const walletAddressColumns = `id, wallet_id, address, label, chain, address_index, created_at`
+
+func scanWalletAddress(row interface{ Scan(...interface{}) error }) (*models.WalletAddress, error) {
+	address := &models.WalletAddress{}
+	err := row.Scan(
+		&address.ID, &address.WalletID, &address.Address, &address.Label,
+		&address.Chain, &address.AddressIndex, &address.CreatedAt,
+	)
+	return address, err
+}
+
+func (r *walletAddressRepository) Create(address *models.WalletAddress) error {
+	query := `
+		INSERT INTO wallet_addresses (id, wallet_id, address, label, chain, address_index)
+		VALUES ($1, $2, $3, $4, $5, $6)
+		RETURNING created_at
+	`
+
+	address.ID = uuid.New()
+	err := r.db.QueryRow(
+		query,
+		address.ID, address.WalletID, address.Address, address.Label,
+		address.Chain, address.AddressIndex,
+	).Scan(&address.CreatedAt)
+
+	var pqErr *pq.Error
+	if errors.As(err, &pqErr) && pqErr.Code == "23505" {
+		return ErrDuplicateWalletAddress
+	}
+	if err != nil {
+		return fmt.Errorf("failed to create wallet address: %w", err)
+	}
+
+	return nil
+}
+
+// GetByLabel returns the wallet's address with the given label, or nil if there is none
+func (r *walletAddressRepository) GetByLabel(walletID uuid.UUID, label string) (*models.WalletAddress, error) {
+	query := `SELECT ` + walletAddressColumns + ` FROM wallet_addresses WHERE wallet_id = $1 AND label = $2`
+
+	address, err := scanWalletAddress(r.db.QueryRow(query, walletID, label))
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get wallet address by label: %w", err)
+	}
+
+	return address, nil
+}
+
+// GetByAddress returns the wallet's generated address matching address, or nil if it isn't
+// one of the wallet's addresses. Hex (0x) addresses are compared case-insensitively.
+func (r *walletAddressRepository) GetByAddress(walletID uuid.UUID, address string) (*models.WalletAddress, error) {
+	query := `SELECT ` + walletAddressColumns + ` FROM wallet_addresses WHERE wallet_id = $1 AND address = $2`
+	if strings.HasPrefix(strings.ToLower(address), "0x") {
+		query = `SELECT ` + walletAddressColumns + ` FROM wallet_addresses WHERE wallet_id = $1 AND LOWER(address) = LOWER($2)`
+	}
+
+	found, err := scanWalletAddress(r.db.QueryRow(query, walletID, address))
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get wallet address: %w", err)
+	}
+
+	return found, nil
+}
+
+func (r *walletAddressRepository) ListByWallet(walletID uuid.UUID) ([]*models.WalletAddress, error) {
+	query := `SELECT ` + walletAddressColumns + ` FROM wallet_addresses WHERE wallet_id = $1 ORDER BY created_at DESC`
+
+	rows, err := r.db.Query(query, walletID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list wallet addresses: %w", err)
+	}
+	defer rows.Close()
+
+	var addresses []*models.WalletAddress
+	for rows.Next() {
+		address, err := scanWalletAddress(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan wallet address: %w", err)
+		}
+		addresses = append(addresses, address)
+	}
+
+	return addresses, nil
+}
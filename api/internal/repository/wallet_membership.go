@@ -0,0 +1,83 @@
+package repository
+
+import (
+	"database/sql"
+	"fmt"
+
+	"bitgo-wallets-api/internal/models"
+
+	"github.com/google/uuid"
+)
+
+type WalletMembershipRepository interface {
+	GetByWalletAndUser(walletID, userID uuid.UUID) (*models.WalletMembership, error)
+	ListApprovers(walletID uuid.UUID) ([]*models.WalletApprover, error)
+}
+
+type walletMembershipRepository struct {
+	db *sql.DB
+}
+
+func NewWalletMembershipRepository(db *sql.DB) WalletMembershipRepository {
+	return &walletMembershipRepository{db: db}
+}
+
+// GetByWalletAndUser returns the user's membership on the wallet, or nil if they have none
+func (r *walletMembershipRepository) GetByWalletAndUser(walletID, userID uuid.UUID) (*models.WalletMembership, error) {
+	query := `
+		SELECT id, wallet_id, user_id, role, permissions, created_at, updated_at
+		FROM wallet_memberships
+		WHERE wallet_id = $1 AND user_id = $2
+	`
+
+	membership := &models.WalletMembership{}
+	err := r.db.QueryRow(query, walletID, userID).Scan(
+		&membership.ID, &membership.WalletID, &membership.UserID, &membership.Role,
+		&membership.Permissions, &membership.CreatedAt, &membership.UpdatedAt,
+	)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get wallet membership: %w", err)
+	}
+
+	return membership, nil
+}
+
+// ListApprovers returns the wallet's active members who can approve its transfers: those with
+// an approver or admin membership, or whose user role is approver or admin
+func (r *walletMembershipRepository) ListApprovers(walletID uuid.UUID) ([]*models.WalletApprover, error) {
+	query := `
+		SELECT u.id, u.email, u.first_name, u.last_name, m.role, u.role
+		FROM wallet_memberships m
+		JOIN users u ON u.id = m.user_id
+		WHERE m.wallet_id = $1 AND u.is_active
+		  AND (m.role IN ('approver', 'admin') OR u.role IN ('approver', 'admin'))
+		ORDER BY u.email
+	`
+
+	rows, err := r.db.Query(query, walletID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list wallet approvers: %w", err)
+	}
+	defer rows.Close()
+
+	var approvers []*models.WalletApprover
+	for rows.Next() {
+		approver := &models.WalletApprover{}
+		if err := rows.Scan(
+			&approver.UserID, &approver.Email, &approver.FirstName, &approver.LastName,
+			&approver.WalletRole, &approver.UserRole,
+		); err != nil {
+			return nil, fmt.Errorf("failed to scan wallet approver: %w", err)
+		}
+		approvers = append(approvers, approver)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to list wallet approvers: %w", err)
+	}
+
+	return approvers, nil
+}
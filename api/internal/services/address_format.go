@@ -0,0 +1,30 @@
+package services
+
+import (
+	"fmt"
+	"strings"
+
+	"bitgo-wallets-api/internal/bitgo"
+)
+
+// validateAddressFormat applies a basic format check for the coin's family, so testnet
+// variants (tbtc, tltc, teth, hteth, ...) get the same checks as their mainnet coin. Coins
+// without a known family are accepted as-is.
+func validateAddressFormat(address, coin string) error {
+	switch bitgo.CoinFamily(coin) {
+	case "btc":
+		if len(address) < 26 || len(address) > 62 {
+			return fmt.Errorf("invalid Bitcoin address format")
+		}
+	case "ltc":
+		if len(address) < 26 || len(address) > 63 {
+			return fmt.Errorf("invalid Litecoin address format")
+		}
+	case "eth":
+		if len(address) != 42 || !strings.HasPrefix(address, "0x") {
+			return fmt.Errorf("invalid Ethereum address format")
+		}
+	}
+
+	return nil
+}
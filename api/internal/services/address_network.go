@@ -0,0 +1,72 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"bitgo-wallets-api/internal/bitgo"
+)
+
+// Network is the blockchain network a coin or address belongs to
+type Network string
+
+const (
+	NetworkMainnet Network = "mainnet"
+	NetworkTestnet Network = "testnet"
+)
+
+// ErrAddressNetworkMismatch is returned when a recipient address or coin belongs to a different
+// network than the configured BitGo environment
+var ErrAddressNetworkMismatch = errors.New("address network mismatch")
+
+// NetworkForEnvironment maps a BitGo environment to the network it transacts on. Anything
+// other than prod is BitGo's test environment.
+func NetworkForEnvironment(environment string) Network {
+	if strings.EqualFold(strings.TrimSpace(environment), "prod") {
+		return NetworkMainnet
+	}
+	return NetworkTestnet
+}
+
+// bitcoinAddressNetwork infers the network from a bitcoin address prefix, returning "" when
+// the prefix isn't recognised
+func bitcoinAddressNetwork(address string) Network {
+	lower := strings.ToLower(address)
+	switch {
+	case strings.HasPrefix(lower, "bc1"):
+		return NetworkMainnet
+	case strings.HasPrefix(lower, "tb1"):
+		return NetworkTestnet
+	case strings.HasPrefix(address, "1"), strings.HasPrefix(address, "3"):
+		return NetworkMainnet
+	case strings.HasPrefix(address, "m"), strings.HasPrefix(address, "n"), strings.HasPrefix(address, "2"):
+		return NetworkTestnet
+	}
+	return ""
+}
+
+// ValidateAddressNetwork checks that a bitcoin coin and recipient address both belong to the
+// expected network. Other coins are accepted as-is since their addresses look the same on
+// every network.
+func ValidateAddressNetwork(address, coin string, expected Network) error {
+	info, ok := bitgo.LookupCoin(coin)
+	if !ok || info.Family != "btc" {
+		return nil
+	}
+	coinNetwork := NetworkMainnet
+	if info.Testnet {
+		coinNetwork = NetworkTestnet
+	}
+
+	if coinNetwork != expected {
+		return fmt.Errorf("%w: %s is a %s coin but the BitGo environment is %s", ErrAddressNetworkMismatch, coin, coinNetwork, expected)
+	}
+
+	addressNetwork := bitcoinAddressNetwork(strings.TrimSpace(address))
+	if addressNetwork != "" && addressNetwork != expected {
+		return fmt.Errorf("%w: %s is a %s address but %s transfers need a %s address", ErrAddressNetworkMismatch, address, addressNetwork, coin, expected)
+	}
+
+	return nil
+}
@@ -0,0 +1,72 @@
+package services
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestValidateAddressNetwork(t *testing.T) {
+	tests := []struct {
+		name     string
+		address  string
+		coin     string
+		expected Network
+		wantErr  bool
+	}{
+		{name: "mainnet bech32 on testnet", address: "bc1qar0srrr7xfkvy5l643lydnw9re59gtzzwf5mdq", coin: "tbtc", expected: NetworkTestnet, wantErr: true},
+		{name: "mainnet legacy on testnet", address: "1BoatSLRHtKNngkdXEeobR76b53LETtpyT", coin: "tbtc", expected: NetworkTestnet, wantErr: true},
+		{name: "testnet bech32 on testnet", address: "tb1qw508d6qejxtdg4y5r3zarvary0c5xw7kxpjzsx", coin: "tbtc", expected: NetworkTestnet},
+		{name: "testnet p2sh on testnet", address: "2MzQwSSnBHWHqSAqtTVQ6v47XtaisrJa1Vc", coin: "tbtc", expected: NetworkTestnet},
+		{name: "mainnet on mainnet", address: "bc1qar0srrr7xfkvy5l643lydnw9re59gtzzwf5mdq", coin: "btc", expected: NetworkMainnet},
+		{name: "testnet address on mainnet", address: "tb1qw508d6qejxtdg4y5r3zarvary0c5xw7kxpjzsx", coin: "btc", expected: NetworkMainnet, wantErr: true},
+		{name: "mainnet coin on testnet", address: "tb1qw508d6qejxtdg4y5r3zarvary0c5xw7kxpjzsx", coin: "btc", expected: NetworkTestnet, wantErr: true},
+		{name: "other coin family", address: "0x742d35Cc6634C0532925a3b844Bc454e4438f44e", coin: "eth", expected: NetworkTestnet},
+		{name: "tbtc4 alias on testnet", address: "tb1qw508d6qejxtdg4y5r3zarvary0c5xw7kxpjzsx", coin: "tbtc4", expected: NetworkTestnet},
+		{name: "tbtc4 alias with mainnet address", address: "bc1qar0srrr7xfkvy5l643lydnw9re59gtzzwf5mdq", coin: "TBTC4", expected: NetworkTestnet, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ValidateAddressNetwork(tt.address, tt.coin, tt.expected)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ValidateAddressNetwork() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err != nil && !errors.Is(err, ErrAddressNetworkMismatch) {
+				t.Errorf("ValidateAddressNetwork() error = %v, want ErrAddressNetworkMismatch", err)
+			}
+		})
+	}
+}
+
+func TestNetworkForEnvironment(t *testing.T) {
+	for environment, want := range map[string]Network{"prod": NetworkMainnet, " PROD ": NetworkMainnet, "test": NetworkTestnet, "": NetworkTestnet} {
+		if got := NetworkForEnvironment(environment); got != want {
+			t.Errorf("NetworkForEnvironment(%q) = %s, want %s", environment, got, want)
+		}
+	}
+}
+
+func TestValidateAddressFormatCoversTestnetCoins(t *testing.T) {
+	const ethAddress = "0x742d35Cc6634C0532925a3b844Bc454e4438f44e"
+	tests := []struct {
+		coin    string
+		address string
+		wantErr bool
+	}{
+		{coin: "teth", address: ethAddress},
+		{coin: "hteth", address: ethAddress},
+		{coin: " TETH ", address: ethAddress},
+		{coin: "teth", address: "742d35Cc6634C0532925a3b844Bc454e4438f44e", wantErr: true},
+		{coin: "hteth", address: "0x742d", wantErr: true},
+		{coin: "tltc", address: "QWHf6CHCAkBCYM5zd7RJqSyP2Nt1QZf6bx"},
+		{coin: "tltc", address: "tltc1short", wantErr: true},
+		{coin: "ltc", address: "tltc1short", wantErr: true},
+		{coin: "unknowncoin", address: "anything"},
+	}
+
+	for _, tt := range tests {
+		if err := validateAddressFormat(tt.address, tt.coin); (err != nil) != tt.wantErr {
+			t.Errorf("validateAddressFormat(%q, %q) error = %v, wantErr %v", tt.address, tt.coin, err, tt.wantErr)
+		}
+	}
+}
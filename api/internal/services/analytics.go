@@ -0,0 +1,259 @@
+package services
+
+import (
+	"context"
+	"math/big"
+	"sort"
+	"strings"
+
+	"bitgo-wallets-api/internal/models"
+)
+
+// TransferAnalytics summarizes a set of transfers
+type TransferAnalytics struct {
+	TransferCount         int                           `json:"transfer_count"`
+	Volume                string                        `json:"volume,omitempty"`
+	AvgProcessingHours    float64                       `json:"avg_processing_hours"`
+	MedianProcessingHours float64                       `json:"median_processing_hours"`
+	SuccessRate           float64                       `json:"success_rate"`
+	StatusBreakdown       map[models.TransferStatus]int `json:"status_breakdown"`
+	OriginBreakdown       map[models.TransferOrigin]int `json:"origin_breakdown"`
+	AutoRate              float64                       `json:"auto_rate"` // Share of transfers created by the auto workflow
+}
+
+// TransferAnalyticsReport holds overall analytics plus a per-coin breakdown.
+// Volume is only reported per coin since amounts in different coins can't be summed.
+type TransferAnalyticsReport struct {
+	TransferAnalytics
+	Coins map[string]*TransferAnalytics `json:"coins"`
+}
+
+// successfulTransferStatuses are the statuses that count as a successful transfer
+var successfulTransferStatuses = []models.TransferStatus{
+	models.TransferStatusConfirmed,
+	models.TransferStatusCompleted,
+}
+
+// unsuccessfulTransferStatuses are the terminal statuses that count as an unsuccessful transfer
+var unsuccessfulTransferStatuses = []models.TransferStatus{
+	models.TransferStatusFailed,
+	models.TransferStatusRejected,
+	models.TransferStatusCancelled,
+	models.TransferStatusExpired,
+}
+
+// transferAccumulator collects the raw values needed to compute TransferAnalytics
+type transferAccumulator struct {
+	count           int
+	volume          *big.Rat
+	volumeScale     int
+	volumeValid     bool
+	processingHours []float64
+	succeeded       int
+	finished        int
+	statusBreakdown map[models.TransferStatus]int
+	originBreakdown map[models.TransferOrigin]int
+}
+
+func newTransferAccumulator() *transferAccumulator {
+	return &transferAccumulator{
+		volume:          new(big.Rat),
+		statusBreakdown: make(map[models.TransferStatus]int),
+		originBreakdown: make(map[models.TransferOrigin]int),
+	}
+}
+
+func (a *transferAccumulator) add(transfer *models.TransferRequest) {
+	a.count++
+	a.statusBreakdown[transfer.Status]++
+
+	origin := transfer.Origin
+	if origin == "" {
+		origin = models.TransferOriginAPI
+	}
+	a.originBreakdown[origin]++
+
+	if amount, ok := new(big.Rat).SetString(strings.TrimSpace(transfer.AmountString)); ok {
+		a.volume.Add(a.volume, amount)
+		a.volumeValid = true
+		if scale := decimalScale(transfer.AmountString); scale > a.volumeScale {
+			a.volumeScale = scale
+		}
+	}
+
+	if statusIn(transfer.Status, successfulTransferStatuses) {
+		a.succeeded++
+		a.finished++
+
+		completedAt := transfer.UpdatedAt
+		if transfer.CompletedAt != nil {
+			completedAt = *transfer.CompletedAt
+		}
+		if !completedAt.IsZero() && completedAt.After(transfer.CreatedAt) {
+			a.processingHours = append(a.processingHours, completedAt.Sub(transfer.CreatedAt).Hours())
+		}
+	} else if statusIn(transfer.Status, unsuccessfulTransferStatuses) {
+		a.finished++
+	}
+}
+
+func (a *transferAccumulator) result(includeVolume bool) *TransferAnalytics {
+	analytics := &TransferAnalytics{
+		TransferCount:         a.count,
+		AvgProcessingHours:    average(a.processingHours),
+		MedianProcessingHours: median(a.processingHours),
+		StatusBreakdown:       a.statusBreakdown,
+		OriginBreakdown:       a.originBreakdown,
+	}
+
+	if includeVolume && a.volumeValid {
+		analytics.Volume = a.volume.FloatString(a.volumeScale)
+	}
+
+	if a.count > 0 {
+		analytics.AutoRate = float64(a.originBreakdown[models.TransferOriginAuto]) / float64(a.count)
+	}
+	if a.finished > 0 {
+		analytics.SuccessRate = float64(a.succeeded) / float64(a.finished)
+	}
+
+	return analytics
+}
+
+// TransferAnalyticsAggregator builds a TransferAnalyticsReport one transfer at a time, so
+// callers can stream transfers from the database instead of loading them all
+type TransferAnalyticsAggregator struct {
+	overall *transferAccumulator
+	byCoin  map[string]*transferAccumulator
+}
+
+func NewTransferAnalyticsAggregator() *TransferAnalyticsAggregator {
+	return &TransferAnalyticsAggregator{
+		overall: newTransferAccumulator(),
+		byCoin:  make(map[string]*transferAccumulator),
+	}
+}
+
+// Add counts a transfer overall and under its coin
+func (g *TransferAnalyticsAggregator) Add(transfer *models.TransferRequest) {
+	g.overall.add(transfer)
+
+	coin := strings.ToLower(transfer.Coin)
+	acc, ok := g.byCoin[coin]
+	if !ok {
+		acc = newTransferAccumulator()
+		g.byCoin[coin] = acc
+	}
+	acc.add(transfer)
+}
+
+// Report returns the analytics of every transfer added so far
+func (g *TransferAnalyticsAggregator) Report() *TransferAnalyticsReport {
+	report := &TransferAnalyticsReport{
+		TransferAnalytics: *g.overall.result(false),
+		Coins:             make(map[string]*TransferAnalytics, len(g.byCoin)),
+	}
+	for coin, acc := range g.byCoin {
+		report.Coins[coin] = acc.result(true)
+	}
+
+	return report
+}
+
+// ComputeTransferAnalytics aggregates transfers overall and per coin using exact decimal
+// arithmetic for volumes. Processing time is measured from creation to completion for
+// successful transfers; success rate is successful over all finished transfers.
+func ComputeTransferAnalytics(transfers []*models.TransferRequest) *TransferAnalyticsReport {
+	aggregator := NewTransferAnalyticsAggregator()
+	for _, transfer := range transfers {
+		aggregator.Add(transfer)
+	}
+
+	return aggregator.Report()
+}
+
+// FiatVolume is per-coin transfer volume converted to USD. Coins the oracle can't price are
+// listed in Unpriced and left out of the total rather than guessed.
+type FiatVolume struct {
+	Currency string             `json:"currency"`
+	Total    float64            `json:"total"`
+	Coins    map[string]float64 `json:"coins"`
+	Unpriced []string           `json:"unpriced,omitempty"`
+}
+
+// ConvertVolumeToUSD prices each coin's volume in the report so volumes can be compared and
+// summed across coins
+func ConvertVolumeToUSD(ctx context.Context, oracle PriceOracle, report *TransferAnalyticsReport) *FiatVolume {
+	fiat := &FiatVolume{
+		Currency: "USD",
+		Coins:    make(map[string]float64, len(report.Coins)),
+	}
+
+	for coin, analytics := range report.Coins {
+		if analytics.Volume == "" {
+			continue
+		}
+		volume, ok := new(big.Rat).SetString(analytics.Volume)
+		if !ok {
+			fiat.Unpriced = append(fiat.Unpriced, coin)
+			continue
+		}
+		price, err := oracle.USDPrice(ctx, coin)
+		if err != nil || price <= 0 {
+			fiat.Unpriced = append(fiat.Unpriced, coin)
+			continue
+		}
+
+		amount, _ := volume.Float64()
+		fiat.Coins[coin] = amount * price
+		fiat.Total += fiat.Coins[coin]
+	}
+	sort.Strings(fiat.Unpriced)
+
+	return fiat
+}
+
+// decimalScale returns the number of digits after the decimal point in an amount string
+func decimalScale(amountStr string) int {
+	amountStr = strings.TrimSpace(amountStr)
+	if i := strings.IndexByte(amountStr, '.'); i >= 0 {
+		return len(amountStr) - i - 1
+	}
+	return 0
+}
+
+func statusIn(status models.TransferStatus, statuses []models.TransferStatus) bool {
+	for _, s := range statuses {
+		if s == status {
+			return true
+		}
+	}
+	return false
+}
+
+func average(values []float64) float64 {
+	if len(values) == 0 {
+		return 0
+	}
+
+	sum := 0.0
+	for _, v := range values {
+		sum += v
+	}
+	return sum / float64(len(values))
+}
+
+func median(values []float64) float64 {
+	if len(values) == 0 {
+		return 0
+	}
+
+	sorted := append([]float64(nil), values...)
+	sort.Float64s(sorted)
+
+	mid := len(sorted) / 2
+	if len(sorted)%2 == 0 {
+		return (sorted[mid-1] + sorted[mid]) / 2
+	}
+	return sorted[mid]
+}
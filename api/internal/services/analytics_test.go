@@ -0,0 +1,169 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"bitgo-wallets-api/internal/models"
+)
+
+func TestMedian(t *testing.T) {
+	tests := []struct {
+		name   string
+		values []float64
+		want   float64
+	}{
+		{name: "empty", want: 0},
+		{name: "single", values: []float64{3}, want: 3},
+		{name: "odd count unsorted", values: []float64{5, 1, 3}, want: 3},
+		{name: "even count averages the middle pair", values: []float64{4, 1, 3, 2}, want: 2.5},
+		{name: "outlier", values: []float64{1, 2, 100}, want: 2},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			values := append([]float64(nil), tt.values...)
+			if got := median(values); got != tt.want {
+				t.Errorf("median(%v) = %v, want %v", tt.values, got, tt.want)
+			}
+			for i := range values {
+				if values[i] != tt.values[i] {
+					t.Fatalf("median reordered its input to %v", values)
+				}
+			}
+		})
+	}
+}
+
+func TestComputeTransferAnalyticsGroupsByCoin(t *testing.T) {
+	created := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	transfer := func(coin, amount string, status models.TransferStatus, hours int) *models.TransferRequest {
+		transfer := &models.TransferRequest{Coin: coin, AmountString: amount, Status: status, CreatedAt: created}
+		if hours > 0 {
+			completedAt := created.Add(time.Duration(hours) * time.Hour)
+			transfer.CompletedAt = &completedAt
+		}
+		return transfer
+	}
+
+	report := ComputeTransferAnalytics([]*models.TransferRequest{
+		transfer("btc", "0.1", models.TransferStatusCompleted, 1),
+		transfer("BTC", "0.2", models.TransferStatusConfirmed, 3),
+		transfer("btc", "0.00000001", models.TransferStatusFailed, 0),
+		transfer("eth", "1.5", models.TransferStatusCompleted, 2),
+		transfer("eth", "2", models.TransferStatusPendingApproval, 0),
+	})
+
+	if report.TransferCount != 5 {
+		t.Errorf("overall count = %d, want 5", report.TransferCount)
+	}
+	if report.Volume != "" {
+		t.Errorf("overall volume = %q, want none since coins can't be summed", report.Volume)
+	}
+	if report.SuccessRate != 0.75 {
+		t.Errorf("overall success rate = %v, want 0.75", report.SuccessRate)
+	}
+	if report.MedianProcessingHours != 2 {
+		t.Errorf("overall median processing hours = %v, want 2", report.MedianProcessingHours)
+	}
+
+	tests := []struct {
+		coin        string
+		count       int
+		volume      string
+		successRate float64
+		median      float64
+	}{
+		{coin: "btc", count: 3, volume: "0.30000001", successRate: 2.0 / 3, median: 2},
+		{coin: "eth", count: 2, volume: "3.5", successRate: 1, median: 2},
+	}
+	if len(report.Coins) != len(tests) {
+		t.Fatalf("report has %d coins, want %d: %v", len(report.Coins), len(tests), report.Coins)
+	}
+	for _, tt := range tests {
+		coin := report.Coins[tt.coin]
+		if coin == nil {
+			t.Errorf("no analytics for %s", tt.coin)
+			continue
+		}
+		if coin.TransferCount != tt.count || coin.Volume != tt.volume || coin.SuccessRate != tt.successRate || coin.MedianProcessingHours != tt.median {
+			t.Errorf("%s: count %d, volume %s, success rate %v, median %v; want %d, %s, %v, %v",
+				tt.coin, coin.TransferCount, coin.Volume, coin.SuccessRate, coin.MedianProcessingHours,
+				tt.count, tt.volume, tt.successRate, tt.median)
+		}
+	}
+}
+
+// priceTable prices the coins it lists and fails for any other
+type priceTable map[string]float64
+
+func (p priceTable) USDPrice(_ context.Context, coin string) (float64, error) {
+	price, ok := p[coin]
+	if !ok {
+		return 0, fmt.Errorf("no price for %s", coin)
+	}
+	return price, nil
+}
+
+func TestConvertVolumeToUSD(t *testing.T) {
+	report := ComputeTransferAnalytics([]*models.TransferRequest{
+		{Coin: "btc", AmountString: "0.1", Status: models.TransferStatusCompleted},
+		{Coin: "btc", AmountString: "0.15", Status: models.TransferStatusBroadcast},
+		{Coin: "eth", AmountString: "1.5", Status: models.TransferStatusCompleted},
+		{Coin: "doge", AmountString: "1000", Status: models.TransferStatusCompleted},
+	})
+
+	// Native volumes stay per coin; 0.25 BTC and 1.5 ETH are never added together
+	if report.Volume != "" {
+		t.Errorf("overall volume = %q, want none", report.Volume)
+	}
+	for coin, want := range map[string]string{"btc": "0.25", "eth": "1.5", "doge": "1000"} {
+		if got := report.Coins[coin].Volume; got != want {
+			t.Errorf("%s volume = %q, want %q", coin, got, want)
+		}
+	}
+
+	// doge has no price, so it is listed as unpriced and left out of the total
+	oracle := priceTable{"btc": 40000, "eth": 2000}
+	fiat := ConvertVolumeToUSD(context.Background(), oracle, report)
+
+	if fiat.Currency != "USD" {
+		t.Errorf("currency = %q, want USD", fiat.Currency)
+	}
+	wantCoins := map[string]float64{"btc": 10000, "eth": 3000}
+	if len(fiat.Coins) != len(wantCoins) {
+		t.Errorf("priced coins = %v, want %v", fiat.Coins, wantCoins)
+	}
+	for coin, want := range wantCoins {
+		if got := fiat.Coins[coin]; got != want {
+			t.Errorf("%s USD volume = %v, want %v", coin, got, want)
+		}
+	}
+	if fiat.Total != 13000 {
+		t.Errorf("USD total = %v, want 13000", fiat.Total)
+	}
+	if len(fiat.Unpriced) != 1 || fiat.Unpriced[0] != "doge" {
+		t.Errorf("unpriced = %v, want [doge]", fiat.Unpriced)
+	}
+}
+
+func TestConvertVolumeToUSDWithoutPrices(t *testing.T) {
+	report := ComputeTransferAnalytics([]*models.TransferRequest{
+		{Coin: "eth", AmountString: "2", Status: models.TransferStatusCompleted},
+		{Coin: "btc", AmountString: "1", Status: models.TransferStatusCompleted},
+	})
+	unavailable := PriceOracleFunc(func(context.Context, string) (float64, error) {
+		return 0, errors.New("price feed down")
+	})
+
+	fiat := ConvertVolumeToUSD(context.Background(), unavailable, report)
+	if fiat.Total != 0 || len(fiat.Coins) != 0 {
+		t.Errorf("fiat volume = %+v, want nothing priced", fiat)
+	}
+	if len(fiat.Unpriced) != 2 || fiat.Unpriced[0] != "btc" || fiat.Unpriced[1] != "eth" {
+		t.Errorf("unpriced = %v, want [btc eth]", fiat.Unpriced)
+	}
+}
@@ -0,0 +1,228 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"bitgo-wallets-api/internal/models"
+	"bitgo-wallets-api/internal/repository"
+)
+
+// ApprovalTimeoutConfig configures the approval timeout sweeper
+type ApprovalTimeoutConfig struct {
+	SweepInterval   time.Duration                       // How often to look for expired transfers
+	BatchSize       int                                 // Max transfers expired per wallet type per sweep
+	Timeouts        map[models.WalletType]time.Duration // Approval window per wallet type; zero disables
+	ShutdownTimeout time.Duration                       // Timeout for graceful shutdown
+	Clock           Clock                               // Supplies the current time; nil uses the wall clock
+}
+
+// DefaultApprovalTimeoutConfig returns sensible defaults
+func DefaultApprovalTimeoutConfig() ApprovalTimeoutConfig {
+	return ApprovalTimeoutConfig{
+		SweepInterval:   5 * time.Minute,
+		BatchSize:       100,
+		Timeouts:        map[models.WalletType]time.Duration{},
+		ShutdownTimeout: 30 * time.Second,
+	}
+}
+
+// approvalPendingStatuses are the statuses in which a transfer is still gathering approvals
+var approvalPendingStatuses = []models.TransferStatus{
+	models.TransferStatusSubmitted,
+	models.TransferStatusPendingApproval,
+}
+
+// ApprovalTimeoutSweeper expires transfers whose approvals were not gathered in time
+type ApprovalTimeoutSweeper struct {
+	config          ApprovalTimeoutConfig
+	logger          Logger
+	transferRepo    repository.TransferRequestRepository
+	notificationSvc NotificationService
+
+	ctx       context.Context
+	cancel    context.CancelFunc
+	wg        sync.WaitGroup
+	isRunning bool
+	lastSweep time.Time
+	mu        sync.RWMutex
+}
+
+// NewApprovalTimeoutSweeper creates a new approval timeout sweeper
+func NewApprovalTimeoutSweeper(
+	config ApprovalTimeoutConfig,
+	logger Logger,
+	transferRepo repository.TransferRequestRepository,
+	notificationSvc NotificationService,
+) *ApprovalTimeoutSweeper {
+	config.Clock = clockOrReal(config.Clock)
+
+	ctx, cancel := context.WithCancel(context.Background())
+
+	return &ApprovalTimeoutSweeper{
+		config:          config,
+		logger:          logger,
+		transferRepo:    transferRepo,
+		notificationSvc: notificationSvc,
+		ctx:             ctx,
+		cancel:          cancel,
+	}
+}
+
+// Start begins the periodic sweep
+func (s *ApprovalTimeoutSweeper) Start() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.isRunning {
+		return fmt.Errorf("approval timeout sweeper is already running")
+	}
+
+	s.isRunning = true
+	s.logger.Info("Starting approval timeout sweeper",
+		"sweep_interval", s.config.SweepInterval,
+		"timeouts", s.config.Timeouts,
+	)
+
+	s.wg.Add(1)
+	go s.sweepLoop()
+
+	return nil
+}
+
+// Stop gracefully stops the sweeper, returning ErrShutdownTimeout if a sweep outlasts the
+// shutdown timeout
+func (s *ApprovalTimeoutSweeper) Stop() error {
+	s.mu.Lock()
+	if !s.isRunning {
+		s.mu.Unlock()
+		return fmt.Errorf("approval timeout sweeper is not running")
+	}
+	s.isRunning = false
+	s.mu.Unlock()
+
+	s.logger.Info("Stopping approval timeout sweeper")
+	s.cancel()
+
+	done := make(chan struct{})
+	go func() {
+		s.wg.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		s.logger.Info("Approval timeout sweeper stopped gracefully")
+	case <-time.After(s.config.ShutdownTimeout):
+		s.logger.Warn("Approval timeout sweeper shutdown timed out")
+		return fmt.Errorf("%w after %s: a sweep is still running", ErrShutdownTimeout, s.config.ShutdownTimeout)
+	}
+
+	return nil
+}
+
+// sweepLoop runs Sweep on every tick until the sweeper is stopped
+func (s *ApprovalTimeoutSweeper) sweepLoop() {
+	defer s.wg.Done()
+
+	ticker := time.NewTicker(s.config.SweepInterval)
+	defer ticker.Stop()
+
+	s.Sweep(s.config.Clock.Now())
+
+	for {
+		select {
+		case <-ticker.C:
+			s.Sweep(s.config.Clock.Now())
+		case <-s.ctx.Done():
+			s.logger.Info("Approval timeout sweep loop shutting down")
+			return
+		}
+	}
+}
+
+// Sweep expires every transfer that has been awaiting approval longer than
+// its wallet type's timeout as of now, and returns how many were expired
+func (s *ApprovalTimeoutSweeper) Sweep(now time.Time) int {
+	expired := 0
+
+	for walletType, timeout := range s.config.Timeouts {
+		if timeout <= 0 {
+			continue
+		}
+
+		transfers, err := s.transferRepo.ListUnsubmittedCreatedBefore(walletType, approvalPendingStatuses, now.Add(-timeout), s.config.BatchSize)
+		if err != nil {
+			s.logger.Error("Failed to get transfers past approval timeout",
+				"wallet_type", walletType,
+				"error", err,
+			)
+			continue
+		}
+
+		for _, transfer := range transfers {
+			if err := s.expireTransfer(transfer, timeout); err != nil {
+				s.logger.Error("Failed to expire transfer",
+					"transfer_id", transfer.ID,
+					"error", err,
+				)
+				continue
+			}
+			expired++
+		}
+	}
+
+	s.mu.Lock()
+	s.lastSweep = now
+	s.mu.Unlock()
+
+	if expired > 0 {
+		s.logger.Info("Expired transfers past approval timeout", "count", expired)
+	}
+
+	return expired
+}
+
+// expireTransfer moves a single transfer to expired and notifies its requestor
+func (s *ApprovalTimeoutSweeper) expireTransfer(transfer *models.TransferRequest, timeout time.Duration) error {
+	reason := fmt.Sprintf("approval timeout of %s exceeded with %d of %d approvals received",
+		timeout, transfer.ReceivedApprovals, transfer.RequiredApprovals)
+
+	oldStatus := transfer.Status
+	transfer.Status = models.TransferStatusExpired
+	transfer.StatusReason = &reason
+
+	if err := s.transferRepo.Update(transfer); err != nil {
+		return fmt.Errorf("failed to update transfer: %w", err)
+	}
+
+	s.notificationSvc.SendTransferExpiredNotification(transfer, reason)
+
+	s.logger.Info("Transfer expired awaiting approval",
+		"transfer_id", transfer.ID,
+		"transfer_type", transfer.TransferType,
+		"old_status", oldStatus,
+		"reason", reason,
+	)
+
+	return nil
+}
+
+// HealthCheck returns the health status of the sweeper
+func (s *ApprovalTimeoutSweeper) HealthCheck() map[string]interface{} {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	status := "stopped"
+	if s.isRunning {
+		status = "running"
+	}
+
+	return map[string]interface{}{
+		"status":         status,
+		"last_sweep":     s.lastSweep.UTC(),
+		"sweep_interval": s.config.SweepInterval.String(),
+	}
+}
@@ -0,0 +1,49 @@
+package services
+
+import (
+	"testing"
+	"time"
+
+	"bitgo-wallets-api/internal/models"
+
+	"github.com/google/uuid"
+)
+
+func TestSweepExpiresOnlyTransfersPastTheTimeout(t *testing.T) {
+	now := time.Date(2024, 1, 2, 12, 0, 0, 0, time.UTC)
+	pending := func(age time.Duration) *models.TransferRequest {
+		return &models.TransferRequest{
+			ID:                uuid.New(),
+			TransferType:      models.WalletTypeWarm,
+			Status:            models.TransferStatusPendingApproval,
+			RequiredApprovals: 2,
+			Version:           1,
+			CreatedAt:         now.Add(-age),
+		}
+	}
+	overdue := pending(25 * time.Hour)
+	recent := pending(23 * time.Hour)
+	repo := newMemTransferRepo(overdue, recent)
+	notifier := &expiryRecordingNotifier{}
+
+	config := DefaultApprovalTimeoutConfig()
+	config.Timeouts = map[models.WalletType]time.Duration{models.WalletTypeWarm: 24 * time.Hour}
+	sweeper := NewApprovalTimeoutSweeper(config, testLogger{}, repo, notifier)
+
+	if expired := sweeper.Sweep(now); expired != 1 {
+		t.Fatalf("Sweep() expired %d transfers, want 1", expired)
+	}
+
+	stored, _ := repo.GetByID(overdue.ID)
+	if stored.Status != models.TransferStatusExpired || stored.StatusReason == nil {
+		t.Errorf("overdue transfer: status %s, reason %v; want expired with a reason", stored.Status, stored.StatusReason)
+	}
+	if len(notifier.expired) != 1 {
+		t.Errorf("%d expiry notifications, want 1", len(notifier.expired))
+	}
+
+	stored, _ = repo.GetByID(recent.ID)
+	if stored.Status != models.TransferStatusPendingApproval || stored.Version != 1 {
+		t.Errorf("transfer within the window: status %s, version %d; want it untouched", stored.Status, stored.Version)
+	}
+}
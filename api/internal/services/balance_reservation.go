@@ -0,0 +1,370 @@
+package services
+
+import (
+	"fmt"
+	"math/big"
+	"strings"
+	"sync"
+
+	"bitgo-wallets-api/internal/bitgo"
+	"bitgo-wallets-api/internal/models"
+	"bitgo-wallets-api/internal/repository"
+
+	"github.com/google/uuid"
+)
+
+// maxHydratedTransfers caps how many of a wallet's most recent transfers are read to rebuild
+// its reservations the first time the wallet is seen
+const maxHydratedTransfers = 500
+
+// DefaultEstimatedFees returns the fee held alongside each transfer's amount, per coin, as a
+// decimal amount like transfer amounts. Coins without an entry only reserve the amount.
+func DefaultEstimatedFees() map[string]string {
+	return map[string]string{
+		"btc":  "0.0005",
+		"tbtc": "0.0005",
+		"ltc":  "0.001",
+		"tltc": "0.001",
+		"eth":  "0.005",
+		"teth": "0.005",
+	}
+}
+
+// BalanceReservationError is returned when a wallet's unreserved balance can't cover a
+// transfer. Amounts are in the coin's base units.
+type BalanceReservationError struct {
+	Coin      string
+	Required  *big.Int
+	Spendable *big.Int
+	Reserved  *big.Int
+}
+
+func (e *BalanceReservationError) Error() string {
+	available := new(big.Int).Sub(e.Spendable, e.Reserved)
+	return fmt.Sprintf("amount plus estimated fee (%s) exceeds available balance of %s (%s reserved by in-flight transfers)",
+		formatBaseUnits(e.Coin, e.Required), formatBaseUnits(e.Coin, available), formatBaseUnits(e.Coin, e.Reserved))
+}
+
+// BalanceHold is the amount and estimated fee set aside for one transfer, in base units
+type BalanceHold struct {
+	WalletID   uuid.UUID
+	TransferID uuid.UUID // Nil until the transfer has been created
+	Amount     *big.Int
+}
+
+// BalanceReservations holds amount plus estimated fee for transfers that have passed
+// validation but haven't settled, so two transfers validated at the same time can't both
+// count on the same spendable balance. A hold is dropped once its transfer is broadcast,
+// since BitGo's spendable balance accounts for it from then on, or reaches a terminal state.
+// Holds live in memory; after a restart each wallet's are rebuilt from its in-flight
+// transfers the first time it is seen. Amounts are kept in base units so holds add up exactly.
+type BalanceReservations struct {
+	transferRepo  repository.TransferRequestRepository
+	estimatedFees map[string]string
+	logger        Logger
+
+	mu       sync.Mutex
+	holds    map[uuid.UUID]map[*BalanceHold]struct{}
+	hydrated map[uuid.UUID]bool
+}
+
+// NewBalanceReservations creates an empty reservation ledger
+func NewBalanceReservations(transferRepo repository.TransferRequestRepository, estimatedFees map[string]string, logger Logger) *BalanceReservations {
+	return &BalanceReservations{
+		transferRepo:  transferRepo,
+		estimatedFees: estimatedFees,
+		logger:        logger,
+		holds:         make(map[uuid.UUID]map[*BalanceHold]struct{}),
+		hydrated:      make(map[uuid.UUID]bool),
+	}
+}
+
+// EstimatedFee returns the fee reserved alongside a transfer of the coin, in base units
+func (r *BalanceReservations) EstimatedFee(coin string) *big.Int {
+	fee, ok := r.estimatedFees[strings.ToLower(coin)]
+	if !ok {
+		return new(big.Int)
+	}
+	units, err := toBaseUnits(coin, fee)
+	if err != nil {
+		r.logger.Warn("Ignoring unusable estimated fee",
+			"coin", coin,
+			"fee", fee,
+			"error", err,
+		)
+		return new(big.Int)
+	}
+	return units
+}
+
+// Reserved returns the total held against a wallet by its in-flight transfers, in base units
+func (r *BalanceReservations) Reserved(walletID uuid.UUID) *big.Int {
+	r.prune(walletID)
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.total(walletID)
+}
+
+// Check reports whether a transfer of amount would fit in the wallet's spendable balance
+// after what is already reserved. Both are decimal amounts of the coin. It doesn't hold
+// anything; Reserve does.
+func (r *BalanceReservations) Check(walletID uuid.UUID, coin, amount, spendable string) error {
+	required, available, err := r.requiredAndSpendable(coin, amount, spendable)
+	if err != nil {
+		return err
+	}
+	r.prune(walletID)
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.fits(walletID, coin, required, available)
+}
+
+// Reserve holds amount plus the coin's estimated fee against the wallet if it fits in the
+// spendable balance after existing holds. Checking and holding happen under one lock, so of
+// two transfers that together exceed the balance only one can reserve. The hold must be
+// assigned to the created transfer or released.
+func (r *BalanceReservations) Reserve(walletID uuid.UUID, coin, amount, spendable string) (*BalanceHold, error) {
+	required, available, err := r.requiredAndSpendable(coin, amount, spendable)
+	if err != nil {
+		return nil, err
+	}
+	r.prune(walletID)
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if err := r.fits(walletID, coin, required, available); err != nil {
+		return nil, err
+	}
+
+	hold := &BalanceHold{WalletID: walletID, Amount: required}
+	r.add(hold)
+	return hold, nil
+}
+
+// Hold sets aside amount plus the coin's estimated fee without checking it fits, for
+// transfers whose balance BitGo checks when building them, such as hot transfers. The hold
+// still counts against transfers reserved after it.
+func (r *BalanceReservations) Hold(walletID uuid.UUID, coin, amount string) (*BalanceHold, error) {
+	required, err := r.required(coin, amount)
+	if err != nil {
+		return nil, err
+	}
+	r.prune(walletID)
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	hold := &BalanceHold{WalletID: walletID, Amount: required}
+	r.add(hold)
+	return hold, nil
+}
+
+// Assign ties a hold to the transfer it was reserved for, so it is released when the
+// transfer settles. A hold for the same transfer rebuilt from the database in the meantime
+// is dropped so the transfer isn't counted twice.
+func (r *BalanceReservations) Assign(hold *BalanceHold, transferID uuid.UUID) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for other := range r.holds[hold.WalletID] {
+		if other != hold && other.TransferID == transferID {
+			delete(r.holds[hold.WalletID], other)
+		}
+	}
+	hold.TransferID = transferID
+}
+
+// Release drops a hold, e.g. when creating its transfer failed
+func (r *BalanceReservations) Release(hold *BalanceHold) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if holds, ok := r.holds[hold.WalletID]; ok {
+		delete(holds, hold)
+	}
+}
+
+// required returns amount plus the coin's estimated fee in base units
+func (r *BalanceReservations) required(coin, amount string) (*big.Int, error) {
+	units, err := toBaseUnits(coin, amount)
+	if err != nil {
+		return nil, fmt.Errorf("invalid amount: %w", err)
+	}
+	return units.Add(units, r.EstimatedFee(coin)), nil
+}
+
+// requiredAndSpendable converts a transfer's amount plus estimated fee and the wallet's
+// spendable balance to base units
+func (r *BalanceReservations) requiredAndSpendable(coin, amount, spendable string) (*big.Int, *big.Int, error) {
+	required, err := r.required(coin, amount)
+	if err != nil {
+		return nil, nil, err
+	}
+	available, err := toBaseUnits(coin, spendable)
+	if err != nil {
+		return nil, nil, fmt.Errorf("unable to verify wallet balance: %w", err)
+	}
+	return required, available, nil
+}
+
+func (r *BalanceReservations) fits(walletID uuid.UUID, coin string, required, spendable *big.Int) error {
+	reserved := r.total(walletID)
+	if new(big.Int).Add(required, reserved).Cmp(spendable) > 0 {
+		return &BalanceReservationError{Coin: coin, Required: required, Spendable: spendable, Reserved: reserved}
+	}
+	return nil
+}
+
+func (r *BalanceReservations) add(hold *BalanceHold) {
+	holds, ok := r.holds[hold.WalletID]
+	if !ok {
+		holds = make(map[*BalanceHold]struct{})
+		r.holds[hold.WalletID] = holds
+	}
+	holds[hold] = struct{}{}
+}
+
+func (r *BalanceReservations) total(walletID uuid.UUID) *big.Int {
+	total := new(big.Int)
+	for hold := range r.holds[walletID] {
+		total.Add(total, hold.Amount)
+	}
+	return total
+}
+
+// prune drops the wallet's holds whose transfers no longer hold funds, rebuilding the
+// wallet's holds from the database first if it hasn't been seen since startup. Holds not
+// yet assigned to a transfer belong to a creation in progress and are kept. The database is
+// read without the lock held, in one query for all of the wallet's holds.
+func (r *BalanceReservations) prune(walletID uuid.UUID) {
+	r.mu.Lock()
+	hydrated := r.hydrated[walletID]
+	r.mu.Unlock()
+	if !hydrated {
+		r.hydrate(walletID)
+	}
+
+	r.mu.Lock()
+	var ids []uuid.UUID
+	for hold := range r.holds[walletID] {
+		if hold.TransferID != uuid.Nil {
+			ids = append(ids, hold.TransferID)
+		}
+	}
+	r.mu.Unlock()
+	if len(ids) == 0 {
+		return
+	}
+
+	transfers, err := r.transferRepo.GetByIDs(ids)
+	if err != nil {
+		// Keep the holds; over-reserving is safer than spending the same balance twice
+		r.logger.Warn("Failed to check reserved transfer statuses",
+			"wallet_id", walletID,
+			"error", err,
+		)
+		return
+	}
+	settled := make(map[uuid.UUID]bool, len(ids))
+	for _, id := range ids {
+		settled[id] = true // Missing transfers release their holds
+	}
+	for _, transfer := range transfers {
+		settled[transfer.ID] = !holdsFunds(transfer.Status)
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for hold := range r.holds[walletID] {
+		// Holds assigned since the query was made aren't in settled and are kept
+		if settled[hold.TransferID] {
+			delete(r.holds[walletID], hold)
+		}
+	}
+}
+
+// hydrate reserves the wallet's in-flight transfers recorded before this process started.
+// Transfers that already have a hold, e.g. ones created while an earlier attempt failed,
+// aren't held again.
+func (r *BalanceReservations) hydrate(walletID uuid.UUID) {
+	transfers, err := r.transferRepo.ListByWallet(walletID, repository.TransferListFilter{}, maxHydratedTransfers, 0)
+	if err != nil {
+		// Try again next time rather than treating the wallet as having nothing in flight
+		r.logger.Warn("Failed to load in-flight transfers for balance reservations",
+			"wallet_id", walletID,
+			"error", err,
+		)
+		return
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.hydrated[walletID] {
+		return
+	}
+
+	held := make(map[uuid.UUID]bool)
+	for hold := range r.holds[walletID] {
+		held[hold.TransferID] = true
+	}
+	for _, transfer := range transfers {
+		if !holdsFunds(transfer.Status) || held[transfer.ID] {
+			continue
+		}
+		amount, err := r.required(transfer.Coin, transfer.AmountString)
+		if err != nil {
+			continue
+		}
+		r.add(&BalanceHold{
+			WalletID:   walletID,
+			TransferID: transfer.ID,
+			Amount:     amount,
+		})
+	}
+	r.hydrated[walletID] = true
+}
+
+// holdsFunds reports whether a transfer in the status still needs its balance reserved:
+// it hasn't been broadcast yet and hasn't ended
+func holdsFunds(status models.TransferStatus) bool {
+	if status == models.TransferStatusBroadcast {
+		return false
+	}
+	for _, terminal := range terminalTransferStatuses {
+		if status == terminal {
+			return false
+		}
+	}
+	return true
+}
+
+// toBaseUnits converts a decimal amount of the coin to base units
+func toBaseUnits(coin, amount string) (*big.Int, error) {
+	units, err := bitgo.AmountToBaseUnits(coin, amount)
+	if err != nil {
+		return nil, err
+	}
+	value, ok := new(big.Int).SetString(units, 10)
+	if !ok {
+		return nil, fmt.Errorf("invalid base units %q", units)
+	}
+	return value, nil
+}
+
+// formatBaseUnits formats base units of the coin as a decimal amount, keeping the sign
+func formatBaseUnits(coin string, value *big.Int) string {
+	if value.Sign() == 0 {
+		return "0"
+	}
+	amount, err := bitgo.AmountFromBaseUnits(coin, new(big.Int).Abs(value).String())
+	if err != nil {
+		return value.String() + " base units"
+	}
+	if value.Sign() < 0 {
+		return "-" + amount
+	}
+	return amount
+}
@@ -0,0 +1,87 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+
+	"github.com/google/uuid"
+)
+
+func TestConcurrentTransfersCannotBothReserveTheBalance(t *testing.T) {
+	withoutSimulatedDelay(t)
+	wallet := newTestWarmWallet()
+	wallet.SpendableBalanceString = "1"
+	reservations := NewBalanceReservations(nil, DefaultEstimatedFees(), testLogger{})
+	config := DefaultWarmWalletConfig()
+	config.Reservations = reservations
+	wws, repo := newTestWarmWalletService(wallet, config)
+	reservations.transferRepo = repo
+
+	// Either transfer fits on its own; together they exceed the balance
+	const attempts = 2
+	var wg sync.WaitGroup
+	errs := make([]error, attempts)
+	start := make(chan struct{})
+	for i := 0; i < attempts; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			<-start
+			_, errs[i] = wws.CreateWarmTransferRequest(context.Background(), newTestWarmRequest(wallet, "0.6"), uuid.New())
+		}(i)
+	}
+	close(start)
+	wg.Wait()
+
+	var succeeded int
+	for _, err := range errs {
+		if err == nil {
+			succeeded++
+		}
+	}
+	if succeeded != 1 {
+		t.Fatalf("%d transfers succeeded, want exactly 1: %v", succeeded, errs)
+	}
+	if reserved := reservations.Reserved(wallet.ID).String(); reserved != "60050000" {
+		t.Errorf("reserved = %s satoshis, want one transfer plus its fee", reserved)
+	}
+}
+
+func TestReservationsAddUpExactly(t *testing.T) {
+	reservations := NewBalanceReservations(newMemTransferRepo(), map[string]string{}, testLogger{})
+	walletID := uuid.New()
+
+	// 0.1 + 0.2 is slightly more than 0.3 in floating point; in satoshis it is exact
+	for _, amount := range []string{"0.1", "0.2"} {
+		if _, err := reservations.Reserve(walletID, "btc", amount, "0.3"); err != nil {
+			t.Fatalf("Reserve(%s) error = %v", amount, err)
+		}
+	}
+
+	_, err := reservations.Reserve(walletID, "btc", "0.00000001", "0.3")
+	var reservationErr *BalanceReservationError
+	if !errors.As(err, &reservationErr) {
+		t.Fatalf("Reserve() past the balance error = %v, want a BalanceReservationError", err)
+	}
+	const want = "amount plus estimated fee (0.00000001) exceeds available balance of 0 (0.3 reserved by in-flight transfers)"
+	if err.Error() != want {
+		t.Errorf("error = %q, want %q", err.Error(), want)
+	}
+}
+
+func TestReservationsIncludeEstimatedFee(t *testing.T) {
+	reservations := NewBalanceReservations(newMemTransferRepo(), DefaultEstimatedFees(), testLogger{})
+	walletID := uuid.New()
+
+	if err := reservations.Check(walletID, "btc", "0.9995", "1"); err != nil {
+		t.Errorf("Check() with room for the fee error = %v", err)
+	}
+	if err := reservations.Check(walletID, "btc", "0.99951", "1"); err == nil {
+		t.Error("Check() without room for the fee succeeded")
+	}
+	if _, err := reservations.Hold(walletID, "btc", "not-an-amount"); err == nil {
+		t.Error("Hold() of an invalid amount succeeded")
+	}
+}
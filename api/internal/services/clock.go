@@ -0,0 +1,24 @@
+package services
+
+import "time"
+
+// Clock supplies the current time to time-dependent logic (SLA deadlines, escalation,
+// staleness, sweeps) so it can be driven deterministically instead of by the wall clock
+type Clock interface {
+	Now() time.Time
+}
+
+type realClock struct{}
+
+func (realClock) Now() time.Time { return time.Now() }
+
+// RealClock is the wall clock, used whenever a config doesn't set one
+var RealClock Clock = realClock{}
+
+// clockOrReal returns clock, or RealClock when it is nil
+func clockOrReal(clock Clock) Clock {
+	if clock == nil {
+		return RealClock
+	}
+	return clock
+}
@@ -2,6 +2,7 @@ package services
 
 import (
 	"context"
+	stderrors "errors"
 	"fmt"
 	"regexp"
 	"strings"
@@ -16,32 +17,49 @@ import (
 
 // ColdWalletService handles cold wallet specific operations
 type ColdWalletService struct {
-	bitgoClient     *bitgo.Client
+	bitgoClient     bitgo.BitGoAPI
 	walletRepo      repository.WalletRepository
 	transferRepo    repository.TransferRequestRepository
 	notificationSvc NotificationService
 	logger          Logger
 	config          ColdWalletConfig
+
+	validationMetrics *ValidationMetrics
+	priceOracle       PriceOracle
 }
 
 // ColdWalletConfig contains configuration for cold wallet operations
 type ColdWalletConfig struct {
 	// Validation settings
-	MaxDailyTransferLimit  string   `json:"maxDailyTransferLimit"`
-	MaxSingleTransferLimit string   `json:"maxSingleTransferLimit"`
-	AllowedAddressPatterns []string `json:"allowedAddressPatterns"`
-	RequiredApprovals      int      `json:"requiredApprovals"`
-	ApprovalTimeoutHours   int      `json:"approvalTimeoutHours"`
+	MaxDailyTransferLimit  string        `json:"maxDailyTransferLimit"`
+	MaxSingleTransferLimit string        `json:"maxSingleTransferLimit"`
+	MaxSingleTransferUSD   float64       `json:"maxSingleTransferUSD"` // Optional fiat ceiling; 0 disables it
+	BalanceMaxAge          time.Duration `json:"balanceMaxAge"`        // Refresh cached balances older than this before validating; 0 trusts the cache
+	AllowedAddressPatterns []string      `json:"allowedAddressPatterns"`
+	RequiredApprovals      int           `json:"requiredApprovals"`
+	ApprovalTimeoutHours   int           `json:"approvalTimeoutHours"`
+	MinConfirms            int           `json:"minConfirms"` // Confirmations every input, change included, needs before it can be spent
 
 	// SLA settings
 	InitialResponseSLA time.Duration `json:"initialResponseSLA"`
 	ProcessingSLA      time.Duration `json:"processingSLA"`
 	CompletionSLA      time.Duration `json:"completionSLA"`
 
+	// Per-urgency SLA adjustments; an override replaces the SLA set, otherwise the multiplier scales it
+	UrgencySLAMultipliers map[string]float64    `json:"urgencySLAMultipliers"`
+	UrgencySLAOverrides   map[string]SLATargets `json:"urgencySLAOverrides"`
+
 	// Offline workflow settings
 	ManualReviewThreshold    string        `json:"manualReviewThreshold"`
 	OperatorNotificationList []string      `json:"operatorNotificationList"`
 	EscalationThreshold      time.Duration `json:"escalationThreshold"`
+	HSMSigningTimeout        time.Duration `json:"hsmSigningTimeout"` // How long an HSM session has to return a signature
+
+	// Clock supplies the current time; nil uses the wall clock
+	Clock Clock `json:"-"`
+
+	// Reservations holds balance for in-flight transfers; nil checks each transfer alone
+	Reservations *BalanceReservations `json:"-"`
 }
 
 // DefaultColdWalletConfig returns sensible defaults for cold wallet operations
@@ -52,25 +70,33 @@ func DefaultColdWalletConfig() ColdWalletConfig {
 		AllowedAddressPatterns: []string{},     // Empty = no restrictions
 		RequiredApprovals:      3,              // Minimum 3 approvals
 		ApprovalTimeoutHours:   72,             // 3 days
+		MinConfirms:            1,              // Never spend unconfirmed change
 		InitialResponseSLA:     2 * time.Hour,  // 2 hours for initial response
 		ProcessingSLA:          24 * time.Hour, // 24 hours for processing
 		CompletionSLA:          72 * time.Hour, // 72 hours total completion
 		ManualReviewThreshold:  "1.0",          // Manual review for 1+ BTC
 		EscalationThreshold:    48 * time.Hour, // Escalate after 48 hours
+		HSMSigningTimeout:      4 * time.Hour,  // 4 hours to sign once at the HSM
+		UrgencySLAMultipliers:  DefaultUrgencySLAMultipliers(),
 	}
 }
 
 // ColdTransferRequest represents a cold storage transfer request
 type ColdTransferRequest struct {
-	WalletID         uuid.UUID `json:"walletId"`
-	RecipientAddress string    `json:"recipientAddress"`
-	AmountString     string    `json:"amountString"`
-	Coin             string    `json:"coin"`
-	BusinessPurpose  string    `json:"businessPurpose"`
-	RequestorName    string    `json:"requestorName"`
-	RequestorEmail   string    `json:"requestorEmail"`
-	UrgencyLevel     string    `json:"urgencyLevel"`
-	Memo             string    `json:"memo,omitempty"`
+	WalletID         uuid.UUID             `json:"wallet_id"`
+	RecipientAddress string                `json:"recipient_address"`
+	AmountString     string                `json:"amount_string"`
+	Coin             string                `json:"coin"`
+	BusinessPurpose  string                `json:"business_purpose"`
+	RequestorName    string                `json:"requestor_name"`
+	RequestorEmail   string                `json:"requestor_email"`
+	UrgencyLevel     string                `json:"urgency_level"`
+	Memo             string                `json:"memo,omitempty"`
+	DestinationTag   string                `json:"destination_tag,omitempty"`
+	Comment          string                `json:"comment,omitempty"`
+	Tags             []string              `json:"tags,omitempty"`
+	AllowSelfSend    bool                  `json:"allow_self_send,omitempty"` // Checked by the handler before creation
+	Origin           models.TransferOrigin `json:"origin,omitempty"`          // retry, sweep or consolidate; defaults to api
 }
 
 // ColdTransferValidationError represents validation errors for cold transfers
@@ -100,20 +126,25 @@ const (
 
 // NewColdWalletService creates a new cold wallet service
 func NewColdWalletService(
-	bitgoClient *bitgo.Client,
+	bitgoClient bitgo.BitGoAPI,
 	walletRepo repository.WalletRepository,
 	transferRepo repository.TransferRequestRepository,
 	notificationSvc NotificationService,
 	logger Logger,
 	config ColdWalletConfig,
+	validationMetrics *ValidationMetrics,
+	priceOracle PriceOracle,
 ) *ColdWalletService {
+	config.Clock = clockOrReal(config.Clock)
 	return &ColdWalletService{
-		bitgoClient:     bitgoClient,
-		walletRepo:      walletRepo,
-		transferRepo:    transferRepo,
-		notificationSvc: notificationSvc,
-		logger:          logger,
-		config:          config,
+		bitgoClient:       bitgoClient,
+		walletRepo:        walletRepo,
+		transferRepo:      transferRepo,
+		notificationSvc:   notificationSvc,
+		logger:            logger,
+		config:            config,
+		validationMetrics: validationMetrics,
+		priceOracle:       priceOracle,
 	}
 }
 
@@ -124,9 +155,13 @@ func (cws *ColdWalletService) ValidateColdTransferRequest(ctx context.Context, r
 	// Validate wallet exists and is cold type
 	wallet, err := cws.walletRepo.GetByID(request.WalletID)
 	if err != nil {
+		message := "Failed to look up wallet"
+		if stderrors.Is(err, repository.ErrWalletNotFound) {
+			message = "Wallet not found"
+		}
 		errors = append(errors, ColdTransferValidationError{
 			Field:   "walletId",
-			Message: "Wallet not found",
+			Message: message,
 		})
 		return errors
 	}
@@ -146,8 +181,57 @@ func (cws *ColdWalletService) ValidateColdTransferRequest(ctx context.Context, r
 		})
 	}
 
-	// Validate transfer amounts
-	if err := cws.validateTransferAmount(request.AmountString, request.Coin, wallet); err != nil {
+	// Validate destination memo/tag for coins that require one
+	if err := bitgo.ValidateDestination(request.Coin, request.RecipientAddress, request.Memo, request.DestinationTag); err != nil {
+		field := "memo"
+		if strings.TrimSpace(request.DestinationTag) != "" {
+			field = "destinationTag"
+		}
+		errors = append(errors, ColdTransferValidationError{
+			Field:   field,
+			Message: err.Error(),
+		})
+	}
+
+	// Validate optional comment and tags
+	if err := ValidateTransferComment(request.Comment); err != nil {
+		errors = append(errors, ColdTransferValidationError{
+			Field:   "comment",
+			Message: err.Error(),
+		})
+	}
+	if _, err := NormalizeTransferTags(request.Tags); err != nil {
+		errors = append(errors, ColdTransferValidationError{
+			Field:   "tags",
+			Message: err.Error(),
+		})
+	}
+	if _, err := RequestedTransferOrigin(request.Origin); err != nil {
+		errors = append(errors, ColdTransferValidationError{
+			Field:   "origin",
+			Message: err.Error(),
+		})
+	}
+
+	// Validate transfer amounts, against a fresh balance if the cached one is stale
+	if _, err := refreshStaleBalance(ctx, cws.bitgoClient, cws.walletRepo, wallet, cws.config.BalanceMaxAge, cws.config.Clock.Now()); err != nil {
+		cws.logger.Warn("Failed to refresh stale wallet balance",
+			"wallet_id", wallet.ID,
+			"error", err,
+		)
+		errors = append(errors, ColdTransferValidationError{
+			Field:   "amountString",
+			Message: "unable to verify wallet balance",
+		})
+	} else if err := cws.validateTransferAmount(request.AmountString, request.Coin, wallet); err != nil {
+		errors = append(errors, ColdTransferValidationError{
+			Field:   "amountString",
+			Message: err.Error(),
+		})
+	}
+
+	// Validate the USD value against the fiat limit, if one is configured
+	if err := validateUSDLimit(ctx, cws.priceOracle, cws.config.MaxSingleTransferUSD, request.AmountString, request.Coin); err != nil {
 		errors = append(errors, ColdTransferValidationError{
 			Field:   "amountString",
 			Message: err.Error(),
@@ -194,9 +278,20 @@ func (cws *ColdWalletService) CreateColdTransferRequest(ctx context.Context, req
 	// Validate the request
 	validationErrors := cws.ValidateColdTransferRequest(ctx, request)
 	if len(validationErrors) > 0 {
+		cws.recordValidationFailures(request, validationErrors)
 		return nil, fmt.Errorf("validation failed: %v", validationErrors)
 	}
 
+	// Tags and origin were validated above, so normalizing them can't fail here
+	tags, _ := NormalizeTransferTags(request.Tags)
+	origin, _ := RequestedTransferOrigin(request.Origin)
+
+	// Validation found the wallet, so its override can be read here
+	wallet, err := cws.walletRepo.GetByID(request.WalletID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get wallet: %w", err)
+	}
+
 	// Create transfer request with cold-specific settings
 	transferRequest := &models.TransferRequest{
 		WalletID:          request.WalletID,
@@ -206,15 +301,33 @@ func (cws *ColdWalletService) CreateColdTransferRequest(ctx context.Context, req
 		Coin:              request.Coin,
 		TransferType:      models.WalletTypeCold,
 		Status:            models.TransferStatusSubmitted,
-		RequiredApprovals: cws.config.RequiredApprovals,
+		RequiredApprovals: applyApprovalsOverride(wallet, cws.config.RequiredApprovals),
 		ReceivedApprovals: 0,
 		Memo:              &request.Memo,
+		DestinationTag:    optionalString(request.DestinationTag),
+		Comment:           optionalString(request.Comment),
+		Tags:              tags,
+		Origin:            origin,
+	}
+
+	// Record the SLA deadlines that apply to this transfer's urgency
+	setSLAMetadata(transferRequest, computeSLADeadlines(request.UrgencyLevel, cws.slaTargets(request.UrgencyLevel), cws.config.Clock.Now()))
+
+	hold, err := cws.reserveBalance(wallet, request.AmountString, request.Coin)
+	if err != nil {
+		return nil, err
 	}
 
 	// Create the transfer request in the database
 	if err := cws.transferRepo.Create(transferRequest); err != nil {
+		if hold != nil {
+			cws.config.Reservations.Release(hold)
+		}
 		return nil, fmt.Errorf("failed to create cold transfer request: %w", err)
 	}
+	if hold != nil {
+		cws.config.Reservations.Assign(hold, transferRequest.ID)
+	}
 
 	// Send notifications to operators
 	cws.notifyColdTransferCreated(transferRequest, request)
@@ -253,19 +366,22 @@ func (cws *ColdWalletService) GetColdTransfersSLAStatus(ctx context.Context) (ma
 		}
 	}
 
-	now := time.Now()
+	now := cws.config.Clock.Now()
 	slaBreached := 0
 	atRisk := 0
 	escalated := 0
+	breachedByUrgency := make(map[string]int)
 
 	for _, transfer := range coldTransfers {
 		// Calculate time since creation
 		elapsed := now.Sub(transfer.CreatedAt)
 
-		// Check SLA status
-		if elapsed > cws.config.CompletionSLA {
+		// Check SLA status against the deadlines for the transfer's urgency
+		deadlines := transferSLADeadlines(transfer, cws.slaTargets(""))
+		if now.After(deadlines.CompletionBy) {
 			slaBreached++
-		} else if elapsed > cws.config.CompletionSLA/2 {
+			breachedByUrgency[deadlines.UrgencyLevel]++
+		} else if now.After(transfer.CreatedAt.Add(deadlines.CompletionBy.Sub(transfer.CreatedAt) / 2)) {
 			atRisk++
 		}
 
@@ -280,49 +396,111 @@ func (cws *ColdWalletService) GetColdTransfersSLAStatus(ctx context.Context) (ma
 		"slaBreached":        slaBreached,
 		"atRisk":             atRisk,
 		"escalated":          escalated,
+		"breachedByUrgency":  breachedByUrgency,
 		"config": map[string]interface{}{
-			"initialResponseSLA": cws.config.InitialResponseSLA.String(),
-			"processingSLA":      cws.config.ProcessingSLA.String(),
-			"completionSLA":      cws.config.CompletionSLA.String(),
+			"initialResponseSLA":    cws.config.InitialResponseSLA.String(),
+			"processingSLA":         cws.config.ProcessingSLA.String(),
+			"completionSLA":         cws.config.CompletionSLA.String(),
+			"urgencySLAMultipliers": cws.config.UrgencySLAMultipliers,
+			"urgencySLAOverrides":   cws.config.UrgencySLAOverrides,
 		},
 	}, nil
 }
 
-// UpdateOfflineWorkflowState updates the offline workflow state for a cold transfer
-func (cws *ColdWalletService) UpdateOfflineWorkflowState(ctx context.Context, transferID uuid.UUID, newState OfflineWorkflowState, notes string) error {
+// UpdateOfflineWorkflowState updates the offline workflow state for a cold transfer.
+// Entering awaiting_hsm requires hsm details identifying the operator and HSM doing the signing.
+// ready_to_execute can't be set here: only RecordOfflineSignature reaches it, with the signature.
+func (cws *ColdWalletService) UpdateOfflineWorkflowState(ctx context.Context, transferID uuid.UUID, newState OfflineWorkflowState, notes string, hsm *HSMSessionRequest) error {
 	transfer, err := cws.transferRepo.GetByID(transferID)
 	if err != nil {
 		return fmt.Errorf("failed to get transfer: %w", err)
 	}
 
+	if transfer == nil {
+		return fmt.Errorf("transfer not found")
+	}
+
 	if transfer.TransferType != models.WalletTypeCold {
 		return fmt.Errorf("transfer is not a cold storage transfer")
 	}
 
-	// Update metadata with new offline state
-	// In a real implementation, this would be stored in a separate metadata table
+	// Repeating the current state is a no-op so operators can safely retry
+	currentState := offlineWorkflowState(transfer)
+	if newState == currentState {
+		cws.logger.Debug("Cold transfer already in offline state",
+			"transfer_id", transferID,
+			"state", newState,
+		)
+		return nil
+	}
+
+	if err := validateOfflineTransition(currentState, newState); err != nil {
+		return err
+	}
+	if newState == OfflineStateReadyToExecute {
+		return fmt.Errorf("%w: %s is reached by recording the HSM signature", ErrInvalidOfflineTransition, newState)
+	}
 
-	// Update corresponding transfer status
-	switch newState {
-	case OfflineStateSecurityReview, OfflineStateComplianceCheck:
-		transfer.Status = models.TransferStatusPendingApproval
-	case OfflineStateOperatorQueued, OfflineStateManualProcessing:
-		transfer.Status = models.TransferStatusApproved
-	case OfflineStateAwaitingHSM, OfflineStateReadyToExecute:
-		transfer.Status = models.TransferStatusSigned
-	case OfflineStateExecuted:
-		transfer.Status = models.TransferStatusBroadcast
-	case OfflineStateEscalated:
-		// Keep current status but mark as escalated
-		// In a real implementation, this would be stored in metadata
+	now := cws.config.Clock.Now()
+	var build *bitgo.BuildTransferResponse
+	if newState == OfflineStateAwaitingHSM {
+		if hsm == nil || strings.TrimSpace(hsm.Operator) == "" || strings.TrimSpace(hsm.HSMID) == "" {
+			return fmt.Errorf("operator and hsm_id are required to start an HSM signing session")
+		}
+		build, err = cws.buildForSigning(ctx, transfer)
+		if err != nil {
+			return err
+		}
 	}
 
-	if err := cws.transferRepo.Update(transfer); err != nil {
+	// Reapply the transition to the latest row on a version conflict, as long as another
+	// update hasn't moved the workflow on meanwhile
+	latestState := currentState
+	updated, err := repository.UpdateWithRetry(cws.transferRepo, transfer, func(transfer *models.TransferRequest) bool {
+		latestState = offlineWorkflowState(transfer)
+		if latestState != currentState {
+			return false
+		}
+
+		if build != nil {
+			recordBuildFee(transfer, build)
+			startHSMSession(transfer, *hsm, unsignedTxHex(build), now, cws.config.HSMSigningTimeout)
+		}
+		recordOfflineTransition(transfer, OfflineStateTransition{
+			From:  currentState,
+			To:    newState,
+			Notes: notes,
+			At:    now,
+		})
+
+		// Update corresponding transfer status
+		switch newState {
+		case OfflineStateSecurityReview, OfflineStateComplianceCheck:
+			transfer.Status = models.TransferStatusPendingApproval
+		case OfflineStateOperatorQueued, OfflineStateManualProcessing:
+			transfer.Status = models.TransferStatusApproved
+		case OfflineStateAwaitingHSM, OfflineStateReadyToExecute:
+			transfer.Status = models.TransferStatusSigned
+		case OfflineStateExecuted:
+			transfer.Status = models.TransferStatusBroadcast
+		case OfflineStateEscalated:
+			// Keep current status; the escalation is recorded in the offline state metadata
+		}
+		return true
+	})
+	if err != nil {
 		return fmt.Errorf("failed to update transfer: %w", err)
 	}
+	if !updated {
+		if latestState == newState {
+			return nil // Another request made the same transition
+		}
+		return fmt.Errorf("%w: transfer moved to %s while updating", ErrInvalidOfflineTransition, latestState)
+	}
 
 	cws.logger.Info("Cold transfer offline state updated",
 		"transfer_id", transferID,
+		"previous_state", currentState,
 		"new_state", newState,
 		"notes", notes,
 	)
@@ -330,8 +508,146 @@ func (cws *ColdWalletService) UpdateOfflineWorkflowState(ctx context.Context, tr
 	return nil
 }
 
+// RecordOfflineSignature stores the signed transaction produced at the HSM and moves the
+// transfer to ready_to_execute. Re-sending the same signature is a no-op.
+func (cws *ColdWalletService) RecordOfflineSignature(ctx context.Context, transferID uuid.UUID, signedTxHex, signedBy string) (*models.TransferRequest, error) {
+	signedTxHex, err := normalizeSignedTxHex(signedTxHex)
+	if err != nil {
+		return nil, err
+	}
+
+	transfer, err := cws.transferRepo.GetByID(transferID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get transfer: %w", err)
+	}
+
+	if transfer == nil {
+		return nil, fmt.Errorf("transfer not found")
+	}
+
+	if transfer.TransferType != models.WalletTypeCold {
+		return nil, fmt.Errorf("transfer is not a cold storage transfer")
+	}
+
+	session, ok := hsmSession(transfer)
+	currentState := offlineWorkflowState(transfer)
+
+	// Replaying the signature that moved the transfer on is a no-op
+	if currentState == OfflineStateReadyToExecute && ok && session.SignedTxHex == signedTxHex {
+		return transfer, nil
+	}
+
+	if currentState != OfflineStateAwaitingHSM || !ok {
+		return nil, fmt.Errorf("%w: transfer is %s, not awaiting an HSM signature", ErrInvalidOfflineTransition, currentState)
+	}
+
+	now := cws.config.Clock.Now()
+	if !session.Deadline.IsZero() && now.After(session.Deadline) {
+		return nil, fmt.Errorf("%w: deadline was %s", ErrHSMSessionExpired, session.Deadline.Format(time.RFC3339))
+	}
+
+	if strings.TrimSpace(signedBy) == "" {
+		signedBy = session.Operator
+	}
+
+	// Reapply the signature to the latest row on a version conflict, as long as the transfer
+	// is still awaiting it
+	updated, err := repository.UpdateWithRetry(cws.transferRepo, transfer, func(transfer *models.TransferRequest) bool {
+		if offlineWorkflowState(transfer) != OfflineStateAwaitingHSM {
+			return false
+		}
+		recordHSMSignature(transfer, signedTxHex, signedBy, now)
+		recordOfflineTransition(transfer, OfflineStateTransition{
+			From:  OfflineStateAwaitingHSM,
+			To:    OfflineStateReadyToExecute,
+			Notes: fmt.Sprintf("Signed on HSM %s", session.HSMID),
+			At:    now,
+		})
+		transfer.Status = models.TransferStatusSigned
+		return true
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to update transfer: %w", err)
+	}
+	if !updated {
+		if latest, ok := hsmSession(transfer); ok && latest.SignedTxHex == signedTxHex {
+			return transfer, nil // Another request recorded the same signature
+		}
+		return nil, fmt.Errorf("%w: transfer moved to %s while recording the signature", ErrInvalidOfflineTransition, offlineWorkflowState(transfer))
+	}
+
+	cws.logger.Info("Cold transfer offline signature recorded",
+		"transfer_id", transferID,
+		"hsm_id", session.HSMID,
+		"signed_by", signedBy,
+	)
+
+	return transfer, nil
+}
+
+// TransferSLADeadlines returns the SLA deadlines that apply to a cold transfer
+func (cws *ColdWalletService) TransferSLADeadlines(transfer *models.TransferRequest) SLADeadlines {
+	return transferSLADeadlines(transfer, cws.slaTargets(""))
+}
+
+// slaTargets returns the SLA targets for a cold transfer of the given urgency
+func (cws *ColdWalletService) slaTargets(urgency string) SLATargets {
+	base := SLATargets{
+		InitialResponse: cws.config.InitialResponseSLA,
+		Processing:      cws.config.ProcessingSLA,
+		Completion:      cws.config.CompletionSLA,
+	}
+	return effectiveSLATargets(base, urgency, cws.config.UrgencySLAMultipliers, cws.config.UrgencySLAOverrides)
+}
+
+// buildForSigning builds the transaction the HSM will sign with BitGo, under the cold build
+// policy
+func (cws *ColdWalletService) buildForSigning(ctx context.Context, transfer *models.TransferRequest) (*bitgo.BuildTransferResponse, error) {
+	wallet, err := cws.walletRepo.GetByID(transfer.WalletID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get wallet: %w", err)
+	}
+
+	response, err := buildTransfer(ctx, cws.bitgoClient, wallet, transfer, cws.ApplyBuildPolicy)
+	if err != nil {
+		return nil, fmt.Errorf("failed to build transfer with BitGo: %w", err)
+	}
+	return response, nil
+}
+
+// ApplyBuildPolicy sets the cold confirmation requirements on a BitGo build. Change outputs are
+// held to the same minimum so a cold wallet never spends unconfirmed change.
+func (cws *ColdWalletService) ApplyBuildPolicy(req *bitgo.BuildTransferRequest) {
+	if cws.config.MinConfirms <= 0 {
+		return
+	}
+	req.MinConfirms = cws.config.MinConfirms
+	req.EnforceMinConfirmsForChange = true
+}
+
+// ApprovalTimeout returns how long a cold transfer may wait for approvals before it expires
+func (cws *ColdWalletService) ApprovalTimeout() time.Duration {
+	return time.Duration(cws.config.ApprovalTimeoutHours) * time.Hour
+}
+
 // Helper methods
 
+// recordValidationFailures logs each rejected field and counts it for analytics
+func (cws *ColdWalletService) recordValidationFailures(request ColdTransferRequest, validationErrors []ColdTransferValidationError) {
+	for _, validationErr := range validationErrors {
+		cws.logger.Warn("Cold transfer validation failed",
+			"transfer_type", models.WalletTypeCold,
+			"field", validationErr.Field,
+			"reason", validationErr.Message,
+			"wallet_id", request.WalletID,
+			"coin", request.Coin,
+		)
+		if cws.validationMetrics != nil {
+			cws.validationMetrics.Record(models.WalletTypeCold, validationErr.Field)
+		}
+	}
+}
+
 func (cws *ColdWalletService) validateRecipientAddress(address, coin string) error {
 	if strings.TrimSpace(address) == "" {
 		return fmt.Errorf("recipient address is required")
@@ -352,18 +668,7 @@ func (cws *ColdWalletService) validateRecipientAddress(address, coin string) err
 	}
 
 	// Basic format validation (simplified)
-	switch strings.ToLower(coin) {
-	case "btc", "tbtc":
-		if len(address) < 26 || len(address) > 62 {
-			return fmt.Errorf("invalid Bitcoin address format")
-		}
-	case "eth":
-		if len(address) != 42 || !strings.HasPrefix(address, "0x") {
-			return fmt.Errorf("invalid Ethereum address format")
-		}
-	}
-
-	return nil
+	return validateAddressFormat(address, coin)
 }
 
 func (cws *ColdWalletService) validateTransferAmount(amountStr, coin string, wallet *models.Wallet) error {
@@ -393,9 +698,31 @@ func (cws *ColdWalletService) validateTransferAmount(amountStr, coin string, wal
 		return fmt.Errorf("amount exceeds spendable balance of %s %s", wallet.SpendableBalanceString, coin)
 	}
 
+	// Leave room for transfers already in flight from this wallet
+	if cws.config.Reservations != nil {
+		if err := cws.config.Reservations.Check(wallet.ID, coin, amountStr, wallet.SpendableBalanceString); err != nil {
+			return err
+		}
+	}
+
 	return nil
 }
 
+// reserveBalance holds the transfer's amount and estimated fee against the wallet, failing
+// if a transfer created since validation has taken the room. A nil hold means reservations
+// aren't configured.
+func (cws *ColdWalletService) reserveBalance(wallet *models.Wallet, amountStr, coin string) (*BalanceHold, error) {
+	if cws.config.Reservations == nil {
+		return nil, nil
+	}
+
+	hold, err := cws.config.Reservations.Reserve(wallet.ID, coin, amountStr, wallet.SpendableBalanceString)
+	if err != nil {
+		return nil, fmt.Errorf("validation failed: %v", []ColdTransferValidationError{{Field: "amountString", Message: err.Error()}})
+	}
+	return hold, nil
+}
+
 func (cws *ColdWalletService) requiresManualReview(amountStr string) bool {
 	amount, err := parseAmount(amountStr)
 	if err != nil {
@@ -440,3 +767,12 @@ func parseAmount(amountStr string) (float64, error) {
 	_, err := fmt.Sscanf(amountStr, "%f", &amount)
 	return amount, err
 }
+
+// applyApprovalsOverride raises required to the wallet's approvals override when the
+// wallet has a higher one. Overrides only tighten; they never lower the service default.
+func applyApprovalsOverride(wallet *models.Wallet, required int) int {
+	if wallet != nil && wallet.RequiredApprovalsOverride != nil && *wallet.RequiredApprovalsOverride > required {
+		return *wallet.RequiredApprovalsOverride
+	}
+	return required
+}
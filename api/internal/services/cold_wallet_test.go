@@ -0,0 +1,108 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"bitgo-wallets-api/internal/bitgo"
+	"bitgo-wallets-api/internal/models"
+	"bitgo-wallets-api/internal/repository"
+
+	"github.com/google/uuid"
+)
+
+const testXRPAddress = "rEb8TK3gBgk5auZkwc6sHnwrGVJH8DuaLh"
+
+// failingWalletRepo fails every wallet lookup; other methods aren't used
+type failingWalletRepo struct {
+	repository.WalletRepository
+}
+
+func (failingWalletRepo) GetByID(uuid.UUID) (*models.Wallet, error) {
+	return nil, errors.New("connection refused")
+}
+
+func TestValidationReportsMissingWallet(t *testing.T) {
+	client := bitgo.NewSimulatedClient(bitgo.SimulationConfig{}, testLogger{})
+	tests := []struct {
+		name        string
+		walletRepo  repository.WalletRepository
+		wantMessage string
+	}{
+		{name: "not found", walletRepo: newMemWalletRepo(), wantMessage: "Wallet not found"},
+		{name: "lookup failure", walletRepo: failingWalletRepo{}, wantMessage: "Failed to look up wallet"},
+	}
+
+	for _, tt := range tests {
+		t.Run("cold "+tt.name, func(t *testing.T) {
+			cws := NewColdWalletService(client, tt.walletRepo, newMemTransferRepo(), nopNotifier{}, testLogger{}, DefaultColdWalletConfig(), nil, nil)
+			errs := cws.ValidateColdTransferRequest(context.Background(), ColdTransferRequest{
+				WalletID:         uuid.New(),
+				RecipientAddress: testTrustedAddress,
+				AmountString:     "1",
+				Coin:             "btc",
+			})
+			if len(errs) != 1 || errs[0].Field != "walletId" || errs[0].Message != tt.wantMessage {
+				t.Errorf("ValidateColdTransferRequest() = %+v, want one walletId error %q", errs, tt.wantMessage)
+			}
+		})
+
+		t.Run("warm "+tt.name, func(t *testing.T) {
+			wws := NewWarmWalletService(client, tt.walletRepo, newMemTransferRepo(), nopNotifier{}, testLogger{}, DefaultWarmWalletConfig(), nil, nil, nil)
+			errs := wws.ValidateWarmTransferRequest(context.Background(), newTestWarmRequest(&models.Wallet{ID: uuid.New(), Coin: "btc"}, "1"))
+			if len(errs) != 1 || errs[0].Field != "walletId" || errs[0].Message != tt.wantMessage {
+				t.Errorf("ValidateWarmTransferRequest() = %+v, want one walletId error %q", errs, tt.wantMessage)
+			}
+		})
+	}
+}
+
+func TestValidationRequiresXRPDestinationTag(t *testing.T) {
+	client := bitgo.NewSimulatedClient(bitgo.SimulationConfig{}, testLogger{})
+	tests := []struct {
+		name    string
+		tag     string
+		wantErr bool
+	}{
+		{name: "without tag", wantErr: true},
+		{name: "numeric tag", tag: "123456"},
+	}
+
+	for _, tt := range tests {
+		t.Run("cold "+tt.name, func(t *testing.T) {
+			wallet := &models.Wallet{ID: uuid.New(), Coin: "xrp", WalletType: models.WalletTypeCold, IsActive: true}
+			cws := NewColdWalletService(client, newMemWalletRepo(wallet), newMemTransferRepo(), nopNotifier{}, testLogger{}, DefaultColdWalletConfig(), nil, nil)
+			errs := cws.ValidateColdTransferRequest(context.Background(), ColdTransferRequest{
+				WalletID:         wallet.ID,
+				RecipientAddress: testXRPAddress,
+				AmountString:     "1",
+				Coin:             "xrp",
+				DestinationTag:   tt.tag,
+			})
+			got := false
+			for _, err := range errs {
+				got = got || err.Field == "memo" || err.Field == "destinationTag"
+			}
+			if got != tt.wantErr {
+				t.Errorf("destination tag error = %v, want %v: %+v", got, tt.wantErr, errs)
+			}
+		})
+
+		t.Run("warm "+tt.name, func(t *testing.T) {
+			wallet := &models.Wallet{ID: uuid.New(), Coin: "xrp", WalletType: models.WalletTypeWarm, IsActive: true}
+			wws := NewWarmWalletService(client, newMemWalletRepo(wallet), newMemTransferRepo(), nopNotifier{}, testLogger{}, DefaultWarmWalletConfig(), nil, nil, nil)
+			request := newTestWarmRequest(wallet, "1")
+			request.RecipientAddress = testXRPAddress
+			request.DestinationTag = tt.tag
+			errs := wws.ValidateWarmTransferRequest(context.Background(), request)
+			got := false
+			for _, err := range errs {
+				got = got || err.Field == "memo" || err.Field == "destinationTag"
+			}
+			if got != tt.wantErr {
+				t.Errorf("destination tag error = %v, want %v: %+v", got, tt.wantErr, errs)
+			}
+		})
+	}
+}
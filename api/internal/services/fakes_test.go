@@ -0,0 +1,301 @@
+package services
+
+import (
+	"sort"
+	"sync"
+	"time"
+
+	"bitgo-wallets-api/internal/models"
+	"bitgo-wallets-api/internal/repository"
+
+	"github.com/google/uuid"
+)
+
+// memTransferRepo keeps transfers in memory, with Update's version check; methods a test
+// doesn't override aren't used
+type memTransferRepo struct {
+	repository.TransferRequestRepository
+
+	mu        sync.Mutex
+	transfers map[uuid.UUID]*models.TransferRequest
+}
+
+func newMemTransferRepo(transfers ...*models.TransferRequest) *memTransferRepo {
+	repo := &memTransferRepo{transfers: make(map[uuid.UUID]*models.TransferRequest)}
+	for _, transfer := range transfers {
+		if transfer.ID == uuid.Nil {
+			transfer.ID = uuid.New()
+		}
+		stored := *transfer
+		repo.transfers[transfer.ID] = &stored
+	}
+	return repo
+}
+
+func (r *memTransferRepo) Create(transfer *models.TransferRequest) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	transfer.ID = uuid.New()
+	if transfer.Origin == "" {
+		transfer.Origin = models.TransferOriginAPI
+	}
+	transfer.Version = 1
+	transfer.CreatedAt = time.Now()
+	transfer.UpdatedAt = transfer.CreatedAt
+	stored := *transfer
+	r.transfers[transfer.ID] = &stored
+	return nil
+}
+
+func (r *memTransferRepo) GetByID(id uuid.UUID) (*models.TransferRequest, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	stored, ok := r.transfers[id]
+	if !ok {
+		return nil, nil
+	}
+	copied := *stored
+	return &copied, nil
+}
+
+func (r *memTransferRepo) Update(transfer *models.TransferRequest) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	stored, ok := r.transfers[transfer.ID]
+	if !ok {
+		return repository.ErrTransferRequestNotFound
+	}
+	if stored.Version != transfer.Version {
+		return repository.ErrTransferVersionConflict
+	}
+	transfer.Version++
+	transfer.UpdatedAt = time.Now()
+	updated := *transfer
+	if updated.Origin == "" {
+		updated.Origin = stored.Origin
+	}
+	r.transfers[transfer.ID] = &updated
+	return nil
+}
+
+// GetByIDs returns copies of the stored transfers with the given IDs
+func (r *memTransferRepo) GetByIDs(ids []uuid.UUID) ([]*models.TransferRequest, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	var transfers []*models.TransferRequest
+	for _, id := range ids {
+		if stored, ok := r.transfers[id]; ok {
+			copied := *stored
+			transfers = append(transfers, &copied)
+		}
+	}
+	return transfers, nil
+}
+
+// ListByWallet returns the wallet's transfers newest first, ignoring the filter
+func (r *memTransferRepo) ListByWallet(walletID uuid.UUID, filter repository.TransferListFilter, limit, offset int) ([]*models.TransferRequest, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	var list []*models.TransferRequest
+	for _, stored := range r.transfers {
+		if stored.WalletID == walletID {
+			copied := *stored
+			list = append(list, &copied)
+		}
+	}
+	sort.Slice(list, func(i, j int) bool { return list[i].CreatedAt.After(list[j].CreatedAt) })
+	if offset >= len(list) {
+		return nil, nil
+	}
+	list = list[offset:]
+	if limit > 0 && limit < len(list) {
+		list = list[:limit]
+	}
+	return list, nil
+}
+
+func (r *memTransferRepo) GetTransfersByStatuses(statuses []models.TransferStatus, limit int) ([]*models.TransferRequest, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	var transfers []*models.TransferRequest
+	for _, stored := range r.transfers {
+		if statusIn(stored.Status, statuses) {
+			copied := *stored
+			transfers = append(transfers, &copied)
+		}
+	}
+	sort.Slice(transfers, func(i, j int) bool { return transfers[i].CreatedAt.After(transfers[j].CreatedAt) })
+	if len(transfers) > limit {
+		transfers = transfers[:limit]
+	}
+	return transfers, nil
+}
+
+func (r *memTransferRepo) UpdateStatus(id uuid.UUID, status models.TransferStatus) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if stored, ok := r.transfers[id]; ok {
+		stored.Status = status
+		stored.Version++
+	}
+	return nil
+}
+
+func (r *memTransferRepo) RecordPollFailure(id uuid.UUID, failures int, pollError string, nextPollAt time.Time, needsAttention bool) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if stored, ok := r.transfers[id]; ok {
+		stored.PollFailures = failures
+		stored.PollError = &pollError
+		stored.NextPollAt = &nextPollAt
+		if needsAttention && stored.NeedsAttentionAt == nil {
+			now := time.Now()
+			stored.NeedsAttentionAt = &now
+		}
+	}
+	return nil
+}
+
+func (r *memTransferRepo) ListBitGoReferences(walletID uuid.UUID) (map[string]bool, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	references := make(map[string]bool)
+	for _, stored := range r.transfers {
+		if stored.WalletID != walletID {
+			continue
+		}
+		for _, reference := range []*string{stored.BitgoTransferID, stored.BitgoTxid, stored.TransactionHash} {
+			if reference != nil && *reference != "" {
+				references[*reference] = true
+			}
+		}
+	}
+	return references, nil
+}
+
+func (r *memTransferRepo) ListUnsubmittedCreatedBefore(transferType models.WalletType, statuses []models.TransferStatus, before time.Time, limit int) ([]*models.TransferRequest, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	var transfers []*models.TransferRequest
+	for _, stored := range r.transfers {
+		if stored.TransferType != transferType || stored.SubmittedAt != nil || !stored.CreatedAt.Before(before) || !statusIn(stored.Status, statuses) {
+			continue
+		}
+		copied := *stored
+		transfers = append(transfers, &copied)
+	}
+	sort.Slice(transfers, func(i, j int) bool { return transfers[i].CreatedAt.Before(transfers[j].CreatedAt) })
+	if len(transfers) > limit {
+		transfers = transfers[:limit]
+	}
+	return transfers, nil
+}
+
+func (r *memTransferRepo) ListByTypeAndStatusesAfter(transferType models.WalletType, statuses []models.TransferStatus, after *repository.TransferCursor, limit int) ([]*models.TransferRequest, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	var transfers []*models.TransferRequest
+	for _, stored := range r.transfers {
+		if stored.TransferType != transferType || !statusIn(stored.Status, statuses) || (after != nil && !transferAfterCursor(stored, after)) {
+			continue
+		}
+		copied := *stored
+		transfers = append(transfers, &copied)
+	}
+	sort.Slice(transfers, func(i, j int) bool {
+		if !transfers[i].UpdatedAt.Equal(transfers[j].UpdatedAt) {
+			return transfers[i].UpdatedAt.Before(transfers[j].UpdatedAt)
+		}
+		return transfers[i].ID.String() < transfers[j].ID.String()
+	})
+	if len(transfers) > limit {
+		transfers = transfers[:limit]
+	}
+	return transfers, nil
+}
+
+func (r *memTransferRepo) ArchiveInactiveSince(statuses []models.TransferStatus, before time.Time, limit int) (int64, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	var inactive []*models.TransferRequest
+	for _, stored := range r.transfers {
+		if stored.ArchivedAt == nil && stored.UpdatedAt.Before(before) && statusIn(stored.Status, statuses) {
+			inactive = append(inactive, stored)
+		}
+	}
+	sort.Slice(inactive, func(i, j int) bool { return inactive[i].UpdatedAt.Before(inactive[j].UpdatedAt) })
+	if len(inactive) > limit {
+		inactive = inactive[:limit]
+	}
+	now := time.Now()
+	for _, stored := range inactive {
+		stored.ArchivedAt = &now
+	}
+	return int64(len(inactive)), nil
+}
+
+// memWalletRepo serves wallets from memory; methods a test doesn't override aren't used
+type memWalletRepo struct {
+	repository.WalletRepository
+	wallets map[uuid.UUID]*models.Wallet
+}
+
+func newMemWalletRepo(wallets ...*models.Wallet) *memWalletRepo {
+	repo := &memWalletRepo{wallets: make(map[uuid.UUID]*models.Wallet)}
+	for _, wallet := range wallets {
+		if wallet.ID == uuid.Nil {
+			wallet.ID = uuid.New()
+		}
+		repo.wallets[wallet.ID] = wallet
+	}
+	return repo
+}
+
+func (r *memWalletRepo) GetByID(id uuid.UUID) (*models.Wallet, error) {
+	wallet, ok := r.wallets[id]
+	if !ok {
+		return nil, repository.ErrWalletNotFound
+	}
+	copied := *wallet
+	return &copied, nil
+}
+
+func (r *memWalletRepo) Update(wallet *models.Wallet) error {
+	if _, ok := r.wallets[wallet.ID]; !ok {
+		return repository.ErrWalletNotFound
+	}
+	copied := *wallet
+	r.wallets[wallet.ID] = &copied
+	return nil
+}
+
+// nopNotifier drops notifications
+type nopNotifier struct {
+	NotificationService
+}
+
+func (nopNotifier) SendTransferStatusNotification(*models.TransferRequest, models.TransferStatus, models.TransferStatus) {
+}
+func (nopNotifier) SendTransferCreatedNotification(*models.TransferRequest)         {}
+func (nopNotifier) SendTransferCompletedNotification(*models.TransferRequest)       {}
+func (nopNotifier) SendTransferFailedNotification(*models.TransferRequest, string)  {}
+func (nopNotifier) SendTransferExpiredNotification(*models.TransferRequest, string) {}
+func (nopNotifier) SendWalletFrozenNotification(*models.Wallet, string)             {}
+
+// testClock is a settable clock
+type testClock struct {
+	mu  sync.Mutex
+	now time.Time
+}
+
+func (c *testClock) Now() time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.now
+}
+
+func (c *testClock) Set(now time.Time) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.now = now
+}
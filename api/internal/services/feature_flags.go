@@ -0,0 +1,54 @@
+package services
+
+import "sync"
+
+// FeatureFlags toggles optional transfer-service behaviors
+type FeatureFlags struct {
+	AutoProcessing bool `json:"auto_processing"` // Warm transfers may be processed without manual review
+	RiskScoring    bool `json:"risk_scoring"`    // Warm transfers are risk assessed; when off unscored ones go to manual review
+	VelocityChecks bool `json:"velocity_checks"` // Risk assessment includes transfer velocity
+	Escalation     bool `json:"escalation"`      // Overdue warm transfers are counted as escalated
+
+	// NotificationChannels replaces the notification service's default channels when set
+	NotificationChannels []NotificationChannel `json:"notification_channels"`
+}
+
+// DefaultFeatureFlags enables every behavior and keeps the default notification channels
+func DefaultFeatureFlags() FeatureFlags {
+	return FeatureFlags{
+		AutoProcessing: true,
+		RiskScoring:    true,
+		VelocityChecks: true,
+		Escalation:     true,
+	}
+}
+
+// FeatureFlagStore holds the current flags and lets them be changed while the service runs
+type FeatureFlagStore struct {
+	mu    sync.RWMutex
+	flags FeatureFlags
+}
+
+// NewFeatureFlagStore creates a store starting with flags
+func NewFeatureFlagStore(flags FeatureFlags) *FeatureFlagStore {
+	return &FeatureFlagStore{flags: flags}
+}
+
+// Get returns a copy of the current flags
+func (s *FeatureFlagStore) Get() FeatureFlags {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	flags := s.flags
+	flags.NotificationChannels = append([]NotificationChannel(nil), s.flags.NotificationChannels...)
+	return flags
+}
+
+// Set replaces the current flags
+func (s *FeatureFlagStore) Set(flags FeatureFlags) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	flags.NotificationChannels = append([]NotificationChannel(nil), flags.NotificationChannels...)
+	s.flags = flags
+}
@@ -2,12 +2,16 @@ package services
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
 	"sync"
+	"sync/atomic"
 	"time"
 
 	"bitgo-wallets-api/internal/bitgo"
 	"bitgo-wallets-api/internal/models"
+	"bitgo-wallets-api/internal/repository"
+	"bitgo-wallets-api/internal/webhooksig"
 
 	"github.com/google/uuid"
 )
@@ -19,6 +23,12 @@ type NotificationService interface {
 	SendTransferCreatedNotification(transfer *models.TransferRequest)
 	SendTransferCompletedNotification(transfer *models.TransferRequest)
 	SendTransferFailedNotification(transfer *models.TransferRequest, reason string)
+	SendTransferExpiredNotification(transfer *models.TransferRequest, reason string)
+	SendWalletFrozenNotification(wallet *models.Wallet, reason string)
+
+	// Shutdown delivers what is already queued, then stops the workers. It returns
+	// ErrShutdownTimeout, wrapping the context's error, if the queue could not be drained in time.
+	Shutdown(ctx context.Context) error
 }
 
 // NotificationChannel represents different notification delivery methods
@@ -42,6 +52,8 @@ const (
 	NotificationTypeTransferCompleted    NotificationType = "transfer_completed"
 	NotificationTypeTransferFailed       NotificationType = "transfer_failed"
 	NotificationTypeApprovalExpiring     NotificationType = "approval_expiring"
+	NotificationTypeApprovalExpired      NotificationType = "approval_expired"
+	NotificationTypeWalletFrozen         NotificationType = "wallet_frozen"
 )
 
 // NotificationPriority represents the urgency of a notification
@@ -54,6 +66,15 @@ const (
 	NotificationPriorityCritical NotificationPriority = "critical"
 )
 
+// QueueOverflowStrategy controls what happens when the notification queue is full
+type QueueOverflowStrategy string
+
+const (
+	QueueOverflowBlock      QueueOverflowStrategy = "block"       // Wait up to OverflowBlockTimeout for space
+	QueueOverflowDropOldest QueueOverflowStrategy = "drop_oldest" // Evict the oldest queued notification
+	QueueOverflowDropNew    QueueOverflowStrategy = "drop_new"    // Discard the incoming notification
+)
+
 // Notification represents a notification message
 type Notification struct {
 	ID          string                 `json:"id"`
@@ -70,19 +91,53 @@ type Notification struct {
 	FailedAt    *time.Time             `json:"failedAt,omitempty"`
 	RetryCount  int                    `json:"retryCount"`
 	MaxRetries  int                    `json:"maxRetries"`
+	Sequence    int64                  `json:"sequence,omitempty"` // Per-transfer delivery order, starting at 1
+	LastError   string                 `json:"lastError,omitempty"`
 }
 
 // NotificationConfig configures the notification service
 type NotificationConfig struct {
 	DefaultChannels []NotificationChannel `json:"defaultChannels"`
 	WebhookURL      string                `json:"webhookUrl,omitempty"`
+	WebhookSecret   string                `json:"-"` // Signs webhook bodies; empty sends them unsigned
 	EmailConfig     *EmailConfig          `json:"emailConfig,omitempty"`
+	SMSConfig       *SMSConfig            `json:"smsConfig,omitempty"`
 	SlackConfig     *SlackConfig          `json:"slackConfig,omitempty"`
 	RetryAttempts   int                   `json:"retryAttempts"`
 	RetryDelay      time.Duration         `json:"retryDelay"`
 	BatchSize       int                   `json:"batchSize"`
-	QueueSize       int                   `json:"queueSize"`
+	QueueSize       int                   `json:"queueSize"` // Capacity of each priority bucket
 	Workers         int                   `json:"workers"`
+
+	// Workers drain higher priorities first; every FairnessInterval-th pick starts from the
+	// lowest priority instead so low-priority notifications aren't starved. Zero disables it.
+	FairnessInterval int `json:"fairnessInterval"`
+
+	// Queue overflow handling. Critical notifications are never dropped: they block for up
+	// to OverflowBlockTimeout and are then delivered inline on the caller's goroutine.
+	OverflowStrategy     QueueOverflowStrategy `json:"overflowStrategy"`
+	OverflowBlockTimeout time.Duration         `json:"overflowBlockTimeout"`
+
+	// SendTimeout is how long callers wait on a send before moving on without it
+	SendTimeout time.Duration `json:"sendTimeout"`
+
+	// DedupWindow suppresses a notification identical to one sent this recently (same type,
+	// recipients, transfer and status). Zero disables deduplication.
+	DedupWindow time.Duration `json:"dedupWindow"`
+
+	// Flags, when set, can override DefaultChannels at runtime
+	Flags *FeatureFlagStore `json:"-"`
+
+	// Approvers, when set, resolves who pending-approval notifications go to
+	Approvers ApproverDirectory `json:"-"`
+
+	// Templates overrides the built-in title and message templates per type and channel
+	Templates NotificationTemplates `json:"templates,omitempty"`
+}
+
+// ApproverDirectory resolves the users who may approve a wallet's transfers
+type ApproverDirectory interface {
+	ListApprovers(walletID uuid.UUID) ([]*models.WalletApprover, error)
 }
 
 // EmailConfig contains email notification configuration
@@ -96,6 +151,11 @@ type EmailConfig struct {
 	UseSTARTTLS bool   `json:"useStartTLS"`
 }
 
+// SMSConfig contains SMS notification configuration
+type SMSConfig struct {
+	FromNumber string `json:"fromNumber"`
+}
+
 // SlackConfig contains Slack notification configuration
 type SlackConfig struct {
 	WebhookURL string `json:"webhookUrl"`
@@ -113,6 +173,14 @@ func DefaultNotificationConfig() NotificationConfig {
 		BatchSize:       10,
 		QueueSize:       1000,
 		Workers:         2,
+
+		FairnessInterval: 10,
+
+		OverflowStrategy:     QueueOverflowDropNew,
+		OverflowBlockTimeout: 2 * time.Second,
+
+		SendTimeout: 500 * time.Millisecond,
+		DedupWindow: 5 * time.Minute,
 	}
 }
 
@@ -120,29 +188,72 @@ func DefaultNotificationConfig() NotificationConfig {
 type notificationService struct {
 	config    NotificationConfig
 	logger    Logger
-	queue     chan *Notification
+	repo      repository.NotificationRepository
+	queues    map[NotificationPriority]chan *Notification
+	templates *notificationTemplateRegistry
+	dedup     *notificationDeduper
 	ctx       context.Context
 	cancel    context.CancelFunc
 	wg        sync.WaitGroup
 	isRunning bool
 	mu        sync.RWMutex
 
+	// processing counts notifications a worker has taken but not finished, so Shutdown
+	// knows when the queues are truly drained
+	processing atomic.Int64
+
 	// In-memory storage for demo (in production, use database)
 	notifications   map[string]*Notification
 	notificationsMu sync.RWMutex
+
+	// Per-transfer ordering: the head of each transfer's line is queued or retrying, and the
+	// rest wait for it to be delivered or given up on
+	ordered    map[string][]*Notification
+	sequences  map[string]int64
+	orderingMu sync.Mutex
+}
+
+// notificationPriorities lists the priority buckets in the order workers drain them
+var notificationPriorities = []NotificationPriority{
+	NotificationPriorityCritical,
+	NotificationPriorityHigh,
+	NotificationPriorityNormal,
+	NotificationPriorityLow,
 }
 
-// NewNotificationService creates a new notification service
-func NewNotificationService(config NotificationConfig, logger Logger) NotificationService {
+// fairnessPriorities is the drain order used on fairness picks
+var fairnessPriorities = []NotificationPriority{
+	NotificationPriorityLow,
+	NotificationPriorityNormal,
+	NotificationPriorityHigh,
+	NotificationPriorityCritical,
+}
+
+func newPriorityQueues(size int) map[NotificationPriority]chan *Notification {
+	queues := make(map[NotificationPriority]chan *Notification, len(notificationPriorities))
+	for _, priority := range notificationPriorities {
+		queues[priority] = make(chan *Notification, size)
+	}
+	return queues
+}
+
+// NewNotificationService creates a new notification service. When repo is set, every
+// notification's delivery state is also persisted so it can be looked up by transfer.
+func NewNotificationService(config NotificationConfig, logger Logger, repo repository.NotificationRepository) NotificationService {
 	ctx, cancel := context.WithCancel(context.Background())
 
 	service := &notificationService{
 		config:        config,
 		logger:        logger,
-		queue:         make(chan *Notification, config.QueueSize),
+		repo:          repo,
+		queues:        newPriorityQueues(config.QueueSize),
+		templates:     newNotificationTemplateRegistry(config.Templates, logger),
+		dedup:         newNotificationDeduper(config.DedupWindow),
 		ctx:           ctx,
 		cancel:        cancel,
 		notifications: make(map[string]*Notification),
+		ordered:       make(map[string][]*Notification),
+		sequences:     make(map[string]int64),
 	}
 
 	// Start worker goroutines
@@ -185,35 +296,123 @@ func (ns *notificationService) stop() {
 
 	ns.logger.Info("Stopping notification service")
 
-	close(ns.queue)
 	ns.cancel()
 	ns.wg.Wait()
 
 	ns.logger.Info("Notification service stopped")
 }
 
-// worker processes notifications from the queue
-func (ns *notificationService) worker(workerID int) {
-	defer ns.wg.Done()
+// shutdownPollInterval is how often Shutdown checks whether the queues have drained
+const shutdownPollInterval = 50 * time.Millisecond
 
-	ns.logger.Debug("Starting notification worker", "worker_id", workerID)
+// Shutdown waits for queued and in-progress notifications to be delivered, then stops the
+// workers. Retries still waiting out their delay are not waited for; their state is
+// already stored as retrying.
+func (ns *notificationService) Shutdown(ctx context.Context) error {
+	ticker := time.NewTicker(shutdownPollInterval)
+	defer ticker.Stop()
 
+	// A worker may have taken a notification but not yet counted it, so the queues must
+	// look drained on two checks in a row
+	quiet := 0
 	for {
+		if ns.drained() {
+			quiet++
+		} else {
+			quiet = 0
+		}
+		if quiet == 2 {
+			break
+		}
+
 		select {
-		case notification, ok := <-ns.queue:
-			if !ok {
-				ns.logger.Debug("Notification queue closed, worker stopping", "worker_id", workerID)
-				return
-			}
-			ns.processNotification(notification)
+		case <-ctx.Done():
+			ns.logger.Warn("Notification queue not drained before shutdown deadline",
+				"queued", ns.queued(),
+				"processing", ns.processing.Load(),
+			)
+			queued, processing := ns.queued(), ns.processing.Load()
+			ns.stop()
+			return fmt.Errorf("%w: %d notifications queued and %d being delivered: %w", ErrShutdownTimeout, queued, processing, ctx.Err())
+		case <-ticker.C:
+		}
+	}
+
+	ns.stop()
+	return nil
+}
+
+// queued returns how many notifications are waiting across all priority buckets
+func (ns *notificationService) queued() int {
+	total := 0
+	for _, queue := range ns.queues {
+		total += len(queue)
+	}
+	return total
+}
+
+func (ns *notificationService) drained() bool {
+	return ns.queued() == 0 && ns.processing.Load() == 0
+}
 
-		case <-ns.ctx.Done():
+// worker processes notifications from the priority queues
+func (ns *notificationService) worker(workerID int) {
+	defer ns.wg.Done()
+
+	ns.logger.Debug("Starting notification worker", "worker_id", workerID)
+
+	for pick := 1; ; pick++ {
+		notification, ok := ns.nextNotification(pick)
+		if !ok {
 			ns.logger.Debug("Worker context cancelled", "worker_id", workerID)
 			return
 		}
+		ns.processing.Add(1)
+		ns.processNotification(notification)
+		ns.processing.Add(-1)
 	}
 }
 
+// nextNotification blocks until a notification is available, taking it from the highest
+// priority bucket that has one. Every FairnessInterval-th pick prefers the lowest instead.
+// It returns false once the service is stopped.
+func (ns *notificationService) nextNotification(pick int) (*Notification, bool) {
+	order := notificationPriorities
+	if ns.config.FairnessInterval > 0 && pick%ns.config.FairnessInterval == 0 {
+		order = fairnessPriorities
+	}
+
+	for _, priority := range order {
+		select {
+		case notification := <-ns.queues[priority]:
+			return notification, true
+		default:
+		}
+	}
+
+	// Everything is empty; take whatever arrives first
+	select {
+	case notification := <-ns.queues[NotificationPriorityCritical]:
+		return notification, true
+	case notification := <-ns.queues[NotificationPriorityHigh]:
+		return notification, true
+	case notification := <-ns.queues[NotificationPriorityNormal]:
+		return notification, true
+	case notification := <-ns.queues[NotificationPriorityLow]:
+		return notification, true
+	case <-ns.ctx.Done():
+		return nil, false
+	}
+}
+
+// queueFor returns the bucket for a notification; unknown priorities are treated as normal
+func (ns *notificationService) queueFor(notification *Notification) chan *Notification {
+	if queue, ok := ns.queues[notification.Priority]; ok {
+		return queue
+	}
+	return ns.queues[NotificationPriorityNormal]
+}
+
 // processNotification handles delivery of a single notification
 func (ns *notificationService) processNotification(notification *Notification) {
 	ns.logger.Info("Processing notification",
@@ -251,6 +450,28 @@ func (ns *notificationService) processNotification(notification *Notification) {
 				success = true
 			}
 
+		case NotificationChannelEmail:
+			if err := ns.sendEmail(notification); err != nil {
+				ns.logger.Error("Failed to send email notification",
+					"notification_id", notification.ID,
+					"error", err,
+				)
+				lastError = err
+			} else {
+				success = true
+			}
+
+		case NotificationChannelSMS:
+			if err := ns.sendSMS(notification); err != nil {
+				ns.logger.Error("Failed to send SMS notification",
+					"notification_id", notification.ID,
+					"error", err,
+				)
+				lastError = err
+			} else {
+				success = true
+			}
+
 		case NotificationChannelSlack:
 			if err := ns.sendSlack(notification); err != nil {
 				ns.logger.Error("Failed to send Slack notification",
@@ -272,15 +493,24 @@ func (ns *notificationService) processNotification(notification *Notification) {
 
 	// Update notification status
 	now := time.Now()
+	status := models.NotificationDeliveryDelivered
 	if success {
 		notification.DeliveredAt = &now
+		notification.LastError = ""
 		ns.logger.Info("Notification delivered successfully",
 			"id", notification.ID,
 			"type", notification.Type,
 		)
 	} else {
+		if lastError != nil {
+			notification.LastError = lastError.Error()
+		} else {
+			notification.LastError = "no supported channel"
+		}
 		notification.RetryCount++
+		status = models.NotificationDeliveryRetrying
 		if notification.RetryCount >= notification.MaxRetries {
+			status = models.NotificationDeliveryFailed
 			notification.FailedAt = &now
 			ns.logger.Error("Notification failed after max retries",
 				"id", notification.ID,
@@ -295,7 +525,11 @@ func (ns *notificationService) processNotification(notification *Notification) {
 	}
 
 	// Store notification (in production, save to database)
-	ns.storeNotification(notification)
+	ns.storeNotification(notification, status)
+
+	if notification.DeliveredAt != nil || notification.FailedAt != nil {
+		ns.releaseNext(notification)
+	}
 }
 
 // scheduleRetry schedules a notification for retry
@@ -311,7 +545,7 @@ func (ns *notificationService) scheduleRetry(notification *Notification) {
 	time.Sleep(delay)
 
 	select {
-	case ns.queue <- notification:
+	case ns.queueFor(notification) <- notification:
 		// Queued for retry
 	case <-ns.ctx.Done():
 		// Service is shutting down
@@ -320,14 +554,79 @@ func (ns *notificationService) scheduleRetry(notification *Notification) {
 		ns.logger.Error("Failed to queue notification retry, queue full",
 			"id", notification.ID,
 		)
+		ns.dropNotification(notification)
 	}
 }
 
-// storeNotification stores the notification (in-memory for demo)
-func (ns *notificationService) storeNotification(notification *Notification) {
+// storeNotification stores the notification in memory and, when a repository is configured,
+// persists its delivery state. Persistence failures are logged but never block delivery.
+func (ns *notificationService) storeNotification(notification *Notification, status models.NotificationDeliveryStatus) {
 	ns.notificationsMu.Lock()
-	defer ns.notificationsMu.Unlock()
 	ns.notifications[notification.ID] = notification
+	ns.notificationsMu.Unlock()
+
+	if ns.repo == nil {
+		return
+	}
+
+	record, err := newNotificationRecord(notification, status)
+	if err == nil {
+		err = ns.repo.Save(record)
+	}
+	if err != nil {
+		ns.logger.Warn("Failed to persist notification",
+			"id", notification.ID,
+			"status", status,
+			"error", err,
+		)
+	}
+}
+
+// newNotificationRecord converts a notification to its stored form
+func newNotificationRecord(notification *Notification, status models.NotificationDeliveryStatus) (*models.NotificationRecord, error) {
+	id, err := uuid.Parse(notification.ID)
+	if err != nil {
+		return nil, fmt.Errorf("invalid notification ID: %w", err)
+	}
+
+	record := &models.NotificationRecord{
+		ID:          id,
+		Type:        string(notification.Type),
+		Priority:    string(notification.Priority),
+		Title:       notification.Title,
+		Message:     notification.Message,
+		Recipients:  notification.Recipients,
+		Status:      status,
+		RetryCount:  notification.RetryCount,
+		CreatedAt:   notification.CreatedAt,
+		DeliveredAt: notification.DeliveredAt,
+		FailedAt:    notification.FailedAt,
+	}
+	for _, channel := range notification.Channels {
+		record.Channels = append(record.Channels, string(channel))
+	}
+	if key := orderingKey(notification); key != "" {
+		if transferID, err := uuid.Parse(key); err == nil {
+			record.TransferID = &transferID
+		}
+	}
+	if notification.Sequence > 0 {
+		sequence := notification.Sequence
+		record.Sequence = &sequence
+	}
+	if notification.LastError != "" {
+		lastError := notification.LastError
+		record.LastError = &lastError
+	}
+
+	return record, nil
+}
+
+// dropNotification records a notification that was discarded without delivery and lets the
+// next one for its transfer go ahead
+func (ns *notificationService) dropNotification(notification *Notification) {
+	ns.storeNotification(notification, models.NotificationDeliveryDropped)
+	ns.releaseNext(notification)
 }
 
 // sendWebhook sends notification via webhook
@@ -336,10 +635,32 @@ func (ns *notificationService) sendWebhook(notification *Notification) error {
 		return fmt.Errorf("webhook URL not configured")
 	}
 
-	// In a real implementation, make HTTP POST request to webhook URL
+	title, message := ns.contentFor(notification, NotificationChannelWebhook)
+	body, err := json.Marshal(map[string]interface{}{
+		"id":        notification.ID,
+		"type":      notification.Type,
+		"title":     title,
+		"message":   message,
+		"data":      notification.Data,
+		"createdAt": notification.CreatedAt,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to encode webhook body: %w", err)
+	}
+
+	// Receivers verify the body against this header with webhooksig.Verify
+	headers := map[string]string{"Content-Type": "application/json"}
+	if ns.config.WebhookSecret != "" {
+		headers[webhooksig.Header] = webhooksig.Sign(body, ns.config.WebhookSecret)
+	}
+
+	// In a real implementation, make HTTP POST request to webhook URL with body and headers
 	ns.logger.Info("Sending webhook notification",
+		"title", title,
 		"url", ns.config.WebhookURL,
 		"notification_id", notification.ID,
+		"headers", headers,
+		"bytes", len(body),
 	)
 
 	return nil // Simulated success
@@ -347,7 +668,9 @@ func (ns *notificationService) sendWebhook(notification *Notification) error {
 
 // sendInApp stores notification for in-app display
 func (ns *notificationService) sendInApp(notification *Notification) error {
+	title, _ := ns.contentFor(notification, NotificationChannelInApp)
 	ns.logger.Info("Storing in-app notification",
+		"title", title,
 		"notification_id", notification.ID,
 		"recipients", notification.Recipients,
 	)
@@ -356,13 +679,52 @@ func (ns *notificationService) sendInApp(notification *Notification) error {
 	return nil // Simulated success
 }
 
+// sendEmail sends notification by email
+func (ns *notificationService) sendEmail(notification *Notification) error {
+	if ns.config.EmailConfig == nil || ns.config.EmailConfig.SMTPHost == "" {
+		return fmt.Errorf("email SMTP host not configured")
+	}
+
+	subject, body := ns.contentFor(notification, NotificationChannelEmail)
+	ns.logger.Info("Sending email notification",
+		"subject", subject,
+		"from", ns.config.EmailConfig.FromAddress,
+		"recipients", notification.Recipients,
+		"notification_id", notification.ID,
+		"bytes", len(body),
+	)
+
+	// In a real implementation, send through the SMTP server
+	return nil // Simulated success
+}
+
+// sendSMS sends notification by SMS. Texts are short, so only the message is sent.
+func (ns *notificationService) sendSMS(notification *Notification) error {
+	if ns.config.SMSConfig == nil || ns.config.SMSConfig.FromNumber == "" {
+		return fmt.Errorf("SMS sender number not configured")
+	}
+
+	_, message := ns.contentFor(notification, NotificationChannelSMS)
+	ns.logger.Info("Sending SMS notification",
+		"from", ns.config.SMSConfig.FromNumber,
+		"recipients", notification.Recipients,
+		"notification_id", notification.ID,
+		"length", len(message),
+	)
+
+	// In a real implementation, send through the SMS provider
+	return nil // Simulated success
+}
+
 // sendSlack sends notification to Slack
 func (ns *notificationService) sendSlack(notification *Notification) error {
 	if ns.config.SlackConfig == nil || ns.config.SlackConfig.WebhookURL == "" {
 		return fmt.Errorf("Slack webhook URL not configured")
 	}
 
+	title, _ := ns.contentFor(notification, NotificationChannelSlack)
 	ns.logger.Info("Sending Slack notification",
+		"title", title,
 		"webhook_url", ns.config.SlackConfig.WebhookURL,
 		"notification_id", notification.ID,
 	)
@@ -373,6 +735,15 @@ func (ns *notificationService) sendSlack(notification *Notification) error {
 
 // enqueueNotification adds a notification to the processing queue
 func (ns *notificationService) enqueueNotification(notification *Notification) {
+	if ns.dedup.isDuplicate(notification) {
+		ns.logger.Debug("Suppressing duplicate notification",
+			"type", notification.Type,
+			"transfer_id", orderingKey(notification),
+			"window", ns.config.DedupWindow,
+		)
+		return
+	}
+
 	// Set defaults
 	if notification.ID == "" {
 		notification.ID = uuid.New().String()
@@ -384,30 +755,203 @@ func (ns *notificationService) enqueueNotification(notification *Notification) {
 		notification.MaxRetries = ns.config.RetryAttempts
 	}
 	if len(notification.Channels) == 0 {
-		notification.Channels = ns.config.DefaultChannels
+		notification.Channels = ns.defaultChannels()
+	}
+
+	// Record it before it can be dispatched so a fast delivery isn't overwritten as pending
+	ns.storeNotification(notification, models.NotificationDeliveryPending)
+	if ns.holdForOrdering(notification) {
+		return
+	}
+	ns.dispatch(notification)
+}
+
+// defaultChannels returns the channels from the feature flags if any are set, otherwise the
+// configured defaults
+func (ns *notificationService) defaultChannels() []NotificationChannel {
+	if ns.config.Flags != nil {
+		if channels := ns.config.Flags.Get().NotificationChannels; len(channels) > 0 {
+			return channels
+		}
+	}
+	return ns.config.DefaultChannels
+}
+
+// orderingKey returns the transfer a notification belongs to, or "" for notifications that
+// don't need to be ordered
+func orderingKey(notification *Notification) string {
+	transferID, _ := notification.Data["transfer_id"].(string)
+	return transferID
+}
+
+// holdForOrdering assigns the notification its per-transfer sequence number and reports
+// whether it has to wait behind an earlier notification for the same transfer
+func (ns *notificationService) holdForOrdering(notification *Notification) bool {
+	key := orderingKey(notification)
+	if key == "" {
+		return false
+	}
+
+	ns.orderingMu.Lock()
+	defer ns.orderingMu.Unlock()
+
+	ns.sequences[key]++
+	notification.Sequence = ns.sequences[key]
+
+	line := ns.ordered[key]
+	ns.ordered[key] = append(line, notification)
+	if len(line) == 0 {
+		return false
 	}
 
+	ns.logger.Debug("Holding notification behind earlier one for the same transfer",
+		"id", notification.ID,
+		"transfer_id", key,
+		"sequence", notification.Sequence,
+		"waiting_on", line[0].ID,
+	)
+	return true
+}
+
+// releaseNext is called once a notification is delivered, gives up, or is dropped, and
+// dispatches the next notification held for the same transfer
+func (ns *notificationService) releaseNext(notification *Notification) {
+	key := orderingKey(notification)
+	if key == "" {
+		return
+	}
+
+	ns.orderingMu.Lock()
+	line := ns.ordered[key]
+	if len(line) == 0 || line[0] != notification {
+		ns.orderingMu.Unlock()
+		return
+	}
+	line = line[1:]
+
+	var next *Notification
+	if len(line) == 0 {
+		delete(ns.ordered, key)
+		delete(ns.sequences, key)
+	} else {
+		ns.ordered[key] = line
+		next = line[0]
+	}
+	ns.orderingMu.Unlock()
+
+	if next != nil {
+		ns.dispatch(next)
+	}
+}
+
+// dispatch puts a notification on the queue, applying the overflow strategy when it's full
+func (ns *notificationService) dispatch(notification *Notification) {
 	select {
-	case ns.queue <- notification:
-		ns.logger.Debug("Notification queued",
+	case ns.queueFor(notification) <- notification:
+		ns.logNotificationQueued(notification)
+		return
+	default:
+	}
+
+	// Queue is full
+	if notification.Priority == NotificationPriorityCritical {
+		ns.enqueueCritical(notification)
+		return
+	}
+
+	switch ns.config.OverflowStrategy {
+	case QueueOverflowBlock:
+		if ns.enqueueWithTimeout(notification) {
+			ns.logNotificationQueued(notification)
+			return
+		}
+		ns.logger.Error("Notification queue full after waiting, dropping notification",
 			"id", notification.ID,
 			"type", notification.Type,
+			"timeout", ns.config.OverflowBlockTimeout,
 		)
+		ns.dropNotification(notification)
+
+	case QueueOverflowDropOldest:
+		ns.enqueueDroppingOldest(notification)
+
 	default:
 		ns.logger.Error("Notification queue full, dropping notification",
 			"id", notification.ID,
 			"type", notification.Type,
 		)
+		ns.dropNotification(notification)
+	}
+}
+
+// enqueueCritical waits for queue space and, if none frees up in time, delivers the
+// notification inline so critical alerts are never lost
+func (ns *notificationService) enqueueCritical(notification *Notification) {
+	if ns.enqueueWithTimeout(notification) {
+		ns.logNotificationQueued(notification)
+		return
+	}
+
+	ns.logger.Warn("Notification queue full, delivering critical notification inline",
+		"id", notification.ID,
+		"type", notification.Type,
+	)
+	ns.processNotification(notification)
+}
+
+// enqueueWithTimeout waits up to OverflowBlockTimeout for space in the queue
+func (ns *notificationService) enqueueWithTimeout(notification *Notification) bool {
+	timer := time.NewTimer(ns.config.OverflowBlockTimeout)
+	defer timer.Stop()
+
+	select {
+	case ns.queueFor(notification) <- notification:
+		return true
+	case <-timer.C:
+		return false
+	case <-ns.ctx.Done():
+		return false
+	}
+}
+
+// enqueueDroppingOldest evicts the oldest notifications in the same priority bucket until the
+// new one fits. Critical notifications never get here, so nothing critical is evicted.
+func (ns *notificationService) enqueueDroppingOldest(notification *Notification) {
+	queue := ns.queueFor(notification)
+	for {
+		select {
+		case queue <- notification:
+			ns.logNotificationQueued(notification)
+			return
+		default:
+		}
+
+		select {
+		case oldest := <-queue:
+			ns.logger.Error("Notification queue full, dropping oldest notification",
+				"id", oldest.ID,
+				"type", oldest.Type,
+				"replaced_by", notification.ID,
+			)
+			ns.dropNotification(oldest)
+		default:
+			// A worker drained the queue in the meantime; try again
+		}
 	}
 }
 
+func (ns *notificationService) logNotificationQueued(notification *Notification) {
+	ns.logger.Debug("Notification queued",
+		"id", notification.ID,
+		"type", notification.Type,
+	)
+}
+
 // SendTransferStatusNotification sends notification when transfer status changes
 func (ns *notificationService) SendTransferStatusNotification(transfer *models.TransferRequest, oldStatus, newStatus models.TransferStatus) {
 	notification := &Notification{
 		Type:       NotificationTypeTransferStatusChange,
 		Priority:   ns.getStatusChangePriority(oldStatus, newStatus),
-		Title:      fmt.Sprintf("Transfer Status Updated"),
-		Message:    fmt.Sprintf("Transfer %s status changed from %s to %s", transfer.ID, oldStatus, newStatus),
 		Recipients: []string{transfer.RequestedByUserID.String()},
 		Data: map[string]interface{}{
 			"transfer_id": transfer.ID.String(),
@@ -419,6 +963,7 @@ func (ns *notificationService) SendTransferStatusNotification(transfer *models.T
 		},
 	}
 
+	ns.applyTemplate(notification)
 	ns.enqueueNotification(notification)
 }
 
@@ -427,9 +972,7 @@ func (ns *notificationService) SendPendingApprovalNotification(transfer *models.
 	notification := &Notification{
 		Type:       NotificationTypePendingApproval,
 		Priority:   NotificationPriorityHigh,
-		Title:      fmt.Sprintf("Transfer Requires Approval"),
-		Message:    fmt.Sprintf("Transfer %s requires %d approval(s). %d received, %d pending.", transfer.ID, approval.RequiredApprovals, approval.ReceivedApprovals, approval.PendingApprovals),
-		Recipients: []string{transfer.RequestedByUserID.String()}, // In real app, send to approvers
+		Recipients: ns.approvalRecipients(transfer),
 		Data: map[string]interface{}{
 			"transfer_id":        transfer.ID.String(),
 			"approval_id":        approval.ID,
@@ -441,16 +984,50 @@ func (ns *notificationService) SendPendingApprovalNotification(transfer *models.
 		},
 	}
 
+	ns.applyTemplate(notification)
 	ns.enqueueNotification(notification)
 }
 
+// operatorsRecipient addresses the operations team when a notification has no better recipient
+const operatorsRecipient = "operators"
+
+// approvalRecipients returns the emails of the wallet's approvers, falling back to the
+// requestor when no directory is configured or the wallet has no approvers
+func (ns *notificationService) approvalRecipients(transfer *models.TransferRequest) []string {
+	return ns.walletApproverEmails(transfer.WalletID, []string{transfer.RequestedByUserID.String()})
+}
+
+// walletApproverEmails returns the emails of the wallet's approvers, or fallback when no
+// directory is configured or the wallet has no approvers
+func (ns *notificationService) walletApproverEmails(walletID uuid.UUID, fallback []string) []string {
+	if ns.config.Approvers == nil {
+		return fallback
+	}
+
+	approvers, err := ns.config.Approvers.ListApprovers(walletID)
+	if err != nil {
+		ns.logger.Warn("Failed to resolve wallet approvers",
+			"wallet_id", walletID,
+			"error", err,
+		)
+		return fallback
+	}
+	if len(approvers) == 0 {
+		return fallback
+	}
+
+	recipients := make([]string, len(approvers))
+	for i, approver := range approvers {
+		recipients[i] = approver.Email
+	}
+	return recipients
+}
+
 // SendTransferCreatedNotification sends notification when transfer is created
 func (ns *notificationService) SendTransferCreatedNotification(transfer *models.TransferRequest) {
 	notification := &Notification{
 		Type:       NotificationTypeTransferCreated,
 		Priority:   NotificationPriorityNormal,
-		Title:      fmt.Sprintf("Transfer Created"),
-		Message:    fmt.Sprintf("Transfer of %s %s to %s has been created", transfer.AmountString, transfer.Coin, transfer.RecipientAddress),
 		Recipients: []string{transfer.RequestedByUserID.String()},
 		Data: map[string]interface{}{
 			"transfer_id": transfer.ID.String(),
@@ -460,6 +1037,7 @@ func (ns *notificationService) SendTransferCreatedNotification(transfer *models.
 		},
 	}
 
+	ns.applyTemplate(notification)
 	ns.enqueueNotification(notification)
 }
 
@@ -468,8 +1046,6 @@ func (ns *notificationService) SendTransferCompletedNotification(transfer *model
 	notification := &Notification{
 		Type:       NotificationTypeTransferCompleted,
 		Priority:   NotificationPriorityNormal,
-		Title:      fmt.Sprintf("Transfer Completed"),
-		Message:    fmt.Sprintf("Transfer of %s %s has been completed successfully", transfer.AmountString, transfer.Coin),
 		Recipients: []string{transfer.RequestedByUserID.String()},
 		Data: map[string]interface{}{
 			"transfer_id":      transfer.ID.String(),
@@ -480,6 +1056,7 @@ func (ns *notificationService) SendTransferCompletedNotification(transfer *model
 		},
 	}
 
+	ns.applyTemplate(notification)
 	ns.enqueueNotification(notification)
 }
 
@@ -488,8 +1065,6 @@ func (ns *notificationService) SendTransferFailedNotification(transfer *models.T
 	notification := &Notification{
 		Type:       NotificationTypeTransferFailed,
 		Priority:   NotificationPriorityHigh,
-		Title:      fmt.Sprintf("Transfer Failed"),
-		Message:    fmt.Sprintf("Transfer of %s %s has failed: %s", transfer.AmountString, transfer.Coin, reason),
 		Recipients: []string{transfer.RequestedByUserID.String()},
 		Data: map[string]interface{}{
 			"transfer_id": transfer.ID.String(),
@@ -500,6 +1075,47 @@ func (ns *notificationService) SendTransferFailedNotification(transfer *models.T
 		},
 	}
 
+	ns.applyTemplate(notification)
+	ns.enqueueNotification(notification)
+}
+
+// SendTransferExpiredNotification sends notification when a transfer expires awaiting approval
+func (ns *notificationService) SendTransferExpiredNotification(transfer *models.TransferRequest, reason string) {
+	notification := &Notification{
+		Type:       NotificationTypeApprovalExpired,
+		Priority:   NotificationPriorityHigh,
+		Recipients: []string{transfer.RequestedByUserID.String()},
+		Data: map[string]interface{}{
+			"transfer_id":        transfer.ID.String(),
+			"amount":             transfer.AmountString,
+			"coin":               transfer.Coin,
+			"recipient":          transfer.RecipientAddress,
+			"required_approvals": transfer.RequiredApprovals,
+			"received_approvals": transfer.ReceivedApprovals,
+			"reason":             reason,
+		},
+	}
+
+	ns.applyTemplate(notification)
+	ns.enqueueNotification(notification)
+}
+
+// SendWalletFrozenNotification alerts the wallet's approvers, or the operators when it has
+// none, that the wallet was frozen automatically and needs someone to review and unfreeze it
+func (ns *notificationService) SendWalletFrozenNotification(wallet *models.Wallet, reason string) {
+	notification := &Notification{
+		Type:       NotificationTypeWalletFrozen,
+		Priority:   NotificationPriorityCritical,
+		Recipients: ns.walletApproverEmails(wallet.ID, []string{operatorsRecipient}),
+		Data: map[string]interface{}{
+			"wallet_id": wallet.ID.String(),
+			"label":     wallet.Label,
+			"coin":      wallet.Coin,
+			"reason":    reason,
+		},
+	}
+
+	ns.applyTemplate(notification)
 	ns.enqueueNotification(notification)
 }
 
@@ -508,7 +1124,7 @@ func (ns *notificationService) getStatusChangePriority(oldStatus, newStatus mode
 	switch newStatus {
 	case models.TransferStatusCompleted:
 		return NotificationPriorityNormal
-	case models.TransferStatusFailed, models.TransferStatusRejected:
+	case models.TransferStatusFailed, models.TransferStatusRejected, models.TransferStatusExpired:
 		return NotificationPriorityHigh
 	case models.TransferStatusPendingApproval:
 		return NotificationPriorityHigh
@@ -0,0 +1,89 @@
+package services
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// notificationDeduper suppresses notifications identical to one sent within the window, such
+// as the repeated status changes of a transfer flapping between statuses while it's polled.
+// Notifications are identical when their type, recipients, wallet, transfer and status match.
+// Only notifications about a transfer are deduplicated; others, such as wallet alerts, carry
+// no ID that tells two different events apart and are always sent.
+type notificationDeduper struct {
+	window time.Duration
+	now    func() time.Time
+
+	mu        sync.Mutex
+	seen      map[string]time.Time
+	lastSweep time.Time
+}
+
+func newNotificationDeduper(window time.Duration) *notificationDeduper {
+	return &notificationDeduper{
+		window: window,
+		now:    time.Now,
+		seen:   make(map[string]time.Time),
+	}
+}
+
+// isDuplicate reports whether an identical notification was let through within the window.
+// A notification that isn't a duplicate starts a new window for its key.
+func (d *notificationDeduper) isDuplicate(notification *Notification) bool {
+	if d == nil || d.window <= 0 || orderingKey(notification) == "" {
+		return false
+	}
+
+	key := notificationDedupKey(notification)
+	now := d.now()
+
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	d.sweep(now)
+	if sentAt, ok := d.seen[key]; ok && now.Sub(sentAt) < d.window {
+		return true
+	}
+	d.seen[key] = now
+	return false
+}
+
+// sweep forgets keys whose window has passed, at most once per window
+func (d *notificationDeduper) sweep(now time.Time) {
+	if now.Sub(d.lastSweep) < d.window {
+		return
+	}
+	d.lastSweep = now
+
+	for key, sentAt := range d.seen {
+		if now.Sub(sentAt) >= d.window {
+			delete(d.seen, key)
+		}
+	}
+}
+
+// notificationDedupKey hashes what makes two notifications the same for deduplication
+func notificationDedupKey(notification *Notification) string {
+	recipients := append([]string(nil), notification.Recipients...)
+	sort.Strings(recipients)
+
+	status, _ := notification.Data["new_status"].(string)
+	if status == "" {
+		status, _ = notification.Data["status"].(string)
+	}
+	walletID, _ := notification.Data["wallet_id"].(string)
+
+	sum := sha256.Sum256([]byte(fmt.Sprintf("%s\x00%s\x00%s\x00%s\x00%s",
+		notification.Type,
+		strings.Join(recipients, ","),
+		walletID,
+		orderingKey(notification),
+		status,
+	)))
+	return hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,56 @@
+package services
+
+import (
+	"testing"
+	"time"
+)
+
+func TestNotificationDeduperSuppressesRepeatedTransferStatus(t *testing.T) {
+	d := newNotificationDeduper(time.Minute)
+	now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	d.now = func() time.Time { return now }
+
+	status := func(transferID, newStatus string) *Notification {
+		return &Notification{
+			Type:       NotificationTypeTransferStatusChange,
+			Recipients: []string{"ops@example.com"},
+			Data:       map[string]interface{}{"transfer_id": transferID, "wallet_id": "w1", "new_status": newStatus},
+		}
+	}
+
+	if d.isDuplicate(status("t1", "signed")) {
+		t.Fatal("first notification reported as a duplicate")
+	}
+	if !d.isDuplicate(status("t1", "signed")) {
+		t.Error("repeated status within the window was not suppressed")
+	}
+	if d.isDuplicate(status("t2", "signed")) {
+		t.Error("same status for another transfer was suppressed")
+	}
+	if d.isDuplicate(status("t1", "broadcast")) {
+		t.Error("a different status was suppressed")
+	}
+
+	now = now.Add(time.Minute)
+	if d.isDuplicate(status("t1", "signed")) {
+		t.Error("repeated status after the window was suppressed")
+	}
+}
+
+func TestNotificationDeduperIgnoresNotificationsWithoutTransfer(t *testing.T) {
+	d := newNotificationDeduper(time.Minute)
+
+	alert := func(walletID string) *Notification {
+		return &Notification{
+			Type:       NotificationTypeWalletFrozen,
+			Recipients: []string{"ops@example.com"},
+			Data:       map[string]interface{}{"wallet_id": walletID},
+		}
+	}
+
+	for i := 0; i < 2; i++ {
+		if d.isDuplicate(alert("w1")) {
+			t.Fatalf("wallet alert %d was suppressed", i+1)
+		}
+	}
+}
@@ -0,0 +1,109 @@
+package services
+
+import (
+	"context"
+	"time"
+
+	"bitgo-wallets-api/internal/bitgo"
+	"bitgo-wallets-api/internal/models"
+
+	"github.com/google/uuid"
+)
+
+// guardedNotificationService wraps a NotificationService so a broken notification subsystem
+// can never block or crash the transfer flows that send notifications
+type guardedNotificationService struct {
+	inner   NotificationService
+	timeout time.Duration
+	logger  Logger
+}
+
+// NewGuardedNotificationService runs each send on its own goroutine with panic recovery and
+// waits at most timeout for it. Waiting keeps sends from one caller in order in the normal
+// case; a send that takes longer carries on in the background.
+func NewGuardedNotificationService(inner NotificationService, timeout time.Duration, logger Logger) NotificationService {
+	return &guardedNotificationService{
+		inner:   inner,
+		timeout: timeout,
+		logger:  logger,
+	}
+}
+
+// send runs fn without letting it panic into or hold up the caller. The subject, e.g. the
+// transfer ID, is logged under subjectKey if the send panics or times out.
+func (g *guardedNotificationService) send(kind, subjectKey string, subject uuid.UUID, fn func()) {
+	done := make(chan struct{})
+
+	go func() {
+		defer close(done)
+		defer func() {
+			if r := recover(); r != nil {
+				g.logger.Error("Notification send panicked",
+					"notification", kind,
+					subjectKey, subject,
+					"panic", r,
+				)
+			}
+		}()
+		fn()
+	}()
+
+	timer := time.NewTimer(g.timeout)
+	defer timer.Stop()
+
+	select {
+	case <-done:
+	case <-timer.C:
+		g.logger.Warn("Notification send timed out, continuing without it",
+			"notification", kind,
+			subjectKey, subject,
+			"timeout", g.timeout,
+		)
+	}
+}
+
+func (g *guardedNotificationService) SendTransferStatusNotification(transfer *models.TransferRequest, oldStatus, newStatus models.TransferStatus) {
+	g.send("transfer_status", "transfer_id", transfer.ID, func() {
+		g.inner.SendTransferStatusNotification(transfer, oldStatus, newStatus)
+	})
+}
+
+func (g *guardedNotificationService) SendPendingApprovalNotification(transfer *models.TransferRequest, approval *bitgo.ApprovalStatus) {
+	g.send("pending_approval", "transfer_id", transfer.ID, func() {
+		g.inner.SendPendingApprovalNotification(transfer, approval)
+	})
+}
+
+func (g *guardedNotificationService) SendTransferCreatedNotification(transfer *models.TransferRequest) {
+	g.send("transfer_created", "transfer_id", transfer.ID, func() {
+		g.inner.SendTransferCreatedNotification(transfer)
+	})
+}
+
+func (g *guardedNotificationService) SendTransferCompletedNotification(transfer *models.TransferRequest) {
+	g.send("transfer_completed", "transfer_id", transfer.ID, func() {
+		g.inner.SendTransferCompletedNotification(transfer)
+	})
+}
+
+func (g *guardedNotificationService) SendTransferFailedNotification(transfer *models.TransferRequest, reason string) {
+	g.send("transfer_failed", "transfer_id", transfer.ID, func() {
+		g.inner.SendTransferFailedNotification(transfer, reason)
+	})
+}
+
+func (g *guardedNotificationService) SendTransferExpiredNotification(transfer *models.TransferRequest, reason string) {
+	g.send("transfer_expired", "transfer_id", transfer.ID, func() {
+		g.inner.SendTransferExpiredNotification(transfer, reason)
+	})
+}
+
+func (g *guardedNotificationService) SendWalletFrozenNotification(wallet *models.Wallet, reason string) {
+	g.send("wallet_frozen", "wallet_id", wallet.ID, func() {
+		g.inner.SendWalletFrozenNotification(wallet, reason)
+	})
+}
+
+func (g *guardedNotificationService) Shutdown(ctx context.Context) error {
+	return g.inner.Shutdown(ctx)
+}
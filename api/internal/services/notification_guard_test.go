@@ -0,0 +1,75 @@
+package services
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"bitgo-wallets-api/internal/bitgo"
+	"bitgo-wallets-api/internal/models"
+
+	"github.com/google/uuid"
+)
+
+// panickingNotifier panics on every created notification
+type panickingNotifier struct {
+	nopNotifier
+}
+
+func (panickingNotifier) SendTransferCreatedNotification(*models.TransferRequest) {
+	panic("notification backend exploded")
+}
+
+// blockingNotifier blocks every created notification until release is closed
+type blockingNotifier struct {
+	nopNotifier
+	release chan struct{}
+}
+
+func (n blockingNotifier) SendTransferCreatedNotification(*models.TransferRequest) {
+	<-n.release
+}
+
+func TestTransferCreationSurvivesFailingNotifications(t *testing.T) {
+	withoutSimulatedDelay(t)
+	release := make(chan struct{})
+	t.Cleanup(func() { close(release) })
+
+	tests := []struct {
+		name     string
+		notifier NotificationService
+	}{
+		{name: "panicking", notifier: panickingNotifier{}},
+		{name: "blocking", notifier: blockingNotifier{release: release}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			wallet := newTestWarmWallet()
+			repo := newMemTransferRepo()
+			wws := NewWarmWalletService(
+				bitgo.NewSimulatedClient(bitgo.SimulationConfig{}, testLogger{}),
+				newMemWalletRepo(wallet),
+				repo,
+				NewGuardedNotificationService(tt.notifier, 50*time.Millisecond, testLogger{}),
+				testLogger{},
+				DefaultWarmWalletConfig(),
+				nil,
+				nil,
+				nil,
+			)
+
+			start := time.Now()
+			transfer, err := wws.CreateWarmTransferRequest(context.Background(), newTestWarmRequest(wallet, "0.1"), uuid.New())
+			if err != nil {
+				t.Fatalf("CreateWarmTransferRequest() error = %v", err)
+			}
+			if elapsed := time.Since(start); elapsed > time.Second {
+				t.Errorf("CreateWarmTransferRequest() took %v, want it bounded by the send timeout", elapsed)
+			}
+			if _, err := repo.GetByID(transfer.ID); err != nil {
+				t.Errorf("created transfer not stored: %v", err)
+			}
+		})
+	}
+}
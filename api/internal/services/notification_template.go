@@ -0,0 +1,219 @@
+package services
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"text/template"
+)
+
+// NotificationTemplate holds the text/template sources for a notification's title and
+// message. Both are executed against Notification.Data.
+type NotificationTemplate struct {
+	Title   string `json:"title"`
+	Message string `json:"message"`
+}
+
+// NotificationTemplates maps a notification type to its templates per channel. The empty
+// channel is the template used for any channel without one of its own.
+type NotificationTemplates map[NotificationType]map[NotificationChannel]NotificationTemplate
+
+// DefaultNotificationTemplates returns the built-in templates
+func DefaultNotificationTemplates() NotificationTemplates {
+	return NotificationTemplates{
+		NotificationTypeTransferStatusChange: {"": {
+			Title:   "Transfer Status Updated",
+			Message: "Transfer {{.transfer_id}} status changed from {{.old_status}} to {{.new_status}}",
+		}},
+		NotificationTypePendingApproval: {"": {
+			Title:   "Transfer Requires Approval",
+			Message: "Transfer {{.transfer_id}} requires {{.required_approvals}} approval(s). {{.received_approvals}} received, {{.pending_approvals}} pending.",
+		}},
+		NotificationTypeTransferCreated: {"": {
+			Title:   "Transfer Created",
+			Message: "Transfer of {{.amount}} {{.coin}} to {{.recipient}} has been created",
+		}},
+		NotificationTypeTransferCompleted: {"": {
+			Title:   "Transfer Completed",
+			Message: "Transfer of {{.amount}} {{.coin}} has been completed successfully",
+		}},
+		NotificationTypeTransferFailed: {"": {
+			Title:   "Transfer Failed",
+			Message: "Transfer of {{.amount}} {{.coin}} has failed: {{.reason}}",
+		}},
+		NotificationTypeApprovalExpired: {"": {
+			Title:   "Transfer Expired",
+			Message: "Transfer of {{.amount}} {{.coin}} has expired: {{.reason}}",
+		}},
+		NotificationTypeWalletFrozen: {"": {
+			Title:   "Wallet Frozen",
+			Message: "Wallet {{.label}} ({{.coin}}) was frozen automatically: {{.reason}}. Transfers are blocked until it is unfrozen.",
+		}},
+	}
+}
+
+// LoadNotificationTemplates reads template overrides from a JSON file shaped like
+// NotificationTemplates
+func LoadNotificationTemplates(path string) (NotificationTemplates, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read notification templates: %w", err)
+	}
+
+	var templates NotificationTemplates
+	if err := json.Unmarshal(data, &templates); err != nil {
+		return nil, fmt.Errorf("failed to parse notification templates: %w", err)
+	}
+	return templates, nil
+}
+
+type templateKey struct {
+	notificationType NotificationType
+	channel          NotificationChannel
+}
+
+type parsedTemplate struct {
+	title   *template.Template
+	message *template.Template
+}
+
+// notificationTemplateRegistry renders notification titles and messages from parsed templates
+type notificationTemplateRegistry struct {
+	templates map[templateKey]parsedTemplate
+	defaults  map[templateKey]parsedTemplate
+}
+
+// newNotificationTemplateRegistry parses the built-in templates and layers overrides on top.
+// An override that fails to parse is logged and the built-in template is kept.
+func newNotificationTemplateRegistry(overrides NotificationTemplates, logger Logger) *notificationTemplateRegistry {
+	registry := &notificationTemplateRegistry{
+		templates: make(map[templateKey]parsedTemplate),
+		defaults:  make(map[templateKey]parsedTemplate),
+	}
+
+	for notificationType, channels := range DefaultNotificationTemplates() {
+		for channel, source := range channels {
+			parsed, err := parseNotificationTemplate(notificationType, channel, source)
+			if err != nil {
+				// The built-in templates are constants; this only fires if one is edited badly
+				panic(err)
+			}
+			key := templateKey{notificationType, channel}
+			registry.defaults[key] = parsed
+			registry.templates[key] = parsed
+		}
+	}
+
+	for notificationType, channels := range overrides {
+		for channel, source := range channels {
+			parsed, err := parseNotificationTemplate(notificationType, channel, source)
+			if err != nil {
+				logger.Error("Invalid notification template override, keeping default",
+					"type", notificationType,
+					"channel", channel,
+					"error", err,
+				)
+				continue
+			}
+			registry.templates[templateKey{notificationType, channel}] = parsed
+		}
+	}
+
+	return registry
+}
+
+func parseNotificationTemplate(notificationType NotificationType, channel NotificationChannel, source NotificationTemplate) (parsedTemplate, error) {
+	name := fmt.Sprintf("%s/%s", notificationType, channel)
+	title, err := template.New(name + "/title").Parse(source.Title)
+	if err != nil {
+		return parsedTemplate{}, err
+	}
+	message, err := template.New(name + "/message").Parse(source.Message)
+	if err != nil {
+		return parsedTemplate{}, err
+	}
+	return parsedTemplate{title: title, message: message}, nil
+}
+
+// lookupTemplate finds the template for a type on a channel, falling back to the type's
+// any-channel template
+func lookupTemplate(templates map[templateKey]parsedTemplate, notificationType NotificationType, channel NotificationChannel) (parsedTemplate, bool) {
+	if parsed, ok := templates[templateKey{notificationType, channel}]; ok {
+		return parsed, true
+	}
+	parsed, ok := templates[templateKey{notificationType, ""}]
+	return parsed, ok
+}
+
+// hasChannelTemplate reports whether a type has a template specific to the channel
+func (r *notificationTemplateRegistry) hasChannelTemplate(notificationType NotificationType, channel NotificationChannel) bool {
+	_, ok := r.templates[templateKey{notificationType, channel}]
+	return ok
+}
+
+// render executes the template for a notification type and channel against data. If an
+// override fails to execute, the built-in template is used instead.
+func (r *notificationTemplateRegistry) render(notificationType NotificationType, channel NotificationChannel, data map[string]interface{}) (string, string, error) {
+	parsed, ok := lookupTemplate(r.templates, notificationType, channel)
+	if !ok {
+		return "", "", fmt.Errorf("no template for notification type %s", notificationType)
+	}
+
+	title, message, err := executeTemplate(parsed, data)
+	if err == nil {
+		return title, message, nil
+	}
+
+	fallback, ok := lookupTemplate(r.defaults, notificationType, channel)
+	if !ok {
+		return "", "", err
+	}
+	return executeTemplate(fallback, data)
+}
+
+func executeTemplate(parsed parsedTemplate, data map[string]interface{}) (string, string, error) {
+	var title, message bytes.Buffer
+	if err := parsed.title.Execute(&title, data); err != nil {
+		return "", "", err
+	}
+	if err := parsed.message.Execute(&message, data); err != nil {
+		return "", "", err
+	}
+	return title.String(), message.String(), nil
+}
+
+// applyTemplate renders a notification's title and message from its type's any-channel
+// template. The strings are what is stored and shown when a channel has no template of
+// its own.
+func (ns *notificationService) applyTemplate(notification *Notification) {
+	title, message, err := ns.templates.render(notification.Type, "", notification.Data)
+	if err != nil {
+		ns.logger.Error("Failed to render notification template",
+			"type", notification.Type,
+			"error", err,
+		)
+		title, message = string(notification.Type), ""
+	}
+	notification.Title = title
+	notification.Message = message
+}
+
+// contentFor returns the title and message to deliver on a channel, rendering the
+// channel's own template when one is registered
+func (ns *notificationService) contentFor(notification *Notification, channel NotificationChannel) (string, string) {
+	if !ns.templates.hasChannelTemplate(notification.Type, channel) {
+		return notification.Title, notification.Message
+	}
+
+	title, message, err := ns.templates.render(notification.Type, channel, notification.Data)
+	if err != nil {
+		ns.logger.Warn("Failed to render channel notification template",
+			"type", notification.Type,
+			"channel", channel,
+			"error", err,
+		)
+		return notification.Title, notification.Message
+	}
+	return title, message
+}
@@ -0,0 +1,88 @@
+package services
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"bitgo-wallets-api/internal/models"
+	"bitgo-wallets-api/internal/repository"
+
+	"github.com/google/uuid"
+)
+
+// contentRecordingRepo keeps the last saved record of each notification
+type contentRecordingRepo struct {
+	repository.NotificationRepository
+
+	mu      sync.Mutex
+	records []models.NotificationRecord
+}
+
+func (r *contentRecordingRepo) Save(record *models.NotificationRecord) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for i := range r.records {
+		if r.records[i].ID == record.ID {
+			r.records[i] = *record
+			return nil
+		}
+	}
+	r.records = append(r.records, *record)
+	return nil
+}
+
+func (r *contentRecordingRepo) saved() []models.NotificationRecord {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return append([]models.NotificationRecord(nil), r.records...)
+}
+
+func TestCustomTransferFailedTemplateRendersReason(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "templates.json")
+	overrides := `{"transfer_failed": {"": {"title": "{{.coin}} transfer failed", "message": "Sending {{.amount}} {{.coin}} failed because {{.reason}}"}}}`
+	if err := os.WriteFile(path, []byte(overrides), 0o600); err != nil {
+		t.Fatalf("write templates: %v", err)
+	}
+	templates, err := LoadNotificationTemplates(path)
+	if err != nil {
+		t.Fatalf("LoadNotificationTemplates() error = %v", err)
+	}
+
+	repo := &contentRecordingRepo{}
+	ns := NewNotificationService(NotificationConfig{
+		DefaultChannels: []NotificationChannel{NotificationChannelInApp},
+		RetryAttempts:   1,
+		RetryDelay:      time.Millisecond,
+		QueueSize:       10,
+		Workers:         1,
+		Templates:       templates,
+	}, testLogger{}, repo)
+
+	transfer := &models.TransferRequest{ID: uuid.New(), RequestedByUserID: uuid.New(), AmountString: "0.25", Coin: "btc"}
+	ns.SendTransferFailedNotification(transfer, "insufficient fee")
+
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+	defer cancel()
+	if err := ns.Shutdown(ctx); err != nil {
+		t.Fatalf("Shutdown() error = %v", err)
+	}
+
+	records := repo.saved()
+	if len(records) != 1 {
+		t.Fatalf("%d notifications saved, want 1", len(records))
+	}
+	record := records[0]
+	if record.Type != string(NotificationTypeTransferFailed) {
+		t.Errorf("type = %q, want %q", record.Type, NotificationTypeTransferFailed)
+	}
+	if want := "btc transfer failed"; record.Title != want {
+		t.Errorf("title = %q, want %q", record.Title, want)
+	}
+	if want := "Sending 0.25 btc failed because insufficient fee"; record.Message != want {
+		t.Errorf("message = %q, want %q", record.Message, want)
+	}
+}
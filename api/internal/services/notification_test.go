@@ -0,0 +1,329 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"bitgo-wallets-api/internal/models"
+	"bitgo-wallets-api/internal/repository"
+
+	"github.com/google/uuid"
+)
+
+// statusRecordingRepo keeps the last delivery status saved for each notification
+type statusRecordingRepo struct {
+	repository.NotificationRepository
+
+	mu       sync.Mutex
+	statuses map[uuid.UUID]models.NotificationDeliveryStatus
+}
+
+func newStatusRecordingRepo() *statusRecordingRepo {
+	return &statusRecordingRepo{statuses: make(map[uuid.UUID]models.NotificationDeliveryStatus)}
+}
+
+func (r *statusRecordingRepo) Save(record *models.NotificationRecord) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.statuses[record.ID] = record.Status
+	return nil
+}
+
+func (r *statusRecordingRepo) status(notification *Notification) models.NotificationDeliveryStatus {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.statuses[uuid.MustParse(notification.ID)]
+}
+
+func TestShutdownReportsUndrainedNotifications(t *testing.T) {
+	tests := []struct {
+		name    string
+		queued  int
+		wantErr error
+	}{
+		{name: "drained", queued: 0},
+		{name: "timed out", queued: 1, wantErr: ErrShutdownTimeout},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Without workers nothing queued is ever delivered
+			ns := NewNotificationService(NotificationConfig{QueueSize: 10}, testLogger{}, nil).(*notificationService)
+			for i := 0; i < tt.queued; i++ {
+				ns.enqueueNotification(&Notification{Type: NotificationTypeTransferCreated, Priority: NotificationPriorityLow})
+			}
+
+			ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+			defer cancel()
+			err := ns.Shutdown(ctx)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("Shutdown() = %v, want %v", err, tt.wantErr)
+			}
+			if tt.wantErr != nil && !errors.Is(err, context.DeadlineExceeded) {
+				t.Errorf("Shutdown() = %v, want it to wrap the context's error", err)
+			}
+		})
+	}
+}
+
+func TestQueueOverflowStrategies(t *testing.T) {
+	tests := []struct {
+		strategy    QueueOverflowStrategy
+		wantQueued  models.NotificationDeliveryStatus // Status of the notification already queued
+		wantNew     models.NotificationDeliveryStatus // Status of the one that didn't fit
+		wantInQueue int                               // Which of the two is left queued, 0 or 1
+	}{
+		{strategy: QueueOverflowDropNew, wantQueued: models.NotificationDeliveryPending, wantNew: models.NotificationDeliveryDropped, wantInQueue: 0},
+		{strategy: QueueOverflowDropOldest, wantQueued: models.NotificationDeliveryDropped, wantNew: models.NotificationDeliveryPending, wantInQueue: 1},
+		{strategy: QueueOverflowBlock, wantQueued: models.NotificationDeliveryPending, wantNew: models.NotificationDeliveryDropped, wantInQueue: 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(string(tt.strategy), func(t *testing.T) {
+			repo := newStatusRecordingRepo()
+			// Without workers the queue of one stays full
+			ns := NewNotificationService(NotificationConfig{
+				QueueSize:            1,
+				OverflowStrategy:     tt.strategy,
+				OverflowBlockTimeout: 20 * time.Millisecond,
+				DefaultChannels:      []NotificationChannel{NotificationChannelInApp},
+			}, testLogger{}, repo).(*notificationService)
+			defer ns.stop()
+
+			notifications := []*Notification{
+				{Type: NotificationTypeTransferCreated, Priority: NotificationPriorityLow},
+				{Type: NotificationTypeTransferCreated, Priority: NotificationPriorityLow},
+			}
+			for _, notification := range notifications {
+				ns.enqueueNotification(notification)
+			}
+
+			if got := repo.status(notifications[0]); got != tt.wantQueued {
+				t.Errorf("first notification = %s, want %s", got, tt.wantQueued)
+			}
+			if got := repo.status(notifications[1]); got != tt.wantNew {
+				t.Errorf("overflowing notification = %s, want %s", got, tt.wantNew)
+			}
+			queue := ns.queues[NotificationPriorityLow]
+			if len(queue) != 1 {
+				t.Fatalf("%d notifications queued, want 1", len(queue))
+			}
+			if queued := <-queue; queued != notifications[tt.wantInQueue] {
+				t.Errorf("queued notification %s, want %s", queued.ID, notifications[tt.wantInQueue].ID)
+			}
+		})
+
+		t.Run(string(tt.strategy)+" critical", func(t *testing.T) {
+			repo := newStatusRecordingRepo()
+			ns := NewNotificationService(NotificationConfig{
+				QueueSize:            1,
+				OverflowStrategy:     tt.strategy,
+				OverflowBlockTimeout: 20 * time.Millisecond,
+				DefaultChannels:      []NotificationChannel{NotificationChannelInApp},
+			}, testLogger{}, repo).(*notificationService)
+			defer ns.stop()
+
+			notifications := []*Notification{
+				{Type: NotificationTypeTransferFailed, Priority: NotificationPriorityCritical},
+				{Type: NotificationTypeTransferFailed, Priority: NotificationPriorityCritical},
+			}
+			for _, notification := range notifications {
+				ns.enqueueNotification(notification)
+			}
+
+			// The first stays queued and the second is delivered inline; neither is dropped
+			if got := repo.status(notifications[0]); got != models.NotificationDeliveryPending {
+				t.Errorf("queued critical notification = %s, want %s", got, models.NotificationDeliveryPending)
+			}
+			if got := repo.status(notifications[1]); got != models.NotificationDeliveryDelivered {
+				t.Errorf("overflowing critical notification = %s, want %s", got, models.NotificationDeliveryDelivered)
+			}
+		})
+	}
+}
+
+func TestBlockingOverflowQueuesOnceSpaceFrees(t *testing.T) {
+	repo := newStatusRecordingRepo()
+	ns := NewNotificationService(NotificationConfig{
+		QueueSize:            1,
+		OverflowStrategy:     QueueOverflowBlock,
+		OverflowBlockTimeout: time.Second,
+		DefaultChannels:      []NotificationChannel{NotificationChannelInApp},
+	}, testLogger{}, repo).(*notificationService)
+	defer ns.stop()
+
+	first := &Notification{Type: NotificationTypeTransferCreated, Priority: NotificationPriorityLow}
+	ns.enqueueNotification(first)
+
+	queue := ns.queues[NotificationPriorityLow]
+	go func() {
+		time.Sleep(10 * time.Millisecond)
+		<-queue
+	}()
+
+	second := &Notification{Type: NotificationTypeTransferCreated, Priority: NotificationPriorityLow}
+	ns.enqueueNotification(second)
+	if got := repo.status(second); got != models.NotificationDeliveryPending {
+		t.Errorf("blocked notification = %s, want %s once space freed", got, models.NotificationDeliveryPending)
+	}
+	if queued := <-queue; queued != second {
+		t.Errorf("queued notification %s, want %s", queued.ID, second.ID)
+	}
+}
+
+// deliveryLogRepo records the order in which notifications reach a final status
+type deliveryLogRepo struct {
+	repository.NotificationRepository
+
+	mu  sync.Mutex
+	log []string
+}
+
+func (r *deliveryLogRepo) Save(record *models.NotificationRecord) error {
+	if record.Status != models.NotificationDeliveryDelivered && record.Status != models.NotificationDeliveryFailed {
+		return nil
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.log = append(r.log, record.ID.String())
+	return nil
+}
+
+func (r *deliveryLogRepo) finished() []string {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return append([]string(nil), r.log...)
+}
+
+func TestStatusNotificationsKeepOrderAcrossRetries(t *testing.T) {
+	repo := &deliveryLogRepo{}
+	ns := NewNotificationService(NotificationConfig{
+		QueueSize:       10,
+		Workers:         2,
+		RetryAttempts:   3,
+		RetryDelay:      20 * time.Millisecond,
+		DefaultChannels: []NotificationChannel{NotificationChannelInApp},
+	}, testLogger{}, repo).(*notificationService)
+	defer ns.stop()
+
+	transferID, otherTransferID := uuid.New().String(), uuid.New().String()
+	statusChange := func(transferID, status string, channels ...NotificationChannel) *Notification {
+		return &Notification{
+			Type:     NotificationTypeTransferStatusChange,
+			Priority: NotificationPriorityLow,
+			Channels: channels,
+			Data:     map[string]interface{}{"transfer_id": transferID, "new_status": status},
+		}
+	}
+
+	// The webhook isn't configured, so "broadcast" retries until it gives up while the
+	// later statuses for the same transfer wait behind it
+	broadcast := statusChange(transferID, "broadcast", NotificationChannelWebhook)
+	confirmed := statusChange(transferID, "confirmed")
+	completed := statusChange(transferID, "completed")
+	unrelated := statusChange(otherTransferID, "broadcast")
+	for _, notification := range []*Notification{broadcast, confirmed, completed, unrelated} {
+		ns.enqueueNotification(notification)
+	}
+
+	var finished []string
+	for deadline := time.Now().Add(2 * time.Second); len(finished) < 4 && time.Now().Before(deadline); time.Sleep(5 * time.Millisecond) {
+		finished = repo.finished()
+	}
+	if len(finished) != 4 {
+		t.Fatalf("%d notifications finished, want 4", len(finished))
+	}
+
+	position := make(map[string]int, len(finished))
+	for i, id := range finished {
+		position[id] = i
+	}
+	if !(position[broadcast.ID] < position[confirmed.ID] && position[confirmed.ID] < position[completed.ID]) {
+		t.Errorf("finished in order %v, want broadcast %s, confirmed %s, completed %s", finished, broadcast.ID, confirmed.ID, completed.ID)
+	}
+	if position[unrelated.ID] > position[broadcast.ID] {
+		t.Errorf("notification for another transfer waited behind the retrying one: %v", finished)
+	}
+	if broadcast.RetryCount != 3 || broadcast.FailedAt == nil {
+		t.Errorf("broadcast notification retried %d times, failed at %v; want 3 attempts then failed", broadcast.RetryCount, broadcast.FailedAt)
+	}
+	for i, notification := range []*Notification{broadcast, confirmed, completed} {
+		if notification.Sequence != int64(i+1) {
+			t.Errorf("%s sequence = %d, want %d", notification.Data["new_status"], notification.Sequence, i+1)
+		}
+	}
+}
+
+func TestCriticalNotificationJumpsLowPriorityBacklog(t *testing.T) {
+	repo := &deliveryLogRepo{}
+	// No workers yet, so the backlog builds up before anything is delivered
+	ns := NewNotificationService(NotificationConfig{
+		QueueSize:       2000,
+		DefaultChannels: []NotificationChannel{NotificationChannelInApp},
+	}, testLogger{}, repo).(*notificationService)
+
+	for i := 0; i < 1000; i++ {
+		ns.enqueueNotification(&Notification{Type: NotificationTypeTransferCreated, Priority: NotificationPriorityLow})
+	}
+	critical := &Notification{Type: NotificationTypeTransferFailed, Priority: NotificationPriorityCritical}
+	ns.enqueueNotification(critical)
+
+	ns.wg.Add(1)
+	go ns.worker(0)
+	defer ns.stop()
+
+	var finished []string
+	for deadline := time.Now().Add(2 * time.Second); len(finished) == 0 && time.Now().Before(deadline); time.Sleep(time.Millisecond) {
+		finished = repo.finished()
+	}
+	if len(finished) == 0 {
+		t.Fatal("nothing was delivered")
+	}
+	if finished[0] != critical.ID {
+		t.Errorf("first delivered %s, want the critical notification %s ahead of 1000 low-priority ones", finished[0], critical.ID)
+	}
+}
+
+func TestFairnessIntervalPreventsStarvation(t *testing.T) {
+	tests := []struct {
+		name     string
+		interval int
+		wantPick int // Pick on which the low-priority notification is taken; 0 means never
+	}{
+		{name: "every fifth pick", interval: 5, wantPick: 5},
+		{name: "every pick", interval: 1, wantPick: 1},
+		{name: "disabled", interval: 0, wantPick: 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ns := NewNotificationService(NotificationConfig{QueueSize: 200, FairnessInterval: tt.interval}, testLogger{}, nil).(*notificationService)
+			defer ns.stop()
+
+			low := &Notification{Type: NotificationTypeTransferCreated, Priority: NotificationPriorityLow}
+			ns.enqueueNotification(low)
+			// Far more critical notifications than picks, so one is always waiting
+			for i := 0; i < 150; i++ {
+				ns.enqueueNotification(&Notification{Type: NotificationTypeTransferFailed, Priority: NotificationPriorityCritical})
+			}
+
+			gotPick := 0
+			for pick := 1; pick <= 100; pick++ {
+				notification, ok := ns.nextNotification(pick)
+				if !ok {
+					t.Fatalf("nextNotification(%d) returned nothing", pick)
+				}
+				if notification == low {
+					gotPick = pick
+					break
+				}
+			}
+			if gotPick != tt.wantPick {
+				t.Errorf("low-priority notification taken on pick %d, want %d", gotPick, tt.wantPick)
+			}
+		})
+	}
+}
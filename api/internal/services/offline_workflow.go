@@ -0,0 +1,225 @@
+package services
+
+import (
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"bitgo-wallets-api/internal/models"
+)
+
+// ErrInvalidOfflineTransition is returned when an offline workflow state change skips or reverses a step
+var ErrInvalidOfflineTransition = errors.New("invalid offline workflow transition")
+
+// ErrHSMSessionExpired is returned when a signature arrives after the HSM signing deadline
+var ErrHSMSessionExpired = errors.New("HSM signing session has expired")
+
+// Metadata keys used to store offline workflow progress on a cold transfer
+const (
+	metadataOfflineState        = "offline_state"
+	metadataOfflineStateHistory = "offline_state_history"
+	metadataHSMSession          = "hsm_session"
+)
+
+// offlineWorkflowTransitions lists the states each offline workflow state may move to.
+// Any active state can be escalated; an escalated transfer resumes at review or the operator queue.
+var offlineWorkflowTransitions = map[OfflineWorkflowState][]OfflineWorkflowState{
+	OfflineStateSubmitted:        {OfflineStateSecurityReview, OfflineStateEscalated},
+	OfflineStateSecurityReview:   {OfflineStateComplianceCheck, OfflineStateEscalated},
+	OfflineStateComplianceCheck:  {OfflineStateOperatorQueued, OfflineStateEscalated},
+	OfflineStateOperatorQueued:   {OfflineStateManualProcessing, OfflineStateEscalated},
+	OfflineStateManualProcessing: {OfflineStateAwaitingHSM, OfflineStateEscalated},
+	OfflineStateAwaitingHSM:      {OfflineStateReadyToExecute, OfflineStateEscalated},
+	OfflineStateReadyToExecute:   {OfflineStateExecuted, OfflineStateEscalated},
+	OfflineStateEscalated:        {OfflineStateSecurityReview, OfflineStateOperatorQueued},
+	OfflineStateExecuted:         {},
+}
+
+// OfflineStateTransition is a single recorded offline workflow state change
+type OfflineStateTransition struct {
+	From  OfflineWorkflowState `json:"from"`
+	To    OfflineWorkflowState `json:"to"`
+	Notes string               `json:"notes,omitempty"`
+	At    time.Time            `json:"at"`
+}
+
+// IsValid reports whether the state is a known offline workflow state
+func (s OfflineWorkflowState) IsValid() bool {
+	_, ok := offlineWorkflowTransitions[s]
+	return ok
+}
+
+// CanTransitionTo reports whether moving from s to next is an allowed offline workflow step
+func (s OfflineWorkflowState) CanTransitionTo(next OfflineWorkflowState) bool {
+	for _, allowed := range offlineWorkflowTransitions[s] {
+		if allowed == next {
+			return true
+		}
+	}
+	return false
+}
+
+// validateOfflineTransition checks a state change, wrapping ErrInvalidOfflineTransition on failure
+func validateOfflineTransition(from, to OfflineWorkflowState) error {
+	if !to.IsValid() {
+		return fmt.Errorf("%w: unknown state %q", ErrInvalidOfflineTransition, to)
+	}
+	if !from.CanTransitionTo(to) {
+		return fmt.Errorf("%w: cannot move from %s to %s", ErrInvalidOfflineTransition, from, to)
+	}
+	return nil
+}
+
+// offlineWorkflowState returns the transfer's current offline workflow state, defaulting to submitted
+func offlineWorkflowState(transfer *models.TransferRequest) OfflineWorkflowState {
+	if transfer.Metadata != nil {
+		if state, ok := transfer.Metadata[metadataOfflineState].(string); ok && state != "" {
+			return OfflineWorkflowState(state)
+		}
+	}
+	return OfflineStateSubmitted
+}
+
+// OfflineStateHistory returns the offline workflow transitions recorded on a transfer, oldest first
+func OfflineStateHistory(transfer *models.TransferRequest) []OfflineStateTransition {
+	if transfer.Metadata == nil {
+		return nil
+	}
+	history, _ := transfer.Metadata[metadataOfflineStateHistory].([]interface{})
+
+	transitions := make([]OfflineStateTransition, 0, len(history))
+	for _, item := range history {
+		entry, ok := item.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		transition := OfflineStateTransition{}
+		if from, ok := entry["from"].(string); ok {
+			transition.From = OfflineWorkflowState(from)
+		}
+		if to, ok := entry["to"].(string); ok {
+			transition.To = OfflineWorkflowState(to)
+		}
+		transition.Notes, _ = entry["notes"].(string)
+		if at, ok := entry["at"].(string); ok {
+			transition.At, _ = time.Parse(time.RFC3339, at)
+		}
+		transitions = append(transitions, transition)
+	}
+
+	return transitions
+}
+
+// recordOfflineTransition stores the new state and appends the change to the transfer's state history
+func recordOfflineTransition(transfer *models.TransferRequest, transition OfflineStateTransition) {
+	if transfer.Metadata == nil {
+		transfer.Metadata = models.JSON{}
+	}
+
+	// History round-trips through JSONB, so existing entries come back as generic values
+	history, _ := transfer.Metadata[metadataOfflineStateHistory].([]interface{})
+	entry := map[string]interface{}{
+		"from": string(transition.From),
+		"to":   string(transition.To),
+		"at":   transition.At.UTC().Format(time.RFC3339),
+	}
+	if transition.Notes != "" {
+		entry["notes"] = transition.Notes
+	}
+
+	transfer.Metadata[metadataOfflineState] = string(transition.To)
+	transfer.Metadata[metadataOfflineStateHistory] = append(history, entry)
+}
+
+// HSMSessionRequest identifies who is signing a cold transfer offline and on which HSM
+type HSMSessionRequest struct {
+	Operator string `json:"operator"`
+	HSMID    string `json:"hsm_id"`
+}
+
+// HSMSession is the offline signing session stored in a transfer's hsm_session metadata
+type HSMSession struct {
+	Operator      string     `json:"operator"`
+	HSMID         string     `json:"hsm_id"`
+	StartedAt     time.Time  `json:"started_at"`
+	Deadline      time.Time  `json:"deadline"`
+	UnsignedTxHex string     `json:"unsigned_tx_hex,omitempty"` // Transaction BitGo built under the cold build policy, for the HSM to sign
+	SignedTxHex   string     `json:"signed_tx_hex,omitempty"`
+	SignedBy      string     `json:"signed_by,omitempty"`
+	SignedAt      *time.Time `json:"signed_at,omitempty"`
+}
+
+// startHSMSession records the operator, HSM, signing deadline and the unsigned transaction to
+// sign for a transfer entering awaiting_hsm
+func startHSMSession(transfer *models.TransferRequest, request HSMSessionRequest, unsignedTxHex string, now time.Time, timeout time.Duration) {
+	if transfer.Metadata == nil {
+		transfer.Metadata = models.JSON{}
+	}
+	raw := map[string]interface{}{
+		"operator":   request.Operator,
+		"hsm_id":     request.HSMID,
+		"started_at": now.UTC().Format(time.RFC3339),
+		"deadline":   now.Add(timeout).UTC().Format(time.RFC3339),
+	}
+	if unsignedTxHex != "" {
+		raw["unsigned_tx_hex"] = unsignedTxHex
+	}
+	transfer.Metadata[metadataHSMSession] = raw
+}
+
+// hsmSession reads the HSM session from a transfer's metadata
+func hsmSession(transfer *models.TransferRequest) (*HSMSession, bool) {
+	if transfer.Metadata == nil {
+		return nil, false
+	}
+	raw, ok := transfer.Metadata[metadataHSMSession].(map[string]interface{})
+	if !ok {
+		return nil, false
+	}
+
+	session := &HSMSession{}
+	session.Operator, _ = raw["operator"].(string)
+	session.HSMID, _ = raw["hsm_id"].(string)
+	session.UnsignedTxHex, _ = raw["unsigned_tx_hex"].(string)
+	session.SignedTxHex, _ = raw["signed_tx_hex"].(string)
+	session.SignedBy, _ = raw["signed_by"].(string)
+	if value, ok := raw["started_at"].(string); ok {
+		session.StartedAt, _ = time.Parse(time.RFC3339, value)
+	}
+	if value, ok := raw["deadline"].(string); ok {
+		session.Deadline, _ = time.Parse(time.RFC3339, value)
+	}
+	if value, ok := raw["signed_at"].(string); ok {
+		if signedAt, err := time.Parse(time.RFC3339, value); err == nil {
+			session.SignedAt = &signedAt
+		}
+	}
+
+	return session, true
+}
+
+// recordHSMSignature adds the signed payload to the transfer's HSM session
+func recordHSMSignature(transfer *models.TransferRequest, signedTxHex, signedBy string, now time.Time) {
+	raw, _ := transfer.Metadata[metadataHSMSession].(map[string]interface{})
+	if raw == nil {
+		raw = map[string]interface{}{}
+	}
+	raw["signed_tx_hex"] = signedTxHex
+	raw["signed_by"] = signedBy
+	raw["signed_at"] = now.UTC().Format(time.RFC3339)
+	transfer.Metadata[metadataHSMSession] = raw
+}
+
+// normalizeSignedTxHex trims an optional 0x prefix and checks the payload is well-formed hex
+func normalizeSignedTxHex(signedTxHex string) (string, error) {
+	signedTxHex = strings.TrimPrefix(strings.TrimSpace(signedTxHex), "0x")
+	if signedTxHex == "" {
+		return "", fmt.Errorf("signed transaction hex is required")
+	}
+	if _, err := hex.DecodeString(signedTxHex); err != nil {
+		return "", fmt.Errorf("signed transaction is not valid hex: %w", err)
+	}
+	return strings.ToLower(signedTxHex), nil
+}
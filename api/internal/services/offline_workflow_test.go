@@ -0,0 +1,316 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"bitgo-wallets-api/internal/bitgo"
+	"bitgo-wallets-api/internal/models"
+
+	"github.com/google/uuid"
+)
+
+// newTestOfflineWorkflow returns a cold wallet service holding one submitted cold transfer,
+// building through a simulated BitGo
+func newTestOfflineWorkflow(config ColdWalletConfig) (*ColdWalletService, *memTransferRepo, uuid.UUID) {
+	return newTestOfflineWorkflowWithClient(config, bitgo.NewSimulatedClient(bitgo.SimulationConfig{}, testLogger{}))
+}
+
+// newTestOfflineWorkflowWithClient returns a cold wallet service holding one submitted cold
+// transfer from a cold BTC wallet, building through client
+func newTestOfflineWorkflowWithClient(config ColdWalletConfig, client bitgo.BitGoAPI) (*ColdWalletService, *memTransferRepo, uuid.UUID) {
+	wallet := &models.Wallet{ID: uuid.New(), BitgoWalletID: "bitgo-cold-1", Coin: "btc", WalletType: models.WalletTypeCold}
+	transfer := &models.TransferRequest{
+		ID:               uuid.New(),
+		WalletID:         wallet.ID,
+		RecipientAddress: testTrustedAddress,
+		AmountString:     "0.1",
+		Coin:             "btc",
+		TransferType:     models.WalletTypeCold,
+		Status:           models.TransferStatusSubmitted,
+		Version:          1,
+	}
+	repo := newMemTransferRepo(transfer)
+	cws := NewColdWalletService(client, newMemWalletRepo(wallet), repo, nopNotifier{}, testLogger{}, config, nil, nil)
+	return cws, repo, transfer.ID
+}
+
+func TestOfflineWorkflowFollowsTheLegalSequence(t *testing.T) {
+	cws, repo, transferID := newTestOfflineWorkflow(DefaultColdWalletConfig())
+	ctx := context.Background()
+
+	steps := []struct {
+		state      OfflineWorkflowState
+		wantStatus models.TransferStatus
+	}{
+		{state: OfflineStateSecurityReview, wantStatus: models.TransferStatusPendingApproval},
+		{state: OfflineStateComplianceCheck, wantStatus: models.TransferStatusPendingApproval},
+		{state: OfflineStateOperatorQueued, wantStatus: models.TransferStatusApproved},
+		{state: OfflineStateManualProcessing, wantStatus: models.TransferStatusApproved},
+		{state: OfflineStateAwaitingHSM, wantStatus: models.TransferStatusSigned},
+	}
+	for _, step := range steps {
+		if err := cws.UpdateOfflineWorkflowState(ctx, transferID, step.state, "", &HSMSessionRequest{Operator: "alice", HSMID: "hsm-1"}); err != nil {
+			t.Fatalf("UpdateOfflineWorkflowState(%s) error = %v", step.state, err)
+		}
+		stored, _ := repo.GetByID(transferID)
+		if state := offlineWorkflowState(stored); state != step.state || stored.Status != step.wantStatus {
+			t.Fatalf("after %s: state %s with status %s, want %s with status %s", step.state, state, stored.Status, step.state, step.wantStatus)
+		}
+	}
+
+	if _, err := cws.RecordOfflineSignature(ctx, transferID, "0xdeadbeef", ""); err != nil {
+		t.Fatalf("RecordOfflineSignature() error = %v", err)
+	}
+	if err := cws.UpdateOfflineWorkflowState(ctx, transferID, OfflineStateExecuted, "broadcast", nil); err != nil {
+		t.Fatalf("UpdateOfflineWorkflowState(%s) error = %v", OfflineStateExecuted, err)
+	}
+
+	stored, _ := repo.GetByID(transferID)
+	if stored.Status != models.TransferStatusBroadcast {
+		t.Errorf("status = %s, want %s", stored.Status, models.TransferStatusBroadcast)
+	}
+	history := OfflineStateHistory(stored)
+	if len(history) != len(steps)+2 {
+		t.Fatalf("recorded %d transitions, want %d", len(history), len(steps)+2)
+	}
+	previous := OfflineStateSubmitted
+	for i, transition := range history {
+		if transition.From != previous {
+			t.Errorf("transition %d from %s, want %s", i, transition.From, previous)
+		}
+		previous = transition.To
+	}
+	if previous != OfflineStateExecuted {
+		t.Errorf("history ends at %s, want %s", previous, OfflineStateExecuted)
+	}
+}
+
+func TestOfflineWorkflowRejectsSkippedSteps(t *testing.T) {
+	tests := []struct {
+		name  string
+		path  []OfflineWorkflowState
+		state OfflineWorkflowState
+	}{
+		{name: "submitted to operator queue", state: OfflineStateOperatorQueued},
+		{name: "submitted to executed", state: OfflineStateExecuted},
+		{name: "review back to submitted", path: []OfflineWorkflowState{OfflineStateSecurityReview}, state: OfflineStateSubmitted},
+		{name: "ready to execute without a signature", path: []OfflineWorkflowState{OfflineStateSecurityReview, OfflineStateComplianceCheck, OfflineStateOperatorQueued, OfflineStateManualProcessing, OfflineStateAwaitingHSM}, state: OfflineStateReadyToExecute},
+		{name: "unknown state", state: "approved"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cws, repo, transferID := newTestOfflineWorkflow(DefaultColdWalletConfig())
+			ctx := context.Background()
+			hsm := &HSMSessionRequest{Operator: "alice", HSMID: "hsm-1"}
+			for _, state := range tt.path {
+				if err := cws.UpdateOfflineWorkflowState(ctx, transferID, state, "", hsm); err != nil {
+					t.Fatalf("UpdateOfflineWorkflowState(%s) error = %v", state, err)
+				}
+			}
+			before, _ := repo.GetByID(transferID)
+			wantState, wantStatus := offlineWorkflowState(before), before.Status
+
+			err := cws.UpdateOfflineWorkflowState(ctx, transferID, tt.state, "", hsm)
+			if !errors.Is(err, ErrInvalidOfflineTransition) {
+				t.Fatalf("UpdateOfflineWorkflowState(%s) error = %v, want ErrInvalidOfflineTransition", tt.state, err)
+			}
+			after, _ := repo.GetByID(transferID)
+			if state := offlineWorkflowState(after); state != wantState || after.Status != wantStatus {
+				t.Errorf("rejected step left state %s with status %s, want %s with status %s", state, after.Status, wantState, wantStatus)
+			}
+		})
+	}
+}
+
+func TestHSMSigningSessionIsRecorded(t *testing.T) {
+	start := time.Date(2024, 1, 1, 9, 0, 0, 0, time.UTC)
+	config := DefaultColdWalletConfig()
+	config.HSMSigningTimeout = time.Hour
+
+	// awaitingHSM moves a fresh transfer to awaiting_hsm, opening the session at start
+	awaitingHSM := func(t *testing.T) (*ColdWalletService, *memTransferRepo, *testClock, uuid.UUID) {
+		t.Helper()
+		clock := &testClock{}
+		clock.Set(start)
+		config := config
+		config.Clock = clock
+		cws, repo, transferID := newTestOfflineWorkflow(config)
+		ctx := context.Background()
+		for _, state := range []OfflineWorkflowState{OfflineStateSecurityReview, OfflineStateComplianceCheck, OfflineStateOperatorQueued, OfflineStateManualProcessing, OfflineStateAwaitingHSM} {
+			if err := cws.UpdateOfflineWorkflowState(ctx, transferID, state, "", &HSMSessionRequest{Operator: "alice", HSMID: "hsm-1"}); err != nil {
+				t.Fatalf("UpdateOfflineWorkflowState(%s) error = %v", state, err)
+			}
+		}
+		return cws, repo, clock, transferID
+	}
+
+	t.Run("session opened with operator, HSM and deadline", func(t *testing.T) {
+		_, repo, _, transferID := awaitingHSM(t)
+		stored, _ := repo.GetByID(transferID)
+		session, ok := hsmSession(stored)
+		if !ok {
+			t.Fatal("no HSM session recorded")
+		}
+		if session.Operator != "alice" || session.HSMID != "hsm-1" {
+			t.Errorf("session operator %q on %q, want alice on hsm-1", session.Operator, session.HSMID)
+		}
+		if !session.StartedAt.Equal(start) || !session.Deadline.Equal(start.Add(time.Hour)) {
+			t.Errorf("session %s to %s, want %s to %s", session.StartedAt, session.Deadline, start, start.Add(time.Hour))
+		}
+	})
+
+	t.Run("hsm details required", func(t *testing.T) {
+		cws, _, transferID := newTestOfflineWorkflow(config)
+		ctx := context.Background()
+		for _, state := range []OfflineWorkflowState{OfflineStateSecurityReview, OfflineStateComplianceCheck, OfflineStateOperatorQueued, OfflineStateManualProcessing} {
+			if err := cws.UpdateOfflineWorkflowState(ctx, transferID, state, "", nil); err != nil {
+				t.Fatalf("UpdateOfflineWorkflowState(%s) error = %v", state, err)
+			}
+		}
+		if err := cws.UpdateOfflineWorkflowState(ctx, transferID, OfflineStateAwaitingHSM, "", &HSMSessionRequest{Operator: "alice"}); err == nil {
+			t.Error("UpdateOfflineWorkflowState() without an HSM ID succeeded, want an error")
+		}
+	})
+
+	t.Run("signature recorded", func(t *testing.T) {
+		cws, _, clock, transferID := awaitingHSM(t)
+		clock.Set(start.Add(30 * time.Minute))
+
+		transfer, err := cws.RecordOfflineSignature(context.Background(), transferID, "0xDEADBEEF", "")
+		if err != nil {
+			t.Fatalf("RecordOfflineSignature() error = %v", err)
+		}
+		session, _ := hsmSession(transfer)
+		if session.SignedTxHex != "deadbeef" || session.SignedBy != "alice" {
+			t.Errorf("signed %q by %q, want deadbeef by the session operator alice", session.SignedTxHex, session.SignedBy)
+		}
+		if session.SignedAt == nil || !session.SignedAt.Equal(clock.Now()) {
+			t.Errorf("signed at %v, want %s", session.SignedAt, clock.Now())
+		}
+		if state := offlineWorkflowState(transfer); state != OfflineStateReadyToExecute {
+			t.Errorf("state = %s, want %s", state, OfflineStateReadyToExecute)
+		}
+
+		// Replaying the same signature is a no-op; a different one is refused
+		if _, err := cws.RecordOfflineSignature(context.Background(), transferID, "deadbeef", "bob"); err != nil {
+			t.Errorf("replayed RecordOfflineSignature() error = %v", err)
+		}
+		if _, err := cws.RecordOfflineSignature(context.Background(), transferID, "cafebabe", "bob"); !errors.Is(err, ErrInvalidOfflineTransition) {
+			t.Errorf("second RecordOfflineSignature() error = %v, want ErrInvalidOfflineTransition", err)
+		}
+	})
+
+	t.Run("signature after the deadline", func(t *testing.T) {
+		cws, repo, clock, transferID := awaitingHSM(t)
+		clock.Set(start.Add(time.Hour + time.Second))
+
+		if _, err := cws.RecordOfflineSignature(context.Background(), transferID, "deadbeef", ""); !errors.Is(err, ErrHSMSessionExpired) {
+			t.Fatalf("RecordOfflineSignature() error = %v, want ErrHSMSessionExpired", err)
+		}
+		stored, _ := repo.GetByID(transferID)
+		if state := offlineWorkflowState(stored); state != OfflineStateAwaitingHSM {
+			t.Errorf("state = %s, want %s", state, OfflineStateAwaitingHSM)
+		}
+	})
+
+	t.Run("malformed signature", func(t *testing.T) {
+		cws, _, _, transferID := awaitingHSM(t)
+		if _, err := cws.RecordOfflineSignature(context.Background(), transferID, "not-hex", ""); err == nil {
+			t.Error("RecordOfflineSignature() with malformed hex succeeded, want an error")
+		}
+	})
+}
+
+// buildRecordingClient records the build requests sent to a simulated BitGo
+type buildRecordingClient struct {
+	*bitgo.SimulatedClient
+
+	mu     sync.Mutex
+	builds []bitgo.BuildTransferRequest
+}
+
+func newBuildRecordingClient() *buildRecordingClient {
+	return &buildRecordingClient{SimulatedClient: bitgo.NewSimulatedClient(bitgo.SimulationConfig{}, testLogger{})}
+}
+
+func (c *buildRecordingClient) BuildTransfer(ctx context.Context, walletID, coin string, req bitgo.BuildTransferRequest) (*bitgo.BuildTransferResponse, error) {
+	c.mu.Lock()
+	c.builds = append(c.builds, req)
+	c.mu.Unlock()
+	return c.SimulatedClient.BuildTransfer(ctx, walletID, coin, req)
+}
+
+func (c *buildRecordingClient) recorded() []bitgo.BuildTransferRequest {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return append([]bitgo.BuildTransferRequest(nil), c.builds...)
+}
+
+func TestHSMSessionBuildsUnderTheColdPolicy(t *testing.T) {
+	config := DefaultColdWalletConfig()
+	config.MinConfirms = 2
+	client := newBuildRecordingClient()
+	cws, repo, transferID := newTestOfflineWorkflowWithClient(config, client)
+	ctx := context.Background()
+
+	for _, state := range []OfflineWorkflowState{OfflineStateSecurityReview, OfflineStateComplianceCheck, OfflineStateOperatorQueued, OfflineStateManualProcessing} {
+		if err := cws.UpdateOfflineWorkflowState(ctx, transferID, state, "", nil); err != nil {
+			t.Fatalf("UpdateOfflineWorkflowState(%s) error = %v", state, err)
+		}
+	}
+	if builds := client.recorded(); len(builds) != 0 {
+		t.Fatalf("built %d transactions before the HSM session, want none", len(builds))
+	}
+
+	if err := cws.UpdateOfflineWorkflowState(ctx, transferID, OfflineStateAwaitingHSM, "", &HSMSessionRequest{Operator: "alice", HSMID: "hsm-1"}); err != nil {
+		t.Fatalf("UpdateOfflineWorkflowState(%s) error = %v", OfflineStateAwaitingHSM, err)
+	}
+
+	builds := client.recorded()
+	if len(builds) != 1 {
+		t.Fatalf("built %d transactions, want 1", len(builds))
+	}
+	if builds[0].MinConfirms != 2 || !builds[0].EnforceMinConfirmsForChange {
+		t.Errorf("build minConfirms = %d, enforceMinConfirmsForChange = %v; want 2, true", builds[0].MinConfirms, builds[0].EnforceMinConfirmsForChange)
+	}
+	if builds[0].SequenceId != transferID.String() {
+		t.Errorf("build sequenceId = %q, want the transfer ID", builds[0].SequenceId)
+	}
+
+	stored, _ := repo.GetByID(transferID)
+	if session, _ := hsmSession(stored); session == nil || session.UnsignedTxHex == "" {
+		t.Error("HSM session has no unsigned transaction to sign")
+	}
+}
+
+func TestHSMSessionNotStartedWhenBuildFails(t *testing.T) {
+	cws, repo, transferID := newTestOfflineWorkflowWithClient(DefaultColdWalletConfig(), &failingBuildClient{})
+	ctx := context.Background()
+	for _, state := range []OfflineWorkflowState{OfflineStateSecurityReview, OfflineStateComplianceCheck, OfflineStateOperatorQueued, OfflineStateManualProcessing} {
+		if err := cws.UpdateOfflineWorkflowState(ctx, transferID, state, "", nil); err != nil {
+			t.Fatalf("UpdateOfflineWorkflowState(%s) error = %v", state, err)
+		}
+	}
+
+	if err := cws.UpdateOfflineWorkflowState(ctx, transferID, OfflineStateAwaitingHSM, "", &HSMSessionRequest{Operator: "alice", HSMID: "hsm-1"}); err == nil {
+		t.Fatal("UpdateOfflineWorkflowState() with a failing build succeeded, want an error")
+	}
+	stored, _ := repo.GetByID(transferID)
+	if state := offlineWorkflowState(stored); state != OfflineStateManualProcessing {
+		t.Errorf("state = %s, want %s", state, OfflineStateManualProcessing)
+	}
+}
+
+// failingBuildClient fails every build; other BitGo calls aren't used
+type failingBuildClient struct {
+	bitgo.BitGoAPI
+}
+
+func (failingBuildClient) BuildTransfer(ctx context.Context, walletID, coin string, req bitgo.BuildTransferRequest) (*bitgo.BuildTransferResponse, error) {
+	return nil, bitgo.APIError{StatusCode: 400, Message: "insufficient funds", Name: "InsufficientBalance"}
+}
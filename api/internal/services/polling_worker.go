@@ -2,6 +2,7 @@ package services
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"sync"
 	"time"
@@ -11,6 +12,10 @@ import (
 	"bitgo-wallets-api/internal/repository"
 )
 
+// ErrShutdownTimeout is returned by a background service's Stop or Shutdown when its
+// in-flight work didn't finish within the shutdown timeout
+var ErrShutdownTimeout = errors.New("shutdown timed out")
+
 // Logger interface for the worker service
 type Logger interface {
 	Info(msg string, fields ...interface{})
@@ -21,23 +26,46 @@ type Logger interface {
 
 // PollingWorkerConfig configures the polling worker
 type PollingWorkerConfig struct {
-	PollInterval      time.Duration // How often to poll for updates
-	BatchSize         int           // Number of transfers to process per batch
-	MaxRetries        int           // Max retries for failed polling attempts
-	StaleThreshold    time.Duration // How old a transfer can be before considered stale
-	ConcurrentWorkers int           // Number of concurrent workers
-	ShutdownTimeout   time.Duration // Timeout for graceful shutdown
+	PollInterval      time.Duration                       // How often to check for transfers due for polling
+	TypePollIntervals map[models.WalletType]time.Duration // How often each wallet type's transfers are polled
+	BatchSize         int                                 // Number of transfers to process per batch
+	MaxRetries        int                                 // Max retries for failed polling attempts
+	StaleThreshold    time.Duration                       // How old a transfer can be before considered stale
+	ConcurrentWorkers int                                 // Number of concurrent workers
+	ShutdownTimeout   time.Duration                       // Timeout for graceful shutdown
+	StallMultiplier   int                                 // Poll intervals without a completed cycle before reporting degraded
+	Clock             Clock                               // Supplies the current time; nil uses the wall clock
+
+	// Each BitGo lookup is bounded by RequestTimeout, or the coin's entry in CoinRequestTimeouts
+	RequestTimeout      time.Duration
+	CoinRequestTimeouts map[string]time.Duration
+
+	// After a failed lookup a transfer isn't polled again for its type's poll interval, doubled
+	// for each further consecutive failure up to MaxPollBackoff. At MaxPollFailures it is
+	// flagged for manual attention and no longer polled.
+	MaxPollBackoff  time.Duration
+	MaxPollFailures int
 }
 
 // DefaultPollingWorkerConfig returns sensible defaults
 func DefaultPollingWorkerConfig() PollingWorkerConfig {
 	return PollingWorkerConfig{
-		PollInterval:      30 * time.Second,
+		PollInterval: 30 * time.Second,
+		TypePollIntervals: map[models.WalletType]time.Duration{
+			models.WalletTypeHot:       30 * time.Second,
+			models.WalletTypeCustodial: 30 * time.Second,
+			models.WalletTypeWarm:      2 * time.Minute,
+			models.WalletTypeCold:      15 * time.Minute,
+		},
 		BatchSize:         50,
 		MaxRetries:        3,
 		StaleThreshold:    24 * time.Hour,
 		ConcurrentWorkers: 3,
 		ShutdownTimeout:   30 * time.Second,
+		StallMultiplier:   3,
+		RequestTimeout:    30 * time.Second,
+		MaxPollBackoff:    6 * time.Hour,
+		MaxPollFailures:   10,
 	}
 }
 
@@ -45,12 +73,22 @@ func DefaultPollingWorkerConfig() PollingWorkerConfig {
 type TransferPollingWorker struct {
 	config          PollingWorkerConfig
 	logger          Logger
-	bitgoClient     *bitgo.Client
+	bitgoClient     bitgo.BitGoAPI
 	approvalService *bitgo.ApprovalService
 	transferRepo    repository.TransferRequestRepository
 	walletRepo      repository.WalletRepository
 	notificationSvc NotificationService
 
+	// Work queue for transfers due for polling
+	workQueue chan *models.TransferRequest
+
+	// Watermark of the last transfer picked up, so successive polls walk the whole backlog
+	pollCursor *repository.TransferCursor
+
+	// Liveness tracking: when the worker started and when a poll cycle last completed
+	startedAt   time.Time
+	lastCycleAt time.Time
+
 	// Control channels
 	ctx       context.Context
 	cancel    context.CancelFunc
@@ -65,11 +103,13 @@ type TransferPollingWorker struct {
 func NewTransferPollingWorker(
 	config PollingWorkerConfig,
 	logger Logger,
-	bitgoClient *bitgo.Client,
+	bitgoClient bitgo.BitGoAPI,
 	transferRepo repository.TransferRequestRepository,
 	walletRepo repository.WalletRepository,
 	notificationSvc NotificationService,
 ) *TransferPollingWorker {
+	config.Clock = clockOrReal(config.Clock)
+
 	ctx, cancel := context.WithCancel(context.Background())
 
 	approvalService := bitgo.NewApprovalService(bitgoClient, logger)
@@ -82,6 +122,7 @@ func NewTransferPollingWorker(
 		transferRepo:    transferRepo,
 		walletRepo:      walletRepo,
 		notificationSvc: notificationSvc,
+		workQueue:       make(chan *models.TransferRequest, config.BatchSize),
 		ctx:             ctx,
 		cancel:          cancel,
 		shutdown:        make(chan struct{}),
@@ -99,8 +140,10 @@ func (w *TransferPollingWorker) Start() error {
 	}
 
 	w.isRunning = true
+	w.startedAt = w.config.Clock.Now()
 	w.logger.Info("Starting transfer polling worker",
 		"poll_interval", w.config.PollInterval,
+		"type_poll_intervals", w.config.TypePollIntervals,
 		"batch_size", w.config.BatchSize,
 		"concurrent_workers", w.config.ConcurrentWorkers,
 	)
@@ -118,7 +161,8 @@ func (w *TransferPollingWorker) Start() error {
 	return nil
 }
 
-// Stop gracefully stops the polling worker
+// Stop gracefully stops the polling worker, returning ErrShutdownTimeout if polls outlast
+// the shutdown timeout
 func (w *TransferPollingWorker) Stop() error {
 	w.mu.Lock()
 	if !w.isRunning {
@@ -141,15 +185,17 @@ func (w *TransferPollingWorker) Stop() error {
 		close(done)
 	}()
 
+	var err error
 	select {
 	case <-done:
 		w.logger.Info("Transfer polling worker stopped gracefully")
 	case <-time.After(w.config.ShutdownTimeout):
 		w.logger.Warn("Transfer polling worker shutdown timed out")
+		err = fmt.Errorf("%w after %s: a poll is still running", ErrShutdownTimeout, w.config.ShutdownTimeout)
 	}
 
 	close(w.stopped)
-	return nil
+	return err
 }
 
 // IsRunning returns whether the worker is currently running
@@ -194,27 +240,72 @@ func (w *TransferPollingWorker) pollTransfers() {
 		models.TransferStatusBroadcast,
 	}
 
-	transfers, err := w.transferRepo.GetTransfersByStatuses(statuses, w.config.BatchSize)
+	now := w.config.Clock.Now()
+	dueBefore := make(map[models.WalletType]time.Time, len(w.config.TypePollIntervals))
+	for walletType, interval := range w.config.TypePollIntervals {
+		dueBefore[walletType] = now.Add(-interval)
+	}
+
+	transfers, err := w.transferRepo.ListDueForPolling(statuses, dueBefore, now, w.pollCursor, w.config.BatchSize)
 	if err != nil {
 		w.logger.Error("Failed to get transfers for polling", "error", err)
 		return
 	}
 
+	// Advance the watermark, wrapping back to the oldest transfers once we reach the end
+	if len(transfers) < w.config.BatchSize {
+		w.pollCursor = nil
+	} else {
+		last := transfers[len(transfers)-1]
+		w.pollCursor = &repository.TransferCursor{UpdatedAt: last.UpdatedAt, ID: last.ID}
+	}
+
 	if len(transfers) == 0 {
 		w.logger.Debug("No transfers need status polling")
+		w.recordCycleCompleted()
 		return
 	}
 
 	w.logger.Info("Found transfers to poll", "count", len(transfers))
 
-	// Distribute transfers to workers via channel
-	transferChan := make(chan *models.TransferRequest, len(transfers))
+	// Mark transfers as polled before queueing so the next tick doesn't pick them up again
 	for _, transfer := range transfers {
-		transferChan <- transfer
+		if err := w.transferRepo.MarkPolled(transfer.ID, now); err != nil {
+			w.logger.Error("Failed to mark transfer as polled",
+				"transfer_id", transfer.ID,
+				"error", err,
+			)
+			continue
+		}
+		transfer.LastPolledAt = &now
+
+		select {
+		case w.workQueue <- transfer:
+		case <-w.shutdown:
+			return
+		case <-w.ctx.Done():
+			return
+		}
 	}
-	close(transferChan)
 
-	// Workers will process from the channel
+	w.recordCycleCompleted()
+}
+
+// recordCycleCompleted notes that a poll cycle ran to completion
+func (w *TransferPollingWorker) recordCycleCompleted() {
+	w.mu.Lock()
+	w.lastCycleAt = w.config.Clock.Now()
+	w.mu.Unlock()
+}
+
+// stallThreshold is how long the worker can go without completing a poll cycle
+// before it's considered wedged
+func (w *TransferPollingWorker) stallThreshold() time.Duration {
+	multiplier := w.config.StallMultiplier
+	if multiplier <= 0 {
+		multiplier = 3
+	}
+	return time.Duration(multiplier) * w.config.PollInterval
 }
 
 // worker processes transfers from the work queue
@@ -231,17 +322,15 @@ func (w *TransferPollingWorker) worker(workerID int) {
 		case <-w.ctx.Done():
 			w.logger.Debug("Worker context cancelled", "worker_id", workerID)
 			return
-		default:
-			// This would normally read from a work channel
-			// For now, just sleep to avoid busy waiting
-			time.Sleep(100 * time.Millisecond)
+		case transfer := <-w.workQueue:
+			w.processTransfer(transfer)
 		}
 	}
 }
 
 // processTransfer handles status polling for a single transfer
 func (w *TransferPollingWorker) processTransfer(transfer *models.TransferRequest) {
-	ctx, cancel := context.WithTimeout(w.ctx, 30*time.Second)
+	ctx, cancel := context.WithTimeout(w.ctx, w.requestTimeout(transfer.Coin))
 	defer cancel()
 
 	w.logger.Debug("Processing transfer",
@@ -285,6 +374,20 @@ func (w *TransferPollingWorker) processTransfer(transfer *models.TransferRequest
 	}
 }
 
+// polledTransitionAllowed reports whether polling may move a transfer from its status to the
+// one BitGo reports. Polling only moves transfers forward: a terminal transfer stays as it
+// is, and a broadcast one can only settle, so a stale BitGo state can't send a transfer back
+// through approval or submission.
+func polledTransitionAllowed(from, to models.TransferStatus) bool {
+	if statusIn(from, terminalTransferStatuses) {
+		return false
+	}
+	if from == models.TransferStatusBroadcast {
+		return statusIn(to, terminalTransferStatuses)
+	}
+	return true
+}
+
 // updateTransferStatus checks and updates transfer status from BitGo
 func (w *TransferPollingWorker) updateTransferStatus(ctx context.Context, transfer *models.TransferRequest, wallet *models.Wallet) (bool, error) {
 	// Only poll transfers that have been submitted to BitGo
@@ -295,40 +398,54 @@ func (w *TransferPollingWorker) updateTransferStatus(ctx context.Context, transf
 	// Get transfer status from BitGo
 	bitgoTransfer, err := w.bitgoClient.GetTransfer(ctx, wallet.BitgoWalletID, wallet.Coin, *transfer.BitgoTransferID)
 	if err != nil {
+		w.recordPollFailure(transfer, err)
 		return false, fmt.Errorf("failed to get BitGo transfer: %w", err)
 	}
+	if transfer.PollFailures > 0 {
+		if err := w.transferRepo.ResetPollFailures(transfer.ID); err != nil {
+			w.logger.Warn("Failed to reset poll failures",
+				"transfer_id", transfer.ID,
+				"error", err,
+			)
+		}
+		transfer.PollFailures = 0
+	}
 
 	// Normalize status using status mapper
 	statusMapper := bitgo.NewStatusMapper()
 	canonicalStatus := statusMapper.NormalizeTransferStatus(bitgoTransfer.State, bitgoTransfer)
 	newStatus := models.TransferStatus(canonicalStatus)
 
-	// Check if status changed
-	if transfer.Status == newStatus {
-		return false, nil // No change
-	}
-
-	// Update transfer with new status
-	oldStatus := transfer.Status
-	transfer.Status = newStatus
-
-	// Update timestamps based on status
-	now := time.Now()
-	switch newStatus {
-	case models.TransferStatusConfirmed:
-		if transfer.CompletedAt == nil {
-			transfer.CompletedAt = &now
+	// Save the new status, reapplying it to the latest row if an API call updated the
+	// transfer while BitGo was being polled
+	var oldStatus models.TransferStatus
+	updated, err := repository.UpdateWithRetry(w.transferRepo, transfer, func(transfer *models.TransferRequest) bool {
+		if transfer.Status == newStatus || !polledTransitionAllowed(transfer.Status, newStatus) {
+			return false // No change, or BitGo's state would move the transfer backwards
 		}
-	case models.TransferStatusFailed:
-		if transfer.FailedAt == nil {
-			transfer.FailedAt = &now
+		oldStatus = transfer.Status
+		transfer.Status = newStatus
+
+		// Update timestamps based on status
+		now := w.config.Clock.Now()
+		switch newStatus {
+		case models.TransferStatusConfirmed:
+			if transfer.CompletedAt == nil {
+				transfer.CompletedAt = &now
+			}
+		case models.TransferStatusFailed:
+			if transfer.FailedAt == nil {
+				transfer.FailedAt = &now
+			}
 		}
-	}
-
-	// Save to database
-	if err := w.transferRepo.Update(transfer); err != nil {
+		return true
+	})
+	if err != nil {
 		return false, fmt.Errorf("failed to update transfer in database: %w", err)
 	}
+	if !updated {
+		return false, nil
+	}
 
 	// Send notification about status change
 	w.notificationSvc.SendTransferStatusNotification(transfer, oldStatus, newStatus)
@@ -336,6 +453,69 @@ func (w *TransferPollingWorker) updateTransferStatus(ctx context.Context, transf
 	return true, nil
 }
 
+// requestTimeout returns how long a BitGo lookup for the coin may take
+func (w *TransferPollingWorker) requestTimeout(coin string) time.Duration {
+	if timeout, ok := w.config.CoinRequestTimeouts[bitgo.CanonicalCoin(coin)]; ok && timeout > 0 {
+		return timeout
+	}
+	if w.config.RequestTimeout > 0 {
+		return w.config.RequestTimeout
+	}
+	return 30 * time.Second
+}
+
+// pollBackoff returns how long to wait before polling a transfer again after its given
+// number of consecutive failures: the type's poll interval, doubled per further failure
+func (w *TransferPollingWorker) pollBackoff(transferType models.WalletType, failures int) time.Duration {
+	backoff := w.config.TypePollIntervals[transferType]
+	if backoff <= 0 {
+		backoff = w.config.PollInterval
+	}
+	for i := 1; i < failures; i++ {
+		backoff *= 2
+		if w.config.MaxPollBackoff > 0 && backoff >= w.config.MaxPollBackoff {
+			return w.config.MaxPollBackoff
+		}
+	}
+	return backoff
+}
+
+// recordPollFailure backs the transfer off after a failed BitGo lookup, flagging it for
+// manual attention once it has failed MaxPollFailures times in a row
+func (w *TransferPollingWorker) recordPollFailure(transfer *models.TransferRequest, lookupErr error) {
+	// A lookup cut short by shutdown says nothing about BitGo, so it doesn't count
+	if errors.Is(lookupErr, context.Canceled) {
+		return
+	}
+	failures := transfer.PollFailures + 1
+	needsAttention := w.config.MaxPollFailures > 0 && failures >= w.config.MaxPollFailures
+	nextPollAt := w.config.Clock.Now().Add(w.pollBackoff(transfer.TransferType, failures))
+
+	if err := w.transferRepo.RecordPollFailure(transfer.ID, failures, lookupErr.Error(), nextPollAt, needsAttention); err != nil {
+		w.logger.Error("Failed to record poll failure",
+			"transfer_id", transfer.ID,
+			"error", err,
+		)
+		return
+	}
+	transfer.PollFailures = failures
+	transfer.NextPollAt = &nextPollAt
+
+	if needsAttention {
+		w.logger.Error("Transfer needs manual attention: BitGo lookups keep failing, polling stopped",
+			"transfer_id", transfer.ID,
+			"failures", failures,
+			"error", lookupErr,
+		)
+		return
+	}
+	w.logger.Warn("Backing off polling transfer after failed BitGo lookup",
+		"transfer_id", transfer.ID,
+		"failures", failures,
+		"next_poll_at", nextPollAt,
+	)
+}
+
 // checkPendingApprovals checks for pending approvals and sends notifications
 func (w *TransferPollingWorker) checkPendingApprovals(ctx context.Context, transfer *models.TransferRequest, wallet *models.Wallet) {
 	if transfer.BitgoTxid == nil {
@@ -362,6 +542,13 @@ func (w *TransferPollingWorker) checkPendingApprovals(ctx context.Context, trans
 		return // No pending approval
 	}
 
+	// BitGo doesn't move the transfer on when its approval lapses, so expire it here rather
+	// than leave it pending forever. An approval without an expiry never lapses.
+	if approvalStatus.IsExpired && !approvalStatus.Expires.IsZero() {
+		w.expireApproval(transfer, approvalStatus)
+		return
+	}
+
 	// Send pending approval notifications
 	w.notificationSvc.SendPendingApprovalNotification(transfer, approvalStatus)
 
@@ -373,17 +560,64 @@ func (w *TransferPollingWorker) checkPendingApprovals(ctx context.Context, trans
 	)
 }
 
+// expireApproval moves a transfer whose BitGo approval expired to expired and notifies its
+// requestor, unless it has already left the approval statuses
+func (w *TransferPollingWorker) expireApproval(transfer *models.TransferRequest, approval *bitgo.ApprovalStatus) {
+	reason := fmt.Sprintf("BitGo approval %s expired at %s with %d of %d approvals received",
+		approval.ID, approval.Expires.UTC().Format(time.RFC3339), approval.ReceivedApprovals, approval.RequiredApprovals)
+
+	var oldStatus models.TransferStatus
+	updated, err := repository.UpdateWithRetry(w.transferRepo, transfer, func(transfer *models.TransferRequest) bool {
+		if !statusIn(transfer.Status, approvalPendingStatuses) {
+			return false
+		}
+		oldStatus = transfer.Status
+		transfer.Status = models.TransferStatusExpired
+		transfer.StatusReason = &reason
+		return true
+	})
+	if err != nil {
+		w.logger.Error("Failed to expire transfer after BitGo approval expired",
+			"transfer_id", transfer.ID,
+			"approval_id", approval.ID,
+			"error", err,
+		)
+		return
+	}
+	if !updated {
+		return
+	}
+
+	w.notificationSvc.SendTransferExpiredNotification(transfer, reason)
+
+	w.logger.Info("Transfer expired after its BitGo approval expired",
+		"transfer_id", transfer.ID,
+		"approval_id", approval.ID,
+		"old_status", oldStatus,
+		"expires", approval.Expires,
+	)
+}
+
 // GetStats returns worker statistics
 func (w *TransferPollingWorker) GetStats() map[string]interface{} {
 	w.mu.RLock()
 	defer w.mu.RUnlock()
 
+	typeIntervals := make(map[string]string, len(w.config.TypePollIntervals))
+	for walletType, interval := range w.config.TypePollIntervals {
+		typeIntervals[string(walletType)] = interval.String()
+	}
+
 	return map[string]interface{}{
-		"is_running":         w.isRunning,
-		"poll_interval":      w.config.PollInterval.String(),
-		"batch_size":         w.config.BatchSize,
-		"concurrent_workers": w.config.ConcurrentWorkers,
-		"stale_threshold":    w.config.StaleThreshold.String(),
+		"is_running":          w.isRunning,
+		"poll_interval":       w.config.PollInterval.String(),
+		"type_poll_intervals": typeIntervals,
+		"batch_size":          w.config.BatchSize,
+		"concurrent_workers":  w.config.ConcurrentWorkers,
+		"stale_threshold":     w.config.StaleThreshold.String(),
+		"request_timeout":     w.config.RequestTimeout.String(),
+		"max_poll_backoff":    w.config.MaxPollBackoff.String(),
+		"max_poll_failures":   w.config.MaxPollFailures,
 	}
 }
 
@@ -391,16 +625,36 @@ func (w *TransferPollingWorker) GetStats() map[string]interface{} {
 func (w *TransferPollingWorker) HealthCheck() map[string]interface{} {
 	w.mu.RLock()
 	isRunning := w.isRunning
+	startedAt := w.startedAt
+	lastCycleAt := w.lastCycleAt
 	w.mu.RUnlock()
 
-	status := "stopped"
-	if isRunning {
-		status = "running"
+	now := w.config.Clock.Now()
+	health := map[string]interface{}{
+		"status":          "stopped",
+		"last_check":      now.UTC(),
+		"stall_threshold": w.stallThreshold().String(),
+		"configuration":   w.GetStats(),
 	}
 
-	return map[string]interface{}{
-		"status":        status,
-		"last_check":    time.Now().UTC(),
-		"configuration": w.GetStats(),
+	if !lastCycleAt.IsZero() {
+		health["last_successful_cycle"] = lastCycleAt.UTC()
 	}
+
+	if isRunning {
+		health["status"] = "running"
+
+		// A worker stuck on a hung call stops completing cycles; measure from start
+		// until the first cycle has finished
+		since := lastCycleAt
+		if since.IsZero() {
+			since = startedAt
+		}
+		if stalledFor := now.Sub(since); stalledFor > w.stallThreshold() {
+			health["status"] = "degraded"
+			health["stalled_for"] = stalledFor.Round(time.Second).String()
+		}
+	}
+
+	return health
 }
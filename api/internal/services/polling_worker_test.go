@@ -0,0 +1,349 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"bitgo-wallets-api/internal/bitgo"
+	"bitgo-wallets-api/internal/models"
+	"bitgo-wallets-api/internal/repository"
+
+	"github.com/google/uuid"
+)
+
+// testLogger discards log output
+type testLogger struct{}
+
+func (testLogger) Info(string, ...interface{})  {}
+func (testLogger) Warn(string, ...interface{})  {}
+func (testLogger) Error(string, ...interface{}) {}
+func (testLogger) Debug(string, ...interface{}) {}
+
+// updateRecordingRepo records the transfers saved through Update; other methods aren't used
+type updateRecordingRepo struct {
+	repository.TransferRequestRepository
+	updated []models.TransferRequest
+}
+
+func (r *updateRecordingRepo) Update(transfer *models.TransferRequest) error {
+	r.updated = append(r.updated, *transfer)
+	return nil
+}
+
+// expiryRecordingNotifier records expired-transfer notifications; other methods aren't used
+type expiryRecordingNotifier struct {
+	NotificationService
+	expired []string
+}
+
+func (n *expiryRecordingNotifier) SendTransferExpiredNotification(transfer *models.TransferRequest, reason string) {
+	n.expired = append(n.expired, reason)
+}
+
+func TestPolledTransitionAllowed(t *testing.T) {
+	tests := []struct {
+		from, to models.TransferStatus
+		want     bool
+	}{
+		{models.TransferStatusSigned, models.TransferStatusBroadcast, true},
+		{models.TransferStatusPendingApproval, models.TransferStatusApproved, true},
+		{models.TransferStatusBroadcast, models.TransferStatusConfirmed, true},
+		{models.TransferStatusBroadcast, models.TransferStatusFailed, true},
+		{models.TransferStatusBroadcast, models.TransferStatusPendingApproval, false},
+		{models.TransferStatusBroadcast, models.TransferStatusSubmitted, false},
+		{models.TransferStatusConfirmed, models.TransferStatusBroadcast, false},
+		{models.TransferStatusCancelled, models.TransferStatusApproved, false},
+		{models.TransferStatusFailed, models.TransferStatusConfirmed, false},
+	}
+
+	for _, tt := range tests {
+		if got := polledTransitionAllowed(tt.from, tt.to); got != tt.want {
+			t.Errorf("polledTransitionAllowed(%s, %s) = %v, want %v", tt.from, tt.to, got, tt.want)
+		}
+	}
+}
+
+func TestPollBackoffGrowsToMaximum(t *testing.T) {
+	w := &TransferPollingWorker{config: PollingWorkerConfig{
+		PollInterval:      time.Minute,
+		TypePollIntervals: map[models.WalletType]time.Duration{models.WalletTypeCold: 5 * time.Minute},
+		MaxPollBackoff:    time.Hour,
+	}}
+
+	tests := []struct {
+		transferType models.WalletType
+		failures     int
+		want         time.Duration
+	}{
+		{models.WalletTypeHot, 1, time.Minute},
+		{models.WalletTypeHot, 2, 2 * time.Minute},
+		{models.WalletTypeHot, 4, 8 * time.Minute},
+		{models.WalletTypeHot, 20, time.Hour},
+		{models.WalletTypeCold, 1, 5 * time.Minute},
+		{models.WalletTypeCold, 3, 20 * time.Minute},
+		{models.WalletTypeCold, 5, time.Hour},
+	}
+
+	for _, tt := range tests {
+		if got := w.pollBackoff(tt.transferType, tt.failures); got != tt.want {
+			t.Errorf("pollBackoff(%s, %d) = %s, want %s", tt.transferType, tt.failures, got, tt.want)
+		}
+	}
+}
+
+func TestExpireApprovalMovesPendingTransferToExpired(t *testing.T) {
+	repo := &updateRecordingRepo{}
+	notifier := &expiryRecordingNotifier{}
+	w := &TransferPollingWorker{logger: testLogger{}, transferRepo: repo, notificationSvc: notifier}
+
+	approval := &bitgo.ApprovalStatus{
+		ID:                "approval-1",
+		RequiredApprovals: 2,
+		ReceivedApprovals: 1,
+		IsExpired:         true,
+		Expires:           time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC),
+	}
+	transfer := &models.TransferRequest{ID: uuid.New(), Status: models.TransferStatusPendingApproval}
+
+	w.expireApproval(transfer, approval)
+
+	if transfer.Status != models.TransferStatusExpired {
+		t.Fatalf("status = %s, want %s", transfer.Status, models.TransferStatusExpired)
+	}
+	if !statusIn(transfer.Status, terminalTransferStatuses) {
+		t.Errorf("expired status %s is not terminal", transfer.Status)
+	}
+	if transfer.StatusReason == nil || *transfer.StatusReason == "" {
+		t.Error("expired transfer has no status reason")
+	}
+	if len(repo.updated) != 1 || len(notifier.expired) != 1 {
+		t.Errorf("saved %d update(s) and sent %d notification(s), want 1 of each", len(repo.updated), len(notifier.expired))
+	}
+}
+
+func TestExpireApprovalLeavesSettledTransferAlone(t *testing.T) {
+	repo := &updateRecordingRepo{}
+	notifier := &expiryRecordingNotifier{}
+	w := &TransferPollingWorker{logger: testLogger{}, transferRepo: repo, notificationSvc: notifier}
+
+	transfer := &models.TransferRequest{ID: uuid.New(), Status: models.TransferStatusBroadcast}
+	w.expireApproval(transfer, &bitgo.ApprovalStatus{ID: "approval-1", IsExpired: true, Expires: time.Now()})
+
+	if transfer.Status != models.TransferStatusBroadcast {
+		t.Errorf("status = %s, want %s", transfer.Status, models.TransferStatusBroadcast)
+	}
+	if len(repo.updated) != 0 || len(notifier.expired) != 0 {
+		t.Errorf("saved %d update(s) and sent %d notification(s), want none", len(repo.updated), len(notifier.expired))
+	}
+}
+
+// pollCutoffRepo records the per-type cutoffs the worker asks ListDueForPolling for; other
+// methods aren't used
+type pollCutoffRepo struct {
+	repository.TransferRequestRepository
+	dueBefore map[models.WalletType]time.Time
+}
+
+func (r *pollCutoffRepo) ListDueForPolling(statuses []models.TransferStatus, dueBefore map[models.WalletType]time.Time, now time.Time, after *repository.TransferCursor, limit int) ([]*models.TransferRequest, error) {
+	r.dueBefore = dueBefore
+	return nil, nil
+}
+
+func TestPollTransfersUsesEachTypesInterval(t *testing.T) {
+	now := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	clock := &testClock{}
+	clock.Set(now)
+	config := DefaultPollingWorkerConfig()
+	config.Clock = clock
+	repo := &pollCutoffRepo{}
+	w := NewTransferPollingWorker(config, testLogger{}, nil, repo, nil, nopNotifier{})
+
+	w.pollTransfers()
+
+	for walletType, interval := range config.TypePollIntervals {
+		if got, want := repo.dueBefore[walletType], now.Add(-interval); !got.Equal(want) {
+			t.Errorf("%s transfers due before %s, want %s", walletType, got, want)
+		}
+	}
+	// A cold transfer last polled a minute ago isn't due, though a hot one would be
+	polledAt := now.Add(-time.Minute)
+	if !polledAt.After(repo.dueBefore[models.WalletTypeCold]) {
+		t.Error("cold transfer polled a minute ago is due again")
+	}
+	if polledAt.After(repo.dueBefore[models.WalletTypeHot]) {
+		t.Error("hot transfer polled a minute ago isn't due")
+	}
+}
+
+// cursorPollRepo serves every transfer as due for polling, paged by the cursor in
+// (updated_at, id) order like the repository, and records which were marked polled
+type cursorPollRepo struct {
+	repository.TransferRequestRepository
+	transfers []*models.TransferRequest
+	polled    map[uuid.UUID]int
+}
+
+func (r *cursorPollRepo) ListDueForPolling(statuses []models.TransferStatus, dueBefore map[models.WalletType]time.Time, now time.Time, after *repository.TransferCursor, limit int) ([]*models.TransferRequest, error) {
+	var due []*models.TransferRequest
+	for _, transfer := range r.transfers {
+		if after != nil && !transferAfterCursor(transfer, after) {
+			continue
+		}
+		due = append(due, transfer)
+		if len(due) == limit {
+			break
+		}
+	}
+	return due, nil
+}
+
+func (r *cursorPollRepo) MarkPolled(id uuid.UUID, polledAt time.Time) error {
+	r.polled[id]++
+	return nil
+}
+
+func transferAfterCursor(transfer *models.TransferRequest, after *repository.TransferCursor) bool {
+	if !transfer.UpdatedAt.Equal(after.UpdatedAt) {
+		return transfer.UpdatedAt.After(after.UpdatedAt)
+	}
+	return transfer.ID.String() > after.ID.String()
+}
+
+func TestPollTransfersCyclesThroughTheWholeBacklog(t *testing.T) {
+	updatedAt := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	repo := &cursorPollRepo{polled: make(map[uuid.UUID]int)}
+	for i := 0; i < 5; i++ {
+		// Two share an updated_at so the cursor has to break the tie on ID
+		repo.transfers = append(repo.transfers, &models.TransferRequest{
+			ID:           uuid.MustParse(fmt.Sprintf("00000000-0000-0000-0000-%012d", i)),
+			TransferType: models.WalletTypeHot,
+			Status:       models.TransferStatusBroadcast,
+			UpdatedAt:    updatedAt.Add(time.Duration(i/2) * time.Minute),
+		})
+	}
+
+	config := DefaultPollingWorkerConfig()
+	config.BatchSize = 2
+	w := NewTransferPollingWorker(config, testLogger{}, nil, repo, nil, nopNotifier{})
+
+	// Three cycles of two cover all five, the third wrapping back to the start
+	for cycle := 0; cycle < 3; cycle++ {
+		w.pollTransfers()
+		for len(w.workQueue) > 0 {
+			<-w.workQueue
+		}
+	}
+
+	for _, transfer := range repo.transfers {
+		if repo.polled[transfer.ID] == 0 {
+			t.Errorf("transfer %s was never polled", transfer.ID)
+		}
+	}
+	if w.pollCursor != nil {
+		t.Errorf("cursor = %+v after the last page, want it reset", w.pollCursor)
+	}
+
+	// The next cycle starts from the head of the backlog again
+	w.pollTransfers()
+	if got := repo.polled[repo.transfers[0].ID]; got != 2 {
+		t.Errorf("first transfer polled %d times, want 2 once the cursor wraps", got)
+	}
+}
+
+func TestHealthCheckReportsStalledWorkerAsDegraded(t *testing.T) {
+	now := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	interval := 30 * time.Second
+
+	tests := []struct {
+		name        string
+		running     bool
+		startedAt   time.Time
+		lastCycleAt time.Time
+		want        string
+	}{
+		{name: "recent cycle", running: true, startedAt: now.Add(-time.Hour), lastCycleAt: now.Add(-interval), want: "running"},
+		{name: "stale cycle", running: true, startedAt: now.Add(-time.Hour), lastCycleAt: now.Add(-4 * interval), want: "degraded"},
+		{name: "just started", running: true, startedAt: now.Add(-interval), want: "running"},
+		{name: "never completed a cycle", running: true, startedAt: now.Add(-time.Hour), want: "degraded"},
+		{name: "stopped", startedAt: now.Add(-time.Hour), lastCycleAt: now.Add(-time.Hour), want: "stopped"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			clock := &testClock{}
+			clock.Set(now)
+			w := &TransferPollingWorker{
+				logger:      testLogger{},
+				config:      PollingWorkerConfig{PollInterval: interval, StallMultiplier: 3, Clock: clock},
+				isRunning:   tt.running,
+				startedAt:   tt.startedAt,
+				lastCycleAt: tt.lastCycleAt,
+			}
+
+			health := w.HealthCheck()
+			if health["status"] != tt.want {
+				t.Errorf("status = %v, want %s", health["status"], tt.want)
+			}
+			if _, stalled := health["stalled_for"]; stalled != (tt.want == "degraded") {
+				t.Errorf("stalled_for reported = %v, want %v", stalled, tt.want == "degraded")
+			}
+		})
+	}
+}
+
+// confirmationsClient reports every transfer as confirmed with a settable confirmation count
+type confirmationsClient struct {
+	bitgo.BitGoAPI
+	confirmations int
+}
+
+func (c *confirmationsClient) GetTransfer(ctx context.Context, walletID, coin, transferID string) (*bitgo.Transfer, error) {
+	return &bitgo.Transfer{ID: transferID, Coin: coin, Wallet: walletID, TxID: "0xabc", State: bitgo.TransferStatusConfirmed, Confirmations: c.confirmations}, nil
+}
+
+func TestPolledETHTransferConfirmsAtRequiredDepth(t *testing.T) {
+	wallet := &models.Wallet{ID: uuid.New(), BitgoWalletID: "bitgo-eth-1", Coin: "eth", WalletType: models.WalletTypeHot}
+	bitgoTransferID := "bitgo-transfer-1"
+	transfer := &models.TransferRequest{
+		ID:              uuid.New(),
+		WalletID:        wallet.ID,
+		Coin:            "eth",
+		TransferType:    models.WalletTypeHot,
+		Status:          models.TransferStatusSigned,
+		BitgoTransferID: &bitgoTransferID,
+		Version:         1,
+	}
+	repo := newMemTransferRepo(transfer)
+	client := &confirmationsClient{}
+	w := &TransferPollingWorker{
+		config:          PollingWorkerConfig{Clock: RealClock},
+		logger:          testLogger{},
+		bitgoClient:     client,
+		transferRepo:    repo,
+		notificationSvc: nopNotifier{},
+	}
+
+	steps := []struct {
+		confirmations int
+		want          models.TransferStatus
+	}{
+		{confirmations: 3, want: models.TransferStatusBroadcast},
+		{confirmations: 12, want: models.TransferStatusConfirmed},
+	}
+	for _, step := range steps {
+		client.confirmations = step.confirmations
+		current, _ := repo.GetByID(transfer.ID)
+		if _, err := w.updateTransferStatus(context.Background(), current, wallet); err != nil {
+			t.Fatalf("updateTransferStatus() error = %v", err)
+		}
+		stored, _ := repo.GetByID(transfer.ID)
+		if stored.Status != step.want {
+			t.Errorf("with %d confirmations status = %s, want %s", step.confirmations, stored.Status, step.want)
+		}
+		if (stored.CompletedAt != nil) != (step.want == models.TransferStatusConfirmed) {
+			t.Errorf("with %d confirmations completed_at = %v", step.confirmations, stored.CompletedAt)
+		}
+	}
+}
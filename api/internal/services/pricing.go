@@ -0,0 +1,96 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+)
+
+// PriceOracle returns the USD price of one unit of a coin
+type PriceOracle interface {
+	USDPrice(ctx context.Context, coin string) (float64, error)
+}
+
+// PriceOracleFunc adapts a function to the PriceOracle interface
+type PriceOracleFunc func(ctx context.Context, coin string) (float64, error)
+
+// USDPrice calls f
+func (f PriceOracleFunc) USDPrice(ctx context.Context, coin string) (float64, error) {
+	return f(ctx, coin)
+}
+
+type cachedPrice struct {
+	price     float64
+	fetchedAt time.Time
+}
+
+// CachedPriceOracle caches prices from another oracle for a TTL so validation doesn't
+// fetch a price for every transfer
+type CachedPriceOracle struct {
+	source PriceOracle
+	ttl    time.Duration
+
+	mu     sync.RWMutex
+	prices map[string]cachedPrice
+}
+
+// NewCachedPriceOracle wraps source with a per-coin price cache
+func NewCachedPriceOracle(source PriceOracle, ttl time.Duration) *CachedPriceOracle {
+	return &CachedPriceOracle{
+		source: source,
+		ttl:    ttl,
+		prices: make(map[string]cachedPrice),
+	}
+}
+
+// USDPrice returns the cached price for coin, fetching it from the source once it has expired
+func (o *CachedPriceOracle) USDPrice(ctx context.Context, coin string) (float64, error) {
+	coin = strings.ToLower(coin)
+
+	o.mu.RLock()
+	cached, ok := o.prices[coin]
+	o.mu.RUnlock()
+	if ok && time.Since(cached.fetchedAt) < o.ttl {
+		return cached.price, nil
+	}
+
+	price, err := o.source.USDPrice(ctx, coin)
+	if err != nil {
+		return 0, err
+	}
+	if price <= 0 {
+		return 0, fmt.Errorf("invalid USD price %v for %s", price, coin)
+	}
+
+	o.mu.Lock()
+	o.prices[coin] = cachedPrice{price: price, fetchedAt: time.Now()}
+	o.mu.Unlock()
+
+	return price, nil
+}
+
+// validateUSDLimit rejects amounts whose USD value exceeds maxUSD. A zero limit or nil
+// oracle disables the check; if the price can't be fetched the transfer is rejected.
+func validateUSDLimit(ctx context.Context, oracle PriceOracle, maxUSD float64, amountStr, coin string) error {
+	if maxUSD <= 0 || oracle == nil {
+		return nil
+	}
+
+	amount, err := parseAmount(amountStr)
+	if err != nil {
+		return fmt.Errorf("invalid amount format")
+	}
+
+	price, err := oracle.USDPrice(ctx, coin)
+	if err != nil {
+		return fmt.Errorf("unable to determine USD value of transfer: %w", err)
+	}
+
+	if usdValue := amount * price; usdValue > maxUSD {
+		return fmt.Errorf("amount is worth $%.2f, exceeding the single transfer limit of $%.2f", usdValue, maxUSD)
+	}
+
+	return nil
+}
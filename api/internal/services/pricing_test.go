@@ -0,0 +1,84 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"bitgo-wallets-api/internal/bitgo"
+)
+
+// fixedPrice prices every coin at price
+func fixedPrice(price float64) PriceOracle {
+	return PriceOracleFunc(func(context.Context, string) (float64, error) { return price, nil })
+}
+
+func TestValidateUSDLimit(t *testing.T) {
+	unavailable := PriceOracleFunc(func(context.Context, string) (float64, error) {
+		return 0, errors.New("price feed down")
+	})
+
+	tests := []struct {
+		name    string
+		oracle  PriceOracle
+		maxUSD  float64
+		amount  string
+		wantErr bool
+	}{
+		{name: "under the limit", oracle: fixedPrice(40000), maxUSD: 50000, amount: "1.2"},
+		{name: "at the limit", oracle: fixedPrice(40000), maxUSD: 50000, amount: "1.25"},
+		{name: "over the limit", oracle: fixedPrice(40000), maxUSD: 50000, amount: "1.3", wantErr: true},
+		{name: "price unavailable", oracle: unavailable, maxUSD: 50000, amount: "0.1", wantErr: true},
+		{name: "no limit", oracle: unavailable, amount: "1000"},
+		{name: "no oracle", maxUSD: 50000, amount: "1000"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := validateUSDLimit(context.Background(), tt.oracle, tt.maxUSD, tt.amount, "btc")
+			if (err != nil) != tt.wantErr {
+				t.Errorf("validateUSDLimit() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestWarmValidationAppliesUSDLimit(t *testing.T) {
+	wallet := newTestWarmWallet()
+	config := DefaultWarmWalletConfig()
+	config.MaxSingleTransferUSD = 50000
+
+	tests := []struct {
+		amount  string
+		wantErr bool
+	}{
+		{amount: "1.2"},
+		{amount: "1.3", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.amount, func(t *testing.T) {
+			wws := NewWarmWalletService(
+				bitgo.NewSimulatedClient(bitgo.SimulationConfig{}, testLogger{}),
+				newMemWalletRepo(wallet),
+				newMemTransferRepo(),
+				nopNotifier{},
+				testLogger{},
+				config,
+				nil,
+				fixedPrice(40000),
+				nil,
+			)
+
+			var usdErrors int
+			for _, validationErr := range wws.ValidateWarmTransferRequest(context.Background(), newTestWarmRequest(wallet, tt.amount)) {
+				if validationErr.Field == "amountString" {
+					usdErrors++
+				}
+			}
+			if (usdErrors > 0) != tt.wantErr {
+				t.Errorf("%d amountString errors for %s BTC at $40000, want errors = %v", usdErrors, tt.amount, tt.wantErr)
+			}
+		})
+	}
+}
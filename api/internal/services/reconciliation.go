@@ -0,0 +1,182 @@
+package services
+
+import (
+	"fmt"
+	"time"
+
+	"bitgo-wallets-api/internal/bitgo"
+	"bitgo-wallets-api/internal/models"
+	"bitgo-wallets-api/internal/repository"
+
+	"github.com/google/uuid"
+)
+
+// TransferDrift describes a local transfer whose status disagrees with BitGo
+type TransferDrift struct {
+	TransferID      uuid.UUID             `json:"transfer_id"`
+	BitgoTransferID string                `json:"bitgo_transfer_id"`
+	Txid            string                `json:"txid,omitempty"`
+	LocalStatus     models.TransferStatus `json:"local_status"`
+	BitgoState      bitgo.TransferStatus  `json:"bitgo_state"`
+	ExpectedStatus  models.TransferStatus `json:"expected_status"`
+	Corrected       bool                  `json:"corrected"`
+	Error           string                `json:"error,omitempty"`
+
+	transfer      *models.TransferRequest
+	bitgoTransfer *bitgo.Transfer
+}
+
+// ReconciliationReport summarizes a comparison of local transfers against BitGo
+type ReconciliationReport struct {
+	WalletID       uuid.UUID       `json:"wallet_id"`
+	DryRun         bool            `json:"dry_run"`
+	LocalCount     int             `json:"local_count"`
+	BitgoCount     int             `json:"bitgo_count"`
+	MatchedCount   int             `json:"matched_count"`
+	UnmatchedBitgo []string        `json:"unmatched_bitgo_transfers"`
+	Drifts         []TransferDrift `json:"drifts"`
+}
+
+// TransferStatusFromCanonical maps a normalized BitGo status onto our transfer status.
+// It returns false for states that don't tell us anything definite (building, unknown).
+func TransferStatusFromCanonical(status bitgo.CanonicalTransferStatus) (models.TransferStatus, bool) {
+	switch status {
+	case bitgo.CanonicalStatusConfirmed:
+		return models.TransferStatusConfirmed, true
+	case bitgo.CanonicalStatusFailed:
+		return models.TransferStatusFailed, true
+	case bitgo.CanonicalStatusRejected:
+		return models.TransferStatusRejected, true
+	case bitgo.CanonicalStatusCanceled:
+		return models.TransferStatusCancelled, true
+	case bitgo.CanonicalStatusBroadcast:
+		return models.TransferStatusBroadcast, true
+	case bitgo.CanonicalStatusWaitingApproval:
+		return models.TransferStatusPendingApproval, true
+	case bitgo.CanonicalStatusSigning:
+		return models.TransferStatusApproved, true
+	case bitgo.CanonicalStatusPending, bitgo.CanonicalStatusSubmitting:
+		return models.TransferStatusSubmitted, true
+	default:
+		return "", false
+	}
+}
+
+// ReconcileTransfers matches local transfers to BitGo transfers by BitGo transfer ID or txid
+// and reports every matched transfer whose local status differs from BitGo's
+func ReconcileTransfers(walletID uuid.UUID, local []*models.TransferRequest, remote []bitgo.Transfer) *ReconciliationReport {
+	report := &ReconciliationReport{
+		WalletID:       walletID,
+		DryRun:         true,
+		LocalCount:     len(local),
+		BitgoCount:     len(remote),
+		UnmatchedBitgo: []string{},
+		Drifts:         []TransferDrift{},
+	}
+
+	byTransferID := make(map[string]*models.TransferRequest)
+	byTxid := make(map[string]*models.TransferRequest)
+	for _, transfer := range local {
+		if transfer.BitgoTransferID != nil && *transfer.BitgoTransferID != "" {
+			byTransferID[*transfer.BitgoTransferID] = transfer
+		}
+		if transfer.BitgoTxid != nil && *transfer.BitgoTxid != "" {
+			byTxid[*transfer.BitgoTxid] = transfer
+		}
+		if transfer.TransactionHash != nil && *transfer.TransactionHash != "" {
+			byTxid[*transfer.TransactionHash] = transfer
+		}
+	}
+
+	statusMapper := bitgo.NewStatusMapper()
+	for i := range remote {
+		bitgoTransfer := &remote[i]
+
+		transfer, ok := byTransferID[bitgoTransfer.ID]
+		if !ok && bitgoTransfer.TxID != "" {
+			transfer, ok = byTxid[bitgoTransfer.TxID]
+		}
+		if !ok {
+			report.UnmatchedBitgo = append(report.UnmatchedBitgo, bitgoTransfer.ID)
+			continue
+		}
+		report.MatchedCount++
+
+		expected, known := TransferStatusFromCanonical(statusMapper.NormalizeTransferStatus(bitgoTransfer.State, bitgoTransfer))
+		if !known || statusesEquivalent(transfer.Status, expected) {
+			continue
+		}
+
+		report.Drifts = append(report.Drifts, TransferDrift{
+			TransferID:      transfer.ID,
+			BitgoTransferID: bitgoTransfer.ID,
+			Txid:            bitgoTransfer.TxID,
+			LocalStatus:     transfer.Status,
+			BitgoState:      bitgoTransfer.State,
+			ExpectedStatus:  expected,
+			transfer:        transfer,
+			bitgoTransfer:   bitgoTransfer,
+		})
+	}
+
+	return report
+}
+
+// Save updates the drifted transfer to match BitGo and saves it, reapplying the correction to
+// the latest row if the transfer was updated concurrently. It reports false, saving nothing,
+// when the transfer has moved off the drifted status since the report was made; the next
+// reconciliation looks at it again.
+func (d *TransferDrift) Save(repo repository.TransferRequestRepository, now time.Time) (bool, error) {
+	return repository.UpdateWithRetry(repo, d.transfer, func(transfer *models.TransferRequest) bool {
+		if transfer.Status != d.LocalStatus {
+			return false
+		}
+		d.apply(transfer, now)
+		return true
+	})
+}
+
+// Transfer returns the drifted transfer, as last loaded or saved
+func (d *TransferDrift) Transfer() *models.TransferRequest {
+	return d.transfer
+}
+
+// apply updates the transfer to match BitGo
+func (d *TransferDrift) apply(transfer *models.TransferRequest, now time.Time) {
+	transfer.Status = d.ExpectedStatus
+
+	reason := fmt.Sprintf("Reconciled with BitGo state %s (was %s)", d.BitgoState, d.LocalStatus)
+	transfer.StatusReason = &reason
+
+	if transfer.BitgoTransferID == nil && d.BitgoTransferID != "" {
+		bitgoTransferID := d.BitgoTransferID
+		transfer.BitgoTransferID = &bitgoTransferID
+	}
+	if transfer.BitgoTxid == nil && d.Txid != "" {
+		txid := d.Txid
+		transfer.BitgoTxid = &txid
+	}
+
+	switch d.ExpectedStatus {
+	case models.TransferStatusConfirmed:
+		if transfer.CompletedAt == nil {
+			completedAt := now
+			if d.bitgoTransfer.ConfirmedTime != nil {
+				completedAt = *d.bitgoTransfer.ConfirmedTime
+			}
+			transfer.CompletedAt = &completedAt
+		}
+	case models.TransferStatusFailed:
+		if transfer.FailedAt == nil {
+			transfer.FailedAt = &now
+		}
+	}
+}
+
+// statusesEquivalent treats our completed status as agreeing with BitGo's confirmed state
+func statusesEquivalent(local, expected models.TransferStatus) bool {
+	if local == expected {
+		return true
+	}
+	return local == models.TransferStatusCompleted && expected == models.TransferStatusConfirmed
+}
@@ -0,0 +1,108 @@
+package services
+
+import (
+	"time"
+
+	"bitgo-wallets-api/internal/models"
+)
+
+// SLATargets holds the SLA durations that apply to a transfer
+type SLATargets struct {
+	InitialResponse time.Duration `json:"initialResponse"`
+	Processing      time.Duration `json:"processing"`
+	Completion      time.Duration `json:"completion"`
+}
+
+// SLADeadlines are the absolute SLA deadlines computed for a transfer when it is created
+type SLADeadlines struct {
+	UrgencyLevel      string    `json:"urgencyLevel"`
+	InitialResponseBy time.Time `json:"initialResponseBy"`
+	ProcessingBy      time.Time `json:"processingBy"`
+	CompletionBy      time.Time `json:"completionBy"`
+}
+
+// Metadata keys used to store SLA deadlines on a transfer
+const (
+	metadataUrgencyLevel      = "urgency_level"
+	metadataInitialResponseBy = "sla_initial_response_by"
+	metadataProcessingBy      = "sla_processing_by"
+	metadataCompletionBy      = "sla_completion_by"
+)
+
+// DefaultUrgencySLAMultipliers tightens SLAs for urgent transfers and relaxes them for low urgency
+func DefaultUrgencySLAMultipliers() map[string]float64 {
+	return map[string]float64{
+		"low":      1.5,
+		"normal":   1.0,
+		"high":     0.5,
+		"critical": 0.25,
+	}
+}
+
+// effectiveSLATargets resolves the SLA targets for an urgency level. An explicit override wins;
+// otherwise the base targets are scaled by the urgency's multiplier (1.0 if none is configured).
+func effectiveSLATargets(base SLATargets, urgency string, multipliers map[string]float64, overrides map[string]SLATargets) SLATargets {
+	if override, ok := overrides[urgency]; ok {
+		return override
+	}
+
+	multiplier, ok := multipliers[urgency]
+	if !ok || multiplier <= 0 {
+		return base
+	}
+
+	scale := func(d time.Duration) time.Duration {
+		return time.Duration(float64(d) * multiplier)
+	}
+	return SLATargets{
+		InitialResponse: scale(base.InitialResponse),
+		Processing:      scale(base.Processing),
+		Completion:      scale(base.Completion),
+	}
+}
+
+// computeSLADeadlines turns SLA targets into absolute deadlines from the transfer's start time
+func computeSLADeadlines(urgency string, targets SLATargets, start time.Time) SLADeadlines {
+	return SLADeadlines{
+		UrgencyLevel:      urgency,
+		InitialResponseBy: start.Add(targets.InitialResponse),
+		ProcessingBy:      start.Add(targets.Processing),
+		CompletionBy:      start.Add(targets.Completion),
+	}
+}
+
+// setSLAMetadata stores SLA deadlines in the transfer's metadata
+func setSLAMetadata(transfer *models.TransferRequest, deadlines SLADeadlines) {
+	if transfer.Metadata == nil {
+		transfer.Metadata = models.JSON{}
+	}
+	transfer.Metadata[metadataUrgencyLevel] = deadlines.UrgencyLevel
+	transfer.Metadata[metadataInitialResponseBy] = deadlines.InitialResponseBy.UTC().Format(time.RFC3339)
+	transfer.Metadata[metadataProcessingBy] = deadlines.ProcessingBy.UTC().Format(time.RFC3339)
+	transfer.Metadata[metadataCompletionBy] = deadlines.CompletionBy.UTC().Format(time.RFC3339)
+}
+
+// transferSLADeadlines reads a transfer's stored SLA deadlines, falling back to the default
+// targets from its creation time for transfers created before deadlines were stored
+func transferSLADeadlines(transfer *models.TransferRequest, fallback SLATargets) SLADeadlines {
+	deadlines := computeSLADeadlines("", fallback, transfer.CreatedAt)
+	if transfer.Metadata == nil {
+		return deadlines
+	}
+
+	if urgency, ok := transfer.Metadata[metadataUrgencyLevel].(string); ok {
+		deadlines.UrgencyLevel = urgency
+	}
+	readTime := func(key string, target *time.Time) {
+		if value, ok := transfer.Metadata[key].(string); ok {
+			if parsed, err := time.Parse(time.RFC3339, value); err == nil {
+				*target = parsed
+			}
+		}
+	}
+	readTime(metadataInitialResponseBy, &deadlines.InitialResponseBy)
+	readTime(metadataProcessingBy, &deadlines.ProcessingBy)
+	readTime(metadataCompletionBy, &deadlines.CompletionBy)
+
+	return deadlines
+}
@@ -0,0 +1,166 @@
+package services
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"bitgo-wallets-api/internal/models"
+
+	"github.com/google/uuid"
+)
+
+func TestCriticalTransferGetsTighterSLADeadlines(t *testing.T) {
+	withoutSimulatedDelay(t)
+	now := time.Date(2024, 1, 1, 9, 0, 0, 0, time.UTC)
+	clock := &testClock{}
+	clock.Set(now)
+
+	config := DefaultWarmWalletConfig()
+	config.Clock = clock
+	config.InitialResponseSLA = time.Hour
+	config.ProcessingSLA = 4 * time.Hour
+	config.CompletionSLA = 8 * time.Hour
+	wallet := newTestWarmWallet()
+	wws, repo := newTestWarmWalletService(wallet, config)
+
+	deadlines := make(map[string]SLADeadlines)
+	for _, urgency := range []string{"low", "critical"} {
+		request := newTestWarmRequest(wallet, "0.1")
+		request.UrgencyLevel = urgency
+		transfer, err := wws.CreateWarmTransferRequest(context.Background(), request, uuid.New())
+		if err != nil {
+			t.Fatalf("%s: CreateWarmTransferRequest() error = %v", urgency, err)
+		}
+		stored, _ := repo.GetByID(transfer.ID)
+		deadlines[urgency] = transferSLADeadlines(stored, SLATargets{})
+	}
+
+	// Default multipliers: 1.5 for low, 0.25 for critical
+	tests := []struct {
+		urgency string
+		want    SLADeadlines
+	}{
+		{urgency: "low", want: SLADeadlines{UrgencyLevel: "low", InitialResponseBy: now.Add(90 * time.Minute), ProcessingBy: now.Add(6 * time.Hour), CompletionBy: now.Add(12 * time.Hour)}},
+		{urgency: "critical", want: SLADeadlines{UrgencyLevel: "critical", InitialResponseBy: now.Add(15 * time.Minute), ProcessingBy: now.Add(time.Hour), CompletionBy: now.Add(2 * time.Hour)}},
+	}
+	for _, tt := range tests {
+		got := deadlines[tt.urgency]
+		if got.UrgencyLevel != tt.want.UrgencyLevel || !got.InitialResponseBy.Equal(tt.want.InitialResponseBy) ||
+			!got.ProcessingBy.Equal(tt.want.ProcessingBy) || !got.CompletionBy.Equal(tt.want.CompletionBy) {
+			t.Errorf("%s deadlines = %+v, want %+v", tt.urgency, got, tt.want)
+		}
+	}
+}
+
+func TestEffectiveSLATargetsPrefersOverride(t *testing.T) {
+	base := SLATargets{InitialResponse: time.Hour, Processing: 4 * time.Hour, Completion: 8 * time.Hour}
+	override := SLATargets{InitialResponse: time.Minute, Processing: 5 * time.Minute, Completion: 10 * time.Minute}
+	overrides := map[string]SLATargets{"critical": override}
+
+	if got := effectiveSLATargets(base, "critical", DefaultUrgencySLAMultipliers(), overrides); got != override {
+		t.Errorf("critical targets = %+v, want the override %+v", got, override)
+	}
+	if got := effectiveSLATargets(base, "unknown", DefaultUrgencySLAMultipliers(), overrides); got != base {
+		t.Errorf("unknown urgency targets = %+v, want the base %+v", got, base)
+	}
+}
+
+// slaCounts is the part of an SLA status report that changes as a transfer ages
+type slaCounts struct {
+	atRisk, escalated, breached int
+}
+
+func slaCountsOf(t *testing.T, status map[string]interface{}) slaCounts {
+	t.Helper()
+	counts := slaCounts{}
+	for key, target := range map[string]*int{"atRisk": &counts.atRisk, "escalated": &counts.escalated, "slaBreached": &counts.breached} {
+		value, ok := status[key].(int)
+		if !ok {
+			t.Fatalf("status[%q] = %v, want an int", key, status[key])
+		}
+		*target = value
+	}
+	return counts
+}
+
+func TestColdSLAStatusFollowsTheClock(t *testing.T) {
+	created := time.Date(2024, 1, 1, 9, 0, 0, 0, time.UTC)
+	clock := &testClock{}
+	clock.Set(created)
+
+	// Defaults: 72h completion (at risk past half of it), escalation after 48h
+	config := DefaultColdWalletConfig()
+	config.Clock = clock
+	cws, repo, transferID := newTestOfflineWorkflow(config)
+	repo.transfers[transferID].CreatedAt = created
+
+	steps := []struct {
+		elapsed time.Duration
+		want    slaCounts
+	}{
+		{elapsed: time.Hour, want: slaCounts{}},
+		{elapsed: 36 * time.Hour, want: slaCounts{}},
+		{elapsed: 37 * time.Hour, want: slaCounts{atRisk: 1}},
+		{elapsed: 48 * time.Hour, want: slaCounts{atRisk: 1}},
+		{elapsed: 49 * time.Hour, want: slaCounts{atRisk: 1, escalated: 1}},
+		{elapsed: 73 * time.Hour, want: slaCounts{escalated: 1, breached: 1}},
+	}
+	for _, step := range steps {
+		clock.Set(created.Add(step.elapsed))
+		status, err := cws.GetColdTransfersSLAStatus(context.Background())
+		if err != nil {
+			t.Fatalf("after %s: GetColdTransfersSLAStatus() error = %v", step.elapsed, err)
+		}
+		if got := slaCountsOf(t, status); got != step.want {
+			t.Errorf("after %s: counts = %+v, want %+v", step.elapsed, got, step.want)
+		}
+	}
+}
+
+func TestWarmSLAStatusFollowsTheClock(t *testing.T) {
+	created := time.Date(2024, 1, 1, 9, 0, 0, 0, time.UTC)
+	clock := &testClock{}
+	clock.Set(created)
+
+	// Defaults: 12h completion (at risk past half of it), escalation after 6h
+	config := DefaultWarmWalletConfig()
+	config.Clock = clock
+	wallet := newTestWarmWallet()
+	wws, repo := newTestWarmWalletService(wallet, config)
+	transfer := &models.TransferRequest{
+		ID:                uuid.New(),
+		WalletID:          wallet.ID,
+		TransferType:      models.WalletTypeWarm,
+		Status:            models.TransferStatusPendingApproval,
+		RequiredApprovals: 1,
+		CreatedAt:         created,
+	}
+	repo.transfers[transfer.ID] = transfer
+
+	steps := []struct {
+		elapsed    time.Duration
+		escalation bool
+		want       slaCounts
+	}{
+		{elapsed: time.Hour, escalation: true, want: slaCounts{}},
+		{elapsed: 6 * time.Hour, escalation: true, want: slaCounts{}},
+		{elapsed: 7 * time.Hour, escalation: true, want: slaCounts{atRisk: 1, escalated: 1}},
+		{elapsed: 7 * time.Hour, escalation: false, want: slaCounts{atRisk: 1}},
+		{elapsed: 13 * time.Hour, escalation: true, want: slaCounts{escalated: 1, breached: 1}},
+	}
+	for _, step := range steps {
+		flags := DefaultFeatureFlags()
+		flags.Escalation = step.escalation
+		wws.flags = NewFeatureFlagStore(flags)
+		clock.Set(created.Add(step.elapsed))
+
+		status, err := wws.GetWarmTransfersSLAStatus(context.Background())
+		if err != nil {
+			t.Fatalf("after %s: GetWarmTransfersSLAStatus() error = %v", step.elapsed, err)
+		}
+		if got := slaCountsOf(t, status); got != step.want {
+			t.Errorf("after %s (escalation %t): counts = %+v, want %+v", step.elapsed, step.escalation, got, step.want)
+		}
+	}
+}
@@ -0,0 +1,622 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"bitgo-wallets-api/internal/bitgo"
+	"bitgo-wallets-api/internal/models"
+	"bitgo-wallets-api/internal/repository"
+)
+
+// SubmissionWorkerConfig configures the transfer submission worker
+type SubmissionWorkerConfig struct {
+	Interval        time.Duration // How often to look for transfers ready to submit
+	BatchSize       int           // Transfers read per page when looking for ones to submit
+	MaxAttempts     int           // Attempts before a transiently failing transfer is dead-lettered
+	InitialBackoff  time.Duration // Wait before the first retry; doubles on every failure
+	MaxBackoff      time.Duration // Upper bound on the retry wait
+	SubmitTimeout   time.Duration // Timeout for a single BitGo submission
+	ShutdownTimeout time.Duration // Timeout for graceful shutdown
+	Clock           Clock         // Supplies the current time; nil uses the wall clock
+}
+
+// DefaultSubmissionWorkerConfig returns sensible defaults
+func DefaultSubmissionWorkerConfig() SubmissionWorkerConfig {
+	return SubmissionWorkerConfig{
+		Interval:        time.Minute,
+		BatchSize:       50,
+		MaxAttempts:     5,
+		InitialBackoff:  30 * time.Second,
+		MaxBackoff:      30 * time.Minute,
+		SubmitTimeout:   30 * time.Second,
+		ShutdownTimeout: 30 * time.Second,
+	}
+}
+
+// Metadata keys used to track submission of a signed transfer
+const (
+	metadataSignedTxHex = "signed_tx_hex"
+	metadataSubmission  = "submission"
+)
+
+// submittableStatuses are the statuses in which a cold/warm transfer may be auto-submitted
+var submittableStatuses = []models.TransferStatus{
+	models.TransferStatusApproved,
+	models.TransferStatusSigned,
+}
+
+// bitgoStatusPendingApproval is the status BitGo returns when a submitted send needs
+// approval under the wallet's policy; it isn't a transfer state, so StatusMapper doesn't know it
+const bitgoStatusPendingApproval = "pendingApproval"
+
+// SubmittedTransferStatus maps the status BitGo returned for a submission onto ours. A
+// submission BitGo accepted without anything more definite to say is broadcast, as are
+// states that would otherwise send the transfer back to awaiting approval. A transfer left
+// pending approval here is awaiting BitGo's approval: once it has been submitted, local
+// approval decisions, the approval inbox and the approval timeout all leave it alone.
+func SubmittedTransferStatus(response *bitgo.SubmitTransferResponse) models.TransferStatus {
+	status := response.Status
+	if status == "" && response.Transfer != nil {
+		status = string(response.Transfer.State)
+	}
+	if strings.EqualFold(status, bitgoStatusPendingApproval) {
+		return models.TransferStatusPendingApproval
+	}
+
+	canonical := bitgo.NewStatusMapper().NormalizeTransferStatus(bitgo.TransferStatus(status), response.Transfer)
+	switch canonical {
+	case bitgo.CanonicalStatusPending, bitgo.CanonicalStatusSubmitting, bitgo.CanonicalStatusSigning:
+		return models.TransferStatusBroadcast
+	}
+	if mapped, ok := TransferStatusFromCanonical(canonical); ok {
+		return mapped
+	}
+	return models.TransferStatusBroadcast
+}
+
+// ApplySubmittedStatus records a submission BitGo accepted on the transfer: the status it
+// maps to, the submission time, and the completion or failure time if BitGo already
+// reports the transfer settled
+func ApplySubmittedStatus(transfer *models.TransferRequest, response *bitgo.SubmitTransferResponse, now time.Time) {
+	transfer.Status = SubmittedTransferStatus(response)
+	transfer.SubmittedAt = &now
+	switch transfer.Status {
+	case models.TransferStatusConfirmed, models.TransferStatusCompleted:
+		if transfer.CompletedAt == nil {
+			transfer.CompletedAt = &now
+		}
+	case models.TransferStatusFailed:
+		if transfer.FailedAt == nil {
+			transfer.FailedAt = &now
+		}
+	}
+}
+
+// SubmitSequenceID returns the sequenceId a transfer is built and submitted under, which is
+// how BitGo finds the transfer again when a submit's outcome is lost
+func SubmitSequenceID(transfer *models.TransferRequest) string {
+	return transfer.ID.String()
+}
+
+// FindAlreadySubmitted looks up the BitGo transfer an earlier submit of transfer created and
+// returns it as the response that submit would have had, so callers can record it as they
+// record any accepted submission
+func FindAlreadySubmitted(ctx context.Context, client bitgo.BitGoAPI, wallet *models.Wallet, transfer *models.TransferRequest) (*bitgo.SubmitTransferResponse, error) {
+	existing, err := client.GetTransferBySequenceID(ctx, wallet.BitgoWalletID, wallet.Coin, SubmitSequenceID(transfer))
+	if err != nil {
+		return nil, err
+	}
+	if existing.ID == "" {
+		return nil, fmt.Errorf("BitGo returned a transfer without an id")
+	}
+	return &bitgo.SubmitTransferResponse{Transfer: existing, TxID: existing.TxID, Status: string(existing.State)}, nil
+}
+
+// SubmitComment returns the note sent to BitGo with a transfer's submission
+func SubmitComment(transfer *models.TransferRequest) string {
+	if transfer.Comment == nil {
+		return ""
+	}
+	return strings.TrimSpace(*transfer.Comment)
+}
+
+// TransferSubmissionWorker submits approved cold and warm transfers that carry a signed
+// payload to BitGo, retrying transient failures with backoff and dead-lettering the rest
+type TransferSubmissionWorker struct {
+	config          SubmissionWorkerConfig
+	logger          Logger
+	bitgoClient     bitgo.BitGoAPI
+	transferRepo    repository.TransferRequestRepository
+	walletRepo      repository.WalletRepository
+	notificationSvc NotificationService
+
+	ctx       context.Context
+	cancel    context.CancelFunc
+	wg        sync.WaitGroup
+	isRunning bool
+	lastRun   time.Time
+	mu        sync.RWMutex
+}
+
+// NewTransferSubmissionWorker creates a new submission worker
+func NewTransferSubmissionWorker(
+	config SubmissionWorkerConfig,
+	logger Logger,
+	bitgoClient bitgo.BitGoAPI,
+	transferRepo repository.TransferRequestRepository,
+	walletRepo repository.WalletRepository,
+	notificationSvc NotificationService,
+) *TransferSubmissionWorker {
+	config.Clock = clockOrReal(config.Clock)
+
+	ctx, cancel := context.WithCancel(context.Background())
+
+	return &TransferSubmissionWorker{
+		config:          config,
+		logger:          logger,
+		bitgoClient:     bitgoClient,
+		transferRepo:    transferRepo,
+		walletRepo:      walletRepo,
+		notificationSvc: notificationSvc,
+		ctx:             ctx,
+		cancel:          cancel,
+	}
+}
+
+// Start begins periodic submission
+func (w *TransferSubmissionWorker) Start() error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if w.isRunning {
+		return fmt.Errorf("submission worker is already running")
+	}
+
+	w.isRunning = true
+	w.logger.Info("Starting transfer submission worker",
+		"interval", w.config.Interval,
+		"max_attempts", w.config.MaxAttempts,
+	)
+
+	w.wg.Add(1)
+	go w.submitLoop()
+
+	return nil
+}
+
+// Stop gracefully stops the worker, returning ErrShutdownTimeout if a submission outlasts
+// the shutdown timeout
+func (w *TransferSubmissionWorker) Stop() error {
+	w.mu.Lock()
+	if !w.isRunning {
+		w.mu.Unlock()
+		return fmt.Errorf("submission worker is not running")
+	}
+	w.isRunning = false
+	w.mu.Unlock()
+
+	w.logger.Info("Stopping transfer submission worker")
+	w.cancel()
+
+	done := make(chan struct{})
+	go func() {
+		w.wg.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		w.logger.Info("Transfer submission worker stopped gracefully")
+	case <-time.After(w.config.ShutdownTimeout):
+		w.logger.Warn("Transfer submission worker shutdown timed out")
+		return fmt.Errorf("%w after %s: a submission is still running", ErrShutdownTimeout, w.config.ShutdownTimeout)
+	}
+
+	return nil
+}
+
+// submitLoop runs SubmitReady on every tick until the worker is stopped
+func (w *TransferSubmissionWorker) submitLoop() {
+	defer w.wg.Done()
+
+	ticker := time.NewTicker(w.config.Interval)
+	defer ticker.Stop()
+
+	w.SubmitReady(w.config.Clock.Now())
+
+	for {
+		select {
+		case <-ticker.C:
+			w.SubmitReady(w.config.Clock.Now())
+		case <-w.ctx.Done():
+			w.logger.Info("Transfer submission loop shutting down")
+			return
+		}
+	}
+}
+
+// SubmitReady submits every cold/warm transfer that is approved, has a signed payload and
+// isn't waiting out a retry backoff, and returns how many were submitted. Transfers are read
+// a batch at a time by cursor, so unsigned or backing-off rows can't crowd out ready ones.
+func (w *TransferSubmissionWorker) SubmitReady(now time.Time) int {
+	submitted := 0
+
+	for _, transferType := range []models.WalletType{models.WalletTypeCold, models.WalletTypeWarm} {
+		var cursor *repository.TransferCursor
+		for {
+			transfers, err := w.transferRepo.ListByTypeAndStatusesAfter(transferType, submittableStatuses, cursor, w.config.BatchSize)
+			if err != nil {
+				w.logger.Error("Failed to get transfers ready for submission",
+					"transfer_type", transferType,
+					"error", err,
+				)
+				break
+			}
+			// Taken before submitting, since a successful update moves the row's updated_at
+			var next *repository.TransferCursor
+			if len(transfers) > 0 {
+				last := transfers[len(transfers)-1]
+				next = &repository.TransferCursor{UpdatedAt: last.UpdatedAt, ID: last.ID}
+			}
+
+			for _, transfer := range transfers {
+				if w.ctx.Err() != nil {
+					return submitted
+				}
+
+				signedTxHex, ok := signedPayload(transfer)
+				if !ok {
+					continue
+				}
+
+				// Flagged transfers wait for someone to look at them, e.g. a submit BitGo called a
+				// duplicate that couldn't be matched to its BitGo transfer
+				state := submissionState(transfer)
+				if state.DeadLettered || now.Before(state.NextAttemptAt) || transfer.NeedsAttentionAt != nil {
+					continue
+				}
+
+				if w.submitTransfer(transfer, signedTxHex, state, now) {
+					submitted++
+				}
+			}
+
+			if len(transfers) == 0 || len(transfers) < w.config.BatchSize {
+				break
+			}
+			cursor = next
+		}
+	}
+
+	w.mu.Lock()
+	w.lastRun = now
+	w.mu.Unlock()
+
+	if submitted > 0 {
+		w.logger.Info("Auto-submitted approved transfers", "count", submitted)
+	}
+
+	return submitted
+}
+
+// submitTransfer submits a single transfer and records the outcome, returning true on success
+func (w *TransferSubmissionWorker) submitTransfer(transfer *models.TransferRequest, signedTxHex string, state submissionAttempts, now time.Time) bool {
+	wallet, err := w.walletRepo.GetByID(transfer.WalletID)
+	if err != nil {
+		w.logger.Error("Failed to get wallet for transfer submission",
+			"transfer_id", transfer.ID,
+			"error", err,
+		)
+		return false
+	}
+	if wallet.Frozen {
+		// Left in place, not dead-lettered, so it is submitted once the wallet is unfrozen
+		w.logger.Warn("Skipping submission from frozen wallet",
+			"transfer_id", transfer.ID,
+			"wallet_id", wallet.ID,
+		)
+		return false
+	}
+
+	ctx, cancel := context.WithTimeout(w.ctx, w.config.SubmitTimeout)
+	defer cancel()
+
+	response, err := w.bitgoClient.SubmitTransfer(ctx, wallet.BitgoWalletID, wallet.Coin, bitgo.SubmitTransferRequest{
+		TxHex:      signedTxHex,
+		Comment:    SubmitComment(transfer),
+		SequenceId: SubmitSequenceID(transfer),
+	})
+	state.Attempts++
+	if bitgo.IsAlreadySubmitted(err) {
+		return w.recordAlreadySubmitted(ctx, wallet, transfer, state, err, now)
+	}
+	if err != nil {
+		w.recordSubmissionFailure(transfer, state, err, now)
+		return false
+	}
+
+	return w.recordSubmitted(transfer, response, state, now, "Submitted to BitGo automatically")
+}
+
+// recordSubmitted saves a submission BitGo accepted on the transfer, returning true once saved.
+// BitGo already has the transfer, so a version conflict reapplies the submission to the latest
+// row rather than dropping it, unless that row has moved on from being submittable.
+func (w *TransferSubmissionWorker) recordSubmitted(transfer *models.TransferRequest, response *bitgo.SubmitTransferResponse, state submissionAttempts, now time.Time, notes string) bool {
+	state.LastError = ""
+
+	var oldStatus models.TransferStatus
+	updated, err := repository.UpdateWithRetry(w.transferRepo, transfer, func(transfer *models.TransferRequest) bool {
+		if !statusIn(transfer.Status, submittableStatuses) {
+			return false
+		}
+		oldStatus = transfer.Status
+		ApplySubmittedStatus(transfer, response, now)
+		if response.TxID != "" {
+			transfer.BitgoTxid = &response.TxID
+		}
+		if response.Transfer != nil && response.Transfer.ID != "" {
+			transfer.BitgoTransferID = &response.Transfer.ID
+		}
+
+		setSubmissionState(transfer, state)
+		if transfer.TransferType == models.WalletTypeCold && offlineWorkflowState(transfer) == OfflineStateReadyToExecute {
+			recordOfflineTransition(transfer, OfflineStateTransition{
+				From:  OfflineStateReadyToExecute,
+				To:    OfflineStateExecuted,
+				Notes: notes,
+				At:    now,
+			})
+		}
+		return true
+	})
+	if err != nil {
+		w.logger.Error("Failed to update submitted transfer",
+			"transfer_id", transfer.ID,
+			"error", err,
+		)
+		return false
+	}
+	if !updated {
+		w.logger.Warn("Submitted transfer was moved on by another update",
+			"transfer_id", transfer.ID,
+			"status", transfer.Status,
+		)
+		return false
+	}
+
+	w.notificationSvc.SendTransferStatusNotification(transfer, oldStatus, transfer.Status)
+
+	w.logger.Info("Transfer auto-submitted to BitGo",
+		"transfer_id", transfer.ID,
+		"transfer_type", transfer.TransferType,
+		"attempts", state.Attempts,
+	)
+
+	return true
+}
+
+// recordAlreadySubmitted handles BitGo rejecting a submit as a duplicate, meaning an earlier
+// submit went through, e.g. an attempt that timed out after BitGo accepted it. If someone
+// else already recorded that submit it is left alone. Otherwise the BitGo transfer the
+// earlier submit created is looked up by sequenceId and recorded as if this submit had
+// succeeded, so the poller can follow it. When it can't be found the transfer is flagged as
+// needing attention, and no longer submitted, rather than recorded without a BitGo id.
+func (w *TransferSubmissionWorker) recordAlreadySubmitted(ctx context.Context, wallet *models.Wallet, transfer *models.TransferRequest, state submissionAttempts, submitErr error, now time.Time) bool {
+	w.logger.Warn("Transfer was already submitted to BitGo",
+		"transfer_id", transfer.ID,
+		"error", submitErr,
+	)
+
+	current, err := w.transferRepo.GetByID(transfer.ID)
+	if err != nil {
+		w.logger.Error("Failed to reload already submitted transfer",
+			"transfer_id", transfer.ID,
+			"error", err,
+		)
+		return false
+	}
+	if current == nil || current.Status != transfer.Status {
+		return true
+	}
+
+	response, err := FindAlreadySubmitted(ctx, w.bitgoClient, wallet, current)
+	if err != nil {
+		w.logger.Error("Failed to find already submitted transfer on BitGo",
+			"transfer_id", transfer.ID,
+			"error", err,
+		)
+		pollError := fmt.Sprintf("already submitted to BitGo, but the transfer could not be found by sequenceId: %v", err)
+		if err := w.transferRepo.RecordPollFailure(current.ID, current.PollFailures, pollError, now, true); err != nil {
+			w.logger.Error("Failed to flag already submitted transfer",
+				"transfer_id", transfer.ID,
+				"error", err,
+			)
+		}
+		return false
+	}
+
+	return w.recordSubmitted(current, response, state, now, "Already submitted to BitGo")
+}
+
+// recordSubmissionFailure schedules a retry for transient failures and dead-letters the
+// transfer for permanent failures or once attempts are exhausted. Either is reapplied to the
+// latest row on a version conflict, as long as the transfer is still waiting to be submitted.
+func (w *TransferSubmissionWorker) recordSubmissionFailure(transfer *models.TransferRequest, state submissionAttempts, submitErr error, now time.Time) {
+	state.LastError = submitErr.Error()
+	transient := isTransientSubmitError(submitErr)
+
+	if transient && state.Attempts < w.config.MaxAttempts {
+		state.NextAttemptAt = now.Add(w.backoff(state.Attempts))
+
+		_, err := repository.UpdateWithRetry(w.transferRepo, transfer, func(transfer *models.TransferRequest) bool {
+			if !statusIn(transfer.Status, submittableStatuses) {
+				return false
+			}
+			setSubmissionState(transfer, state)
+			return true
+		})
+		if err != nil {
+			w.logger.Error("Failed to record submission retry",
+				"transfer_id", transfer.ID,
+				"error", err,
+			)
+		}
+
+		w.logger.Warn("Transfer submission failed, will retry",
+			"transfer_id", transfer.ID,
+			"attempts", state.Attempts,
+			"next_attempt_at", state.NextAttemptAt,
+			"error", submitErr,
+		)
+		return
+	}
+
+	state.DeadLettered = true
+	reason := fmt.Sprintf("Submission to BitGo failed after %d attempt(s): %s", state.Attempts, submitErr)
+
+	var oldStatus models.TransferStatus
+	updated, err := repository.UpdateWithRetry(w.transferRepo, transfer, func(transfer *models.TransferRequest) bool {
+		if !statusIn(transfer.Status, submittableStatuses) {
+			return false
+		}
+		setSubmissionState(transfer, state)
+		oldStatus = transfer.Status
+		transfer.Status = models.TransferStatusFailed
+		transfer.StatusReason = &reason
+		transfer.FailedAt = &now
+		return true
+	})
+	if err != nil {
+		w.logger.Error("Failed to dead-letter transfer",
+			"transfer_id", transfer.ID,
+			"error", err,
+		)
+		return
+	}
+	if !updated {
+		return
+	}
+
+	w.notificationSvc.SendTransferFailedNotification(transfer, reason)
+
+	w.logger.Error("Transfer submission dead-lettered",
+		"transfer_id", transfer.ID,
+		"old_status", oldStatus,
+		"attempts", state.Attempts,
+		"transient", transient,
+		"error", submitErr,
+	)
+}
+
+// backoff returns the wait before the next attempt after the given number of failed attempts
+func (w *TransferSubmissionWorker) backoff(attempts int) time.Duration {
+	wait := w.config.InitialBackoff
+	for i := 1; i < attempts && wait < w.config.MaxBackoff; i++ {
+		wait *= 2
+	}
+	if wait > w.config.MaxBackoff {
+		wait = w.config.MaxBackoff
+	}
+	return wait
+}
+
+// isTransientSubmitError treats BitGo 5xx/429 responses and transport errors as retryable
+func isTransientSubmitError(err error) bool {
+	var apiErr bitgo.APIError
+	if errors.As(err, &apiErr) {
+		return apiErr.StatusCode >= http.StatusInternalServerError || apiErr.StatusCode == http.StatusTooManyRequests
+	}
+	return true
+}
+
+// HealthCheck returns the health status of the worker
+func (w *TransferSubmissionWorker) HealthCheck() map[string]interface{} {
+	w.mu.RLock()
+	defer w.mu.RUnlock()
+
+	status := "stopped"
+	if w.isRunning {
+		status = "running"
+	}
+
+	return map[string]interface{}{
+		"status":       status,
+		"last_run":     w.lastRun.UTC(),
+		"interval":     w.config.Interval.String(),
+		"max_attempts": w.config.MaxAttempts,
+	}
+}
+
+// submissionAttempts is the submission progress stored under the transfer's submission metadata
+type submissionAttempts struct {
+	Attempts      int
+	NextAttemptAt time.Time
+	LastError     string
+	DeadLettered  bool
+}
+
+// signedPayload returns the signed transaction to submit: the HSM signature for cold
+// transfers, or a signed_tx_hex attached directly to the transfer's metadata
+func signedPayload(transfer *models.TransferRequest) (string, bool) {
+	if session, ok := hsmSession(transfer); ok && session.SignedTxHex != "" {
+		return session.SignedTxHex, true
+	}
+	if transfer.Metadata != nil {
+		if signedTxHex, ok := transfer.Metadata[metadataSignedTxHex].(string); ok && signedTxHex != "" {
+			return signedTxHex, true
+		}
+	}
+	return "", false
+}
+
+// submissionState reads the submission progress from a transfer's metadata
+func submissionState(transfer *models.TransferRequest) submissionAttempts {
+	state := submissionAttempts{}
+	if transfer.Metadata == nil {
+		return state
+	}
+	raw, ok := transfer.Metadata[metadataSubmission].(map[string]interface{})
+	if !ok {
+		return state
+	}
+
+	// Numbers round-trip through JSONB as float64
+	switch attempts := raw["attempts"].(type) {
+	case float64:
+		state.Attempts = int(attempts)
+	case int:
+		state.Attempts = attempts
+	}
+	if value, ok := raw["next_attempt_at"].(string); ok {
+		state.NextAttemptAt, _ = time.Parse(time.RFC3339, value)
+	}
+	state.LastError, _ = raw["last_error"].(string)
+	state.DeadLettered, _ = raw["dead_lettered"].(bool)
+
+	return state
+}
+
+// setSubmissionState stores the submission progress in a transfer's metadata
+func setSubmissionState(transfer *models.TransferRequest, state submissionAttempts) {
+	if transfer.Metadata == nil {
+		transfer.Metadata = models.JSON{}
+	}
+
+	raw := map[string]interface{}{
+		"attempts":      state.Attempts,
+		"dead_lettered": state.DeadLettered,
+	}
+	if !state.NextAttemptAt.IsZero() {
+		raw["next_attempt_at"] = state.NextAttemptAt.UTC().Format(time.RFC3339)
+	}
+	if state.LastError != "" {
+		raw["last_error"] = state.LastError
+	}
+	transfer.Metadata[metadataSubmission] = raw
+}
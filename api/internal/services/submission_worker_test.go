@@ -0,0 +1,242 @@
+package services
+
+import (
+	"context"
+	"net/http"
+	"testing"
+	"time"
+
+	"bitgo-wallets-api/internal/bitgo"
+	"bitgo-wallets-api/internal/models"
+
+	"github.com/google/uuid"
+)
+
+func TestApplySubmittedStatus(t *testing.T) {
+	now := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name          string
+		response      *bitgo.SubmitTransferResponse
+		wantStatus    models.TransferStatus
+		wantCompleted bool
+		wantFailed    bool
+	}{
+		{name: "accepted without status", response: &bitgo.SubmitTransferResponse{}, wantStatus: models.TransferStatusBroadcast},
+		{name: "awaiting BitGo approval", response: &bitgo.SubmitTransferResponse{Status: "pendingApproval"}, wantStatus: models.TransferStatusPendingApproval},
+		{name: "already confirmed", response: &bitgo.SubmitTransferResponse{Status: "confirmed"}, wantStatus: models.TransferStatusConfirmed, wantCompleted: true},
+		{name: "failed", response: &bitgo.SubmitTransferResponse{Status: "failed"}, wantStatus: models.TransferStatusFailed, wantFailed: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			transfer := &models.TransferRequest{Status: models.TransferStatusApproved}
+			ApplySubmittedStatus(transfer, tt.response, now)
+
+			if transfer.Status != tt.wantStatus {
+				t.Errorf("status = %s, want %s", transfer.Status, tt.wantStatus)
+			}
+			if transfer.SubmittedAt == nil || !transfer.SubmittedAt.Equal(now) {
+				t.Errorf("submitted_at = %v, want %v", transfer.SubmittedAt, now)
+			}
+			if (transfer.CompletedAt != nil) != tt.wantCompleted {
+				t.Errorf("completed_at = %v, want set: %v", transfer.CompletedAt, tt.wantCompleted)
+			}
+			if (transfer.FailedAt != nil) != tt.wantFailed {
+				t.Errorf("failed_at = %v, want set: %v", transfer.FailedAt, tt.wantFailed)
+			}
+		})
+	}
+}
+
+func TestSubmissionWorkerRecordsAlreadySubmittedTransfer(t *testing.T) {
+	client := bitgo.NewSimulatedClient(bitgo.SimulationConfig{}, testLogger{})
+	wallet := &models.Wallet{ID: uuid.New(), BitgoWalletID: "bitgo-wallet-1", Coin: "btc", WalletType: models.WalletTypeWarm}
+	transfer := &models.TransferRequest{
+		ID:           uuid.New(),
+		WalletID:     wallet.ID,
+		Coin:         "btc",
+		TransferType: models.WalletTypeWarm,
+		Status:       models.TransferStatusApproved,
+		Metadata:     models.JSON{metadataSignedTxHex: "signed-tx-hex"},
+		Version:      1,
+	}
+	repo := newMemTransferRepo(transfer)
+	worker := NewTransferSubmissionWorker(DefaultSubmissionWorkerConfig(), testLogger{}, client, repo, newMemWalletRepo(wallet), nopNotifier{})
+
+	// An earlier attempt BitGo accepted, e.g. one that timed out on our side
+	original, err := client.SubmitTransfer(context.Background(), wallet.BitgoWalletID, wallet.Coin, bitgo.SubmitTransferRequest{
+		TxHex:      "signed-tx-hex",
+		SequenceId: SubmitSequenceID(transfer),
+	})
+	if err != nil {
+		t.Fatalf("first SubmitTransfer() error = %v", err)
+	}
+
+	if !worker.submitTransfer(transfer, "signed-tx-hex", submissionState(transfer), time.Now()) {
+		t.Fatal("submitTransfer() = false, want the earlier submit recorded")
+	}
+
+	stored, _ := repo.GetByID(transfer.ID)
+	if stored.Status != models.TransferStatusBroadcast {
+		t.Errorf("status = %s, want %s", stored.Status, models.TransferStatusBroadcast)
+	}
+	if stored.BitgoTransferID == nil || *stored.BitgoTransferID != original.Transfer.ID {
+		t.Errorf("BitgoTransferID = %v, want %q", stored.BitgoTransferID, original.Transfer.ID)
+	}
+	if stored.BitgoTxid == nil || *stored.BitgoTxid != original.TxID {
+		t.Errorf("BitgoTxid = %v, want %q", stored.BitgoTxid, original.TxID)
+	}
+}
+
+// flakySubmitClient rejects its first few submits with a BitGo error of statusCode, then
+// submits through the simulation
+type flakySubmitClient struct {
+	*bitgo.SimulatedClient
+	failures   int
+	statusCode int
+	calls      int
+}
+
+func (c *flakySubmitClient) SubmitTransfer(ctx context.Context, walletID, coin string, req bitgo.SubmitTransferRequest) (*bitgo.SubmitTransferResponse, error) {
+	c.calls++
+	if c.calls <= c.failures {
+		return nil, bitgo.APIError{StatusCode: c.statusCode, Message: http.StatusText(c.statusCode)}
+	}
+	return c.SimulatedClient.SubmitTransfer(ctx, walletID, coin, req)
+}
+
+func TestSubmitReadyRetriesTransientFailures(t *testing.T) {
+	wallet := &models.Wallet{ID: uuid.New(), BitgoWalletID: "bitgo-wallet-1", Coin: "btc", WalletType: models.WalletTypeWarm}
+	transfer := &models.TransferRequest{
+		ID:           uuid.New(),
+		WalletID:     wallet.ID,
+		Coin:         "btc",
+		TransferType: models.WalletTypeWarm,
+		Status:       models.TransferStatusApproved,
+		Metadata:     models.JSON{metadataSignedTxHex: "signed-tx-hex"},
+		Version:      1,
+	}
+	repo := newMemTransferRepo(transfer)
+	client := &flakySubmitClient{SimulatedClient: bitgo.NewSimulatedClient(bitgo.SimulationConfig{}, testLogger{}), failures: 1, statusCode: http.StatusInternalServerError}
+	config := DefaultSubmissionWorkerConfig()
+	worker := NewTransferSubmissionWorker(config, testLogger{}, client, repo, newMemWalletRepo(wallet), nopNotifier{})
+
+	now := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	if submitted := worker.SubmitReady(now); submitted != 0 {
+		t.Fatalf("first SubmitReady() = %d, want 0 after BitGo's 500", submitted)
+	}
+	stored, _ := repo.GetByID(transfer.ID)
+	state := submissionState(stored)
+	if stored.Status != models.TransferStatusApproved || state.Attempts != 1 || state.DeadLettered || state.LastError == "" {
+		t.Fatalf("after 500: status %s, state %+v; want approved with one attempt scheduled for retry", stored.Status, state)
+	}
+	if !state.NextAttemptAt.Equal(now.Add(config.InitialBackoff)) {
+		t.Errorf("next attempt at %s, want %s", state.NextAttemptAt, now.Add(config.InitialBackoff))
+	}
+
+	// Nothing is submitted while backing off
+	if submitted := worker.SubmitReady(now.Add(config.InitialBackoff / 2)); submitted != 0 || client.calls != 1 {
+		t.Fatalf("SubmitReady() during backoff = %d with %d BitGo calls, want 0 with 1", submitted, client.calls)
+	}
+
+	if submitted := worker.SubmitReady(now.Add(config.InitialBackoff)); submitted != 1 {
+		t.Fatalf("SubmitReady() after backoff = %d, want 1", submitted)
+	}
+	stored, _ = repo.GetByID(transfer.ID)
+	if stored.Status != models.TransferStatusBroadcast || stored.BitgoTransferID == nil {
+		t.Errorf("after retry: status %s with BitGo transfer %v, want broadcast with an ID", stored.Status, stored.BitgoTransferID)
+	}
+	if state := submissionState(stored); state.Attempts != 2 || state.LastError != "" {
+		t.Errorf("after retry: state %+v, want 2 attempts and the error cleared", state)
+	}
+}
+
+func TestSubmitReadyDeadLettersPermanentFailures(t *testing.T) {
+	wallet := &models.Wallet{ID: uuid.New(), BitgoWalletID: "bitgo-wallet-1", Coin: "btc", WalletType: models.WalletTypeWarm}
+	transfer := &models.TransferRequest{
+		ID:           uuid.New(),
+		WalletID:     wallet.ID,
+		Coin:         "btc",
+		TransferType: models.WalletTypeWarm,
+		Status:       models.TransferStatusApproved,
+		Metadata:     models.JSON{metadataSignedTxHex: "signed-tx-hex"},
+		Version:      1,
+	}
+	repo := newMemTransferRepo(transfer)
+	client := &flakySubmitClient{SimulatedClient: bitgo.NewSimulatedClient(bitgo.SimulationConfig{}, testLogger{}), failures: 1, statusCode: http.StatusBadRequest}
+	worker := NewTransferSubmissionWorker(DefaultSubmissionWorkerConfig(), testLogger{}, client, repo, newMemWalletRepo(wallet), nopNotifier{})
+
+	if submitted := worker.SubmitReady(time.Now()); submitted != 0 {
+		t.Fatalf("SubmitReady() = %d, want 0 after BitGo's 400", submitted)
+	}
+	stored, _ := repo.GetByID(transfer.ID)
+	if stored.Status != models.TransferStatusFailed || !submissionState(stored).DeadLettered {
+		t.Errorf("status %s, state %+v; want failed and dead-lettered", stored.Status, submissionState(stored))
+	}
+}
+
+func TestSubmissionWorkerRecordsSubmitDespiteConcurrentUpdate(t *testing.T) {
+	client := bitgo.NewSimulatedClient(bitgo.SimulationConfig{}, testLogger{})
+	wallet := &models.Wallet{ID: uuid.New(), BitgoWalletID: "bitgo-wallet-1", Coin: "btc", WalletType: models.WalletTypeWarm}
+	transfer := &models.TransferRequest{
+		ID:           uuid.New(),
+		WalletID:     wallet.ID,
+		Coin:         "btc",
+		TransferType: models.WalletTypeWarm,
+		Status:       models.TransferStatusApproved,
+		Metadata:     models.JSON{metadataSignedTxHex: "signed-tx-hex"},
+		Version:      1,
+	}
+	repo := newMemTransferRepo(transfer)
+	worker := NewTransferSubmissionWorker(DefaultSubmissionWorkerConfig(), testLogger{}, client, repo, newMemWalletRepo(wallet), nopNotifier{})
+
+	// Someone tags the transfer after the worker read it, so the worker's copy is stale
+	concurrent, _ := repo.GetByID(transfer.ID)
+	concurrent.Tags = []string{"payroll"}
+	if err := repo.Update(concurrent); err != nil {
+		t.Fatalf("concurrent Update() error = %v", err)
+	}
+
+	if !worker.submitTransfer(transfer, "signed-tx-hex", submissionState(transfer), time.Now()) {
+		t.Fatal("submitTransfer() = false, want the accepted submit recorded")
+	}
+
+	stored, _ := repo.GetByID(transfer.ID)
+	if stored.Status != models.TransferStatusBroadcast || stored.BitgoTransferID == nil {
+		t.Errorf("status %s with BitGo transfer %v, want %s with the BitGo ID", stored.Status, stored.BitgoTransferID, models.TransferStatusBroadcast)
+	}
+	if len(stored.Tags) != 1 || stored.Tags[0] != "payroll" {
+		t.Errorf("tags = %v, want the concurrent update kept", stored.Tags)
+	}
+}
+
+func TestSubmissionWorkerLeavesTransferMovedOnBeforeDeadLettering(t *testing.T) {
+	wallet := &models.Wallet{ID: uuid.New(), BitgoWalletID: "bitgo-wallet-1", Coin: "btc", WalletType: models.WalletTypeWarm}
+	transfer := &models.TransferRequest{
+		ID:           uuid.New(),
+		WalletID:     wallet.ID,
+		Coin:         "btc",
+		TransferType: models.WalletTypeWarm,
+		Status:       models.TransferStatusApproved,
+		Metadata:     models.JSON{metadataSignedTxHex: "signed-tx-hex"},
+		Version:      1,
+	}
+	repo := newMemTransferRepo(transfer)
+	worker := NewTransferSubmissionWorker(DefaultSubmissionWorkerConfig(), testLogger{}, nil, repo, newMemWalletRepo(wallet), nopNotifier{})
+
+	// The transfer is cancelled while the failing submit is in flight
+	concurrent, _ := repo.GetByID(transfer.ID)
+	concurrent.Status = models.TransferStatusCancelled
+	if err := repo.Update(concurrent); err != nil {
+		t.Fatalf("concurrent Update() error = %v", err)
+	}
+
+	permanent := bitgo.APIError{StatusCode: http.StatusBadRequest, Message: "invalid transaction"}
+	worker.recordSubmissionFailure(transfer, submissionAttempts{Attempts: 1}, permanent, time.Now())
+
+	stored, _ := repo.GetByID(transfer.ID)
+	if stored.Status != models.TransferStatusCancelled {
+		t.Errorf("status = %s, want %s", stored.Status, models.TransferStatusCancelled)
+	}
+}
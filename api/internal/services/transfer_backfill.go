@@ -0,0 +1,206 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+
+	"bitgo-wallets-api/internal/bitgo"
+	"bitgo-wallets-api/internal/models"
+	"bitgo-wallets-api/internal/repository"
+
+	"github.com/google/uuid"
+)
+
+const (
+	// backfillPageSize is how many transfers are requested from BitGo per page
+	backfillPageSize = 250
+	// maxBackfillPages bounds one backfill run; a longer history is finished by running again
+	maxBackfillPages = 40
+)
+
+// BackfillSkip is a BitGo transfer the backfill couldn't import, and why
+type BackfillSkip struct {
+	BitgoTransferID string `json:"bitgo_transfer_id"`
+	Reason          string `json:"reason"`
+}
+
+// BackfillReport summarizes one run of importing a wallet's BitGo transfer history
+type BackfillReport struct {
+	WalletID       uuid.UUID      `json:"wallet_id"`
+	Pages          int            `json:"pages"`
+	Scanned        int            `json:"scanned"`
+	Created        int            `json:"created"`
+	AlreadyPresent int            `json:"already_present"`
+	Skipped        []BackfillSkip `json:"skipped"`
+	Truncated      bool           `json:"truncated"` // Stopped at maxBackfillPages; run again to continue
+}
+
+// TransferBackfiller imports the send transfers in a wallet's BitGo history that have no local
+// transfer request, e.g. for a wallet onboarded after it was already in use. Transfers are
+// matched on BitGo transfer ID or txid, so running it again only imports what's new.
+type TransferBackfiller struct {
+	bitgoClient  bitgo.BitGoAPI
+	transferRepo repository.TransferRequestRepository
+	logger       Logger
+}
+
+// NewTransferBackfiller creates a transfer backfiller
+func NewTransferBackfiller(bitgoClient bitgo.BitGoAPI, transferRepo repository.TransferRequestRepository, logger Logger) *TransferBackfiller {
+	return &TransferBackfiller{
+		bitgoClient:  bitgoClient,
+		transferRepo: transferRepo,
+		logger:       logger,
+	}
+}
+
+// Backfill pages through the wallet's BitGo send transfers and creates a backfill-origin
+// transfer request for each one not already recorded, attributed to requestedBy
+func (b *TransferBackfiller) Backfill(ctx context.Context, wallet *models.Wallet, requestedBy uuid.UUID) (*BackfillReport, error) {
+	known, err := b.transferRepo.ListBitGoReferences(wallet.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	report := &BackfillReport{WalletID: wallet.ID, Skipped: []BackfillSkip{}}
+	statusMapper := bitgo.NewStatusMapper()
+	options := &bitgo.TransferListOptions{Limit: backfillPageSize, Type: bitgo.TransferTypeSend}
+
+	for {
+		if report.Pages == maxBackfillPages {
+			report.Truncated = true
+			break
+		}
+
+		page, err := b.bitgoClient.ListTransfers(ctx, wallet.BitgoWalletID, wallet.Coin, options)
+		if err != nil {
+			return report, fmt.Errorf("failed to list BitGo transfers: %w", err)
+		}
+		report.Pages++
+
+		for i := range page.Transfers {
+			bitgoTransfer := &page.Transfers[i]
+			if bitgoTransfer.Type != bitgo.TransferTypeSend {
+				continue
+			}
+			report.Scanned++
+
+			if known[bitgoTransfer.ID] || (bitgoTransfer.TxID != "" && known[bitgoTransfer.TxID]) {
+				report.AlreadyPresent++
+				continue
+			}
+
+			transfer, reason := backfillTransferRequest(wallet, bitgoTransfer, statusMapper, requestedBy)
+			if transfer == nil {
+				report.Skipped = append(report.Skipped, BackfillSkip{BitgoTransferID: bitgoTransfer.ID, Reason: reason})
+				continue
+			}
+
+			err := b.transferRepo.Create(transfer)
+			if errors.Is(err, repository.ErrDuplicateTransferRequest) {
+				// A concurrent run imported it first
+				report.AlreadyPresent++
+				continue
+			}
+			if err != nil {
+				return report, fmt.Errorf("failed to backfill BitGo transfer %s: %w", bitgoTransfer.ID, err)
+			}
+
+			known[bitgoTransfer.ID] = true
+			if bitgoTransfer.TxID != "" {
+				known[bitgoTransfer.TxID] = true
+			}
+			report.Created++
+		}
+
+		if page.NextBatchPrevId == "" || len(page.Transfers) == 0 {
+			break
+		}
+		options.PrevID = page.NextBatchPrevId
+	}
+
+	b.logger.Info("Backfilled BitGo transfer history",
+		"wallet_id", wallet.ID,
+		"pages", report.Pages,
+		"created", report.Created,
+		"already_present", report.AlreadyPresent,
+		"skipped", len(report.Skipped),
+		"truncated", report.Truncated,
+	)
+
+	return report, nil
+}
+
+// backfillTransferRequest maps a BitGo send transfer onto a new transfer request, or returns
+// the reason it can't be
+func backfillTransferRequest(wallet *models.Wallet, bitgoTransfer *bitgo.Transfer, statusMapper *bitgo.StatusMapper, requestedBy uuid.UUID) (*models.TransferRequest, string) {
+	status, known := TransferStatusFromCanonical(statusMapper.NormalizeTransferStatus(bitgoTransfer.State, bitgoTransfer))
+	if !known {
+		return nil, fmt.Sprintf("BitGo state %q has no local status", bitgoTransfer.State)
+	}
+	// In-flight transfers would otherwise land in the approval inbox, the submission worker
+	// and the balance reservations; only settled or broadcast history is imported
+	if status != models.TransferStatusBroadcast && !statusIn(status, terminalTransferStatuses) {
+		return nil, fmt.Sprintf("BitGo state %q is still in flight", bitgoTransfer.State)
+	}
+
+	recipients := bitgo.CategorizeEntries(bitgoTransfer.Entries).Recipients
+	if len(recipients) == 0 {
+		return nil, "transfer has no recipient entries"
+	}
+
+	// The amount sent is the sum of the recipient outputs, in base units
+	total := new(big.Int)
+	for _, recipient := range recipients {
+		value, ok := new(big.Int).SetString(recipient.ValueString, 10)
+		if !ok {
+			value = big.NewInt(recipient.Value)
+		}
+		total.Add(total, value)
+	}
+	amount, err := bitgo.AmountFromBaseUnits(wallet.Coin, total.String())
+	if err != nil {
+		return nil, err.Error()
+	}
+
+	transfer := &models.TransferRequest{
+		WalletID:          wallet.ID,
+		RequestedByUserID: requestedBy,
+		RecipientAddress:  recipients[0].Address,
+		AmountString:      amount,
+		Coin:              wallet.Coin,
+		TransferType:      wallet.WalletType,
+		Status:            status,
+		Origin:            models.TransferOriginBackfill,
+		BitgoTransferID:   &bitgoTransfer.ID,
+		Comment:           optionalString(bitgoTransfer.Comment),
+		Metadata: models.JSON{
+			"bitgo_state":           bitgoTransfer.State,
+			"backfilled_recipients": len(recipients),
+		},
+		CreatedAt: bitgoTransfer.CreatedTime,
+	}
+	if transfer.CreatedAt.IsZero() {
+		transfer.CreatedAt = bitgoTransfer.Date
+	}
+	if bitgoTransfer.TxID != "" {
+		transfer.TransactionHash = &bitgoTransfer.TxID
+	}
+	if bitgoTransfer.FeeString != "" {
+		transfer.FeeString = &bitgoTransfer.FeeString
+	}
+	if !bitgoTransfer.Date.IsZero() {
+		submittedAt := bitgoTransfer.Date
+		transfer.SubmittedAt = &submittedAt
+	}
+	switch status {
+	case models.TransferStatusConfirmed:
+		transfer.CompletedAt = bitgoTransfer.ConfirmedTime
+	case models.TransferStatusFailed:
+		failedAt := bitgoTransfer.ModifiedTime
+		transfer.FailedAt = &failedAt
+	}
+
+	return transfer, ""
+}
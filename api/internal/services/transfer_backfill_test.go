@@ -0,0 +1,147 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"bitgo-wallets-api/internal/bitgo"
+	"bitgo-wallets-api/internal/models"
+
+	"github.com/google/uuid"
+)
+
+func TestBackfillTransferRequestImportsOnlySettledOrBroadcast(t *testing.T) {
+	wallet := &models.Wallet{ID: uuid.New(), Coin: "btc", WalletType: models.WalletTypeHot}
+	entries := []bitgo.TransferEntry{{Address: testTrustedAddress, Value: 100000, ValueString: "100000"}}
+	pendingApproval := []bitgo.TransferHistory{{Action: "pendingApproval"}}
+
+	tests := []struct {
+		name       string
+		transfer   bitgo.Transfer
+		wantStatus models.TransferStatus // Empty when the transfer should be skipped
+	}{
+		{name: "confirmed", transfer: bitgo.Transfer{State: bitgo.TransferStatusConfirmed, Confirmations: 6, TxID: "tx-1"}, wantStatus: models.TransferStatusConfirmed},
+		{name: "broadcast", transfer: bitgo.Transfer{State: bitgo.TransferStatusPending, TxID: "tx-2"}, wantStatus: models.TransferStatusBroadcast},
+		{name: "failed", transfer: bitgo.Transfer{State: bitgo.TransferStatusFailed}, wantStatus: models.TransferStatusFailed},
+		{name: "signing", transfer: bitgo.Transfer{State: bitgo.TransferStatusSigning}},
+		{name: "awaiting approval", transfer: bitgo.Transfer{State: bitgo.TransferStatusPending, History: pendingApproval}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.transfer.ID = "bitgo-" + tt.name
+			tt.transfer.Coin = "btc"
+			tt.transfer.Entries = entries
+			tt.transfer.Date = time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+
+			transfer, reason := backfillTransferRequest(wallet, &tt.transfer, bitgo.NewStatusMapper(), uuid.New())
+			if tt.wantStatus == "" {
+				if transfer != nil || reason == "" {
+					t.Errorf("backfillTransferRequest() = %+v, want the transfer skipped", transfer)
+				}
+				return
+			}
+			if transfer == nil {
+				t.Fatalf("backfillTransferRequest() skipped the transfer: %s", reason)
+			}
+			if transfer.Status != tt.wantStatus || transfer.AmountString != "0.001" || transfer.Origin != models.TransferOriginBackfill {
+				t.Errorf("backfilled status %s, amount %s, origin %s; want %s, 0.001, backfill",
+					transfer.Status, transfer.AmountString, transfer.Origin, tt.wantStatus)
+			}
+		})
+	}
+}
+
+// pagedTransferClient serves a wallet's transfer history in pages chained by nextBatchPrevId
+type pagedTransferClient struct {
+	bitgo.BitGoAPI
+	pages    [][]bitgo.Transfer
+	requests []string // PrevID of each request
+}
+
+func (c *pagedTransferClient) ListTransfers(ctx context.Context, walletID, coin string, options *bitgo.TransferListOptions) (*bitgo.TransferListResponse, error) {
+	c.requests = append(c.requests, options.PrevID)
+	page := 0
+	if options.PrevID != "" {
+		if _, err := fmt.Sscanf(options.PrevID, "page-%d", &page); err != nil {
+			return nil, fmt.Errorf("unexpected prevId %q", options.PrevID)
+		}
+	}
+	response := &bitgo.TransferListResponse{Transfers: c.pages[page], Count: len(c.pages[page])}
+	if page+1 < len(c.pages) {
+		response.NextBatchPrevId = fmt.Sprintf("page-%d", page+1)
+	}
+	return response, nil
+}
+
+func TestBackfillFollowsPagesAndSkipsKnownTransfersOnRerun(t *testing.T) {
+	wallet := &models.Wallet{ID: uuid.New(), BitgoWalletID: "bitgo-wallet", Coin: "btc", WalletType: models.WalletTypeHot}
+	confirmed := func(id string) bitgo.Transfer {
+		return bitgo.Transfer{
+			ID:            id,
+			Coin:          "btc",
+			Type:          bitgo.TransferTypeSend,
+			State:         bitgo.TransferStatusConfirmed,
+			Confirmations: 6,
+			TxID:          "tx-" + id,
+			Date:          time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC),
+			Entries:       []bitgo.TransferEntry{{Address: testTrustedAddress, Value: 100000, ValueString: "100000"}},
+		}
+	}
+	client := &pagedTransferClient{pages: [][]bitgo.Transfer{
+		{confirmed("bitgo-1"), confirmed("bitgo-2")},
+		{confirmed("bitgo-3")},
+	}}
+	repo := newMemTransferRepo()
+	backfiller := NewTransferBackfiller(client, repo, testLogger{})
+
+	report, err := backfiller.Backfill(context.Background(), wallet, uuid.New())
+	if err != nil {
+		t.Fatalf("Backfill() error = %v", err)
+	}
+	if report.Pages != 2 || report.Created != 3 || report.AlreadyPresent != 0 {
+		t.Errorf("first run: %d pages, %d created, %d already present; want 2, 3, 0", report.Pages, report.Created, report.AlreadyPresent)
+	}
+	if len(client.requests) != 2 || client.requests[0] != "" || client.requests[1] != "page-1" {
+		t.Errorf("requested prevIds %q, want the first page and then page-1", client.requests)
+	}
+
+	report, err = backfiller.Backfill(context.Background(), wallet, uuid.New())
+	if err != nil {
+		t.Fatalf("rerun: Backfill() error = %v", err)
+	}
+	if report.Created != 0 || report.AlreadyPresent != 3 {
+		t.Errorf("rerun: %d created, %d already present; want 0, 3", report.Created, report.AlreadyPresent)
+	}
+	if len(repo.transfers) != 3 {
+		t.Errorf("%d transfers stored, want 3 with no duplicates", len(repo.transfers))
+	}
+}
+
+func TestBackfillTransferRequestSumsOnlyRecipientOutputs(t *testing.T) {
+	wallet := &models.Wallet{ID: uuid.New(), Coin: "btc", WalletType: models.WalletTypeHot}
+	transfer := bitgo.Transfer{
+		ID:            "bitgo-with-change",
+		Coin:          "btc",
+		State:         bitgo.TransferStatusConfirmed,
+		Confirmations: 6,
+		TxID:          "tx-change",
+		Date:          time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC),
+		Entries: []bitgo.TransferEntry{
+			{Address: "wallet-input", Value: -250000, ValueString: "-250000"},
+			{Address: testTrustedAddress, Value: 100000, ValueString: "100000"},
+			{Address: "change-address", Value: 140000, ValueString: "140000", IsChange: true},
+			{Address: "paygo-address", Value: 5000, ValueString: "5000", IsPayGo: true},
+		},
+	}
+
+	backfilled, reason := backfillTransferRequest(wallet, &transfer, bitgo.NewStatusMapper(), uuid.New())
+	if backfilled == nil {
+		t.Fatalf("backfillTransferRequest() skipped the transfer: %s", reason)
+	}
+	if backfilled.AmountString != "0.001" || backfilled.RecipientAddress != testTrustedAddress {
+		t.Errorf("backfilled %s to %s, want 0.001 to the recipient without change or pay-go", backfilled.AmountString, backfilled.RecipientAddress)
+	}
+}
@@ -0,0 +1,68 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"bitgo-wallets-api/internal/bitgo"
+	"bitgo-wallets-api/internal/models"
+)
+
+// NewTransferBuildRequest returns the BitGo build request for a stored transfer: its recipient,
+// amount, memo or destination tag, comment and the sequenceId it is submitted under. Callers
+// add their wallet type's build policy before building.
+func NewTransferBuildRequest(transfer *models.TransferRequest) bitgo.BuildTransferRequest {
+	memo := ""
+	if transfer.Memo != nil {
+		memo = *transfer.Memo
+	}
+	address := transfer.RecipientAddress
+	if transfer.DestinationTag != nil {
+		address = bitgo.AddressWithDestinationTag(transfer.Coin, address, *transfer.DestinationTag)
+	}
+
+	req := bitgo.BuildTransferRequest{
+		Recipients: []bitgo.TransferRecipient{
+			{
+				Address:      address,
+				AmountString: transfer.AmountString,
+			},
+		},
+		Memo:       bitgo.TextMemo(memo),
+		Comment:    SubmitComment(transfer),
+		SequenceId: SubmitSequenceID(transfer),
+	}
+	if transfer.DestinationTag != nil {
+		if tagMemo := bitgo.DestinationTagMemo(transfer.Coin, transfer.RecipientAddress, *transfer.DestinationTag); tagMemo != nil {
+			req.Memo = tagMemo
+		}
+	}
+	return req
+}
+
+// buildTransfer builds transfer's transaction with BitGo from wallet under the given build policy
+func buildTransfer(ctx context.Context, client bitgo.BitGoAPI, wallet *models.Wallet, transfer *models.TransferRequest, applyPolicy func(*bitgo.BuildTransferRequest)) (*bitgo.BuildTransferResponse, error) {
+	req := NewTransferBuildRequest(transfer)
+	applyPolicy(&req)
+	return client.BuildTransfer(ctx, wallet.BitgoWalletID, wallet.Coin, req)
+}
+
+// recordBuildFee records the fee BitGo quoted for a build on the transfer
+func recordBuildFee(transfer *models.TransferRequest, response *bitgo.BuildTransferResponse) {
+	if response.FeeInfo == nil {
+		return
+	}
+	fee := response.FeeInfo.FeeString
+	feeRate := fmt.Sprintf("%d", response.FeeInfo.FeeRate)
+	transfer.Fee = &fee
+	transfer.FeeRate = &feeRate
+}
+
+// unsignedTxHex returns the transaction hex of a build, which is what gets signed
+func unsignedTxHex(response *bitgo.BuildTransferResponse) string {
+	if response.PrebuildTx == nil {
+		return ""
+	}
+	return strings.TrimSpace(response.PrebuildTx.TxHex)
+}
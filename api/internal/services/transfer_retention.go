@@ -0,0 +1,208 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"bitgo-wallets-api/internal/models"
+	"bitgo-wallets-api/internal/repository"
+)
+
+// TransferRetentionConfig configures the transfer retention job
+type TransferRetentionConfig struct {
+	Interval        time.Duration // How often to look for transfers to archive
+	MaxAge          time.Duration // Terminal transfers untouched for longer are archived; zero disables
+	BatchSize       int           // Max transfers archived per batch
+	MaxBatches      int           // Max batches per run so one run can't hold the database for long
+	ShutdownTimeout time.Duration // Timeout for graceful shutdown
+	Clock           Clock         // Supplies the current time; nil uses the wall clock
+}
+
+// DefaultTransferRetentionConfig returns sensible defaults. Archival is disabled until
+// MaxAge is set.
+func DefaultTransferRetentionConfig() TransferRetentionConfig {
+	return TransferRetentionConfig{
+		Interval:        time.Hour,
+		BatchSize:       500,
+		MaxBatches:      20,
+		ShutdownTimeout: 30 * time.Second,
+	}
+}
+
+// terminalTransferStatuses are the statuses a transfer never leaves, so it's safe to archive
+var terminalTransferStatuses = append(append([]models.TransferStatus{}, successfulTransferStatuses...), unsuccessfulTransferStatuses...)
+
+// TransferRetentionJob periodically archives old transfers in terminal states so they drop
+// out of default list queries. Archived transfers are only flagged, never deleted.
+type TransferRetentionJob struct {
+	config       TransferRetentionConfig
+	logger       Logger
+	transferRepo repository.TransferRequestRepository
+
+	ctx          context.Context
+	cancel       context.CancelFunc
+	wg           sync.WaitGroup
+	isRunning    bool
+	lastRun      time.Time
+	lastArchived int64
+	mu           sync.RWMutex
+}
+
+// NewTransferRetentionJob creates a new transfer retention job
+func NewTransferRetentionJob(
+	config TransferRetentionConfig,
+	logger Logger,
+	transferRepo repository.TransferRequestRepository,
+) *TransferRetentionJob {
+	config.Clock = clockOrReal(config.Clock)
+
+	ctx, cancel := context.WithCancel(context.Background())
+
+	return &TransferRetentionJob{
+		config:       config,
+		logger:       logger,
+		transferRepo: transferRepo,
+		ctx:          ctx,
+		cancel:       cancel,
+	}
+}
+
+// Start begins periodic archival. It does nothing when retention is disabled.
+func (j *TransferRetentionJob) Start() error {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+
+	if j.isRunning {
+		return fmt.Errorf("transfer retention job is already running")
+	}
+	if j.config.MaxAge <= 0 {
+		j.logger.Info("Transfer retention disabled")
+		return nil
+	}
+
+	j.isRunning = true
+	j.logger.Info("Starting transfer retention job",
+		"interval", j.config.Interval,
+		"max_age", j.config.MaxAge,
+	)
+
+	j.wg.Add(1)
+	go j.runLoop()
+
+	return nil
+}
+
+// Stop gracefully stops the job, returning ErrShutdownTimeout if archiving outlasts the
+// shutdown timeout
+func (j *TransferRetentionJob) Stop() error {
+	j.mu.Lock()
+	if !j.isRunning {
+		j.mu.Unlock()
+		return nil
+	}
+	j.isRunning = false
+	j.mu.Unlock()
+
+	j.logger.Info("Stopping transfer retention job")
+	j.cancel()
+
+	done := make(chan struct{})
+	go func() {
+		j.wg.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		j.logger.Info("Transfer retention job stopped gracefully")
+	case <-time.After(j.config.ShutdownTimeout):
+		j.logger.Warn("Transfer retention job shutdown timed out")
+		return fmt.Errorf("%w after %s: archiving is still running", ErrShutdownTimeout, j.config.ShutdownTimeout)
+	}
+
+	return nil
+}
+
+// runLoop runs Archive on every tick until the job is stopped
+func (j *TransferRetentionJob) runLoop() {
+	defer j.wg.Done()
+
+	ticker := time.NewTicker(j.config.Interval)
+	defer ticker.Stop()
+
+	j.Archive(j.config.Clock.Now())
+
+	for {
+		select {
+		case <-ticker.C:
+			j.Archive(j.config.Clock.Now())
+		case <-j.ctx.Done():
+			j.logger.Info("Transfer retention loop shutting down")
+			return
+		}
+	}
+}
+
+// Archive flags terminal transfers that haven't changed for MaxAge as of now, in batches,
+// and returns how many were archived
+func (j *TransferRetentionJob) Archive(now time.Time) int64 {
+	if j.config.MaxAge <= 0 {
+		return 0
+	}
+
+	cutoff := now.Add(-j.config.MaxAge)
+	var archived int64
+
+	for batch := 0; batch < j.config.MaxBatches; batch++ {
+		if j.ctx.Err() != nil {
+			break
+		}
+
+		count, err := j.transferRepo.ArchiveInactiveSince(terminalTransferStatuses, cutoff, j.config.BatchSize)
+		if err != nil {
+			j.logger.Error("Failed to archive old transfers", "error", err)
+			break
+		}
+		archived += count
+
+		if count < int64(j.config.BatchSize) {
+			break
+		}
+	}
+
+	j.mu.Lock()
+	j.lastRun = now
+	j.lastArchived = archived
+	j.mu.Unlock()
+
+	if archived > 0 {
+		j.logger.Info("Archived old transfers",
+			"count", archived,
+			"cutoff", cutoff,
+		)
+	}
+
+	return archived
+}
+
+// HealthCheck returns the health status of the retention job
+func (j *TransferRetentionJob) HealthCheck() map[string]interface{} {
+	j.mu.RLock()
+	defer j.mu.RUnlock()
+
+	status := "stopped"
+	if j.isRunning {
+		status = "running"
+	} else if j.config.MaxAge <= 0 {
+		status = "disabled"
+	}
+
+	return map[string]interface{}{
+		"status":        status,
+		"last_run":      j.lastRun.UTC(),
+		"last_archived": j.lastArchived,
+		"max_age":       j.config.MaxAge.String(),
+	}
+}
@@ -0,0 +1,69 @@
+package services
+
+import (
+	"testing"
+	"time"
+
+	"bitgo-wallets-api/internal/models"
+
+	"github.com/google/uuid"
+)
+
+func TestRetentionArchivesOldTerminalTransfers(t *testing.T) {
+	repo := newMemTransferRepo()
+	now := time.Now()
+	create := func(status models.TransferStatus, age time.Duration) uuid.UUID {
+		transfer := &models.TransferRequest{Status: status, TransferType: models.WalletTypeWarm}
+		if err := repo.Create(transfer); err != nil {
+			t.Fatalf("Create() error = %v", err)
+		}
+		repo.transfers[transfer.ID].UpdatedAt = now.Add(-age)
+		return transfer.ID
+	}
+
+	oldCompleted := create(models.TransferStatusCompleted, 90*24*time.Hour)
+	oldFailed := create(models.TransferStatusFailed, 60*24*time.Hour)
+	oldPending := create(models.TransferStatusPendingApproval, 90*24*time.Hour)
+	recentCompleted := create(models.TransferStatusCompleted, time.Hour)
+
+	config := DefaultTransferRetentionConfig()
+	config.MaxAge = 30 * 24 * time.Hour
+	config.BatchSize = 1
+	job := NewTransferRetentionJob(config, testLogger{}, repo)
+
+	if archived := job.Archive(now); archived != 2 {
+		t.Errorf("Archive() = %d, want 2", archived)
+	}
+	for _, tt := range []struct {
+		name         string
+		id           uuid.UUID
+		wantArchived bool
+	}{
+		{name: "old completed", id: oldCompleted, wantArchived: true},
+		{name: "old failed", id: oldFailed, wantArchived: true},
+		{name: "old pending", id: oldPending},
+		{name: "recent completed", id: recentCompleted},
+	} {
+		stored, _ := repo.GetByID(tt.id)
+		if (stored.ArchivedAt != nil) != tt.wantArchived {
+			t.Errorf("%s transfer archived = %v, want %v", tt.name, stored.ArchivedAt != nil, tt.wantArchived)
+		}
+	}
+
+	if archived := job.Archive(now); archived != 0 {
+		t.Errorf("second Archive() = %d, want 0", archived)
+	}
+}
+
+func TestRetentionDisabledWithoutMaxAge(t *testing.T) {
+	repo := newMemTransferRepo()
+	transfer := &models.TransferRequest{Status: models.TransferStatusCompleted}
+	if err := repo.Create(transfer); err != nil {
+		t.Fatalf("Create() error = %v", err)
+	}
+
+	job := NewTransferRetentionJob(DefaultTransferRetentionConfig(), testLogger{}, repo)
+	if archived := job.Archive(time.Now().Add(365 * 24 * time.Hour)); archived != 0 {
+		t.Errorf("Archive() = %d, want 0 with retention disabled", archived)
+	}
+}
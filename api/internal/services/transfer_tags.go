@@ -0,0 +1,87 @@
+package services
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"bitgo-wallets-api/internal/models"
+)
+
+const (
+	// MaxTransferTags is the maximum number of tags a transfer can carry
+	MaxTransferTags = 10
+	// MaxTransferTagLength is the maximum length of a single tag
+	MaxTransferTagLength = 64
+	// MaxTransferCommentLength is the maximum length of a transfer comment
+	MaxTransferCommentLength = 500
+)
+
+var transferTagPattern = regexp.MustCompile(`^[a-z0-9][a-z0-9_.:-]*$`)
+
+// NormalizeTransferTags lowercases, trims and de-duplicates tags, keeping their order.
+// It returns an error if there are too many tags or a tag has an unsupported format.
+func NormalizeTransferTags(tags []string) ([]string, error) {
+	normalized := make([]string, 0, len(tags))
+	seen := make(map[string]bool, len(tags))
+
+	for _, tag := range tags {
+		tag = strings.ToLower(strings.TrimSpace(tag))
+		if tag == "" || seen[tag] {
+			continue
+		}
+		if len(tag) > MaxTransferTagLength {
+			return nil, fmt.Errorf("tag %q exceeds %d characters", tag, MaxTransferTagLength)
+		}
+		if !transferTagPattern.MatchString(tag) {
+			return nil, fmt.Errorf("tag %q may only contain letters, digits and _ . : -", tag)
+		}
+		seen[tag] = true
+		normalized = append(normalized, tag)
+	}
+
+	if len(normalized) > MaxTransferTags {
+		return nil, fmt.Errorf("at most %d tags are allowed", MaxTransferTags)
+	}
+
+	return normalized, nil
+}
+
+// ValidateTransferComment checks that a transfer comment fits the stored limit
+func ValidateTransferComment(comment string) error {
+	if len(comment) > MaxTransferCommentLength {
+		return fmt.Errorf("comment exceeds %d characters", MaxTransferCommentLength)
+	}
+	return nil
+}
+
+// requestableTransferOrigins are the origins a caller may declare when creating a transfer,
+// e.g. a treasury job sweeping deposits. auto and backfill are only set by the service.
+var requestableTransferOrigins = map[models.TransferOrigin]bool{
+	models.TransferOriginAPI:         true,
+	models.TransferOriginRetry:       true,
+	models.TransferOriginSweep:       true,
+	models.TransferOriginConsolidate: true,
+}
+
+// RequestedTransferOrigin returns the origin to record for a caller-declared origin, api when
+// none was given. It returns an error for origins callers may not declare.
+func RequestedTransferOrigin(origin models.TransferOrigin) (models.TransferOrigin, error) {
+	origin = models.TransferOrigin(strings.ToLower(strings.TrimSpace(string(origin))))
+	if origin == "" {
+		return models.TransferOriginAPI, nil
+	}
+	if !requestableTransferOrigins[origin] {
+		return "", fmt.Errorf("origin must be one of: api, retry, sweep, consolidate")
+	}
+	return origin, nil
+}
+
+// optionalString returns nil for blank strings so empty values aren't persisted
+func optionalString(s string) *string {
+	s = strings.TrimSpace(s)
+	if s == "" {
+		return nil
+	}
+	return &s
+}
@@ -0,0 +1,84 @@
+package services
+
+import (
+	"context"
+	"fmt"
+
+	"bitgo-wallets-api/internal/models"
+	"bitgo-wallets-api/internal/repository"
+)
+
+// metadataTrustedDestination records on a transfer which allowlist made its recipient trusted
+const metadataTrustedDestination = "trusted_destination"
+
+// Where a trusted destination was listed
+const (
+	TrustedDestinationWallet = "wallet" // The wallet's own trusted addresses
+	TrustedDestinationConfig = "config" // The service-wide TrustedAddresses
+)
+
+// trustedDestination returns which allowlist trusts the recipient address, checking the
+// wallet's own list before the service-wide one, or "" when the address isn't trusted.
+// Addresses compare as the blocked-address denylist does, so hex case doesn't matter. The
+// service-wide list isn't tied to a coin or checked when it's set, so an entry there only
+// counts when it's a valid address for the wallet's coin and isn't blocked.
+func (wws *WarmWalletService) trustedDestination(ctx context.Context, wallet *models.Wallet, address string) string {
+	normalized := repository.NormalizeBlockedAddress(address)
+	if normalized == "" {
+		return ""
+	}
+
+	for _, trusted := range wallet.TrustedAddresses {
+		if repository.NormalizeBlockedAddress(trusted) == normalized {
+			return TrustedDestinationWallet
+		}
+	}
+	for _, trusted := range wws.config.TrustedAddresses {
+		if repository.NormalizeBlockedAddress(trusted) == normalized {
+			if !wws.configTrustedAddressUsable(ctx, wallet, trusted) {
+				return ""
+			}
+			return TrustedDestinationConfig
+		}
+	}
+	return ""
+}
+
+// configTrustedAddressUsable reports whether a TrustedAddresses entry may be trusted for the
+// wallet: it must be valid for the wallet's coin and not on the blocked-address denylist.
+// Entries that can't be checked aren't trusted.
+func (wws *WarmWalletService) configTrustedAddressUsable(ctx context.Context, wallet *models.Wallet, address string) bool {
+	valid, err := wws.bitgoClient.ValidateAddress(ctx, wallet.Coin, address)
+	if err != nil || !valid {
+		wws.logger.Warn("Ignoring trusted address that isn't valid for the wallet's coin",
+			"address", address,
+			"coin", wallet.Coin,
+			"error", err,
+		)
+		return false
+	}
+
+	if wws.config.BlockedAddresses == nil {
+		return true
+	}
+	blocked, err := wws.config.BlockedAddresses.GetByAddress(address)
+	if err != nil || blocked != nil {
+		wws.logger.Warn("Ignoring trusted address that is blocked",
+			"address", address,
+			"error", err,
+		)
+		return false
+	}
+	return true
+}
+
+// markTrustedDestination records on the transfer that it bypassed risk review and approvals
+// because its recipient is trusted. The status reason lands in the transfer's event log.
+func markTrustedDestination(transfer *models.TransferRequest, source string) {
+	reason := fmt.Sprintf("recipient is a trusted destination (%s allowlist); risk review and approvals bypassed", source)
+	transfer.StatusReason = &reason
+	if transfer.Metadata == nil {
+		transfer.Metadata = models.JSON{}
+	}
+	transfer.Metadata[metadataTrustedDestination] = source
+}
@@ -0,0 +1,62 @@
+package services
+
+import (
+	"context"
+	"testing"
+
+	"bitgo-wallets-api/internal/bitgo"
+	"bitgo-wallets-api/internal/models"
+	"bitgo-wallets-api/internal/repository"
+)
+
+const (
+	testTrustedAddress = "bc1qar0srrr7xfkvy5l643lydnw9re59gtzzwf5mdq"
+	testBlockedAddress = "bc1q9d4ywgfnd8h43da5tpcxcn6ajv590cg6d3tg6axemvljvt2k76zs50tv4q"
+	testEthAddress     = "0x52908400098527886E0F7030069857D2E4169EE7"
+)
+
+// blockedListRepo reports the listed addresses as blocked; other methods aren't used
+type blockedListRepo struct {
+	repository.BlockedAddressRepository
+	blocked map[string]bool
+}
+
+func (r *blockedListRepo) GetByAddress(address string) (*models.BlockedAddress, error) {
+	if r.blocked[address] {
+		return &models.BlockedAddress{Address: address}, nil
+	}
+	return nil, nil
+}
+
+func TestTrustedDestination(t *testing.T) {
+	wws := &WarmWalletService{
+		bitgoClient: bitgo.NewSimulatedClient(bitgo.SimulationConfig{}, testLogger{}),
+		logger:      testLogger{},
+		config: WarmWalletConfig{
+			TrustedAddresses: []string{testTrustedAddress, testEthAddress, testBlockedAddress},
+			BlockedAddresses: &blockedListRepo{blocked: map[string]bool{testBlockedAddress: true}},
+		},
+	}
+	btcWallet := &models.Wallet{Coin: "btc", TrustedAddresses: []string{"bc1qwallettrusted"}}
+
+	tests := []struct {
+		name    string
+		address string
+		want    string
+	}{
+		{name: "wallet list", address: " bc1qwallettrusted ", want: TrustedDestinationWallet},
+		{name: "config list", address: testTrustedAddress, want: TrustedDestinationConfig},
+		{name: "config entry for another coin", address: testEthAddress, want: ""},
+		{name: "blocked config entry", address: testBlockedAddress, want: ""},
+		{name: "unlisted", address: "bc1qsomeoneelse", want: ""},
+		{name: "empty", address: " ", want: ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := wws.trustedDestination(context.Background(), btcWallet, tt.address); got != tt.want {
+				t.Errorf("trustedDestination(%q) = %q, want %q", tt.address, got, tt.want)
+			}
+		})
+	}
+}
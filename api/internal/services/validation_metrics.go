@@ -0,0 +1,80 @@
+package services
+
+import (
+	"sort"
+	"sync"
+
+	"bitgo-wallets-api/internal/models"
+)
+
+// ValidationMetrics counts transfer validation failures per wallet type and field
+type ValidationMetrics struct {
+	mu     sync.RWMutex
+	counts map[validationMetricKey]int
+}
+
+type validationMetricKey struct {
+	transferType models.WalletType
+	field        string
+}
+
+// RejectedFieldCount is how often a field was rejected for a wallet type
+type RejectedFieldCount struct {
+	TransferType models.WalletType `json:"transfer_type"`
+	Field        string            `json:"field"`
+	Count        int               `json:"count"`
+}
+
+// NewValidationMetrics creates an empty set of validation counters
+func NewValidationMetrics() *ValidationMetrics {
+	return &ValidationMetrics{
+		counts: make(map[validationMetricKey]int),
+	}
+}
+
+// Record increments the counter for a rejected field
+func (m *ValidationMetrics) Record(transferType models.WalletType, field string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.counts[validationMetricKey{transferType: transferType, field: field}]++
+}
+
+// Count returns how often a field was rejected for a wallet type
+func (m *ValidationMetrics) Count(transferType models.WalletType, field string) int {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return m.counts[validationMetricKey{transferType: transferType, field: field}]
+}
+
+// TopRejectedFields returns the most rejected fields, most frequent first. When transferType
+// is empty, counts across all wallet types are returned.
+func (m *ValidationMetrics) TopRejectedFields(transferType models.WalletType, n int) []RejectedFieldCount {
+	m.mu.RLock()
+	results := make([]RejectedFieldCount, 0, len(m.counts))
+	for key, count := range m.counts {
+		if transferType != "" && key.transferType != transferType {
+			continue
+		}
+		results = append(results, RejectedFieldCount{
+			TransferType: key.transferType,
+			Field:        key.field,
+			Count:        count,
+		})
+	}
+	m.mu.RUnlock()
+
+	sort.Slice(results, func(i, j int) bool {
+		if results[i].Count != results[j].Count {
+			return results[i].Count > results[j].Count
+		}
+		if results[i].TransferType != results[j].TransferType {
+			return results[i].TransferType < results[j].TransferType
+		}
+		return results[i].Field < results[j].Field
+	})
+
+	if n > 0 && len(results) > n {
+		results = results[:n]
+	}
+	return results
+}
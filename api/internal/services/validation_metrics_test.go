@@ -0,0 +1,61 @@
+package services
+
+import (
+	"context"
+	"testing"
+
+	"bitgo-wallets-api/internal/bitgo"
+	"bitgo-wallets-api/internal/models"
+
+	"github.com/google/uuid"
+)
+
+func TestValidationFailuresAreCountedPerField(t *testing.T) {
+	withoutSimulatedDelay(t)
+	metrics := NewValidationMetrics()
+	wallet := newTestWarmWallet()
+	wws := NewWarmWalletService(
+		bitgo.NewSimulatedClient(bitgo.SimulationConfig{}, testLogger{}),
+		newMemWalletRepo(wallet),
+		newMemTransferRepo(),
+		nopNotifier{},
+		testLogger{},
+		DefaultWarmWalletConfig(),
+		metrics,
+		nil,
+		nil,
+	)
+
+	request := newTestWarmRequest(wallet, "0.1")
+	request.RequestorName = " "
+	for i := 0; i < 2; i++ {
+		if _, err := wws.CreateWarmTransferRequest(context.Background(), request, uuid.New()); err == nil {
+			t.Fatal("CreateWarmTransferRequest() succeeded without a requestor name")
+		}
+	}
+
+	if got := metrics.Count(models.WalletTypeWarm, "requestorName"); got != 2 {
+		t.Errorf("warm requestorName rejections = %d, want 2", got)
+	}
+	for _, field := range []string{"recipientAddress", "amountString", "requestorEmail"} {
+		if got := metrics.Count(models.WalletTypeWarm, field); got != 0 {
+			t.Errorf("warm %s rejections = %d, want 0", field, got)
+		}
+	}
+	if got := metrics.Count(models.WalletTypeCold, "requestorName"); got != 0 {
+		t.Errorf("cold requestorName rejections = %d, want 0", got)
+	}
+
+	top := metrics.TopRejectedFields("", 5)
+	if len(top) != 1 || top[0] != (RejectedFieldCount{TransferType: models.WalletTypeWarm, Field: "requestorName", Count: 2}) {
+		t.Errorf("TopRejectedFields() = %+v, want only warm requestorName twice", top)
+	}
+
+	// A valid request counts nothing
+	if _, err := wws.CreateWarmTransferRequest(context.Background(), newTestWarmRequest(wallet, "0.1"), uuid.New()); err != nil {
+		t.Fatalf("CreateWarmTransferRequest() error = %v", err)
+	}
+	if got := len(metrics.TopRejectedFields("", 5)); got != 1 {
+		t.Errorf("%d rejected fields after a valid request, want 1", got)
+	}
+}
@@ -0,0 +1,274 @@
+package services
+
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	"bitgo-wallets-api/internal/models"
+	"bitgo-wallets-api/internal/repository"
+
+	"github.com/google/uuid"
+)
+
+// Wallet metadata keys recording why and when a wallet was frozen automatically
+const (
+	metadataFrozenReason = "frozen_reason"
+	metadataFrozenBy     = "frozen_by"
+	metadataFrozenAt     = "frozen_at"
+)
+
+// velocityGuardActor is recorded as who froze a wallet the guard froze
+const velocityGuardActor = "velocity_guard"
+
+// VelocityGuardConfig configures the automatic freeze on anomalous outbound velocity
+type VelocityGuardConfig struct {
+	Enabled              bool
+	Window               time.Duration // Outbound volume is compared over windows this long
+	BaselinePeriod       time.Duration // How much history the baseline window average is taken from
+	Multiplier           float64       // A window above this multiple of the baseline freezes the wallet
+	MinBaselineTransfers int           // Fewer transfers in the baseline period means no baseline yet
+	Clock                Clock         // Supplies the current time; nil uses the wall clock
+}
+
+// DefaultVelocityGuardConfig returns sensible defaults
+func DefaultVelocityGuardConfig() VelocityGuardConfig {
+	return VelocityGuardConfig{
+		Enabled:              true,
+		Window:               time.Hour,
+		BaselinePeriod:       7 * 24 * time.Hour,
+		Multiplier:           5,
+		MinBaselineTransfers: 5,
+	}
+}
+
+// VelocitySpikeError is returned for a transfer that pushed its wallet's outbound volume in
+// the current window past the allowed multiple of the wallet's baseline
+type VelocitySpikeError struct {
+	WindowVolume float64
+	Baseline     float64
+	Multiplier   float64
+	Window       time.Duration
+}
+
+func (e *VelocitySpikeError) Error() string {
+	return fmt.Sprintf("outbound volume of %g in the last %s exceeds %gx the wallet's baseline of %g per %s",
+		e.WindowVolume, e.Window, e.Multiplier, e.Baseline, e.Window)
+}
+
+type velocityEvent struct {
+	at     time.Time
+	amount float64
+}
+
+// walletVelocity is the outbound history tracked for one wallet
+type walletVelocity struct {
+	events  []velocityEvent
+	resetAt time.Time // Events before this don't count towards the current window
+}
+
+// VelocityGuard tracks each wallet's outbound transfer volume and freezes a wallet whose
+// volume in the current window spikes beyond a multiple of its baseline, the average volume
+// per window over the baseline period. The freeze blocks further transfers until an operator
+// unfreezes the wallet. History lives in memory and is rebuilt from a wallet's recent
+// transfers the first time the wallet is seen.
+type VelocityGuard struct {
+	config        VelocityGuardConfig
+	walletRepo    repository.WalletRepository
+	transferRepo  repository.TransferRequestRepository
+	notifications NotificationService
+	logger        Logger
+
+	mu      sync.Mutex
+	wallets map[uuid.UUID]*walletVelocity
+}
+
+// NewVelocityGuard creates a velocity guard
+func NewVelocityGuard(
+	config VelocityGuardConfig,
+	walletRepo repository.WalletRepository,
+	transferRepo repository.TransferRequestRepository,
+	notifications NotificationService,
+	logger Logger,
+) *VelocityGuard {
+	config.Clock = clockOrReal(config.Clock)
+	return &VelocityGuard{
+		config:        config,
+		walletRepo:    walletRepo,
+		transferRepo:  transferRepo,
+		notifications: notifications,
+		logger:        logger,
+		wallets:       make(map[uuid.UUID]*walletVelocity),
+	}
+}
+
+// Check returns a *VelocitySpikeError when a transfer of amount would push the wallet's
+// outbound volume in the current window past the allowed multiple of its baseline. The
+// wallet is frozen and operators alerted before the error is returned. Wallets without
+// enough history for a baseline are never frozen.
+func (g *VelocityGuard) Check(wallet *models.Wallet, amount float64) error {
+	if !g.config.Enabled || g.config.Window <= 0 || g.config.Multiplier <= 0 {
+		return nil
+	}
+
+	spike := g.spike(wallet, amount)
+	if spike == nil {
+		return nil
+	}
+
+	g.freeze(wallet, spike)
+	return spike
+}
+
+// Record adds a created transfer to the wallet's outbound history
+func (g *VelocityGuard) Record(walletID uuid.UUID, amount float64) {
+	if !g.config.Enabled {
+		return
+	}
+
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	velocity := g.velocity(walletID)
+	velocity.events = append(velocity.events, velocityEvent{at: g.config.Clock.Now(), amount: amount})
+}
+
+// Reset starts a new window for the wallet, e.g. when an operator unfreezes it, so the
+// volume that tripped the freeze doesn't trip it again. That volume still counts towards
+// the baseline.
+func (g *VelocityGuard) Reset(walletID uuid.UUID) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	g.velocity(walletID).resetAt = g.config.Clock.Now()
+}
+
+func (g *VelocityGuard) spike(wallet *models.Wallet, amount float64) *VelocitySpikeError {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	now := g.config.Clock.Now()
+	windowStart := now.Add(-g.config.Window)
+	baselineStart := now.Add(-g.config.BaselinePeriod)
+	if wallet.CreatedAt.After(baselineStart) {
+		baselineStart = wallet.CreatedAt
+	}
+
+	velocity := g.velocity(wallet.ID)
+	velocity.prune(now.Add(-g.config.BaselinePeriod))
+
+	current := amount
+	var baselineVolume float64
+	baselineTransfers := 0
+	for _, event := range velocity.events {
+		switch {
+		case !event.at.Before(windowStart):
+			if !event.at.Before(velocity.resetAt) {
+				current += event.amount
+			}
+		case !event.at.Before(baselineStart):
+			baselineVolume += event.amount
+			baselineTransfers++
+		}
+	}
+
+	// The baseline is averaged over the history before the current window
+	span := windowStart.Sub(baselineStart)
+	if span < g.config.Window || baselineTransfers < g.config.MinBaselineTransfers {
+		return nil
+	}
+	baseline := baselineVolume * float64(g.config.Window) / float64(span)
+
+	if current <= g.config.Multiplier*baseline {
+		return nil
+	}
+	return &VelocitySpikeError{
+		WindowVolume: current,
+		Baseline:     baseline,
+		Multiplier:   g.config.Multiplier,
+		Window:       g.config.Window,
+	}
+}
+
+// freeze marks the wallet frozen and alerts operators
+func (g *VelocityGuard) freeze(wallet *models.Wallet, spike *VelocitySpikeError) {
+	reason := spike.Error()
+	wallet.Frozen = true
+	if wallet.Metadata == nil {
+		wallet.Metadata = models.JSON{}
+	}
+	wallet.Metadata[metadataFrozenReason] = reason
+	wallet.Metadata[metadataFrozenBy] = velocityGuardActor
+	wallet.Metadata[metadataFrozenAt] = g.config.Clock.Now().UTC().Format(time.RFC3339)
+
+	if err := g.walletRepo.SetFrozen(wallet.ID, true, wallet.Metadata); err != nil {
+		// The transfer is still rejected; alert anyway so someone freezes it by hand
+		g.logger.Error("Failed to freeze wallet after velocity spike",
+			"wallet_id", wallet.ID,
+			"error", err,
+		)
+	} else {
+		g.logger.Warn("Wallet frozen after velocity spike",
+			"wallet_id", wallet.ID,
+			"window_volume", spike.WindowVolume,
+			"baseline", spike.Baseline,
+		)
+	}
+
+	g.notifications.SendWalletFrozenNotification(wallet, reason)
+}
+
+// FrozenReason returns why the wallet was frozen automatically, or "" if it wasn't
+func FrozenReason(wallet *models.Wallet) string {
+	reason, _ := wallet.Metadata[metadataFrozenReason].(string)
+	return reason
+}
+
+// ClearFrozenMetadata removes the automatic freeze details from a wallet being unfrozen
+func ClearFrozenMetadata(wallet *models.Wallet) {
+	for _, key := range []string{metadataFrozenReason, metadataFrozenBy, metadataFrozenAt} {
+		delete(wallet.Metadata, key)
+	}
+}
+
+// velocity returns the wallet's history, loading it from the database the first time
+func (g *VelocityGuard) velocity(walletID uuid.UUID) *walletVelocity {
+	if velocity, ok := g.wallets[walletID]; ok {
+		return velocity
+	}
+
+	velocity := &walletVelocity{}
+	transfers, err := g.transferRepo.ListByWallet(walletID, repository.TransferListFilter{IncludeArchived: true}, maxHydratedTransfers, 0)
+	if err != nil {
+		// Start empty; without a baseline the wallet just isn't guarded until it builds one
+		g.logger.Warn("Failed to load transfer history for velocity guard",
+			"wallet_id", walletID,
+			"error", err,
+		)
+	}
+
+	cutoff := g.config.Clock.Now().Add(-g.config.BaselinePeriod)
+	for i := len(transfers) - 1; i >= 0; i-- {
+		transfer := transfers[i]
+		if transfer.CreatedAt.Before(cutoff) {
+			continue
+		}
+		amount, err := parseAmount(transfer.AmountString)
+		if err != nil {
+			continue
+		}
+		velocity.events = append(velocity.events, velocityEvent{at: transfer.CreatedAt, amount: amount})
+	}
+
+	g.wallets[walletID] = velocity
+	return velocity
+}
+
+// prune forgets events older than cutoff
+func (v *walletVelocity) prune(cutoff time.Time) {
+	keep := 0
+	for keep < len(v.events) && v.events[keep].at.Before(cutoff) {
+		keep++
+	}
+	v.events = v.events[keep:]
+}
@@ -0,0 +1,44 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"bitgo-wallets-api/internal/bitgo"
+	"bitgo-wallets-api/internal/models"
+	"bitgo-wallets-api/internal/repository"
+)
+
+// balanceIsStale reports whether the wallet's cached balance is older than maxAge as of now.
+// A zero maxAge trusts the cached balance; a balance that was never synced is always stale.
+func balanceIsStale(wallet *models.Wallet, maxAge time.Duration, now time.Time) bool {
+	if maxAge <= 0 {
+		return false
+	}
+	return wallet.BalanceSyncedAt == nil || now.Sub(*wallet.BalanceSyncedAt) > maxAge
+}
+
+// refreshStaleBalance fetches the wallet's balance from BitGo and saves it when the cached
+// figures are stale, updating wallet in place. It reports whether a refresh happened.
+func refreshStaleBalance(ctx context.Context, client bitgo.BitGoAPI, walletRepo repository.WalletRepository, wallet *models.Wallet, maxAge time.Duration, now time.Time) (bool, error) {
+	if !balanceIsStale(wallet, maxAge, now) {
+		return false, nil
+	}
+
+	balance, err := client.GetWalletBalance(ctx, wallet.BitgoWalletID, wallet.Coin)
+	if err != nil {
+		return false, fmt.Errorf("failed to refresh wallet balance: %w", err)
+	}
+
+	wallet.BalanceString = balance.Balance
+	wallet.ConfirmedBalanceString = balance.ConfirmedBalance
+	wallet.SpendableBalanceString = balance.SpendableBalance
+	wallet.BalanceSyncedAt = &now
+
+	if err := walletRepo.Update(wallet); err != nil {
+		return true, fmt.Errorf("failed to save refreshed wallet balance: %w", err)
+	}
+
+	return true, nil
+}
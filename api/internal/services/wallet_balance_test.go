@@ -0,0 +1,114 @@
+package services
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"bitgo-wallets-api/internal/bitgo"
+)
+
+// balanceClient reports spendable as the wallet's BitGo balance and counts the lookups
+type balanceClient struct {
+	bitgo.BitGoAPI
+
+	spendable string
+	fetches   int
+}
+
+func (c *balanceClient) GetWalletBalance(ctx context.Context, walletID, coin string) (*bitgo.WalletBalance, error) {
+	c.fetches++
+	return &bitgo.WalletBalance{WalletID: walletID, Coin: coin, Balance: c.spendable, ConfirmedBalance: c.spendable, SpendableBalance: c.spendable}, nil
+}
+
+func TestRefreshStaleBalance(t *testing.T) {
+	now := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	syncedAgo := func(d time.Duration) *time.Time {
+		synced := now.Add(-d)
+		return &synced
+	}
+
+	tests := []struct {
+		name        string
+		syncedAt    *time.Time
+		maxAge      time.Duration
+		wantRefresh bool
+	}{
+		{name: "never synced", maxAge: 5 * time.Minute, wantRefresh: true},
+		{name: "stale", syncedAt: syncedAgo(10 * time.Minute), maxAge: 5 * time.Minute, wantRefresh: true},
+		{name: "fresh", syncedAt: syncedAgo(time.Minute), maxAge: 5 * time.Minute},
+		{name: "cache trusted", syncedAt: syncedAgo(24 * time.Hour)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			wallet := newTestWarmWallet()
+			wallet.BalanceSyncedAt = tt.syncedAt
+			walletRepo := newMemWalletRepo(wallet)
+			client := &balanceClient{spendable: "0.5"}
+
+			refreshed, err := refreshStaleBalance(context.Background(), client, walletRepo, wallet, tt.maxAge, now)
+			if err != nil {
+				t.Fatalf("refreshStaleBalance() error = %v", err)
+			}
+			if refreshed != tt.wantRefresh || (client.fetches == 1) != tt.wantRefresh {
+				t.Fatalf("refreshed = %v after %d BitGo lookups, want %v", refreshed, client.fetches, tt.wantRefresh)
+			}
+
+			stored, _ := walletRepo.GetByID(wallet.ID)
+			wantSpendable := "10"
+			if tt.wantRefresh {
+				wantSpendable = "0.5"
+				if stored.BalanceSyncedAt == nil || !stored.BalanceSyncedAt.Equal(now) {
+					t.Errorf("balance_synced_at = %v, want %v", stored.BalanceSyncedAt, now)
+				}
+			}
+			if wallet.SpendableBalanceString != wantSpendable || stored.SpendableBalanceString != wantSpendable {
+				t.Errorf("spendable = %s in memory, %s stored; want %s", wallet.SpendableBalanceString, stored.SpendableBalanceString, wantSpendable)
+			}
+		})
+	}
+}
+
+func TestWarmValidationUsesRefreshedBalance(t *testing.T) {
+	clock := &testClock{}
+	clock.Set(time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC))
+
+	tests := []struct {
+		name        string
+		syncedAgo   time.Duration
+		wantFetches int
+		wantErr     bool
+	}{
+		// The cache says 10 BTC, but BitGo now reports 0.5, which can't cover 1 BTC
+		{name: "stale cache refreshed", syncedAgo: time.Hour, wantFetches: 1, wantErr: true},
+		{name: "fresh cache trusted", syncedAgo: time.Minute},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			wallet := newTestWarmWallet()
+			synced := clock.Now().Add(-tt.syncedAgo)
+			wallet.BalanceSyncedAt = &synced
+
+			config := DefaultWarmWalletConfig()
+			config.BalanceMaxAge = 5 * time.Minute
+			config.Clock = clock
+			client := &balanceClient{BitGoAPI: bitgo.NewSimulatedClient(bitgo.SimulationConfig{}, testLogger{}), spendable: "0.5"}
+			wws := NewWarmWalletService(client, newMemWalletRepo(wallet), newMemTransferRepo(), nopNotifier{}, testLogger{}, config, nil, nil, nil)
+
+			var amountErrors []WarmTransferValidationError
+			for _, validationErr := range wws.ValidateWarmTransferRequest(context.Background(), newTestWarmRequest(wallet, "1.0")) {
+				if validationErr.Field == "amountString" {
+					amountErrors = append(amountErrors, validationErr)
+				}
+			}
+			if client.fetches != tt.wantFetches {
+				t.Errorf("%d BitGo balance lookups, want %d", client.fetches, tt.wantFetches)
+			}
+			if (len(amountErrors) > 0) != tt.wantErr {
+				t.Errorf("amountString errors = %v, want errors = %v", amountErrors, tt.wantErr)
+			}
+		})
+	}
+}
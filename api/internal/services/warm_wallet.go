@@ -2,9 +2,11 @@ package services
 
 import (
 	"context"
+	stderrors "errors"
 	"fmt"
 	"regexp"
 	"strings"
+	"sync"
 	"time"
 
 	"bitgo-wallets-api/internal/bitgo"
@@ -16,69 +18,119 @@ import (
 
 // WarmWalletService handles warm wallet specific operations
 type WarmWalletService struct {
-	bitgoClient     *bitgo.Client
+	bitgoClient     bitgo.BitGoAPI
 	walletRepo      repository.WalletRepository
 	transferRepo    repository.TransferRequestRepository
 	notificationSvc NotificationService
 	logger          Logger
 	config          WarmWalletConfig
+
+	validationMetrics *ValidationMetrics
+	priceOracle       PriceOracle
+	flags             *FeatureFlagStore
+
+	// Automated processing runs in goroutines bounded by autoProcessSlots and tracked
+	// by autoProcessWG so Stop can drain them. walletSlots additionally bounds how many
+	// transfers from one wallet run at once.
+	autoProcessSlots chan struct{}
+	walletSlots      map[uuid.UUID]*walletSlot
+	autoProcessWG    sync.WaitGroup
+	autoProcessMu    sync.Mutex
+	autoProcessing   int
+	stopping         chan struct{}
+	stopped          bool
 }
 
 // WarmWalletConfig contains configuration for warm wallet operations
 type WarmWalletConfig struct {
 	// Validation settings
-	MaxDailyTransferLimit  string   `json:"maxDailyTransferLimit"`
-	MaxSingleTransferLimit string   `json:"maxSingleTransferLimit"`
-	AllowedAddressPatterns []string `json:"allowedAddressPatterns"`
-	RequiredApprovals      int      `json:"requiredApprovals"`
-	ApprovalTimeoutHours   int      `json:"approvalTimeoutHours"`
+	MaxDailyTransferLimit  string        `json:"maxDailyTransferLimit"`
+	MaxSingleTransferLimit string        `json:"maxSingleTransferLimit"`
+	MaxSingleTransferUSD   float64       `json:"maxSingleTransferUSD"` // Optional fiat ceiling; 0 disables it
+	BalanceMaxAge          time.Duration `json:"balanceMaxAge"`        // Refresh cached balances older than this before validating; 0 trusts the cache
+	AllowedAddressPatterns []string      `json:"allowedAddressPatterns"`
+	TrustedAddresses       []string      `json:"trustedAddresses"` // Recipients that skip risk review and approvals for every warm wallet
+	RequiredApprovals      int           `json:"requiredApprovals"`
+	ApprovalTimeoutHours   int           `json:"approvalTimeoutHours"`
+	MinConfirms            int           `json:"minConfirms"` // Confirmations non-change inputs need before they can be spent
 
 	// SLA settings (faster than cold)
 	InitialResponseSLA time.Duration `json:"initialResponseSLA"`
 	ProcessingSLA      time.Duration `json:"processingSLA"`
 	CompletionSLA      time.Duration `json:"completionSLA"`
 
+	// Per-urgency SLA adjustments; an override replaces the SLA set, otherwise the multiplier scales it
+	UrgencySLAMultipliers map[string]float64    `json:"urgencySLAMultipliers"`
+	UrgencySLAOverrides   map[string]SLATargets `json:"urgencySLAOverrides"`
+
 	// Automated workflow settings
-	AutoProcessThreshold  string        `json:"autoProcessThreshold"`
-	ManualReviewThreshold string        `json:"manualReviewThreshold"`
-	RiskScoringEnabled    bool          `json:"riskScoringEnabled"`
-	MaxRiskScore          float64       `json:"maxRiskScore"`
-	VelocityCheckEnabled  bool          `json:"velocityCheckEnabled"`
-	EscalationThreshold   time.Duration `json:"escalationThreshold"`
+	AutoProcessThreshold             string        `json:"autoProcessThreshold"`
+	ManualReviewThreshold            string        `json:"manualReviewThreshold"`
+	BusinessPurposeRequiredThreshold string        `json:"businessPurposeRequiredThreshold"` // Purpose required at or above this amount, independent of manual review
+	RiskScoringEnabled               bool          `json:"riskScoringEnabled"`
+	MaxRiskScore                     float64       `json:"maxRiskScore"`
+	VelocityCheckEnabled             bool          `json:"velocityCheckEnabled"`
+	EscalationThreshold              time.Duration `json:"escalationThreshold"`
+
+	// Automated processing concurrency
+	MaxConcurrentAutoProcessing          int           `json:"maxConcurrentAutoProcessing"`
+	MaxConcurrentAutoProcessingPerWallet int           `json:"maxConcurrentAutoProcessingPerWallet"` // 1 processes each wallet's transfers one at a time
+	ShutdownTimeout                      time.Duration `json:"shutdownTimeout"`                      // How long Stop waits for in-flight processing
+
+	// Clock supplies the current time; nil uses the wall clock
+	Clock Clock `json:"-"`
+
+	// Reservations holds balance for in-flight transfers; nil checks each transfer alone
+	Reservations *BalanceReservations `json:"-"`
+
+	// BlockedAddresses is the recipient denylist; a blocked address is never a trusted
+	// destination. nil skips the check.
+	BlockedAddresses repository.BlockedAddressRepository `json:"-"`
 }
 
 // DefaultWarmWalletConfig returns sensible defaults for warm wallet operations
 func DefaultWarmWalletConfig() WarmWalletConfig {
 	return WarmWalletConfig{
-		MaxDailyTransferLimit:  "100.0",          // 100 BTC or equivalent (higher than cold)
-		MaxSingleTransferLimit: "25.0",           // 25 BTC or equivalent (higher than cold)
-		AllowedAddressPatterns: []string{},       // Empty = no restrictions
-		RequiredApprovals:      1,                // Only 1 approval needed for warm
-		ApprovalTimeoutHours:   24,               // 1 day (faster than cold)
-		InitialResponseSLA:     15 * time.Minute, // 15 minutes for initial response
-		ProcessingSLA:          2 * time.Hour,    // 2 hours for processing
-		CompletionSLA:          12 * time.Hour,   // 12 hours total completion
-		AutoProcessThreshold:   "5.0",            // Auto-process up to 5 BTC
-		ManualReviewThreshold:  "10.0",           // Manual review for 10+ BTC
-		RiskScoringEnabled:     true,             // Enable risk scoring
-		MaxRiskScore:           0.7,              // Max acceptable risk score
-		VelocityCheckEnabled:   true,             // Enable velocity checks
-		EscalationThreshold:    6 * time.Hour,    // Escalate after 6 hours
+		MaxDailyTransferLimit:            "100.0",          // 100 BTC or equivalent (higher than cold)
+		MaxSingleTransferLimit:           "25.0",           // 25 BTC or equivalent (higher than cold)
+		AllowedAddressPatterns:           []string{},       // Empty = no restrictions
+		RequiredApprovals:                1,                // Only 1 approval needed for warm
+		ApprovalTimeoutHours:             24,               // 1 day (faster than cold)
+		InitialResponseSLA:               15 * time.Minute, // 15 minutes for initial response
+		ProcessingSLA:                    2 * time.Hour,    // 2 hours for processing
+		CompletionSLA:                    12 * time.Hour,   // 12 hours total completion
+		AutoProcessThreshold:             "5.0",            // Auto-process up to 5 BTC
+		ManualReviewThreshold:            "10.0",           // Manual review for 10+ BTC
+		BusinessPurposeRequiredThreshold: "10.0",           // Business purpose for 10+ BTC
+		RiskScoringEnabled:               true,             // Enable risk scoring
+		MaxRiskScore:                     0.7,              // Max acceptable risk score
+		VelocityCheckEnabled:             true,             // Enable velocity checks
+		EscalationThreshold:              6 * time.Hour,    // Escalate after 6 hours
+		UrgencySLAMultipliers:            DefaultUrgencySLAMultipliers(),
+
+		MaxConcurrentAutoProcessing:          4,
+		MaxConcurrentAutoProcessingPerWallet: 1,
+		ShutdownTimeout:                      30 * time.Second,
 	}
 }
 
 // WarmTransferRequest represents a warm storage transfer request
 type WarmTransferRequest struct {
-	WalletID         uuid.UUID `json:"walletId"`
-	RecipientAddress string    `json:"recipientAddress"`
-	AmountString     string    `json:"amountString"`
-	Coin             string    `json:"coin"`
-	BusinessPurpose  string    `json:"businessPurpose"`
-	RequestorName    string    `json:"requestorName"`
-	RequestorEmail   string    `json:"requestorEmail"`
-	UrgencyLevel     string    `json:"urgencyLevel"`
-	Memo             string    `json:"memo,omitempty"`
-	AutoProcess      bool      `json:"autoProcess,omitempty"` // Allow automatic processing
+	WalletID         uuid.UUID             `json:"wallet_id"`
+	RecipientAddress string                `json:"recipient_address"`
+	AmountString     string                `json:"amount_string"`
+	Coin             string                `json:"coin"`
+	BusinessPurpose  string                `json:"business_purpose"`
+	RequestorName    string                `json:"requestor_name"`
+	RequestorEmail   string                `json:"requestor_email"`
+	UrgencyLevel     string                `json:"urgency_level"`
+	Memo             string                `json:"memo,omitempty"`
+	DestinationTag   string                `json:"destination_tag,omitempty"`
+	Comment          string                `json:"comment,omitempty"`
+	Tags             []string              `json:"tags,omitempty"`
+	AllowSelfSend    bool                  `json:"allow_self_send,omitempty"` // Checked by the handler before creation
+	Origin           models.TransferOrigin `json:"origin,omitempty"`          // retry, sweep or consolidate; defaults to api
+	AutoProcess      bool                  `json:"auto_process,omitempty"`    // Allow automatic processing
 }
 
 // WarmTransferValidationError represents validation errors for warm transfers
@@ -117,20 +169,166 @@ const (
 
 // NewWarmWalletService creates a new warm wallet service
 func NewWarmWalletService(
-	bitgoClient *bitgo.Client,
+	bitgoClient bitgo.BitGoAPI,
 	walletRepo repository.WalletRepository,
 	transferRepo repository.TransferRequestRepository,
 	notificationSvc NotificationService,
 	logger Logger,
 	config WarmWalletConfig,
+	validationMetrics *ValidationMetrics,
+	priceOracle PriceOracle,
+	flags *FeatureFlagStore,
 ) *WarmWalletService {
+	config.Clock = clockOrReal(config.Clock)
+
+	maxConcurrent := config.MaxConcurrentAutoProcessing
+	if maxConcurrent <= 0 {
+		maxConcurrent = 1
+	}
+	if config.MaxConcurrentAutoProcessingPerWallet <= 0 {
+		config.MaxConcurrentAutoProcessingPerWallet = 1
+	}
+
 	return &WarmWalletService{
-		bitgoClient:     bitgoClient,
-		walletRepo:      walletRepo,
-		transferRepo:    transferRepo,
-		notificationSvc: notificationSvc,
-		logger:          logger,
-		config:          config,
+		bitgoClient:       bitgoClient,
+		walletRepo:        walletRepo,
+		transferRepo:      transferRepo,
+		notificationSvc:   notificationSvc,
+		logger:            logger,
+		config:            config,
+		validationMetrics: validationMetrics,
+		priceOracle:       priceOracle,
+		flags:             flags,
+		autoProcessSlots:  make(chan struct{}, maxConcurrent),
+		walletSlots:       make(map[uuid.UUID]*walletSlot),
+		stopping:          make(chan struct{}),
+	}
+}
+
+// Stop prevents new automated processing and waits for in-flight processing to finish.
+// Transfers still waiting for a slot are left for manual review. It returns
+// ErrShutdownTimeout if processing is still running after the shutdown timeout.
+func (wws *WarmWalletService) Stop() error {
+	wws.autoProcessMu.Lock()
+	if wws.stopped {
+		wws.autoProcessMu.Unlock()
+		return fmt.Errorf("warm wallet service is already stopped")
+	}
+	wws.stopped = true
+	close(wws.stopping)
+	inFlight := wws.autoProcessing
+	wws.autoProcessMu.Unlock()
+
+	wws.logger.Info("Stopping warm wallet automated processing", "in_flight", inFlight)
+
+	done := make(chan struct{})
+	go func() {
+		wws.autoProcessWG.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		wws.logger.Info("Warm wallet automated processing drained")
+	case <-time.After(wws.config.ShutdownTimeout):
+		wws.logger.Warn("Warm wallet automated processing shutdown timed out")
+		return fmt.Errorf("%w after %s: %d transfers still auto-processing", ErrShutdownTimeout, wws.config.ShutdownTimeout, wws.AutoProcessingInFlight())
+	}
+
+	return nil
+}
+
+// AutoProcessingInFlight returns the number of transfers currently being auto-processed
+func (wws *WarmWalletService) AutoProcessingInFlight() int {
+	wws.autoProcessMu.Lock()
+	defer wws.autoProcessMu.Unlock()
+	return wws.autoProcessing
+}
+
+// startAutomatedProcessing schedules automated processing for a transfer. It returns
+// false if the service is stopping, in which case the transfer should go to manual review.
+func (wws *WarmWalletService) startAutomatedProcessing(ctx context.Context, transfer *models.TransferRequest, riskResult *RiskAssessmentResult) bool {
+	wws.autoProcessMu.Lock()
+	if wws.stopped {
+		wws.autoProcessMu.Unlock()
+		return false
+	}
+	wws.autoProcessWG.Add(1)
+	wws.autoProcessMu.Unlock()
+
+	go func() {
+		defer wws.autoProcessWG.Done()
+
+		// Take the wallet's slot before a global one so a burst on one wallet queues behind
+		// its own transfers without holding global slots other wallets could use
+		slot := wws.acquireWalletSlot(transfer.WalletID)
+		defer wws.releaseWalletSlot(transfer.WalletID, slot)
+
+		select {
+		case slot.slots <- struct{}{}:
+		case <-wws.stopping:
+			wws.logger.Warn("Warm wallet service stopping, leaving transfer for manual review",
+				"transfer_id", transfer.ID,
+			)
+			return
+		}
+		defer func() { <-slot.slots }()
+
+		// Wait for a free slot so only MaxConcurrentAutoProcessing transfers run at once
+		select {
+		case wws.autoProcessSlots <- struct{}{}:
+		case <-wws.stopping:
+			wws.logger.Warn("Warm wallet service stopping, leaving transfer for manual review",
+				"transfer_id", transfer.ID,
+			)
+			return
+		}
+		defer func() { <-wws.autoProcessSlots }()
+
+		wws.autoProcessMu.Lock()
+		wws.autoProcessing++
+		wws.autoProcessMu.Unlock()
+		defer func() {
+			wws.autoProcessMu.Lock()
+			wws.autoProcessing--
+			wws.autoProcessMu.Unlock()
+		}()
+
+		wws.processAutomatedTransfer(ctx, transfer, riskResult)
+	}()
+
+	return true
+}
+
+// walletSlot bounds concurrent automated processing for one wallet. users counts the
+// goroutines holding or waiting for it so the entry can be dropped once idle.
+type walletSlot struct {
+	slots chan struct{}
+	users int
+}
+
+// acquireWalletSlot returns the wallet's slot, creating it on first use
+func (wws *WarmWalletService) acquireWalletSlot(walletID uuid.UUID) *walletSlot {
+	wws.autoProcessMu.Lock()
+	defer wws.autoProcessMu.Unlock()
+
+	slot, ok := wws.walletSlots[walletID]
+	if !ok {
+		slot = &walletSlot{slots: make(chan struct{}, wws.config.MaxConcurrentAutoProcessingPerWallet)}
+		wws.walletSlots[walletID] = slot
+	}
+	slot.users++
+	return slot
+}
+
+// releaseWalletSlot drops the wallet's slot once nothing holds or waits for it
+func (wws *WarmWalletService) releaseWalletSlot(walletID uuid.UUID, slot *walletSlot) {
+	wws.autoProcessMu.Lock()
+	defer wws.autoProcessMu.Unlock()
+
+	slot.users--
+	if slot.users == 0 {
+		delete(wws.walletSlots, walletID)
 	}
 }
 
@@ -141,9 +339,13 @@ func (wws *WarmWalletService) ValidateWarmTransferRequest(ctx context.Context, r
 	// Validate wallet exists and is warm type
 	wallet, err := wws.walletRepo.GetByID(request.WalletID)
 	if err != nil {
+		message := "Failed to look up wallet"
+		if stderrors.Is(err, repository.ErrWalletNotFound) {
+			message = "Wallet not found"
+		}
 		errors = append(errors, WarmTransferValidationError{
 			Field:   "walletId",
-			Message: "Wallet not found",
+			Message: message,
 		})
 		return errors
 	}
@@ -163,8 +365,57 @@ func (wws *WarmWalletService) ValidateWarmTransferRequest(ctx context.Context, r
 		})
 	}
 
-	// Validate transfer amounts
-	if err := wws.validateTransferAmount(request.AmountString, request.Coin, wallet); err != nil {
+	// Validate destination memo/tag for coins that require one
+	if err := bitgo.ValidateDestination(request.Coin, request.RecipientAddress, request.Memo, request.DestinationTag); err != nil {
+		field := "memo"
+		if strings.TrimSpace(request.DestinationTag) != "" {
+			field = "destinationTag"
+		}
+		errors = append(errors, WarmTransferValidationError{
+			Field:   field,
+			Message: err.Error(),
+		})
+	}
+
+	// Validate optional comment and tags
+	if err := ValidateTransferComment(request.Comment); err != nil {
+		errors = append(errors, WarmTransferValidationError{
+			Field:   "comment",
+			Message: err.Error(),
+		})
+	}
+	if _, err := NormalizeTransferTags(request.Tags); err != nil {
+		errors = append(errors, WarmTransferValidationError{
+			Field:   "tags",
+			Message: err.Error(),
+		})
+	}
+	if _, err := RequestedTransferOrigin(request.Origin); err != nil {
+		errors = append(errors, WarmTransferValidationError{
+			Field:   "origin",
+			Message: err.Error(),
+		})
+	}
+
+	// Validate transfer amounts, against a fresh balance if the cached one is stale
+	if _, err := refreshStaleBalance(ctx, wws.bitgoClient, wws.walletRepo, wallet, wws.config.BalanceMaxAge, wws.config.Clock.Now()); err != nil {
+		wws.logger.Warn("Failed to refresh stale wallet balance",
+			"wallet_id", wallet.ID,
+			"error", err,
+		)
+		errors = append(errors, WarmTransferValidationError{
+			Field:   "amountString",
+			Message: "unable to verify wallet balance",
+		})
+	} else if err := wws.validateTransferAmount(request.AmountString, request.Coin, wallet); err != nil {
+		errors = append(errors, WarmTransferValidationError{
+			Field:   "amountString",
+			Message: err.Error(),
+		})
+	}
+
+	// Validate the USD value against the fiat limit, if one is configured
+	if err := validateUSDLimit(ctx, wws.priceOracle, wws.config.MaxSingleTransferUSD, request.AmountString, request.Coin); err != nil {
 		errors = append(errors, WarmTransferValidationError{
 			Field:   "amountString",
 			Message: err.Error(),
@@ -172,7 +423,7 @@ func (wws *WarmWalletService) ValidateWarmTransferRequest(ctx context.Context, r
 	}
 
 	// Business purpose is less strict for warm wallets but still recommended
-	if strings.TrimSpace(request.BusinessPurpose) == "" && wws.requiresManualReview(request.AmountString) {
+	if strings.TrimSpace(request.BusinessPurpose) == "" && wws.requiresBusinessPurpose(request.AmountString) {
 		errors = append(errors, WarmTransferValidationError{
 			Field:   "businessPurpose",
 			Message: "Business purpose is required for high-value warm storage transfers",
@@ -211,17 +462,39 @@ func (wws *WarmWalletService) CreateWarmTransferRequest(ctx context.Context, req
 	// Validate the request
 	validationErrors := wws.ValidateWarmTransferRequest(ctx, request)
 	if len(validationErrors) > 0 {
+		wws.recordValidationFailures(request, validationErrors)
 		return nil, fmt.Errorf("validation failed: %v", validationErrors)
 	}
 
-	// Perform risk assessment
-	riskResult, err := wws.assessTransferRisk(ctx, request)
+	// Tags and origin were validated above, so normalizing them can't fail here
+	tags, _ := NormalizeTransferTags(request.Tags)
+	origin, _ := RequestedTransferOrigin(request.Origin)
+
+	flags := wws.featureFlags()
+
+	wallet, err := wws.walletRepo.GetByID(request.WalletID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get wallet: %w", err)
+	}
+
+	// Transfers to a trusted destination skip risk scoring and need no approvals, whatever the amount
+	trustedSource := wws.trustedDestination(ctx, wallet, request.RecipientAddress)
+
+	unscored := trustedSource == "" && !flags.RiskScoring
+	riskResult, err := wws.assessRisk(ctx, request, flags, trustedSource)
 	if err != nil {
 		return nil, fmt.Errorf("risk assessment failed: %w", err)
 	}
 
-	// Determine required approvals based on risk and amount
-	requiredApprovals := wws.calculateRequiredApprovals(request.AmountString, riskResult.Score)
+	// Determine required approvals based on risk and amount, raised to the wallet's override.
+	// Unscored transfers need at least one approval.
+	requiredApprovals := 0
+	if trustedSource == "" {
+		requiredApprovals = applyApprovalsOverride(wallet, wws.calculateRequiredApprovals(request.AmountString, riskResult.Score))
+		if unscored && requiredApprovals < 1 {
+			requiredApprovals = 1
+		}
+	}
 
 	// Create transfer request with warm-specific settings
 	transferRequest := &models.TransferRequest{
@@ -235,17 +508,58 @@ func (wws *WarmWalletService) CreateWarmTransferRequest(ctx context.Context, req
 		RequiredApprovals: requiredApprovals,
 		ReceivedApprovals: 0,
 		Memo:              &request.Memo,
+		DestinationTag:    optionalString(request.DestinationTag),
+		Comment:           optionalString(request.Comment),
+		Tags:              tags,
+		Origin:            origin,
+	}
+
+	// A wallet with an approvals override always goes through manual approval, unless the
+	// recipient is trusted. Eligible transfers are marked auto once processing actually starts.
+	autoEligible := flags.AutoProcessing && (trustedSource != "" ||
+		!unscored && wws.canAutoProcess(request.AmountString, riskResult.Score) && request.AutoProcess && wallet.RequiredApprovalsOverride == nil)
+	if trustedSource != "" {
+		markTrustedDestination(transferRequest, trustedSource)
+	}
+
+	// Record the SLA deadlines that apply to this transfer's urgency
+	setSLAMetadata(transferRequest, computeSLADeadlines(request.UrgencyLevel, wws.slaTargets(request.UrgencyLevel), wws.config.Clock.Now()))
+
+	hold, err := wws.reserveBalance(wallet, request.AmountString, request.Coin)
+	if err != nil {
+		return nil, err
 	}
 
 	// Create the transfer request in the database
 	if err := wws.transferRepo.Create(transferRequest); err != nil {
+		if hold != nil {
+			wws.config.Reservations.Release(hold)
+		}
 		return nil, fmt.Errorf("failed to create warm transfer request: %w", err)
 	}
+	if hold != nil {
+		wws.config.Reservations.Assign(hold, transferRequest.ID)
+	}
+
+	if trustedSource != "" {
+		wws.logger.Warn("Warm transfer to trusted destination bypassed risk review and approvals",
+			"transfer_id", transferRequest.ID,
+			"wallet_id", request.WalletID,
+			"recipient_address", request.RecipientAddress,
+			"amount", request.AmountString,
+			"coin", request.Coin,
+			"allowlist", trustedSource,
+		)
+	}
 
 	// Start automated processing if eligible
-	if wws.canAutoProcess(request.AmountString, riskResult.Score) && request.AutoProcess {
-		go wws.processAutomatedTransfer(ctx, transferRequest, riskResult)
-	} else {
+	autoProcessing := autoEligible && wws.startAutomatedProcessing(ctx, transferRequest, riskResult)
+	if !autoProcessing && trustedSource != "" {
+		// A trusted transfer needs no approvals, so rather than sit in the approval inbox it
+		// is approved now and waits for a manual "process" action
+		wws.approveTrustedTransfer(transferRequest)
+	}
+	if !autoProcessing {
 		// Send notifications for manual review
 		wws.notifyWarmTransferCreated(transferRequest, request, riskResult)
 	}
@@ -264,37 +578,76 @@ func (wws *WarmWalletService) CreateWarmTransferRequest(ctx context.Context, req
 	return transferRequest, nil
 }
 
-// ProcessAutomatedTransfer handles automated processing for eligible warm transfers
+// approveTrustedTransfer approves a transfer to a trusted destination that isn't being
+// processed automatically, updating transfer in place
+func (wws *WarmWalletService) approveTrustedTransfer(transfer *models.TransferRequest) {
+	if err := wws.transferRepo.UpdateStatus(transfer.ID, models.TransferStatusApproved); err != nil {
+		wws.logger.Error("Failed to approve trusted transfer",
+			"transfer_id", transfer.ID,
+			"error", err,
+		)
+		return
+	}
+	latest, err := wws.transferRepo.GetByID(transfer.ID)
+	if err != nil || latest == nil {
+		wws.logger.Warn("Failed to reload approved trusted transfer",
+			"transfer_id", transfer.ID,
+			"error", err,
+		)
+		transfer.Status = models.TransferStatusApproved
+		return
+	}
+	*transfer = *latest
+}
+
+// simulatedSigningDelay is how long simulated automated processing takes to sign a
+// transfer; broadcasting takes half as long
+var simulatedSigningDelay = 2 * time.Second
+
+// ProcessAutomatedTransfer handles automated processing for eligible warm transfers. Each
+// step is reapplied to the latest row if a concurrent update conflicts with it, and processing
+// stops once another update has moved the transfer out of the state the step expects.
 func (wws *WarmWalletService) processAutomatedTransfer(ctx context.Context, transfer *models.TransferRequest, riskResult *RiskAssessmentResult) {
 	wws.logger.Info("Starting automated processing for warm transfer",
 		"transfer_id", transfer.ID,
 		"risk_score", riskResult.Score,
 	)
 
-	// Update status to auto-approved
-	transfer.Status = models.TransferStatusApproved
-	transfer.ReceivedApprovals = transfer.RequiredApprovals
-	if err := wws.transferRepo.Update(transfer); err != nil {
-		wws.logger.Error("Failed to update transfer status", "error", err)
+	// Update status to auto-approved; only now is the transfer known to be the auto workflow's
+	if !wws.advanceAutomatedTransfer(transfer, models.TransferStatusSubmitted, func(transfer *models.TransferRequest) {
+		transfer.Status = models.TransferStatusApproved
+		transfer.ReceivedApprovals = transfer.RequiredApprovals
+		transfer.Origin = models.TransferOriginAuto
+	}) {
 		return
 	}
 
-	// In a real implementation, this would trigger the actual BitGo transfer
-	// For now, we'll simulate the process
-	time.Sleep(2 * time.Second) // Simulate processing time
+	// Build the transaction with BitGo under the warm build policy; signing and broadcast
+	// are still simulated
+	build, err := wws.buildAutomatedTransfer(ctx, transfer)
+	if err != nil {
+		wws.logger.Error("Failed to build automated warm transfer",
+			"transfer_id", transfer.ID,
+			"error", err,
+		)
+		wws.failAutomatedTransfer(transfer, fmt.Sprintf("Failed to build transfer with BitGo: %s", err))
+		return
+	}
+	time.Sleep(simulatedSigningDelay)
 
 	// Update to signed status
-	transfer.Status = models.TransferStatusSigned
-	if err := wws.transferRepo.Update(transfer); err != nil {
-		wws.logger.Error("Failed to update transfer to signed", "error", err)
+	if !wws.advanceAutomatedTransfer(transfer, models.TransferStatusApproved, func(transfer *models.TransferRequest) {
+		transfer.Status = models.TransferStatusSigned
+		recordBuildFee(transfer, build)
+	}) {
 		return
 	}
 
 	// Simulate broadcast
-	time.Sleep(1 * time.Second)
-	transfer.Status = models.TransferStatusBroadcast
-	if err := wws.transferRepo.Update(transfer); err != nil {
-		wws.logger.Error("Failed to update transfer to broadcast", "error", err)
+	time.Sleep(simulatedSigningDelay / 2)
+	if !wws.advanceAutomatedTransfer(transfer, models.TransferStatusSigned, func(transfer *models.TransferRequest) {
+		transfer.Status = models.TransferStatusBroadcast
+	}) {
 		return
 	}
 
@@ -303,8 +656,73 @@ func (wws *WarmWalletService) processAutomatedTransfer(ctx context.Context, tran
 	)
 }
 
+// advanceAutomatedTransfer applies step to a transfer in status from and saves it, retrying
+// on version conflicts. It returns false, having logged why, if the transfer wasn't saved.
+func (wws *WarmWalletService) advanceAutomatedTransfer(transfer *models.TransferRequest, from models.TransferStatus, step func(transfer *models.TransferRequest)) bool {
+	updated, err := repository.UpdateWithRetry(wws.transferRepo, transfer, func(transfer *models.TransferRequest) bool {
+		if transfer.Status != from {
+			return false
+		}
+		step(transfer)
+		return true
+	})
+	if err != nil {
+		wws.logger.Error("Failed to update automated warm transfer",
+			"transfer_id", transfer.ID,
+			"error", err,
+		)
+		return false
+	}
+	if !updated {
+		wws.logger.Warn("Automated warm transfer was moved on by another update, stopping processing",
+			"transfer_id", transfer.ID,
+			"status", transfer.Status,
+			"expected_status", from,
+		)
+		return false
+	}
+	return true
+}
+
+// buildAutomatedTransfer builds an automatically processed transfer with BitGo under the warm
+// build policy
+func (wws *WarmWalletService) buildAutomatedTransfer(ctx context.Context, transfer *models.TransferRequest) (*bitgo.BuildTransferResponse, error) {
+	wallet, err := wws.walletRepo.GetByID(transfer.WalletID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get wallet: %w", err)
+	}
+	return buildTransfer(ctx, wws.bitgoClient, wallet, transfer, wws.ApplyBuildPolicy)
+}
+
+// failAutomatedTransfer marks an automatically processed, approved transfer failed with reason
+func (wws *WarmWalletService) failAutomatedTransfer(transfer *models.TransferRequest, reason string) {
+	now := wws.config.Clock.Now()
+	if !wws.advanceAutomatedTransfer(transfer, models.TransferStatusApproved, func(transfer *models.TransferRequest) {
+		transfer.Status = models.TransferStatusFailed
+		transfer.StatusReason = &reason
+		transfer.FailedAt = &now
+	}) {
+		return
+	}
+	wws.notificationSvc.SendTransferFailedNotification(transfer, reason)
+}
+
+// assessRisk returns the risk assessment a new warm transfer is created with. Transfers to a
+// trusted destination aren't scored. With scoring off a transfer's risk is unknown, so it
+// fails closed into manual review rather than passing as risk-free.
+func (wws *WarmWalletService) assessRisk(ctx context.Context, request WarmTransferRequest, flags FeatureFlags, trustedSource string) (*RiskAssessmentResult, error) {
+	switch {
+	case trustedSource != "":
+		return &RiskAssessmentResult{Factors: map[string]string{}, Approved: true, Reason: "Trusted destination"}, nil
+	case !flags.RiskScoring:
+		return &RiskAssessmentResult{Factors: map[string]string{}, Approved: false, Reason: "Risk scoring disabled; manual review required"}, nil
+	default:
+		return wws.assessTransferRisk(ctx, request, flags)
+	}
+}
+
 // AssessTransferRisk performs risk assessment for warm transfers
-func (wws *WarmWalletService) assessTransferRisk(ctx context.Context, request WarmTransferRequest) (*RiskAssessmentResult, error) {
+func (wws *WarmWalletService) assessTransferRisk(ctx context.Context, request WarmTransferRequest, flags FeatureFlags) (*RiskAssessmentResult, error) {
 	result := &RiskAssessmentResult{
 		Factors: make(map[string]string),
 		Score:   0.0,
@@ -328,7 +746,7 @@ func (wws *WarmWalletService) assessTransferRisk(ctx context.Context, request Wa
 	}
 
 	// Velocity check
-	if wws.config.VelocityCheckEnabled {
+	if flags.VelocityChecks {
 		velocityRisk, err := wws.checkTransferVelocity(ctx, request.WalletID, amount)
 		if err == nil && velocityRisk > 0 {
 			result.Score += velocityRisk
@@ -381,25 +799,29 @@ func (wws *WarmWalletService) GetWarmTransfersSLAStatus(ctx context.Context) (ma
 		}
 	}
 
-	now := time.Now()
+	now := wws.config.Clock.Now()
 	slaBreached := 0
 	atRisk := 0
 	escalated := 0
+	breachedByUrgency := make(map[string]int)
 	automated := 0
+	escalation := wws.featureFlags().Escalation
 
 	for _, transfer := range warmTransfers {
 		// Calculate time since creation
 		elapsed := now.Sub(transfer.CreatedAt)
 
-		// Check SLA status
-		if elapsed > wws.config.CompletionSLA {
+		// Check SLA status against the deadlines for the transfer's urgency
+		deadlines := transferSLADeadlines(transfer, wws.slaTargets(""))
+		if now.After(deadlines.CompletionBy) {
 			slaBreached++
-		} else if elapsed > wws.config.CompletionSLA/2 {
+			breachedByUrgency[deadlines.UrgencyLevel]++
+		} else if now.After(transfer.CreatedAt.Add(deadlines.CompletionBy.Sub(transfer.CreatedAt) / 2)) {
 			atRisk++
 		}
 
 		// Check if escalated
-		if elapsed > wws.config.EscalationThreshold {
+		if escalation && elapsed > wws.config.EscalationThreshold {
 			escalated++
 		}
 
@@ -409,23 +831,79 @@ func (wws *WarmWalletService) GetWarmTransfersSLAStatus(ctx context.Context) (ma
 		}
 	}
 
+	// With nothing in progress the rate is zero, not NaN, which JSON can't encode
+	automationRate := 0.0
+	if len(warmTransfers) > 0 {
+		automationRate = float64(automated) / float64(len(warmTransfers)) * 100
+	}
+
 	return map[string]interface{}{
 		"totalWarmTransfers": len(warmTransfers),
 		"slaBreached":        slaBreached,
 		"atRisk":             atRisk,
 		"escalated":          escalated,
+		"breachedByUrgency":  breachedByUrgency,
 		"automated":          automated,
-		"automationRate":     float64(automated) / float64(len(warmTransfers)) * 100,
+		"automationRate":     automationRate,
+		"autoProcessing":     wws.AutoProcessingInFlight(),
 		"config": map[string]interface{}{
-			"initialResponseSLA": wws.config.InitialResponseSLA.String(),
-			"processingSLA":      wws.config.ProcessingSLA.String(),
-			"completionSLA":      wws.config.CompletionSLA.String(),
+			"initialResponseSLA":                   wws.config.InitialResponseSLA.String(),
+			"processingSLA":                        wws.config.ProcessingSLA.String(),
+			"completionSLA":                        wws.config.CompletionSLA.String(),
+			"urgencySLAMultipliers":                wws.config.UrgencySLAMultipliers,
+			"urgencySLAOverrides":                  wws.config.UrgencySLAOverrides,
+			"maxConcurrentAutoProcessing":          wws.config.MaxConcurrentAutoProcessing,
+			"maxConcurrentAutoProcessingPerWallet": wws.config.MaxConcurrentAutoProcessingPerWallet,
 		},
 	}, nil
 }
 
+// TransferSLADeadlines returns the SLA deadlines that apply to a warm transfer
+func (wws *WarmWalletService) TransferSLADeadlines(transfer *models.TransferRequest) SLADeadlines {
+	return transferSLADeadlines(transfer, wws.slaTargets(""))
+}
+
+// slaTargets returns the SLA targets for a warm transfer of the given urgency
+func (wws *WarmWalletService) slaTargets(urgency string) SLATargets {
+	base := SLATargets{
+		InitialResponse: wws.config.InitialResponseSLA,
+		Processing:      wws.config.ProcessingSLA,
+		Completion:      wws.config.CompletionSLA,
+	}
+	return effectiveSLATargets(base, urgency, wws.config.UrgencySLAMultipliers, wws.config.UrgencySLAOverrides)
+}
+
+// ApplyBuildPolicy sets the warm confirmation requirement on a BitGo build. Unlike cold, change
+// is left to BitGo's default so automated transfers aren't held up by a pending change output.
+func (wws *WarmWalletService) ApplyBuildPolicy(req *bitgo.BuildTransferRequest) {
+	if wws.config.MinConfirms > 0 {
+		req.MinConfirms = wws.config.MinConfirms
+	}
+}
+
+// ApprovalTimeout returns how long a warm transfer may wait for approvals before it expires
+func (wws *WarmWalletService) ApprovalTimeout() time.Duration {
+	return time.Duration(wws.config.ApprovalTimeoutHours) * time.Hour
+}
+
 // Helper methods
 
+// recordValidationFailures logs each rejected field and counts it for analytics
+func (wws *WarmWalletService) recordValidationFailures(request WarmTransferRequest, validationErrors []WarmTransferValidationError) {
+	for _, validationErr := range validationErrors {
+		wws.logger.Warn("Warm transfer validation failed",
+			"transfer_type", models.WalletTypeWarm,
+			"field", validationErr.Field,
+			"reason", validationErr.Message,
+			"wallet_id", request.WalletID,
+			"coin", request.Coin,
+		)
+		if wws.validationMetrics != nil {
+			wws.validationMetrics.Record(models.WalletTypeWarm, validationErr.Field)
+		}
+	}
+}
+
 func (wws *WarmWalletService) validateRecipientAddress(address, coin string) error {
 	if strings.TrimSpace(address) == "" {
 		return fmt.Errorf("recipient address is required")
@@ -446,18 +924,7 @@ func (wws *WarmWalletService) validateRecipientAddress(address, coin string) err
 	}
 
 	// Basic format validation (simplified)
-	switch strings.ToLower(coin) {
-	case "btc", "tbtc":
-		if len(address) < 26 || len(address) > 62 {
-			return fmt.Errorf("invalid Bitcoin address format")
-		}
-	case "eth":
-		if len(address) != 42 || !strings.HasPrefix(address, "0x") {
-			return fmt.Errorf("invalid Ethereum address format")
-		}
-	}
-
-	return nil
+	return validateAddressFormat(address, coin)
 }
 
 func (wws *WarmWalletService) validateTransferAmount(amountStr, coin string, wallet *models.Wallet) error {
@@ -487,9 +954,45 @@ func (wws *WarmWalletService) validateTransferAmount(amountStr, coin string, wal
 		return fmt.Errorf("amount exceeds spendable balance of %s %s", wallet.SpendableBalanceString, coin)
 	}
 
+	// Leave room for transfers already in flight from this wallet
+	if wws.config.Reservations != nil {
+		if err := wws.config.Reservations.Check(wallet.ID, coin, amountStr, wallet.SpendableBalanceString); err != nil {
+			return err
+		}
+	}
+
 	return nil
 }
 
+// reserveBalance holds the transfer's amount and estimated fee against the wallet. Validation
+// only checked the balance; this re-checks and holds atomically, so a concurrent transfer that
+// validated against the same balance can't also reserve it. Returns nil when reservations
+// aren't configured.
+func (wws *WarmWalletService) reserveBalance(wallet *models.Wallet, amountStr, coin string) (*BalanceHold, error) {
+	if wws.config.Reservations == nil {
+		return nil, nil
+	}
+
+	hold, err := wws.config.Reservations.Reserve(wallet.ID, coin, amountStr, wallet.SpendableBalanceString)
+	if err != nil {
+		return nil, fmt.Errorf("validation failed: %v", []WarmTransferValidationError{{Field: "amountString", Message: err.Error()}})
+	}
+	return hold, nil
+}
+
+// featureFlags returns the current flags, falling back to the static config when the service
+// was created without a flag store
+func (wws *WarmWalletService) featureFlags() FeatureFlags {
+	if wws.flags != nil {
+		return wws.flags.Get()
+	}
+
+	flags := DefaultFeatureFlags()
+	flags.RiskScoring = wws.config.RiskScoringEnabled
+	flags.VelocityChecks = wws.config.VelocityCheckEnabled
+	return flags
+}
+
 func (wws *WarmWalletService) canAutoProcess(amountStr string, riskScore float64) bool {
 	amount, err := parseAmount(amountStr)
 	if err != nil {
@@ -518,6 +1021,20 @@ func (wws *WarmWalletService) requiresManualReview(amountStr string) bool {
 	return amount >= threshold
 }
 
+func (wws *WarmWalletService) requiresBusinessPurpose(amountStr string) bool {
+	amount, err := parseAmount(amountStr)
+	if err != nil {
+		return true // Default to requiring a purpose on parsing error
+	}
+
+	threshold, err := parseAmount(wws.config.BusinessPurposeRequiredThreshold)
+	if err != nil {
+		return true
+	}
+
+	return amount >= threshold
+}
+
 func (wws *WarmWalletService) calculateRequiredApprovals(amountStr string, riskScore float64) int {
 	amount, err := parseAmount(amountStr)
 	if err != nil {
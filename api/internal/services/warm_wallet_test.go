@@ -0,0 +1,601 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"bitgo-wallets-api/internal/bitgo"
+	"bitgo-wallets-api/internal/models"
+
+	"github.com/google/uuid"
+)
+
+// withoutSimulatedDelay removes the simulated signing delay for the rest of the test
+func withoutSimulatedDelay(t *testing.T) {
+	t.Helper()
+	previous := simulatedSigningDelay
+	simulatedSigningDelay = 0
+	t.Cleanup(func() { simulatedSigningDelay = previous })
+}
+
+func TestAutomatedProcessingRecordsAutoOrigin(t *testing.T) {
+	withoutSimulatedDelay(t)
+	repo := &updateRecordingRepo{}
+	wallet := newTestWarmWallet()
+	wws := &WarmWalletService{
+		bitgoClient:  bitgo.NewSimulatedClient(bitgo.SimulationConfig{}, testLogger{}),
+		walletRepo:   newMemWalletRepo(wallet),
+		transferRepo: repo,
+		logger:       testLogger{},
+	}
+
+	transfer := &models.TransferRequest{
+		ID:                uuid.New(),
+		WalletID:          wallet.ID,
+		RecipientAddress:  testTrustedAddress,
+		AmountString:      "0.1",
+		Coin:              wallet.Coin,
+		Status:            models.TransferStatusSubmitted,
+		Origin:            models.TransferOriginAPI,
+		RequiredApprovals: 1,
+	}
+	wws.processAutomatedTransfer(context.Background(), transfer, &RiskAssessmentResult{Approved: true})
+
+	if len(repo.updated) == 0 {
+		t.Fatal("processAutomatedTransfer() saved nothing")
+	}
+	for _, saved := range repo.updated {
+		if saved.Origin != models.TransferOriginAuto {
+			t.Errorf("saved %s transfer with origin %q, want %q", saved.Status, saved.Origin, models.TransferOriginAuto)
+		}
+	}
+	if last := repo.updated[len(repo.updated)-1]; last.Status != models.TransferStatusBroadcast {
+		t.Errorf("final status = %s, want %s", last.Status, models.TransferStatusBroadcast)
+	}
+}
+
+// newTestWarmWalletService returns a warm wallet service over in-memory repositories holding
+// wallet, with the default config and a simulated BitGo
+func newTestWarmWalletService(wallet *models.Wallet, config WarmWalletConfig) (*WarmWalletService, *memTransferRepo) {
+	repo := newMemTransferRepo()
+	wws := NewWarmWalletService(
+		bitgo.NewSimulatedClient(bitgo.SimulationConfig{}, testLogger{}),
+		newMemWalletRepo(wallet),
+		repo,
+		nopNotifier{},
+		testLogger{},
+		config,
+		nil,
+		nil,
+		nil,
+	)
+	return wws, repo
+}
+
+// newTestWarmWallet returns a warm BTC wallet with a spendable balance of 10
+func newTestWarmWallet() *models.Wallet {
+	return &models.Wallet{
+		ID:                     uuid.New(),
+		BitgoWalletID:          "bitgo-warm-1",
+		Coin:                   "btc",
+		WalletType:             models.WalletTypeWarm,
+		BalanceString:          "10",
+		ConfirmedBalanceString: "10",
+		SpendableBalanceString: "10",
+		IsActive:               true,
+	}
+}
+
+// newTestWarmRequest returns a valid warm transfer request from the wallet
+func newTestWarmRequest(wallet *models.Wallet, amount string) WarmTransferRequest {
+	return WarmTransferRequest{
+		WalletID:         wallet.ID,
+		RecipientAddress: testTrustedAddress,
+		AmountString:     amount,
+		Coin:             wallet.Coin,
+		RequestorName:    "Test Requestor",
+		RequestorEmail:   "requestor@example.com",
+		UrgencyLevel:     "normal",
+	}
+}
+
+func TestWalletOverrideRaisesRequiredApprovals(t *testing.T) {
+	config := DefaultWarmWalletConfig()
+	override := 3
+
+	// A small, low-risk transfer needs no approvals by default
+	tests := []struct {
+		name     string
+		amount   string
+		override *int
+		want     int
+	}{
+		{name: "service default", amount: "1", want: 0},
+		{name: "wallet override", amount: "1", override: &override, want: override},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			wallet := newTestWarmWallet()
+			wallet.RequiredApprovalsOverride = tt.override
+			wws, _ := newTestWarmWalletService(wallet, config)
+
+			transfer, err := wws.CreateWarmTransferRequest(context.Background(), newTestWarmRequest(wallet, tt.amount), uuid.New())
+			if err != nil {
+				t.Fatalf("CreateWarmTransferRequest() error = %v", err)
+			}
+			if transfer.RequiredApprovals != tt.want {
+				t.Errorf("RequiredApprovals = %d, want %d", transfer.RequiredApprovals, tt.want)
+			}
+		})
+	}
+}
+
+func TestApprovalsOverrideNeverLowersRequiredApprovals(t *testing.T) {
+	lower := 1
+	wallet := &models.Wallet{RequiredApprovalsOverride: &lower}
+	if got := applyApprovalsOverride(wallet, 2); got != 2 {
+		t.Errorf("applyApprovalsOverride() = %d, want 2", got)
+	}
+}
+
+func TestRiskScoringDisabledSkipsAssessment(t *testing.T) {
+	wallet := newTestWarmWallet()
+	flags := DefaultFeatureFlags()
+	flags.RiskScoring = false
+	wws, _ := newTestWarmWalletService(wallet, DefaultWarmWalletConfig())
+	wws.flags = NewFeatureFlagStore(flags)
+
+	// Critical urgency adds to the score whenever the transfer is assessed
+	request := newTestWarmRequest(wallet, "1")
+	request.UrgencyLevel = "critical"
+	request.AutoProcess = true
+
+	risk, err := wws.assessRisk(context.Background(), request, flags, "")
+	if err != nil {
+		t.Fatalf("assessRisk() error = %v", err)
+	}
+	if risk.Score != 0 || len(risk.Factors) != 0 {
+		t.Errorf("risk = %+v, want the transfer left unscored", risk)
+	}
+	if risk.Approved {
+		t.Error("Approved = true, want unscored transfers sent to manual review")
+	}
+	if want := "Risk scoring disabled; manual review required"; risk.Reason != want {
+		t.Errorf("Reason = %q, want %q", risk.Reason, want)
+	}
+
+	transfer, err := wws.CreateWarmTransferRequest(context.Background(), request, uuid.New())
+	if err != nil {
+		t.Fatalf("CreateWarmTransferRequest() error = %v", err)
+	}
+	if transfer.RequiredApprovals < 1 {
+		t.Errorf("RequiredApprovals = %d, want at least 1", transfer.RequiredApprovals)
+	}
+	if transfer.Origin == models.TransferOriginAuto {
+		t.Error("unscored transfer was processed automatically")
+	}
+
+	flags.RiskScoring = true
+	scored, err := wws.assessRisk(context.Background(), request, flags, "")
+	if err != nil {
+		t.Fatalf("assessRisk() error = %v", err)
+	}
+	if scored.Score == 0 {
+		t.Error("Score = 0 with scoring enabled, want the critical urgency scored")
+	}
+}
+
+func TestStopReportsUndrainedAutoProcessing(t *testing.T) {
+	previous := simulatedSigningDelay
+	simulatedSigningDelay = 200 * time.Millisecond
+	t.Cleanup(func() { simulatedSigningDelay = previous })
+
+	tests := []struct {
+		name            string
+		shutdownTimeout time.Duration
+		wantErr         error
+	}{
+		{name: "drained", shutdownTimeout: 5 * time.Second},
+		{name: "timed out", shutdownTimeout: 10 * time.Millisecond, wantErr: ErrShutdownTimeout},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			wallet := newTestWarmWallet()
+			config := DefaultWarmWalletConfig()
+			config.ShutdownTimeout = tt.shutdownTimeout
+			wws, repo := newTestWarmWalletService(wallet, config)
+
+			transfer := &models.TransferRequest{WalletID: wallet.ID, Status: models.TransferStatusSubmitted}
+			if err := repo.Create(transfer); err != nil {
+				t.Fatalf("Create() error = %v", err)
+			}
+			if !wws.startAutomatedProcessing(context.Background(), transfer, &RiskAssessmentResult{Approved: true}) {
+				t.Fatal("startAutomatedProcessing() = false, want processing started")
+			}
+			for deadline := time.Now().Add(time.Second); wws.AutoProcessingInFlight() == 0; {
+				if time.Now().After(deadline) {
+					t.Fatal("automated processing never started")
+				}
+				time.Sleep(time.Millisecond)
+			}
+
+			if err := wws.Stop(); !errors.Is(err, tt.wantErr) {
+				t.Errorf("Stop() = %v, want %v", err, tt.wantErr)
+			}
+
+			// Let abandoned processing finish before the signing delay is restored
+			wws.autoProcessWG.Wait()
+		})
+	}
+}
+
+func TestAutoProcessingConcurrencyIsBounded(t *testing.T) {
+	previous := simulatedSigningDelay
+	simulatedSigningDelay = 50 * time.Millisecond
+	t.Cleanup(func() { simulatedSigningDelay = previous })
+
+	tests := []struct {
+		name      string
+		global    int
+		perWallet int
+		wallets   int
+		transfers int
+		want      int
+	}{
+		{name: "global limit", global: 2, perWallet: 1, wallets: 5, transfers: 5, want: 2},
+		{name: "per-wallet limit", global: 4, perWallet: 1, wallets: 1, transfers: 3, want: 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			config := DefaultWarmWalletConfig()
+			config.MaxConcurrentAutoProcessing = tt.global
+			config.MaxConcurrentAutoProcessingPerWallet = tt.perWallet
+			config.ShutdownTimeout = 5 * time.Second
+			wws, repo := newTestWarmWalletService(newTestWarmWallet(), config)
+
+			wallets := wws.walletRepo.(*memWalletRepo)
+			walletIDs := make([]uuid.UUID, tt.wallets)
+			for i := range walletIDs {
+				wallet := newTestWarmWallet()
+				wallets.wallets[wallet.ID] = wallet
+				walletIDs[i] = wallet.ID
+			}
+			var transfers []*models.TransferRequest
+			for i := 0; i < tt.transfers; i++ {
+				transfer := &models.TransferRequest{WalletID: walletIDs[i%tt.wallets], RecipientAddress: testTrustedAddress, AmountString: "0.1", Coin: "btc", Status: models.TransferStatusSubmitted, RequiredApprovals: 1}
+				if err := repo.Create(transfer); err != nil {
+					t.Fatalf("Create() error = %v", err)
+				}
+				transfers = append(transfers, transfer)
+			}
+
+			// Sample the in-flight count while processing runs
+			peak := 0
+			sampled := make(chan struct{})
+			go func() {
+				defer close(sampled)
+				for deadline := time.Now().Add(250 * time.Millisecond); time.Now().Before(deadline); time.Sleep(time.Millisecond) {
+					if inFlight := wws.AutoProcessingInFlight(); inFlight > peak {
+						peak = inFlight
+					}
+				}
+			}()
+			for _, transfer := range transfers {
+				copied := *transfer
+				if !wws.startAutomatedProcessing(context.Background(), &copied, &RiskAssessmentResult{Approved: true}) {
+					t.Fatal("startAutomatedProcessing() = false, want processing started")
+				}
+			}
+			<-sampled
+
+			if peak != tt.want {
+				t.Errorf("peak in-flight = %d, want %d", peak, tt.want)
+			}
+			if err := wws.Stop(); err != nil {
+				t.Fatalf("Stop() error = %v", err)
+			}
+			if inFlight := wws.AutoProcessingInFlight(); inFlight != 0 {
+				t.Errorf("%d transfers still in flight after Stop()", inFlight)
+			}
+			for _, transfer := range transfers {
+				stored, _ := repo.GetByID(transfer.ID)
+				if stored.Status != models.TransferStatusBroadcast && stored.Status != models.TransferStatusSubmitted {
+					t.Errorf("transfer left %s after Stop(), want broadcast or untouched", stored.Status)
+				}
+			}
+		})
+	}
+}
+
+func TestStopWaitsForInFlightProcessing(t *testing.T) {
+	previous := simulatedSigningDelay
+	simulatedSigningDelay = 100 * time.Millisecond
+	t.Cleanup(func() { simulatedSigningDelay = previous })
+
+	wallet := newTestWarmWallet()
+	config := DefaultWarmWalletConfig()
+	config.ShutdownTimeout = 5 * time.Second
+	wws, repo := newTestWarmWalletService(wallet, config)
+
+	transfer := &models.TransferRequest{WalletID: wallet.ID, Status: models.TransferStatusSubmitted, RequiredApprovals: 1}
+	if err := repo.Create(transfer); err != nil {
+		t.Fatalf("Create() error = %v", err)
+	}
+	copied := *transfer
+	if !wws.startAutomatedProcessing(context.Background(), &copied, &RiskAssessmentResult{Approved: true}) {
+		t.Fatal("startAutomatedProcessing() = false, want processing started")
+	}
+	for deadline := time.Now().Add(time.Second); wws.AutoProcessingInFlight() == 0; {
+		if time.Now().After(deadline) {
+			t.Fatal("automated processing never started")
+		}
+		time.Sleep(time.Millisecond)
+	}
+
+	if err := wws.Stop(); err != nil {
+		t.Fatalf("Stop() error = %v", err)
+	}
+	if stored, _ := repo.GetByID(transfer.ID); stored.Status != models.TransferStatusBroadcast {
+		t.Errorf("status after Stop() = %s, want %s", stored.Status, models.TransferStatusBroadcast)
+	}
+	late := &models.TransferRequest{ID: uuid.New(), WalletID: wallet.ID}
+	if wws.startAutomatedProcessing(context.Background(), late, &RiskAssessmentResult{Approved: true}) {
+		t.Error("startAutomatedProcessing() after Stop() = true, want false")
+	}
+}
+
+// mockBitGoClient answers only the BitGo calls warm transfer creation makes; any other call
+// panics on the nil embedded interface
+type mockBitGoClient struct {
+	bitgo.BitGoAPI
+
+	spendableString string
+}
+
+func (m *mockBitGoClient) ValidateAddress(ctx context.Context, coin, address string) (bool, error) {
+	return true, nil
+}
+
+func (m *mockBitGoClient) BuildTransfer(ctx context.Context, walletID, coin string, req bitgo.BuildTransferRequest) (*bitgo.BuildTransferResponse, error) {
+	return &bitgo.BuildTransferResponse{PrebuildTx: &bitgo.PrebuildTransaction{TxHex: "deadbeef", WalletId: walletID}}, nil
+}
+
+func (m *mockBitGoClient) GetWalletBalance(ctx context.Context, walletID, coin string) (*bitgo.WalletBalance, error) {
+	return &bitgo.WalletBalance{WalletID: walletID, Coin: coin, Balance: m.spendableString, ConfirmedBalance: m.spendableString, SpendableBalance: m.spendableString}, nil
+}
+
+func TestWarmAutoProcessingWithMockClient(t *testing.T) {
+	withoutSimulatedDelay(t)
+
+	tests := []struct {
+		name        string
+		autoProcess bool
+		want        models.TransferStatus
+	}{
+		{name: "auto-processed", autoProcess: true, want: models.TransferStatusBroadcast},
+		{name: "left for manual review", autoProcess: false, want: models.TransferStatusSubmitted},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			wallet := newTestWarmWallet()
+			client := &mockBitGoClient{spendableString: "1000000000"}
+			repo := newMemTransferRepo()
+			config := DefaultWarmWalletConfig()
+			config.ShutdownTimeout = 5 * time.Second
+			wws := NewWarmWalletService(client, newMemWalletRepo(wallet), repo, nopNotifier{}, testLogger{}, config, nil, nil, nil)
+
+			request := newTestWarmRequest(wallet, "0.1")
+			request.AutoProcess = tt.autoProcess
+			transfer, err := wws.CreateWarmTransferRequest(context.Background(), request, uuid.New())
+			if err != nil {
+				t.Fatalf("CreateWarmTransferRequest() error = %v", err)
+			}
+
+			// Stopping straight away could leave the transfer for manual review, so wait for it
+			stored, _ := repo.GetByID(transfer.ID)
+			for deadline := time.Now().Add(time.Second); stored.Status != tt.want && time.Now().Before(deadline); time.Sleep(time.Millisecond) {
+				stored, _ = repo.GetByID(transfer.ID)
+			}
+			if err := wws.Stop(); err != nil {
+				t.Fatalf("Stop() error = %v", err)
+			}
+
+			if stored.Status != tt.want {
+				t.Errorf("status = %s, want %s", stored.Status, tt.want)
+			}
+			if (stored.Origin == models.TransferOriginAuto) != tt.autoProcess {
+				t.Errorf("origin = %q, want auto = %v", stored.Origin, tt.autoProcess)
+			}
+		})
+	}
+}
+
+func TestBusinessPurposeThresholdIsIndependentOfManualReview(t *testing.T) {
+	tests := []struct {
+		name      string
+		threshold string
+		amount    string
+		purpose   string
+		wantErr   bool
+	}{
+		{name: "just under", threshold: "2.0", amount: "1.99999999"},
+		{name: "at the threshold", threshold: "2.0", amount: "2.0", wantErr: true},
+		{name: "over with a purpose", threshold: "2.0", amount: "2.5", purpose: "Exchange rebalancing"},
+		{name: "over with a blank purpose", threshold: "2.0", amount: "2.5", purpose: "   ", wantErr: true},
+		// Above the 10.0 manual review threshold, but under the purpose threshold
+		{name: "manual review without purpose", threshold: "20.0", amount: "15.0"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			wallet := newTestWarmWallet()
+			config := DefaultWarmWalletConfig()
+			config.ManualReviewThreshold = "10.0"
+			config.BusinessPurposeRequiredThreshold = tt.threshold
+			wws, _ := newTestWarmWalletService(wallet, config)
+
+			request := newTestWarmRequest(wallet, tt.amount)
+			request.BusinessPurpose = tt.purpose
+			var purposeErrors int
+			for _, validationErr := range wws.ValidateWarmTransferRequest(context.Background(), request) {
+				if validationErr.Field == "businessPurpose" {
+					purposeErrors++
+				}
+			}
+			if (purposeErrors > 0) != tt.wantErr {
+				t.Errorf("%d businessPurpose errors for %s with threshold %s, want errors = %v", purposeErrors, tt.amount, tt.threshold, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestWarmAutoProcessingBuildsUnderTheWarmPolicy(t *testing.T) {
+	withoutSimulatedDelay(t)
+
+	wallet := newTestWarmWallet()
+	client := newBuildRecordingClient()
+	repo := newMemTransferRepo()
+	config := DefaultWarmWalletConfig()
+	config.MinConfirms = 3
+	config.ShutdownTimeout = 5 * time.Second
+	wws := NewWarmWalletService(client, newMemWalletRepo(wallet), repo, nopNotifier{}, testLogger{}, config, nil, nil, nil)
+
+	request := newTestWarmRequest(wallet, "0.1")
+	request.AutoProcess = true
+	transfer, err := wws.CreateWarmTransferRequest(context.Background(), request, uuid.New())
+	if err != nil {
+		t.Fatalf("CreateWarmTransferRequest() error = %v", err)
+	}
+	stored := waitForTransferStatus(repo, transfer.ID, models.TransferStatusBroadcast)
+	if err := wws.Stop(); err != nil {
+		t.Fatalf("Stop() error = %v", err)
+	}
+
+	builds := client.recorded()
+	if len(builds) != 1 {
+		t.Fatalf("built %d transactions, want 1", len(builds))
+	}
+	if builds[0].MinConfirms != 3 || builds[0].EnforceMinConfirmsForChange {
+		t.Errorf("build minConfirms = %d, enforceMinConfirmsForChange = %v; want 3, false", builds[0].MinConfirms, builds[0].EnforceMinConfirmsForChange)
+	}
+	if len(builds[0].Recipients) != 1 || builds[0].Recipients[0].Address != request.RecipientAddress || builds[0].Recipients[0].AmountString != "0.1" {
+		t.Errorf("build recipients = %+v, want 0.1 to %s", builds[0].Recipients, request.RecipientAddress)
+	}
+
+	if stored.Status != models.TransferStatusBroadcast || stored.Fee == nil {
+		t.Errorf("status %s with fee %v, want %s with the quoted fee", stored.Status, stored.Fee, models.TransferStatusBroadcast)
+	}
+}
+
+func TestWarmAutoProcessingFailsWhenBuildFails(t *testing.T) {
+	withoutSimulatedDelay(t)
+
+	wallet := newTestWarmWallet()
+	client := &mockBitGoClient{spendableString: "1000000000"}
+	repo := newMemTransferRepo()
+	config := DefaultWarmWalletConfig()
+	config.ShutdownTimeout = 5 * time.Second
+	wws := NewWarmWalletService(&failingBuildWarmClient{client}, newMemWalletRepo(wallet), repo, nopNotifier{}, testLogger{}, config, nil, nil, nil)
+
+	request := newTestWarmRequest(wallet, "0.1")
+	request.AutoProcess = true
+	transfer, err := wws.CreateWarmTransferRequest(context.Background(), request, uuid.New())
+	if err != nil {
+		t.Fatalf("CreateWarmTransferRequest() error = %v", err)
+	}
+	stored := waitForTransferStatus(repo, transfer.ID, models.TransferStatusFailed)
+	if err := wws.Stop(); err != nil {
+		t.Fatalf("Stop() error = %v", err)
+	}
+
+	if stored.Status != models.TransferStatusFailed || stored.StatusReason == nil {
+		t.Errorf("status %s with reason %v, want %s with a reason", stored.Status, stored.StatusReason, models.TransferStatusFailed)
+	}
+}
+
+// waitForTransferStatus waits up to a second for the stored transfer to reach status, since
+// stopping the service straight away could leave it for manual review, and returns it
+func waitForTransferStatus(repo *memTransferRepo, id uuid.UUID, status models.TransferStatus) *models.TransferRequest {
+	stored, _ := repo.GetByID(id)
+	for deadline := time.Now().Add(time.Second); stored.Status != status && time.Now().Before(deadline); time.Sleep(time.Millisecond) {
+		stored, _ = repo.GetByID(id)
+	}
+	return stored
+}
+
+// failingBuildWarmClient is a mockBitGoClient whose builds fail
+type failingBuildWarmClient struct {
+	*mockBitGoClient
+}
+
+func (failingBuildWarmClient) BuildTransfer(ctx context.Context, walletID, coin string, req bitgo.BuildTransferRequest) (*bitgo.BuildTransferResponse, error) {
+	return nil, bitgo.APIError{StatusCode: 400, Message: "insufficient funds", Name: "InsufficientBalance"}
+}
+
+func TestSecondTransferOnBusyWalletWaitsWhileOtherWalletsProceed(t *testing.T) {
+	previous := simulatedSigningDelay
+	simulatedSigningDelay = 200 * time.Millisecond
+	t.Cleanup(func() { simulatedSigningDelay = previous })
+
+	busy, other := newTestWarmWallet(), newTestWarmWallet()
+	config := DefaultWarmWalletConfig()
+	config.MaxConcurrentAutoProcessing = 4
+	config.MaxConcurrentAutoProcessingPerWallet = 1
+	config.ShutdownTimeout = 5 * time.Second
+	wws, repo := newTestWarmWalletService(busy, config)
+	wws.walletRepo.(*memWalletRepo).wallets[other.ID] = other
+
+	var transfers []*models.TransferRequest
+	for _, walletID := range []uuid.UUID{busy.ID, busy.ID, other.ID} {
+		transfer := &models.TransferRequest{WalletID: walletID, RecipientAddress: testTrustedAddress, AmountString: "0.1", Coin: "btc", Status: models.TransferStatusSubmitted, RequiredApprovals: 1}
+		if err := repo.Create(transfer); err != nil {
+			t.Fatalf("Create() error = %v", err)
+		}
+		transfers = append(transfers, transfer)
+		copied := *transfer
+		if !wws.startAutomatedProcessing(context.Background(), &copied, &RiskAssessmentResult{Approved: true}) {
+			t.Fatal("startAutomatedProcessing() = false, want processing started")
+		}
+	}
+
+	for deadline := time.Now().Add(time.Second); wws.AutoProcessingInFlight() < 2; {
+		if time.Now().After(deadline) {
+			t.Fatalf("in-flight = %d, want the other wallet processing alongside the busy one", wws.AutoProcessingInFlight())
+		}
+		time.Sleep(time.Millisecond)
+	}
+
+	wws.autoProcessMu.Lock()
+	busySlot, otherSlot := wws.walletSlots[busy.ID], wws.walletSlots[other.ID]
+	if busySlot == nil || busySlot.users != 2 || len(busySlot.slots) != 1 {
+		t.Errorf("busy wallet slot = %+v, want one transfer processing and one waiting", busySlot)
+	}
+	if otherSlot == nil || otherSlot.users != 1 || len(otherSlot.slots) != 1 {
+		t.Errorf("other wallet slot = %+v, want its transfer processing", otherSlot)
+	}
+	inFlight := wws.autoProcessing
+	wws.autoProcessMu.Unlock()
+	if inFlight != 2 {
+		t.Errorf("in-flight = %d, want 2 while the busy wallet's second transfer waits", inFlight)
+	}
+
+	// The waiting transfer runs once the first on its wallet is done
+	wws.autoProcessWG.Wait()
+	for _, transfer := range transfers {
+		if stored, _ := repo.GetByID(transfer.ID); stored.Status != models.TransferStatusBroadcast {
+			t.Errorf("transfer on wallet %s = %s, want %s", transfer.WalletID, stored.Status, models.TransferStatusBroadcast)
+		}
+	}
+	if err := wws.Stop(); err != nil {
+		t.Fatalf("Stop() error = %v", err)
+	}
+	if len(wws.walletSlots) != 0 {
+		t.Errorf("%d wallet slots left after processing, want none", len(wws.walletSlots))
+	}
+}
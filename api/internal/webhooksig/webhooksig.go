@@ -0,0 +1,63 @@
+// Package webhooksig signs and verifies webhook bodies with HMAC-SHA256. Outbound webhooks
+// are signed and inbound BitGo webhooks verified with the same code, so the two can't drift.
+//
+// The signature travels in the X-Signature-SHA256 header as the lowercase hex HMAC-SHA256 of
+// the raw request body, keyed by the shared secret:
+//
+//	X-Signature-SHA256: 5d41402abc4b2a76b9719d911017c592...
+//
+// Verify also accepts the value with a "sha256=" prefix, as some senders add one.
+package webhooksig
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"strings"
+)
+
+// Header is the HTTP header carrying a webhook's signature
+const Header = "X-Signature-SHA256"
+
+// signaturePrefix is an optional scheme prefix on the header value
+const signaturePrefix = "sha256="
+
+var (
+	ErrMissingSecret    = errors.New("webhook secret is not configured")
+	ErrMissingSignature = errors.New("webhook signature is missing")
+	ErrInvalidSignature = errors.New("webhook signature does not match")
+)
+
+// Sign returns the signature for body, in the form sent in Header
+func Sign(body []byte, secret string) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write(body)
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// Verify checks that signature was produced for body with secret. The comparison is
+// constant-time so the signature can't be guessed byte by byte.
+func Verify(body []byte, signature, secret string) error {
+	if secret == "" {
+		return ErrMissingSecret
+	}
+
+	signature = strings.TrimSpace(signature)
+	if len(signature) >= len(signaturePrefix) && strings.EqualFold(signature[:len(signaturePrefix)], signaturePrefix) {
+		signature = signature[len(signaturePrefix):]
+	}
+	if signature == "" {
+		return ErrMissingSignature
+	}
+
+	given, err := hex.DecodeString(signature)
+	if err != nil {
+		return ErrInvalidSignature
+	}
+	expected, _ := hex.DecodeString(Sign(body, secret))
+	if !hmac.Equal(given, expected) {
+		return ErrInvalidSignature
+	}
+	return nil
+}
@@ -0,0 +1,56 @@
+package webhooksig
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestVerify(t *testing.T) {
+	body := []byte(`{"type":"transfer","transfer":"transfer-1","state":"confirmed"}`)
+	secret := "webhook-secret"
+	signature := Sign(body, secret)
+
+	tests := []struct {
+		name      string
+		body      []byte
+		signature string
+		secret    string
+		wantErr   error
+	}{
+		{name: "round trip", body: body, signature: signature, secret: secret},
+		{name: "uppercase hex", body: body, signature: strings.ToUpper(signature), secret: secret},
+		{name: "sha256= prefix", body: body, signature: "sha256=" + signature, secret: secret},
+		{name: "SHA256= prefix with padding", body: body, signature: "  SHA256=" + signature + " ", secret: secret},
+		{name: "tampered body", body: []byte(`{"type":"transfer","transfer":"transfer-1","state":"failed"}`), signature: signature, secret: secret, wantErr: ErrInvalidSignature},
+		{name: "wrong secret", body: body, signature: signature, secret: "other-secret", wantErr: ErrInvalidSignature},
+		{name: "truncated signature", body: body, signature: signature[:len(signature)-2], secret: secret, wantErr: ErrInvalidSignature},
+		{name: "non-hex signature", body: body, signature: "not-a-hex-signature", secret: secret, wantErr: ErrInvalidSignature},
+		{name: "empty signature", body: body, signature: "", secret: secret, wantErr: ErrMissingSignature},
+		{name: "prefix only", body: body, signature: "sha256=", secret: secret, wantErr: ErrMissingSignature},
+		{name: "empty secret", body: body, signature: signature, secret: "", wantErr: ErrMissingSecret},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := Verify(tt.body, tt.signature, tt.secret)
+			if tt.wantErr == nil {
+				if err != nil {
+					t.Errorf("Verify() error = %v, want nil", err)
+				}
+				return
+			}
+			if !errors.Is(err, tt.wantErr) {
+				t.Errorf("Verify() error = %v, want %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestSignIsLowercaseHexHMAC(t *testing.T) {
+	// HMAC-SHA256("key", "The quick brown fox jumps over the lazy dog")
+	const want = "f7bc83f430538424b13298e6aa6fb143ef4d59a14946175997479dbc2d1a3cd8"
+	if got := Sign([]byte("The quick brown fox jumps over the lazy dog"), "key"); got != want {
+		t.Errorf("Sign() = %s, want %s", got, want)
+	}
+}
@@ -0,0 +1,20 @@
+-- 002_transfer_status_reason.sql
+-- Allow transfers to expire when approvals are not gathered in time
+ALTER TABLE transfer_requests DROP CONSTRAINT IF EXISTS transfer_requests_status_check;
+ALTER TABLE transfer_requests ADD CONSTRAINT transfer_requests_status_check CHECK (status IN (
+    'draft',
+    'submitted',
+    'pending_approval',
+    'approved',
+    'signed',
+    'broadcast',
+    'confirmed',
+    'completed',
+    'failed',
+    'rejected',
+    'cancelled',
+    'expired'          -- Approvals not gathered before the timeout
+));
+
+-- Why the transfer reached its current status (e.g. expiry or rejection reason)
+ALTER TABLE transfer_requests ADD COLUMN status_reason TEXT;
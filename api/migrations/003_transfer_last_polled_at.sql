@@ -0,0 +1,5 @@
+-- 003_transfer_last_polled_at.sql
+-- Track when each transfer was last polled so wallet types can be polled at different cadences
+ALTER TABLE transfer_requests ADD COLUMN last_polled_at TIMESTAMP WITH TIME ZONE;
+
+CREATE INDEX idx_transfer_requests_type_last_polled ON transfer_requests(transfer_type, last_polled_at);
@@ -0,0 +1,3 @@
+-- 004_transfer_metadata.sql
+-- Flexible per-transfer metadata (e.g. urgency and effective SLA deadlines)
+ALTER TABLE transfer_requests ADD COLUMN metadata JSONB DEFAULT '{}';
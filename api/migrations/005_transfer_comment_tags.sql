@@ -0,0 +1,6 @@
+-- 005_transfer_comment_tags.sql
+-- Free-form comment (also sent to BitGo) and searchable tags on transfers
+ALTER TABLE transfer_requests ADD COLUMN comment TEXT;
+ALTER TABLE transfer_requests ADD COLUMN tags TEXT[] DEFAULT '{}';
+
+CREATE INDEX idx_transfer_requests_tags ON transfer_requests USING GIN (tags);
@@ -0,0 +1,14 @@
+-- 006_wallet_addresses.sql
+-- Receive addresses generated for our wallets, optionally labeled for reuse
+CREATE TABLE wallet_addresses (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    wallet_id UUID NOT NULL REFERENCES wallets(id) ON DELETE CASCADE,
+    address VARCHAR(255) NOT NULL,
+    label VARCHAR(255),
+    chain INTEGER,
+    address_index INTEGER,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    UNIQUE (wallet_id, address)
+);
+
+CREATE UNIQUE INDEX idx_wallet_addresses_label ON wallet_addresses(wallet_id, label) WHERE label IS NOT NULL;
@@ -0,0 +1,5 @@
+-- 007_transfer_archival.sql
+-- Old transfers in terminal states are flagged as archived and hidden from default lists
+ALTER TABLE transfer_requests ADD COLUMN archived_at TIMESTAMP WITH TIME ZONE;
+
+CREATE INDEX idx_transfer_requests_unarchived ON transfer_requests(wallet_id, created_at DESC) WHERE archived_at IS NULL;
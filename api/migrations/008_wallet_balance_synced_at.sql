@@ -0,0 +1,3 @@
+-- 008_wallet_balance_synced_at.sql
+-- When the cached balances were last fetched from BitGo, so stale values can be refreshed
+ALTER TABLE wallets ADD COLUMN balance_synced_at TIMESTAMP WITH TIME ZONE;
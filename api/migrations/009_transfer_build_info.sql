@@ -0,0 +1,3 @@
+-- 009_transfer_build_info.sql
+-- Summary of the transaction BitGo built for a transfer (size, inputs, outputs, change, fee rate)
+ALTER TABLE transfer_requests ADD COLUMN build_info JSONB;
@@ -0,0 +1,8 @@
+-- 010_blocked_addresses.sql
+-- Operator-managed denylist of recipient addresses that transfers may never be sent to
+CREATE TABLE blocked_addresses (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    address VARCHAR(255) NOT NULL UNIQUE,
+    reason TEXT,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW()
+);
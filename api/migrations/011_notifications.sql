@@ -0,0 +1,22 @@
+-- 011_notifications.sql
+-- Delivery trail of notifications so users can see whether a transfer's notifications went out
+CREATE TABLE notifications (
+    id UUID PRIMARY KEY,
+    transfer_id UUID REFERENCES transfer_requests(id) ON DELETE CASCADE,
+    type VARCHAR(50) NOT NULL,
+    priority VARCHAR(20) NOT NULL,
+    title VARCHAR(255) NOT NULL,
+    message TEXT NOT NULL,
+    recipients TEXT[] NOT NULL DEFAULT '{}',
+    channels TEXT[] NOT NULL DEFAULT '{}',
+    status VARCHAR(20) NOT NULL,
+    retry_count INTEGER NOT NULL DEFAULT 0,
+    last_error TEXT,
+    sequence BIGINT,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    delivered_at TIMESTAMP WITH TIME ZONE,
+    failed_at TIMESTAMP WITH TIME ZONE,
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT NOW()
+);
+
+CREATE INDEX idx_notifications_transfer ON notifications(transfer_id, created_at) WHERE transfer_id IS NOT NULL;
@@ -0,0 +1,3 @@
+-- 012_transfer_origin.sql
+-- Record which path created each transfer (api, auto, retry, sweep, consolidate) for auditing
+ALTER TABLE transfer_requests ADD COLUMN origin VARCHAR(20) NOT NULL DEFAULT 'api';
@@ -0,0 +1,3 @@
+-- 013_wallet_required_approvals_override.sql
+-- Admin-set minimum number of approvals for a wallet's transfers; NULL uses the service default
+ALTER TABLE wallets ADD COLUMN required_approvals_override INTEGER CHECK (required_approvals_override > 0);
@@ -0,0 +1,3 @@
+-- 014_transfer_destination_tag.sql
+-- Destination tag/memo ID for XRP, XLM and EOS recipients, kept apart from the free-text memo
+ALTER TABLE transfer_requests ADD COLUMN destination_tag VARCHAR(256);
@@ -0,0 +1,13 @@
+-- 015_transfer_events.sql
+-- Append-only log of transfer state changes that consumers can replay to rebuild state
+CREATE TABLE transfer_events (
+    id BIGSERIAL PRIMARY KEY,
+    transfer_id UUID NOT NULL REFERENCES transfer_requests(id) ON DELETE CASCADE,
+    sequence BIGINT NOT NULL,
+    type VARCHAR(50) NOT NULL,
+    from_status VARCHAR(50),
+    to_status VARCHAR(50) NOT NULL,
+    payload JSONB NOT NULL DEFAULT '{}',
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    UNIQUE (transfer_id, sequence)
+);
@@ -0,0 +1,3 @@
+-- 016_transfer_version.sql
+-- Row version for optimistic concurrency, so concurrent read-modify-write updates can't clobber each other
+ALTER TABLE transfer_requests ADD COLUMN version INTEGER NOT NULL DEFAULT 1;
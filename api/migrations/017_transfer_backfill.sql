@@ -0,0 +1,3 @@
+-- 017_transfer_backfill.sql
+-- A BitGo transfer can be backfilled into a wallet only once, even by concurrent backfill runs
+CREATE UNIQUE INDEX idx_transfer_requests_backfill ON transfer_requests(wallet_id, bitgo_transfer_id) WHERE origin = 'backfill';
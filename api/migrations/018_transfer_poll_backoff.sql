@@ -0,0 +1,6 @@
+-- 018_transfer_poll_backoff.sql
+-- Back off polling transfers whose BitGo lookups keep failing, and flag them for manual attention
+ALTER TABLE transfer_requests ADD COLUMN poll_failures INTEGER NOT NULL DEFAULT 0;
+ALTER TABLE transfer_requests ADD COLUMN poll_error TEXT;
+ALTER TABLE transfer_requests ADD COLUMN next_poll_at TIMESTAMP WITH TIME ZONE;
+ALTER TABLE transfer_requests ADD COLUMN needs_attention_at TIMESTAMP WITH TIME ZONE;
@@ -0,0 +1,3 @@
+-- 019_wallet_trusted_addresses.sql
+-- Per-wallet trusted destinations whose warm transfers skip risk review and approvals
+ALTER TABLE wallets ADD COLUMN trusted_addresses TEXT[] NOT NULL DEFAULT '{}';
@@ -0,0 +1,9 @@
+-- 020_transfer_approvals.sql
+-- Who decided on each transfer's approval, so every approver counts once
+CREATE TABLE transfer_approvals (
+    transfer_id UUID NOT NULL REFERENCES transfer_requests(id) ON DELETE CASCADE,
+    approver_id UUID NOT NULL,
+    approved BOOLEAN NOT NULL,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (transfer_id, approver_id)
+);
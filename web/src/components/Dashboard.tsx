@@ -102,7 +102,7 @@ export const Dashboard: React.FC<DashboardProps> = () => {
 
     console.log("Setting up WebSocket connection to:", wsUrl);
 
-    const connectWebSocket = () => {
+    const connectWebSocket = async () => {
       if (isConnecting || !shouldConnect) {
         return;
       }
@@ -111,7 +111,11 @@ export const Dashboard: React.FC<DashboardProps> = () => {
 
       try {
         console.log("🔄 Connecting to BitGo request logs...");
-        websocket = new WebSocket(wsUrl);
+        // The log stream requires a short-lived signed token, so fetch a fresh one per connection
+        const tokenResponse = await api.post("/api/v1/auth/ws-token");
+        websocket = new WebSocket(
+          `${wsUrl}?token=${encodeURIComponent(tokenResponse.data.token)}`
+        );
 
         // Set a connection timeout
         const connectionTimeout = setTimeout(() => {
@@ -15,11 +15,15 @@ class APIClient {
     private async request(endpoint: string, options: RequestInit = {}): Promise<any> {
         const url = `${this.baseURL}${endpoint}`;
 
-        // Setup headers (no authentication required)
+        // Setup headers; the session token from login authorizes admin routes
         const headers: Record<string, string> = {
             'Content-Type': 'application/json',
             ...(options.headers as Record<string, string> || {}),
         };
+        const token = typeof window !== 'undefined' ? localStorage.getItem('auth_token') : null;
+        if (token && !headers['Authorization']) {
+            headers['Authorization'] = `Bearer ${token}`;
+        }
 
         // Create AbortController for timeout
         const controller = new AbortController();